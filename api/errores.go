@@ -0,0 +1,55 @@
+package api
+
+import "errors"
+
+// CodigoError identifica la clase de un error con un valor estable que no cambia aunque
+// cambie la redacción del mensaje
+type CodigoError string
+
+// Códigos de error que expone finmex
+const (
+	CodigoTarjetaNoEncontrada CodigoError = "TARJETA_NO_ENCONTRADA"
+	CodigoDatosInvalidos      CodigoError = "DATOS_INVALIDOS"
+	CodigoArchivoCorrupto     CodigoError = "ARCHIVO_CORRUPTO"
+	CodigoVersionFutura       CodigoError = "VERSION_FUTURA"
+)
+
+// Errores de referencia para comparar con errors.Is sin importar el detalle del mensaje
+var (
+	ErrTarjetaNoEncontrada = &ErrorFinmex{Codigo: CodigoTarjetaNoEncontrada}
+	ErrDatosInvalidos      = &ErrorFinmex{Codigo: CodigoDatosInvalidos}
+	ErrArchivoCorrupto     = &ErrorFinmex{Codigo: CodigoArchivoCorrupto}
+	ErrVersionFutura       = &ErrorFinmex{Codigo: CodigoVersionFutura}
+)
+
+// ErrorFinmex es un error con código estable y mensaje en español e inglés
+type ErrorFinmex struct {
+	Codigo  CodigoError
+	Mensaje string // Mensaje en español
+	Message string // Mensaje en inglés; si está vacío se usa el de español
+	Causa   error
+}
+
+// Error regresa el mensaje en español, el idioma del resto de la herramienta
+func (e *ErrorFinmex) Error() string {
+	return e.Mensaje
+}
+
+// Unwrap expone la causa original del error
+func (e *ErrorFinmex) Unwrap() error {
+	return e.Causa
+}
+
+// Is hace que cualquier ErrorFinmex coincida con el error de referencia de su código
+func (e *ErrorFinmex) Is(objetivo error) bool {
+	var otro *ErrorFinmex
+	return errors.As(objetivo, &otro) && otro.Mensaje == "" && otro.Codigo == e.Codigo
+}
+
+// Localizado regresa el mensaje en el idioma pedido ("es" o "en")
+func (e *ErrorFinmex) Localizado(idioma string) string {
+	if idioma == "en" && e.Message != "" {
+		return e.Message
+	}
+	return e.Mensaje
+}
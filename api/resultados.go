@@ -0,0 +1,127 @@
+package api
+
+import "finmex/calc"
+
+// FilaDebito es una cuenta de débito en la salida de debito listar con --output
+type FilaDebito struct {
+	TarjetaDebito
+	SaldoEquilibrio *float64 `json:"saldo_equilibrio"` // null si ningún saldo le gana a la inflación
+	VsMercado       string   `json:"vs_mercado"`
+}
+
+// FilaCredito es una tarjeta de crédito en la salida de credito listar con --output
+type FilaCredito struct {
+	TarjetaCredito
+	VsMercado string `json:"vs_mercado"`
+}
+
+// AnalisisDebito es el resultado de debito analizar con --output. Los montos son en pesos,
+// también los de las cuentas en dólares.
+type AnalisisDebito struct {
+	Nombre             string             `json:"nombre"`
+	Banco              string             `json:"banco"`
+	Moneda             string             `json:"moneda"`
+	Saldo              float64            `json:"saldo"`
+	Tramos             []TramoRendimiento `json:"tramos"`
+	TasaRendimiento    float64            `json:"tasa_rendimiento"` // Ponderada por los tramos que alcanza el saldo
+	RendimientoBruto   float64            `json:"rendimiento_bruto"`
+	Impuestos          float64            `json:"impuestos"`
+	Inflacion          float64            `json:"inflacion"`
+	PerdidaInflacion   float64            `json:"perdida_inflacion"`
+	ComisionAnual      float64            `json:"comision_anual"`
+	RendimientoReal    float64            `json:"rendimiento_real"`
+	RendimientoRealPct float64            `json:"rendimiento_real_pct"`
+	SaldoFinal         float64            `json:"saldo_final"`
+	SaldoEquilibrio    *float64           `json:"saldo_equilibrio"` // En la moneda de la cuenta
+	Gana               bool               `json:"gana"`
+	RiesgoCambiario    *RiesgoCambiario   `json:"riesgo_cambiario,omitempty"` // Solo en cuentas en dólares
+	Proyeccion         []AñoProyeccion    `json:"proyeccion,omitempty"`       // Con --anios
+}
+
+// RiesgoCambiario es el rendimiento real en pesos de una cuenta en dólares si el tipo de cambio
+// se mueve Sensibilidad más de lo esperado en cualquier sentido
+type RiesgoCambiario struct {
+	Moneda            string  `json:"moneda"`
+	TipoCambio        float64 `json:"tipo_cambio"`
+	Variacion         float64 `json:"variacion"`            // Cambio esperado del tipo de cambio en el año
+	Sensibilidad      float64 `json:"sensibilidad"`         // Movimiento adicional evaluado
+	RealSiApreciaMXN  float64 `json:"real_si_aprecia_mxn"`  // Rendimiento real en pesos si el peso se fortalece
+	RealSiDepreciaMXN float64 `json:"real_si_deprecia_mxn"` // Rendimiento real en pesos si el peso se debilita
+}
+
+// AnalisisCredito es el resultado de credito analizar con --output
+type AnalisisCredito struct {
+	Nombre       string                `json:"nombre"`
+	Banco        string                `json:"banco"`
+	Deuda        float64               `json:"deuda"`
+	TasaInteres  float64               `json:"tasa_interes"`
+	TasaEfectiva float64               `json:"tasa_efectiva"`
+	CAT          float64               `json:"cat"`
+	Frecuencia   Frecuencia            `json:"frecuencia"`
+	Pago         float64               `json:"pago"`
+	Pagos        int                   `json:"pagos"`
+	Meses        float64               `json:"meses"`
+	PrimerPago   string                `json:"primer_pago,omitempty"`
+	UltimoPago   string                `json:"ultimo_pago,omitempty"`
+	CostoTotal   float64               `json:"costo_total"`
+	Desglose     calc.DesgloseCredito  `json:"desglose"` // Intereses, comisiones y su IVA
+	CostoPct     float64               `json:"costo_pct"`
+	MontoTotal   float64               `json:"monto_total"`
+	Calendario   []string              `json:"calendario,omitempty"`   // Con --calendario
+	Amortizacion []RenglonTablaCredito `json:"amortizacion,omitempty"` // Con --tabla
+	EnUDIS       *MontoUDIS            `json:"en_udis,omitempty"`      // Con --udis: el monto total pagado en pesos de hoy
+	// AnualidadCondonada indica que con --gasto-mensual se alcanza la facturación que bonifica
+	// la anualidad, que entonces no se cuenta en el costo
+	AnualidadCondonada bool `json:"anualidad_condonada,omitempty"`
+}
+
+// RenglonTablaCredito es un periodo de la tabla de amortización con la fecha de su pago
+type RenglonTablaCredito struct {
+	Fecha string `json:"fecha,omitempty"`
+	RenglonAmortizacion
+}
+
+// MontoUDIS expresa un monto futuro en UDIS y en pesos constantes de hoy
+type MontoUDIS struct {
+	ValorUDI        float64 `json:"valor_udi"`
+	Meses           float64 `json:"meses"`
+	UDIS            float64 `json:"udis"`
+	PesosConstantes float64 `json:"pesos_constantes"`
+}
+
+// ResultadoComparacionDebito es una fila de la comparación de débito en salida NDJSON
+type ResultadoComparacionDebito struct {
+	Nombre             string  `json:"nombre"`
+	Banco              string  `json:"banco"`
+	Moneda             string  `json:"moneda"`
+	Saldo              float64 `json:"saldo"`            // En pesos, como los demás montos
+	TasaRendimiento    float64 `json:"tasa_rendimiento"` // Ponderada por los tramos con el saldo comparado
+	RendimientoReal    float64 `json:"rendimiento_real"`
+	RendimientoRealPct float64 `json:"rendimiento_real_pct"`
+	SaldoFinal         float64 `json:"saldo_final"`
+	Gana               bool    `json:"gana"`
+	// RiesgoCambiario solo se llena en las cuentas en dólares
+	RiesgoCambiario *RiesgoCambiario `json:"riesgo_cambiario,omitempty"`
+}
+
+// ResultadoComparacionCredito es una fila de la comparación de crédito en salida NDJSON
+type ResultadoComparacionCredito struct {
+	Nombre     string     `json:"nombre"`
+	Banco      string     `json:"banco"`
+	Deuda      float64    `json:"deuda"`
+	Pago       float64    `json:"pago"`
+	Frecuencia Frecuencia `json:"frecuencia"`
+	CAT        float64    `json:"cat"`
+	CostoTotal float64    `json:"costo_total"`
+	Pagos      int        `json:"pagos"`
+	Cashback   float64    `json:"cashback"`
+	MSI        bool       `json:"msi"`
+}
+
+// EstadoServidor es la respuesta de /api/salud
+type EstadoServidor struct {
+	Version   string  `json:"version"`
+	Perfil    string  `json:"perfil"`
+	Inflacion float64 `json:"inflacion"`
+	ISR       string  `json:"isr"`
+}
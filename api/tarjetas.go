@@ -0,0 +1,262 @@
+// Package api define los documentos que intercambian finmex serve y sus clientes: las
+// tarjetas como se guardan en tarjetas.json, los resultados de los cálculos y los errores
+// con código. Solo depende de calc y de la biblioteca estándar, para que el paquete client
+// se pueda usar sin traer la línea de comandos.
+package api
+
+import "finmex/calc"
+
+// Tipos de calc que forman parte de los documentos
+type (
+	TarjetaDebito       = calc.TarjetaDebito
+	TramoRendimiento    = calc.TramoRendimiento
+	TarjetaCredito      = calc.TarjetaCredito
+	Frecuencia          = calc.Frecuencia
+	RenglonAmortizacion = calc.RenglonAmortizacion
+	AñoProyeccion       = calc.AñoProyeccion
+)
+
+// Frecuencias de pago que aceptan las consultas de crédito
+const (
+	FrecuenciaMensual    = calc.FrecuenciaMensual
+	FrecuenciaQuincenal  = calc.FrecuenciaQuincenal
+	FrecuenciaCatorcenal = calc.FrecuenciaCatorcenal
+	FrecuenciaSemanal    = calc.FrecuenciaSemanal
+)
+
+// Tarjetas son todas las tarjetas y demás productos guardados, como en tarjetas.json
+type Tarjetas struct {
+	Version       int                `json:"version"` // Versión del formato del archivo
+	Debito        []TarjetaDebito    `json:"debito"`
+	Credito       []TarjetaCredito   `json:"credito"`
+	Nomina        []CuentaNomina     `json:"nomina,omitempty"`
+	Microcreditos []Microcredito     `json:"microcreditos,omitempty"`
+	BNPL          []CompraBNPL       `json:"bnpl,omitempty"`
+	Monederos     []Monedero         `json:"monederos,omitempty"`
+	Vales         []ValeDespensa     `json:"vales,omitempty"`
+	Cajas         []CajaAhorro       `json:"cajas,omitempty"`
+	Informales    []PrestamoInformal `json:"informales,omitempty"`
+	PPR           []PlanRetiro       `json:"ppr,omitempty"`
+	Metas         []Meta             `json:"metas,omitempty"`
+	Polizas       []Poliza           `json:"polizas,omitempty"`
+	Bonos         []BonoBienvenida   `json:"bonos,omitempty"`
+	Cetes         []InversionCetes   `json:"cetes,omitempty"`
+	Sofipos       []CuentaSofipo     `json:"sofipos,omitempty"`
+	Pagares       []PagareBancario   `json:"pagares,omitempty"`
+	Presupuesto   *Presupuesto       `json:"presupuesto,omitempty"`
+	Retiro        *DatosRetiro       `json:"retiro,omitempty"`
+	Saldos        []RegistroSaldo    `json:"saldos,omitempty"` // Historial mensual de saldos por tarjeta
+}
+
+// CuentaNomina representa una cuenta donde se deposita la nómina y sus beneficios
+type CuentaNomina struct {
+	Nombre            string  `json:"nombre"`
+	Banco             string  `json:"banco"`
+	TasaRendimiento   float64 `json:"tasa_rendimiento"`   // Tasa anual sobre el saldo
+	ComisionMensual   float64 `json:"comision_mensual"`   // Cero si la cuenta no cobra comisiones
+	AnticipoNomina    bool    `json:"anticipo_nomina"`    // Ofrece adelanto de nómina
+	TasaAnticipo      float64 `json:"tasa_anticipo"`      // Costo anual del anticipo de nómina
+	PromocionTraspaso float64 `json:"promocion_traspaso"` // Bono único por portabilizar la nómina
+	BeneficioAnual    float64 `json:"beneficio_anual"`    // Otros beneficios valuados en pesos al año
+}
+
+// Microcredito representa un préstamo de app o fintech a plazo corto con comisiones fijas
+type Microcredito struct {
+	Nombre    string  `json:"nombre"`
+	Proveedor string  `json:"proveedor"`  // Kueski, Baubap, Tala, etc.
+	Monto     float64 `json:"monto"`      // Dinero que se recibe
+	Comision  float64 `json:"comision"`   // Comisiones fijas en pesos
+	Interes   float64 `json:"interes"`    // Interés total en pesos (con IVA si lo cobran)
+	PlazoDias int     `json:"plazo_dias"` // Días entre la disposición y el último pago
+	Pagos     int     `json:"pagos"`      // Pagos iguales; 1 si se liquida en una sola exhibición
+}
+
+// CompraBNPL representa una compra a pagos con "compra ahora, paga después" (Aplazo, Kueski Pay)
+type CompraBNPL struct {
+	Nombre        string     `json:"nombre"`
+	Proveedor     string     `json:"proveedor"`
+	Monto         float64    `json:"monto"`           // Precio de contado de la compra
+	Pagos         int        `json:"pagos"`           // Número de pagos
+	Frecuencia    Frecuencia `json:"frecuencia"`      // Normalmente quincenal
+	Comision      float64    `json:"comision"`        // Comisiones totales en pesos
+	TasaInteres   float64    `json:"tasa_interes"`    // Tasa anual, cero si es sin intereses
+	PrimerPagoHoy bool       `json:"primer_pago_hoy"` // El primer pago se cobra al comprar
+	FechaCompra   string     `json:"fecha_compra"`    // Formato AAAA-MM-DD
+}
+
+// Monedero representa saldo líquido fuera del banco: apps de pago, tarjetas de regalo o puntos
+type Monedero struct {
+	Nombre          string  `json:"nombre"`
+	Proveedor       string  `json:"proveedor"`
+	Tipo            string  `json:"tipo"`                  // saldo, regalo o puntos
+	Saldo           float64 `json:"saldo"`                 // En pesos, o en puntos si el tipo es puntos
+	ValorPunto      float64 `json:"valor_punto,omitempty"` // Pesos por punto
+	TasaRendimiento float64 `json:"tasa_rendimiento"`      // Tasa anual, cero si no rinde
+	Vencimiento     string  `json:"vencimiento,omitempty"` // Formato AAAA-MM-DD
+}
+
+// ValeDespensa representa vales de despensa que el empleador deposita cada mes
+type ValeDespensa struct {
+	Nombre       string  `json:"nombre"`
+	Emisor       string  `json:"emisor"`        // Edenred, Sodexo, Si Vale, etc.
+	MontoMensual float64 `json:"monto_mensual"` // Depósito mensual en pesos
+	TopeUMAs     float64 `json:"tope_umas"`     // Tope de exención en UMAs mensuales
+	TasaMarginal float64 `json:"tasa_marginal"` // Tasa de ISR que se aplica a la parte gravada
+}
+
+// CajaAhorro representa una caja popular o cooperativa de ahorro y préstamo (SOCAP)
+type CajaAhorro struct {
+	Nombre           string  `json:"nombre"`
+	Entidad          string  `json:"entidad"`
+	ParteSocial      float64 `json:"parte_social"`      // Aportación obligatoria para ser socio
+	Ahorro           float64 `json:"ahorro"`            // Saldo ahorrado además de la parte social
+	TasaRendimiento  float64 `json:"tasa_rendimiento"`  // Tasa anual sobre el ahorro
+	TasaPrestamo     float64 `json:"tasa_prestamo"`     // Tasa anual preferente para socios
+	MultiploPrestamo float64 `json:"multiplo_prestamo"` // Veces el ahorro que se puede pedir prestado
+	ComisionApertura float64 `json:"comision_apertura"` // Porcentaje del préstamo (decimal)
+	SaldoPrestamo    float64 `json:"saldo_prestamo,omitempty"`
+}
+
+// PrestamoInformal representa dinero prestado entre personas, por cobrar o por pagar
+type PrestamoInformal struct {
+	Persona    string     `json:"persona"`
+	Concepto   string     `json:"concepto,omitempty"`
+	PorCobrar  bool       `json:"por_cobrar"` // Verdadero si yo presté; falso si me prestaron
+	Monto      float64    `json:"monto"`
+	Interes    float64    `json:"interes,omitempty"` // Interés total acordado en pesos
+	Pagos      int        `json:"pagos"`
+	Frecuencia Frecuencia `json:"frecuencia"`
+	PrimerPago string     `json:"primer_pago"` // Formato AAAA-MM-DD
+	Abonado    float64    `json:"abonado"`     // Lo que ya se ha pagado
+}
+
+// PlanRetiro representa un plan personal de retiro (PPR) deducible
+type PlanRetiro struct {
+	Nombre          string  `json:"nombre"`
+	Institucion     string  `json:"institucion"`
+	Saldo           float64 `json:"saldo"`
+	AportacionAnual float64 `json:"aportacion_anual"`
+	TasaRendimiento float64 `json:"tasa_rendimiento"` // Rendimiento bruto anual estimado
+	Comision        float64 `json:"comision"`         // Comisión anual sobre el saldo (decimal)
+}
+
+// Meta es un objetivo de ahorro con fecha límite y los aportes reales hechos hacia él
+type Meta struct {
+	Nombre          string       `json:"nombre"`
+	Objetivo        float64      `json:"objetivo"`
+	FechaInicio     string       `json:"fecha_inicio"`     // Formato AAAA-MM-DD
+	FechaObjetivo   string       `json:"fecha_objetivo"`   // Formato AAAA-MM-DD
+	TasaRendimiento float64      `json:"tasa_rendimiento"` // Tasa anual neta donde se guarda el ahorro
+	Aportes         []AporteMeta `json:"aportes,omitempty"`
+	// Con un producto registrado la tasa es su rendimiento real y los montos quedan en
+	// pesos de hoy; TasaRendimiento guarda la última tasa por si el producto se elimina
+	Producto     string `json:"producto,omitempty"`
+	TipoProducto string `json:"tipo_producto,omitempty"` // debito, sofipo, cetes o pagare
+}
+
+// AporteMeta es un depósito real hacia una meta
+type AporteMeta struct {
+	Fecha string  `json:"fecha"` // Formato AAAA-MM-DD
+	Monto float64 `json:"monto"`
+}
+
+// Poliza representa una cotización de seguro de auto o de gastos médicos mayores
+type Poliza struct {
+	Nombre        string  `json:"nombre"`
+	Aseguradora   string  `json:"aseguradora"`
+	Tipo          string  `json:"tipo"` // auto o gmm
+	PrimaAnual    float64 `json:"prima_anual"`
+	Deducible     float64 `json:"deducible"`                // En pesos
+	Coaseguro     float64 `json:"coaseguro"`                // Porcentaje que paga el asegurado después del deducible
+	TopeCoaseguro float64 `json:"tope_coaseguro,omitempty"` // Máximo de coaseguro en pesos, cero si no hay tope
+	SumaAsegurada float64 `json:"suma_asegurada"`           // Máximo que paga la aseguradora
+}
+
+// BonoBienvenida es la recompensa que da una tarjeta al alcanzar un gasto mínimo en un plazo
+type BonoBienvenida struct {
+	Tarjeta     string  `json:"tarjeta"`
+	Recompensa  float64 `json:"recompensa"` // Valor en pesos de la recompensa
+	GastoMinimo float64 `json:"gasto_minimo"`
+	Inicio      string  `json:"inicio"`       // Fecha desde la que cuenta el gasto, AAAA-MM-DD
+	FechaLimite string  `json:"fecha_limite"` // AAAA-MM-DD
+	Cobrado     bool    `json:"cobrado,omitempty"`
+}
+
+// InversionCetes es una inversión en valores gubernamentales comprados en cetesdirecto
+type InversionCetes struct {
+	Nombre      string  `json:"nombre"`
+	Instrumento string  `json:"instrumento"` // cetes, bondes o udibonos
+	Monto       float64 `json:"monto"`
+	Tasa        float64 `json:"tasa"`       // Tasa anual; real en el caso de los UDIBONOS
+	PlazoDias   int     `json:"plazo_dias"` // 28, 91, 182 o 364 en CETES
+}
+
+// CuentaSofipo es una cuenta o inversión en una Sociedad Financiera Popular (Nu, Stori,
+// Klar, SuperTasas...)
+type CuentaSofipo struct {
+	Nombre      string        `json:"nombre"`
+	Institucion string        `json:"institucion"`
+	Saldo       float64       `json:"saldo"`
+	PlazoDias   int           `json:"plazo_dias"` // Plazo elegido; 0 es a la vista
+	Tramos      []TramoSofipo `json:"tramos"`
+}
+
+// TramoSofipo es la tasa que paga una SOFIPO a un plazo para la parte del saldo entre dos
+// montos. Las tasas escalonadas por monto (15% hasta $25,000 y 9% por el resto) son varios
+// tramos con el mismo plazo.
+type TramoSofipo struct {
+	PlazoDias int     `json:"plazo_dias"`      // 0 es a la vista
+	Hasta     float64 `json:"hasta,omitempty"` // Monto hasta el que aplica la tasa; 0 es sin tope
+	Tasa      float64 `json:"tasa"`
+}
+
+// PagareBancario es un pagaré con rendimiento liquidable al vencimiento (PRLV) de un banco
+type PagareBancario struct {
+	Nombre     string  `json:"nombre"`
+	Banco      string  `json:"banco"`
+	Monto      float64 `json:"monto"`
+	Tasa       float64 `json:"tasa"`       // Tasa anual bruta, con la convención de 360 días
+	PlazoDias  int     `json:"plazo_dias"` // 28, 91, 182 o 364
+	Renovacion bool    `json:"renovacion_automatica"`
+	// Penalizacion es la parte del interés ganado que se pierde si se retira antes del
+	// vencimiento; 1 es perder todo el interés
+	Penalizacion float64 `json:"penalizacion"`
+}
+
+// Presupuesto es el plan mensual de ingresos y límites de gasto por categoría. Los gastos
+// son los movimientos del historial con la categoría correspondiente.
+type Presupuesto struct {
+	Ingresos   []IngresoPresupuesto   `json:"ingresos,omitempty"`
+	Categorias []CategoriaPresupuesto `json:"categorias,omitempty"`
+}
+
+// IngresoPresupuesto es un ingreso mensual fijo, como el sueldo o una renta
+type IngresoPresupuesto struct {
+	Nombre string  `json:"nombre"`
+	Monto  float64 `json:"monto"`
+}
+
+// CategoriaPresupuesto es el límite mensual de gasto de una categoría
+type CategoriaPresupuesto struct {
+	Nombre string  `json:"nombre"`
+	Limite float64 `json:"limite"`
+}
+
+// DatosRetiro son los datos de la cuenta Afore con los que se proyecta el retiro
+type DatosRetiro struct {
+	Nacimiento int     `json:"nacimiento"` // Año de nacimiento, calculado de la edad capturada
+	EdadRetiro int     `json:"edad_retiro"`
+	Salario    float64 `json:"salario"` // Salario mensual bruto
+	SaldoAfore float64 `json:"saldo_afore"`
+	Comision   float64 `json:"comision"`   // Comisión anual de la Afore sobre saldo
+	Voluntaria float64 `json:"voluntaria"` // Aportación voluntaria mensual
+	Semanas    int     `json:"semanas"`    // Semanas cotizadas al IMSS hasta hoy
+}
+
+// RegistroSaldo es el saldo de una tarjeta al cierre de un mes
+type RegistroSaldo struct {
+	Tipo    string  `json:"tipo"` // debito o credito
+	Tarjeta string  `json:"tarjeta"`
+	Mes     string  `json:"mes"` // Formato AAAA-MM
+	Saldo   float64 `json:"saldo"`
+}
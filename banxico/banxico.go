@@ -0,0 +1,208 @@
+// Package banxico consulta las series del Sistema de Información Económica (SIE) de Banco
+// de México y guarda una copia local para no repetir la consulta en cada comando.
+package banxico
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"finmex/storage"
+)
+
+// URL_SIE es la base del API REST del SIE
+const URL_SIE = "https://www.banxico.org.mx/SieAPIRest/service/v1"
+
+// Series de uso común. Las tasas y la inflación se publican en porcentaje; el FIX en pesos
+// por dólar y la UDI en pesos por UDI.
+const (
+	SerieInflacion = "SP30578" // Inflación general anual (INPC)
+	SerieTIIE28    = "SF60648" // TIIE a 28 días
+	SerieCetes28   = "SF43936" // CETES a 28 días, tasa de rendimiento en subasta
+	SerieFIX       = "SF43718" // Tipo de cambio FIX
+	SerieUDIS      = "SP68257" // Valor de la UDI
+)
+
+// VIGENCIA_CACHE es el tiempo que se reutiliza un dato antes de volver a consultarlo
+const VIGENCIA_CACHE = 24 * time.Hour
+
+// ErrSinToken indica que no hay token del SIE para consultar datos nuevos
+var ErrSinToken = errors.New("No hay token del SIE de Banxico configurado")
+
+// Dato es la observación más reciente de una serie
+type Dato struct {
+	Serie      string    `json:"serie"`
+	Titulo     string    `json:"titulo,omitempty"`
+	Fecha      string    `json:"fecha"` // Fecha de la observación, AAAA-MM-DD
+	Valor      float64   `json:"valor"`
+	Consultado time.Time `json:"consultado"` // Cuándo se obtuvo del SIE
+}
+
+// Cliente consulta el SIE usando el token de la cuenta del usuario
+type Cliente struct {
+	Token        string
+	URL          string
+	HTTP         *http.Client
+	ArchivoCache string        // Vacío para no guardar copia local
+	Vigencia     time.Duration // Cero para consultar siempre
+}
+
+// NuevoCliente crea un cliente con los valores por defecto
+func NuevoCliente(token, archivoCache string) *Cliente {
+	return &Cliente{
+		Token:        token,
+		URL:          URL_SIE,
+		HTTP:         &http.Client{Timeout: 10 * time.Second},
+		ArchivoCache: archivoCache,
+		Vigencia:     VIGENCIA_CACHE,
+	}
+}
+
+// Oportuno regresa el dato más reciente de cada serie, en el orden pedido. Usa la copia
+// local mientras esté vigente y solo consulta el SIE por las series que faltan o
+// caducaron. Si la consulta falla o no hay token, regresa el error junto con los datos que
+// haya en la copia local aunque ya no estén vigentes, para que quien llama decida si le
+// sirven.
+func (c *Cliente) Oportuno(series ...string) ([]Dato, error) {
+	cache := c.leerCache()
+
+	var pendientes []string
+	for _, s := range series {
+		d, ok := cache[s]
+		if !ok || c.Vigencia == 0 || time.Since(d.Consultado) > c.Vigencia {
+			pendientes = append(pendientes, s)
+		}
+	}
+	if len(pendientes) == 0 {
+		return ordenar(cache, series), nil
+	}
+	if c.Token == "" {
+		return ordenar(cache, series), ErrSinToken
+	}
+
+	nuevos, err := c.consultar(pendientes)
+	if err != nil {
+		return ordenar(cache, series), err
+	}
+	for _, d := range nuevos {
+		cache[d.Serie] = d
+	}
+	if err := c.guardarCache(cache); err != nil {
+		return ordenar(cache, series), fmt.Errorf("No se pudo guardar la copia local: %v", err)
+	}
+	return ordenar(cache, series), nil
+}
+
+// ordenar toma de la cache las series pedidas, omitiendo las que no estén
+func ordenar(cache map[string]Dato, series []string) []Dato {
+	var datos []Dato
+	for _, s := range series {
+		if d, ok := cache[s]; ok {
+			datos = append(datos, d)
+		}
+	}
+	return datos
+}
+
+// respuestaSIE es el formato de /series/{ids}/datos/oportuno
+type respuestaSIE struct {
+	Bmx struct {
+		Series []struct {
+			IDSerie string `json:"idSerie"`
+			Titulo  string `json:"titulo"`
+			Datos   []struct {
+				Fecha string `json:"fecha"` // dd/mm/aaaa
+				Dato  string `json:"dato"`  // Número con separador de miles o "N/E"
+			} `json:"datos"`
+		} `json:"series"`
+	} `json:"bmx"`
+}
+
+// consultar pide al SIE el dato oportuno de las series
+func (c *Cliente) consultar(series []string) ([]Dato, error) {
+	url := fmt.Sprintf("%s/series/%s/datos/oportuno", strings.TrimRight(c.URL, "/"), strings.Join(series, ","))
+	peticion, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	peticion.Header.Set("Bmx-Token", c.Token)
+	peticion.Header.Set("Accept", "application/json")
+
+	respuesta, err := c.HTTP.Do(peticion)
+	if err != nil {
+		return nil, fmt.Errorf("Error al consultar el SIE de Banxico: %v", err)
+	}
+	defer respuesta.Body.Close()
+	cuerpo, err := io.ReadAll(respuesta.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error al leer la respuesta del SIE: %v", err)
+	}
+	if respuesta.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("El SIE de Banxico respondió %s", respuesta.Status)
+	}
+
+	var r respuestaSIE
+	if err := json.Unmarshal(cuerpo, &r); err != nil {
+		return nil, fmt.Errorf("Respuesta del SIE inválida: %v", err)
+	}
+
+	ahora := time.Now()
+	var datos []Dato
+	for _, s := range r.Bmx.Series {
+		if len(s.Datos) == 0 {
+			continue
+		}
+		ultimo := s.Datos[len(s.Datos)-1]
+		valor, err := strconv.ParseFloat(strings.ReplaceAll(ultimo.Dato, ",", ""), 64)
+		if err != nil {
+			continue // "N/E": sin dato publicado
+		}
+		fecha, err := time.Parse("02/01/2006", ultimo.Fecha)
+		if err != nil {
+			return nil, fmt.Errorf("Fecha inválida en la serie %s: %s", s.IDSerie, ultimo.Fecha)
+		}
+		datos = append(datos, Dato{
+			Serie:      s.IDSerie,
+			Titulo:     s.Titulo,
+			Fecha:      fecha.Format("2006-01-02"),
+			Valor:      valor,
+			Consultado: ahora,
+		})
+	}
+	return datos, nil
+}
+
+// leerCache carga la copia local; si no existe o está dañada se empieza de cero
+func (c *Cliente) leerCache() map[string]Dato {
+	cache := map[string]Dato{}
+	if c.ArchivoCache == "" {
+		return cache
+	}
+	var datos []Dato
+	if storage.LeerJSON(c.ArchivoCache, &datos) != nil {
+		return cache
+	}
+	for _, d := range datos {
+		cache[d.Serie] = d
+	}
+	return cache
+}
+
+// guardarCache escribe la copia local
+func (c *Cliente) guardarCache(cache map[string]Dato) error {
+	if c.ArchivoCache == "" {
+		return nil
+	}
+	var datos []Dato
+	for _, d := range cache {
+		datos = append(datos, d)
+	}
+	sort.Slice(datos, func(i, j int) bool { return datos[i].Serie < datos[j].Serie })
+	return storage.GuardarJSON(c.ArchivoCache, datos)
+}
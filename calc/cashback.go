@@ -0,0 +1,92 @@
+package calc
+
+import (
+	"math"
+	"sort"
+)
+
+// Formas en que una tarjeta entrega la bonificación
+const (
+	CashbackAbono  = "abono"  // Se abona al saldo de la tarjeta y vale su monto
+	CashbackPuntos = "puntos" // Se acumulan puntos que se canjean por menos de su valor nominal
+)
+
+// VALOR_PUNTO_TIPICO es lo que rinde en pesos cada peso en puntos al canjearlo en el catálogo
+// de recompensas, cuando la tarjeta no tiene registrado otro valor
+const VALOR_PUNTO_TIPICO = 0.7
+
+// TasaCashback regresa la bonificación de la tarjeta en una categoría de gasto: la de la
+// categoría si tiene una propia, o la general
+func (t TarjetaCredito) TasaCashback(categoria string) float64 {
+	if tasa, ok := t.Categorias[categoria]; ok {
+		return tasa
+	}
+	return t.BeneficiosCashback
+}
+
+// AnualidadCondonada indica si el gasto mensual dado alcanza en un año la facturación con la
+// que el banco bonifica la anualidad
+func (t TarjetaCredito) AnualidadCondonada(gastoMensual float64) bool {
+	return t.FacturacionParaCondonar > 0 && gastoMensual*12 >= t.FacturacionParaCondonar
+}
+
+// AnualidadEfectiva es la anualidad que se paga con el gasto mensual dado: cero si se condona
+func (t TarjetaCredito) AnualidadEfectiva(gastoMensual float64) float64 {
+	if t.AnualidadCondonada(gastoMensual) {
+		return 0
+	}
+	return t.ComisionAnual
+}
+
+// ValorCanje regresa cuántos pesos vale cada peso bonificado
+func (t TarjetaCredito) ValorCanje() float64 {
+	if t.FormaCashback != CashbackPuntos {
+		return 1
+	}
+	if t.ValorPunto > 0 {
+		return t.ValorPunto
+	}
+	return VALOR_PUNTO_TIPICO
+}
+
+// CashbackCategoria es la bonificación de un mes de gasto en una categoría
+type CashbackCategoria struct {
+	Categoria    string  `json:"categoria"`
+	Gasto        float64 `json:"gasto"`
+	Tasa         float64 `json:"tasa"`
+	Bonificacion float64 `json:"bonificacion"`
+}
+
+// CashbackMes es lo que devuelve una tarjeta en un mes de gasto
+type CashbackMes struct {
+	Categorias []CashbackCategoria `json:"categorias"`
+	Bruto      float64             `json:"bruto"`  // Suma de las categorías
+	Topado     float64             `json:"topado"` // Después del tope mensual
+	Valor      float64             `json:"valor"`  // En pesos, después del valor de canje de los puntos
+}
+
+// CashbackMensual calcula la bonificación de un mes con el gasto por categoría: cada
+// categoría usa su tasa, la suma se limita al tope mensual y los puntos se valúan a su valor
+// de canje
+func CashbackMensual(t TarjetaCredito, gasto map[string]float64) CashbackMes {
+	var m CashbackMes
+	categorias := make([]string, 0, len(gasto))
+	for categoria := range gasto {
+		categorias = append(categorias, categoria)
+	}
+	sort.Strings(categorias)
+
+	for _, categoria := range categorias {
+		c := CashbackCategoria{Categoria: categoria, Gasto: gasto[categoria], Tasa: t.TasaCashback(categoria)}
+		c.Bonificacion = c.Gasto * c.Tasa
+		m.Categorias = append(m.Categorias, c)
+		m.Bruto += c.Bonificacion
+	}
+
+	m.Topado = m.Bruto
+	if t.TopeCashback > 0 {
+		m.Topado = math.Min(m.Bruto, t.TopeCashback)
+	}
+	m.Valor = m.Topado * t.ValorCanje()
+	return m
+}
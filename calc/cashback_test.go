@@ -0,0 +1,47 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCashbackMensualPorCategoria(t *testing.T) {
+	tarjeta := TarjetaCredito{BeneficiosCashback: 0.01, Categorias: map[string]float64{"supermercado": 0.05}}
+	m := CashbackMensual(tarjeta, map[string]float64{"supermercado": 4000, "otros": 6000})
+	if math.Abs(m.Bruto-260) > 0.001 || m.Valor != m.Bruto {
+		t.Errorf("5%% de 4000 más 1%% de 6000 abonados deben ser 260: %+v", m)
+	}
+	if len(m.Categorias) != 2 || m.Categorias[0].Categoria != "otros" {
+		t.Errorf("las categorías deben salir ordenadas: %+v", m.Categorias)
+	}
+}
+
+func TestCashbackMensualTopeYPuntos(t *testing.T) {
+	tarjeta := TarjetaCredito{BeneficiosCashback: 0.05, TopeCashback: 300, FormaCashback: CashbackPuntos}
+	m := CashbackMensual(tarjeta, map[string]float64{"otros": 10000})
+	if m.Bruto != 500 || m.Topado != 300 {
+		t.Errorf("el tope mensual debe limitar la bonificación a 300: %+v", m)
+	}
+	if math.Abs(m.Valor-300*VALOR_PUNTO_TIPICO) > 0.001 {
+		t.Errorf("los puntos sin valor registrado deben valuarse al típico: %+v", m)
+	}
+
+	tarjeta.ValorPunto = 1.2
+	if m := CashbackMensual(tarjeta, map[string]float64{"otros": 10000}); math.Abs(m.Valor-360) > 0.001 {
+		t.Errorf("con valor de canje registrado los puntos valen 1.2 pesos: %+v", m)
+	}
+}
+
+func TestAnualidadEfectiva(t *testing.T) {
+	tarjeta := TarjetaCredito{ComisionAnual: 1200, FacturacionParaCondonar: 60000}
+	if tarjeta.AnualidadEfectiva(5000) != 0 || !tarjeta.AnualidadCondonada(5000) {
+		t.Error("$5,000 al mes facturan $60,000 al año y condonan la anualidad")
+	}
+	if tarjeta.AnualidadEfectiva(4999) != 1200 {
+		t.Error("un gasto menor a la facturación paga la anualidad completa")
+	}
+	sinPrograma := TarjetaCredito{ComisionAnual: 1200}
+	if sinPrograma.AnualidadEfectiva(100000) != 1200 {
+		t.Error("sin facturación para condonar siempre se paga la anualidad")
+	}
+}
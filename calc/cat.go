@@ -0,0 +1,57 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+)
+
+// ResultadoCAT es el CAT de una tarjeta calculado con los flujos de su plan de pagos
+type ResultadoCAT struct {
+	Flujos     []float64 `json:"flujos"` // Flujos mensuales del acreditado; el 0 es la disposición menos la anualidad
+	Pagos      int       `json:"pagos"`
+	Intereses  float64   `json:"intereses"`
+	Comisiones float64   `json:"comisiones"` // Anualidades y comisiones mensuales de todo el plazo
+	TIRMensual float64   `json:"tir_mensual"`
+	CAT        float64   `json:"cat"`
+}
+
+// CalcularCAT calcula el CAT de una tarjeta con la metodología de Banxico: la TIR mensual de
+// los flujos del acreditado, anualizada con capitalización mensual. El acreditado recibe la
+// deuda en el mes 0 y paga cada mes lo que indica la tabla de amortización, sin IVA. La
+// anualidad se cobra al disponer y al empezar cada año mientras quede saldo; la comisión
+// mensual se suma a cada pago.
+func CalcularCAT(tarjeta TarjetaCredito, deuda, pago, comisionMensual float64) (ResultadoCAT, error) {
+	var r ResultadoCAT
+	if deuda <= 0 {
+		return r, fmt.Errorf("El monto del crédito debe ser mayor a cero para calcular el CAT")
+	}
+
+	tabla := TablaAmortizacion(deuda, tarjeta.TasaInteres/12, pagoAjustadoMinimo(tarjeta, deuda, pago, FrecuenciaMensual), 1000)
+	if n := len(tabla); n == 0 || tabla[n-1].SaldoFinal > 0 {
+		return r, fmt.Errorf("El pago de $%.2f no liquida la deuda; el CAT supone que el crédito se paga por completo", pago)
+	}
+
+	r.Pagos = len(tabla)
+	r.Flujos = make([]float64, len(tabla)+1)
+	r.Flujos[0] = deuda - tarjeta.ComisionAnual
+	r.Comisiones = tarjeta.ComisionAnual
+	for i, renglon := range tabla {
+		periodo := i + 1
+		r.Flujos[periodo] = -(renglon.Pago + comisionMensual)
+		r.Intereses += renglon.Interes
+		r.Comisiones += comisionMensual
+		// La anualidad del siguiente año se cobra con el último pago del año si queda saldo
+		if periodo%12 == 0 && renglon.SaldoFinal > 0 {
+			r.Flujos[periodo] -= tarjeta.ComisionAnual
+			r.Comisiones += tarjeta.ComisionAnual
+		}
+	}
+
+	tir, err := TIR(r.Flujos)
+	if err != nil {
+		return r, err
+	}
+	r.TIRMensual = tir
+	r.CAT = math.Pow(1+tir, 12) - 1
+	return r, nil
+}
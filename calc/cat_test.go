@@ -0,0 +1,52 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCATSinComisionesEsLaTasaEfectiva(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.36}
+	r, err := CalcularCAT(tarjeta, 10000, PagoFijo(10000, 0.03, 24), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperado := EfectivaDesdeNominal(0.36, 12); math.Abs(r.CAT-esperado) > 1e-6 {
+		t.Errorf("CAT = %.6f, se esperaba la tasa efectiva %.6f", r.CAT, esperado)
+	}
+	if r.Pagos != 24 || r.Comisiones != 0 {
+		t.Errorf("pagos = %d, comisiones = %.2f", r.Pagos, r.Comisiones)
+	}
+}
+
+func TestCATConAnualidad(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.36, ComisionAnual: 600}
+	pago := PagoFijo(10000, 0.03, 24)
+	r, err := CalcularCAT(tarjeta, 10000, pago, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Anualidad al disponer y al empezar el segundo año
+	if r.Comisiones != 1200 {
+		t.Errorf("comisiones = %.2f, se esperaban 1200", r.Comisiones)
+	}
+	if r.Flujos[0] != 9400 || math.Abs(r.Flujos[12]+pago+600) > 1e-9 {
+		t.Errorf("flujos 0 y 12 = %.2f, %.2f", r.Flujos[0], r.Flujos[12])
+	}
+	if r.CAT <= EfectivaDesdeNominal(0.36, 12) {
+		t.Errorf("la anualidad debe subir el CAT: %.6f", r.CAT)
+	}
+}
+
+func TestCATConPagoMinimoLiquida(t *testing.T) {
+	// Con pago cero se usa el mínimo regulatorio, que siempre cubre el interés más 1.5% del
+	// saldo, así que el crédito se liquida aun con una tasa enorme
+	tarjeta := TarjetaCredito{TasaInteres: 1.2}
+	r, err := CalcularCAT(tarjeta, 10000, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Pagos >= 1000 || r.CAT <= 1.2 {
+		t.Errorf("pagos = %d, CAT = %.4f", r.Pagos, r.CAT)
+	}
+}
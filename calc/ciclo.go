@@ -0,0 +1,114 @@
+package calc
+
+import (
+	"math"
+	"time"
+)
+
+// DIAS_AÑO_COMERCIAL son los días con que los bancos convierten la tasa anual en diaria
+const DIAS_AÑO_COMERCIAL = 360
+
+// CicloCorte es un periodo de facturación de una tarjeta de crédito
+type CicloCorte struct {
+	Inicio     time.Time `json:"inicio"` // Día siguiente al corte anterior
+	Corte      time.Time `json:"corte"`
+	LimitePago time.Time `json:"limite_pago"` // Último día para pagar sin generar intereses
+}
+
+// MovimientoDiario es un cargo (positivo) o un abono (negativo) en un día del periodo
+type MovimientoDiario struct {
+	Fecha time.Time
+	Monto float64
+}
+
+// fechaEnMes regresa el día del mes o, si el mes es más corto, su último día. Los meses
+// fuera de rango pasan al año anterior o siguiente.
+func fechaEnMes(año int, mes time.Month, dia int) time.Time {
+	primero := time.Date(año, mes, 1, 0, 0, 0, 0, time.UTC)
+	if ultimo := primero.AddDate(0, 1, -1).Day(); dia > ultimo {
+		dia = ultimo
+	}
+	return time.Date(primero.Year(), primero.Month(), dia, 0, 0, 0, 0, time.UTC)
+}
+
+// soloFecha quita la hora y la zona para contar días completos
+func soloFecha(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// diasEntre cuenta los días de desde a hasta
+func diasEntre(desde, hasta time.Time) int {
+	return int(math.Round(soloFecha(hasta).Sub(soloFecha(desde)).Hours() / 24))
+}
+
+// CicloDeCorte regresa el periodo que incluye la fecha: termina en el siguiente día de corte
+// (el mismo día si la fecha es de corte) y se paga en el siguiente día límite después del
+// corte. En meses más cortos el corte o el pago caen en el último día del mes.
+func CicloDeCorte(diaCorte, diaPago int, fecha time.Time) CicloCorte {
+	f := soloFecha(fecha)
+	corte := fechaEnMes(f.Year(), f.Month(), diaCorte)
+	if f.After(corte) {
+		corte = fechaEnMes(f.Year(), f.Month()+1, diaCorte)
+	}
+	anterior := fechaEnMes(corte.Year(), corte.Month()-1, diaCorte)
+	limite := fechaEnMes(corte.Year(), corte.Month(), diaPago)
+	if !limite.After(corte) {
+		limite = fechaEnMes(corte.Year(), corte.Month()+1, diaPago)
+	}
+	return CicloCorte{Inicio: anterior.AddDate(0, 0, 1), Corte: corte, LimitePago: limite}
+}
+
+// Ciclo regresa el periodo de la tarjeta que incluye la fecha; false si no tiene registrados
+// el día de corte y el día límite de pago
+func (t TarjetaCredito) Ciclo(fecha time.Time) (CicloCorte, bool) {
+	if t.DiaCorte == 0 || t.DiaLimitePago == 0 {
+		return CicloCorte{}, false
+	}
+	return CicloDeCorte(t.DiaCorte, t.DiaLimitePago, fecha), true
+}
+
+// Dias es la duración del periodo, contando el día de corte
+func (c CicloCorte) Dias() int {
+	return diasEntre(c.Inicio, c.Corte) + 1
+}
+
+// DiasFinanciamiento son los días que pasan entre una compra del periodo y la fecha límite
+// de pago: el financiamiento sin intereses si se paga el total a tiempo
+func (c CicloCorte) DiasFinanciamiento(compra time.Time) int {
+	return diasEntre(compra, c.LimitePago)
+}
+
+// Siguiente es el periodo que empieza después del corte
+func (c CicloCorte) Siguiente(diaCorte, diaPago int) CicloCorte {
+	return CicloDeCorte(diaCorte, diaPago, c.Corte.AddDate(0, 0, 1))
+}
+
+// SaldoPromedioDiario promedia el saldo al final de cada día del periodo, empezando con el
+// saldo del corte anterior. Los movimientos fuera del periodo se ignoran.
+func SaldoPromedioDiario(saldoInicial float64, ciclo CicloCorte, movimientos []MovimientoDiario) float64 {
+	dias := ciclo.Dias()
+	if dias <= 0 {
+		return 0
+	}
+	porDia := make([]float64, dias)
+	for _, m := range movimientos {
+		if d := diasEntre(ciclo.Inicio, m.Fecha); d >= 0 && d < dias {
+			porDia[d] += m.Monto
+		}
+	}
+	saldo, suma := saldoInicial, 0.0
+	for _, monto := range porDia {
+		saldo += monto
+		suma += saldo
+	}
+	return suma / float64(dias)
+}
+
+// InteresDiario es el interés de un periodo sobre el saldo promedio diario, sin IVA, con la
+// tasa anual convertida a diaria con el año comercial
+func InteresDiario(saldoPromedio, tasaAnual float64, dias int) float64 {
+	if saldoPromedio <= 0 || dias <= 0 {
+		return 0
+	}
+	return saldoPromedio * tasaAnual / DIAS_AÑO_COMERCIAL * float64(dias)
+}
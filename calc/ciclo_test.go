@@ -0,0 +1,73 @@
+package calc
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func fecha(texto string) time.Time {
+	f, err := time.Parse("2006-01-02", texto)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestCicloDeCorte(t *testing.T) {
+	casos := []struct {
+		diaCorte, diaPago     int
+		fecha                 string
+		inicio, corte, limite string
+	}{
+		{15, 5, "2026-10-14", "2026-09-16", "2026-10-15", "2026-11-05"},
+		{15, 5, "2026-10-15", "2026-09-16", "2026-10-15", "2026-11-05"},
+		{15, 5, "2026-10-16", "2026-10-16", "2026-11-15", "2026-12-05"},
+		// El pago puede caer en el mismo mes del corte
+		{3, 23, "2026-10-01", "2026-09-04", "2026-10-03", "2026-10-23"},
+		// Un corte el 31 cae el último día de los meses cortos
+		{31, 20, "2026-02-10", "2026-02-01", "2026-02-28", "2026-03-20"},
+		{31, 20, "2026-12-31", "2026-12-01", "2026-12-31", "2027-01-20"},
+	}
+	for _, c := range casos {
+		ciclo := CicloDeCorte(c.diaCorte, c.diaPago, fecha(c.fecha))
+		if !ciclo.Inicio.Equal(fecha(c.inicio)) || !ciclo.Corte.Equal(fecha(c.corte)) || !ciclo.LimitePago.Equal(fecha(c.limite)) {
+			t.Errorf("corte %d, pago %d, %s: %s a %s, pago %s", c.diaCorte, c.diaPago, c.fecha,
+				ciclo.Inicio.Format("2006-01-02"), ciclo.Corte.Format("2006-01-02"), ciclo.LimitePago.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestDiasFinanciamiento(t *testing.T) {
+	ciclo := CicloDeCorte(15, 5, fecha("2026-10-14"))
+	if d := ciclo.DiasFinanciamiento(fecha("2026-10-14")); d != 22 {
+		t.Errorf("una compra un día antes del corte tiene 22 días hasta el 5 de noviembre, no %d", d)
+	}
+	siguiente := ciclo.Siguiente(15, 5)
+	if d := siguiente.DiasFinanciamiento(siguiente.Inicio); d != 50 {
+		t.Errorf("una compra el día después del corte tiene 50 días, no %d", d)
+	}
+	if ciclo.Dias() != 30 {
+		t.Errorf("del 16 de septiembre al 15 de octubre hay 30 días, no %d", ciclo.Dias())
+	}
+}
+
+func TestSaldoPromedioDiario(t *testing.T) {
+	ciclo := CicloDeCorte(30, 20, fecha("2026-09-15")) // 31 de agosto al 30 de septiembre
+	if ciclo.Dias() != 31 {
+		t.Fatalf("el periodo debe tener 31 días, tiene %d", ciclo.Dias())
+	}
+	movimientos := []MovimientoDiario{
+		{fecha("2026-09-30"), 3100}, // Solo cuenta un día
+		{fecha("2026-10-05"), 9999}, // Fuera del periodo
+	}
+	if p := SaldoPromedioDiario(1000, ciclo, movimientos); math.Abs(p-1100) > 1e-9 {
+		t.Errorf("saldo promedio %.2f, se esperaban 1100", p)
+	}
+	if i := InteresDiario(1100, 0.36, 31); math.Abs(i-34.1) > 1e-9 {
+		t.Errorf("interés %.4f, se esperaban 34.10", i)
+	}
+	if InteresDiario(-50, 0.36, 31) != 0 {
+		t.Error("un saldo a favor no genera intereses")
+	}
+}
@@ -0,0 +1,130 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+)
+
+// VPN calcula el valor presente neto de flujos por periodo (el primero ocurre en el periodo 0)
+func VPN(tasaPeriodo float64, flujos []float64) float64 {
+	valor := 0.0
+	for i, flujo := range flujos {
+		valor += flujo / math.Pow(1+tasaPeriodo, float64(i))
+	}
+	return valor
+}
+
+// TIR calcula la tasa interna de retorno por periodo de una serie de flujos. Los flujos
+// deben cambiar de signo; se busca la tasa por bisección entre -99.99% y 100,000% por periodo.
+func TIR(flujos []float64) (float64, error) {
+	positivos, negativos := false, false
+	for _, f := range flujos {
+		if f > 0 {
+			positivos = true
+		}
+		if f < 0 {
+			negativos = true
+		}
+	}
+	if !positivos || !negativos {
+		return 0, fmt.Errorf("Los flujos deben incluir entradas y salidas de dinero para calcular la TIR")
+	}
+
+	bajo, alto := -0.9999, 1000.0
+	vpnBajo := VPN(bajo, flujos)
+	if vpnBajo*VPN(alto, flujos) > 0 {
+		return 0, fmt.Errorf("No se encontró una TIR para estos flujos")
+	}
+
+	for i := 0; i < 200; i++ {
+		medio := (bajo + alto) / 2
+		vpnMedio := VPN(medio, flujos)
+		if math.Abs(vpnMedio) < 1e-9 || alto-bajo < 1e-12 {
+			return medio, nil
+		}
+		if vpnBajo*vpnMedio < 0 {
+			alto = medio
+		} else {
+			bajo, vpnBajo = medio, vpnMedio
+		}
+	}
+	return (bajo + alto) / 2, nil
+}
+
+// TasaAnualEfectiva convierte una tasa por periodo en tasa efectiva anual
+func TasaAnualEfectiva(tasaPeriodo, periodosAño float64) float64 {
+	return math.Pow(1+tasaPeriodo, periodosAño) - 1
+}
+
+// PagoFijo calcula el pago constante por periodo que liquida un monto en n periodos
+func PagoFijo(monto, tasaPeriodo float64, n int) float64 {
+	if n <= 0 {
+		return 0
+	}
+	if tasaPeriodo == 0 {
+		return monto / float64(n)
+	}
+	factor := math.Pow(1+tasaPeriodo, float64(n))
+	return monto * tasaPeriodo * factor / (factor - 1)
+}
+
+// ValorFuturoAportaciones acumula una aportación mensual fija durante los meses indicados con
+// una tasa anual neta capitalizable cada mes
+func ValorFuturoAportaciones(mensual, tasaAnual float64, meses int) float64 {
+	saldo := 0.0
+	for i := 0; i < meses; i++ {
+		saldo = saldo*(1+tasaAnual/12) + mensual
+	}
+	return saldo
+}
+
+// ValorFuturo acumula un monto inicial más una aportación mensual fija con una tasa anual
+// capitalizable cada mes
+func ValorFuturo(inicial, mensual, tasaAnual float64, meses int) float64 {
+	return inicial*math.Pow(1+tasaAnual/12, float64(meses)) + ValorFuturoAportaciones(mensual, tasaAnual, meses)
+}
+
+// TasaRequerida resuelve por bisección la tasa anual, capitalizable cada mes, con la que el
+// monto inicial y las aportaciones alcanzan el objetivo en los meses indicados
+func TasaRequerida(inicial, mensual, objetivo float64, meses int) (float64, error) {
+	if meses <= 0 {
+		return 0, fmt.Errorf("El plazo debe ser de al menos un mes")
+	}
+	if inicial+mensual <= 0 {
+		return 0, fmt.Errorf("Se necesita un monto inicial o una aportación mensual")
+	}
+
+	bajo, alto := -0.99, 5.0
+	if ValorFuturo(inicial, mensual, alto, meses) < objetivo {
+		return 0, fmt.Errorf("El objetivo necesitaría una tasa mayor al %.0f%% anual", alto*100)
+	}
+	if ValorFuturo(inicial, mensual, bajo, meses) >= objetivo {
+		return bajo, nil
+	}
+	for i := 0; i < 100; i++ {
+		medio := (bajo + alto) / 2
+		if ValorFuturo(inicial, mensual, medio, meses) < objetivo {
+			bajo = medio
+		} else {
+			alto = medio
+		}
+	}
+	return alto, nil
+}
+
+// MAX_MESES_OBJETIVO es el plazo máximo que busca MesesParaObjetivo (100 años)
+const MAX_MESES_OBJETIVO = 1200
+
+// MesesParaObjetivo regresa cuántos meses tarda el monto inicial, con la aportación mensual
+// y la tasa anual capitalizable cada mes, en llegar al objetivo; -1 si no llega en
+// MAX_MESES_OBJETIVO meses
+func MesesParaObjetivo(inicial, mensual, tasaAnual, objetivo float64) int {
+	saldo := inicial
+	for meses := 0; meses <= MAX_MESES_OBJETIVO; meses++ {
+		if saldo >= objetivo {
+			return meses
+		}
+		saldo = saldo*(1+tasaAnual/12) + mensual
+	}
+	return -1
+}
@@ -0,0 +1,56 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTIRDePagoFijo(t *testing.T) {
+	const tasa = 0.025
+	pago := PagoFijo(12000, tasa, 18)
+
+	flujos := []float64{-12000}
+	for i := 0; i < 18; i++ {
+		flujos = append(flujos, pago)
+	}
+	if vpn := VPN(tasa, flujos); math.Abs(vpn) > 1e-6 {
+		t.Errorf("el VPN a la tasa del crédito debe ser cero: %.8f", vpn)
+	}
+	tir, err := TIR(flujos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(tir-tasa) > 1e-7 {
+		t.Errorf("TIR = %.8f, se esperaba %.8f", tir, tasa)
+	}
+}
+
+func TestTIRSinCambioDeSigno(t *testing.T) {
+	if _, err := TIR([]float64{100, 200}); err == nil {
+		t.Error("flujos sin salidas de dinero deben regresar error")
+	}
+}
+
+func TestTasaRequerida(t *testing.T) {
+	objetivo := ValorFuturo(5000, 1000, 0.09, 36)
+	tasa, err := TasaRequerida(5000, 1000, objetivo, 36)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(tasa-0.09) > 1e-6 {
+		t.Errorf("TasaRequerida = %.8f, se esperaba 0.09", tasa)
+	}
+}
+
+func TestMesesParaObjetivo(t *testing.T) {
+	objetivo := ValorFuturo(5000, 1000, 0.09, 36)
+	if m := MesesParaObjetivo(5000, 1000, 0.09, objetivo-0.01); m != 36 {
+		t.Errorf("MesesParaObjetivo = %d, se esperaban 36", m)
+	}
+	if m := MesesParaObjetivo(20000, 0, 0, 10000); m != 0 {
+		t.Errorf("un saldo que ya cubre el objetivo tarda 0 meses, no %d", m)
+	}
+	if m := MesesParaObjetivo(1000, 0, -0.02, 2000); m != -1 {
+		t.Errorf("sin aportes y con tasa real negativa nunca se llega: %d", m)
+	}
+}
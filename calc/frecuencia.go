@@ -0,0 +1,106 @@
+package calc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Frecuencia indica cada cuánto se realiza un pago o una aportación
+type Frecuencia string
+
+// Frecuencias de pago soportadas
+const (
+	FrecuenciaMensual    Frecuencia = "mensual"
+	FrecuenciaQuincenal  Frecuencia = "quincenal"  // Días 15 y último de cada mes
+	FrecuenciaCatorcenal Frecuencia = "catorcenal" // Cada 14 días
+	FrecuenciaSemanal    Frecuencia = "semanal"
+)
+
+// ParsearFrecuencia convierte un texto capturado por el usuario en una Frecuencia
+func ParsearFrecuencia(texto string) (Frecuencia, error) {
+	switch Frecuencia(strings.ToLower(strings.TrimSpace(texto))) {
+	case "", FrecuenciaMensual:
+		return FrecuenciaMensual, nil
+	case FrecuenciaQuincenal:
+		return FrecuenciaQuincenal, nil
+	case FrecuenciaCatorcenal:
+		return FrecuenciaCatorcenal, nil
+	case FrecuenciaSemanal:
+		return FrecuenciaSemanal, nil
+	}
+	return "", fmt.Errorf("Frecuencia inválida '%s' (usa mensual, quincenal, catorcenal o semanal)", texto)
+}
+
+// PeriodosPorAño regresa cuántos pagos de esta frecuencia ocurren en un año
+func (f Frecuencia) PeriodosPorAño() int {
+	switch f {
+	case FrecuenciaQuincenal:
+		return 24
+	case FrecuenciaCatorcenal:
+		return 26
+	case FrecuenciaSemanal:
+		return 52
+	default:
+		return 12
+	}
+}
+
+// Siguiente calcula la fecha del pago posterior a la fecha dada
+func (f Frecuencia) Siguiente(fecha time.Time) time.Time {
+	switch f {
+	case FrecuenciaQuincenal:
+		// Los pagos quincenales caen el día 15 y el último día del mes
+		ultimo := ultimoDiaDelMes(fecha)
+		if fecha.Day() < 15 {
+			return time.Date(fecha.Year(), fecha.Month(), 15, 0, 0, 0, 0, fecha.Location())
+		}
+		if fecha.Day() < ultimo.Day() {
+			return ultimo
+		}
+		return time.Date(fecha.Year(), fecha.Month()+1, 15, 0, 0, 0, 0, fecha.Location())
+	case FrecuenciaCatorcenal:
+		return fecha.AddDate(0, 0, 14)
+	case FrecuenciaSemanal:
+		return fecha.AddDate(0, 0, 7)
+	default:
+		return SumarMeses(fecha, 1)
+	}
+}
+
+// SumarMeses regresa el mismo día n meses después, o el último día del mes si es más corto
+// (31 de enero más un mes es el 28 o 29 de febrero). A diferencia de AddDate no se pasa al
+// mes siguiente.
+func SumarMeses(fecha time.Time, n int) time.Time {
+	primero := time.Date(fecha.Year(), fecha.Month()+time.Month(n), 1, 0, 0, 0, 0, fecha.Location())
+	dia := fecha.Day()
+	if ultimo := ultimoDiaDelMes(primero).Day(); dia > ultimo {
+		dia = ultimo
+	}
+	return time.Date(primero.Year(), primero.Month(), dia, 0, 0, 0, 0, fecha.Location())
+}
+
+// CalendarioPagos genera las fechas de los siguientes n pagos a partir de inicio; sin pagos
+// regresa una lista vacía. Los pagos mensuales se cuentan desde inicio y no desde el pago
+// anterior, para que un calendario que empieza el 31 vuelva al 31 después de febrero.
+func CalendarioPagos(inicio time.Time, f Frecuencia, n int) []time.Time {
+	if n <= 0 {
+		return nil
+	}
+	fechas := make([]time.Time, 0, n)
+	fecha := inicio
+	for i := 1; i <= n; i++ {
+		if f.PeriodosPorAño() == 12 {
+			fecha = SumarMeses(inicio, i)
+		} else {
+			fecha = f.Siguiente(fecha)
+		}
+		fechas = append(fechas, fecha)
+	}
+	return fechas
+}
+
+// ultimoDiaDelMes regresa la fecha del último día del mes de la fecha dada
+func ultimoDiaDelMes(fecha time.Time) time.Time {
+	return time.Date(fecha.Year(), fecha.Month()+1, 0, 0, 0, 0, 0, fecha.Location())
+}
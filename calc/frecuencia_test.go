@@ -0,0 +1,76 @@
+package calc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSiguiente(t *testing.T) {
+	fecha := func(s string) time.Time {
+		f, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	casos := []struct {
+		frecuencia Frecuencia
+		desde      string
+		esperado   string
+	}{
+		{FrecuenciaMensual, "2026-01-10", "2026-02-10"},
+		{FrecuenciaMensual, "2026-01-31", "2026-02-28"},
+		{FrecuenciaMensual, "2028-01-31", "2028-02-29"},
+		{FrecuenciaQuincenal, "2026-03-01", "2026-03-15"},
+		{FrecuenciaQuincenal, "2026-03-15", "2026-03-31"},
+		{FrecuenciaQuincenal, "2026-02-28", "2026-03-15"},
+		{FrecuenciaCatorcenal, "2026-12-25", "2027-01-08"},
+		{FrecuenciaSemanal, "2026-06-29", "2026-07-06"},
+	}
+	for _, c := range casos {
+		obtenido := c.frecuencia.Siguiente(fecha(c.desde)).Format("2006-01-02")
+		if obtenido != c.esperado {
+			t.Errorf("%s desde %s: %s, se esperaba %s", c.frecuencia, c.desde, obtenido, c.esperado)
+		}
+	}
+}
+
+func TestCalendarioPagosSinPagos(t *testing.T) {
+	inicio := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	for _, n := range []int{0, -1} {
+		if fechas := CalendarioPagos(inicio, FrecuenciaQuincenal, n); len(fechas) != 0 {
+			t.Errorf("%d pagos: %v", n, fechas)
+		}
+	}
+	if fechas := CalendarioPagos(inicio, FrecuenciaMensual, 2); len(fechas) != 2 || fechas[1].Format("2006-01-02") != "2026-03-10" {
+		t.Errorf("2 pagos: %v", fechas)
+	}
+}
+
+func TestCalendarioMensualNoSeRecorre(t *testing.T) {
+	inicio := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	esperadas := []string{"2027-01-31", "2027-02-28", "2027-03-31", "2027-04-30", "2027-05-31", "2028-02-29"}
+	fechas := CalendarioPagos(inicio, FrecuenciaMensual, 14)
+	for i, indice := range []int{0, 1, 2, 3, 4, 13} {
+		if obtenida := fechas[indice].Format("2006-01-02"); obtenida != esperadas[i] {
+			t.Errorf("pago %d: %s, se esperaba %s", indice+1, obtenida, esperadas[i])
+		}
+	}
+
+	// Un calendario que empieza el 30 vuelve al 30 después de febrero
+	fechas = CalendarioPagos(time.Date(2027, 1, 30, 0, 0, 0, 0, time.UTC), FrecuenciaMensual, 3)
+	if obtenida := fechas[2].Format("2006-01-02"); obtenida != "2027-04-30" {
+		t.Errorf("desde el 30: %s", obtenida)
+	}
+}
+
+func TestParsearFrecuencia(t *testing.T) {
+	if _, err := ParsearFrecuencia("diaria"); err == nil {
+		t.Error("una frecuencia desconocida debe regresar error")
+	}
+	f, err := ParsearFrecuencia("quincenal")
+	if err != nil || f.PeriodosPorAño() != 24 {
+		t.Errorf("quincenal: %v, %d periodos", err, f.PeriodosPorAño())
+	}
+}
@@ -0,0 +1,191 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TramoHipoteca es una parte del crédito hipotecario con su propia tasa, como la parte de
+// Infonavit y la del banco en un cofinanciamiento
+type TramoHipoteca struct {
+	Nombre    string  `json:"nombre"`
+	Monto     float64 `json:"monto"`
+	TasaAnual float64 `json:"tasa_anual"`
+	// Actualizacion es el aumento anual del saldo y de la mensualidad en los créditos en veces
+	// salario mínimo (VSM); cero en los créditos en pesos
+	Actualizacion float64 `json:"actualizacion,omitempty"`
+}
+
+// Hipoteca son las condiciones de un crédito hipotecario
+type Hipoteca struct {
+	ValorVivienda    float64
+	Meses            int
+	Tramos           []TramoHipoteca
+	SeguroVida       float64 // Fracción mensual del saldo insoluto (vida y desempleo)
+	SeguroDaños      float64 // Fracción anual del valor de la vivienda, cobrada cada mes
+	ComisionApertura float64 // Fracción del monto financiado, cobrada al disponer
+	GastosIniciales  float64 // Avalúo, estudio de crédito y otros cargos del banco al disponer
+	ComisionMensual  float64 // Comisión de administración en pesos
+
+	// Pagos anticipados a capital; se aplican primero al tramo de mayor tasa
+	AbonoMensual float64
+	AbonoAnual   float64 // Se abona cada doce meses, por ejemplo con el aguinaldo
+	ReducirPago  bool    // Los abonos bajan la mensualidad en lugar de acortar el plazo
+}
+
+// Financiado suma los montos de los tramos
+func (h Hipoteca) Financiado() float64 {
+	total := 0.0
+	for _, t := range h.Tramos {
+		total += t.Monto
+	}
+	return total
+}
+
+// MesHipoteca es un renglón de la tabla de amortización de la hipoteca
+type MesHipoteca struct {
+	Mes          int     `json:"mes"`
+	SaldoInicial float64 `json:"saldo_inicial"`
+	Interes      float64 `json:"interes"`
+	Capital      float64 `json:"capital"` // Parte de la mensualidad que reduce el saldo
+	Abono        float64 `json:"abono"`   // Pago anticipado a capital
+	Seguros      float64 `json:"seguros"`
+	Comisiones   float64 `json:"comisiones"`
+	Pago         float64 `json:"pago"` // Todo lo pagado en el mes, incluido el abono
+	SaldoFinal   float64 `json:"saldo_final"`
+}
+
+// SimulacionHipoteca es el resultado de recorrer la hipoteca mes por mes
+type SimulacionHipoteca struct {
+	Financiado    float64       `json:"financiado"`
+	CostoInicial  float64       `json:"costo_inicial"` // Comisión por apertura y gastos iniciales
+	Mensualidad   float64       `json:"mensualidad"`   // Primera mensualidad con seguros y comisiones, sin abonos
+	Plazo         int           `json:"plazo"`         // Meses hasta liquidar
+	Intereses     float64       `json:"intereses"`
+	Seguros       float64       `json:"seguros"`
+	Comisiones    float64       `json:"comisiones"` // Comisiones mensuales, sin el costo inicial
+	Abonos        float64       `json:"abonos"`
+	TotalPagado   float64       `json:"total_pagado"` // Incluye el costo inicial
+	Amortizacion  []MesHipoteca `json:"amortizacion"`
+	flujosCliente []float64
+}
+
+// estadoTramo lleva el saldo y la mensualidad de un tramo durante la simulación
+type estadoTramo struct {
+	TramoHipoteca
+	saldo, pago float64
+}
+
+// SimularHipoteca recorre el crédito mes por mes. Cada tramo se amortiza con pago fijo en el
+// plazo; en los tramos en VSM el saldo y la mensualidad suben cada aniversario. Los seguros se
+// cobran mientras haya saldo y los abonos se aplican después de la mensualidad.
+func SimularHipoteca(h Hipoteca) (SimulacionHipoteca, error) {
+	s := SimulacionHipoteca{Financiado: h.Financiado()}
+	if s.Financiado <= 0 {
+		return s, fmt.Errorf("El monto a financiar debe ser mayor a cero")
+	}
+	if h.Meses <= 0 {
+		return s, fmt.Errorf("El plazo debe ser de al menos un mes")
+	}
+
+	tramos := make([]*estadoTramo, 0, len(h.Tramos))
+	for _, t := range h.Tramos {
+		if t.Monto <= 0 {
+			continue
+		}
+		tramos = append(tramos, &estadoTramo{TramoHipoteca: t, saldo: t.Monto, pago: PagoFijo(t.Monto, t.TasaAnual/12, h.Meses)})
+	}
+	// Los abonos van primero a la deuda más cara
+	porTasa := append([]*estadoTramo(nil), tramos...)
+	sort.SliceStable(porTasa, func(i, j int) bool { return porTasa[i].TasaAnual > porTasa[j].TasaAnual })
+
+	s.CostoInicial = s.Financiado*h.ComisionApertura + h.GastosIniciales
+	s.TotalPagado = s.CostoInicial
+	s.flujosCliente = []float64{s.Financiado - s.CostoInicial}
+
+	saldoTotal := func() float64 {
+		total := 0.0
+		for _, t := range tramos {
+			total += t.saldo
+		}
+		return total
+	}
+
+	for mes := 1; mes <= h.Meses && saldoTotal() > 0; mes++ {
+		if mes > 1 && (mes-1)%12 == 0 {
+			for _, t := range tramos {
+				t.saldo *= 1 + t.Actualizacion
+				t.pago *= 1 + t.Actualizacion
+			}
+		}
+
+		r := MesHipoteca{Mes: mes, SaldoInicial: saldoTotal()}
+		r.Seguros = r.SaldoInicial*h.SeguroVida + h.ValorVivienda*h.SeguroDaños/12
+		r.Comisiones = h.ComisionMensual
+		for _, t := range tramos {
+			if t.saldo <= 0 {
+				continue
+			}
+			interes := t.saldo * t.TasaAnual / 12
+			pago := math.Min(t.pago, t.saldo+interes)
+			if mes == h.Meses {
+				pago = t.saldo + interes
+			}
+			t.saldo += interes - pago
+			r.Interes += interes
+			r.Capital += pago - interes
+		}
+
+		abono := h.AbonoMensual
+		if mes%12 == 0 {
+			abono += h.AbonoAnual
+		}
+		for _, t := range porTasa {
+			aplicado := math.Min(abono, t.saldo)
+			t.saldo -= aplicado
+			abono -= aplicado
+			r.Abono += aplicado
+		}
+		for _, t := range tramos {
+			if t.saldo < 0.01 {
+				t.saldo = 0
+			}
+			if h.ReducirPago && r.Abono > 0 && t.saldo > 0 {
+				t.pago = PagoFijo(t.saldo, t.TasaAnual/12, h.Meses-mes)
+			}
+		}
+
+		r.Pago = r.Interes + r.Capital + r.Abono + r.Seguros + r.Comisiones
+		r.SaldoFinal = saldoTotal()
+		if mes == 1 {
+			s.Mensualidad = r.Interes + r.Capital + r.Seguros + r.Comisiones
+		}
+		s.Intereses += r.Interes
+		s.Seguros += r.Seguros
+		s.Comisiones += r.Comisiones
+		s.Abonos += r.Abono
+		s.TotalPagado += r.Pago
+		s.Amortizacion = append(s.Amortizacion, r)
+		s.flujosCliente = append(s.flujosCliente, -r.Pago)
+	}
+	s.Plazo = len(s.Amortizacion)
+	return s, nil
+}
+
+// CATHipoteca calcula el CAT del crédito con el plan de pagos contratado, sin abonos
+// anticipados: la TIR mensual de lo que recibe el acreditado menos la comisión por apertura y
+// los gastos iniciales, contra las mensualidades con seguros y comisiones, anualizada con
+// capitalización mensual
+func CATHipoteca(h Hipoteca) (float64, error) {
+	h.AbonoMensual, h.AbonoAnual, h.ReducirPago = 0, 0, false
+	s, err := SimularHipoteca(h)
+	if err != nil {
+		return 0, err
+	}
+	tir, err := TIR(s.flujosCliente)
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(1+tir, 12) - 1, nil
+}
@@ -0,0 +1,87 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func hipotecaBase() Hipoteca {
+	return Hipoteca{
+		ValorVivienda: 2000000,
+		Meses:         240,
+		Tramos:        []TramoHipoteca{{Nombre: "Banco", Monto: 1600000, TasaAnual: 0.11}},
+	}
+}
+
+func TestHipotecaSinCostosEsPagoFijo(t *testing.T) {
+	s, err := SimularHipoteca(hipotecaBase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pago := PagoFijo(1600000, 0.11/12, 240)
+	if math.Abs(s.Mensualidad-pago) > 1e-6 {
+		t.Errorf("mensualidad = %.2f, se esperaba %.2f", s.Mensualidad, pago)
+	}
+	if s.Plazo != 240 || s.Amortizacion[239].SaldoFinal != 0 {
+		t.Errorf("plazo = %d, saldo final = %.2f", s.Plazo, s.Amortizacion[s.Plazo-1].SaldoFinal)
+	}
+	cat, err := CATHipoteca(hipotecaBase())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if esperado := EfectivaDesdeNominal(0.11, 12); math.Abs(cat-esperado) > 1e-6 {
+		t.Errorf("CAT = %.6f, se esperaba la tasa efectiva %.6f", cat, esperado)
+	}
+}
+
+func TestHipotecaCostosSubenElCAT(t *testing.T) {
+	h := hipotecaBase()
+	h.ComisionApertura = 0.01
+	h.SeguroVida = 0.0003
+	h.SeguroDaños = 0.0015
+	cat, err := CATHipoteca(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cat <= EfectivaDesdeNominal(0.11, 12) {
+		t.Errorf("los seguros y la comisión deben subir el CAT: %.6f", cat)
+	}
+}
+
+func TestHipotecaAbonosAcortanElPlazo(t *testing.T) {
+	base, _ := SimularHipoteca(hipotecaBase())
+	h := hipotecaBase()
+	h.AbonoMensual = 2000
+	s, err := SimularHipoteca(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Plazo >= base.Plazo || s.Intereses >= base.Intereses {
+		t.Errorf("plazo %d vs %d, intereses %.2f vs %.2f", s.Plazo, base.Plazo, s.Intereses, base.Intereses)
+	}
+
+	h.ReducirPago = true
+	r, err := SimularHipoteca(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Plazo != 240 || r.Amortizacion[1].Capital+r.Amortizacion[1].Interes >= base.Mensualidad {
+		t.Errorf("reducir pago: plazo %d, segunda mensualidad %.2f", r.Plazo, r.Amortizacion[1].Capital+r.Amortizacion[1].Interes)
+	}
+}
+
+func TestHipotecaVSMActualizaElSaldo(t *testing.T) {
+	h := hipotecaBase()
+	h.Tramos[0].Actualizacion = 0.05
+	s, err := SimularHipoteca(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pago := s.Amortizacion[12].Capital + s.Amortizacion[12].Interes
+	if math.Abs(pago/s.Mensualidad-1.05) > 1e-9 {
+		t.Errorf("la mensualidad del segundo año debe subir 5%%: %.2f vs %.2f", pago, s.Mensualidad)
+	}
+	if s.Plazo != 240 || s.Amortizacion[239].SaldoFinal != 0 {
+		t.Errorf("plazo = %d", s.Plazo)
+	}
+}
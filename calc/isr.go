@@ -0,0 +1,169 @@
+package calc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Regímenes con los que se calcula el ISR sobre intereses
+const (
+	// RegimenRetencion aplica la retención provisional que hace el banco: un porcentaje
+	// anual sobre el capital, sin importar cuánto rinda
+	RegimenRetencion = "retencion"
+	// RegimenInteresReal calcula el impuesto definitivo: el interés real (lo que rinde por
+	// encima de la inflación) se acumula a los demás ingresos y paga a la tasa marginal
+	RegimenInteresReal = "interes-real"
+)
+
+// TASA_MARGINAL_ISR es la tasa marginal con la que se acumula el interés real si no se
+// indica otra (el renglón de la tarifa anual donde cae un ingreso medio-alto)
+const TASA_MARGINAL_ISR = 0.30
+
+// TasasRetencionISR son las tasas anuales de retención sobre el capital que fija la Ley de
+// Ingresos de la Federación de cada año
+var TasasRetencionISR = map[int]float64{
+	2019: 0.0104,
+	2020: 0.0145,
+	2021: 0.0097,
+	2022: 0.0008,
+	2023: 0.0015,
+	2024: 0.0050,
+	2025: 0.0050,
+	2026: 0.0090,
+}
+
+// TasaRetencionISR regresa la tasa de retención de la LIF del año. Para años sin tasa
+// publicada se usa la del año conocido más cercano.
+func TasaRetencionISR(año int) float64 {
+	if tasa, ok := TasasRetencionISR[año]; ok {
+		return tasa
+	}
+	años := make([]int, 0, len(TasasRetencionISR))
+	for a := range TasasRetencionISR {
+		años = append(años, a)
+	}
+	sort.Ints(años)
+	if año < años[0] {
+		return TasasRetencionISR[años[0]]
+	}
+	return TasasRetencionISR[años[len(años)-1]]
+}
+
+// ISRIntereses describe cómo se calcula el ISR de los intereses de una cuenta o inversión
+type ISRIntereses struct {
+	Regimen      string
+	AñoFiscal    int
+	TasaMarginal float64 // Solo para RegimenInteresReal
+	Retencion    float64 // Reemplaza la retención de la LIF del AñoFiscal; cero usa la publicada
+}
+
+// TasaRetencion regresa la retención anual sobre el capital que se aplica
+func (i ISRIntereses) TasaRetencion() float64 {
+	if i.Retencion > 0 {
+		return i.Retencion
+	}
+	return TasaRetencionISR(i.AñoFiscal)
+}
+
+// ValidarRegimenISR revisa que el régimen sea uno de los conocidos
+func ValidarRegimenISR(regimen string) error {
+	switch regimen {
+	case RegimenRetencion, RegimenInteresReal:
+		return nil
+	}
+	return fmt.Errorf("Régimen de ISR inválido '%s' (usa %s o %s)", regimen, RegimenRetencion, RegimenInteresReal)
+}
+
+// TasaImpuesto regresa el ISR anual como fracción del capital para una tasa de rendimiento
+// bruta. La retención no puede ser mayor que los intereses, y un interés real negativo no
+// paga impuesto.
+func (i ISRIntereses) TasaImpuesto(tasa, inflacion float64) float64 {
+	if tasa <= 0 {
+		return 0
+	}
+	if i.Regimen == RegimenInteresReal {
+		real := tasa - inflacion
+		if real <= 0 {
+			return 0
+		}
+		return real * i.TasaMarginal
+	}
+	retencion := i.TasaRetencion()
+	if retencion > tasa {
+		return tasa
+	}
+	return retencion
+}
+
+// TasaNeta regresa la tasa de rendimiento después de ISR
+func (i ISRIntereses) TasaNeta(tasa, inflacion float64) float64 {
+	return tasa - i.TasaImpuesto(tasa, inflacion)
+}
+
+// TasaBruta es la inversa de TasaNeta: la tasa antes de impuestos que deja la tasa neta dada
+func (i ISRIntereses) TasaBruta(neta, inflacion float64) float64 {
+	if neta <= 0 {
+		return neta
+	}
+	if i.Regimen == RegimenInteresReal {
+		if neta <= inflacion || i.TasaMarginal >= 1 {
+			return neta
+		}
+		return (neta - inflacion*i.TasaMarginal) / (1 - i.TasaMarginal)
+	}
+	return neta + i.TasaRetencion()
+}
+
+// Descripcion resume el régimen para mostrarlo junto a los impuestos
+func (i ISRIntereses) Descripcion() string {
+	if i.Regimen == RegimenInteresReal {
+		return fmt.Sprintf("ISR %.0f%% sobre interés real, %d", i.TasaMarginal*100, i.AñoFiscal)
+	}
+	if i.Retencion > 0 {
+		return fmt.Sprintf("retención ISR %.2f%% sobre capital", i.Retencion*100)
+	}
+	return fmt.Sprintf("retención ISR %.2f%% sobre capital, LIF %d", TasaRetencionISR(i.AñoFiscal)*100, i.AñoFiscal)
+}
+
+// RenglonTarifaISR es un renglón de la tarifa de ISR para personas físicas
+type RenglonTarifaISR struct {
+	LimiteInferior float64
+	CuotaFija      float64
+	Tasa           float64 // Porcentaje sobre el excedente del límite inferior
+}
+
+// TarifaISRAnual es la tarifa del artículo 152 de la LISR vigente desde 2023
+var TarifaISRAnual = []RenglonTarifaISR{
+	{0.01, 0, 0.0192},
+	{8952.50, 171.88, 0.0640},
+	{75984.56, 4461.94, 0.1088},
+	{133536.08, 10723.55, 0.1600},
+	{155229.81, 14194.54, 0.1792},
+	{185852.58, 19682.13, 0.2136},
+	{374837.89, 60049.40, 0.2352},
+	{590795.00, 110842.74, 0.3000},
+	{1127926.85, 271981.99, 0.3200},
+	{1503902.47, 392294.17, 0.3400},
+	{4511707.38, 1414947.85, 0.3500},
+}
+
+// CalcularISR aplica una tarifa a la base gravable y regresa el impuesto y la tasa marginal
+func CalcularISR(tarifa []RenglonTarifaISR, base float64) (float64, float64) {
+	if base <= 0 || len(tarifa) == 0 {
+		return 0, 0
+	}
+	renglon := tarifa[0]
+	for _, r := range tarifa {
+		if base < r.LimiteInferior {
+			break
+		}
+		renglon = r
+	}
+	return renglon.CuotaFija + (base-renglon.LimiteInferior)*renglon.Tasa, renglon.Tasa
+}
+
+// ISRAnual calcula el impuesto anual de una persona física sobre su base gravable
+func ISRAnual(base float64) float64 {
+	impuesto, _ := CalcularISR(TarifaISRAnual, base)
+	return impuesto
+}
@@ -0,0 +1,52 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTasaRetencionISR(t *testing.T) {
+	if tasa := TasaRetencionISR(2024); tasa != 0.0050 {
+		t.Errorf("LIF 2024: %.4f", tasa)
+	}
+	if tasa := TasaRetencionISR(2030); tasa != TasasRetencionISR[2026] {
+		t.Errorf("un año futuro usa la última tasa publicada: %.4f", tasa)
+	}
+	if tasa := TasaRetencionISR(2000); tasa != TasasRetencionISR[2019] {
+		t.Errorf("un año anterior usa la primera tasa conocida: %.4f", tasa)
+	}
+}
+
+func TestISRIntereses(t *testing.T) {
+	retencion := ISRIntereses{Regimen: RegimenRetencion, AñoFiscal: 2026}
+	if neta := retencion.TasaNeta(0.10, 0.04); math.Abs(neta-0.091) > 1e-12 {
+		t.Errorf("retención sobre capital: neta %.4f", neta)
+	}
+	if imp := retencion.TasaImpuesto(0.005, 0.04); imp != 0.005 {
+		t.Errorf("la retención no puede superar los intereses: %.4f", imp)
+	}
+
+	// Una retención propia, como la de un escenario, reemplaza la de la LIF
+	propia := ISRIntereses{Regimen: RegimenRetencion, AñoFiscal: 2026, Retencion: 0.0115}
+	if imp := propia.TasaImpuesto(0.10, 0.04); imp != 0.0115 {
+		t.Errorf("retención propia: impuesto %.4f", imp)
+	}
+
+	real := ISRIntereses{Regimen: RegimenInteresReal, AñoFiscal: 2026, TasaMarginal: 0.30}
+	if imp := real.TasaImpuesto(0.10, 0.04); math.Abs(imp-0.018) > 1e-12 {
+		t.Errorf("interés real: impuesto %.4f", imp)
+	}
+	if imp := real.TasaImpuesto(0.03, 0.04); imp != 0 {
+		t.Errorf("un interés real negativo no paga: %.4f", imp)
+	}
+
+	for _, isr := range []ISRIntereses{retencion, real} {
+		if bruta := isr.TasaBruta(isr.TasaNeta(0.11, 0.04), 0.04); math.Abs(bruta-0.11) > 1e-12 {
+			t.Errorf("%s: TasaBruta no invierte TasaNeta: %.4f", isr.Regimen, bruta)
+		}
+	}
+
+	if ValidarRegimenISR("fijo") == nil {
+		t.Error("un régimen desconocido debe rechazarse")
+	}
+}
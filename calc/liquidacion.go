@@ -0,0 +1,246 @@
+package calc
+
+import "math"
+
+// EventoCredito modifica la simulación de un crédito a partir de un periodo de pago
+type EventoCredito struct {
+	Periodo    int     `json:"periodo"`               // Periodo (empezando en 1) en el que ocurre el evento
+	AbonoExtra float64 `json:"abono_extra,omitempty"` // Pago adicional a capital en ese periodo
+	CambiaTasa bool    `json:"cambia_tasa,omitempty"` // Indica si a partir de este periodo aplica NuevaTasa
+	NuevaTasa  float64 `json:"nueva_tasa,omitempty"`  // Tasa anual vigente desde este periodo (p. ej. al terminar una promoción)
+}
+
+// liquidacionPagoFijo calcula con la fórmula cerrada de NPER cuántos pagos constantes se
+// necesitan para liquidar la deuda y cuánto interés se paga en total. Reproduce las reglas
+// del simulador: el último pago solo cubre el saldo restante y se consideran pagados los
+// saldos menores a un centavo. Si el pago no alcanza a cubrir el interés, se calcula el
+// interés acumulado hasta el límite de pagos.
+func liquidacionPagoFijo(deuda, tasaPeriodo, pago float64, limitePagos int) (float64, int) {
+	if deuda <= 0 {
+		return 0, 0
+	}
+	if pago <= 0 {
+		// Sin pagos el saldo solo crece hasta el límite
+		return deuda*math.Pow(1+tasaPeriodo, float64(limitePagos)) - deuda, limitePagos
+	}
+
+	// Sin interés basta dividir la deuda entre el pago
+	if tasaPeriodo == 0 {
+		pagos := int(math.Ceil(deuda/pago - 1e-9))
+		if pagos > limitePagos {
+			return 0, limitePagos
+		}
+		return 0, pagos
+	}
+
+	// Si el pago no cubre el interés del periodo la deuda nunca se liquida
+	nper := limitePagos + 1
+	if pago > deuda*tasaPeriodo {
+		n := -math.Log(1-tasaPeriodo*deuda/pago) / math.Log(1+tasaPeriodo)
+		nper = int(math.Ceil(n - 1e-9))
+	}
+
+	if nper > limitePagos {
+		saldo := SaldoDespuesDePagos(deuda, tasaPeriodo, pago, limitePagos)
+		return pago*float64(limitePagos) + saldo - deuda, limitePagos
+	}
+
+	// Saldo antes del último pago; el último pago cubre ese saldo más su interés
+	completos := nper - 1
+	saldo := SaldoDespuesDePagos(deuda, tasaPeriodo, pago, completos)
+	if saldo < 0.01 {
+		return pago*float64(completos) + saldo - deuda, completos
+	}
+
+	totalPagado := pago*float64(completos) + saldo*(1+tasaPeriodo)
+	return totalPagado - deuda, nper
+}
+
+// SaldoDespuesDePagos regresa el saldo de una deuda después de n pagos constantes
+func SaldoDespuesDePagos(deuda, tasaPeriodo, pago float64, n int) float64 {
+	if tasaPeriodo == 0 {
+		return deuda - pago*float64(n)
+	}
+	factor := math.Pow(1+tasaPeriodo, float64(n))
+	return deuda*factor - pago*(factor-1)/tasaPeriodo
+}
+
+// simularCredito recorre el crédito periodo por periodo aplicando los eventos. Se usa solo
+// cuando hay abonos extraordinarios o cambios de tasa; para pagos constantes se usa
+// liquidacionPagoFijo.
+func simularCredito(deuda, tasaAnual, periodosAño, pago float64, limitePagos int, eventos []EventoCredito) (float64, int) {
+	porPeriodo := map[int][]EventoCredito{}
+	for _, e := range eventos {
+		porPeriodo[e.Periodo] = append(porPeriodo[e.Periodo], e)
+	}
+
+	tasaPeriodo := tasaAnual / periodosAño
+	deudaActual := deuda
+	pagos := 0
+	interesTotal := 0.0
+
+	for deudaActual > 0 && pagos < limitePagos { // Límite para evitar bucle infinito
+		abonoExtra := 0.0
+		for _, e := range porPeriodo[pagos+1] {
+			if e.CambiaTasa {
+				tasaPeriodo = e.NuevaTasa / periodosAño
+			}
+			abonoExtra += e.AbonoExtra
+		}
+
+		// Interés del periodo
+		interesPeriodo := deudaActual * tasaPeriodo
+		interesTotal += interesPeriodo
+
+		// Aplicamos el pago del periodo más los abonos extraordinarios
+		abono := math.Min(pago+abonoExtra, deudaActual+interesPeriodo)
+		deudaActual = deudaActual + interesPeriodo - abono
+
+		pagos++
+
+		// Si la deuda es muy pequeña, la consideramos pagada
+		if deudaActual < 0.01 {
+			deudaActual = 0
+		}
+	}
+
+	return interesTotal, pagos
+}
+
+// RenglonAmortizacion es un periodo de la tabla de amortización de un crédito
+type RenglonAmortizacion struct {
+	Periodo      int     `json:"periodo"`
+	SaldoInicial float64 `json:"saldo_inicial"`
+	Interes      float64 `json:"interes"`
+	Pago         float64 `json:"pago"`
+	IVA          float64 `json:"iva,omitempty"` // IVA del interés en las tarjetas de crédito
+	Capital      float64 `json:"capital"`       // Parte del pago que reduce la deuda; negativa si el pago no cubre el interés
+	SaldoFinal   float64 `json:"saldo_final"`
+}
+
+// TablaAmortizacion desglosa periodo por periodo la liquidación con pago fijo. Sigue las
+// mismas reglas que liquidacionPagoFijo, así que la suma de intereses coincide con el
+// costo calculado: el último pago solo cubre el saldo restante y los saldos menores a un
+// centavo se dan por pagados.
+func TablaAmortizacion(deuda, tasaPeriodo, pago float64, limitePagos int) []RenglonAmortizacion {
+	var tabla []RenglonAmortizacion
+	saldo := deuda
+	for saldo > 0 && len(tabla) < limitePagos {
+		renglon := RenglonAmortizacion{Periodo: len(tabla) + 1, SaldoInicial: saldo}
+		renglon.Interes = saldo * tasaPeriodo
+		renglon.Pago = math.Min(pago, saldo+renglon.Interes)
+		renglon.Capital = renglon.Pago - renglon.Interes
+
+		saldo = saldo + renglon.Interes - renglon.Pago
+		if saldo < 0.01 {
+			saldo = 0
+		}
+		renglon.SaldoFinal = saldo
+		tabla = append(tabla, renglon)
+	}
+	return tabla
+}
+
+// TablaAmortizacionCredito arma la tabla de amortización de una deuda en la tarjeta con
+// la frecuencia de pago indicada, ajustando el pago al mínimo como CostoCreditoFrecuencia.
+// El pago cubre primero el interés y su IVA, que se desglosa en cada renglón.
+func TablaAmortizacionCredito(tarjeta TarjetaCredito, deuda, pago float64, frecuencia Frecuencia) []RenglonAmortizacion {
+	periodosAño := float64(frecuencia.PeriodosPorAño())
+	tabla := TablaAmortizacion(deuda, TasaConIVA(tarjeta.TasaInteres)/periodosAño, pagoAjustadoMinimo(tarjeta, deuda, pago, frecuencia), 1000*int(periodosAño)/12)
+	for i := range tabla {
+		tabla[i].Interes, tabla[i].IVA = SepararIVA(tabla[i].Interes)
+	}
+	return tabla
+}
+
+// pagoAjustadoMinimo sube el pago al mínimo regulatorio del periodo si no lo alcanza
+func pagoAjustadoMinimo(tarjeta TarjetaCredito, deuda, pago float64, frecuencia Frecuencia) float64 {
+	return math.Max(pago, PagoMinimoPeriodo(tarjeta, deuda, frecuencia))
+}
+
+// PagoRequerido resuelve el pago constante por periodo que liquida la deuda en los meses
+// indicados con la frecuencia dada y regresa también el interés total que se paga, con IVA
+func PagoRequerido(tarjeta TarjetaCredito, deuda float64, meses int, frecuencia Frecuencia) (float64, float64) {
+	periodosAño := frecuencia.PeriodosPorAño()
+	pagos := int(math.Round(float64(meses*periodosAño) / 12))
+	if pagos < 1 {
+		pagos = 1
+	}
+	pago := PagoFijo(deuda, TasaConIVA(tarjeta.TasaInteres)/float64(periodosAño), pagos)
+	return pago, pago*float64(pagos) - deuda
+}
+
+// CostoCredito calcula el costo total de usar la tarjeta de crédito
+func CostoCredito(tarjeta TarjetaCredito, deuda float64, pagoMensual float64) (float64, int, float64) {
+	return CostoCreditoFrecuencia(tarjeta, deuda, pagoMensual, FrecuenciaMensual)
+}
+
+// CostoCreditoFrecuencia calcula el costo total del crédito cuando los pagos
+// se hacen con la frecuencia indicada. Regresa el número de pagos en lugar de meses.
+func CostoCreditoFrecuencia(tarjeta TarjetaCredito, deuda float64, pago float64, frecuencia Frecuencia) (float64, int, float64) {
+	return CostoCreditoEventos(tarjeta, deuda, pago, frecuencia, nil)
+}
+
+// DesgloseCredito es el costo de liquidar una deuda en la tarjeta separado en intereses,
+// comisiones y el IVA de cada uno
+type DesgloseCredito struct {
+	Intereses     float64 `json:"intereses"`
+	IVAIntereses  float64 `json:"iva_intereses"`
+	Comisiones    float64 `json:"comisiones"` // Anualidad prorrateada por el plazo
+	IVAComisiones float64 `json:"iva_comisiones"`
+	Total         float64 `json:"total"`
+	Pagos         int     `json:"pagos"`
+	Porcentaje    float64 `json:"porcentaje"` // Del monto original
+}
+
+// IVA regresa el IVA de los intereses y las comisiones
+func (d DesgloseCredito) IVA() float64 {
+	return d.IVAIntereses + d.IVAComisiones
+}
+
+// CostoCreditoEventos calcula el costo del crédito, con IVA, considerando eventos durante el
+// plazo como CostoCreditoDesglosado
+func CostoCreditoEventos(tarjeta TarjetaCredito, deuda float64, pago float64, frecuencia Frecuencia, eventos []EventoCredito) (float64, int, float64) {
+	d := CostoCreditoDesglosado(tarjeta, deuda, pago, frecuencia, eventos)
+	return d.Total, d.Pagos, d.Porcentaje
+}
+
+// CostoCreditoDesglosado calcula el costo del crédito considerando eventos durante el plazo
+// (abonos extraordinarios, tasas promocionales). Sin eventos y con pago constante se usa la
+// fórmula cerrada; con eventos se simula periodo por periodo. Los intereses y la anualidad
+// causan IVA: el de los intereses se cobra cada periodo y se paga junto con ellos, así que
+// la deuda se amortiza con la tasa más IVA.
+func CostoCreditoDesglosado(tarjeta TarjetaCredito, deuda float64, pago float64, frecuencia Frecuencia, eventos []EventoCredito) DesgloseCredito {
+	periodosAño := float64(frecuencia.PeriodosPorAño())
+	pago = pagoAjustadoMinimo(tarjeta, deuda, pago, frecuencia)
+
+	// Calculamos la tasa de interés por periodo de pago
+	tasaPeriodo := TasaConIVA(tarjeta.TasaInteres) / periodosAño
+	limitePagos := 1000 * int(periodosAño) / 12 // Equivalente a 1000 meses
+
+	var interesTotal float64
+	var d DesgloseCredito
+	if len(eventos) == 0 {
+		interesTotal, d.Pagos = liquidacionPagoFijo(deuda, tasaPeriodo, pago, limitePagos)
+	} else {
+		conIVA := make([]EventoCredito, len(eventos))
+		for i, e := range eventos {
+			conIVA[i] = e
+			conIVA[i].NuevaTasa = TasaConIVA(e.NuevaTasa)
+		}
+		interesTotal, d.Pagos = simularCredito(deuda, TasaConIVA(tarjeta.TasaInteres), periodosAño, pago, limitePagos, conIVA)
+	}
+	d.Intereses, d.IVAIntereses = SepararIVA(interesTotal)
+
+	// Comisión anual prorrateada por los periodos
+	d.Comisiones = tarjeta.ComisionAnual * float64(d.Pagos) / periodosAño
+	d.IVAComisiones = d.Comisiones * IVA
+	d.Total = interesTotal + d.Comisiones + d.IVAComisiones
+
+	// El cashback depende del gasto, no de la deuda, así que no se descuenta aquí; se valúa
+	// aparte con CashbackMensual. Sin deuda no hay porcentaje que calcular.
+	if deuda > 0 {
+		d.Porcentaje = d.Total / deuda * 100
+	}
+	return d
+}
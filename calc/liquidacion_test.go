@@ -0,0 +1,110 @@
+package calc
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+var casosLiquidacion = []struct {
+	nombre      string
+	deuda       float64
+	tasaPeriodo float64
+	pago        float64
+}{
+	{"pago holgado", 10000, 0.36 / 12, 1500},
+	{"pago justo", 25000, 0.45 / 12, 1250},
+	{"sin interés", 6000, 0, 1000},
+	{"quincenal", 18000, 0.52 / 24, 900},
+}
+
+func TestLiquidacionPagoFijoCoincideConTabla(t *testing.T) {
+	for _, c := range casosLiquidacion {
+		t.Run(c.nombre, func(t *testing.T) {
+			interes, pagos := liquidacionPagoFijo(c.deuda, c.tasaPeriodo, c.pago, 1000)
+			tabla := TablaAmortizacion(c.deuda, c.tasaPeriodo, c.pago, 1000)
+
+			if len(tabla) != pagos {
+				t.Fatalf("la tabla tiene %d pagos, la fórmula cerrada %d", len(tabla), pagos)
+			}
+			suma := 0.0
+			for _, r := range tabla {
+				suma += r.Interes
+			}
+			if math.Abs(suma-interes) > 0.01 {
+				t.Errorf("intereses de la tabla %.4f, fórmula cerrada %.4f", suma, interes)
+			}
+			if ultimo := tabla[len(tabla)-1]; ultimo.SaldoFinal != 0 {
+				t.Errorf("la tabla termina con saldo %.4f", ultimo.SaldoFinal)
+			}
+		})
+	}
+}
+
+func TestSimulacionSinEventosCoincideConFormula(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.42, ComisionAnual: 600}
+	for _, f := range []Frecuencia{FrecuenciaMensual, FrecuenciaQuincenal, FrecuenciaSemanal} {
+		cerrada, pagos, _ := CostoCreditoEventos(tarjeta, 15000, 800, f, nil)
+		// Un abono extra de cero obliga a simular sin cambiar el resultado
+		simulada, pagosSimulados, _ := CostoCreditoEventos(tarjeta, 15000, 800, f, []EventoCredito{{Periodo: 1}})
+		if pagos != pagosSimulados || math.Abs(cerrada-simulada) > 0.01 {
+			t.Errorf("%s: fórmula %.4f en %d pagos, simulación %.4f en %d pagos", f, cerrada, pagos, simulada, pagosSimulados)
+		}
+	}
+}
+
+func TestCostoCreditoAplicaPagoMinimo(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.36}
+	conMinimo, pagos, _ := CostoCredito(tarjeta, 10000, PagoMinimoPeriodo(tarjeta, 10000, FrecuenciaMensual))
+	debajo, pagosDebajo, _ := CostoCredito(tarjeta, 10000, 1)
+	if conMinimo != debajo || pagos != pagosDebajo {
+		t.Errorf("un pago menor al mínimo debe calcularse como el mínimo: %.2f/%d contra %.2f/%d", debajo, pagosDebajo, conMinimo, pagos)
+	}
+}
+
+func TestPagoRequeridoLiquidaEnPlazo(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.48}
+	pago, interes := PagoRequerido(tarjeta, 20000, 12, FrecuenciaMensual)
+	// Los intereses causan IVA, así que el pago amortiza la deuda con la tasa más IVA
+	if saldo := SaldoDespuesDePagos(20000, TasaConIVA(0.48)/12, pago, 12); math.Abs(saldo) > 0.01 {
+		t.Errorf("después de 12 pagos de %.2f queda un saldo de %.2f", pago, saldo)
+	}
+	if math.Abs(pago*12-20000-interes) > 1e-6 {
+		t.Errorf("el interés %.2f no corresponde a 12 pagos de %.2f", interes, pago)
+	}
+}
+
+func TestCostoCreditoDesglosaIVA(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.36, ComisionAnual: 600}
+	d := CostoCreditoDesglosado(tarjeta, 10000, 1500, FrecuenciaMensual, nil)
+	if math.Abs(d.IVAIntereses-d.Intereses*IVA) > 1e-9 || math.Abs(d.IVAComisiones-d.Comisiones*IVA) > 1e-9 {
+		t.Errorf("el IVA debe ser 16%% de intereses y comisiones: %+v", d)
+	}
+	if math.Abs(d.Total-d.Intereses-d.Comisiones-d.IVA()) > 1e-9 {
+		t.Errorf("el total no suma el desglose: %+v", d)
+	}
+
+	// La tabla de amortización desglosa el mismo interés y el mismo IVA
+	tabla := TablaAmortizacionCredito(tarjeta, 10000, 1500, FrecuenciaMensual)
+	var interes, iva float64
+	for _, r := range tabla {
+		interes += r.Interes
+		iva += r.IVA
+	}
+	if len(tabla) != d.Pagos || math.Abs(interes-d.Intereses) > 0.01 || math.Abs(iva-d.IVAIntereses) > 0.01 {
+		t.Errorf("tabla con %d pagos, $%.2f de interés y $%.2f de IVA; desglose %+v", len(tabla), interes, iva, d)
+	}
+}
+
+func TestCostoCreditoSinDeuda(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.36, ComisionAnual: 600}
+	for _, deuda := range []float64{0, -100} {
+		d := CostoCreditoDesglosado(tarjeta, deuda, 1500, FrecuenciaMensual, nil)
+		if math.IsNaN(d.Porcentaje) || math.IsInf(d.Porcentaje, 0) || d.Porcentaje != 0 {
+			t.Errorf("deuda %.0f: porcentaje %v", deuda, d.Porcentaje)
+		}
+		if _, err := json.Marshal(d); err != nil {
+			t.Errorf("deuda %.0f: el desglose debe poder emitirse como JSON: %v", deuda, err)
+		}
+	}
+}
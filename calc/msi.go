@@ -0,0 +1,175 @@
+package calc
+
+import (
+	"fmt"
+	"time"
+)
+
+// PlanMSI es una compra a meses sin intereses vigente en una tarjeta de crédito
+type PlanMSI struct {
+	Concepto string  `json:"concepto"`
+	Monto    float64 `json:"monto"`
+	Meses    int     `json:"meses"`
+	Inicio   string  `json:"inicio"` // Mes de la primera mensualidad, formato AAAA-MM
+}
+
+// Mensualidad regresa el pago fijo de cada mes del plan
+func (p PlanMSI) Mensualidad() float64 {
+	if p.Meses <= 0 {
+		return p.Monto
+	}
+	return p.Monto / float64(p.Meses)
+}
+
+// NumeroMensualidad regresa qué mensualidad (empezando en 1) toca en el mes dado; cero si
+// el plan no ha empezado o ya terminó
+func (p PlanMSI) NumeroMensualidad(mes time.Time) (int, error) {
+	inicio, err := time.Parse("2006-01", p.Inicio)
+	if err != nil {
+		return 0, fmt.Errorf("Mes de inicio inválido en el plan '%s': %s (usa AAAA-MM)", p.Concepto, p.Inicio)
+	}
+	n := (mes.Year()-inicio.Year())*12 + int(mes.Month()-inicio.Month()) + 1
+	if n < 1 || n > p.Meses {
+		return 0, nil
+	}
+	return n, nil
+}
+
+// AplicacionPago muestra cómo el banco reparte un pago entre las mensualidades de MSI y el
+// saldo revolvente en un mes
+type AplicacionPago struct {
+	Pago                 float64
+	MensualidadesMSI     float64 // Mensualidades de MSI que vencen en el mes
+	AplicadoMSI          float64
+	Revolvente           float64 // Saldo revolvente al corte
+	AplicadoRevolvente   float64
+	RevolventeRestante   float64 // Saldo revolvente que queda sin pagar y genera intereses
+	Interes              float64 // Interés del siguiente mes sobre el revolvente restante
+	IVAInteres           float64
+	SaldoMSIPendiente    float64 // Mensualidades de meses posteriores
+	PagoParaNoGenerarInt float64 // Mensualidades del mes más todo el revolvente
+	MSISinCubrir         float64 // Parte de las mensualidades que el pago no alcanzó a cubrir
+	Excedente            float64 // Pago por encima de lo necesario para no generar intereses
+}
+
+// AplicarPago simula cómo se aplica un pago en el mes dado: primero se cubren las
+// mensualidades de MSI que vencen y el resto se abona al saldo revolvente. El saldo
+// revolvente que queda genera intereses más IVA el siguiente mes.
+func AplicarPago(t TarjetaCredito, pago float64, mes time.Time) (AplicacionPago, error) {
+	a := AplicacionPago{Pago: pago, Revolvente: t.Saldo}
+
+	for _, p := range t.Planes {
+		n, err := p.NumeroMensualidad(mes)
+		if err != nil {
+			return a, err
+		}
+		if n == 0 {
+			continue
+		}
+		a.MensualidadesMSI += p.Mensualidad()
+		a.SaldoMSIPendiente += p.Mensualidad() * float64(p.Meses-n)
+	}
+
+	a.PagoParaNoGenerarInt = a.MensualidadesMSI + a.Revolvente
+
+	a.AplicadoMSI = pago
+	if a.AplicadoMSI > a.MensualidadesMSI {
+		a.AplicadoMSI = a.MensualidadesMSI
+	}
+	a.MSISinCubrir = a.MensualidadesMSI - a.AplicadoMSI
+
+	a.AplicadoRevolvente = pago - a.AplicadoMSI
+	if a.AplicadoRevolvente > a.Revolvente {
+		a.Excedente = a.AplicadoRevolvente - a.Revolvente
+		a.AplicadoRevolvente = a.Revolvente
+	}
+	a.RevolventeRestante = a.Revolvente - a.AplicadoRevolvente + a.MSISinCubrir
+
+	a.Interes = a.RevolventeRestante * t.TasaInteres / 12
+	a.IVAInteres = a.Interes * IVA
+	return a, nil
+}
+
+// UltimoMes regresa el mes de la última mensualidad del plan
+func (p PlanMSI) UltimoMes() (time.Time, error) {
+	inicio, err := time.Parse("2006-01", p.Inicio)
+	if err != nil {
+		return inicio, fmt.Errorf("Mes de inicio inválido en el plan '%s': %s (usa AAAA-MM)", p.Concepto, p.Inicio)
+	}
+	return inicio.AddDate(0, p.Meses-1, 0), nil
+}
+
+// Formas de pagar una compra que compara SimularCompraMSI
+const (
+	PagoContado   = "contado"
+	PagoMSI       = "msi"
+	PagoIntereses = "intereses" // Diferida en pagos fijos con la tasa de la tarjeta
+)
+
+// OpcionCompra es una forma de pagar una compra y lo que cuesta en valor presente
+type OpcionCompra struct {
+	Forma         string  `json:"forma"` // contado, msi o intereses
+	Meses         int     `json:"meses"`
+	Pago          float64 `json:"pago"` // Mensualidad; el precio con descuento si es de contado
+	TotalPagado   float64 `json:"total_pagado"`
+	Intereses     float64 `json:"intereses,omitempty"` // Al diferir con intereses, sin IVA
+	IVA           float64 `json:"iva,omitempty"`       // De los intereses
+	ValorPresente float64 `json:"valor_presente"`      // Pagos descontados a la tasa de oportunidad
+}
+
+// ValorPresentePagos descuenta a la tasa anual de oportunidad n pagos mensuales iguales,
+// el primero dentro de un mes
+func ValorPresentePagos(pago float64, n int, tasaOportunidad float64) float64 {
+	flujos := make([]float64, n+1)
+	for i := 1; i <= n; i++ {
+		flujos[i] = pago
+	}
+	return VPN(tasaOportunidad/12, flujos)
+}
+
+// SimularCompraMSI compara pagar una compra de contado, con el descuento que se ofrezca por
+// pagar en efectivo, contra pagarla a cada plazo de meses sin intereses mientras el dinero
+// sigue invertido a la tasa de oportunidad. Si tasaCredito es mayor a cero agrega, para cada
+// plazo, diferir la compra en pagos fijos con esa tasa anual más el IVA de los intereses. La
+// opción más barata es la de menor valor presente.
+func SimularCompraMSI(precio, descuentoContado, tasaOportunidad, tasaCredito float64, plazos []int) []OpcionCompra {
+	contado := precio * (1 - descuentoContado)
+	opciones := []OpcionCompra{{Forma: PagoContado, Pago: contado, TotalPagado: contado, ValorPresente: contado}}
+	for _, n := range plazos {
+		if n <= 0 {
+			continue
+		}
+		mensualidad := precio / float64(n)
+		opciones = append(opciones, OpcionCompra{
+			Forma:         PagoMSI,
+			Meses:         n,
+			Pago:          mensualidad,
+			TotalPagado:   precio,
+			ValorPresente: ValorPresentePagos(mensualidad, n, tasaOportunidad),
+		})
+		if tasaCredito > 0 {
+			pago := PagoFijo(precio, TasaConIVA(tasaCredito)/12, n)
+			o := OpcionCompra{
+				Forma:         PagoIntereses,
+				Meses:         n,
+				Pago:          pago,
+				TotalPagado:   pago * float64(n),
+				ValorPresente: ValorPresentePagos(pago, n, tasaOportunidad),
+			}
+			o.Intereses, o.IVA = SepararIVA(o.TotalPagado - precio)
+			opciones = append(opciones, o)
+		}
+	}
+	return opciones
+}
+
+// MejorOpcionCompra regresa el índice de la opción con menor valor presente
+func MejorOpcionCompra(opciones []OpcionCompra) int {
+	mejor := 0
+	for i, o := range opciones {
+		if o.ValorPresente < opciones[mejor].ValorPresente {
+			mejor = i
+		}
+	}
+	return mejor
+}
@@ -0,0 +1,48 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimularCompraMSI(t *testing.T) {
+	opciones := SimularCompraMSI(12000, 0.08, 0.10, 0.40, []int{3, 12})
+	if len(opciones) != 5 {
+		t.Fatalf("se esperaban contado y dos opciones por plazo, hay %d", len(opciones))
+	}
+	if contado := opciones[0]; contado.Forma != PagoContado || contado.ValorPresente != 11040 {
+		t.Errorf("contado con 8%% de descuento: %+v", contado)
+	}
+
+	for _, o := range opciones[1:] {
+		switch o.Forma {
+		case PagoMSI:
+			if o.TotalPagado != 12000 || o.ValorPresente >= 12000 {
+				t.Errorf("a %d MSI se paga el precio y su valor presente debe ser menor: %+v", o.Meses, o)
+			}
+		case PagoIntereses:
+			if o.TotalPagado <= 12000 {
+				t.Errorf("diferir %d meses con intereses debe costar más que el precio: %+v", o.Meses, o)
+			}
+		}
+	}
+
+	// Un plazo más largo a MSI vale menos hoy
+	if opciones[3].ValorPresente >= opciones[1].ValorPresente {
+		t.Errorf("12 MSI (%.2f) debe valer menos que 3 MSI (%.2f)", opciones[3].ValorPresente, opciones[1].ValorPresente)
+	}
+	if mejor := MejorOpcionCompra(opciones); opciones[mejor].Forma != PagoContado {
+		t.Errorf("con 8%% de descuento conviene el contado, se eligió %+v", opciones[mejor])
+	}
+}
+
+func TestValorPresentePagosTasaCredito(t *testing.T) {
+	// Descontar los pagos de un crédito a su propia tasa regresa el monto prestado
+	pago := PagoFijo(10000, 0.36/12, 12)
+	if vp := ValorPresentePagos(pago, 12, 0.36); math.Abs(vp-10000) > 1e-6 {
+		t.Errorf("valor presente %.6f, se esperaba 10000", vp)
+	}
+	if vp := ValorPresentePagos(500, 6, 0); vp != 3000 {
+		t.Errorf("sin tasa de oportunidad el valor presente es la suma: %.2f", vp)
+	}
+}
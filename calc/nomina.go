@@ -0,0 +1,253 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// tarifaISRVigente es una tarifa mensual de retenciones (LISR art. 96) con el año desde el
+// que aplica; rige hasta que se publica la siguiente
+type tarifaISRVigente struct {
+	Desde  int
+	Tarifa []RenglonTarifaISR
+}
+
+// tarifasISRMensual son las tarifas mensuales publicadas en el Anexo 8 de la RMF
+var tarifasISRMensual = []tarifaISRVigente{
+	{2018, []RenglonTarifaISR{
+		{0.01, 0, 0.0192},
+		{578.53, 11.11, 0.0640},
+		{4910.19, 288.33, 0.1088},
+		{8629.21, 692.96, 0.1600},
+		{10031.08, 917.26, 0.1792},
+		{12009.95, 1271.87, 0.2136},
+		{24222.32, 3880.44, 0.2352},
+		{38177.70, 7162.74, 0.3000},
+		{72887.51, 17575.69, 0.3200},
+		{97183.34, 25350.35, 0.3400},
+		{291550.01, 91435.02, 0.3500},
+	}},
+	{2023, []RenglonTarifaISR{
+		{0.01, 0, 0.0192},
+		{746.05, 14.32, 0.0640},
+		{6332.06, 371.83, 0.1088},
+		{11128.02, 893.63, 0.1600},
+		{12935.83, 1182.88, 0.1792},
+		{15487.72, 1640.18, 0.2136},
+		{31236.50, 5004.12, 0.2352},
+		{49233.01, 9236.89, 0.3000},
+		{93993.91, 22665.17, 0.3200},
+		{125325.21, 32691.18, 0.3400},
+		{375975.62, 117912.32, 0.3500},
+	}},
+}
+
+// TarifaISRMensual regresa la tarifa mensual vigente en el año indicado
+func TarifaISRMensual(año int) ([]RenglonTarifaISR, error) {
+	for i := len(tarifasISRMensual) - 1; i >= 0; i-- {
+		if año >= tarifasISRMensual[i].Desde {
+			return tarifasISRMensual[i].Tarifa, nil
+		}
+	}
+	return nil, fmt.Errorf("No hay tarifa de ISR para %d", año)
+}
+
+// renglonSubsidio es un renglón de la tabla de subsidio para el empleo anterior a mayo de 2024
+type renglonSubsidio struct {
+	LimiteInferior float64
+	Subsidio       float64
+}
+
+// tablaSubsidioEmpleo es la tabla mensual de subsidio para el empleo vigente hasta abril de 2024
+var tablaSubsidioEmpleo = []renglonSubsidio{
+	{0.01, 407.02},
+	{1768.97, 406.83},
+	{2653.39, 406.62},
+	{3472.85, 392.77},
+	{3537.88, 382.46},
+	{4446.16, 354.23},
+	{4717.19, 324.87},
+	{5335.43, 294.63},
+	{6224.68, 253.54},
+	{7113.91, 217.61},
+	{7382.34, 0},
+}
+
+// subsidioPorcentajeUMA es el esquema de subsidio desde mayo de 2024: un porcentaje de la UMA
+// mensual para quien gana hasta el límite, que solo puede reducir el ISR hasta cero
+type subsidioPorcentajeUMA struct {
+	Porcentaje float64
+	Limite     float64 // Ingreso mensual máximo que recibe el subsidio
+}
+
+// inicioSubsidioUMA es el mes en que el decreto del 1 de mayo de 2024 reemplazó la tabla de
+// subsidio por el porcentaje de la UMA
+var inicioSubsidioUMA = time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+var subsidiosEmpleo = map[int]subsidioPorcentajeUMA{
+	2024: {0.1182, 9081.00},
+	2025: {0.1380, 10171.00},
+}
+
+// salarioMinimoDiario es el salario mínimo general de cada año, sin la zona libre de la frontera
+var salarioMinimoDiario = map[int]float64{
+	2022: 172.87,
+	2023: 207.44,
+	2024: 248.93,
+	2025: 278.80,
+}
+
+// SalarioMinimoDiario regresa el salario mínimo general del año; para años sin valor
+// publicado se usa el más reciente conocido
+func SalarioMinimoDiario(año int) (float64, error) {
+	if valor, ok := salarioMinimoDiario[año]; ok {
+		return valor, nil
+	}
+	ultimo := 0
+	for a := range salarioMinimoDiario {
+		if a > ultimo {
+			ultimo = a
+		}
+	}
+	if año < ultimo {
+		return 0, fmt.Errorf("No hay valor del salario mínimo para %d", año)
+	}
+	return salarioMinimoDiario[ultimo], nil
+}
+
+// Cuotas obreras del IMSS sobre el salario base de cotización (LSS arts. 25, 106, 107, 147 y 168)
+const (
+	CUOTA_IMSS_EXCEDENTE       = 0.0040  // Enfermedad y maternidad, sobre el excedente de 3 UMA
+	CUOTA_IMSS_DINERO          = 0.0025  // Enfermedad y maternidad, prestaciones en dinero
+	CUOTA_IMSS_PENSIONADOS     = 0.00375 // Gastos médicos de pensionados
+	CUOTA_IMSS_INVALIDEZ       = 0.00625 // Invalidez y vida
+	CUOTA_IMSS_CESANTIA        = 0.01125 // Cesantía en edad avanzada y vejez
+	TOPE_SBC_UMA               = 25      // El salario base de cotización se topa en 25 UMA
+	DIAS_AGUINALDO_LEY         = 15
+	DIAS_VACACIONES_PRIMER_AÑO = 12
+	PRIMA_VACACIONAL_LEY       = 0.25
+)
+
+// FactorIntegracionMinimo integra al salario las prestaciones de ley del primer año:
+// aguinaldo, vacaciones y prima vacacional
+func FactorIntegracionMinimo() float64 {
+	return 1 + (DIAS_AGUINALDO_LEY+DIAS_VACACIONES_PRIMER_AÑO*PRIMA_VACACIONAL_LEY)/365.0
+}
+
+// ConceptoNomina es un renglón de deducción del recibo
+type ConceptoNomina struct {
+	Concepto string  `json:"concepto"`
+	Importe  float64 `json:"importe"`
+}
+
+// ReciboNomina es el desglose de un sueldo mensual como en un recibo de nómina
+type ReciboNomina struct {
+	Año               int              `json:"año"`
+	Mes               time.Month       `json:"mes"`
+	SueldoBruto       float64          `json:"sueldo_bruto"`
+	SBCDiario         float64          `json:"sbc_diario"` // Salario base de cotización ya topado
+	ISRTarifa         float64          `json:"isr_tarifa"` // ISR según la tarifa, antes del subsidio
+	TasaMarginal      float64          `json:"tasa_marginal"`
+	Subsidio          float64          `json:"subsidio"`           // Subsidio para el empleo aplicado
+	SubsidioEntregado float64          `json:"subsidio_entregado"` // Parte del subsidio que se paga en efectivo (hasta abril de 2024)
+	ISR               float64          `json:"isr"`                // ISR retenido después del subsidio
+	IMSS              []ConceptoNomina `json:"imss"`
+	TotalIMSS         float64          `json:"total_imss"`
+	Neto              float64          `json:"neto"`
+	// SalarioMinimo indica que solo se percibe el salario mínimo: no se retiene ISR y el patrón
+	// paga las cuotas obreras (LISR art. 96 y LSS art. 36)
+	SalarioMinimo bool `json:"salario_minimo"`
+}
+
+// CalcularReciboNomina calcula el ISR, el subsidio para el empleo y las cuotas obreras del
+// IMSS de un sueldo mensual con la tarifa del año. El mes decide el esquema de subsidio en
+// 2024, que cambió en mayo. Sin factor de integración se usa el mínimo de ley.
+func CalcularReciboNomina(sueldo float64, año int, mes time.Month, factorIntegracion float64) (ReciboNomina, error) {
+	r := ReciboNomina{Año: año, Mes: mes, SueldoBruto: sueldo}
+	if mes < time.January || mes > time.December {
+		return r, fmt.Errorf("Mes inválido %d (usa 1 a 12)", mes)
+	}
+	tarifa, err := TarifaISRMensual(año)
+	if err != nil {
+		return r, err
+	}
+	umaDiaria, err := UMADiaria(año)
+	if err != nil {
+		return r, err
+	}
+	minimo, err := SalarioMinimoDiario(año)
+	if err != nil {
+		return r, err
+	}
+	if factorIntegracion <= 0 {
+		factorIntegracion = FactorIntegracionMinimo()
+	}
+	r.SalarioMinimo = sueldo/DIAS_MES_UMA <= minimo+0.005
+
+	r.ISRTarifa, r.TasaMarginal = CalcularISR(tarifa, sueldo)
+	r.Subsidio = subsidioEmpleo(sueldo, año, mes, umaDiaria)
+	r.ISR = r.ISRTarifa - r.Subsidio
+	if r.SalarioMinimo {
+		r.Subsidio, r.ISR = 0, 0
+	}
+	if r.ISR < 0 {
+		// Con la tabla anterior a mayo de 2024 el subsidio que excede al ISR se entrega al
+		// trabajador
+		r.SubsidioEntregado = -r.ISR
+		r.ISR = 0
+	}
+
+	r.SBCDiario = math.Min(sueldo/DIAS_MES_UMA*factorIntegracion, umaDiaria*TOPE_SBC_UMA)
+	sbcMensual := r.SBCDiario * DIAS_MES_UMA
+	excedente := math.Max(0, r.SBCDiario-3*umaDiaria) * DIAS_MES_UMA
+	r.IMSS = []ConceptoNomina{
+		{"Enfermedad y maternidad (excedente de 3 UMA)", excedente * CUOTA_IMSS_EXCEDENTE},
+		{"Prestaciones en dinero", sbcMensual * CUOTA_IMSS_DINERO},
+		{"Gastos médicos de pensionados", sbcMensual * CUOTA_IMSS_PENSIONADOS},
+		{"Invalidez y vida", sbcMensual * CUOTA_IMSS_INVALIDEZ},
+		{"Cesantía y vejez", sbcMensual * CUOTA_IMSS_CESANTIA},
+	}
+	for i := range r.IMSS {
+		if r.SalarioMinimo {
+			r.IMSS[i].Importe = 0
+		}
+		r.TotalIMSS += r.IMSS[i].Importe
+	}
+
+	r.Neto = sueldo - r.ISR + r.SubsidioEntregado - r.TotalIMSS
+	return r, nil
+}
+
+// subsidioEmpleo regresa el subsidio para el empleo de un ingreso mensual. Desde mayo de 2024
+// se usa el esquema de porcentaje de la UMA, que no puede exceder al ISR; para años
+// posteriores al último decreto conocido se usa el más reciente.
+func subsidioEmpleo(ingreso float64, año int, mes time.Month, umaDiaria float64) float64 {
+	if time.Date(año, mes, 1, 0, 0, 0, 0, time.UTC).Before(inicioSubsidioUMA) {
+		subsidio := 0.0
+		for _, r := range tablaSubsidioEmpleo {
+			if ingreso < r.LimiteInferior {
+				break
+			}
+			subsidio = r.Subsidio
+		}
+		return subsidio
+	}
+
+	esquema, ok := subsidiosEmpleo[año]
+	if !ok {
+		ultimo := 0
+		for a := range subsidiosEmpleo {
+			if a > ultimo {
+				ultimo = a
+			}
+		}
+		esquema = subsidiosEmpleo[ultimo]
+	}
+	if ingreso > esquema.Limite {
+		return 0
+	}
+	tarifa, _ := TarifaISRMensual(año)
+	isr, _ := CalcularISR(tarifa, ingreso)
+	return math.Min(isr, esquema.Porcentaje*umaDiaria*DIAS_MES_UMA)
+}
@@ -0,0 +1,109 @@
+package calc
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTarifaISRMensualPorRenglon(t *testing.T) {
+	tarifa, err := TarifaISRMensual(2024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Un sueldo en cada renglón del Anexo 8 vigente desde 2023
+	casos := []struct {
+		sueldo, isr, marginal float64
+	}{
+		{500, 9.60, 0.0192},
+		{3000, 158.57, 0.0640},
+		{8000, 553.30, 0.1088},
+		{12000, 1033.15, 0.1600},
+		{15000, 1552.78, 0.1792},
+		{20000, 2604.00, 0.2136},
+		{40000, 7065.30, 0.2352},
+		{60000, 12466.99, 0.3000},
+		{100000, 24587.12, 0.3200},
+		{200000, 58080.61, 0.3400},
+		{400000, 126320.85, 0.3500},
+	}
+	for _, c := range casos {
+		isr, marginal := CalcularISR(tarifa, c.sueldo)
+		if math.Abs(isr-c.isr) > 0.01 || marginal != c.marginal {
+			t.Errorf("sueldo %.0f: ISR %.2f al %.2f%%, se esperaba %.2f al %.2f%%", c.sueldo, isr, marginal*100, c.isr, c.marginal*100)
+		}
+	}
+
+	if anterior, _ := TarifaISRMensual(2022); anterior[1].LimiteInferior != 578.53 {
+		t.Errorf("2022 usa la tarifa de 2018: %+v", anterior[1])
+	}
+	if _, err := TarifaISRMensual(2017); err == nil {
+		t.Error("no hay tarifa antes de 2018")
+	}
+}
+
+func TestReciboSalarioMinimoExento(t *testing.T) {
+	minimo := 248.93 * DIAS_MES_UMA
+	r, err := CalcularReciboNomina(minimo, 2024, time.June, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.SalarioMinimo || r.ISR != 0 || r.TotalIMSS != 0 || r.Neto != minimo {
+		t.Errorf("el salario mínimo no paga ISR ni cuotas: %+v", r)
+	}
+
+	r, _ = CalcularReciboNomina(minimo+100, 2024, time.June, 0)
+	if r.SalarioMinimo || r.TotalIMSS == 0 {
+		t.Errorf("arriba del mínimo sí se descuentan cuotas: %+v", r)
+	}
+}
+
+func TestReciboTopeSBC(t *testing.T) {
+	tope := 108.57 * TOPE_SBC_UMA
+	for _, sueldo := range []float64{100000, 250000} {
+		r, err := CalcularReciboNomina(sueldo, 2024, time.June, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(r.SBCDiario-tope) > 1e-9 {
+			t.Errorf("sueldo %.0f: SBC %.2f, se topa en 25 UMA (%.2f)", sueldo, r.SBCDiario, tope)
+		}
+		// Excedente de 3 UMA al 0.40% más 2.375% del SBC mensual topado
+		if math.Abs(r.TotalIMSS-2250.13) > 0.01 {
+			t.Errorf("sueldo %.0f: cuotas %.2f, se esperaban 2250.13", sueldo, r.TotalIMSS)
+		}
+	}
+}
+
+func TestSubsidioEmpleo2024CambiaEnMayo(t *testing.T) {
+	// De enero a abril rige la tabla anterior, que ya no da subsidio arriba de $7,382.34
+	abril, err := CalcularReciboNomina(8000, 2024, time.April, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abril.Subsidio != 0 || math.Abs(abril.ISR-553.30) > 0.01 {
+		t.Errorf("abril de 2024 usa la tabla: subsidio %.2f, ISR %.2f", abril.Subsidio, abril.ISR)
+	}
+	if r, _ := CalcularReciboNomina(7000, 2023, time.December, 0); r.Subsidio != 253.54 {
+		t.Errorf("tabla de 2023: subsidio %.2f", r.Subsidio)
+	}
+
+	// Desde mayo es 11.82% de la UMA mensual para quien gana hasta $9,081
+	mayo, _ := CalcularReciboNomina(8000, 2024, time.May, 0)
+	if math.Abs(mayo.Subsidio-390.12) > 0.01 || math.Abs(mayo.ISR-163.18) > 0.01 {
+		t.Errorf("mayo de 2024: subsidio %.2f, ISR %.2f", mayo.Subsidio, mayo.ISR)
+	}
+	if arriba, _ := CalcularReciboNomina(9100, 2024, time.May, 0); arriba.Subsidio != 0 {
+		t.Errorf("arriba del límite no hay subsidio: %.2f", arriba.Subsidio)
+	}
+
+	// En 2025 es 13.8% de la UMA mensual
+	r, _ := CalcularReciboNomina(9000, 2025, time.March, 0)
+	if math.Abs(r.Subsidio-474.64) > 0.01 || math.Abs(r.ISR-187.46) > 0.01 {
+		t.Errorf("subsidio 2025: %.2f, ISR %.2f", r.Subsidio, r.ISR)
+	}
+
+	if _, err := CalcularReciboNomina(9000, 2025, 13, 0); err == nil {
+		t.Error("mes 13 debe ser inválido")
+	}
+}
@@ -0,0 +1,61 @@
+package calc
+
+import "math"
+
+// Regla del pago mínimo de las tarjetas de crédito (Circular 13/2011 de Banxico, supervisada
+// por la CNBV). El mínimo real cambia cada mes con el saldo; PagoMinimoPeriodo fija el del
+// primer mes como pago constante.
+const (
+	PAGO_MINIMO_SALDO  = 0.015  // Del saldo revolvente al corte, sin los intereses ni el IVA del periodo
+	PAGO_MINIMO_LIMITE = 0.0125 // Del límite de crédito
+)
+
+// PagoMinimoRegulatorio es el pago mínimo de un periodo: el mayor entre el 1.5% del saldo
+// revolvente más los intereses del periodo con su IVA y el 1.25% del límite de crédito. Si el
+// adeudo es menor, el mínimo es el adeudo. El saldo no incluye los intereses del periodo.
+func PagoMinimoRegulatorio(saldo, interes, limite float64) float64 {
+	adeudo := saldo + interes*(1+IVA)
+	minimo := math.Max(saldo*PAGO_MINIMO_SALDO+interes*(1+IVA), limite*PAGO_MINIMO_LIMITE)
+	return math.Min(minimo, adeudo)
+}
+
+// PagoMinimoPeriodo es el pago mínimo regulatorio del primer mes de la deuda con el límite de
+// crédito de la tarjeta, repartido entre los pagos del mes de la frecuencia. Los simuladores
+// de pago fijo lo usan como piso: después del primer mes el mínimo solo baja con el saldo.
+func PagoMinimoPeriodo(t TarjetaCredito, deuda float64, frecuencia Frecuencia) float64 {
+	minimo := PagoMinimoRegulatorio(deuda, deuda*t.TasaInteres/12, t.LimiteCredito)
+	return minimo * 12 / float64(frecuencia.PeriodosPorAño())
+}
+
+// ProyeccionPagoMinimo es lo que cuesta liquidar una deuda pagando cada mes solo el mínimo,
+// como el recuadro de advertencia de los estados de cuenta
+type ProyeccionPagoMinimo struct {
+	PagoInicial float64 `json:"pago_inicial"` // Mínimo del primer mes; después baja con el saldo
+	Meses       int     `json:"meses"`
+	Intereses   float64 `json:"intereses"` // Con IVA
+	TotalPagado float64 `json:"total_pagado"`
+	Liquidada   bool    `json:"liquidada"` // Falso si no se liquida en 1000 meses
+}
+
+// ProyectarPagoMinimo simula mes por mes la deuda de la tarjeta pagando solo el mínimo
+// regulatorio. No hay compras nuevas y el interés mensual es la tasa anual entre 12.
+func ProyectarPagoMinimo(t TarjetaCredito, deuda float64) ProyeccionPagoMinimo {
+	var p ProyeccionPagoMinimo
+	saldo := deuda
+	for saldo > 0 && p.Meses < 1000 {
+		interes := saldo * t.TasaInteres / 12
+		pago := PagoMinimoRegulatorio(saldo, interes, t.LimiteCredito)
+		if p.Meses == 0 {
+			p.PagoInicial = pago
+		}
+		p.Meses++
+		p.Intereses += interes * (1 + IVA)
+		p.TotalPagado += pago
+		saldo = saldo + interes*(1+IVA) - pago
+		if saldo < 0.01 {
+			saldo = 0
+		}
+	}
+	p.Liquidada = saldo == 0
+	return p
+}
@@ -0,0 +1,49 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPagoMinimoRegulatorio(t *testing.T) {
+	// $20,000 al 36%: $600 de interés, 1.5% del saldo más interés con IVA es $996
+	if minimo := PagoMinimoRegulatorio(20000, 600, 30000); math.Abs(minimo-996) > 1e-9 {
+		t.Errorf("mínimo por saldo %.2f, se esperaba 996", minimo)
+	}
+	// Con poco saldo y un límite alto manda el 1.25% del límite, pero nunca más que el adeudo
+	if minimo := PagoMinimoRegulatorio(5000, 150, 100000); math.Abs(minimo-1250) > 1e-9 {
+		t.Errorf("mínimo por límite %.2f, se esperaba 1250", minimo)
+	}
+	if minimo := PagoMinimoRegulatorio(1000, 30, 100000); math.Abs(minimo-1034.8) > 1e-9 {
+		t.Errorf("mínimo topado al adeudo %.2f, se esperaba 1034.80", minimo)
+	}
+}
+
+func TestPagoMinimoPeriodo(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.36, LimiteCredito: 30000}
+	if minimo := PagoMinimoPeriodo(tarjeta, 20000, FrecuenciaMensual); math.Abs(minimo-996) > 1e-9 {
+		t.Errorf("mínimo mensual %.2f, se esperaba 996", minimo)
+	}
+	if minimo := PagoMinimoPeriodo(tarjeta, 20000, FrecuenciaQuincenal); math.Abs(minimo-498) > 1e-9 {
+		t.Errorf("mínimo quincenal %.2f, se esperaba la mitad del mensual", minimo)
+	}
+	// Con poca deuda manda el 1.25% del límite
+	if minimo := PagoMinimoPeriodo(tarjeta, 5000, FrecuenciaMensual); math.Abs(minimo-375) > 1e-9 {
+		t.Errorf("mínimo por límite %.2f, se esperaba 375", minimo)
+	}
+}
+
+func TestProyectarPagoMinimo(t *testing.T) {
+	tarjeta := TarjetaCredito{TasaInteres: 0.36, LimiteCredito: 30000}
+	p := ProyectarPagoMinimo(tarjeta, 20000)
+	if !p.Liquidada || math.Abs(p.PagoInicial-996) > 1e-9 {
+		t.Fatalf("proyección %+v", p)
+	}
+	if math.Abs(p.TotalPagado-20000-p.Intereses) > 1e-6 {
+		t.Errorf("lo pagado (%.2f) debe ser la deuda más los intereses (%.2f)", p.TotalPagado, p.Intereses)
+	}
+	// Pagar el mínimo tarda más y cuesta más que liquidar en un año con pago fijo
+	if fijo := PagoFijo(20000, 0.36*(1+IVA)/12, 12); p.Meses <= 12 || p.Intereses <= fijo*12-20000 {
+		t.Errorf("el mínimo tarda %d meses con $%.2f de intereses", p.Meses, p.Intereses)
+	}
+}
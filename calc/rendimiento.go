@@ -0,0 +1,219 @@
+package calc
+
+import (
+	"math"
+	"sort"
+)
+
+// TasaUnica regresa los tramos de una cuenta que paga la misma tasa por todo el saldo
+func TasaUnica(tasa float64) []TramoRendimiento {
+	return []TramoRendimiento{{Tasa: tasa}}
+}
+
+// MigrarTramos convierte la tasa única del formato anterior en un tramo sin tope
+func (t *TarjetaDebito) MigrarTramos() {
+	if len(t.Tramos) == 0 && t.TasaRendimiento != 0 {
+		t.Tramos = TasaUnica(t.TasaRendimiento)
+	}
+	t.TasaRendimiento = 0
+}
+
+// TramosOrdenados regresa los tramos ordenados por monto, con el tramo sin tope al final. Una
+// cuenta sin migrar vale como un solo tramo con su tasa anterior.
+func (t TarjetaDebito) TramosOrdenados() []TramoRendimiento {
+	if len(t.Tramos) == 0 {
+		if t.TasaRendimiento == 0 {
+			return nil
+		}
+		return TasaUnica(t.TasaRendimiento)
+	}
+	tramos := append([]TramoRendimiento(nil), t.Tramos...)
+	sort.SliceStable(tramos, func(i, j int) bool {
+		if tramos[i].Hasta == 0 || tramos[j].Hasta == 0 {
+			return tramos[j].Hasta == 0 && tramos[i].Hasta != 0
+		}
+		return tramos[i].Hasta < tramos[j].Hasta
+	})
+	return tramos
+}
+
+// TasaPonderada regresa la tasa anual que gana el saldo: el promedio de las tasas de los
+// tramos ponderado por la parte del saldo que cae en cada uno. El saldo que pasa del último
+// tope no gana nada. Sin saldo regresa la tasa del primer tramo, que es la que se anuncia.
+func (t TarjetaDebito) TasaPonderada(saldo float64) float64 {
+	tramos := t.TramosOrdenados()
+	if len(tramos) == 0 {
+		return 0
+	}
+	if saldo <= 0 {
+		return tramos[0].Tasa
+	}
+
+	desde, interes := 0.0, 0.0
+	for _, tramo := range tramos {
+		hasta := saldo
+		if tramo.Hasta > 0 && tramo.Hasta < hasta {
+			hasta = tramo.Hasta
+		}
+		if hasta > desde {
+			interes += (hasta - desde) * tramo.Tasa
+			desde = hasta
+		}
+		if desde >= saldo {
+			break
+		}
+	}
+	return interes / saldo
+}
+
+// RendimientoReal calcula el rendimiento real de un año después de ISR, comisiones e
+// inflación. Regresa el rendimiento real en pesos, el mismo como porcentaje del saldo y el
+// saldo final.
+func RendimientoReal(tarjeta TarjetaDebito, saldo, inflacion float64, isr ISRIntereses) (float64, float64, float64) {
+	// Calculamos solo si el saldo es mayor al mínimo requerido
+	if saldo < tarjeta.SaldoMinimo {
+		return 0, 0, saldo - tarjeta.ComisionAnual
+	}
+
+	// Rendimiento anual bruto con la tasa de los tramos que alcanza el saldo
+	tasa := tarjeta.TasaPonderada(saldo)
+	rendimientoBruto := saldo * tasa
+
+	// ISR según el régimen: retención sobre el capital o sobre el interés real
+	impuestos := saldo * isr.TasaImpuesto(tasa, inflacion)
+
+	// Rendimiento neto después de impuestos
+	rendimientoNeto := rendimientoBruto - impuestos
+
+	// Pérdida por inflación
+	perdidaInflacion := saldo * inflacion
+
+	// Rendimiento real (considerando inflación)
+	rendimientoReal := rendimientoNeto - perdidaInflacion - tarjeta.ComisionAnual
+
+	// Saldo final después de un año
+	saldoFinal := saldo + rendimientoReal
+
+	return rendimientoReal, rendimientoReal / saldo * 100, saldoFinal
+}
+
+// TasaEnPesos es el rendimiento en pesos de una tasa en otra moneda cuando el tipo de cambio
+// varía en el año: con 4% en dólares y el dólar 5% más caro se gana 9.2% en pesos
+func TasaEnPesos(tasa, variacionCambiaria float64) float64 {
+	return (1+tasa)*(1+variacionCambiaria) - 1
+}
+
+// RendimientoRealDivisa calcula en pesos el rendimiento real de un año de una cuenta en otra
+// moneda. El saldo está en la moneda de la cuenta y se convierte con el tipo de cambio de hoy;
+// la variación es el cambio esperado del tipo de cambio en el año. El ISR grava la ganancia en
+// pesos, que incluye la cambiaria, y la comisión se paga al tipo de cambio de fin de año.
+// Regresa lo mismo que RendimientoReal, en pesos.
+func RendimientoRealDivisa(tarjeta TarjetaDebito, saldo, tipoCambio, variacionCambiaria, inflacion float64, isr ISRIntereses) (float64, float64, float64) {
+	pesos := saldo * tipoCambio
+	comision := tarjeta.ComisionAnual * tipoCambio * (1 + variacionCambiaria)
+	if saldo < tarjeta.SaldoMinimo {
+		return 0, 0, pesos - comision
+	}
+
+	tasa := TasaEnPesos(tarjeta.TasaPonderada(saldo), variacionCambiaria)
+	impuestos := pesos * isr.TasaImpuesto(tasa, inflacion)
+	rendimientoReal := pesos*tasa - impuestos - pesos*inflacion - comision
+	return rendimientoReal, rendimientoReal / pesos * 100, pesos + rendimientoReal
+}
+
+// SaldoEquilibrio calcula el saldo a partir del cual el rendimiento real de la cuenta se vuelve
+// positivo: el rendimiento neto de ISR debe cubrir la inflación y la comisión anual, y el saldo
+// debe alcanzar el mínimo que exige la cuenta. Regresa false si ningún saldo gana, porque la
+// tasa neta de los tramos no supera la inflación.
+func SaldoEquilibrio(t TarjetaDebito, inflacion float64, isr ISRIntereses) (float64, bool) {
+	ganancia := func(saldo float64) float64 {
+		return saldo*(isr.TasaNeta(t.TasaPonderada(saldo), inflacion)-inflacion) - t.ComisionAnual
+	}
+	// Sin comisión basta con que la tasa neta del saldo mínimo supere la inflación
+	sinComision := t.ComisionAnual <= 0 && isr.TasaNeta(t.TasaPonderada(t.SaldoMinimo), inflacion) > inflacion
+	if sinComision || ganancia(t.SaldoMinimo) > 0 {
+		return t.SaldoMinimo, true
+	}
+
+	// Dentro de un tramo la ganancia crece o decrece sin cambiar de sentido, así que basta
+	// encontrar el primer tramo que termina ganando y buscar ahí el punto de equilibrio
+	desde := t.SaldoMinimo
+	for _, tramo := range t.TramosOrdenados() {
+		if tramo.Hasta > 0 && tramo.Hasta <= desde {
+			continue
+		}
+		hasta := tramo.Hasta
+		if hasta == 0 {
+			if isr.TasaNeta(tramo.Tasa, inflacion) <= inflacion {
+				return 0, false
+			}
+			hasta = math.Max(2*desde, 1)
+			for ganancia(hasta) <= 0 {
+				hasta *= 2
+			}
+		}
+		if ganancia(hasta) > 0 {
+			for i := 0; i < 200; i++ {
+				if medio := (desde + hasta) / 2; ganancia(medio) > 0 {
+					hasta = medio
+				} else {
+					desde = medio
+				}
+			}
+			return hasta, true
+		}
+		desde = hasta
+	}
+	return 0, false
+}
+
+// AñoProyeccion es un año de la proyección de una cuenta con aportaciones
+type AñoProyeccion struct {
+	Año          int     `json:"año"`
+	SaldoInicial float64 `json:"saldo_inicial"`
+	Aportaciones float64 `json:"aportaciones"`
+	Intereses    float64 `json:"intereses"` // Intereses brutos capitalizados cada mes
+	Impuestos    float64 `json:"impuestos"`
+	Comisiones   float64 `json:"comisiones"`
+	SaldoFinal   float64 `json:"saldo_final"`
+	SaldoReal    float64 `json:"saldo_real"`    // Saldo final en pesos de hoy
+	GananciaReal float64 `json:"ganancia_real"` // Saldo real menos todo lo aportado, también en pesos de hoy
+}
+
+// ProyectarRendimiento proyecta el saldo de la cuenta año por año con capitalización
+// mensual y una aportación al final de cada mes. Los meses en que el saldo no llega al
+// mínimo no generan intereses. Al cierre de cada año se descuentan el ISR sobre el saldo
+// promedio del año, según el régimen, y la comisión anual. La inflación se compone para
+// expresar cada saldo y cada aportación en pesos de hoy.
+func ProyectarRendimiento(t TarjetaDebito, saldo, aportacionMensual float64, años int, inflacion float64, isr ISRIntereses) []AñoProyeccion {
+	proyeccion := make([]AñoProyeccion, 0, años)
+	aportadoReal := saldo
+	for año := 1; año <= años; año++ {
+		a := AñoProyeccion{Año: año, SaldoInicial: saldo}
+		suma := 0.0
+		for mes := 1; mes <= 12; mes++ {
+			if saldo >= t.SaldoMinimo {
+				interes := saldo * t.TasaPonderada(saldo) / 12
+				a.Intereses += interes
+				saldo += interes
+			}
+			suma += saldo
+			saldo += aportacionMensual
+			a.Aportaciones += aportacionMensual
+			aportadoReal += aportacionMensual / math.Pow(1+inflacion, float64((año-1)*12+mes)/12)
+		}
+
+		if a.Intereses > 0 {
+			promedio := suma / 12
+			a.Impuestos = math.Max(0, promedio*isr.TasaImpuesto(t.TasaPonderada(promedio), inflacion))
+		}
+		a.Comisiones = t.ComisionAnual
+		saldo = math.Max(0, saldo-a.Impuestos-a.Comisiones)
+
+		a.SaldoFinal = saldo
+		a.SaldoReal = saldo / math.Pow(1+inflacion, float64(año))
+		a.GananciaReal = a.SaldoReal - aportadoReal
+		proyeccion = append(proyeccion, a)
+	}
+	return proyeccion
+}
@@ -0,0 +1,168 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+var retencion2024 = ISRIntereses{Regimen: RegimenRetencion, AñoFiscal: 2024}
+
+func TestRendimientoReal(t *testing.T) {
+	tarjeta := TarjetaDebito{TasaRendimiento: 0.10, ComisionAnual: 100, SaldoMinimo: 1000}
+
+	// 10% bruto, 9.5% después de la retención de 0.5%, menos 4% de inflación y la comisión
+	real, porcentaje, final := RendimientoReal(tarjeta, 10000, 0.04, retencion2024)
+	if math.Abs(real-450) > 1e-9 || math.Abs(porcentaje-4.5) > 1e-9 || math.Abs(final-10450) > 1e-9 {
+		t.Errorf("RendimientoReal = %.2f, %.2f%%, %.2f; se esperaba 450, 4.5%%, 10450", real, porcentaje, final)
+	}
+
+	// Con interés real: 6% por encima de la inflación, al 30% de tasa marginal
+	real, _, _ = RendimientoReal(tarjeta, 10000, 0.04, ISRIntereses{Regimen: RegimenInteresReal, AñoFiscal: 2024, TasaMarginal: 0.30})
+	if math.Abs(real-320) > 1e-9 {
+		t.Errorf("con interés real se esperaba 320, salió %.2f", real)
+	}
+
+	// Debajo del saldo mínimo no hay rendimiento y sí se cobra la comisión
+	real, _, final = RendimientoReal(tarjeta, 500, 0.04, retencion2024)
+	if real != 0 || final != 400 {
+		t.Errorf("debajo del mínimo: rendimiento %.2f, saldo final %.2f", real, final)
+	}
+}
+
+func TestRendimientoRealDivisa(t *testing.T) {
+	// Sin variación y a un peso por unidad es lo mismo que una cuenta en pesos
+	tarjeta := TarjetaDebito{TasaRendimiento: 0.10, ComisionAnual: 100, SaldoMinimo: 1000}
+	if real, _, _ := RendimientoRealDivisa(tarjeta, 10000, 1, 0, 0.04, retencion2024); math.Abs(real-450) > 1e-9 {
+		t.Errorf("sin tipo de cambio se esperaba 450, salió %.2f", real)
+	}
+
+	// US$1,000 al 4% con el dólar de $18 a $18.90: 9.2% en pesos sobre $18,000, menos la
+	// retención y la inflación
+	dolares := TarjetaDebito{TasaRendimiento: 0.04, Moneda: "USD"}
+	real, porcentaje, final := RendimientoRealDivisa(dolares, 1000, 18, 0.05, 0.04, retencion2024)
+	if math.Abs(real-846) > 1e-9 || math.Abs(porcentaje-4.7) > 1e-9 || math.Abs(final-18846) > 1e-9 {
+		t.Errorf("RendimientoRealDivisa = %.2f, %.2f%%, %.2f; se esperaba 846, 4.7%%, 18846", real, porcentaje, final)
+	}
+
+	// Si el peso se aprecia la cuenta pierde aunque pague intereses
+	if real, _, _ := RendimientoRealDivisa(dolares, 1000, 18, -0.10, 0.04, retencion2024); real >= 0 {
+		t.Errorf("con el dólar 10%% más barato se esperaba pérdida, salió %.2f", real)
+	}
+}
+
+func TestSaldoEquilibrio(t *testing.T) {
+	tarjeta := TarjetaDebito{TasaRendimiento: 0.10, ComisionAnual: 200}
+	saldo, ok := SaldoEquilibrio(tarjeta, 0.04, retencion2024)
+	if !ok {
+		t.Fatal("una tasa neta mayor a la inflación debe tener saldo de equilibrio")
+	}
+	if real, _, _ := RendimientoReal(tarjeta, saldo, 0.04, retencion2024); math.Abs(real) > 1e-9 {
+		t.Errorf("en el saldo de equilibrio %.2f el rendimiento real es %.4f", saldo, real)
+	}
+
+	conMinimo := TarjetaDebito{TasaRendimiento: 0.10, ComisionAnual: 200, SaldoMinimo: 50000}
+	if saldo, _ := SaldoEquilibrio(conMinimo, 0.04, retencion2024); saldo != 50000 {
+		t.Errorf("el equilibrio no puede ser menor al saldo mínimo: %.2f", saldo)
+	}
+
+	if _, ok := SaldoEquilibrio(TarjetaDebito{TasaRendimiento: 0.045}, 0.04, retencion2024); ok {
+		t.Error("una tasa neta menor a la inflación no tiene saldo de equilibrio")
+	}
+}
+
+func TestProyectarRendimiento(t *testing.T) {
+	tarjeta := TarjetaDebito{TasaRendimiento: 0.12, ComisionAnual: 100}
+	p := ProyectarRendimiento(tarjeta, 10000, 1000, 3, 0.04, retencion2024)
+	if len(p) != 3 {
+		t.Fatalf("se esperaban 3 años, hay %d", len(p))
+	}
+	// Antes de ISR y comisión, el primer año coincide con el valor futuro de las aportaciones
+	if antes := p[0].SaldoFinal + p[0].Impuestos + p[0].Comisiones; math.Abs(antes-ValorFuturo(10000, 1000, 0.12, 12)) > 1e-6 {
+		t.Errorf("saldo antes de ISR = %.2f, se esperaba %.2f", antes, ValorFuturo(10000, 1000, 0.12, 12))
+	}
+	if p[1].SaldoInicial != p[0].SaldoFinal || p[2].Aportaciones != 12000 {
+		t.Errorf("los años no se encadenan: %+v", p)
+	}
+	if math.Abs(p[2].SaldoReal-p[2].SaldoFinal/math.Pow(1.04, 3)) > 1e-9 {
+		t.Errorf("saldo real = %.2f", p[2].SaldoReal)
+	}
+	if p[2].GananciaReal <= 0 {
+		t.Errorf("al 12%% sobre 4%% de inflación la ganancia real debe ser positiva: %.2f", p[2].GananciaReal)
+	}
+}
+
+func TestProyectarRendimientoDebajoDelMinimo(t *testing.T) {
+	tarjeta := TarjetaDebito{TasaRendimiento: 0.10, SaldoMinimo: 50000}
+	p := ProyectarRendimiento(tarjeta, 1000, 0, 2, 0.04, retencion2024)
+	if p[1].Intereses != 0 || p[1].Impuestos != 0 || p[1].SaldoFinal != 1000 {
+		t.Errorf("debajo del mínimo no debe generar intereses: %+v", p[1])
+	}
+	if p[1].GananciaReal >= 0 {
+		t.Errorf("sin intereses la inflación debe causar pérdida real: %.2f", p[1].GananciaReal)
+	}
+}
+
+func TestTasaPonderada(t *testing.T) {
+	// Desordenados a propósito: el tramo sin tope siempre va al final
+	tarjeta := TarjetaDebito{Tramos: []TramoRendimiento{{Tasa: 0.08}, {Hasta: 25000, Tasa: 0.15}}}
+	casos := []struct {
+		saldo, tasa float64
+	}{
+		{0, 0.15},
+		{10000, 0.15},
+		{25000, 0.15},
+		{50000, 0.115}, // 3,750 del primer tramo y 2,000 del excedente
+	}
+	for _, c := range casos {
+		if tasa := tarjeta.TasaPonderada(c.saldo); math.Abs(tasa-c.tasa) > 1e-12 {
+			t.Errorf("TasaPonderada(%.0f) = %.4f, se esperaba %.4f", c.saldo, tasa, c.tasa)
+		}
+	}
+
+	// Lo que pasa del último tope no gana nada
+	conTope := TarjetaDebito{Tramos: []TramoRendimiento{{Hasta: 25000, Tasa: 0.15}}}
+	if tasa := conTope.TasaPonderada(50000); math.Abs(tasa-0.075) > 1e-12 {
+		t.Errorf("con tope = %.4f, se esperaba 0.075", tasa)
+	}
+
+	real, _, _ := RendimientoReal(tarjeta, 50000, 0.04, retencion2024)
+	if math.Abs(real-(5750-250-2000)) > 1e-9 {
+		t.Errorf("RendimientoReal con tramos = %.2f, se esperaba 3500", real)
+	}
+}
+
+func TestMigrarTramos(t *testing.T) {
+	tarjeta := TarjetaDebito{TasaRendimiento: 0.10}
+	if tasa := tarjeta.TasaPonderada(5000); tasa != 0.10 {
+		t.Errorf("sin migrar la tasa anterior debe valer para todo el saldo: %.4f", tasa)
+	}
+	tarjeta.MigrarTramos()
+	if tarjeta.TasaRendimiento != 0 || len(tarjeta.Tramos) != 1 || tarjeta.Tramos[0] != (TramoRendimiento{Tasa: 0.10}) {
+		t.Errorf("migración = %+v", tarjeta)
+	}
+}
+
+func TestSaldoEquilibrioTramos(t *testing.T) {
+	// El equilibrio cae en el primer tramo aunque el excedente pague menos que la inflación
+	tarjeta := TarjetaDebito{Tramos: []TramoRendimiento{{Hasta: 1000, Tasa: 0.15}, {Tasa: 0.02}}, ComisionAnual: 100}
+	saldo, ok := SaldoEquilibrio(tarjeta, 0.04, retencion2024)
+	if !ok || math.Abs(saldo-100/0.105) > 1e-6 {
+		t.Errorf("SaldoEquilibrio = %.2f, %v; se esperaba %.2f", saldo, ok, 100/0.105)
+	}
+
+	// Con un primer tramo más chico la comisión ya no se alcanza a cubrir
+	tarjeta.Tramos[0].Hasta = 500
+	if saldo, ok := SaldoEquilibrio(tarjeta, 0.04, retencion2024); ok {
+		t.Errorf("no debería haber equilibrio, salió %.2f", saldo)
+	}
+
+	// Un excedente que sí gana lleva el equilibrio al segundo tramo
+	tarjeta.Tramos[1].Tasa = 0.09
+	saldo, ok = SaldoEquilibrio(tarjeta, 0.04, retencion2024)
+	if !ok || saldo <= 500 {
+		t.Fatalf("SaldoEquilibrio = %.2f, %v", saldo, ok)
+	}
+	if real, _, _ := RendimientoReal(tarjeta, saldo, 0.04, retencion2024); math.Abs(real) > 1e-9 {
+		t.Errorf("en el saldo de equilibrio %.2f el rendimiento real es %.6f", saldo, real)
+	}
+}
@@ -0,0 +1,248 @@
+package calc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Las simulaciones de este archivo son funciones puras: todo lo que usan llega en la entrada
+// (tasas, inflación, régimen de ISR) y no leen archivos, la red ni la fecha del sistema, así
+// que la misma entrada siempre da la misma salida. Los tests golden de testdata/golden fijan
+// sus resultados para escenarios conocidos.
+
+// EntradaCredito es una deuda de tarjeta que se liquida con un pago constante
+type EntradaCredito struct {
+	Deuda         float64         `json:"deuda"`
+	TasaAnual     float64         `json:"tasa_anual"`
+	ComisionAnual float64         `json:"comision_anual,omitempty"`
+	LimiteCredito float64         `json:"limite_credito,omitempty"` // Para el pago mínimo regulatorio
+	Pago          float64         `json:"pago"`                     // Por periodo; se ajusta al pago mínimo
+	Frecuencia    Frecuencia      `json:"frecuencia,omitempty"`     // Mensual si se omite
+	Eventos       []EventoCredito `json:"eventos,omitempty"`
+}
+
+// ResultadoCredito es lo que cuesta liquidar la deuda
+type ResultadoCredito struct {
+	PagoAplicado    float64 `json:"pago_aplicado"` // El pago después de ajustarlo al mínimo
+	Pagos           int     `json:"pagos"`
+	Meses           float64 `json:"meses"`
+	Intereses       float64 `json:"intereses"`  // Sin IVA
+	Comisiones      float64 `json:"comisiones"` // Anualidad prorrateada por el plazo, sin IVA
+	IVA             float64 `json:"iva"`        // De los intereses y las comisiones
+	CostoTotal      float64 `json:"costo_total"`
+	CostoPorcentaje float64 `json:"costo_porcentaje"` // Del monto original
+	TotalPagado     float64 `json:"total_pagado"`
+}
+
+// SimularCredito calcula lo que cuesta liquidar una deuda como CostoCreditoDesglosado
+func SimularCredito(e EntradaCredito) (ResultadoCredito, error) {
+	var r ResultadoCredito
+	if e.Deuda <= 0 {
+		return r, fmt.Errorf("deuda debe ser mayor a cero")
+	}
+	frecuencia, err := ParsearFrecuencia(string(e.Frecuencia))
+	if err != nil {
+		return r, err
+	}
+	periodosAño := float64(frecuencia.PeriodosPorAño())
+
+	tarjeta := TarjetaCredito{TasaInteres: e.TasaAnual, ComisionAnual: e.ComisionAnual, LimiteCredito: e.LimiteCredito}
+	d := CostoCreditoDesglosado(tarjeta, e.Deuda, e.Pago, frecuencia, e.Eventos)
+	return ResultadoCredito{
+		PagoAplicado:    pagoAjustadoMinimo(tarjeta, e.Deuda, e.Pago, frecuencia),
+		Pagos:           d.Pagos,
+		Meses:           float64(d.Pagos) * 12 / periodosAño,
+		Intereses:       d.Intereses,
+		Comisiones:      d.Comisiones,
+		IVA:             d.IVA(),
+		CostoTotal:      d.Total,
+		CostoPorcentaje: d.Porcentaje,
+		TotalPagado:     e.Deuda + d.Total,
+	}, nil
+}
+
+// EntradaRendimiento es un saldo en una cuenta de débito durante un año
+type EntradaRendimiento struct {
+	Saldo         float64            `json:"saldo"`
+	Tramos        []TramoRendimiento `json:"tramos"`
+	ComisionAnual float64            `json:"comision_anual,omitempty"`
+	SaldoMinimo   float64            `json:"saldo_minimo,omitempty"`
+	Inflacion     float64            `json:"inflacion"`
+	RegimenISR    string             `json:"regimen_isr,omitempty"` // retencion si se omite
+	AñoFiscal     int                `json:"anio_fiscal,omitempty"` // Año de la retención de la LIF
+	RetencionISR  float64            `json:"retencion_isr,omitempty"`
+	TasaMarginal  float64            `json:"tasa_marginal,omitempty"` // Para el régimen de interés real
+}
+
+// ResultadoRendimiento es lo que gana el saldo en el año después de ISR e inflación
+type ResultadoRendimiento struct {
+	Tasa             float64 `json:"tasa"` // Ponderada por los tramos que alcanza el saldo
+	Bruto            float64 `json:"bruto"`
+	Impuestos        float64 `json:"impuestos"`
+	PerdidaInflacion float64 `json:"perdida_inflacion"`
+	Real             float64 `json:"real"`
+	RealPorcentaje   float64 `json:"real_porcentaje"`
+	SaldoFinal       float64 `json:"saldo_final"`
+}
+
+// SimularRendimiento calcula el rendimiento real del saldo como RendimientoReal
+func SimularRendimiento(e EntradaRendimiento) (ResultadoRendimiento, error) {
+	var r ResultadoRendimiento
+	isr := ISRIntereses{Regimen: e.RegimenISR, AñoFiscal: e.AñoFiscal, TasaMarginal: e.TasaMarginal, Retencion: e.RetencionISR}
+	if isr.Regimen == "" {
+		isr.Regimen = RegimenRetencion
+	}
+	if err := ValidarRegimenISR(isr.Regimen); err != nil {
+		return r, err
+	}
+	if isr.Regimen == RegimenRetencion && isr.Retencion == 0 && isr.AñoFiscal == 0 {
+		return r, fmt.Errorf("indica anio_fiscal o retencion_isr para calcular la retención")
+	}
+
+	cuenta := TarjetaDebito{Tramos: e.Tramos, ComisionAnual: e.ComisionAnual, SaldoMinimo: e.SaldoMinimo}
+	r.Real, r.RealPorcentaje, r.SaldoFinal = RendimientoReal(cuenta, e.Saldo, e.Inflacion, isr)
+	if e.Saldo >= e.SaldoMinimo {
+		r.Tasa = cuenta.TasaPonderada(e.Saldo)
+		r.Bruto = e.Saldo * r.Tasa
+		r.Impuestos = e.Saldo * isr.TasaImpuesto(r.Tasa, e.Inflacion)
+		r.PerdidaInflacion = e.Saldo * e.Inflacion
+	}
+	return r, nil
+}
+
+// EntradaCompraMSI es una compra que se puede pagar de contado o a meses
+type EntradaCompraMSI struct {
+	Precio           float64 `json:"precio"`
+	DescuentoContado float64 `json:"descuento_contado,omitempty"`
+	TasaOportunidad  float64 `json:"tasa_oportunidad"`       // Tasa neta anual que rinde el dinero
+	TasaCredito      float64 `json:"tasa_credito,omitempty"` // Para diferir con intereses; cero no lo simula
+	Plazos           []int   `json:"plazos"`
+}
+
+// ResultadoCompraMSI son las formas de pagar la compra y la más barata
+type ResultadoCompraMSI struct {
+	Opciones []OpcionCompra `json:"opciones"`
+	Mejor    int            `json:"mejor"` // Índice en Opciones
+}
+
+// SimularMSI compara las formas de pagar la compra como SimularCompraMSI
+func SimularMSI(e EntradaCompraMSI) (ResultadoCompraMSI, error) {
+	if e.Precio <= 0 {
+		return ResultadoCompraMSI{}, fmt.Errorf("precio debe ser mayor a cero")
+	}
+	opciones := SimularCompraMSI(e.Precio, e.DescuentoContado, e.TasaOportunidad, e.TasaCredito, e.Plazos)
+	return ResultadoCompraMSI{Opciones: opciones, Mejor: MejorOpcionCompra(opciones)}, nil
+}
+
+// EntradaTransferencia es una deuda que se puede traspasar a otra tarjeta con promoción
+type EntradaTransferencia struct {
+	Deuda       float64 `json:"deuda"`
+	TasaOrigen  float64 `json:"tasa_origen"`
+	TasaDestino float64 `json:"tasa_destino"` // Después de la promoción
+	PagoMensual float64 `json:"pago_mensual"`
+	TransferenciaSaldo
+}
+
+// ResultadoTransferencia compara quedarse con la deuda contra traspasarla
+type ResultadoTransferencia struct {
+	Actual        CostoTraspaso `json:"actual"`
+	Transferencia CostoTraspaso `json:"transferencia"`
+	Ahorro        float64       `json:"ahorro"` // Negativo si traspasar cuesta más
+	PagoPromocion float64       `json:"pago_promocion"`
+}
+
+// SimularTransferencia compara CostoSinTransferir contra CostoConTransferencia
+func SimularTransferencia(e EntradaTransferencia) (ResultadoTransferencia, error) {
+	if e.Deuda <= 0 {
+		return ResultadoTransferencia{}, fmt.Errorf("deuda debe ser mayor a cero")
+	}
+	r := ResultadoTransferencia{
+		Actual:        CostoSinTransferir(TarjetaCredito{TasaInteres: e.TasaOrigen}, e.Deuda, e.PagoMensual),
+		Transferencia: CostoConTransferencia(TarjetaCredito{TasaInteres: e.TasaDestino}, e.Deuda, e.PagoMensual, e.TransferenciaSaldo),
+		PagoPromocion: PagoDentroPromocion(e.Deuda, e.TransferenciaSaldo),
+	}
+	r.Ahorro = r.Actual.Total - r.Transferencia.Total
+	return r, nil
+}
+
+// EntradaAhorro es un saldo inicial con aportaciones mensuales a una tasa anual
+type EntradaAhorro struct {
+	Inicial   float64 `json:"inicial"`
+	Mensual   float64 `json:"mensual"`
+	TasaAnual float64 `json:"tasa_anual"`
+	Meses     int     `json:"meses"`
+}
+
+// ResultadoAhorro es el saldo al final del plazo
+type ResultadoAhorro struct {
+	ValorFuturo float64 `json:"valor_futuro"`
+	Aportado    float64 `json:"aportado"`
+	Intereses   float64 `json:"intereses"`
+}
+
+// SimularAhorro proyecta el saldo como ValorFuturo
+func SimularAhorro(e EntradaAhorro) (ResultadoAhorro, error) {
+	if e.Meses <= 0 {
+		return ResultadoAhorro{}, fmt.Errorf("meses debe ser mayor a cero")
+	}
+	r := ResultadoAhorro{
+		ValorFuturo: ValorFuturo(e.Inicial, e.Mensual, e.TasaAnual, e.Meses),
+		Aportado:    e.Inicial + e.Mensual*float64(e.Meses),
+	}
+	r.Intereses = r.ValorFuturo - r.Aportado
+	return r, nil
+}
+
+// EntradaPagoMinimo es una deuda de tarjeta que se paga cada mes con el mínimo regulatorio
+type EntradaPagoMinimo struct {
+	Deuda         float64 `json:"deuda"`
+	TasaAnual     float64 `json:"tasa_anual"`
+	LimiteCredito float64 `json:"limite_credito"`
+}
+
+// SimularPagoMinimo proyecta la deuda pagando solo el mínimo como ProyectarPagoMinimo
+func SimularPagoMinimo(e EntradaPagoMinimo) (ProyeccionPagoMinimo, error) {
+	if e.Deuda <= 0 {
+		return ProyeccionPagoMinimo{}, fmt.Errorf("deuda debe ser mayor a cero")
+	}
+	return ProyectarPagoMinimo(TarjetaCredito{TasaInteres: e.TasaAnual, LimiteCredito: e.LimiteCredito}, e.Deuda), nil
+}
+
+// Simulacion corre un simulador con su entrada en JSON. Un campo desconocido es error, para
+// que un nombre mal escrito no se simule como cero.
+type Simulacion func(entrada []byte) (interface{}, error)
+
+// simulacionJSON adapta un simulador tipado a Simulacion
+func simulacionJSON[E, R any](simular func(E) (R, error)) Simulacion {
+	return func(entrada []byte) (interface{}, error) {
+		var e E
+		decoder := json.NewDecoder(bytes.NewReader(entrada))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&e); err != nil {
+			return nil, fmt.Errorf("entrada inválida: %v", err)
+		}
+		return simular(e)
+	}
+}
+
+// Simulaciones son los simuladores deterministas por nombre
+var Simulaciones = map[string]Simulacion{
+	"credito":       simulacionJSON(SimularCredito),
+	"rendimiento":   simulacionJSON(SimularRendimiento),
+	"msi":           simulacionJSON(SimularMSI),
+	"transferencia": simulacionJSON(SimularTransferencia),
+	"ahorro":        simulacionJSON(SimularAhorro),
+	"pago_minimo":   simulacionJSON(SimularPagoMinimo),
+}
+
+// NombresSimulaciones regresa los nombres de los simuladores en orden alfabético
+func NombresSimulaciones() []string {
+	nombres := make([]string, 0, len(Simulaciones))
+	for nombre := range Simulaciones {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	return nombres
+}
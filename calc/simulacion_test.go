@@ -0,0 +1,136 @@
+package calc
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Con go test ./calc -run Golden -actualizar se reescriben las salidas de los escenarios. Solo
+// debe usarse cuando un cambio en los cálculos es intencional, revisando la diferencia.
+var actualizarGolden = flag.Bool("actualizar", false, "reescribir la salida de los escenarios golden")
+
+// escenarioGolden es un archivo de testdata/golden: una simulación con su entrada y la salida
+// esperada
+type escenarioGolden struct {
+	Simulacion  string          `json:"simulacion"`
+	Descripcion string          `json:"descripcion"`
+	Entrada     json.RawMessage `json:"entrada"`
+	Salida      json.RawMessage `json:"salida"`
+}
+
+func TestSimulacionesGolden(t *testing.T) {
+	archivos, err := filepath.Glob(filepath.Join("testdata", "golden", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archivos) == 0 {
+		t.Fatal("no hay escenarios en testdata/golden")
+	}
+	for _, archivo := range archivos {
+		t.Run(filepath.Base(archivo), func(t *testing.T) {
+			data, err := os.ReadFile(archivo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var e escenarioGolden
+			if err := json.Unmarshal(data, &e); err != nil {
+				t.Fatalf("escenario inválido: %v", err)
+			}
+			simular, ok := Simulaciones[e.Simulacion]
+			if !ok {
+				t.Fatalf("no hay simulación '%s'", e.Simulacion)
+			}
+			resultado, err := simular(e.Entrada)
+			if err != nil {
+				t.Fatalf("%s: %v", e.Descripcion, err)
+			}
+			obtenido, err := json.Marshal(resultado)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *actualizarGolden {
+				e.Salida = obtenido
+				if err := escribirGolden(archivo, e); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var esperado, actual interface{}
+			if err := json.Unmarshal(e.Salida, &esperado); err != nil {
+				t.Fatalf("salida esperada inválida: %v", err)
+			}
+			json.Unmarshal(obtenido, &actual)
+			for _, diferencia := range compararGolden("", esperado, actual) {
+				t.Errorf("%s: %s", e.Descripcion, diferencia)
+			}
+		})
+	}
+}
+
+// escribirGolden guarda el escenario con sangría para que las diferencias se lean en git
+func escribirGolden(archivo string, e escenarioGolden) error {
+	var salida bytes.Buffer
+	if err := json.Indent(&salida, e.Salida, "    ", "  "); err != nil {
+		return err
+	}
+	e.Salida = salida.Bytes()
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(archivo, append(data, '\n'), 0644)
+}
+
+// compararGolden regresa las diferencias entre dos valores JSON; los números coinciden si
+// difieren en menos de una millonésima
+func compararGolden(ruta string, esperado, actual interface{}) []string {
+	switch e := esperado.(type) {
+	case float64:
+		if a, ok := actual.(float64); ok && math.Abs(a-e) < 1e-6 {
+			return nil
+		}
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var diferencias []string
+		for clave, valor := range e {
+			diferencias = append(diferencias, compararGolden(ruta+"."+clave, valor, a[clave])...)
+		}
+		for clave := range a {
+			if _, ok := e[clave]; !ok {
+				diferencias = append(diferencias, fmt.Sprintf("%s.%s: campo nuevo en la salida", ruta, clave))
+			}
+		}
+		return diferencias
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			break
+		}
+		var diferencias []string
+		for i := range e {
+			diferencias = append(diferencias, compararGolden(fmt.Sprintf("%s[%d]", ruta, i), e[i], a[i])...)
+		}
+		return diferencias
+	default:
+		if esperado == actual {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("%s: se esperaba %v y se obtuvo %v", ruta, esperado, actual)}
+}
+
+func TestSimulacionRechazaCamposDesconocidos(t *testing.T) {
+	if _, err := Simulaciones["credito"]([]byte(`{"deuda": 10000, "tasa": 0.36, "pago": 500}`)); err == nil {
+		t.Error("un campo mal escrito debe ser error, no una tasa en cero")
+	}
+}
@@ -0,0 +1,63 @@
+// Package calc contiene los cálculos financieros de finmex sin depender de la línea de
+// comandos ni del almacenamiento: rendimiento real de cuentas de débito, costo de créditos
+// con distintas frecuencias de pago, tablas de amortización, meses sin intereses, cashback
+// por categoría de gasto y conversiones de tasas. Las tasas se expresan en decimal (0.36
+// para 36%).
+package calc
+
+// Constantes financieras para México
+const (
+	INFLACION_ANUAL = 0.042 // Inflación anual estimada (4.2%)
+	PAGO_MINIMO     = 0.05  // Pago mínimo aproximado (5%) que se expone a los scripts; los cálculos usan PagoMinimoRegulatorio
+)
+
+// TarjetaDebito representa la información de una tarjeta de débito
+type TarjetaDebito struct {
+	Nombre              string             `json:"nombre"`
+	Banco               string             `json:"banco"`
+	Tramos              []TramoRendimiento `json:"tramos,omitempty"` // Tasas anuales por monto del saldo
+	SaldoMinimo         float64            `json:"saldo_minimo"`
+	ComisionAnual       float64            `json:"comision_anual"`
+	ComisionInactividad float64            `json:"comision_inactividad"`
+	Saldo               float64            `json:"saldo,omitempty"`  // Saldo actual en la cuenta
+	Tags                []string           `json:"tags,omitempty"`   // Etiquetas para filtrar comparaciones
+	Moneda              string             `json:"moneda,omitempty"` // MXN o USD, la de saldos, tramos y comisiones; vacía es MXN
+	// Deprecated: antes de los tramos la cuenta tenía una sola tasa anual. MigrarTramos la
+	// convierte en un tramo sin tope al cargar los archivos anteriores.
+	TasaRendimiento float64 `json:"tasa_rendimiento,omitempty"`
+}
+
+// TramoRendimiento es la tasa que paga una cuenta de débito por la parte del saldo que llega
+// hasta un monto. Las tasas escalonadas (15% hasta $25,000 y 8% por el excedente) son varios
+// tramos; una cuenta con una sola tasa tiene un tramo sin tope.
+type TramoRendimiento struct {
+	Hasta float64 `json:"monto_hasta"` // Monto hasta el que aplica la tasa; 0 es sin tope
+	Tasa  float64 `json:"tasa"`        // Tasa anual
+}
+
+// TarjetaCredito representa la información de una tarjeta de crédito
+type TarjetaCredito struct {
+	Nombre             string    `json:"nombre"`
+	Banco              string    `json:"banco"`
+	TasaInteres        float64   `json:"tasa_interes"` // Tasa anual
+	CAT                float64   `json:"cat"`          // Costo Anual Total
+	ComisionAnual      float64   `json:"comision_anual"`
+	LimiteCredito      float64   `json:"limite_credito"`
+	BeneficiosCashback float64   `json:"beneficios_cashback"`       // Porcentaje de cashback
+	MesesSinIntereses  bool      `json:"meses_sin_intereses"`       // Ofrece MSI
+	Saldo              float64   `json:"saldo,omitempty"`           // Deuda revolvente actual, sin contar MSI
+	Tags               []string  `json:"tags,omitempty"`            // Etiquetas para filtrar comparaciones
+	Planes             []PlanMSI `json:"planes_msi,omitempty"`      // Compras a MSI vigentes
+	FechaAnualidad     string    `json:"fecha_anualidad,omitempty"` // Próximo cobro de anualidad, AAAA-MM-DD
+	DiaCorte           int       `json:"dia_corte,omitempty"`       // Día del mes en que cierra el periodo
+	DiaLimitePago      int       `json:"dia_limite_pago,omitempty"` // Día del mes para pagar sin intereses
+	// FacturacionParaCondonar es el gasto anual con el que el banco bonifica la anualidad;
+	// cero si no la bonifica
+	FacturacionParaCondonar float64 `json:"facturacion_para_condonar,omitempty"`
+	// Programa de bonificación: BeneficiosCashback aplica a todo el gasto salvo las categorías
+	// con tasa propia
+	Categorias    map[string]float64 `json:"categorias,omitempty"`     // Cashback por categoría de gasto
+	TopeCashback  float64            `json:"tope_cashback,omitempty"`  // Bonificación máxima al mes; cero sin tope
+	FormaCashback string             `json:"forma_cashback,omitempty"` // abono (por omisión) o puntos
+	ValorPunto    float64            `json:"valor_punto,omitempty"`    // Pesos que vale cada peso en puntos al canjearlo
+}
@@ -0,0 +1,113 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// IVA es el impuesto que causan los intereses y comisiones de los créditos
+const IVA = 0.16
+
+// TasaConIVA es la tasa que se paga de verdad en un crédito cuyos intereses causan IVA: el
+// banco cobra el IVA de los intereses de cada periodo junto con ellos
+func TasaConIVA(tasa float64) float64 {
+	return tasa * (1 + IVA)
+}
+
+// SepararIVA divide un monto que ya incluye IVA en la parte sin IVA y el impuesto
+func SepararIVA(conIVA float64) (float64, float64) {
+	sinIVA := conIVA / (1 + IVA)
+	return sinIVA, conIVA - sinIVA
+}
+
+// periodosCapitalizacion relaciona cada capitalización con sus periodos por año
+var periodosCapitalizacion = map[string]int{
+	"diaria":     365,
+	"semanal":    52,
+	"catorcenal": 26,
+	"quincenal":  24,
+	"mensual":    12,
+	"bimestral":  6,
+	"trimestral": 4,
+	"semestral":  2,
+	"anual":      1,
+}
+
+// ParsearCapitalizacion convierte el nombre de una capitalización en periodos por año
+func ParsearCapitalizacion(texto string) (int, error) {
+	if periodos, ok := periodosCapitalizacion[strings.ToLower(strings.TrimSpace(texto))]; ok {
+		return periodos, nil
+	}
+	return 0, fmt.Errorf("Capitalización inválida '%s' (usa diaria, semanal, catorcenal, quincenal, mensual, bimestral, trimestral, semestral o anual)", texto)
+}
+
+// EfectivaDesdeNominal convierte una tasa nominal anual con la capitalización dada en tasa
+// efectiva anual
+func EfectivaDesdeNominal(nominal float64, periodos int) float64 {
+	return TasaAnualEfectiva(nominal/float64(periodos), float64(periodos))
+}
+
+// NominalDesdeEfectiva convierte una tasa efectiva anual en la tasa nominal anual que la
+// produce con la capitalización dada
+func NominalDesdeEfectiva(efectiva float64, periodos int) float64 {
+	return float64(periodos) * TasaEquivalente(efectiva, float64(periodos))
+}
+
+// TasaEquivalente regresa la tasa por periodo equivalente a una tasa efectiva anual cuando
+// hay periodosAño periodos en el año (12 para mensual, 365 para diaria)
+func TasaEquivalente(efectiva, periodosAño float64) float64 {
+	return math.Pow(1+efectiva, 1/periodosAño) - 1
+}
+
+// ConversionTasa muestra la misma tasa expresada de distintas formas
+type ConversionTasa struct {
+	Nominal       float64 // Tasa nominal anual
+	Periodos      int     // Periodos de capitalización por año de la tasa nominal
+	EfectivaAnual float64
+	Mensual       float64 // Tasa efectiva mensual equivalente
+	Diaria        float64 // Tasa efectiva diaria equivalente
+}
+
+// ConvertirTasa expresa una tasa nominal con su capitalización como tasa efectiva anual,
+// mensual y diaria
+func ConvertirTasa(nominal float64, periodos int) ConversionTasa {
+	efectiva := EfectivaDesdeNominal(nominal, periodos)
+	return ConversionTasa{
+		Nominal:       nominal,
+		Periodos:      periodos,
+		EfectivaAnual: efectiva,
+		Mensual:       TasaEquivalente(efectiva, 12),
+		Diaria:        TasaEquivalente(efectiva, 365),
+	}
+}
+
+// EquivalenciaCAT relaciona un CAT con la tasa que efectivamente cobra la deuda cada mes. El
+// CAT se publica sin IVA, pero los intereses causan IVA y se cobran encima.
+type EquivalenciaCAT struct {
+	CAT            float64 // Costo Anual Total, efectivo anual sin IVA
+	MensualSinIVA  float64
+	NominalSinIVA  float64 // Tasa anual que el banco anuncia (mensual por 12)
+	MensualConIVA  float64
+	NominalConIVA  float64
+	EfectivaConIVA float64 // Costo efectivo anual real incluyendo el IVA de los intereses
+}
+
+// EquivalenciaDesdeCAT calcula las tasas mensuales y anuales implícitas en un CAT suponiendo
+// que todo el costo son intereses capitalizados cada mes
+func EquivalenciaDesdeCAT(cat float64) EquivalenciaCAT {
+	return EquivalenciaDesdeMensual(TasaEquivalente(cat, 12))
+}
+
+// EquivalenciaDesdeMensual arma la equivalencia a partir de la tasa mensual sin IVA
+func EquivalenciaDesdeMensual(mensual float64) EquivalenciaCAT {
+	conIVA := mensual * (1 + IVA)
+	return EquivalenciaCAT{
+		CAT:            TasaAnualEfectiva(mensual, 12),
+		MensualSinIVA:  mensual,
+		NominalSinIVA:  mensual * 12,
+		MensualConIVA:  conIVA,
+		NominalConIVA:  conIVA * 12,
+		EfectivaConIVA: TasaAnualEfectiva(conIVA, 12),
+	}
+}
@@ -0,0 +1,64 @@
+package calc
+
+import "math"
+
+// TransferenciaSaldo es una promoción para traspasar la deuda de una tarjeta a otra: una
+// comisión sobre el monto y una tasa promocional durante unos meses. Al terminar la promoción
+// el saldo que quede paga la tasa normal de la tarjeta destino.
+type TransferenciaSaldo struct {
+	Comision        float64 `json:"comision"`         // Fracción del monto traspasado, sin IVA
+	TasaPromocional float64 `json:"tasa_promocional"` // Tasa anual durante la promoción; 0 es sin intereses
+	MesesPromocion  int     `json:"meses_promocion"`
+}
+
+// CostoTraspaso es lo que cuesta liquidar una deuda con un pago mensual fijo
+type CostoTraspaso struct {
+	Intereses float64 `json:"intereses"` // Con IVA
+	Comision  float64 `json:"comision"`  // Comisión del traspaso con IVA
+	Pagos     int     `json:"pagos"`
+	Total     float64 `json:"total"`
+	// SaldoFinPromocion es lo que queda por pagar cuando termina la tasa promocional
+	SaldoFinPromocion float64 `json:"saldo_fin_promocion"`
+}
+
+// CostoSinTransferir calcula los intereses de seguir pagando la deuda en la tarjeta actual
+// con el pago mensual dado, ajustado al pago mínimo
+func CostoSinTransferir(tarjeta TarjetaCredito, deuda, pagoMensual float64) CostoTraspaso {
+	pago := math.Max(pagoMensual, PagoMinimoPeriodo(tarjeta, deuda, FrecuenciaMensual))
+	intereses, pagos := liquidacionPagoFijo(deuda, TasaConIVA(tarjeta.TasaInteres)/12, pago, 1000)
+	return CostoTraspaso{Intereses: intereses, Pagos: pagos, Total: intereses}
+}
+
+// CostoConTransferencia calcula lo que cuesta traspasar la deuda a la tarjeta destino y
+// liquidarla ahí con el mismo pago mensual. La comisión y su IVA se cargan al saldo
+// traspasado, así que también generan intereses.
+func CostoConTransferencia(destino TarjetaCredito, deuda, pagoMensual float64, promocion TransferenciaSaldo) CostoTraspaso {
+	comision := deuda * promocion.Comision * (1 + IVA)
+	saldo := deuda + comision
+	// El mínimo del primer mes se calcula con la tasa promocional
+	pago := math.Max(pagoMensual, PagoMinimoRegulatorio(saldo, saldo*promocion.TasaPromocional/12, destino.LimiteCredito))
+
+	var eventos []EventoCredito
+	if promocion.MesesPromocion > 0 {
+		eventos = append(eventos, EventoCredito{Periodo: promocion.MesesPromocion + 1, CambiaTasa: true, NuevaTasa: TasaConIVA(destino.TasaInteres)})
+	}
+	intereses, pagos := simularCredito(saldo, TasaConIVA(promocion.TasaPromocional), 12, pago, 1000, eventos)
+	restante := math.Max(0, SaldoDespuesDePagos(saldo, TasaConIVA(promocion.TasaPromocional)/12, pago, promocion.MesesPromocion))
+	return CostoTraspaso{
+		Intereses:         intereses,
+		Comision:          comision,
+		Pagos:             pagos,
+		Total:             intereses + comision,
+		SaldoFinPromocion: restante,
+	}
+}
+
+// PagoDentroPromocion es el pago mensual que liquida la deuda traspasada, con su comisión,
+// antes de que termine la tasa promocional
+func PagoDentroPromocion(deuda float64, promocion TransferenciaSaldo) float64 {
+	if promocion.MesesPromocion <= 0 {
+		return 0
+	}
+	saldo := deuda * (1 + promocion.Comision*(1+IVA))
+	return PagoFijo(saldo, TasaConIVA(promocion.TasaPromocional)/12, promocion.MesesPromocion)
+}
@@ -0,0 +1,33 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCostoConTransferencia(t *testing.T) {
+	destino := TarjetaCredito{TasaInteres: 0.36}
+	promocion := TransferenciaSaldo{Comision: 0.03, TasaPromocional: 0, MesesPromocion: 6}
+
+	// $12,000 con 3% de comisión más IVA: $417.60 que se suman al saldo traspasado
+	pago := PagoDentroPromocion(12000, promocion)
+	if math.Abs(pago-2069.6) > 1e-9 {
+		t.Fatalf("pago para liquidar en la promoción %.4f, se esperaba 2069.60", pago)
+	}
+	costo := CostoConTransferencia(destino, 12000, pago, promocion)
+	if math.Abs(costo.Comision-417.6) > 1e-9 || costo.Intereses > 1e-6 || costo.Pagos != 6 || costo.SaldoFinPromocion > 1e-6 {
+		t.Errorf("liquidando en la promoción: %+v", costo)
+	}
+
+	// Con un pago menor queda saldo al terminar la promoción y paga la tasa normal
+	costo = CostoConTransferencia(destino, 12000, 1000, promocion)
+	if math.Abs(costo.SaldoFinPromocion-6417.6) > 1e-9 || costo.Intereses <= 0 {
+		t.Errorf("con $1,000 al mes: %+v", costo)
+	}
+
+	// Quedarse en una tarjeta al 40% cuesta más que la comisión
+	actual := CostoSinTransferir(TarjetaCredito{TasaInteres: 0.40}, 12000, pago)
+	if actual.Intereses <= costo.Comision || actual.Comision != 0 {
+		t.Errorf("sin transferir: %+v", actual)
+	}
+}
@@ -0,0 +1,43 @@
+package calc
+
+import "math"
+
+// Las UDIS (Unidades de Inversión) son una unidad de cuenta que Banxico actualiza cada día
+// con la inflación; una cantidad en UDIS conserva su poder de compra. Convertir un monto
+// futuro a UDIS con el valor que tendrá la UDI en esa fecha equivale a expresarlo en pesos
+// constantes de hoy.
+
+// UDIsAPesos convierte UDIS a pesos con el valor de la UDI dado
+func UDIsAPesos(udis, valorUDI float64) float64 {
+	return udis * valorUDI
+}
+
+// PesosAUDIs convierte pesos a UDIS con el valor de la UDI dado
+func PesosAUDIs(pesos, valorUDI float64) float64 {
+	if valorUDI <= 0 {
+		return 0
+	}
+	return pesos / valorUDI
+}
+
+// ValorUDIProyectado estima el valor de la UDI dentro de los meses dados si la inflación
+// anual se mantiene
+func ValorUDIProyectado(valorHoy, inflacion, meses float64) float64 {
+	return valorHoy * math.Pow(1+inflacion, meses/12)
+}
+
+// PesosConstantes regresa cuánto vale hoy un monto en pesos corrientes que se recibe o se
+// paga dentro de los meses dados
+func PesosConstantes(monto, inflacion, meses float64) float64 {
+	return monto / math.Pow(1+inflacion, meses/12)
+}
+
+// PagosPesosConstantes suma los pagos de una tabla de amortización en pesos de hoy; cada
+// pago se descuenta con la inflación acumulada hasta su periodo
+func PagosPesosConstantes(tabla []RenglonAmortizacion, inflacion float64, periodosPorAño int) float64 {
+	total := 0.0
+	for _, r := range tabla {
+		total += PesosConstantes(r.Pago, inflacion, float64(r.Periodo)*12/float64(periodosPorAño))
+	}
+	return total
+}
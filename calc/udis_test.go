@@ -0,0 +1,42 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConversionUDIS(t *testing.T) {
+	if pesos := UDIsAPesos(1000, 8.5); pesos != 8500 {
+		t.Errorf("1000 UDIS a 8.5 = %.2f", pesos)
+	}
+	if udis := PesosAUDIs(8500, 8.5); udis != 1000 {
+		t.Errorf("8500 pesos a 8.5 = %.2f UDIS", udis)
+	}
+	if udis := PesosAUDIs(100, 0); udis != 0 {
+		t.Errorf("sin valor de UDI no se convierte: %.2f", udis)
+	}
+
+	// Un año con 4% de inflación: la UDI sube 4% y un peso de entonces vale 1/1.04 de hoy
+	if valor := ValorUDIProyectado(8, 0.04, 12); math.Abs(valor-8.32) > 1e-12 {
+		t.Errorf("UDI en un año: %.4f", valor)
+	}
+	if constante := PesosConstantes(1040, 0.04, 12); math.Abs(constante-1000) > 1e-9 {
+		t.Errorf("pesos constantes: %.4f", constante)
+	}
+	// Convertir a UDIS con el valor futuro y regresar con el de hoy da pesos constantes
+	if hoy := UDIsAPesos(PesosAUDIs(1040, ValorUDIProyectado(8, 0.04, 12)), 8); math.Abs(hoy-1000) > 1e-9 {
+		t.Errorf("ida y vuelta por UDIS: %.4f", hoy)
+	}
+}
+
+func TestPagosPesosConstantes(t *testing.T) {
+	tabla := []RenglonAmortizacion{{Periodo: 1, Pago: 1000}, {Periodo: 2, Pago: 1000}}
+	total := PagosPesosConstantes(tabla, 0.12, 12)
+	esperado := 1000/math.Pow(1.12, 1.0/12) + 1000/math.Pow(1.12, 2.0/12)
+	if math.Abs(total-esperado) > 1e-9 {
+		t.Errorf("PagosPesosConstantes = %.4f, se esperaba %.4f", total, esperado)
+	}
+	if sin := PagosPesosConstantes(tabla, 0, 12); sin != 2000 {
+		t.Errorf("sin inflación los pagos no cambian: %.2f", sin)
+	}
+}
@@ -0,0 +1,42 @@
+package calc
+
+import "fmt"
+
+// DIAS_MES_UMA es el factor oficial para convertir la UMA diaria en mensual
+const DIAS_MES_UMA = 30.4
+
+// umaDiaria guarda el valor diario de la Unidad de Medida y Actualización publicado por el INEGI
+var umaDiaria = map[int]float64{
+	2022: 96.22,
+	2023: 103.74,
+	2024: 108.57,
+	2025: 113.14,
+}
+
+// UMADiaria regresa el valor diario de la UMA del año indicado. Para años sin valor
+// publicado se usa el más reciente conocido.
+func UMADiaria(año int) (float64, error) {
+	if valor, ok := umaDiaria[año]; ok {
+		return valor, nil
+	}
+
+	ultimo := 0
+	for a := range umaDiaria {
+		if a > ultimo {
+			ultimo = a
+		}
+	}
+	if año < ultimo {
+		return 0, fmt.Errorf("No hay valor de la UMA para %d", año)
+	}
+	return umaDiaria[ultimo], nil
+}
+
+// UMAMensual regresa el valor mensual de la UMA del año indicado
+func UMAMensual(año int) (float64, error) {
+	diaria, err := UMADiaria(año)
+	if err != nil {
+		return 0, err
+	}
+	return diaria * DIAS_MES_UMA, nil
+}
@@ -0,0 +1,98 @@
+// Package calculadora permite agregar calculadoras a finmex desde otros paquetes de Go.
+//
+// Un paquete externo implementa Calculator y se registra en su función init:
+//
+//	func init() {
+//		calculadora.Registrar(miCalculadora{})
+//	}
+//
+// Para que finmex lo descubra basta importarlo en blanco desde plugins.go y recompilar.
+// Cada calculadora registrada aparece como un subcomando de "finmex calcular", con un flag
+// por cada uno de sus parámetros.
+package calculadora
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TipoValor indica cómo se muestra un parámetro o un resultado
+type TipoValor int
+
+const (
+	Numero   TipoValor = iota // Valor sin unidad
+	Monto                     // Cantidad en pesos
+	Tasa                      // Tasa en decimal (0.10 es 10%)
+	Periodos                  // Número de periodos, meses o turnos
+)
+
+// Parametro es un dato de entrada de una calculadora
+type Parametro struct {
+	Nombre         string // Nombre del flag, en minúsculas y con guiones
+	Descripcion    string
+	Tipo           TipoValor
+	Predeterminado float64
+	Requerido      bool
+}
+
+// Resultado es un valor calculado
+type Resultado struct {
+	Concepto string
+	Valor    float64
+	Tipo     TipoValor
+}
+
+// Calculator es un cálculo que finmex puede ejecutar con parámetros numéricos
+type Calculator interface {
+	Nombre() string      // Nombre del subcomando; debe ser único
+	Descripcion() string // Descripción corta para la ayuda
+	Parametros() []Parametro
+	Calcular(valores map[string]float64) ([]Resultado, error)
+}
+
+var (
+	mu          sync.RWMutex
+	registradas = map[string]Calculator{}
+)
+
+// Registrar agrega una calculadora. Se llama desde init; falla si la calculadora es nil o si
+// su nombre ya está registrado, igual que los drivers de database/sql.
+func Registrar(c Calculator) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if c == nil {
+		panic("calculadora: Registrar con una calculadora nil")
+	}
+	nombre := c.Nombre()
+	if nombre == "" {
+		panic("calculadora: Registrar con una calculadora sin nombre")
+	}
+	if _, existe := registradas[nombre]; existe {
+		panic(fmt.Sprintf("calculadora: la calculadora '%s' ya está registrada", nombre))
+	}
+	registradas[nombre] = c
+}
+
+// Obtener regresa la calculadora registrada con el nombre dado
+func Obtener(nombre string) (Calculator, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := registradas[nombre]
+	return c, ok
+}
+
+// Registradas regresa todas las calculadoras ordenadas por nombre
+func Registradas() []Calculator {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	lista := make([]Calculator, 0, len(registradas))
+	for _, c := range registradas {
+		lista = append(lista, c)
+	}
+	sort.Slice(lista, func(i, j int) bool { return lista[i].Nombre() < lista[j].Nombre() })
+	return lista
+}
@@ -0,0 +1,84 @@
+// Package tanda registra una calculadora para tandas: grupos en los que cada participante
+// aporta lo mismo cada periodo y, por turnos, uno de ellos recibe el total.
+//
+// Sirve como ejemplo de un módulo externo de finmex: basta importarlo en blanco.
+package tanda
+
+import (
+	"fmt"
+	"math"
+
+	"finmex/calculadora"
+)
+
+func init() {
+	calculadora.Registrar(Tanda{})
+}
+
+// Tanda calcula cuánto gana o pierde un participante según su turno, comparado con invertir
+// sus aportaciones a una tasa alternativa
+type Tanda struct{}
+
+// Nombre implementa calculadora.Calculator
+func (Tanda) Nombre() string {
+	return "tanda"
+}
+
+// Descripcion implementa calculadora.Calculator
+func (Tanda) Descripcion() string {
+	return "Ganancia o costo de un turno en una tanda contra invertir las aportaciones"
+}
+
+// Parametros implementa calculadora.Calculator
+func (Tanda) Parametros() []calculadora.Parametro {
+	return []calculadora.Parametro{
+		{Nombre: "aportacion", Descripcion: "Aportación de cada participante por periodo", Tipo: calculadora.Monto, Requerido: true},
+		{Nombre: "participantes", Descripcion: "Número de participantes", Tipo: calculadora.Periodos, Requerido: true},
+		{Nombre: "turno", Descripcion: "Periodo en el que recibes la tanda (1 es el primero)", Tipo: calculadora.Periodos, Requerido: true},
+		{Nombre: "tasa", Descripcion: "Tasa anual alternativa para tu dinero (decimal)", Tipo: calculadora.Tasa, Predeterminado: 0.10},
+		{Nombre: "periodos-anio", Descripcion: "Periodos por año: 52 semanal, 24 quincenal, 12 mensual", Tipo: calculadora.Numero, Predeterminado: 52},
+	}
+}
+
+// Calcular implementa calculadora.Calculator. Las aportaciones se hacen al inicio de cada
+// periodo y la tanda se recibe en el periodo del turno; todo se trae a valor presente con la
+// tasa alternativa.
+func (Tanda) Calcular(v map[string]float64) ([]calculadora.Resultado, error) {
+	aportacion := v["aportacion"]
+	participantes := int(v["participantes"])
+	turno := int(v["turno"])
+	periodosAño := v["periodos-anio"]
+
+	if aportacion <= 0 {
+		return nil, fmt.Errorf("La aportación debe ser mayor a cero")
+	}
+	if participantes < 2 || float64(participantes) != v["participantes"] {
+		return nil, fmt.Errorf("La tanda necesita al menos 2 participantes (número entero)")
+	}
+	if turno < 1 || turno > participantes || float64(turno) != v["turno"] {
+		return nil, fmt.Errorf("El turno debe ser un entero entre 1 y %d", participantes)
+	}
+	if periodosAño <= 0 {
+		return nil, fmt.Errorf("Los periodos por año deben ser mayores a cero")
+	}
+
+	i := v["tasa"] / periodosAño
+	descuento := func(periodo int) float64 { return math.Pow(1+i, -float64(periodo-1)) }
+
+	recibe := aportacion * float64(participantes)
+	vpAportaciones := 0.0
+	for t := 1; t <= participantes; t++ {
+		vpAportaciones += aportacion * descuento(t)
+	}
+	vpRecibe := recibe * descuento(turno)
+	neto := vpRecibe - vpAportaciones
+
+	return []calculadora.Resultado{
+		{Concepto: "Recibes en tu turno", Valor: recibe, Tipo: calculadora.Monto},
+		{Concepto: "Aportas en total", Valor: aportacion * float64(participantes), Tipo: calculadora.Monto},
+		{Concepto: "Duración (periodos)", Valor: float64(participantes), Tipo: calculadora.Periodos},
+		{Concepto: "Valor presente de lo que recibes", Valor: vpRecibe, Tipo: calculadora.Monto},
+		{Concepto: "Valor presente de lo que aportas", Valor: vpAportaciones, Tipo: calculadora.Monto},
+		{Concepto: "Ganancia (+) o costo (-) contra invertir", Valor: neto, Tipo: calculadora.Monto},
+	}, nil
+}
@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// version es la versión del binario; se fija al compilar con
+// -ldflags "-X finmex/cli.version=v1.2.3"
+var version = "dev"
+
+// clavePublicaReleases es la llave ed25519 (base64) con la que se firman los checksums de las
+// releases. Se fija al compilar con -ldflags "-X finmex/cli.clavePublicaReleases=...";
+// sin ella finmex actualizar no instala nada salvo con --sin-firma.
+var clavePublicaReleases = ""
+
+// URL_RELEASES es el API de GitHub con las releases de finmex
+const URL_RELEASES = "https://api.github.com/repos/Momentitos/finmex/releases"
+
+// Archivos que acompañan a cada release
+const (
+	archivoChecksums = "checksums.txt"
+	archivoFirma     = "checksums.txt.sig"
+)
+
+// Canales de actualización
+const (
+	CanalEstable = "estable"
+	CanalBeta    = "beta"
+)
+
+// ValidarCanal verifica que el canal de actualización sea uno de los soportados
+func ValidarCanal(canal string) error {
+	if canal != CanalEstable && canal != CanalBeta {
+		return fmt.Errorf("Canal inválido '%s' (usa estable o beta)", canal)
+	}
+	return nil
+}
+
+// ArchivoRelease es un archivo descargable de una release
+type ArchivoRelease struct {
+	Nombre string `json:"name"`
+	URL    string `json:"browser_download_url"`
+}
+
+// Release es una versión publicada en GitHub
+type Release struct {
+	Etiqueta   string           `json:"tag_name"`
+	Borrador   bool             `json:"draft"`
+	Preliminar bool             `json:"prerelease"`
+	Archivos   []ArchivoRelease `json:"assets"`
+}
+
+// archivo busca un archivo de la release por nombre
+func (r Release) archivo(nombre string) (ArchivoRelease, bool) {
+	for _, a := range r.Archivos {
+		if a.Nombre == nombre {
+			return a, true
+		}
+	}
+	return ArchivoRelease{}, false
+}
+
+// nombreBinario es el nombre del binario de la release para este sistema
+func nombreBinario() string {
+	nombre := fmt.Sprintf("finmex_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		nombre += ".exe"
+	}
+	return nombre
+}
+
+// urlReleases permite apuntar a otro servidor con FINMEX_URL_RELEASES, p. ej. un espejo
+func urlReleases() string {
+	if url := os.Getenv("FINMEX_URL_RELEASES"); url != "" {
+		return url
+	}
+	return URL_RELEASES
+}
+
+var clienteHTTP = &http.Client{Timeout: 60 * time.Second}
+
+// descargar obtiene el contenido de una URL
+func descargar(url string) ([]byte, error) {
+	registro.Debug("descargando", "url", url)
+	respuesta, err := clienteHTTP.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer respuesta.Body.Close()
+	if respuesta.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s respondió %s", url, respuesta.Status)
+	}
+	return io.ReadAll(respuesta.Body)
+}
+
+// UltimaRelease busca la release más reciente del canal. El canal beta incluye las
+// releases preliminares; el estable solo las definitivas.
+func UltimaRelease(canal string) (Release, error) {
+	data, err := descargar(urlReleases())
+	if err != nil {
+		return Release{}, fmt.Errorf("Error al consultar releases: %v", err)
+	}
+	var releases []Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return Release{}, fmt.Errorf("Respuesta de releases inválida: %v", err)
+	}
+
+	var mejor *Release
+	for i, r := range releases {
+		if r.Borrador || (r.Preliminar && canal != CanalBeta) {
+			continue
+		}
+		if _, ok := parsearVersion(r.Etiqueta); !ok {
+			continue
+		}
+		if mejor == nil || compararVersiones(r.Etiqueta, mejor.Etiqueta) > 0 {
+			mejor = &releases[i]
+		}
+	}
+	if mejor == nil {
+		return Release{}, fmt.Errorf("No hay releases publicadas en el canal %s", canal)
+	}
+	return *mejor, nil
+}
+
+// versionSemantica es una versión vMAYOR.MENOR.PARCHE con sufijo opcional (-beta.1)
+type versionSemantica struct {
+	Numeros    [3]int
+	Preliminar string
+}
+
+// parsearVersion interpreta una etiqueta de release
+func parsearVersion(etiqueta string) (versionSemantica, bool) {
+	var v versionSemantica
+	texto := strings.TrimPrefix(etiqueta, "v")
+	texto, v.Preliminar, _ = strings.Cut(texto, "-")
+	partes := strings.Split(texto, ".")
+	if len(partes) != 3 {
+		return v, false
+	}
+	for i, p := range partes {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.Numeros[i] = n
+	}
+	return v, true
+}
+
+// compararVersiones regresa 1 si a es más nueva que b, -1 si es más vieja y 0 si son
+// iguales. Una versión preliminar es anterior a la definitiva con los mismos números.
+func compararVersiones(a, b string) int {
+	va, _ := parsearVersion(a)
+	vb, _ := parsearVersion(b)
+	for i := range va.Numeros {
+		if va.Numeros[i] != vb.Numeros[i] {
+			if va.Numeros[i] > vb.Numeros[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	switch {
+	case va.Preliminar == vb.Preliminar:
+		return 0
+	case va.Preliminar == "":
+		return 1
+	case vb.Preliminar == "":
+		return -1
+	}
+	return compararPreliminares(va.Preliminar, vb.Preliminar)
+}
+
+// compararPreliminares ordena sufijos como beta.2 y beta.10 comparando numéricamente las
+// partes que son números
+func compararPreliminares(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			if na > nb {
+				return 1
+			}
+			return -1
+		case (errA != nil || errB != nil) && pa[i] != pb[i]:
+			if pa[i] > pb[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	switch {
+	case len(pa) > len(pb):
+		return 1
+	case len(pa) < len(pb):
+		return -1
+	}
+	return 0
+}
+
+// HayActualizacion indica si la release es más nueva que la versión instalada. Una
+// compilación de desarrollo nunca se considera desactualizada.
+func HayActualizacion(r Release) bool {
+	if _, ok := parsearVersion(version); !ok {
+		return false
+	}
+	return compararVersiones(r.Etiqueta, version) > 0
+}
+
+// checksumEsperado busca el SHA-256 de un archivo en un checksums.txt con formato sha256sum
+func checksumEsperado(checksums []byte, nombre string) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		campos := strings.Fields(scanner.Text())
+		if len(campos) == 2 && strings.TrimPrefix(campos[1], "*") == nombre {
+			return strings.ToLower(campos[0]), true
+		}
+	}
+	return "", false
+}
+
+// verificarFirma comprueba la firma ed25519 del archivo de checksums
+func verificarFirma(checksums, firma []byte) error {
+	clave, err := base64.StdEncoding.DecodeString(clavePublicaReleases)
+	if err != nil || len(clave) != ed25519.PublicKeySize {
+		return fmt.Errorf("La llave pública de releases compilada en el binario es inválida")
+	}
+	decodificada, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(firma)))
+	if err != nil {
+		decodificada = firma
+	}
+	if !ed25519.Verify(ed25519.PublicKey(clave), checksums, decodificada) {
+		return fmt.Errorf("La firma de %s no es válida", archivoChecksums)
+	}
+	return nil
+}
+
+// DescargarBinario descarga el binario de la release para este sistema y verifica la firma
+// de los checksums y su checksum. Si el binario no trae llave pública se rechaza la descarga,
+// salvo con sinFirma, que solo verifica el checksum.
+func DescargarBinario(r Release, sinFirma bool) ([]byte, error) {
+	if clavePublicaReleases == "" && !sinFirma {
+		return nil, fmt.Errorf("Este binario no trae la llave pública de releases y no puede verificar la firma de %s; usa --sin-firma para instalar verificando solo el checksum", r.Etiqueta)
+	}
+	binario, ok := r.archivo(nombreBinario())
+	if !ok {
+		return nil, fmt.Errorf("La release %s no tiene binario para %s/%s", r.Etiqueta, runtime.GOOS, runtime.GOARCH)
+	}
+	archivoSumas, ok := r.archivo(archivoChecksums)
+	if !ok {
+		return nil, fmt.Errorf("La release %s no publica %s; no se puede verificar", r.Etiqueta, archivoChecksums)
+	}
+
+	checksums, err := descargar(archivoSumas.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Error al descargar %s: %v", archivoChecksums, err)
+	}
+	if clavePublicaReleases != "" {
+		archivoSig, ok := r.archivo(archivoFirma)
+		if !ok {
+			return nil, fmt.Errorf("La release %s no está firmada", r.Etiqueta)
+		}
+		firma, err := descargar(archivoSig.URL)
+		if err != nil {
+			return nil, fmt.Errorf("Error al descargar la firma: %v", err)
+		}
+		if err := verificarFirma(checksums, firma); err != nil {
+			return nil, err
+		}
+	}
+
+	esperado, ok := checksumEsperado(checksums, binario.Nombre)
+	if !ok {
+		return nil, fmt.Errorf("%s no incluye el checksum de %s", archivoChecksums, binario.Nombre)
+	}
+	data, err := descargar(binario.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Error al descargar %s: %v", binario.Nombre, err)
+	}
+	suma := sha256.Sum256(data)
+	if hex.EncodeToString(suma[:]) != esperado {
+		return nil, fmt.Errorf("El checksum de %s no coincide; la descarga se descartó", binario.Nombre)
+	}
+	return data, nil
+}
+
+// ReemplazarBinario instala el binario nuevo en lugar del ejecutable actual. Se escribe
+// junto al actual y se renombra para que una falla a medias no deje un binario roto.
+func ReemplazarBinario(data []byte) (string, error) {
+	actual, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if actual, err = filepath.EvalSymlinks(actual); err != nil {
+		return "", err
+	}
+
+	nuevo := actual + ".nuevo"
+	if err := os.WriteFile(nuevo, data, 0755); err != nil {
+		return "", fmt.Errorf("No se pudo escribir junto a %s: %v", actual, err)
+	}
+
+	// En Windows no se puede sobrescribir un ejecutable en uso, pero sí renombrarlo
+	anterior := actual + ".anterior"
+	os.Remove(anterior)
+	if err := os.Rename(actual, anterior); err != nil {
+		os.Remove(nuevo)
+		return "", err
+	}
+	if err := os.Rename(nuevo, actual); err != nil {
+		os.Rename(anterior, actual)
+		return "", err
+	}
+	os.Remove(anterior)
+	return actual, nil
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// servirRelease publica un binario con su checksums.txt firmado con la llave privada y
+// regresa la release que apunta a él con el contador de solicitudes
+func servirRelease(t *testing.T, privada ed25519.PrivateKey) (Release, *int) {
+	binario := []byte("binario nuevo")
+	suma := sha256.Sum256(binario)
+	checksums := []byte(hex.EncodeToString(suma[:]) + "  " + nombreBinario() + "\n")
+	firma := base64.StdEncoding.EncodeToString(ed25519.Sign(privada, checksums))
+
+	solicitudes := 0
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		solicitudes++
+		switch r.URL.Path {
+		case "/" + nombreBinario():
+			w.Write(binario)
+		case "/" + archivoChecksums:
+			w.Write(checksums)
+		case "/" + archivoFirma:
+			w.Write([]byte(firma))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(servidor.Close)
+
+	r := Release{Etiqueta: "v9.0.0"}
+	for _, nombre := range []string{nombreBinario(), archivoChecksums, archivoFirma} {
+		r.Archivos = append(r.Archivos, ArchivoRelease{Nombre: nombre, URL: servidor.URL + "/" + nombre})
+	}
+	return r, &solicitudes
+}
+
+// conClave compila en el binario la llave pública mientras dura la prueba
+func conClave(t *testing.T, clave string) {
+	anterior := clavePublicaReleases
+	clavePublicaReleases = clave
+	t.Cleanup(func() { clavePublicaReleases = anterior })
+}
+
+func TestDescargarBinarioSinLlaveSeRechaza(t *testing.T) {
+	_, privada, _ := ed25519.GenerateKey(nil)
+	r, solicitudes := servirRelease(t, privada)
+	conClave(t, "")
+
+	if _, err := DescargarBinario(r, false); err == nil || !strings.Contains(err.Error(), "--sin-firma") {
+		t.Errorf("sin llave se esperaba un error que mencione --sin-firma, no %v", err)
+	}
+	if *solicitudes != 0 {
+		t.Errorf("no se debe descargar nada sin llave; hubo %d solicitudes", *solicitudes)
+	}
+
+	data, err := DescargarBinario(r, true)
+	if err != nil || string(data) != "binario nuevo" {
+		t.Errorf("con --sin-firma se esperaba el binario, no %q, %v", data, err)
+	}
+}
+
+func TestDescargarBinarioVerificaLaFirma(t *testing.T) {
+	publica, privada, _ := ed25519.GenerateKey(nil)
+	r, _ := servirRelease(t, privada)
+	conClave(t, base64.StdEncoding.EncodeToString(publica))
+
+	if data, err := DescargarBinario(r, false); err != nil || string(data) != "binario nuevo" {
+		t.Errorf("firma válida: %q, %v", data, err)
+	}
+
+	// Una release firmada con otra llave no se instala aunque se pida --sin-firma
+	_, otra, _ := ed25519.GenerateKey(nil)
+	falsa, _ := servirRelease(t, otra)
+	if _, err := DescargarBinario(falsa, true); err == nil || !strings.Contains(err.Error(), "no es válida") {
+		t.Errorf("firma con otra llave: se esperaba error, no %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"finmex/calc"
+)
+
+// COMISION_AFORE es la comisión máxima autorizada a las Afores sobre el saldo (2024)
+const COMISION_AFORE = 0.0057
+
+// SieforeGeneracional relaciona el año de nacimiento con la Siefore que administra la cuenta
+type SieforeGeneracional struct {
+	Nombre           string
+	NacidoDesde      int
+	RendimientoAnual float64 // Rendimiento nominal histórico aproximado de largo plazo
+}
+
+// siefores ordenadas de la más conservadora a la más agresiva. Los rendimientos son
+// aproximaciones de largo plazo y deben ajustarse con los datos vigentes de CONSAR.
+var siefores = []SieforeGeneracional{
+	{"SB 55-59", 1955, 0.070},
+	{"SB 60-64", 1960, 0.075},
+	{"SB 65-69", 1965, 0.080},
+	{"SB 70-74", 1970, 0.085},
+	{"SB 75-79", 1975, 0.090},
+	{"SB 80-84", 1980, 0.095},
+	{"SB 85-89", 1985, 0.100},
+	{"SB 90-94", 1990, 0.105},
+	{"SB 95-99", 1995, 0.110},
+	{"SB Inicial", 2000, 0.110},
+}
+
+// SieforeParaAño regresa la Siefore generacional que corresponde al año de nacimiento
+func SieforeParaAño(nacimiento int) (SieforeGeneracional, error) {
+	i := sort.Search(len(siefores), func(i int) bool { return siefores[i].NacidoDesde > nacimiento })
+	if i == 0 {
+		return SieforeGeneracional{}, fmt.Errorf("No hay Siefore generacional para nacidos en %d", nacimiento)
+	}
+	return siefores[i-1], nil
+}
+
+// AportacionVoluntaria describe aportaciones mensuales a la subcuenta voluntaria de la Afore
+type AportacionVoluntaria struct {
+	Mensual      float64
+	Meses        int
+	Rendimiento  float64 // Rendimiento anual de la Siefore
+	Comision     float64 // Comisión anual sobre saldo
+	Deducir      bool    // Se deducen como aportaciones complementarias
+	IngresoAnual float64
+}
+
+// ResultadoVoluntaria compara la Afore contra invertir lo mismo por fuera
+type ResultadoVoluntaria struct {
+	Aportado     float64
+	SaldoAfore   float64
+	Devoluciones float64 // ISR devuelto por deducir, sin rendimientos
+	ValorAfore   float64 // Saldo más devoluciones invertidas a la tasa alternativa
+	SaldoCETES   float64
+	SaldoDebito  float64
+}
+
+// SimularAportacionVoluntaria acumula las aportaciones en la Afore y en las alternativas. Las
+// tasas alternativas son brutas; se les descuenta el ISR sobre intereses. Las devoluciones
+// de ISR de cada año se invierten en la mejor alternativa.
+func SimularAportacionVoluntaria(a AportacionVoluntaria, tasaCETES, tasaDebito float64, año int) (ResultadoVoluntaria, error) {
+	r := ResultadoVoluntaria{Aportado: a.Mensual * float64(a.Meses)}
+	r.SaldoAfore = calc.ValorFuturoAportaciones(a.Mensual, a.Rendimiento-a.Comision, a.Meses)
+	r.SaldoCETES = calc.ValorFuturoAportaciones(a.Mensual, TasaNetaISR(tasaCETES), a.Meses)
+	r.SaldoDebito = calc.ValorFuturoAportaciones(a.Mensual, TasaNetaISR(tasaDebito), a.Meses)
+	r.ValorAfore = r.SaldoAfore
+
+	if !a.Deducir {
+		return r, nil
+	}
+
+	tope, err := TopeDeduccionPPR(a.IngresoAnual, año)
+	if err != nil {
+		return r, err
+	}
+	devolucion := DevolucionISR(a.IngresoAnual, math.Min(a.Mensual*12, tope))
+
+	alternativa := tasaCETES
+	if tasaDebito > alternativa {
+		alternativa = tasaDebito
+	}
+	acumulado := 0.0
+	for mes := 12; mes <= a.Meses; mes += 12 {
+		restantes := a.Meses - mes
+		acumulado += devolucion * math.Pow(1+TasaNetaISR(alternativa)/12, float64(restantes))
+		r.Devoluciones += devolucion
+	}
+	r.ValorAfore += acumulado
+	return r, nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"finmex/storage"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ARCHIVO_BASE_DATOS guarda tarjetas y movimientos cuando el almacén es SQLite
+const ARCHIVO_BASE_DATOS = "finmex.db"
+
+// Almacenes que acepta --almacen
+const (
+	AlmacenSQLite = "sqlite"
+	AlmacenJSON   = "json"
+)
+
+// SUFIJO_MIGRADO se agrega a los archivos JSON después de copiarlos a SQLite
+const SUFIJO_MIGRADO = ".migrado"
+
+var (
+	tipoAlmacen = AlmacenSQLite
+	almacen     storage.Storage // Se abre la primera vez que un comando lo necesita
+)
+
+// flagAlmacen elige dónde se guardan los datos
+func flagAlmacen() cli.Flag {
+	return &cli.StringFlag{Name: "almacen", Value: AlmacenSQLite, Usage: "Dónde guardar los datos: sqlite (finmex.db) o json (tarjetas.json y movimientos.ndjson)", EnvVars: []string{"FINMEX_ALMACEN"}}
+}
+
+// elegirAlmacen valida el almacén pedido; no abre nada hasta que se use
+func elegirAlmacen(tipo string) error {
+	switch tipo {
+	case AlmacenSQLite, AlmacenJSON:
+		tipoAlmacen = tipo
+		return nil
+	}
+	return fmt.Errorf("Almacén inválido '%s' (usa sqlite o json)", tipo)
+}
+
+// almacenDatos regresa el almacén elegido, abriéndolo la primera vez. Si la base SQLite
+// no existe todavía pero hay archivos JSON de una versión anterior, los migra. Los datos
+// cifrados con 'finmex cifrar' se usan sin importar el almacén elegido.
+func almacenDatos() (storage.Storage, error) {
+	if almacen != nil {
+		return almacen, nil
+	}
+	if almacenCifrado() {
+		cifrado, err := abrirAlmacenCifrado()
+		if err != nil {
+			return nil, err
+		}
+		almacen = cifrado
+		return almacen, nil
+	}
+
+	if tipoAlmacen == AlmacenJSON {
+		almacen = storage.NuevoArchivosJSON(rutaDatos(ARCHIVO_TARJETAS), rutaDatos(ARCHIVO_MOVIMIENTOS))
+		return almacen, nil
+	}
+
+	nueva := !storage.Existe(rutaDatos(ARCHIVO_BASE_DATOS))
+	registro.Debug("abriendo base de datos", "archivo", rutaDatos(ARCHIVO_BASE_DATOS), "nueva", nueva)
+	db, err := storage.AbrirSQLite(rutaDatos(ARCHIVO_BASE_DATOS))
+	if err != nil {
+		return nil, err
+	}
+	if nueva {
+		if err := migrarDesdeJSON(db); err != nil {
+			db.Close()
+			os.Remove(rutaDatos(ARCHIVO_BASE_DATOS))
+			return nil, err
+		}
+	}
+	almacen = db
+	return almacen, nil
+}
+
+// cerrarAlmacen cierra el almacén si se llegó a abrir
+func cerrarAlmacen() {
+	if almacen != nil {
+		almacen.Close()
+		almacen = nil
+	}
+}
+
+// migrarDesdeJSON copia a la base nueva tarjetas.json y movimientos.ndjson, si existen, y
+// los renombra con SUFIJO_MIGRADO para que no parezca que siguen en uso. Los archivos
+// originales no se tocan si la copia falla.
+func migrarDesdeJSON(db *storage.SQLite) error {
+	if !storage.Existe(rutaDatos(ARCHIVO_TARJETAS)) && !storage.Existe(rutaDatos(ARCHIVO_MOVIMIENTOS)) {
+		return nil
+	}
+
+	registro.Info("migrando archivos JSON a SQLite", "origen", rutaDatos(ARCHIVO_TARJETAS), "destino", rutaDatos(ARCHIVO_BASE_DATOS))
+	origen := storage.NuevoArchivosJSON(rutaDatos(ARCHIVO_TARJETAS), rutaDatos(ARCHIVO_MOVIMIENTOS))
+	if err := storage.Copiar(db, origen); err != nil {
+		var formato *storage.ErrorFormato
+		if errors.As(err, &formato) {
+			return errArchivoCorrupto(formato.Archivo, formato.Causa)
+		}
+		return fmt.Errorf("No se pudieron migrar los datos a %s: %v", rutaDatos(ARCHIVO_BASE_DATOS), err)
+	}
+
+	for _, archivo := range []string{rutaDatos(ARCHIVO_TARJETAS), rutaDatos(ARCHIVO_MOVIMIENTOS)} {
+		if !storage.Existe(archivo) {
+			continue
+		}
+		if err := os.Rename(archivo, archivo+SUFIJO_MIGRADO); err != nil {
+			registro.Warn("no se pudo renombrar el archivo migrado", "archivo", archivo, "error", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "AVISO: Tus datos se migraron a %s; los archivos JSON originales quedaron con la extensión %s. Usa 'finmex exportar' para obtener el JSON o --almacen json para seguir usando archivos.\n",
+		rutaDatos(ARCHIVO_BASE_DATOS), SUFIJO_MIGRADO)
+	return nil
+}
+
+// almacenEsArchivoJSON indica si los movimientos están en movimientos.ndjson, que es lo que
+// recorre el índice de búsqueda
+func almacenEsArchivoJSON() bool {
+	return tipoAlmacen == AlmacenJSON && !almacenCifrado()
+}
+
+// rutaAlmacen es el archivo donde quedan las tarjetas con el almacén elegido
+func rutaAlmacen() string {
+	if almacenCifrado() {
+		return rutaDatos(ARCHIVO_CIFRADO)
+	}
+	if tipoAlmacen == AlmacenJSON {
+		return rutaDatos(ARCHIVO_TARJETAS)
+	}
+	return rutaDatos(ARCHIVO_BASE_DATOS)
+}
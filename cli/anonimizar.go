@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+)
+
+// Anonimizador reemplaza los datos personales de una exportación. Los nombres se cambian
+// por hashes con una llave aleatoria, de modo que el mismo nombre siempre da el mismo hash
+// dentro de la exportación pero no se puede adivinar el original. Los montos se multiplican
+// por un mismo factor aleatorio para conservar las proporciones entre ellos. Tasas, plazos,
+// fechas e instituciones se conservan.
+type Anonimizador struct {
+	llave  []byte
+	factor float64
+}
+
+// NuevoAnonimizador crea un anonimizador con llave y factor de escala aleatorios
+func NuevoAnonimizador() (*Anonimizador, error) {
+	llave := make([]byte, 32)
+	if _, err := rand.Read(llave); err != nil {
+		return nil, err
+	}
+	// El factor queda entre 0.5 y 2 y se deriva de la llave para no guardarlo aparte
+	fraccion := float64(binary.BigEndian.Uint64(llave[:8])>>11) / (1 << 53)
+	return &Anonimizador{llave: llave, factor: math.Pow(4, fraccion) / 2}, nil
+}
+
+// Nombre convierte un nombre en un identificador con el prefijo dado. Se normaliza antes
+// para que las referencias entre productos sigan coincidiendo.
+func (a *Anonimizador) Nombre(prefijo, nombre string) string {
+	if nombre == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, a.llave)
+	mac.Write([]byte(normalizarClave(nombre)))
+	return prefijo + "-" + hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// Nombres anonimiza una lista de nombres o etiquetas
+func (a *Anonimizador) Nombres(prefijo string, nombres []string) []string {
+	if nombres == nil {
+		return nil
+	}
+	resultado := make([]string, len(nombres))
+	for i, n := range nombres {
+		resultado[i] = a.Nombre(prefijo, n)
+	}
+	return resultado
+}
+
+// Monto escala una cantidad en pesos y la redondea a centavos
+func (a *Anonimizador) Monto(monto float64) float64 {
+	return math.Round(monto*a.factor*100) / 100
+}
+
+// Tarjetas regresa una copia anonimizada de todos los productos
+func (a *Anonimizador) Tarjetas(t Tarjetas) Tarjetas {
+	r := Tarjetas{
+		Debito:  make([]TarjetaDebito, len(t.Debito)),
+		Credito: make([]TarjetaCredito, len(t.Credito)),
+	}
+
+	for i, d := range t.Debito {
+		d.Nombre = a.Nombre("tarjeta", d.Nombre)
+		d.SaldoMinimo = a.Monto(d.SaldoMinimo)
+		d.ComisionAnual = a.Monto(d.ComisionAnual)
+		d.ComisionInactividad = a.Monto(d.ComisionInactividad)
+		d.Saldo = a.Monto(d.Saldo)
+		d.Tags = a.Nombres("etiqueta", d.Tags)
+		r.Debito[i] = d
+	}
+
+	for i, c := range t.Credito {
+		c.Nombre = a.Nombre("tarjeta", c.Nombre)
+		c.ComisionAnual = a.Monto(c.ComisionAnual)
+		c.LimiteCredito = a.Monto(c.LimiteCredito)
+		c.Saldo = a.Monto(c.Saldo)
+		c.TopeCashback = a.Monto(c.TopeCashback)
+		c.Tags = a.Nombres("etiqueta", c.Tags)
+		planes := make([]PlanMSI, len(c.Planes))
+		for j, p := range c.Planes {
+			p.Concepto = a.Nombre("compra", p.Concepto)
+			p.Monto = a.Monto(p.Monto)
+			planes[j] = p
+		}
+		if c.Planes == nil {
+			planes = nil
+		}
+		c.Planes = planes
+		r.Credito[i] = c
+	}
+
+	for _, n := range t.Nomina {
+		n.Nombre = a.Nombre("cuenta", n.Nombre)
+		n.ComisionMensual = a.Monto(n.ComisionMensual)
+		n.PromocionTraspaso = a.Monto(n.PromocionTraspaso)
+		n.BeneficioAnual = a.Monto(n.BeneficioAnual)
+		r.Nomina = append(r.Nomina, n)
+	}
+
+	for _, m := range t.Microcreditos {
+		m.Nombre = a.Nombre("microcredito", m.Nombre)
+		m.Monto = a.Monto(m.Monto)
+		m.Comision = a.Monto(m.Comision)
+		m.Interes = a.Monto(m.Interes)
+		r.Microcreditos = append(r.Microcreditos, m)
+	}
+
+	for _, b := range t.BNPL {
+		b.Nombre = a.Nombre("compra", b.Nombre)
+		b.Monto = a.Monto(b.Monto)
+		b.Comision = a.Monto(b.Comision)
+		r.BNPL = append(r.BNPL, b)
+	}
+
+	for _, m := range t.Monederos {
+		m.Nombre = a.Nombre("monedero", m.Nombre)
+		m.Saldo = a.Monto(m.Saldo)
+		r.Monederos = append(r.Monederos, m)
+	}
+
+	for _, v := range t.Vales {
+		v.Nombre = a.Nombre("vales", v.Nombre)
+		v.MontoMensual = a.Monto(v.MontoMensual)
+		r.Vales = append(r.Vales, v)
+	}
+
+	for _, c := range t.Cajas {
+		c.Nombre = a.Nombre("caja", c.Nombre)
+		c.ParteSocial = a.Monto(c.ParteSocial)
+		c.Ahorro = a.Monto(c.Ahorro)
+		c.SaldoPrestamo = a.Monto(c.SaldoPrestamo)
+		r.Cajas = append(r.Cajas, c)
+	}
+
+	for _, p := range t.Informales {
+		p.Persona = a.Nombre("persona", p.Persona)
+		p.Concepto = a.Nombre("concepto", p.Concepto)
+		p.Monto = a.Monto(p.Monto)
+		p.Interes = a.Monto(p.Interes)
+		p.Abonado = a.Monto(p.Abonado)
+		r.Informales = append(r.Informales, p)
+	}
+
+	for _, p := range t.PPR {
+		p.Nombre = a.Nombre("plan", p.Nombre)
+		p.Saldo = a.Monto(p.Saldo)
+		p.AportacionAnual = a.Monto(p.AportacionAnual)
+		r.PPR = append(r.PPR, p)
+	}
+
+	for _, m := range t.Metas {
+		m.Nombre = a.Nombre("meta", m.Nombre)
+		m.Objetivo = a.Monto(m.Objetivo)
+		var aportes []AporteMeta
+		for _, ap := range m.Aportes {
+			ap.Monto = a.Monto(ap.Monto)
+			aportes = append(aportes, ap)
+		}
+		m.Aportes = aportes
+		// El producto lleva el mismo nombre anonimizado que su cuenta o inversión
+		prefijos := map[string]string{ProductoDebito: "tarjeta", ProductoSofipo: "cuenta", ProductoCetes: "inversion", ProductoPagare: "pagare"}
+		if prefijo, ok := prefijos[m.TipoProducto]; ok {
+			m.Producto = a.Nombre(prefijo, m.Producto)
+		} else {
+			m.Producto = a.Nombre("producto", m.Producto)
+		}
+		r.Metas = append(r.Metas, m)
+	}
+
+	for _, p := range t.Polizas {
+		p.Nombre = a.Nombre("poliza", p.Nombre)
+		p.PrimaAnual = a.Monto(p.PrimaAnual)
+		p.Deducible = a.Monto(p.Deducible)
+		p.TopeCoaseguro = a.Monto(p.TopeCoaseguro)
+		p.SumaAsegurada = a.Monto(p.SumaAsegurada)
+		r.Polizas = append(r.Polizas, p)
+	}
+
+	for _, b := range t.Bonos {
+		b.Tarjeta = a.Nombre("tarjeta", b.Tarjeta)
+		b.Recompensa = a.Monto(b.Recompensa)
+		b.GastoMinimo = a.Monto(b.GastoMinimo)
+		r.Bonos = append(r.Bonos, b)
+	}
+
+	for _, c := range t.Cetes {
+		c.Nombre = a.Nombre("inversion", c.Nombre)
+		c.Monto = a.Monto(c.Monto)
+		r.Cetes = append(r.Cetes, c)
+	}
+
+	for _, s := range t.Sofipos {
+		s.Nombre = a.Nombre("cuenta", s.Nombre)
+		s.Saldo = a.Monto(s.Saldo)
+		r.Sofipos = append(r.Sofipos, s)
+	}
+
+	for _, p := range t.Pagares {
+		p.Nombre = a.Nombre("pagare", p.Nombre)
+		p.Monto = a.Monto(p.Monto)
+		r.Pagares = append(r.Pagares, p)
+	}
+
+	// Las categorías se conservan, igual que en los movimientos
+	if t.Presupuesto != nil {
+		p := &Presupuesto{}
+		for _, i := range t.Presupuesto.Ingresos {
+			p.Ingresos = append(p.Ingresos, IngresoPresupuesto{Nombre: a.Nombre("ingreso", i.Nombre), Monto: a.Monto(i.Monto)})
+		}
+		for _, c := range t.Presupuesto.Categorias {
+			p.Categorias = append(p.Categorias, CategoriaPresupuesto{Nombre: c.Nombre, Limite: a.Monto(c.Limite)})
+		}
+		r.Presupuesto = p
+	}
+
+	for _, s := range t.Saldos {
+		s.Tarjeta = a.Nombre("tarjeta", s.Tarjeta)
+		s.Saldo = a.Monto(s.Saldo)
+		r.Saldos = append(r.Saldos, s)
+	}
+
+	if t.Retiro != nil {
+		d := *t.Retiro
+		d.Salario = a.Monto(d.Salario)
+		d.SaldoAfore = a.Monto(d.SaldoAfore)
+		d.Voluntaria = a.Monto(d.Voluntaria)
+		r.Retiro = &d
+	}
+
+	return r
+}
+
+// Movimiento regresa una copia anonimizada de un movimiento del historial; la categoría se
+// conserva porque no identifica a nadie
+func (a *Anonimizador) Movimiento(m Movimiento) Movimiento {
+	m.Descripcion = a.Nombre("movimiento", m.Descripcion)
+	m.Tarjeta = a.Nombre("tarjeta", m.Tarjeta)
+	m.Monto = a.Monto(m.Monto)
+	return m
+}
@@ -0,0 +1,46 @@
+package cli
+
+import "finmex/api"
+
+// Los documentos que viajan por la API HTTP se definen en el paquete api, que también usa
+// el paquete client; los alias permiten usarlos aquí sin prefijo. Los productos que tienen
+// métodos en cli se declaran en su archivo como tipos propios sobre los de api.
+type (
+	CodigoError                 = api.CodigoError
+	ErrorFinmex                 = api.ErrorFinmex
+	CuentaNomina                = api.CuentaNomina
+	Microcredito                = api.Microcredito
+	ValeDespensa                = api.ValeDespensa
+	PlanRetiro                  = api.PlanRetiro
+	AporteMeta                  = api.AporteMeta
+	TramoSofipo                 = api.TramoSofipo
+	IngresoPresupuesto          = api.IngresoPresupuesto
+	CategoriaPresupuesto        = api.CategoriaPresupuesto
+	DatosRetiro                 = api.DatosRetiro
+	RegistroSaldo               = api.RegistroSaldo
+	FilaDebito                  = api.FilaDebito
+	FilaCredito                 = api.FilaCredito
+	AnalisisDebito              = api.AnalisisDebito
+	RiesgoCambiario             = api.RiesgoCambiario
+	AnalisisCredito             = api.AnalisisCredito
+	RenglonTablaCredito         = api.RenglonTablaCredito
+	MontoUDIS                   = api.MontoUDIS
+	ResultadoComparacionDebito  = api.ResultadoComparacionDebito
+	ResultadoComparacionCredito = api.ResultadoComparacionCredito
+	EstadoServidor              = api.EstadoServidor
+)
+
+const (
+	CodigoTarjetaNoEncontrada = api.CodigoTarjetaNoEncontrada
+	CodigoDatosInvalidos      = api.CodigoDatosInvalidos
+	CodigoArchivoCorrupto     = api.CodigoArchivoCorrupto
+	CodigoVersionFutura       = api.CodigoVersionFutura
+)
+
+// Errores de referencia para comparar con errors.Is sin importar el detalle del mensaje
+var (
+	ErrTarjetaNoEncontrada = api.ErrTarjetaNoEncontrada
+	ErrDatosInvalidos      = api.ErrDatosInvalidos
+	ErrArchivoCorrupto     = api.ErrArchivoCorrupto
+	ErrVersionFutura       = api.ErrVersionFutura
+)
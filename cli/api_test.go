@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"finmex/api"
+)
+
+// mismaForma indica si dos tipos se codifican igual en JSON: los productos de cli son tipos
+// propios sobre los de api, con los mismos campos
+func mismaForma(a, b reflect.Type) bool {
+	if a.Kind() != b.Kind() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Slice, reflect.Ptr:
+		return mismaForma(a.Elem(), b.Elem())
+	}
+	return a.ConvertibleTo(b)
+}
+
+func TestTarjetasComoEnLaAPI(t *testing.T) {
+	propias, publicas := reflect.TypeOf(Tarjetas{}), reflect.TypeOf(api.Tarjetas{})
+	if propias.NumField() != publicas.NumField() {
+		t.Fatalf("Tarjetas tiene %d campos y api.Tarjetas %d", propias.NumField(), publicas.NumField())
+	}
+	for i := 0; i < propias.NumField(); i++ {
+		a, b := propias.Field(i), publicas.Field(i)
+		if a.Name != b.Name || a.Tag.Get("json") != b.Tag.Get("json") || !mismaForma(a.Type, b.Type) {
+			t.Errorf("campo %d: %s %s `%s` contra %s %s `%s`", i, a.Name, a.Type, a.Tag, b.Name, b.Type, b.Tag)
+		}
+	}
+}
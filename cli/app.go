@@ -0,0 +1,1062 @@
+// Package cli implementa la línea de comandos de finmex: los comandos, la captura de datos,
+// el daemon y la persistencia de los productos registrados. Los cálculos viven en el
+// paquete calc y el acceso a archivos en storage; el binario solo llama a Ejecutar.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"finmex/calc"
+	"finmex/storage"
+	"github.com/urfave/cli/v2"
+)
+
+// ARCHIVO_TARJETAS guarda las tarjetas y demás productos registrados
+const ARCHIVO_TARJETAS = "tarjetas.json"
+
+// Tarjetas almacena todas las tarjetas y demás productos guardados. Tiene los campos de
+// api.Tarjetas, con los tipos de cli que agregan los métodos de cada producto.
+type Tarjetas struct {
+	Version       int                `json:"version"` // Versión del formato del archivo, ver VERSION_ESQUEMA
+	Debito        []TarjetaDebito    `json:"debito"`
+	Credito       []TarjetaCredito   `json:"credito"`
+	Nomina        []CuentaNomina     `json:"nomina,omitempty"`
+	Microcreditos []Microcredito     `json:"microcreditos,omitempty"`
+	BNPL          []CompraBNPL       `json:"bnpl,omitempty"`
+	Monederos     []Monedero         `json:"monederos,omitempty"`
+	Vales         []ValeDespensa     `json:"vales,omitempty"`
+	Cajas         []CajaAhorro       `json:"cajas,omitempty"`
+	Informales    []PrestamoInformal `json:"informales,omitempty"`
+	PPR           []PlanRetiro       `json:"ppr,omitempty"`
+	Metas         []Meta             `json:"metas,omitempty"`
+	Polizas       []Poliza           `json:"polizas,omitempty"`
+	Bonos         []BonoBienvenida   `json:"bonos,omitempty"`
+	Cetes         []InversionCetes   `json:"cetes,omitempty"`
+	Sofipos       []CuentaSofipo     `json:"sofipos,omitempty"`
+	Pagares       []PagareBancario   `json:"pagares,omitempty"`
+	Presupuesto   *Presupuesto       `json:"presupuesto,omitempty"`
+	Retiro        *DatosRetiro       `json:"retiro,omitempty"`
+	Saldos        []RegistroSaldo    `json:"saldos,omitempty"` // Historial mensual de saldos por tarjeta
+}
+
+// CargarTarjetas carga las tarjetas desde el daemon, si está activo, o desde el almacén. Con
+// un escenario activo las tasas se ajustan como indica.
+func CargarTarjetas() (Tarjetas, error) {
+	tarjetas, err := leerTarjetas()
+	if err == nil && escenarioActivo != nil {
+		tarjetas = escenarioActivo.AjustarTarjetas(tarjetas)
+	}
+	return tarjetas, err
+}
+
+// leerTarjetas carga las tarjetas del daemon, si está activo, o del almacén
+func leerTarjetas() (Tarjetas, error) {
+	if cliente, ok := conectarDaemon(); ok {
+		defer cliente.Close()
+		registro.Debug("cargando tarjetas desde el daemon", "socket", rutaDatos(ARCHIVO_SOCKET))
+		return cliente.cargarTarjetas()
+	}
+	
+	return cargarTarjetasAlmacen()
+}
+
+// GuardarTarjetas guarda las tarjetas a través del daemon, si está activo, o en el almacén
+func GuardarTarjetas(tarjetas Tarjetas) error {
+	if escenarioActivo != nil {
+		return errDatosInvalidos("Un escenario solo simula; corre el comando sin 'escenario' para guardar cambios",
+			"A scenario is only a simulation; run the command without 'escenario' to save changes")
+	}
+	if cliente, ok := conectarDaemon(); ok {
+		defer cliente.Close()
+		registro.Debug("guardando tarjetas a través del daemon", "socket", rutaDatos(ARCHIVO_SOCKET))
+		return cliente.guardarTarjetas(tarjetas)
+	}
+	
+	return guardarTarjetasAlmacen(tarjetas)
+}
+
+// cargarTarjetasAlmacen carga las tarjetas directamente del almacén elegido con --almacen
+func cargarTarjetasAlmacen() (Tarjetas, error) {
+	var tarjetas Tarjetas
+	destino, err := almacenDatos()
+	if err != nil {
+		return tarjetas, err
+	}
+
+	registro.Debug("leyendo tarjetas", "almacen", destino.Ruta())
+	var formato *storage.ErrorFormato
+	var documento json.RawMessage
+	hay, err := destino.Cargar(&documento)
+	if errors.As(err, &formato) {
+		return tarjetas, errArchivoCorrupto(formato.Archivo, formato.Causa)
+	} else if err != nil {
+		return tarjetas, err
+	}
+	if !hay {
+		// Si todavía no hay datos, guarda la estructura vacía
+		registro.Info("creando almacén de tarjetas vacío", "almacen", destino.Ruta())
+		tarjetas = Tarjetas{
+			Debito:  []TarjetaDebito{},
+			Credito: []TarjetaCredito{},
+		}
+		
+		return tarjetas, guardarTarjetasAlmacen(tarjetas)
+	}
+	
+	// Los archivos de versiones anteriores se migran y se guardan ya en la versión actual;
+	// todos se validan contra el esquema
+	tarjetas, migracion, err := decodificarTarjetas(destino.Ruta(), documento)
+	if err != nil || migracion.Desde == VERSION_ESQUEMA {
+		return tarjetas, err
+	}
+	registro.Info("guardando datos migrados", "almacen", destino.Ruta(), "desde", migracion.Desde, "version", VERSION_ESQUEMA)
+	if err := guardarTarjetasAlmacen(tarjetas); err != nil {
+		return tarjetas, err
+	}
+	if len(migracion.Completados) > 0 {
+		fmt.Fprintf(os.Stderr, "AVISO: Tus datos pasaron de la versión %d a la %d del formato; estos campos no existían y quedaron en cero o vacíos, revísalos: %s\n",
+			migracion.Desde, VERSION_ESQUEMA, strings.Join(migracion.Completados, ", "))
+	}
+	return tarjetas, nil
+}
+
+// guardarTarjetasAlmacen guarda las tarjetas directamente en el almacén. Tanto el archivo
+// JSON como la base SQLite reemplazan los datos de una sola vez para no dejarlos a medias.
+func guardarTarjetasAlmacen(tarjetas Tarjetas) error {
+	destino, err := almacenDatos()
+	if err != nil {
+		return err
+	}
+	registro.Debug("escribiendo tarjetas", "almacen", destino.Ruta())
+	tarjetas.Version = VERSION_ESQUEMA
+	return destino.Guardar(tarjetas)
+}
+
+// CalcularRendimientoReal calcula el rendimiento real después de impuestos e inflación con
+// la inflación más reciente de Banxico cuando está disponible (ver InflacionVigente). El saldo
+// y el resultado son en pesos; las cuentas en dólares se convierten con TipoCambioVigente.
+func CalcularRendimientoReal(tarjeta TarjetaDebito, saldo float64) (float64, float64, float64) {
+	if enDolares(tarjeta) {
+		tipoCambio, _ := TipoCambioVigente()
+		return calc.RendimientoRealDivisa(tarjeta, saldo/tipoCambio, tipoCambio, VariacionCambiaria(), InflacionVigente(), ISRVigente())
+	}
+	return calc.RendimientoReal(tarjeta, saldo, InflacionVigente(), ISRVigente())
+}
+
+// Ejecutar corre la línea de comandos de finmex con los argumentos dados y termina el
+// proceso con el código de salida del error, si lo hay
+func Ejecutar(args []string) {
+	var privado *salidaPrivada
+	app := &cli.App{
+		Name:  "finmex",
+		Usage: "Calculadora financiera para productos financieros mexicanos",
+		Version: version,
+		EnableBashCompletion: true,
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{Name: "idioma", Value: "es", Usage: "Idioma de los mensajes de error: es o en", EnvVars: []string{"FINMEX_IDIOMA"}},
+			&cli.BoolFlag{Name: "privado", Usage: "Ocultar saldos, límites y demás montos en pantalla", EnvVars: []string{"FINMEX_PRIVADO"}},
+			flagOutput(),
+			flagAlmacen(),
+			flagPerfil(),
+			flagValorUDI(),
+		}, append(append(flagsISR(), flagsTipoCambio()...), flagsLog()...)...),
+		Before: func(c *cli.Context) error {
+			if err := ValidarIdioma(c.String("idioma")); err != nil {
+				return err
+			}
+			idiomaMensajes = c.String("idioma")
+			if err := aplicarConfiguracion(c); err != nil {
+				return err
+			}
+			// Primero la salida de datos, para que el modo privado cubra los mensajes que pasan a stderr
+			if err := activarOutput(c.String("output")); err != nil {
+				return err
+			}
+			if err := elegirAlmacen(c.String("almacen")); err != nil {
+				return err
+			}
+			if err := activarPerfil(c.String("perfil")); err != nil {
+				return err
+			}
+			if err := configurarISR(c.String("regimen-isr"), c.Int("anio-fiscal"), c.Float64("tasa-marginal")); err != nil {
+				return err
+			}
+			if err := configurarValorUDI(c.Float64("valor-udi")); err != nil {
+				return err
+			}
+			if err := configurarTipoCambio(c.Float64("tipo-cambio"), c.Float64("variacion-cambiaria")); err != nil {
+				return err
+			}
+			if c.Bool("privado") {
+				var err error
+				if privado, err = activarModoPrivado(); err != nil {
+					return err
+				}
+			}
+			return configurarLog(c.String("log-level"), c.String("log-file"))
+		},
+		After: func(c *cli.Context) error {
+			if privado != nil {
+				privado.cerrar()
+			}
+			cerrarAlmacen()
+			restaurarOutput()
+			cerrarLog()
+			return nil
+		},
+		Commands: []*cli.Command{
+			{
+				Name:  "debito",
+				Usage: "Operaciones con tarjetas de débito",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "agregar",
+						Usage: "Agregar una nueva tarjeta de débito",
+						Flags: flagsTarjetaDebito(),
+						Action: func(c *cli.Context) error {
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							var tarjeta TarjetaDebito
+							captura := nuevaCaptura(c)
+							
+							if tarjeta.Nombre, err = captura.Texto("nombre", "Nombre de la tarjeta: "); err != nil {
+								return err
+							}
+							
+							if tarjeta.Banco, err = captura.Texto("banco", "Banco emisor: "); err != nil {
+								return err
+							}
+							
+							if tarjeta.Moneda, err = captura.Moneda("moneda", "Moneda (MXN o USD)", ""); err != nil {
+								return err
+							}
+							
+							if tarjeta.Tramos, err = captura.Tramos("Tasa de rendimiento anual (decimal, ej: 0.05 para 5%; por tramos 25000:0.15,0.08)"); err != nil {
+								return err
+							}
+							
+							if tarjeta.SaldoMinimo, err = captura.NumeroOpcional("saldo-minimo", "Saldo mínimo requerido: ", limitesMonto); err != nil {
+								return err
+							}
+							
+							if tarjeta.ComisionAnual, err = captura.NumeroOpcional("comision-anual", "Comisión anual: ", limitesMonto); err != nil {
+								return err
+							}
+							
+							if tarjeta.ComisionInactividad, err = captura.NumeroOpcional("comision-inactividad", "Comisión por inactividad (mensual): ", limitesMonto); err != nil {
+								return err
+							}
+							
+							if tarjeta.Saldo, err = captura.NumeroOpcional("saldo", "Saldo actual (0 si no quieres registrarlo): ", limitesMonto); err != nil {
+								return err
+							}
+							
+							if tarjeta.Tags, err = captura.Lista("etiquetas", "Etiquetas separadas por comas (opcional): "); err != nil {
+								return err
+							}
+							
+							tarjetas.Debito = append(tarjetas.Debito, tarjeta)
+							
+							err = GuardarTarjetas(tarjetas)
+							if err != nil {
+								return fmt.Errorf("Error al guardar tarjeta: %w", err)
+							}
+							
+							fmt.Printf("Tarjeta de débito '%s' agregada exitosamente\n", tarjeta.Nombre)
+							return nil
+						},
+					},
+					{
+						Name:         "analizar",
+						Usage:        "Analizar rendimiento de una tarjeta de débito",
+						ArgsUsage:    "[nombre o número]",
+						BashComplete: completarTarjetas(nombresDebito),
+						Flags: []cli.Flag{
+							&cli.IntFlag{Name: "anios", Aliases: []string{"años"}, Usage: "Proyectar el saldo año por año durante N años"},
+							conLimites(&cli.Float64Flag{Name: "aportacion-mensual", Usage: "Depósito al final de cada mes en la proyección"}, limitesMonto),
+						},
+						Action: func(c *cli.Context) error {
+							años := c.Int("anios")
+							if años < 0 || años > 100 {
+								return errDatosInvalidos(fmt.Sprintf("--anios debe estar entre 1 y 100, no %d", años), fmt.Sprintf("--anios must be between 1 and 100, not %d", años))
+							}
+							if c.IsSet("aportacion-mensual") && años == 0 {
+								return errDatosInvalidos("--aportacion-mensual requiere --anios", "--aportacion-mensual requires --anios")
+							}
+							
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							if len(tarjetas.Debito) == 0 {
+								return fmt.Errorf("No hay tarjetas de débito registradas")
+							}
+							
+							seleccion := 0
+							if c.NArg() > 0 {
+								i, err := buscarTarjeta(nombresDebito(tarjetas), "debito", argumentoNombre(c.Args().Slice()))
+								if err != nil {
+									return err
+								}
+								seleccion = i + 1
+							} else {
+								fmt.Println("Tarjetas de débito disponibles:")
+								for i, t := range tarjetas.Debito {
+									fmt.Printf("%d. %s (%s)\n", i+1, t.Nombre, t.Banco)
+								}
+								
+								if seleccion, err = leerEntero("Selecciona una tarjeta (número): ", 1, len(tarjetas.Debito)); err != nil {
+									return err
+								}
+							}
+							
+							tarjeta := tarjetas.Debito[seleccion-1]
+							
+							var saldo float64
+							if saldo, err = leerNumero("Ingresa el saldo promedio a mantener (en pesos): ", limitesMonto); err != nil {
+								return err
+							}
+							
+							var proyeccion []AñoProyeccion
+							if años > 0 {
+								proyeccion = calc.ProyectarRendimiento(cuentaEnPesos(tarjeta), saldo, c.Float64("aportacion-mensual"), años, InflacionVigente(), ISRVigente())
+							}
+							
+							if salidaEstructurada() {
+								// En CSV la proyección es lo que se puede poner en renglones
+								if años > 0 && formatoDatos == FormatoCSV {
+									return emitirDatos(proyeccion)
+								}
+								analisis := analisisDebito(tarjeta, saldo)
+								analisis.Proyeccion = proyeccion
+								return emitirDatos(analisis)
+							}
+							
+							a := analisisDebito(tarjeta, saldo)
+							
+							fmt.Println("\n=== Análisis de Rendimiento ===")
+							fmt.Printf("Tarjeta: %s (%s)\n", tarjeta.Nombre, tarjeta.Banco)
+							tasa := a.TasaRendimiento
+							fmt.Printf("Tasa nominal: %s\n", DescribirTramos(tarjeta))
+							if tramosEscalonados(tarjeta.TramosOrdenados()) {
+								fmt.Printf("Tasa ponderada con tu saldo: %.2f%%\n", tasa*100)
+							}
+							fmt.Printf("Saldo inicial: $%.2f\n", saldo)
+							if enDolares(tarjeta) {
+								tipoCambio, origen := TipoCambioVigente()
+								fmt.Printf("Cuenta en dólares: %s a $%.4f por dólar (%s), variación esperada de %.1f%%\n",
+									formatoMoneda(MonedaUSD, saldoEnMoneda(tarjeta, saldo)), tipoCambio, origen, VariacionCambiaria()*100)
+							}
+							fmt.Printf("Rendimiento bruto anual: $%.2f\n", a.RendimientoBruto)
+							fmt.Printf("Impuestos (%s): $%.2f\n", ISRVigente().Descripcion(), a.Impuestos)
+							fmt.Printf("Pérdida por inflación (%.1f%%): $%.2f\n", a.Inflacion*100, a.PerdidaInflacion)
+							fmt.Printf("Comisión anual: $%.2f\n", a.ComisionAnual)
+							fmt.Printf("Rendimiento real anual: $%.2f (%.2f%%)\n", a.RendimientoReal, a.RendimientoRealPct)
+							if a.SaldoEquilibrio != nil {
+								moneda := MonedaCuenta(tarjeta)
+								fmt.Printf("Saldo de equilibrio: %s (a partir de ahí el rendimiento real es positivo)\n", formatoMoneda(moneda, *a.SaldoEquilibrio))
+								if falta := *a.SaldoEquilibrio - saldoEnMoneda(tarjeta, saldo); falta > 0 {
+									fmt.Printf("Te faltan %s de saldo para que la cuenta le gane a la inflación\n", formatoMoneda(moneda, falta))
+								}
+							} else {
+								fmt.Println("Saldo de equilibrio: ninguno, la tasa neta de ISR no supera la inflación")
+							}
+							
+							catalogo, err := CargarCatalogo()
+							if err != nil {
+								return err
+							}
+							// El mercado se compara con el saldo analizado, que cambia la tasa de los tramos
+							conSaldo := tarjeta
+							conSaldo.Saldo = saldoEnMoneda(tarjeta, saldo)
+							posicion := PosicionDebito(conSaldo, catalogo)
+							if posicion.Muestra > 0 {
+								fmt.Printf("Frente al mercado: tasa %.2f%% vs promedio %s de %.2f%% (mejor que el %.0f%% de las cuentas comparables)\n",
+									tasa*100, posicion.Segmento.Nombre(), posicion.Promedio*100, posicion.Percentil)
+							}
+							
+							if a.Gana {
+								fmt.Printf("RESULTADO: Tu dinero GANA valor real ($%.2f después de un año)\n", a.SaldoFinal)
+							} else {
+								fmt.Printf("RESULTADO: Tu dinero PIERDE valor real ($%.2f después de un año)\n", a.SaldoFinal)
+							}
+							imprimirRiesgoCambiario(tarjeta.Nombre, a.RiesgoCambiario)
+							
+							if años > 0 {
+								imprimirProyeccion(proyeccion, c.Float64("aportacion-mensual"))
+							}
+							
+							return nil
+						},
+					},
+					{
+						Name:  "listar",
+						Usage: "Listar tarjetas de débito registradas",
+						Action: func(c *cli.Context) error {
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							if len(tarjetas.Debito) == 0 && !salidaEstructurada() {
+								fmt.Println("No hay tarjetas de débito registradas")
+								return nil
+							}
+							
+							catalogo, err := CargarCatalogo()
+							if err != nil {
+								return err
+							}
+							
+							if salidaEstructurada() {
+								filas := []FilaDebito{}
+								for _, t := range tarjetas.Debito {
+									filas = append(filas, filaDebito(t, catalogo))
+								}
+								return emitirDatos(filas)
+							}
+							
+							w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+							fmt.Fprintln(w, "Nombre\tBanco\tRendimiento\tSaldo Mínimo\tComisión Anual\tSaldo\tSaldo de Equilibrio\tVs Mercado\tEtiquetas")
+							fmt.Fprintln(w, "------\t-----\t-----------\t------------\t--------------\t-----\t-------------------\t----------\t---------")
+							
+							for _, t := range tarjetas.Debito {
+								moneda := MonedaCuenta(t)
+								equilibrio := "Nunca"
+								if saldo, ok := calc.SaldoEquilibrio(t, InflacionVigente(), ISRVigente()); ok {
+									equilibrio = formatoMoneda(moneda, saldo)
+								}
+								fmt.Fprintf(w, "%s\t%s\t%.2f%%\t%s\t%s\t%s\t%s\t%s\t%s\n",
+									t.Nombre, t.Banco, t.TasaPonderada(t.Saldo)*100, 
+									formatoMoneda(moneda, t.SaldoMinimo), formatoMoneda(moneda, t.ComisionAnual), formatoMoneda(moneda, t.Saldo), equilibrio, PosicionDebito(t, catalogo).Descripcion(), strings.Join(t.Tags, ", "))
+							}
+							
+							w.Flush()
+							fmt.Printf("\nSaldo de equilibrio: saldo desde el cual la cuenta le gana a la inflación (%.1f%%) después de ISR y comisiones\n", InflacionVigente()*100)
+							return nil
+						},
+					},
+					comandoEditarDebito(),
+					comandoEliminarDebito(),
+				},
+			},
+			{
+				Name:  "credito",
+				Usage: "Operaciones con tarjetas de crédito",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "agregar",
+						Usage: "Agregar una nueva tarjeta de crédito",
+						Flags: flagsTarjetaCredito(),
+						Action: func(c *cli.Context) error {
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							var tarjeta TarjetaCredito
+							captura := nuevaCaptura(c)
+							
+							if tarjeta.Nombre, err = captura.Texto("nombre", "Nombre de la tarjeta: "); err != nil {
+								return err
+							}
+							
+							if tarjeta.Banco, err = captura.Texto("banco", "Banco emisor: "); err != nil {
+								return err
+							}
+							
+							if tarjeta.TasaInteres, err = captura.Numero("tasa", "Tasa de interés anual (decimal, ej: 0.36 para 36%): ", limitesTasa); err != nil {
+								return err
+							}
+							
+							if tarjeta.CAT, err = captura.Numero("cat", "CAT (decimal, ej: 0.45 para 45%): ", limitesTasa); err != nil {
+								return err
+							}
+							
+							if tarjeta.ComisionAnual, err = captura.NumeroOpcional("comision-anual", "Comisión anual: ", limitesMonto); err != nil {
+								return err
+							}
+							
+							if tarjeta.ComisionAnual > 0 {
+								if tarjeta.FacturacionParaCondonar, err = captura.NumeroOpcional("facturacion-condonar", "Facturación anual con la que te bonifican la anualidad (0 si no aplica): ", limitesMonto); err != nil {
+									return err
+								}
+							}
+							
+							if tarjeta.LimiteCredito, err = captura.Numero("limite", "Límite de crédito: ", limitesMonto); err != nil {
+								return err
+							}
+							
+							if tarjeta.BeneficiosCashback, err = captura.NumeroOpcional("cashback", "Porcentaje de cashback (decimal, ej: 0.02 para 2%): ", limitesTasa); err != nil {
+								return err
+							}
+							
+							if tarjeta.MesesSinIntereses, err = captura.SiNo("msi", "¿Ofrece meses sin intereses? (s/n): "); err != nil {
+								return err
+							}
+							
+							if tarjeta.Saldo, err = captura.NumeroOpcional("saldo", "Deuda actual (0 si no debes nada): ", limitesMonto); err != nil {
+								return err
+							}
+							
+							if tarjeta.Tags, err = captura.Lista("etiquetas", "Etiquetas separadas por comas (opcional): "); err != nil {
+								return err
+							}
+							
+							if err := aplicarDiasCiclo(c, &tarjeta); err != nil {
+								return err
+							}
+							
+							tarjetas.Credito = append(tarjetas.Credito, tarjeta)
+							
+							err = GuardarTarjetas(tarjetas)
+							if err != nil {
+								return fmt.Errorf("Error al guardar tarjeta: %w", err)
+							}
+							
+							fmt.Printf("Tarjeta de crédito '%s' agregada exitosamente\n", tarjeta.Nombre)
+							return nil
+						},
+					},
+					{
+						Name:         "analizar",
+						Usage:        "Analizar costo de una tarjeta de crédito",
+						BashComplete: completarFlagTarjeta("tarjeta", nombresCredito),
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "frecuencia", Value: "mensual", Usage: "Frecuencia de pago: mensual, quincenal, catorcenal o semanal"},
+							&cli.BoolFlag{Name: "calendario", Usage: "Mostrar el calendario completo de pagos"},
+							&cli.BoolFlag{Name: "tabla", Usage: "Mostrar la tabla de amortización periodo por periodo"},
+							&cli.StringFlag{Name: "tarjeta", Usage: "Nombre de la tarjeta a analizar"},
+							conLimites(&cli.Float64Flag{Name: "deuda", Usage: "Monto de la deuda/compra"}, limitesMonto),
+							conLimites(&cli.Float64Flag{Name: "pago", Usage: "Pago que planeas hacer en cada periodo"}, limitesMonto),
+							&cli.IntFlag{Name: "meses", Usage: "Plazo en meses; calcula el pago necesario para liquidar en ese tiempo"},
+							&cli.BoolFlag{Name: "udis", Usage: "Mostrar también lo que pagas en pesos constantes (UDIS)"},
+							conLimites(&cli.Float64Flag{Name: "gasto-mensual", Usage: "Gasto mensual con la tarjeta, para saber si te condonan la anualidad"}, limitesMonto),
+						},
+						Action: func(c *cli.Context) error {
+							frecuencia, err := calc.ParsearFrecuencia(c.String("frecuencia"))
+							if err != nil {
+								return err
+							}
+							
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							if len(tarjetas.Credito) == 0 {
+								return fmt.Errorf("No hay tarjetas de crédito registradas")
+							}
+							
+							var tarjeta TarjetaCredito
+							if nombre := c.String("tarjeta"); nombre != "" {
+								encontrada := false
+								for _, t := range tarjetas.Credito {
+									if normalizarClave(t.Nombre) == normalizarClave(nombre) {
+										tarjeta, encontrada = t, true
+										break
+									}
+								}
+								if !encontrada {
+									return errTarjetaNoEncontrada("credito", nombre)
+								}
+							} else {
+								fmt.Println("Tarjetas de crédito disponibles:")
+								for i, t := range tarjetas.Credito {
+									fmt.Printf("%d. %s (%s)\n", i+1, t.Nombre, t.Banco)
+								}
+								
+								seleccion, err := leerEntero("Selecciona una tarjeta (número): ", 1, len(tarjetas.Credito))
+								if err != nil {
+									return err
+								}
+								
+								tarjeta = tarjetas.Credito[seleccion-1]
+							}
+							
+							deuda := c.Float64("deuda")
+							if deuda <= 0 {
+								if deuda, err = leerNumero("Ingresa el monto de la deuda/compra: ", limitesMonto); err != nil {
+									return err
+								}
+							}
+							
+							pago := c.Float64("pago")
+							if meses := c.Int("meses"); meses > 0 {
+								var intereses float64
+								pago, intereses = calc.PagoRequerido(tarjeta, deuda, meses, frecuencia)
+								fmt.Printf("Para liquidar $%.2f en %d meses necesitas pagar $%.2f %s (intereses totales con IVA: $%.2f)\n",
+									deuda, meses, pago, frecuencia, intereses)
+							} else if pago <= 0 {
+								if pago, err = leerNumero(fmt.Sprintf("Ingresa el pago %s que planeas hacer: ", frecuencia), limitesMonto); err != nil {
+									return err
+								}
+							}
+							
+							pagoMinimo := calc.PagoMinimoPeriodo(tarjeta, deuda, frecuencia)
+							if pago < pagoMinimo {
+								if c.Int("meses") > 0 {
+									fmt.Printf("AVISO: Ese pago es menor al pago mínimo; pagando el mínimo ($%.2f) liquidas antes de %d meses\n", pagoMinimo, c.Int("meses"))
+								} else {
+									fmt.Printf("AVISO: El pago ingresado es menor al pago mínimo. Se ajustará a $%.2f\n", pagoMinimo)
+								}
+								pago = pagoMinimo
+							}
+							
+							// Con la facturación suficiente el banco bonifica la anualidad y no es parte del costo
+							anualidad := tarjeta.ComisionAnual
+							condonada := tarjeta.AnualidadCondonada(c.Float64("gasto-mensual"))
+							if condonada {
+								tarjeta.ComisionAnual = 0
+							}
+							
+							catalogo, err := CargarCatalogo()
+							if err != nil {
+								return err
+							}
+							
+							desglose := calc.CostoCreditoDesglosado(tarjeta, deuda, pago, frecuencia, nil)
+							costo, pagos := desglose.Total, desglose.Pagos
+							meses := float64(pagos) * 12 / float64(frecuencia.PeriodosPorAño())
+							calendario := calc.CalendarioPagos(time.Now(), frecuencia, pagos)
+							
+							if salidaEstructurada() {
+								analisis := analisisCredito(tarjeta, deuda, pago, frecuencia, calendario)
+								analisis.AnualidadCondonada = condonada
+								if c.Bool("calendario") {
+									for _, fecha := range calendario {
+										analisis.Calendario = append(analisis.Calendario, fecha.Format("2006-01-02"))
+									}
+								}
+								if c.Bool("udis") {
+									pagado := pagadoEnUDIS(tarjeta, deuda, pago, frecuencia)
+									analisis.EnUDIS = &pagado
+								}
+								if c.Bool("tabla") {
+									renglones := renglonesTablaCredito(calc.TablaAmortizacionCredito(tarjeta, deuda, pago, frecuencia), calendario)
+									// En CSV la tabla de amortización es lo que se puede poner en renglones
+									if formatoDatos == FormatoCSV {
+										return emitirDatos(renglones)
+									}
+									analisis.Amortizacion = renglones
+								}
+								return emitirDatos(analisis)
+							}
+							
+							fmt.Println("\n=== Análisis de Crédito ===")
+							fmt.Printf("Tarjeta: %s (%s)\n", tarjeta.Nombre, tarjeta.Banco)
+							fmt.Printf("Deuda/Compra: $%.2f\n", deuda)
+							fmt.Printf("Tasa de interés anual: %.2f%% (efectiva %.2f%% con capitalización mensual)\n",
+								tarjeta.TasaInteres*100, calc.EfectivaDesdeNominal(tarjeta.TasaInteres, 12)*100)
+							fmt.Printf("CAT: %.2f%%\n", tarjeta.CAT*100)
+							if posicion := PosicionCredito(tarjeta, catalogo); posicion.Muestra > 0 {
+								fmt.Printf("Frente al mercado: CAT promedio %s de %.2f%% (más barata que el %.0f%% de las tarjetas comparables)\n",
+									posicion.Segmento.Nombre(), posicion.Promedio*100, posicion.Percentil)
+							}
+							fmt.Printf("Pago %s: $%.2f\n", frecuencia, pago)
+							if frecuencia == FrecuenciaMensual {
+								fmt.Printf("Tiempo para liquidar: %d meses (%.1f años)\n", pagos, meses/12)
+							} else {
+								fmt.Printf("Tiempo para liquidar: %d pagos %ses (%.1f meses, %.1f años)\n", pagos, frecuencia, meses, meses/12)
+							}
+							if len(calendario) > 0 {
+								fmt.Printf("Primer pago: %s | Último pago: %s\n",
+									calendario[0].Format("2006-01-02"), calendario[len(calendario)-1].Format("2006-01-02"))
+							}
+							
+							fmt.Printf("Costo total del crédito: $%.2f (%.2f%% del monto original)\n", costo, desglose.Porcentaje)
+							fmt.Printf("  Intereses: $%.2f + IVA $%.2f\n", desglose.Intereses, desglose.IVAIntereses)
+							if desglose.Comisiones > 0 {
+								fmt.Printf("  Anualidad: $%.2f + IVA $%.2f\n", desglose.Comisiones, desglose.IVAComisiones)
+							}
+							fmt.Printf("Monto total pagado: $%.2f\n", deuda+costo)
+							if c.Bool("udis") {
+								pagado := pagadoEnUDIS(tarjeta, deuda, pago, frecuencia)
+								imprimirEnUDIS("Monto total pagado", pagado)
+								fmt.Printf("Costo real del crédito: $%.2f\n", pagado.PesosConstantes-deuda)
+							}
+							if condonada {
+								fmt.Printf("Anualidad: condonada, con $%.2f al mes facturas $%.2f al año (la meta es $%.2f)\n",
+									c.Float64("gasto-mensual"), c.Float64("gasto-mensual")*12, tarjeta.FacturacionParaCondonar)
+							} else if tarjeta.FacturacionParaCondonar > 0 && anualidad > 0 {
+								fmt.Printf("AVISO: Facturando $%.2f al año ($%.2f al mes) te condonan la anualidad de $%.2f",
+									tarjeta.FacturacionParaCondonar, tarjeta.FacturacionParaCondonar/12, anualidad)
+								if gasto := c.Float64("gasto-mensual"); gasto > 0 {
+									fmt.Printf("; con tu gasto te faltan $%.2f al mes\n", tarjeta.FacturacionParaCondonar/12-gasto)
+								} else {
+									fmt.Println("; indica tu gasto con --gasto-mensual")
+								}
+							}
+							if tarjeta.BeneficiosCashback > 0 || len(tarjeta.Categorias) > 0 {
+								fmt.Println("El cashback depende de tu gasto, no de la deuda; estímalo con 'finmex credito cashback'")
+							}
+							
+							if c.Bool("calendario") {
+								fmt.Println("\n=== Calendario de Pagos ===")
+								for n, fecha := range calendario {
+									fmt.Printf("%3d. %s\n", n+1, fecha.Format("2006-01-02"))
+								}
+							}
+							
+							if c.Bool("tabla") {
+								imprimirTablaAmortizacion(calc.TablaAmortizacionCredito(tarjeta, deuda, pago, frecuencia), calendario)
+							}
+							
+							return nil
+						},
+					},
+					{
+						Name:  "listar",
+						Usage: "Listar tarjetas de crédito registradas",
+						Action: func(c *cli.Context) error {
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							if len(tarjetas.Credito) == 0 && !salidaEstructurada() {
+								fmt.Println("No hay tarjetas de crédito registradas")
+								return nil
+							}
+							
+							catalogo, err := CargarCatalogo()
+							if err != nil {
+								return err
+							}
+							
+							if salidaEstructurada() {
+								filas := []FilaCredito{}
+								for _, t := range tarjetas.Credito {
+									filas = append(filas, FilaCredito{TarjetaCredito: t, VsMercado: PosicionCredito(t, catalogo).Descripcion()})
+								}
+								return emitirDatos(filas)
+							}
+							
+							w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+							fmt.Fprintln(w, "Nombre\tBanco\tInterés\tCAT\tComisión Anual\tLímite\tDeuda\tCashback\tMSI\tVs Mercado\tEtiquetas")
+							fmt.Fprintln(w, "------\t-----\t-------\t---\t--------------\t------\t-----\t--------\t---\t----------\t---------")
+							
+							for _, t := range tarjetas.Credito {
+								msi := "No"
+								if t.MesesSinIntereses {
+									msi = "Sí"
+								}
+								
+								fmt.Fprintf(w, "%s\t%s\t%.2f%%\t%.2f%%\t$%.2f\t$%.2f\t$%.2f\t%.2f%%\t%s\t%s\t%s\n",
+									t.Nombre, t.Banco, t.TasaInteres*100, t.CAT*100,
+									t.ComisionAnual, t.LimiteCredito, t.Saldo, t.BeneficiosCashback*100, msi, PosicionCredito(t, catalogo).Descripcion(), strings.Join(t.Tags, ", "))
+							}
+							
+							w.Flush()
+							return nil
+						},
+					},
+					comandoCreditoPlanes(),
+					comandoCreditoMSI(),
+					comandoCreditoCashback(),
+					comandoCreditoAplicarPago(),
+					comandoCreditoInferirTasa(),
+					comandoCreditoCAT(),
+					comandoCreditoCancelar(),
+					comandoCreditoCiclo(),
+					comandoCreditoTransferencia(),
+					comandoCreditoMinimo(),
+					comandoEditarCredito(),
+					comandoEliminarCredito(),
+				},
+			},
+			{
+				Name:  "comparar",
+				Usage: "Comparar tarjetas registradas",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "debito",
+						Usage: "Comparar tarjetas de débito",
+						Flags: append([]cli.Flag{
+							conLimites(&cli.Float64Flag{Name: "saldo", Usage: "Saldo promedio a mantener para la comparación"}, limitesMonto),
+							flagSalida(),
+						}, flagsFiltroTarjetas()...),
+						Action: func(c *cli.Context) error {
+							ndjson, err := salidaNDJSON(c)
+							if err != nil {
+								return err
+							}
+							
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							filtro := filtroTarjetas(c)
+							var cuentas, todas []TarjetaDebito
+							todas = append(todas, tarjetas.Debito...)
+							for _, caja := range tarjetas.Cajas {
+								todas = append(todas, caja.ComoDebito())
+							}
+							var nombres []string
+							for _, t := range todas {
+								nombres = append(nombres, t.Nombre)
+								if filtro.Incluye(t.Nombre, t.Tags) {
+									cuentas = append(cuentas, t)
+								}
+							}
+							if err := filtro.Verificar(nombres); err != nil {
+								return err
+							}
+							
+							if len(cuentas) < 2 {
+								return fmt.Errorf("Se necesitan al menos 2 tarjetas de débito o cajas de ahorro para comparar")
+							}
+							
+							saldo, err := valorRequeridoNDJSON(c, ndjson, "saldo", "Ingresa el saldo promedio a mantener para la comparación: ", limitesMonto)
+							if err != nil {
+								return err
+							}
+							
+							if ndjson || salidaEstructurada() {
+								emisor := nuevoEmisor(ndjson)
+								for _, t := range cuentas {
+									if err := emisor.Emitir(resultadoComparacionDebito(t, saldo)); err != nil {
+										return err
+									}
+								}
+								return emisor.Cerrar()
+							}
+							
+							fmt.Println("\n=== Comparación de Tarjetas de Débito ===")
+							fmt.Printf("Saldo a comparar: $%.2f\n", saldo)
+							imprimirTipoCambio(cuentas)
+							fmt.Println()
+							
+							w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+							fmt.Fprintln(w, "Nombre\tBanco\tMoneda\tRend. Nominal\tRend. Real\tSaldo Final\tResultado")
+							fmt.Fprintln(w, "------\t-----\t------\t------------\t---------\t-----------\t--------")
+							
+							var resultados []ResultadoComparacionDebito
+							for _, t := range cuentas {
+								r := resultadoComparacionDebito(t, saldo)
+								resultados = append(resultados, r)
+								
+								resultado := "PIERDE"
+								if r.Gana {
+									resultado = "GANA"
+								}
+								
+								fmt.Fprintf(w, "%s\t%s\t%s\t%.2f%%\t%.2f%%\t$%.2f\t%s\n",
+									r.Nombre, r.Banco, r.Moneda, r.TasaRendimiento*100, r.RendimientoRealPct,
+									r.SaldoFinal, resultado)
+							}
+							
+							w.Flush()
+							for _, r := range resultados {
+								imprimirRiesgoCambiario(r.Nombre, r.RiesgoCambiario)
+							}
+							return nil
+						},
+					},
+					{
+						Name:  "credito",
+						Usage: "Comparar tarjetas de crédito",
+						ArgsUsage: "[tarjeta1 tarjeta2]",
+						Flags: append([]cli.Flag{
+							&cli.StringFlag{Name: "frecuencia", Value: "mensual", Usage: "Frecuencia de pago: mensual, quincenal, catorcenal o semanal"},
+							&cli.BoolFlag{Name: "detalle", Usage: "Comparar dos tarjetas cara a cara con escenarios de deuda a 6, 12 y 24 meses"},
+							conLimites(&cli.Float64Flag{Name: "deuda", Usage: "Monto de la deuda/compra a comparar"}, limitesMonto),
+							conLimites(&cli.Float64Flag{Name: "pago", Usage: "Pago que planeas hacer en cada periodo"}, limitesMonto),
+							&cli.StringFlag{Name: "pagos", Usage: "Barrido de pagos desde:hasta:paso (p. ej. 500:5000:250) en lugar de un solo pago"},
+							conLimites(&cli.Float64Flag{Name: "gasto-mensual", Usage: "Gasto mensual para valuar el cashback en --detalle"}, limitesMonto),
+							flagSalida(),
+						}, flagsFiltroTarjetas()...),
+						Action: func(c *cli.Context) error {
+							if c.Bool("detalle") {
+								return compararCreditoDetalle(c)
+							}
+							
+							frecuencia, err := calc.ParsearFrecuencia(c.String("frecuencia"))
+							if err != nil {
+								return err
+							}
+							ndjson, err := salidaNDJSON(c)
+							if err != nil {
+								return err
+							}
+							
+							tarjetas, err := CargarTarjetas()
+							if err != nil {
+								return fmt.Errorf("Error al cargar tarjetas: %w", err)
+							}
+							
+							filtro := filtroTarjetas(c)
+							var seleccion []TarjetaCredito
+							var nombres []string
+							for _, t := range tarjetas.Credito {
+								nombres = append(nombres, t.Nombre)
+								if filtro.Incluye(t.Nombre, t.Tags) {
+									seleccion = append(seleccion, t)
+								}
+							}
+							if err := filtro.Verificar(nombres); err != nil {
+								return err
+							}
+							
+							if len(seleccion) < 2 {
+								return fmt.Errorf("Se necesitan al menos 2 tarjetas de crédito para comparar")
+							}
+							
+							deuda, err := valorRequeridoNDJSON(c, ndjson, "deuda", "Ingresa el monto de la deuda/compra para la comparación: ", limitesMonto)
+							if err != nil {
+								return err
+							}
+							
+							var pagos []float64
+							if c.String("pagos") != "" {
+								if pagos, err = ParsearRango(c.String("pagos")); err != nil {
+									return err
+								}
+							} else {
+								pago, err := valorRequeridoNDJSON(c, ndjson, "pago", fmt.Sprintf("Ingresa el pago %s que planeas hacer: ", frecuencia), limitesMonto)
+								if err != nil {
+									return err
+								}
+								pagos = []float64{pago}
+							}
+							
+							// En NDJSON cada resultado se escribe en cuanto se calcula; con --output al final
+							if ndjson || salidaEstructurada() {
+								emisor := nuevoEmisor(ndjson)
+								for _, pago := range pagos {
+									for _, t := range seleccion {
+										if err := emisor.Emitir(resultadoComparacionCredito(t, deuda, pago, frecuencia)); err != nil {
+											return err
+										}
+									}
+								}
+								return emisor.Cerrar()
+							}
+							
+							fmt.Println("\n=== Comparación de Tarjetas de Crédito ===")
+							fmt.Printf("Deuda a comparar: $%.2f\n", deuda)
+							if len(pagos) == 1 {
+								fmt.Printf("Pago %s: $%.2f\n\n", frecuencia, pagos[0])
+							} else {
+								fmt.Printf("Pago %s: de $%.2f a $%.2f\n\n", frecuencia, pagos[0], pagos[len(pagos)-1])
+							}
+							
+							columnaPlazo := "Meses"
+							if frecuencia != FrecuenciaMensual {
+								columnaPlazo = "Pagos"
+							}
+							
+							w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+							if len(pagos) > 1 {
+								fmt.Fprint(w, "Pago\t")
+							}
+							fmt.Fprintf(w, "Nombre\tBanco\tCAT\tCosto Total\t%s\tCashback\tMSI\n", columnaPlazo)
+							if len(pagos) > 1 {
+								fmt.Fprint(w, "----\t")
+							}
+							fmt.Fprintln(w, "------\t-----\t---\t-----------\t-----\t--------\t---")
+							
+							for _, pago := range pagos {
+								for _, t := range seleccion {
+									costo, meses, _ := calc.CostoCreditoFrecuencia(t, deuda, pago, frecuencia)
+									
+									msi := "No"
+									if t.MesesSinIntereses {
+										msi = "Sí"
+									}
+									
+									if len(pagos) > 1 {
+										fmt.Fprintf(w, "$%.2f\t", pago)
+									}
+									fmt.Fprintf(w, "%s\t%s\t%.2f%%\t$%.2f\t%d\t%.2f%%\t%s\n",
+										t.Nombre, t.Banco, t.CAT*100, costo, meses,
+										t.BeneficiosCashback*100, msi)
+								}
+							}
+							
+							w.Flush()
+							return nil
+						},
+					},
+					comandoCompararMejor(),
+				},
+			},
+			comandoMovimientos(),
+			comandoListar(),
+			comandoBuscar(),
+			comandoDaemon(),
+			comandoServe(),
+			comandoNomina(),
+			comandoMicrocredito(),
+			comandoBNPL(),
+			comandoCalendario(),
+			comandoRecordatorios(),
+			comandoMonedero(),
+			comandoResumen(),
+			comandoReporte(),
+			comandoEscenario(),
+			comandoConfig(),
+			comandoCompletion(),
+			comandoCifrar(),
+			comandoDescifrar(),
+			comandoVales(),
+			comandoFondoAhorro(),
+			comandoCaja(),
+			comandoPrestamo(),
+			comandoInformal(),
+			comandoAuto(),
+			comandoHipoteca(),
+			comandoProyecto(),
+			comandoSeguro(),
+			comandoPPR(),
+			comandoAfore(),
+			comandoRetiro(),
+			comandoSaldo(),
+			comandoRecomendar(),
+			comandoCatalogo(),
+			comandoPagar(),
+			comandoMetas(),
+			comandoInsights(),
+			comandoDeuda(),
+			comandoEtiquetar(),
+			comandoTasa(),
+			comandoCalcular(),
+			comandoSimular(),
+			comandoBono(),
+			comandoScripts(),
+			comandoValidar(),
+			comandoDoctor(),
+			comandoEsquema(),
+			comandoExportar(),
+			comandoImportar(),
+			comandoActualizar(),
+			comandoBanxico(),
+			comandoCetes(),
+			comandoConvertir(),
+			comandoSofipo(),
+			comandoPagare(),
+			comandoPerfil(),
+			comandoPresupuesto(),
+			comandoTUI(),
+		},
+	}
+
+	err := app.Run(args)
+	if err != nil {
+		registro.Debug("comando terminado con error", "error", err)
+		mensaje, codigo := mensajeError(err)
+		fmt.Println(mensaje)
+		os.Exit(codigo)
+	}
+}
+
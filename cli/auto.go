@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"math"
+	"sort"
+
+	"finmex/calc"
+)
+
+// Límites de deducción de automóviles para quien factura (LISR art. 34 y 36)
+const (
+	TOPE_DEDUCCION_AUTO     = 175000 // Monto máximo deducible de la inversión en un auto
+	TASA_DEDUCCION_AUTO     = 0.25   // Porcentaje anual de deducción de la inversión
+	TOPE_RENTA_DIARIA_AUTO  = 200    // Renta diaria deducible en arrendamiento puro
+	DIAS_MES_DEDUCCION_AUTO = 30.4
+)
+
+// DEPRECIACION_ANUAL_AUTO estima cuánto valor pierde un auto cada año cuando no se conoce su
+// valor al final del plazo
+const DEPRECIACION_ANUAL_AUTO = 0.15
+
+// CotizacionAuto reúne los datos para comparar contado, crédito automotriz y arrendamiento puro;
+// auto simular agrega el crédito de agencia y el arrendamiento financiero
+type CotizacionAuto struct {
+	Precio            float64
+	ValorMercadoFinal float64 // Lo que valdría el auto al terminar el plazo
+	Meses             int
+	SeguroAnual       float64
+
+	Enganche         float64 // Porcentaje del precio (decimal)
+	TasaCredito      float64 // Tasa anual del crédito automotriz
+	ComisionApertura float64 // Porcentaje del monto financiado (decimal)
+
+	RentaMensual  float64
+	Deposito      float64 // Depósito en garantía que se devuelve al final
+	ValorResidual float64 // Opción de compra al terminar el arrendamiento
+	SeguroEnRenta bool    // El seguro viene incluido en la renta
+
+	Factura      bool    // El usuario deduce el auto en su declaración
+	TasaMarginal float64 // Tasa de ISR con la que se valúan las deducciones
+
+	// Crédito de agencia: enganche alto a cambio de una tasa promocional, a veces con el seguro
+	// de la aseguradora de la agencia. Solo lo usa SimularAuto.
+	EngancheAgencia float64 // Porcentaje del precio (decimal)
+	TasaAgencia     float64
+	ComisionAgencia float64 // Porcentaje del monto financiado (decimal)
+	MesesAgencia    int     // Plazo del crédito, a lo más Meses; cero para usar Meses
+	SeguroAgencia   float64 // Seguro anual que exige la agencia; cero para usar SeguroAnual
+
+	// Arrendamiento financiero: rentas que amortizan el precio menos una opción de compra final
+	// con la que el auto pasa a ser tuyo. Solo lo usa SimularAuto.
+	TasaFinanciero        float64
+	PagoInicialFinanciero float64 // Porcentaje del precio (decimal)
+	ResidualFinanciero    float64 // Opción de compra como porcentaje del precio (decimal)
+
+	ComisionArrendamiento float64 // Porcentaje del precio que cobra la arrendadora al firmar
+
+	// Opciones que compara SimularAuto además del crédito bancario
+	ConAgencia       bool
+	ConArrendamiento bool
+	ConFinanciero    bool
+}
+
+// CostoOpcionAuto resume el costo real, en valor presente, de una forma de adquirir el auto
+type CostoOpcionAuto struct {
+	Opcion       string  `json:"opcion"`
+	Desembolso   float64 `json:"desembolso"`    // Suma de todo lo pagado, sin descontar
+	ValorFinal   float64 `json:"valor_final"`   // Valor del auto al final menos lo que falte pagar para quedárselo
+	AhorroFiscal float64 `json:"ahorro_fiscal"` // ISR que se deja de pagar por las deducciones, sin descontar
+	CostoReal    float64 `json:"costo_real"`    // Valor presente de pagos menos ahorro fiscal y valor final
+}
+
+// CompararAuto calcula el costo real de cada opción con flujos mensuales descontados a la
+// tasa de oportunidad anual, ordenados como contado, crédito y arrendamiento
+func CompararAuto(c CotizacionAuto, tasaOportunidad float64) []CostoOpcionAuto {
+	return []CostoOpcionAuto{
+		costoAutoContado(c, tasaOportunidad),
+		costoAutoCredito(c, tasaOportunidad),
+		costoAutoArrendamiento(c, tasaOportunidad),
+	}
+}
+
+// flujosAuto acumula pagos por mes de una opción y calcula su costo real al final
+type flujosAuto struct {
+	pagos  []float64
+	ahorro []float64
+}
+
+func nuevosFlujosAuto(meses int) *flujosAuto {
+	return &flujosAuto{pagos: make([]float64, meses+1), ahorro: make([]float64, meses+1)}
+}
+
+func (f *flujosAuto) costo(opcion string, valorFinal, tasaOportunidad float64) CostoOpcionAuto {
+	tasa := tasaOportunidad / 12
+	meses := len(f.pagos) - 1
+	o := CostoOpcionAuto{Opcion: opcion, ValorFinal: valorFinal}
+	for i := range f.pagos {
+		o.Desembolso += f.pagos[i]
+		o.AhorroFiscal += f.ahorro[i]
+	}
+	o.CostoReal = calc.VPN(tasa, f.pagos) - calc.VPN(tasa, f.ahorro) - valorFinal/math.Pow(1+tasa, float64(meses))
+	return o
+}
+
+// seguros agrega el seguro anual al inicio de cada año del plazo
+func (f *flujosAuto) seguros(seguroAnual float64) {
+	for mes := 0; mes < len(f.pagos)-1; mes += 12 {
+		f.pagos[mes] += seguroAnual
+	}
+}
+
+// deduccionInversion agrega el ahorro fiscal de deducir la compra del auto. La deducción es
+// anual sobre el precio topado y dura a lo más cuatro años; se reparte por mes.
+func (f *flujosAuto) deduccionInversion(c CotizacionAuto) {
+	if !c.Factura {
+		return
+	}
+	mensual := math.Min(c.Precio, TOPE_DEDUCCION_AUTO) * TASA_DEDUCCION_AUTO / 12 * c.TasaMarginal
+	for mes := 1; mes < len(f.ahorro) && mes <= 48; mes++ {
+		f.ahorro[mes] += mensual
+	}
+}
+
+func costoAutoContado(c CotizacionAuto, tasaOportunidad float64) CostoOpcionAuto {
+	f := nuevosFlujosAuto(c.Meses)
+	f.pagos[0] = c.Precio
+	f.seguros(c.SeguroAnual)
+	f.deduccionInversion(c)
+	return f.costo("Contado", c.ValorMercadoFinal, tasaOportunidad)
+}
+
+func costoAutoCredito(c CotizacionAuto, tasaOportunidad float64) CostoOpcionAuto {
+	return costoCreditoAuto("Crédito automotriz", c, c.Enganche, c.TasaCredito, c.ComisionApertura, c.Meses, c.SeguroAnual, tasaOportunidad)
+}
+
+// costoCreditoAuto calcula un crédito con enganche y pago fijo a los meses indicados, que no
+// pueden pasar del horizonte de la cotización
+func costoCreditoAuto(opcion string, c CotizacionAuto, engancheFraccion, tasaAnual, comision float64, meses int, seguroAnual, tasaOportunidad float64) CostoOpcionAuto {
+	f := nuevosFlujosAuto(c.Meses)
+	enganche := c.Precio * engancheFraccion
+	financiado := c.Precio - enganche
+	f.pagos[0] = enganche + financiado*comision
+
+	tasa := tasaAnual / 12
+	pago := calc.PagoFijo(financiado, tasa, meses)
+	saldo := financiado
+	proporcion := 1.0
+	if c.Precio > TOPE_DEDUCCION_AUTO {
+		proporcion = TOPE_DEDUCCION_AUTO / c.Precio
+	}
+	for mes := 1; mes <= meses; mes++ {
+		interes := saldo * tasa
+		saldo -= pago - interes
+		f.pagos[mes] += pago
+		if c.Factura {
+			// Los intereses se deducen en la proporción del precio que es deducible
+			f.ahorro[mes] += interes * proporcion * c.TasaMarginal
+		}
+	}
+
+	f.seguros(seguroAnual)
+	f.deduccionInversion(c)
+	return f.costo(opcion, c.ValorMercadoFinal, tasaOportunidad)
+}
+
+func costoAutoArrendamiento(c CotizacionAuto, tasaOportunidad float64) CostoOpcionAuto {
+	f := nuevosFlujosAuto(c.Meses)
+	f.pagos[0] = c.Deposito + c.Precio*c.ComisionArrendamiento
+	if !c.SeguroEnRenta {
+		f.seguros(c.SeguroAnual)
+	}
+
+	deducible := math.Min(c.RentaMensual, TOPE_RENTA_DIARIA_AUTO*DIAS_MES_DEDUCCION_AUTO)
+	for mes := 1; mes <= c.Meses; mes++ {
+		f.pagos[mes] += c.RentaMensual
+		if c.Factura {
+			f.ahorro[mes] += deducible * c.TasaMarginal
+		}
+	}
+	// El depósito se devuelve al final
+	f.pagos[c.Meses] -= c.Deposito
+
+	// Solo conviene ejercer la opción de compra si el auto vale más que el valor residual
+	valorFinal := math.Max(0, c.ValorMercadoFinal-c.ValorResidual)
+	return f.costo("Arrendamiento puro", valorFinal, tasaOportunidad)
+}
+
+// SimularAuto compara el crédito bancario con el crédito de agencia, el arrendamiento puro y
+// el arrendamiento financiero que estén activos en la cotización, ordenados de menor a mayor
+// costo real
+func SimularAuto(c CotizacionAuto, tasaOportunidad float64) []CostoOpcionAuto {
+	opciones := []CostoOpcionAuto{costoAutoCredito(c, tasaOportunidad)}
+	opciones[0].Opcion = "Crédito bancario"
+	if c.ConAgencia {
+		meses, seguro := c.MesesAgencia, c.SeguroAgencia
+		if meses == 0 {
+			meses = c.Meses
+		}
+		if seguro == 0 {
+			seguro = c.SeguroAnual
+		}
+		opciones = append(opciones, costoCreditoAuto("Crédito de agencia", c, c.EngancheAgencia, c.TasaAgencia, c.ComisionAgencia, meses, seguro, tasaOportunidad))
+	}
+	if c.ConArrendamiento {
+		opciones = append(opciones, costoAutoArrendamiento(c, tasaOportunidad))
+	}
+	if c.ConFinanciero {
+		opciones = append(opciones, costoAutoArrendamientoFinanciero(c, tasaOportunidad))
+	}
+	sort.SliceStable(opciones, func(i, j int) bool { return opciones[i].CostoReal < opciones[j].CostoReal })
+	return opciones
+}
+
+// costoAutoArrendamientoFinanciero calcula las rentas que amortizan el precio menos el pago
+// inicial, dejando la opción de compra como último pago. Fiscalmente se trata como una compra
+// a crédito: se deducen la inversión y los intereses.
+func costoAutoArrendamientoFinanciero(c CotizacionAuto, tasaOportunidad float64) CostoOpcionAuto {
+	f := nuevosFlujosAuto(c.Meses)
+	inicial := c.Precio * c.PagoInicialFinanciero
+	financiado := c.Precio - inicial
+	residual := c.Precio * c.ResidualFinanciero
+	f.pagos[0] = inicial + c.Precio*c.ComisionArrendamiento
+
+	tasa := c.TasaFinanciero / 12
+	// La renta amortiza el valor presente de lo financiado menos la opción de compra
+	renta := calc.PagoFijo(financiado-residual/math.Pow(1+tasa, float64(c.Meses)), tasa, c.Meses)
+	saldo := financiado
+	proporcion := 1.0
+	if c.Precio > TOPE_DEDUCCION_AUTO {
+		proporcion = TOPE_DEDUCCION_AUTO / c.Precio
+	}
+	for mes := 1; mes <= c.Meses; mes++ {
+		interes := saldo * tasa
+		saldo -= renta - interes
+		f.pagos[mes] += renta
+		if c.Factura {
+			f.ahorro[mes] += interes * proporcion * c.TasaMarginal
+		}
+	}
+	f.pagos[c.Meses] += residual
+
+	f.seguros(c.SeguroAnual)
+	f.deduccionInversion(c)
+	return f.costo("Arrendamiento financiero", c.ValorMercadoFinal, tasaOportunidad)
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"finmex/api"
+	"finmex/calc"
+)
+
+// CompraBNPL representa una compra a pagos con "compra ahora, paga después" (Aplazo, Kueski Pay)
+type CompraBNPL api.CompraBNPL
+
+// FilaBNPL es una compra a pagos en la salida de bnpl listar con --output
+type FilaBNPL struct {
+	CompraBNPL
+	Pago         float64 `json:"pago"`
+	CostoTotal   float64 `json:"costo_total"`
+	TasaEfectiva float64 `json:"tasa_efectiva"`
+	UltimoPago   string  `json:"ultimo_pago,omitempty"`
+}
+
+// filaBNPL agrega a la compra los datos calculados que muestra la tabla
+func filaBNPL(b CompraBNPL) (FilaBNPL, error) {
+	fechas, err := b.FechasPago()
+	if err != nil {
+		return FilaBNPL{}, err
+	}
+	fila := FilaBNPL{CompraBNPL: b, Pago: b.Pago(), CostoTotal: b.CostoTotal(), TasaEfectiva: b.TasaEfectiva()}
+	fila.Frecuencia = b.frecuencia()
+	if len(fechas) > 0 {
+		fila.UltimoPago = fechas[len(fechas)-1].Format("2006-01-02")
+	}
+	return fila, nil
+}
+
+// Pago regresa el monto de cada pago de la compra, incluyendo la comisión repartida
+func (b CompraBNPL) Pago() float64 {
+	if b.Pagos <= 0 {
+		return 0
+	}
+
+	tasa := b.TasaInteres / float64(b.frecuencia().PeriodosPorAño())
+	return calc.PagoFijo(b.Monto, tasa, b.Pagos) + b.Comision/float64(b.Pagos)
+}
+
+// CostoTotal regresa lo que se paga por encima del precio de contado
+func (b CompraBNPL) CostoTotal() float64 {
+	return b.Pago()*float64(b.Pagos) - b.Monto
+}
+
+// FechasPago regresa las fechas de todos los pagos de la compra
+func (b CompraBNPL) FechasPago() ([]time.Time, error) {
+	compra, err := time.Parse("2006-01-02", b.FechaCompra)
+	if err != nil {
+		return nil, fmt.Errorf("Fecha de compra inválida en '%s': %v", b.Nombre, err)
+	}
+
+	if !b.PrimerPagoHoy {
+		return calc.CalendarioPagos(compra, b.frecuencia(), b.Pagos), nil
+	}
+
+	fechas := []time.Time{compra}
+	return append(fechas, calc.CalendarioPagos(compra, b.frecuencia(), b.Pagos-1)...), nil
+}
+
+// TasaEfectiva calcula la tasa anual efectiva de la compra a partir de sus flujos
+func (b CompraBNPL) TasaEfectiva() float64 {
+	flujos := b.flujos()
+	if b.CostoTotal() <= 0.005 {
+		return 0
+	}
+	tasa, err := calc.TIR(flujos)
+	if err != nil {
+		return 0
+	}
+	return calc.TasaAnualEfectiva(tasa, float64(b.frecuencia().PeriodosPorAño()))
+}
+
+// ValorPresente descuenta los pagos a la tasa de oportunidad anual (lo que rendiría el
+// dinero mientras no se paga). Un valor menor al precio de contado significa que diferir conviene.
+func (b CompraBNPL) ValorPresente(tasaOportunidad float64) float64 {
+	tasa := tasaOportunidad / float64(b.frecuencia().PeriodosPorAño())
+	flujos := b.flujos()
+	flujos[0] = 0
+	if b.PrimerPagoHoy {
+		flujos[0] = -b.Pago()
+	}
+	return -calc.VPN(tasa, flujos)
+}
+
+// flujos regresa el dinero recibido y pagado por periodo (periodo 0 = día de compra)
+func (b CompraBNPL) flujos() []float64 {
+	pago := b.Pago()
+	if b.PrimerPagoHoy {
+		flujos := []float64{b.Monto - pago}
+		for i := 1; i < b.Pagos; i++ {
+			flujos = append(flujos, -pago)
+		}
+		return flujos
+	}
+
+	flujos := []float64{b.Monto}
+	for i := 0; i < b.Pagos; i++ {
+		flujos = append(flujos, -pago)
+	}
+	return flujos
+}
+
+// frecuencia regresa la frecuencia de pago, quincenal si no se capturó
+func (b CompraBNPL) frecuencia() Frecuencia {
+	if b.Frecuencia == "" {
+		return FrecuenciaQuincenal
+	}
+	return b.Frecuencia
+}
+
+// ValorPresenteMSI descuenta a la tasa de oportunidad los pagos mensuales de una compra a
+// meses sin intereses, para compararla contra un plan BNPL
+func ValorPresenteMSI(monto float64, meses int, tasaOportunidad float64) float64 {
+	if meses <= 0 {
+		return monto
+	}
+	return calc.ValorPresentePagos(monto/float64(meses), meses, tasaOportunidad)
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"finmex/api"
+)
+
+// BonoBienvenida es la recompensa que da una tarjeta al alcanzar un gasto mínimo en un plazo
+type BonoBienvenida api.BonoBienvenida
+
+// fechas regresa el inicio y la fecha límite del bono
+func (b BonoBienvenida) fechas() (time.Time, time.Time, error) {
+	inicio, err := time.Parse("2006-01-02", b.Inicio)
+	if err != nil {
+		return inicio, inicio, fmt.Errorf("Fecha de inicio inválida en el bono de %s: %s (usa AAAA-MM-DD)", b.Tarjeta, b.Inicio)
+	}
+	limite, err := time.Parse("2006-01-02", b.FechaLimite)
+	if err != nil {
+		return inicio, limite, fmt.Errorf("Fecha límite inválida en el bono de %s: %s (usa AAAA-MM-DD)", b.Tarjeta, b.FechaLimite)
+	}
+	if limite.Before(inicio) {
+		return inicio, limite, fmt.Errorf("La fecha límite del bono de %s es anterior al inicio", b.Tarjeta)
+	}
+	return inicio, limite, nil
+}
+
+// ProgresoBono es el avance hacia el gasto mínimo y si el bono conviene
+type ProgresoBono struct {
+	Bono           BonoBienvenida
+	Gastado        float64
+	Faltante       float64
+	DiasRestantes  int
+	RitmoNecesario float64 // Gasto mensual que falta para cumplir a tiempo
+	GastoNormal    float64 // Gasto mensual habitual del usuario
+	GastoExtra     float64 // Gasto por encima del habitual que haría falta para cumplir
+	Anualidad      float64
+	ValorNeto      float64 // Recompensa menos la anualidad del primer año
+	Cumplido       bool
+	Vencido        bool
+	AlcanzaNormal  bool // El gasto habitual basta para cumplir
+	Conviene       bool
+}
+
+// CalcularProgresoBono mide el avance del bono con el gasto registrado en la tarjeta desde el
+// inicio y decide si conviene: la recompensa debe superar la anualidad y el gasto mínimo debe
+// alcanzarse con el gasto habitual, sin comprar de más solo por el bono
+func CalcularProgresoBono(b BonoBienvenida, tarjeta TarjetaCredito, gastado, gastoNormal float64, hoy time.Time) (ProgresoBono, error) {
+	p := ProgresoBono{Bono: b, Gastado: gastado, GastoNormal: gastoNormal, Anualidad: tarjeta.AnualidadEfectiva(gastoNormal)}
+	_, limite, err := b.fechas()
+	if err != nil {
+		return p, err
+	}
+
+	p.ValorNeto = b.Recompensa - p.Anualidad
+	p.Faltante = math.Max(b.GastoMinimo-gastado, 0)
+	p.Cumplido = p.Faltante == 0
+	p.Vencido = !p.Cumplido && hoy.After(limite)
+	if !p.Vencido {
+		p.DiasRestantes = int(limite.Sub(hoy).Hours()/24) + 1
+	}
+
+	meses := float64(p.DiasRestantes) * 12 / 365
+	if !p.Cumplido && meses > 0 {
+		p.RitmoNecesario = p.Faltante / meses
+		p.GastoExtra = math.Max(p.Faltante-gastoNormal*meses, 0)
+	}
+	p.AlcanzaNormal = p.Cumplido || (!p.Vencido && p.GastoExtra == 0)
+	p.Conviene = !p.Vencido && p.ValorNeto > 0 && p.AlcanzaNormal
+	return p, nil
+}
+
+// GastoTarjetaEntre suma los cargos de una tarjeta registrados en movimientos entre dos fechas
+func GastoTarjetaEntre(tarjeta string, desde, hasta time.Time) (float64, error) {
+	filtro := FiltroMovimientos{Desde: desde.Format("2006-01-02"), Hasta: hasta.Format("2006-01-02")}
+	total := 0.0
+	err := RecorrerMovimientosFiltrados(filtro, func(m Movimiento) error {
+		if m.Monto > 0 && normalizarClave(m.Tarjeta) == normalizarClave(tarjeta) {
+			total += m.Monto
+		}
+		return nil
+	})
+	return total, err
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"math"
+
+	"finmex/api"
+	"finmex/calc"
+)
+
+// CajaAhorro representa una caja popular o cooperativa de ahorro y préstamo (SOCAP)
+type CajaAhorro api.CajaAhorro
+
+// FilaCaja es una caja de ahorro en la salida de caja listar con --output
+type FilaCaja struct {
+	CajaAhorro
+	PrestamoMaximo float64 `json:"prestamo_maximo"`
+}
+
+// ComoDebito expresa el ahorro de la caja como una cuenta de débito para compararla con las demás;
+// la parte social funciona como saldo mínimo que no genera rendimiento disponible
+func (c CajaAhorro) ComoDebito() TarjetaDebito {
+	return TarjetaDebito{
+		Nombre:      c.Nombre,
+		Banco:       c.Entidad,
+		Tramos:      calc.TasaUnica(c.TasaRendimiento),
+		SaldoMinimo: c.ParteSocial,
+	}
+}
+
+// PrestamoMaximo regresa cuánto presta la caja con base en el ahorro del socio
+func (c CajaAhorro) PrestamoMaximo() float64 {
+	return math.Max(0, (c.Ahorro+c.ParteSocial)*c.MultiploPrestamo-c.SaldoPrestamo)
+}
+
+// CostoPrestamo regresa el pago mensual y el costo total de un préstamo a socio
+func (c CajaAhorro) CostoPrestamo(monto float64, meses int) (float64, float64) {
+	pago := calc.PagoFijo(monto, c.TasaPrestamo/12, meses)
+	return pago, pago*float64(meses) - monto + monto*c.ComisionApertura
+}
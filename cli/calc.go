@@ -0,0 +1,42 @@
+package cli
+
+import "finmex/calc"
+
+// Los tipos y constantes del paquete calc se usan en todos los comandos; los alias evitan
+// repetir el prefijo calc. sin crear tipos distintos
+type (
+	TarjetaDebito       = calc.TarjetaDebito
+	TramoRendimiento    = calc.TramoRendimiento
+	TarjetaCredito      = calc.TarjetaCredito
+	PlanMSI             = calc.PlanMSI
+	AplicacionPago      = calc.AplicacionPago
+	Frecuencia          = calc.Frecuencia
+	EventoCredito       = calc.EventoCredito
+	RenglonAmortizacion = calc.RenglonAmortizacion
+	ConversionTasa      = calc.ConversionTasa
+	EquivalenciaCAT     = calc.EquivalenciaCAT
+	ResultadoCAT        = calc.ResultadoCAT
+	AñoProyeccion       = calc.AñoProyeccion
+	Hipoteca            = calc.Hipoteca
+	TramoHipoteca       = calc.TramoHipoteca
+	SimulacionHipoteca  = calc.SimulacionHipoteca
+	MesHipoteca         = calc.MesHipoteca
+	CicloCorte          = calc.CicloCorte
+	TransferenciaSaldo  = calc.TransferenciaSaldo
+	CostoTraspaso       = calc.CostoTraspaso
+	RenglonTarifaISR    = calc.RenglonTarifaISR
+	ReciboNomina        = calc.ReciboNomina
+	ConceptoNomina      = calc.ConceptoNomina
+)
+
+const (
+	INFLACION_ANUAL = calc.INFLACION_ANUAL // Se usa si no hay dato de Banxico
+	PAGO_MINIMO     = calc.PAGO_MINIMO
+	IVA             = calc.IVA
+	TOPE_SBC_UMA    = calc.TOPE_SBC_UMA
+
+	FrecuenciaMensual    = calc.FrecuenciaMensual
+	FrecuenciaQuincenal  = calc.FrecuenciaQuincenal
+	FrecuenciaCatorcenal = calc.FrecuenciaCatorcenal
+	FrecuenciaSemanal    = calc.FrecuenciaSemanal
+)
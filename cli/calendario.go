@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PagoProgramado es un pago futuro que aparece en el calendario de pagos
+type PagoProgramado struct {
+	Fecha    time.Time
+	Tipo     string
+	Concepto string
+	Monto    float64
+	Cobro    bool // Dinero que se recibe en lugar de pagarse
+}
+
+// PagosProgramados reúne los pagos pendientes de todos los productos registrados entre dos fechas
+func PagosProgramados(tarjetas Tarjetas, desde, hasta time.Time) ([]PagoProgramado, error) {
+	var pagos []PagoProgramado
+
+	for _, b := range tarjetas.BNPL {
+		fechas, err := b.FechasPago()
+		if err != nil {
+			return nil, err
+		}
+		for i, fecha := range fechas {
+			if fecha.Before(desde) || fecha.After(hasta) {
+				continue
+			}
+			pagos = append(pagos, PagoProgramado{
+				Fecha:    fecha,
+				Tipo:     "BNPL",
+				Concepto: fmt.Sprintf("%s (%s) pago %d de %d", b.Nombre, b.Proveedor, i+1, b.Pagos),
+				Monto:    b.Pago(),
+			})
+		}
+	}
+
+	for _, p := range tarjetas.Informales {
+		pendientes, err := p.PagosPendientes(desde)
+		if err != nil {
+			return nil, err
+		}
+		for _, pendiente := range pendientes {
+			if pendiente.Fecha.After(hasta) {
+				continue
+			}
+			fecha := pendiente.Fecha
+			if pendiente.Vencido {
+				fecha = desde
+			}
+			concepto := fmt.Sprintf("%s pago %d de %d", p.Persona, pendiente.Numero, p.Pagos)
+			if pendiente.Vencido {
+				concepto += fmt.Sprintf(" (vencido el %s)", pendiente.Fecha.Format("2006-01-02"))
+			}
+			pagos = append(pagos, PagoProgramado{
+				Fecha:    fecha,
+				Tipo:     "Informal",
+				Concepto: concepto,
+				Monto:    pendiente.Monto,
+				Cobro:    p.PorCobrar,
+			})
+		}
+	}
+
+	sort.SliceStable(pagos, func(i, j int) bool { return pagos[i].Fecha.Before(pagos[j].Fecha) })
+	return pagos, nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import "time"
+
+// AsesoriaCancelacion resume qué hay que resolver antes de cancelar una tarjeta y cuándo conviene
+type AsesoriaCancelacion struct {
+	Renovacion      time.Time // Próximo cobro de la anualidad
+	Anualidad       float64
+	Revolvente      float64   // Saldo a liquidar antes de cancelar
+	MSIPendiente    float64   // Mensualidades de MSI que faltan por pagar
+	FinMSI          time.Time // Mes de la última mensualidad; cero si no hay planes
+	Beneficios      float64   // Valor de los beneficios ya pagados que faltan por usar
+	BeneficiosHasta time.Time // Fecha hasta la que se usan los beneficios; cero si no hay
+	Desde           time.Time // Primer día en que conviene cancelar
+	AntesRenovacion bool      // Se puede cancelar antes del cobro de la anualidad
+	Esperar         bool      // Conviene pagar la anualidad y cancelar después de usar beneficios y MSI
+}
+
+// AsesorarCancelacion decide cuándo cancelar: lo ideal es después de usar los beneficios
+// pagados y de terminar los MSI, pero antes de que se cobre la anualidad. Si eso no es posible,
+// compara la anualidad contra lo que se pierde al cancelar antes (beneficios sin usar); los MSI
+// pendientes se liquidan de golpe al cancelar, así que solo mueven la fecha, no el costo.
+func AsesorarCancelacion(t TarjetaCredito, hoy, renovacion time.Time, beneficios float64, beneficiosHasta time.Time) (AsesoriaCancelacion, error) {
+	// Si la fecha capturada ya pasó, la anualidad se vuelve a cobrar cada año
+	for renovacion.Before(hoy) {
+		renovacion = renovacion.AddDate(1, 0, 0)
+	}
+
+	a := AsesoriaCancelacion{
+		Renovacion:      renovacion,
+		Anualidad:       t.ComisionAnual,
+		Revolvente:      t.Saldo,
+		Beneficios:      beneficios,
+		BeneficiosHasta: beneficiosHasta,
+		Desde:           hoy,
+	}
+
+	for _, p := range t.Planes {
+		ultimo, err := p.UltimoMes()
+		if err != nil {
+			return a, err
+		}
+		n, err := p.NumeroMensualidad(hoy)
+		if err != nil {
+			return a, err
+		}
+		inicio, _ := time.Parse("2006-01", p.Inicio)
+		switch {
+		case n > 0:
+			a.MSIPendiente += p.Mensualidad() * float64(p.Meses-n+1)
+		case hoy.Before(inicio):
+			a.MSIPendiente += p.Monto
+		default:
+			continue
+		}
+		// La última mensualidad se paga a más tardar al final de ese mes
+		fin := ultimo.AddDate(0, 1, -1)
+		if fin.After(a.FinMSI) {
+			a.FinMSI = fin
+		}
+	}
+
+	if a.FinMSI.After(a.Desde) {
+		a.Desde = a.FinMSI
+	}
+	if beneficios > 0 && beneficiosHasta.After(a.Desde) {
+		a.Desde = beneficiosHasta
+	}
+
+	a.AntesRenovacion = a.Desde.Before(renovacion)
+	if !a.AntesRenovacion {
+		// Cancelar antes de la renovación pierde los beneficios sin usar; conviene esperar
+		// solo si valen más que la anualidad
+		a.Esperar = beneficios > a.Anualidad
+	}
+	return a, nil
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// capturaFlags toma los datos de un comando de sus flags y solo pregunta los que faltan.
+// Sin ningún flag el comando es completamente interactivo, como siempre; con al menos uno
+// se preguntan nada más los datos obligatorios que no se pasaron y los opcionales toman su
+// valor por defecto, para que un script pueda agregar una tarjeta sin quedarse esperando.
+type capturaFlags struct {
+	c           *cli.Context
+	interactiva bool
+}
+
+// nuevaCaptura prepara la captura de los datos del comando
+func nuevaCaptura(c *cli.Context) capturaFlags {
+	return capturaFlags{c: c, interactiva: c.NumFlags() == 0}
+}
+
+// Texto regresa un dato obligatorio de texto
+func (k capturaFlags) Texto(flag, pregunta string) (string, error) {
+	if !k.c.IsSet(flag) {
+		return leerTextoRequerido(pregunta)
+	}
+	texto := normalizarTexto(strings.TrimSpace(k.c.String(flag)))
+	if texto == "" {
+		return "", errDatosInvalidos(fmt.Sprintf("--%s no puede estar vacío", flag), fmt.Sprintf("--%s cannot be empty", flag))
+	}
+	return texto, nil
+}
+
+// Numero regresa un dato numérico obligatorio
+func (k capturaFlags) Numero(flag, pregunta string, limites LimitesNumero) (float64, error) {
+	if !k.c.IsSet(flag) {
+		return leerNumero(pregunta, limites)
+	}
+	return k.numeroDeFlag(flag, limites)
+}
+
+// NumeroOpcional regresa un dato numérico que vale 0 si no se indica
+func (k capturaFlags) NumeroOpcional(flag, pregunta string, limites LimitesNumero) (float64, error) {
+	if k.c.IsSet(flag) {
+		return k.numeroDeFlag(flag, limites)
+	}
+	if k.interactiva {
+		return leerNumero(pregunta, limites)
+	}
+	return 0, nil
+}
+
+// SiNo regresa un dato de sí o no que es falso si no se indica
+func (k capturaFlags) SiNo(flag, pregunta string) (bool, error) {
+	if k.c.IsSet(flag) || !k.interactiva {
+		return k.c.Bool(flag), nil
+	}
+	return leerSiNo(pregunta)
+}
+
+// Lista regresa una lista separada por comas que queda vacía si no se indica
+func (k capturaFlags) Lista(flag, pregunta string) ([]string, error) {
+	if k.c.IsSet(flag) {
+		return ParsearLista(k.c.String(flag)), nil
+	}
+	if !k.interactiva {
+		return nil, nil
+	}
+	texto, err := leerTexto(pregunta)
+	if err != nil {
+		return nil, err
+	}
+	return ParsearLista(texto), nil
+}
+
+// Moneda regresa la moneda de una cuenta; se conserva la actual si no se indica otra. MXN
+// se guarda vacía, como en las cuentas registradas antes de que hubiera monedas.
+func (k capturaFlags) Moneda(flag, pregunta, actual string) (string, error) {
+	texto := actual
+	if k.c.IsSet(flag) {
+		texto = k.c.String(flag)
+	} else if k.interactiva {
+		capturada, err := leerTexto(fmt.Sprintf("%s [%s]: ", pregunta, MonedaCuenta(TarjetaDebito{Moneda: actual})))
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(capturada) != "" {
+			texto = capturada
+		}
+	}
+	moneda, err := ValidarMoneda(texto)
+	if moneda == MonedaMXN {
+		moneda = ""
+	}
+	return moneda, err
+}
+
+func (k capturaFlags) numeroDeFlag(flag string, limites LimitesNumero) (float64, error) {
+	valor := k.c.Float64(flag)
+	return valor, validarFlag(flag, valor, limites)
+}
+
+// EditarTexto regresa el nuevo valor de un dato de texto. Sin flags se pregunta mostrando
+// el valor actual, que se conserva si no se captura nada.
+func (k capturaFlags) EditarTexto(flag, pregunta, actual string) (string, error) {
+	if k.c.IsSet(flag) {
+		return k.Texto(flag, pregunta)
+	}
+	if !k.interactiva {
+		return actual, nil
+	}
+	return leerTextoConValor(pregunta, actual)
+}
+
+// EditarNumero regresa el nuevo valor de un dato numérico
+func (k capturaFlags) EditarNumero(flag, pregunta string, actual float64, limites LimitesNumero) (float64, error) {
+	if k.c.IsSet(flag) {
+		return k.numeroDeFlag(flag, limites)
+	}
+	if !k.interactiva {
+		return actual, nil
+	}
+	return leerNumeroConValor(pregunta, actual, limites)
+}
+
+// EditarSiNo regresa el nuevo valor de un dato de sí o no
+func (k capturaFlags) EditarSiNo(flag, pregunta string, actual bool) (bool, error) {
+	if k.c.IsSet(flag) {
+		return k.c.Bool(flag), nil
+	}
+	if !k.interactiva {
+		return actual, nil
+	}
+	return leerSiNoConValor(pregunta, actual)
+}
+
+// EditarLista regresa la nueva lista; en modo interactivo "-" la deja vacía
+func (k capturaFlags) EditarLista(flag, pregunta string, actual []string) ([]string, error) {
+	if k.c.IsSet(flag) {
+		return ParsearLista(k.c.String(flag)), nil
+	}
+	if !k.interactiva {
+		return actual, nil
+	}
+	texto, err := leerTextoConValor(pregunta+" (- para quitarlas)", strings.Join(actual, ", "))
+	if err != nil || texto == "-" {
+		return nil, err
+	}
+	return ParsearLista(texto), nil
+}
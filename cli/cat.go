@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"math"
+
+	"finmex/calc"
+)
+
+// toleranciaCAT es la diferencia en puntos porcentuales a partir de la cual el CAT calculado
+// se considera distinto del anunciado. Los bancos publican el CAT con un decimal y con montos
+// de referencia propios, así que diferencias menores no indican un error.
+const toleranciaCAT = 0.01
+
+// VerificacionCAT compara el CAT calculado con los flujos del plan de pagos contra el CAT
+// capturado de la tarjeta
+type VerificacionCAT struct {
+	Tarjeta      string  `json:"tarjeta"`
+	Deuda        float64 `json:"deuda"`
+	Pago         float64 `json:"pago"`
+	ResultadoCAT         // Flujos y CAT calculado
+	SoloTasa     float64 `json:"solo_tasa"` // Tasa efectiva sin comisiones, para ver cuánto suman las comisiones
+	Capturado    float64 `json:"cat_capturado"`
+	Diferencia   float64 `json:"diferencia"` // Calculado menos capturado
+}
+
+// Discrepancia indica si el CAT calculado y el capturado difieren más de la tolerancia
+func (v VerificacionCAT) Discrepancia() bool {
+	return math.Abs(v.Diferencia) > toleranciaCAT
+}
+
+// VerificarCAT calcula el CAT de la tarjeta para la deuda y el pago mensual dados. Un pago
+// menor al mínimo se ajusta al mínimo, como en el resto de los análisis.
+func VerificarCAT(t TarjetaCredito, deuda, pago, comisionMensual float64) (VerificacionCAT, error) {
+	if minimo := calc.PagoMinimoPeriodo(t, deuda, FrecuenciaMensual); pago < minimo {
+		pago = minimo
+	}
+	r, err := calc.CalcularCAT(t, deuda, pago, comisionMensual)
+	if err != nil {
+		return VerificacionCAT{}, err
+	}
+	return VerificacionCAT{
+		Tarjeta:      t.Nombre,
+		Deuda:        deuda,
+		Pago:         pago,
+		ResultadoCAT: r,
+		SoloTasa:     calc.EfectivaDesdeNominal(t.TasaInteres, 12),
+		Capturado:    t.CAT,
+		Diferencia:   r.CAT - t.CAT,
+	}, nil
+}
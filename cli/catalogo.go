@@ -0,0 +1,168 @@
+package cli
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ARCHIVO_CATALOGO es la copia local del catálogo; si existe, reemplaza al embebido
+// para poder actualizar tasas y productos sin recompilar
+const ARCHIVO_CATALOGO = "catalogo.json"
+
+// URL_CATALOGO es el catálogo publicado en el repositorio, que se actualiza más seguido que
+// las releases
+const URL_CATALOGO = "https://raw.githubusercontent.com/Momentitos/finmex/main/cli/catalogo.json"
+
+// catalogoEmbebido trae productos de referencia del mercado mexicano dentro del binario
+//
+//go:embed catalogo.json
+var catalogoEmbebido []byte
+
+// CreditoCatalogo es una tarjeta de crédito del catálogo con los beneficios que permiten
+// valuarla contra un perfil de gasto
+type CreditoCatalogo struct {
+	TarjetaCredito
+	Segmento              string  `json:"segmento"`         // clasica, oro, platino, digital
+	Viajes                bool    `json:"viajes,omitempty"` // Salas VIP, seguros de viaje, etc.
+	SinComisionExtranjero bool    `json:"sin_comision_extranjero,omitempty"`
+	ValorViaje            float64 `json:"valor_viaje,omitempty"` // Valor estimado de los beneficios por viaje
+}
+
+// DebitoCatalogo es una cuenta de débito del catálogo
+type DebitoCatalogo struct {
+	TarjetaDebito
+	Segmento string `json:"segmento"` // digital o tradicional
+}
+
+// Benchmark es una tasa de referencia de inversión de bajo riesgo (CETES, SOFIPOs)
+type Benchmark struct {
+	Nombre string  `json:"nombre"`
+	Tipo   string  `json:"tipo"` // gubernamental o sofipo
+	Tasa   float64 `json:"tasa"` // Tasa anual bruta
+}
+
+// Catalogo agrupa los productos de referencia
+type Catalogo struct {
+	Version    string            `json:"version"`
+	Credito    []CreditoCatalogo `json:"credito"`
+	Debito     []DebitoCatalogo  `json:"debito"`
+	Benchmarks []Benchmark       `json:"benchmarks"`
+}
+
+// CargarCatalogo lee el catálogo local si existe y, si no, el embebido en el binario. Con un
+// escenario activo las tasas se ajustan como indica.
+func CargarCatalogo() (Catalogo, error) {
+	catalogo, err := leerCatalogo()
+	if err == nil && escenarioActivo != nil {
+		catalogo = escenarioActivo.AjustarCatalogo(catalogo)
+	}
+	return catalogo, err
+}
+
+func leerCatalogo() (Catalogo, error) {
+	var catalogo Catalogo
+
+	data, err := ioutil.ReadFile(rutaComun(ARCHIVO_CATALOGO))
+	if os.IsNotExist(err) {
+		registro.Debug("usando catálogo embebido")
+		if err := json.Unmarshal(catalogoEmbebido, &catalogo); err != nil {
+			return catalogo, fmt.Errorf("Catálogo embebido inválido: %v", err)
+		}
+		return catalogo, nil
+	}
+	if err != nil {
+		return catalogo, err
+	}
+
+	if err := json.Unmarshal(data, &catalogo); err != nil {
+		return catalogo, errArchivoCorrupto(rutaComun(ARCHIVO_CATALOGO), err)
+	}
+	// Un catálogo descargado antes de los tramos trae una sola tasa por cuenta
+	for i := range catalogo.Debito {
+		catalogo.Debito[i].MigrarTramos()
+	}
+	registro.Debug("usando catálogo local", "archivo", ARCHIVO_CATALOGO, "version", catalogo.Version)
+	return catalogo, nil
+}
+
+// urlCatalogo permite apuntar a otro catálogo con FINMEX_URL_CATALOGO, p. ej. un espejo
+func urlCatalogo() string {
+	if url := os.Getenv("FINMEX_URL_CATALOGO"); url != "" {
+		return url
+	}
+	return URL_CATALOGO
+}
+
+// CatalogoLocal indica si se está usando una copia local del catálogo en lugar del embebido
+func CatalogoLocal() bool {
+	_, err := os.Stat(rutaComun(ARCHIVO_CATALOGO))
+	return err == nil
+}
+
+// LeerCatalogoJSON valida un catálogo contra su esquema antes de usarlo, para que un archivo
+// descargado con un campo mal escrito no deje tasas en cero. El origen es la URL o el archivo
+// de donde viene y solo se usa en los mensajes.
+func LeerCatalogoJSON(origen string, data []byte) (Catalogo, error) {
+	var catalogo Catalogo
+	valor, err := decodificarParaValidar(data)
+	if err != nil {
+		return catalogo, errArchivoCorrupto(origen, err)
+	}
+	esquema, err := EsquemaArchivo("catalogo")
+	if err != nil {
+		return catalogo, err
+	}
+	if errores := esquema.Validar(valor); len(errores) > 0 {
+		return catalogo, errArchivoCorrupto(origen, resumenErroresValidacion(errores))
+	}
+	if err := json.Unmarshal(data, &catalogo); err != nil {
+		return catalogo, errArchivoCorrupto(origen, err)
+	}
+	if len(catalogo.Credito) == 0 && len(catalogo.Debito) == 0 {
+		return catalogo, errDatosInvalidos(
+			fmt.Sprintf("El catálogo de %s no trae productos", origen),
+			fmt.Sprintf("The catalog from %s has no products", origen))
+	}
+	return catalogo, nil
+}
+
+// InstalarCatalogo valida el catálogo y lo guarda como copia local, que desde entonces
+// reemplaza al embebido
+func InstalarCatalogo(origen string, data []byte) (Catalogo, error) {
+	catalogo, err := LeerCatalogoJSON(origen, data)
+	if err != nil {
+		return catalogo, err
+	}
+	ruta := rutaComun(ARCHIVO_CATALOGO)
+	if err := os.MkdirAll(filepath.Dir(ruta), 0755); err != nil {
+		return catalogo, err
+	}
+	temporal := ruta + ".nuevo"
+	if err := os.WriteFile(temporal, data, 0644); err != nil {
+		return catalogo, err
+	}
+	registro.Info("catálogo instalado", "origen", origen, "version", catalogo.Version)
+	return catalogo, os.Rename(temporal, ruta)
+}
+
+// DescargarCatalogo descarga el catálogo publicado y lo instala
+func DescargarCatalogo(url string) (Catalogo, error) {
+	data, err := descargar(url)
+	if err != nil {
+		return Catalogo{}, fmt.Errorf("Error al descargar el catálogo: %v", err)
+	}
+	return InstalarCatalogo(url, data)
+}
+
+// RestaurarCatalogo borra la copia local para volver al catálogo embebido en el binario
+func RestaurarCatalogo() error {
+	err := os.Remove(rutaComun(ARCHIVO_CATALOGO))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
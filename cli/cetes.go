@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"finmex/api"
+)
+
+// Instrumentos gubernamentales que se compran en cetesdirecto
+const (
+	InstrumentoCetes    = "cetes"    // Certificados de la Tesorería, a descuento
+	InstrumentoBondes   = "bondes"   // Bonos de desarrollo con tasa revisable
+	InstrumentoUdibonos = "udibonos" // Bonos en UDIs; la tasa es real, por encima de la inflación
+)
+
+// DIAS_AÑO_GUBERNAMENTAL es la convención de días con la que se cotizan las tasas de los
+// valores gubernamentales
+const DIAS_AÑO_GUBERNAMENTAL = 360
+
+// ParsearInstrumento valida el nombre de un instrumento de cetesdirecto
+func ParsearInstrumento(texto string) (string, error) {
+	instrumento := strings.ToLower(strings.TrimSpace(texto))
+	switch instrumento {
+	case InstrumentoCetes, InstrumentoBondes, InstrumentoUdibonos:
+		return instrumento, nil
+	}
+	return "", fmt.Errorf("Instrumento inválido '%s' (usa cetes, bondes o udibonos)", texto)
+}
+
+// InversionCetes es una inversión en valores gubernamentales comprados en cetesdirecto
+type InversionCetes api.InversionCetes
+
+// RendimientoCetes desglosa lo que deja una inversión al vencimiento
+type RendimientoCetes struct {
+	TasaNominal float64 `json:"tasa_nominal"` // Tasa anual en pesos; en UDIBONOS incluye la inflación
+	Bruto       float64 `json:"bruto"`        // Interés del plazo
+	Retencion   float64 `json:"retencion"`    // ISR del plazo según el régimen elegido
+	Neto        float64 `json:"neto"`
+	TasaNeta    float64 `json:"tasa_neta"` // Rendimiento neto anualizado
+	TasaReal    float64 `json:"tasa_real"` // Rendimiento neto anualizado menos la inflación
+	MontoFinal  float64 `json:"monto_final"`
+}
+
+// FilaCetes es una inversión con su rendimiento en la salida de cetes listar y analizar
+// con --output
+type FilaCetes struct {
+	InversionCetes
+	RendimientoCetes
+	Inflacion float64    `json:"inflacion"`
+	EnUDIS    *MontoUDIS `json:"en_udis,omitempty"` // Con --udis: el monto al vencimiento en pesos de hoy
+}
+
+// TasaNominal regresa la tasa anual en pesos de la inversión. Los UDIBONOS pagan una tasa
+// real sobre UDIs, que suben con la inflación.
+func (i InversionCetes) TasaNominal(inflacion float64) float64 {
+	if i.Instrumento == InstrumentoUdibonos {
+		return (1+i.Tasa)*(1+inflacion) - 1
+	}
+	return i.Tasa
+}
+
+// Rendimiento calcula el interés del plazo con la convención de 360 días y le resta el ISR
+// del régimen elegido, proporcional a los días de la inversión. La tasa real se obtiene igual que en CalcularRendimientoReal, restando
+// la inflación al rendimiento neto anual, para poder compararla con las cuentas de débito.
+func (i InversionCetes) Rendimiento(inflacion float64) RendimientoCetes {
+	r := RendimientoCetes{TasaNominal: i.TasaNominal(inflacion)}
+	if i.Monto <= 0 || i.PlazoDias <= 0 {
+		return r
+	}
+
+	dias := float64(i.PlazoDias)
+	r.Bruto = i.Monto * r.TasaNominal * dias / DIAS_AÑO_GUBERNAMENTAL
+	r.Retencion = i.Monto * ISRVigente().TasaImpuesto(r.TasaNominal, inflacion) * dias / 365
+	r.Neto = r.Bruto - r.Retencion
+	r.TasaNeta = r.Neto / i.Monto * 365 / dias
+	r.TasaReal = r.TasaNeta - inflacion
+	r.MontoFinal = i.Monto + r.Neto
+	return r
+}
+
+// PrecioCete regresa el precio de un CETE de valor nominal $10 con la tasa de rendimiento y
+// el plazo dados; los CETES se compran a descuento y se liquidan a $10
+func PrecioCete(tasa float64, plazoDias int) float64 {
+	return 10 / (1 + tasa*float64(plazoDias)/DIAS_AÑO_GUBERNAMENTAL)
+}
+
+// TitulosCetes regresa cuántos títulos completos se compran con el monto y cuánto sobra
+func TitulosCetes(monto, tasa float64, plazoDias int) (int, float64) {
+	precio := PrecioCete(tasa, plazoDias)
+	titulos := int(math.Floor(monto / precio))
+	return titulos, monto - float64(titulos)*precio
+}
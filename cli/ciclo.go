@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"time"
+
+	"finmex/calc"
+)
+
+// EstadoCiclo es el periodo de facturación de una tarjeta de crédito con los días de
+// financiamiento de una compra y el interés que se generaría sobre el saldo promedio diario
+type EstadoCiclo struct {
+	Tarjeta string `json:"tarjeta"`
+	CicloCorte
+	Dias               int       `json:"dias"`
+	Compra             time.Time `json:"compra"`
+	DiasFinanciamiento int       `json:"dias_financiamiento"`
+	MejorDia           time.Time `json:"mejor_dia"` // El día siguiente al corte da el mayor plazo
+	DiasMejorDia       int       `json:"dias_mejor_dia"`
+	SaldoInicial       float64   `json:"saldo_inicial"`
+	Movimientos        int       `json:"movimientos"`
+	SaldoPromedio      float64   `json:"saldo_promedio"`
+	Interes            float64   `json:"interes"` // Si no se paga el total a tiempo, sin IVA
+	IVAInteres         float64   `json:"iva_interes"`
+}
+
+// EvaluarCiclo arma el periodo de la tarjeta que incluye la compra. El saldo promedio parte
+// del saldo al corte anterior y suma los movimientos registrados de la tarjeta en el periodo.
+func EvaluarCiclo(t TarjetaCredito, compra time.Time, saldoInicial float64) (EstadoCiclo, error) {
+	ciclo, _ := t.Ciclo(compra)
+	mejor := ciclo.Corte.AddDate(0, 0, 1)
+	e := EstadoCiclo{
+		Tarjeta:            t.Nombre,
+		CicloCorte:         ciclo,
+		Dias:               ciclo.Dias(),
+		Compra:             compra,
+		DiasFinanciamiento: ciclo.DiasFinanciamiento(compra),
+		MejorDia:           mejor,
+		DiasMejorDia:       ciclo.Siguiente(t.DiaCorte, t.DiaLimitePago).DiasFinanciamiento(mejor),
+		SaldoInicial:       saldoInicial,
+	}
+
+	var movimientos []calc.MovimientoDiario
+	err := RecorrerMovimientos(func(m Movimiento) error {
+		if normalizarClave(m.Tarjeta) != normalizarClave(t.Nombre) {
+			return nil
+		}
+		fecha, err := time.Parse("2006-01-02", m.Fecha)
+		if err != nil || fecha.Before(ciclo.Inicio) || fecha.After(ciclo.Corte) {
+			return nil
+		}
+		movimientos = append(movimientos, calc.MovimientoDiario{Fecha: fecha, Monto: m.Monto})
+		return nil
+	})
+	if err != nil {
+		return e, err
+	}
+	e.Movimientos = len(movimientos)
+	e.SaldoPromedio = calc.SaldoPromedioDiario(saldoInicial, ciclo, movimientos)
+	e.Interes = calc.InteresDiario(e.SaldoPromedio, t.TasaInteres, e.Dias)
+	e.IVAInteres = e.Interes * IVA
+	return e, nil
+}
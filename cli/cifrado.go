@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"finmex/storage"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// ARCHIVO_CIFRADO guarda tarjetas y movimientos cifrados después de 'finmex cifrar'. Mientras
+// existe, se usa en lugar del almacén elegido con --almacen.
+const ARCHIVO_CIFRADO = "finmex.cifrado"
+
+// VARIABLE_PASSPHRASE permite dar la passphrase sin escribirla, p. ej. desde el llavero del
+// sistema: FINMEX_PASSPHRASE="$(secret-tool lookup app finmex)"
+const VARIABLE_PASSPHRASE = "FINMEX_PASSPHRASE"
+
+// passphraseDatos se pide una sola vez por ejecución
+var passphraseDatos []byte
+
+// almacenCifrado indica si los datos del perfil activo están cifrados
+func almacenCifrado() bool {
+	return storage.Existe(rutaDatos(ARCHIVO_CIFRADO))
+}
+
+// leerPassphrase toma la passphrase de FINMEX_PASSPHRASE o la pide sin mostrarla en pantalla.
+// Sin terminal, como en un script, se lee una línea de la entrada estándar.
+func leerPassphrase(pregunta string) ([]byte, error) {
+	if p := os.Getenv(VARIABLE_PASSPHRASE); p != "" {
+		return []byte(p), nil
+	}
+	fmt.Fprint(os.Stderr, pregunta)
+	if term.IsTerminal(os.Stdin.Fd()) {
+		p, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	linea, err := leerLinea()
+	return []byte(linea), err
+}
+
+// pedirPassphraseNueva pide la passphrase dos veces para evitar una errata que deje los
+// datos inaccesibles
+func pedirPassphraseNueva() ([]byte, error) {
+	if p := os.Getenv(VARIABLE_PASSPHRASE); p != "" {
+		return []byte(p), nil
+	}
+	p, err := leerPassphrase("Passphrase nueva: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(p) < 8 {
+		return nil, errDatosInvalidos("La passphrase debe tener al menos 8 caracteres", "The passphrase must be at least 8 characters long")
+	}
+	confirmacion, err := leerPassphrase("Repite la passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if string(confirmacion) != string(p) {
+		return nil, errDatosInvalidos("Las passphrases no coinciden", "The passphrases do not match")
+	}
+	return p, nil
+}
+
+// abrirAlmacenCifrado descifra los datos del perfil activo con la passphrase
+func abrirAlmacenCifrado() (*storage.Cifrado, error) {
+	if passphraseDatos == nil {
+		p, err := leerPassphrase("Passphrase de tus datos: ")
+		if err != nil {
+			return nil, err
+		}
+		passphraseDatos = p
+	}
+	registro.Debug("abriendo datos cifrados", "archivo", rutaDatos(ARCHIVO_CIFRADO))
+	cifrado, err := storage.AbrirCifrado(rutaDatos(ARCHIVO_CIFRADO), passphraseDatos)
+	if errors.Is(err, storage.ErrPassphraseIncorrecta) {
+		passphraseDatos = nil
+		return nil, &ErrorFinmex{
+			Codigo:  CodigoDatosInvalidos,
+			Mensaje: fmt.Sprintf("No se pudo descifrar %s: la passphrase es incorrecta o el archivo fue alterado", rutaDatos(ARCHIVO_CIFRADO)),
+			Message: fmt.Sprintf("Could not decrypt %s: the passphrase is wrong or the file was altered", rutaDatos(ARCHIVO_CIFRADO)),
+			Causa:   err,
+		}
+	}
+	var formato *storage.ErrorFormato
+	if errors.As(err, &formato) {
+		return nil, errArchivoCorrupto(formato.Archivo, formato.Causa)
+	}
+	return cifrado, err
+}
+
+// archivosEnClaro son los archivos del perfil activo que pueden tener los datos sin cifrar
+func archivosEnClaro() []string {
+	var archivos []string
+	for _, nombre := range []string{
+		ARCHIVO_BASE_DATOS, ARCHIVO_BASE_DATOS + "-wal", ARCHIVO_BASE_DATOS + "-shm",
+		ARCHIVO_TARJETAS, ARCHIVO_MOVIMIENTOS, ARCHIVO_INDICE_MOVIMIENTOS,
+	} {
+		if storage.Existe(rutaDatos(nombre)) {
+			archivos = append(archivos, rutaDatos(nombre))
+		}
+	}
+	return archivos
+}
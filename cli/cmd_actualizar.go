@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoActualizar descarga e instala la última release publicada
+func comandoActualizar() *cli.Command {
+	return &cli.Command{
+		Name:  "actualizar",
+		Usage: "Actualizar finmex a la última versión publicada",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "canal", Value: CanalEstable, Usage: "Canal de releases: estable o beta"},
+			&cli.BoolFlag{Name: "verificar", Usage: "Solo revisar si hay una versión nueva, sin instalarla"},
+			&cli.BoolFlag{Name: "forzar", Usage: "Instalar aunque la versión publicada no sea más nueva"},
+			&cli.BoolFlag{Name: "sin-firma", Usage: "Instalar aunque el binario no traiga la llave para verificar la firma; solo se verifica el checksum"},
+		},
+		Action: func(c *cli.Context) error {
+			canal := c.String("canal")
+			if err := ValidarCanal(canal); err != nil {
+				return err
+			}
+
+			release, err := UltimaRelease(canal)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Versión instalada: %s\n", version)
+			fmt.Printf("Última versión (%s): %s\n", canal, release.Etiqueta)
+
+			if !HayActualizacion(release) && !c.Bool("forzar") {
+				if _, ok := parsearVersion(version); !ok {
+					fmt.Println("RESULTADO: Esta es una compilación de desarrollo; usa --forzar para reemplazarla por la release")
+				} else {
+					fmt.Println("RESULTADO: Ya tienes la versión más reciente")
+				}
+				return nil
+			}
+			if c.Bool("verificar") {
+				fmt.Println("RESULTADO: Hay una versión nueva; ejecuta 'finmex actualizar' para instalarla")
+				return nil
+			}
+
+			data, err := DescargarBinario(release, c.Bool("sin-firma"))
+			if err != nil {
+				return err
+			}
+			if clavePublicaReleases != "" {
+				fmt.Println("Firma y checksum verificados")
+			} else {
+				fmt.Println("AVISO: Solo se verificó el checksum; este binario no puede verificar la firma de la release")
+			}
+
+			ruta, err := ReemplazarBinario(data)
+			if err != nil {
+				return fmt.Errorf("Error al reemplazar el binario: %v", err)
+			}
+			fmt.Printf("RESULTADO: finmex actualizado a %s en %s\n", release.Etiqueta, ruta)
+			return nil
+		},
+	}
+}
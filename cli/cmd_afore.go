@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoAfore agrupa los análisis de la cuenta de la Afore
+func comandoAfore() *cli.Command {
+	return &cli.Command{
+		Name:  "afore",
+		Usage: "Análisis de la cuenta Afore",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "voluntarias",
+				Usage: "Comparar aportaciones voluntarias a la Afore contra CETES y tu cuenta de débito",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "rendimiento", Usage: "Rendimiento anual de la Siefore (por omisión el histórico aproximado de tu generación)"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "comision", Value: COMISION_AFORE, Usage: "Comisión anual de la Afore sobre saldo"}, limitesFraccion),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					nacimiento, err := leerEntero("Año de nacimiento: ", 1940, time.Now().Year())
+					if err != nil {
+						return err
+					}
+					edadRetiro, err := leerEntero("Edad de retiro (ej. 65): ", 40, 100)
+					if err != nil {
+						return err
+					}
+					meses := (nacimiento + edadRetiro - time.Now().Year()) * 12
+					if meses <= 0 {
+						return fmt.Errorf("La edad de retiro debe ser posterior a tu edad actual")
+					}
+
+					a := AportacionVoluntaria{Meses: meses, Comision: c.Float64("comision")}
+					if a.Mensual, err = leerNumero("Aportación voluntaria mensual: ", limitesMonto); err != nil {
+						return err
+					}
+
+					siefore, err := SieforeParaAño(nacimiento)
+					if err != nil {
+						return err
+					}
+					a.Rendimiento = siefore.RendimientoAnual
+					if c.IsSet("rendimiento") {
+						a.Rendimiento = c.Float64("rendimiento")
+					}
+
+					if a.Deducir, err = leerSiNo("¿Las deducirás en tu declaración anual? (s/n): "); err != nil {
+						return err
+					}
+					if a.Deducir {
+						if a.IngresoAnual, err = leerNumero("Ingreso anual acumulable: ", limitesMonto); err != nil {
+							return err
+						}
+					}
+
+					tasaCETES, err := leerNumero("Tasa anual de CETES (decimal): ", limitesTasa)
+					if err != nil {
+						return err
+					}
+					netaDebito, cuenta := MejorTasaDebitoNeta(tarjetas)
+					tasaDebito := TasaBrutaISR(netaDebito)
+
+					r, err := SimularAportacionVoluntaria(a, tasaCETES, tasaDebito, time.Now().Year())
+					if err != nil {
+						return err
+					}
+
+					fmt.Println("\n=== Aportaciones Voluntarias a la Afore ===")
+					fmt.Printf("Siefore: %s, rendimiento %.2f%% menos comisión %.2f%%\n", siefore.Nombre, a.Rendimiento*100, a.Comision*100)
+					fmt.Printf("Horizonte: %d años | Total aportado: $%.2f\n\n", meses/12, r.Aportado)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Opción\tTasa Neta\tSaldo al Retiro\tDevoluciones ISR\tValor Total")
+					fmt.Fprintln(w, "------\t---------\t---------------\t----------------\t-----------")
+					fmt.Fprintf(w, "Afore (%s)\t%.2f%%\t$%.2f\t$%.2f\t$%.2f\n", siefore.Nombre, (a.Rendimiento-a.Comision)*100, r.SaldoAfore, r.Devoluciones, r.ValorAfore)
+					fmt.Fprintf(w, "CETES\t%.2f%%\t$%.2f\t$0.00\t$%.2f\n", TasaNetaISR(tasaCETES)*100, r.SaldoCETES, r.SaldoCETES)
+					if cuenta != "" {
+						fmt.Fprintf(w, "%s\t%.2f%%\t$%.2f\t$0.00\t$%.2f\n", cuenta, TasaNetaISR(tasaDebito)*100, r.SaldoDebito, r.SaldoDebito)
+					}
+					w.Flush()
+
+					mejor, valor := "la Afore", r.ValorAfore
+					if r.SaldoCETES > valor {
+						mejor, valor = "CETES", r.SaldoCETES
+					}
+					if cuenta != "" && r.SaldoDebito > valor {
+						mejor, valor = cuenta, r.SaldoDebito
+					}
+					fmt.Printf("\nRESULTADO: Al retiro te conviene más %s ($%.2f)\n", mejor, valor)
+					fmt.Println("Las aportaciones a la Afore no se pueden retirar libremente antes del retiro.")
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// imprimirTablaAmortizacion muestra la tabla de amortización como en un estado de cuenta,
+// con la fecha de cada pago del calendario y una fila de totales
+func imprimirTablaAmortizacion(tabla []RenglonAmortizacion, calendario []time.Time) {
+	fmt.Println("\n=== Tabla de Amortización ===")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "No.\tFecha\tSaldo Inicial\tInterés\tIVA\tPago\tAbono a Capital\tSaldo Final")
+	fmt.Fprintln(w, "---\t-----\t-------------\t-------\t---\t----\t---------------\t-----------")
+
+	var interes, iva, pagado, capital float64
+	for _, r := range tabla {
+		fecha := "-"
+		if r.Periodo <= len(calendario) {
+			fecha = calendario[r.Periodo-1].Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%d\t%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+			r.Periodo, fecha, r.SaldoInicial, r.Interes, r.IVA, r.Pago, r.Capital, r.SaldoFinal)
+		interes += r.Interes
+		iva += r.IVA
+		pagado += r.Pago
+		capital += r.Capital
+	}
+	fmt.Fprintf(w, "Total\t\t\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t\n", interes, iva, pagado, capital)
+	w.Flush()
+
+	if n := len(tabla); n > 0 && tabla[n-1].SaldoFinal > 0 {
+		fmt.Printf("AVISO: Con ese pago la deuda no se liquida; la tabla se corta en %d pagos con saldo de $%.2f\n", n, tabla[n-1].SaldoFinal)
+	}
+}
@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoAuto agrupa los análisis para adquirir un auto
+func comandoAuto() *cli.Command {
+	return &cli.Command{
+		Name:  "auto",
+		Usage: "Análisis para adquirir un auto",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "comparar",
+				Usage: "Comparar pago de contado, crédito automotriz y arrendamiento puro",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					cotizacion, err := leerCotizacionAuto()
+					if err != nil {
+						return err
+					}
+
+					tasaOportunidad, cuenta := MejorTasaDebitoNeta(tarjetas)
+					if cuenta == "" {
+						if tasaOportunidad, err = leerNumero("Tasa anual neta que rinde tu dinero (decimal): ", limitesTasa); err != nil {
+							return err
+						}
+						cuenta = "capturada"
+					}
+
+					opciones := CompararAuto(cotizacion, tasaOportunidad)
+					if salidaEstructurada() {
+						return emitirDatos(opciones)
+					}
+
+					fmt.Println("\n=== Contado vs Crédito vs Arrendamiento ===")
+					fmt.Printf("Precio: $%.2f | Plazo: %d meses | Valor al final: $%.2f\n", cotizacion.Precio, cotizacion.Meses, cotizacion.ValorMercadoFinal)
+					fmt.Printf("Tasa de oportunidad: %.2f%% neta (%s)\n\n", tasaOportunidad*100, cuenta)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Opción\tDesembolso\tAhorro Fiscal\tValor Final\tCosto Real")
+					fmt.Fprintln(w, "------\t----------\t-------------\t-----------\t----------")
+
+					mejor := opciones[0]
+					for _, o := range opciones {
+						fmt.Fprintf(w, "%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n", o.Opcion, o.Desembolso, o.AhorroFiscal, o.ValorFinal, o.CostoReal)
+						if o.CostoReal < mejor.CostoReal {
+							mejor = o
+						}
+					}
+
+					w.Flush()
+					fmt.Println("\nEl costo real es el valor presente de lo pagado menos el ahorro fiscal y lo que vale el auto al final.")
+					fmt.Printf("RESULTADO: La opción más barata es %s, con un costo real de $%.2f\n", mejor.Opcion, mejor.CostoReal)
+					return nil
+				},
+			},
+			{
+				Name:  "simular",
+				Usage: "Comparar crédito bancario, crédito de agencia y arrendamiento puro y financiero en pesos netos",
+				Description: "Cada opción se incluye si se capturan sus datos: --tasa-agencia para el crédito de agencia, --renta para el\n" +
+					"arrendamiento puro y --tasa-financiero para el financiero. El costo real es el valor presente de lo pagado,\n" +
+					"incluidos seguros y comisiones, menos el ahorro fiscal si facturas y lo que vale el auto al final.\n" +
+					"Ejemplo: finmex auto simular --precio 450000 --tasa 0.14 --tasa-agencia 0 --enganche-agencia 0.4 --renta 11500",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "precio", Required: true, Usage: "Precio de contado del auto"}, limitesMonto),
+					&cli.IntFlag{Name: "meses", Value: 36, Usage: "Plazo a comparar en meses"},
+					conLimites(&cli.Float64Flag{Name: "valor-final", Usage: "Valor del auto al terminar el plazo; por defecto se estima con una depreciación de 15% anual"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "seguro-anual", Usage: "Seguro anual del auto"}, limitesMonto),
+
+					conLimites(&cli.Float64Flag{Name: "enganche", Value: 0.20, Usage: "Crédito bancario: enganche como fracción del precio"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "tasa", Required: true, Usage: "Crédito bancario: tasa anual en decimal"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "comision-apertura", Usage: "Crédito bancario: comisión por apertura como fracción de lo financiado"}, limitesFraccion),
+
+					conLimites(&cli.Float64Flag{Name: "tasa-agencia", Usage: "Crédito de agencia: tasa anual en decimal, 0 si es sin intereses"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "enganche-agencia", Value: 0.40, Usage: "Crédito de agencia: enganche como fracción del precio"}, limitesFraccion),
+					&cli.IntFlag{Name: "meses-agencia", Usage: "Crédito de agencia: plazo en meses, por defecto --meses"},
+					conLimites(&cli.Float64Flag{Name: "comision-agencia", Usage: "Crédito de agencia: comisión por apertura como fracción de lo financiado"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "seguro-agencia", Usage: "Crédito de agencia: seguro anual obligatorio, por defecto --seguro-anual"}, limitesMonto),
+
+					conLimites(&cli.Float64Flag{Name: "renta", Usage: "Arrendamiento puro: renta mensual"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "deposito", Usage: "Arrendamiento puro: depósito en garantía"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "residual", Usage: "Arrendamiento puro: opción de compra al final en pesos"}, limitesMonto),
+					&cli.BoolFlag{Name: "seguro-en-renta", Usage: "Arrendamiento puro: la renta incluye el seguro"},
+
+					conLimites(&cli.Float64Flag{Name: "tasa-financiero", Usage: "Arrendamiento financiero: tasa anual en decimal"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "pago-inicial-financiero", Value: 0.10, Usage: "Arrendamiento financiero: pago inicial como fracción del precio"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "residual-financiero", Value: 0.10, Usage: "Arrendamiento financiero: opción de compra como fracción del precio"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "comision-arrendamiento", Usage: "Comisión de la arrendadora como fracción del precio"}, limitesFraccion),
+
+					&cli.BoolFlag{Name: "factura", Usage: "Facturas y deduces el auto en tu declaración"},
+					conLimites(&cli.Float64Flag{Name: "tasa-marginal", Value: 0.30, Usage: "Tasa marginal de ISR con que se valúan las deducciones"}, limitesTasaMarginal),
+					conLimites(&cli.Float64Flag{Name: "tasa-oportunidad", Usage: "Tasa anual neta que rinde tu dinero; por defecto la de tu mejor cuenta de débito"}, limitesTasa),
+				},
+				Action: func(c *cli.Context) error {
+					cotizacion, err := cotizacionSimularAuto(c)
+					if err != nil {
+						return err
+					}
+
+					tasaOportunidad, cuenta := c.Float64("tasa-oportunidad"), "capturada"
+					if !c.IsSet("tasa-oportunidad") {
+						tarjetas, err := CargarTarjetas()
+						if err != nil {
+							return fmt.Errorf("Error al cargar tarjetas: %w", err)
+						}
+						if tasaOportunidad, cuenta = MejorTasaDebitoNeta(tarjetas); cuenta == "" {
+							return errDatosInvalidos("No hay cuentas de débito registradas; indica --tasa-oportunidad",
+								"No debit accounts are registered; pass --tasa-oportunidad")
+						}
+					}
+
+					opciones := SimularAuto(cotizacion, tasaOportunidad)
+					if salidaEstructurada() {
+						return emitirDatos(opciones)
+					}
+
+					fmt.Println("\n=== Simulación de Financiamiento del Auto ===")
+					fmt.Printf("Precio: $%.2f | Plazo: %d meses | Valor al final: $%.2f", cotizacion.Precio, cotizacion.Meses, cotizacion.ValorMercadoFinal)
+					if !c.IsSet("valor-final") {
+						fmt.Print(" (estimado)")
+					}
+					fmt.Println()
+					fmt.Printf("Tasa de oportunidad: %.2f%% neta (%s)\n\n", tasaOportunidad*100, cuenta)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Opción\tDesembolso\tAhorro Fiscal\tValor Final\tCosto Real")
+					fmt.Fprintln(w, "------\t----------\t-------------\t-----------\t----------")
+					for _, o := range opciones {
+						fmt.Fprintf(w, "%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n", o.Opcion, o.Desembolso, o.AhorroFiscal, o.ValorFinal, o.CostoReal)
+					}
+					w.Flush()
+
+					if len(opciones) == 1 {
+						fmt.Println("\nAVISO: Solo se simuló el crédito bancario; agrega --tasa-agencia, --renta o --tasa-financiero para comparar")
+						return nil
+					}
+					mejor, siguiente := opciones[0], opciones[1]
+					fmt.Printf("\nRESULTADO: La opción más barata es %s, con un costo real de $%.2f; ahorra $%.2f contra %s\n",
+						mejor.Opcion, mejor.CostoReal, siguiente.CostoReal-mejor.CostoReal, siguiente.Opcion)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// cotizacionSimularAuto lee y valida las banderas de auto simular
+func cotizacionSimularAuto(c *cli.Context) (CotizacionAuto, error) {
+	cotizacion := CotizacionAuto{
+		Precio:                c.Float64("precio"),
+		ValorMercadoFinal:     c.Float64("valor-final"),
+		Meses:                 c.Int("meses"),
+		SeguroAnual:           c.Float64("seguro-anual"),
+		Enganche:              c.Float64("enganche"),
+		TasaCredito:           c.Float64("tasa"),
+		ComisionApertura:      c.Float64("comision-apertura"),
+		RentaMensual:          c.Float64("renta"),
+		Deposito:              c.Float64("deposito"),
+		ValorResidual:         c.Float64("residual"),
+		SeguroEnRenta:         c.Bool("seguro-en-renta"),
+		Factura:               c.Bool("factura"),
+		EngancheAgencia:       c.Float64("enganche-agencia"),
+		TasaAgencia:           c.Float64("tasa-agencia"),
+		ComisionAgencia:       c.Float64("comision-agencia"),
+		MesesAgencia:          c.Int("meses-agencia"),
+		SeguroAgencia:         c.Float64("seguro-agencia"),
+		TasaFinanciero:        c.Float64("tasa-financiero"),
+		PagoInicialFinanciero: c.Float64("pago-inicial-financiero"),
+		ResidualFinanciero:    c.Float64("residual-financiero"),
+		ComisionArrendamiento: c.Float64("comision-arrendamiento"),
+		ConAgencia:            c.IsSet("tasa-agencia"),
+		ConArrendamiento:      c.Float64("renta") > 0,
+		ConFinanciero:         c.IsSet("tasa-financiero"),
+	}
+	if cotizacion.Factura {
+		cotizacion.TasaMarginal = c.Float64("tasa-marginal")
+	}
+
+	if cotizacion.Precio <= 0 {
+		return cotizacion, errDatosInvalidos("El precio del auto debe ser mayor a cero", "The car price must be greater than zero")
+	}
+	if cotizacion.Meses < 1 || cotizacion.Meses > 120 {
+		return cotizacion, errDatosInvalidos("El plazo debe ser de 1 a 120 meses", "The term must be 1 to 120 months")
+	}
+	if cotizacion.MesesAgencia < 0 || cotizacion.MesesAgencia > cotizacion.Meses {
+		return cotizacion, errDatosInvalidos(
+			fmt.Sprintf("El plazo de la agencia debe ser de 1 a %d meses, el plazo a comparar", cotizacion.Meses),
+			fmt.Sprintf("The agency term must be 1 to %d months, the compared term", cotizacion.Meses))
+	}
+	if !c.IsSet("valor-final") {
+		cotizacion.ValorMercadoFinal = cotizacion.Precio * math.Pow(1-DEPRECIACION_ANUAL_AUTO, float64(cotizacion.Meses)/12)
+	}
+	return cotizacion, nil
+}
+
+// leerCotizacionAuto pide los datos de las tres formas de adquirir el auto
+func leerCotizacionAuto() (CotizacionAuto, error) {
+	var c CotizacionAuto
+	var err error
+
+	if c.Precio, err = leerNumero("Precio de contado del auto: ", limitesMonto); err != nil {
+		return c, err
+	}
+	if c.Meses, err = leerEntero("Plazo a comparar en meses: ", 1, 120); err != nil {
+		return c, err
+	}
+	if c.ValorMercadoFinal, err = leerNumero("Valor estimado del auto al terminar el plazo: ", limitesMonto); err != nil {
+		return c, err
+	}
+	if c.SeguroAnual, err = leerNumero("Seguro anual: ", limitesMonto); err != nil {
+		return c, err
+	}
+
+	fmt.Println("\n-- Crédito automotriz --")
+	if c.Enganche, err = leerNumero("Enganche (decimal, ej. 0.20): ", limitesFraccion); err != nil {
+		return c, err
+	}
+	if c.TasaCredito, err = leerNumero("Tasa de interés anual (decimal): ", limitesTasa); err != nil {
+		return c, err
+	}
+	if c.ComisionApertura, err = leerNumero("Comisión por apertura (decimal): ", limitesFraccion); err != nil {
+		return c, err
+	}
+
+	fmt.Println("\n-- Arrendamiento puro --")
+	if c.RentaMensual, err = leerNumero("Renta mensual: ", limitesMonto); err != nil {
+		return c, err
+	}
+	if c.Deposito, err = leerNumero("Depósito en garantía: ", limitesMonto); err != nil {
+		return c, err
+	}
+	if c.ValorResidual, err = leerNumero("Valor residual (opción de compra al final): ", limitesMonto); err != nil {
+		return c, err
+	}
+	if c.SeguroEnRenta, err = leerSiNo("¿El seguro está incluido en la renta? (s/n): "); err != nil {
+		return c, err
+	}
+
+	fmt.Println()
+	if c.Factura, err = leerSiNo("¿Facturas y deduces el auto en tu declaración? (s/n): "); err != nil {
+		return c, err
+	}
+	if c.Factura {
+		if c.TasaMarginal, err = leerNumero("Tasa marginal de ISR (decimal): ", limitesTasaMarginal); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"finmex/banxico"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoBanxico muestra los indicadores de referencia publicados por Banxico
+func comandoBanxico() *cli.Command {
+	return &cli.Command{
+		Name:  "banxico",
+		Usage: "Consultar inflación, TIIE, CETES, tipo de cambio FIX y valor de la UDI en el SIE de Banxico",
+		Description: "Requiere un token del SIE en FINMEX_BANXICO_TOKEN (se obtiene gratis en banxico.org.mx).\n" +
+			"Los datos se guardan en " + ARCHIVO_CACHE_BANXICO + " y se reutilizan por un día.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "actualizar", Usage: "Consultar el SIE aunque la copia local siga vigente"},
+		},
+		Action: func(c *cli.Context) error {
+			cliente := clienteBanxico()
+			if c.Bool("actualizar") {
+				cliente.Vigencia = 0
+			}
+
+			var series []string
+			for _, ind := range indicadoresBanxico {
+				series = append(series, ind.Serie)
+			}
+			datos, err := cliente.Oportuno(series...)
+			if err != nil {
+				if len(datos) == 0 {
+					if errors.Is(err, banxico.ErrSinToken) {
+						return fmt.Errorf("%v: define FINMEX_BANXICO_TOKEN para consultar los indicadores", err)
+					}
+					return err
+				}
+				fmt.Printf("AVISO: %v; se muestran los últimos datos guardados\n", err)
+			}
+
+			porSerie := map[string]banxico.Dato{}
+			for _, d := range datos {
+				porSerie[d.Serie] = d
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Indicador\tValor\tFecha\tSerie\tConsultado")
+			fmt.Fprintln(w, "---------\t-----\t-----\t-----\t----------")
+			for _, ind := range indicadoresBanxico {
+				d, ok := porSerie[ind.Serie]
+				if !ok {
+					fmt.Fprintf(w, "%s\tsin dato\t-\t%s\t-\n", ind.Descripcion, ind.Serie)
+					continue
+				}
+				valor := fmt.Sprintf("$%.4f", d.Valor)
+				if ind.Porcentaje {
+					valor = fmt.Sprintf("%.2f%%", d.Valor)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ind.Descripcion, valor, d.Fecha, d.Serie, d.Consultado.Format("2006-01-02 15:04"))
+			}
+			w.Flush()
+
+			fmt.Printf("\nLos cálculos de rendimiento real usan una inflación de %.2f%%\n", InflacionVigente()*100)
+			return nil
+		},
+	}
+}
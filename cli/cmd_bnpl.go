@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoBNPL agrupa las operaciones con compras a pagos BNPL
+func comandoBNPL() *cli.Command {
+	return &cli.Command{
+		Name:  "bnpl",
+		Usage: "Compras a pagos con Aplazo, Kueski Pay y similares",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar una compra a pagos",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var b CompraBNPL
+
+					if b.Nombre, err = leerTextoRequerido("Descripción de la compra: "); err != nil {
+						return err
+					}
+					if b.Proveedor, err = leerTextoRequerido("Proveedor (Aplazo, Kueski Pay, etc.): "); err != nil {
+						return err
+					}
+					if b.Monto, err = leerNumero("Precio de contado: ", limitesMonto); err != nil {
+						return err
+					}
+					if b.Pagos, err = leerEntero("Número de pagos: ", 1, 104); err != nil {
+						return err
+					}
+
+					texto, err := leerTexto("Frecuencia de pago (quincenal, catorcenal, semanal, mensual) [quincenal]: ")
+					if err != nil {
+						return err
+					}
+					if texto == "" {
+						texto = string(FrecuenciaQuincenal)
+					}
+					if b.Frecuencia, err = calc.ParsearFrecuencia(texto); err != nil {
+						return err
+					}
+
+					if b.TasaInteres, err = leerNumero("Tasa de interés anual (decimal, 0 si es sin intereses): ", limitesTasa); err != nil {
+						return err
+					}
+					if b.Comision, err = leerNumero("Comisiones totales en pesos (0 si no cobra): ", limitesMonto); err != nil {
+						return err
+					}
+					if b.PrimerPagoHoy, err = leerSiNo("¿El primer pago se cobra el día de la compra? (s/n): "); err != nil {
+						return err
+					}
+
+					if b.FechaCompra, err = leerTexto("Fecha de compra (AAAA-MM-DD) [hoy]: "); err != nil {
+						return err
+					}
+					if b.FechaCompra == "" {
+						b.FechaCompra = time.Now().Format("2006-01-02")
+					}
+					if _, err := b.FechasPago(); err != nil {
+						return err
+					}
+
+					tarjetas.BNPL = append(tarjetas.BNPL, b)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar compra: %w", err)
+					}
+
+					fmt.Printf("Compra '%s' registrada: %d pagos %ses de $%.2f\n", b.Nombre, b.Pagos, b.frecuencia(), b.Pago())
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar compras a pagos registradas",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if salidaEstructurada() {
+						filas := []FilaBNPL{}
+						for _, b := range tarjetas.BNPL {
+							fila, err := filaBNPL(b)
+							if err != nil {
+								return err
+							}
+							filas = append(filas, fila)
+						}
+						return emitirDatos(filas)
+					}
+
+					if len(tarjetas.BNPL) == 0 {
+						fmt.Println("No hay compras a pagos registradas")
+						return nil
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Compra\tProveedor\tPrecio\tPagos\tPago\tCosto Extra\tTasa Efectiva\tÚltimo Pago")
+					fmt.Fprintln(w, "------\t---------\t------\t-----\t----\t-----------\t-------------\t-----------")
+
+					for _, b := range tarjetas.BNPL {
+						fechas, err := b.FechasPago()
+						if err != nil {
+							return err
+						}
+						ultimo := "-"
+						if len(fechas) > 0 {
+							ultimo = fechas[len(fechas)-1].Format("2006-01-02")
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%d %s\t$%.2f\t$%.2f\t%.1f%%\t%s\n",
+							b.Nombre, b.Proveedor, b.Monto, b.Pagos, b.frecuencia(), b.Pago(),
+							b.CostoTotal(), b.TasaEfectiva()*100, ultimo)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:  "comparar",
+				Usage: "Comparar una compra BNPL contra pagarla a meses sin intereses con tarjeta",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.BNPL) == 0 {
+						return fmt.Errorf("No hay compras a pagos registradas")
+					}
+
+					var nombres []string
+					for _, b := range tarjetas.BNPL {
+						nombres = append(nombres, fmt.Sprintf("%s (%s)", b.Nombre, b.Proveedor))
+					}
+					seleccion, err := elegirOpcion("Compras registradas:", nombres)
+					if err != nil {
+						return err
+					}
+					b := tarjetas.BNPL[seleccion]
+
+					meses, err := leerEntero("Meses sin intereses disponibles con tu tarjeta para la misma compra: ", 1, 48)
+					if err != nil {
+						return err
+					}
+
+					tasaOportunidad, cuenta := MejorTasaDebitoNeta(tarjetas)
+					if cuenta == "" {
+						if tasaOportunidad, err = leerNumero("Tasa anual que rinde tu dinero mientras no pagas (decimal): ", limitesTasa); err != nil {
+							return err
+						}
+						cuenta = "capturada"
+					}
+
+					vpBNPL := b.ValorPresente(tasaOportunidad)
+					vpMSI := ValorPresenteMSI(b.Monto, meses, tasaOportunidad)
+					if salidaEstructurada() {
+						type opcion struct {
+							Opcion        string     `json:"opcion"`
+							Pagos         int        `json:"pagos"`
+							Frecuencia    Frecuencia `json:"frecuencia"`
+							Pago          float64    `json:"pago"`
+							TotalPagado   float64    `json:"total_pagado"`
+							TasaEfectiva  float64    `json:"tasa_efectiva"`
+							ValorPresente float64    `json:"valor_presente"`
+						}
+						return emitirDatos([]opcion{
+							{b.Proveedor, b.Pagos, b.frecuencia(), b.Pago(), b.Monto + b.CostoTotal(), b.TasaEfectiva(), vpBNPL},
+							{fmt.Sprintf("Tarjeta a %d MSI", meses), meses, FrecuenciaMensual, b.Monto / float64(meses), b.Monto, 0, vpMSI},
+						})
+					}
+
+					fmt.Println("\n=== BNPL vs Meses Sin Intereses ===")
+					fmt.Printf("Compra: %s (precio de contado $%.2f)\n", b.Nombre, b.Monto)
+					fmt.Printf("Tasa de oportunidad: %.2f%% neta (%s)\n\n", tasaOportunidad*100, cuenta)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Opción\tPagos\tPago\tTotal Pagado\tTasa Efectiva\tValor Presente")
+					fmt.Fprintln(w, "------\t-----\t----\t------------\t-------------\t--------------")
+					fmt.Fprintf(w, "%s\t%d %s\t$%.2f\t$%.2f\t%.1f%%\t$%.2f\n", b.Proveedor, b.Pagos, b.frecuencia(),
+						b.Pago(), b.Monto+b.CostoTotal(), b.TasaEfectiva()*100, vpBNPL)
+					fmt.Fprintf(w, "Tarjeta a %d MSI\t%d mensual\t$%.2f\t$%.2f\t0.0%%\t$%.2f\n", meses, meses,
+						b.Monto/float64(meses), b.Monto, vpMSI)
+					w.Flush()
+
+					if vpMSI < vpBNPL {
+						fmt.Printf("\nRESULTADO: Pagar a %d MSI con tarjeta te cuesta $%.2f menos en valor presente\n", meses, vpBNPL-vpMSI)
+					} else {
+						fmt.Printf("\nRESULTADO: %s te cuesta $%.2f menos en valor presente\n", b.Proveedor, vpMSI-vpBNPL)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
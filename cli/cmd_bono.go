@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoBono agrupa el seguimiento de bonos de bienvenida de tarjetas de crédito
+func comandoBono() *cli.Command {
+	return &cli.Command{
+		Name:  "bono",
+		Usage: "Bonos de bienvenida: requisito de gasto, avance y valuación",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar el bono de bienvenida de una tarjeta",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var b BonoBienvenida
+					if b.Tarjeta, err = leerTextoRequerido("Tarjeta de crédito: "); err != nil {
+						return err
+					}
+					if _, ok := indiceCredito(tarjetas, b.Tarjeta); !ok {
+						return errTarjetaNoEncontrada("credito", b.Tarjeta)
+					}
+					if b.Recompensa, err = leerNumero("Valor de la recompensa en pesos: ", limitesMonto); err != nil {
+						return err
+					}
+					if b.GastoMinimo, err = leerNumero("Gasto mínimo requerido: ", limitesMonto); err != nil {
+						return err
+					}
+					if b.Inicio, err = leerTextoRequerido("Fecha desde la que cuenta el gasto (AAAA-MM-DD): "); err != nil {
+						return err
+					}
+					if b.FechaLimite, err = leerTextoRequerido("Fecha límite para cumplir (AAAA-MM-DD): "); err != nil {
+						return err
+					}
+					if _, _, err := b.fechas(); err != nil {
+						return err
+					}
+
+					tarjetas.Bonos = append(tarjetas.Bonos, b)
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar bono: %w", err)
+					}
+					fmt.Printf("Bono de %s registrado: gasta $%.2f antes del %s para ganar $%.2f\n", b.Tarjeta, b.GastoMinimo, b.FechaLimite, b.Recompensa)
+					return nil
+				},
+			},
+			{
+				Name:  "estado",
+				Usage: "Ver el avance de los bonos activos y si conviene perseguirlos",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "gasto-mensual", Usage: "Gasto mensual habitual; por defecto el promedio de los movimientos de cada tarjeta"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if len(tarjetas.Bonos) == 0 {
+						fmt.Println("No hay bonos de bienvenida registrados")
+						return nil
+					}
+
+					hoy := time.Now().Truncate(24 * time.Hour)
+					gastoAnual, err := GastoAnualPorTarjeta(hoy)
+					if err != nil {
+						return fmt.Errorf("Error al leer movimientos: %w", err)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Tarjeta\tRecompensa\tGastado\tFalta\tDías\tRitmo Necesario\tValor Neto\tConviene")
+					fmt.Fprintln(w, "-------\t----------\t-------\t-----\t----\t---------------\t----------\t--------")
+
+					var avisos []string
+					ganado := 0.0
+					for _, b := range tarjetas.Bonos {
+						if b.Cobrado {
+							ganado += b.Recompensa
+							continue
+						}
+						i, ok := indiceCredito(tarjetas, b.Tarjeta)
+						if !ok {
+							return fmt.Errorf("El bono hace referencia a la tarjeta '%s', que ya no está registrada", b.Tarjeta)
+						}
+						inicio, limite, err := b.fechas()
+						if err != nil {
+							return err
+						}
+						gastado, err := GastoTarjetaEntre(b.Tarjeta, inicio, limite)
+						if err != nil {
+							return fmt.Errorf("Error al leer movimientos: %w", err)
+						}
+						normal := gastoAnual[normalizarClave(b.Tarjeta)] / 12
+						if c.IsSet("gasto-mensual") {
+							normal = c.Float64("gasto-mensual")
+						}
+
+						p, err := CalcularProgresoBono(b, tarjetas.Credito[i], gastado, normal, hoy)
+						if err != nil {
+							return err
+						}
+
+						conviene := "No"
+						switch {
+						case p.Cumplido:
+							conviene = "Cumplido"
+							avisos = append(avisos, fmt.Sprintf("%s: requisito cumplido; marca el bono con 'bono cobrar %s' al recibirlo", b.Tarjeta, b.Tarjeta))
+						case p.Vencido:
+							conviene = "Vencido"
+						case p.Conviene:
+							conviene = "Sí"
+							avisos = append(avisos, fmt.Sprintf("%s: te faltan $%.2f en %d días ($%.2f al mes)", b.Tarjeta, p.Faltante, p.DiasRestantes, p.RitmoNecesario))
+						case p.ValorNeto <= 0:
+							avisos = append(avisos, fmt.Sprintf("%s: la recompensa no cubre la anualidad de $%.2f", b.Tarjeta, p.Anualidad))
+						default:
+							avisos = append(avisos, fmt.Sprintf("%s: con tu gasto habitual de $%.2f al mes tendrías que gastar $%.2f de más", b.Tarjeta, p.GastoNormal, p.GastoExtra))
+						}
+
+						fmt.Fprintf(w, "%s\t$%.2f\t$%.2f\t$%.2f\t%d\t$%.2f\t$%.2f\t%s\n",
+							b.Tarjeta, b.Recompensa, p.Gastado, p.Faltante, p.DiasRestantes, p.RitmoNecesario, p.ValorNeto, conviene)
+					}
+					w.Flush()
+
+					for _, a := range avisos {
+						fmt.Println("- " + a)
+					}
+					if ganado > 0 {
+						fmt.Printf("\nBonos cobrados hasta ahora: $%.2f\n", ganado)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "cobrar",
+				Usage:     "Marcar como cobrado el bono activo de una tarjeta",
+				ArgsUsage: "<tarjeta>",
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return fmt.Errorf("Indica la tarjeta, p. ej. bono cobrar Oro")
+					}
+					nombre := argumentoNombre(c.Args().Slice())
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					for i, b := range tarjetas.Bonos {
+						if !b.Cobrado && normalizarClave(b.Tarjeta) == normalizarClave(nombre) {
+							tarjetas.Bonos[i].Cobrado = true
+							if err := GuardarTarjetas(tarjetas); err != nil {
+								return fmt.Errorf("Error al guardar bono: %w", err)
+							}
+							fmt.Printf("Bono de %s marcado como cobrado ($%.2f)\n", b.Tarjeta, b.Recompensa)
+							return nil
+						}
+					}
+					return fmt.Errorf("No hay un bono activo para la tarjeta '%s'", nombre)
+				},
+			},
+		},
+	}
+}
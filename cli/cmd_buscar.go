@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoBuscar busca tarjetas de débito y crédito por nombre o banco
+func comandoBuscar() *cli.Command {
+	return &cli.Command{
+		Name:      "buscar",
+		Usage:     "Buscar tarjetas registradas por nombre o banco",
+		ArgsUsage: "[nombre]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "banco", Usage: "Mostrar solo tarjetas de este banco"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			texto := strings.Join(c.Args().Slice(), " ")
+			banco := c.String("banco")
+			if texto == "" && banco == "" {
+				return fmt.Errorf("Indica un nombre o usa --banco")
+			}
+
+			indice := NuevoIndiceTarjetas(tarjetas)
+			debito := candidatosBusqueda(len(tarjetas.Debito), texto, banco, indice.Debito, indice.DebitoPorBanco,
+				func(i int) string { return tarjetas.Debito[i].Nombre })
+			credito := candidatosBusqueda(len(tarjetas.Credito), texto, banco, indice.Credito, indice.CreditoPorBanco,
+				func(i int) string { return tarjetas.Credito[i].Nombre })
+
+			if len(debito) == 0 && len(credito) == 0 {
+				fmt.Println("No se encontraron tarjetas")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Tipo\tNombre\tBanco\tTasa")
+			fmt.Fprintln(w, "----\t------\t-----\t----")
+
+			for _, i := range debito {
+				t := tarjetas.Debito[i]
+				fmt.Fprintf(w, "Débito\t%s\t%s\t%.2f%%\n", t.Nombre, t.Banco, t.TasaPonderada(t.Saldo)*100)
+			}
+			for _, i := range credito {
+				t := tarjetas.Credito[i]
+				fmt.Fprintf(w, "Crédito\t%s\t%s\t%.2f%%\n", t.Nombre, t.Banco, t.TasaInteres*100)
+			}
+
+			w.Flush()
+			return nil
+		},
+	}
+}
+
+// candidatosBusqueda usa el índice para resolver coincidencias exactas de nombre y de banco;
+// si el nombre no coincide exactamente se buscan nombres que lo contengan
+func candidatosBusqueda(total int, texto, banco string, porNombre func(string) (int, bool),
+	porBanco func(string) []int, nombre func(int) string) []int {
+
+	var posiciones []int
+	if banco != "" {
+		posiciones = porBanco(banco)
+	} else {
+		for i := 0; i < total; i++ {
+			posiciones = append(posiciones, i)
+		}
+	}
+
+	if texto == "" {
+		return posiciones
+	}
+
+	if i, ok := porNombre(texto); ok {
+		for _, p := range posiciones {
+			if p == i {
+				return []int{i}
+			}
+		}
+	}
+
+	var resultado []int
+	buscado := normalizarClave(texto)
+	for _, p := range posiciones {
+		if strings.Contains(normalizarClave(nombre(p)), buscado) {
+			resultado = append(resultado, p)
+		}
+	}
+	return resultado
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCaja agrupa las operaciones con cajas de ahorro y cooperativas
+func comandoCaja() *cli.Command {
+	return &cli.Command{
+		Name:  "caja",
+		Usage: "Operaciones con cajas de ahorro y cooperativas (SOCAPs)",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Agregar una caja de ahorro",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var caja CajaAhorro
+
+					if caja.Nombre, err = leerTextoRequerido("Nombre de la caja: "); err != nil {
+						return err
+					}
+					if caja.Entidad, err = leerTextoRequerido("Cooperativa o entidad: "); err != nil {
+						return err
+					}
+					if caja.ParteSocial, err = leerNumero("Parte social (aportación obligatoria): ", limitesMonto); err != nil {
+						return err
+					}
+					if caja.Ahorro, err = leerNumero("Saldo ahorrado además de la parte social: ", limitesMonto); err != nil {
+						return err
+					}
+					if caja.TasaRendimiento, err = leerNumero("Tasa de rendimiento anual del ahorro (decimal): ", limitesTasa); err != nil {
+						return err
+					}
+					if caja.TasaPrestamo, err = leerNumero("Tasa anual de préstamos a socios (decimal): ", limitesTasa); err != nil {
+						return err
+					}
+					if caja.MultiploPrestamo, err = leerNumero("Veces tu ahorro que te prestan (ej. 3): ", LimitesNumero{Min: 0, Max: 100}); err != nil {
+						return err
+					}
+					if caja.ComisionApertura, err = leerNumero("Comisión por apertura de préstamo (decimal): ", limitesFraccion); err != nil {
+						return err
+					}
+					if caja.SaldoPrestamo, err = leerNumero("Saldo de préstamo vigente con la caja (0 si no tienes): ", limitesMonto); err != nil {
+						return err
+					}
+
+					tarjetas.Cajas = append(tarjetas.Cajas, caja)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar caja: %w", err)
+					}
+
+					fmt.Printf("Caja '%s' agregada exitosamente\n", caja.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar cajas de ahorro registradas",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if salidaEstructurada() {
+						filas := []FilaCaja{}
+						for _, caja := range tarjetas.Cajas {
+							filas = append(filas, FilaCaja{caja, caja.PrestamoMaximo()})
+						}
+						return emitirDatos(filas)
+					}
+
+					if len(tarjetas.Cajas) == 0 {
+						fmt.Println("No hay cajas de ahorro registradas")
+						return nil
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tEntidad\tParte Social\tAhorro\tRendimiento\tTasa Préstamo\tPréstamo Máximo\tSaldo Préstamo")
+					fmt.Fprintln(w, "------\t-------\t------------\t------\t-----------\t-------------\t---------------\t--------------")
+
+					for _, caja := range tarjetas.Cajas {
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t%.2f%%\t%.2f%%\t$%.2f\t$%.2f\n",
+							caja.Nombre, caja.Entidad, caja.ParteSocial, caja.Ahorro,
+							caja.TasaRendimiento*100, caja.TasaPrestamo*100,
+							caja.PrestamoMaximo(), caja.SaldoPrestamo)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+
+	"finmex/calc"
+	"finmex/calculadora"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCalcular hace cálculos rápidos con datos dados por flags, sin leer ni crear el
+// archivo de tarjetas
+func comandoCalcular() *cli.Command {
+	return &cli.Command{
+		Name:  "calcular",
+		Usage: "Cálculos rápidos sin tarjetas registradas",
+		Subcommands: append([]*cli.Command{
+			{
+				Name:  "credito",
+				Usage: "Costo de una deuda con la tasa y el pago dados",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "tasa", Required: true, Usage: "Tasa de interés anual (decimal)"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "deuda", Required: true, Usage: "Monto de la deuda"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "pago", Required: true, Usage: "Pago en cada periodo"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "comision", Usage: "Comisión anual"}, limitesMonto),
+					&cli.StringFlag{Name: "frecuencia", Value: "mensual", Usage: "Frecuencia de pago: mensual, quincenal, catorcenal o semanal"},
+				},
+				Action: func(c *cli.Context) error {
+					frecuencia, err := calc.ParsearFrecuencia(c.String("frecuencia"))
+					if err != nil {
+						return err
+					}
+
+					entrada := calc.EntradaCredito{
+						Deuda: c.Float64("deuda"), TasaAnual: c.Float64("tasa"), ComisionAnual: c.Float64("comision"),
+						Pago: c.Float64("pago"), Frecuencia: frecuencia,
+					}
+					r, err := calc.SimularCredito(entrada)
+					if err != nil {
+						return errDatosInvalidos(err.Error(), err.Error())
+					}
+					if r.PagoAplicado > entrada.Pago {
+						fmt.Printf("AVISO: El pago es menor al pago mínimo. Se ajustará a $%.2f\n", r.PagoAplicado)
+					}
+
+					fmt.Println("=== Cálculo de Crédito ===")
+					fmt.Printf("Deuda: $%.2f al %.2f%% anual\n", entrada.Deuda, entrada.TasaAnual*100)
+					fmt.Printf("Pago %s: $%.2f\n", frecuencia, r.PagoAplicado)
+					fmt.Printf("Tiempo para liquidar: %d pagos (%.1f meses)\n", r.Pagos, r.Meses)
+					fmt.Printf("Costo total del crédito: $%.2f (%.2f%% del monto original)\n", r.CostoTotal, r.CostoPorcentaje)
+					fmt.Printf("Monto total pagado: $%.2f\n", r.TotalPagado)
+					return nil
+				},
+			},
+			{
+				Name:  "debito",
+				Usage: "Rendimiento real de un saldo con la tasa dada",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "tasa", Required: true, Usage: "Tasa de rendimiento anual (decimal)"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "saldo", Required: true, Usage: "Saldo promedio"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "comision", Usage: "Comisión anual"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "saldo-minimo", Usage: "Saldo mínimo para generar rendimiento"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					tasa := c.Float64("tasa")
+					isr := ISRVigente()
+					entrada := calc.EntradaRendimiento{
+						Saldo: c.Float64("saldo"), Tramos: calc.TasaUnica(tasa), ComisionAnual: c.Float64("comision"),
+						SaldoMinimo: c.Float64("saldo-minimo"), Inflacion: InflacionVigente(),
+						RegimenISR: isr.Regimen, AñoFiscal: isr.AñoFiscal, RetencionISR: isr.Retencion, TasaMarginal: isr.TasaMarginal,
+					}
+					r, err := calc.SimularRendimiento(entrada)
+					if err != nil {
+						return errDatosInvalidos(err.Error(), err.Error())
+					}
+
+					fmt.Println("=== Cálculo de Rendimiento ===")
+					fmt.Printf("Saldo: $%.2f al %.2f%% anual\n", entrada.Saldo, tasa*100)
+					fmt.Printf("Rendimiento bruto anual: $%.2f\n", r.Bruto)
+					fmt.Printf("Impuestos (%s): $%.2f\n", isr.Descripcion(), r.Impuestos)
+					fmt.Printf("Pérdida por inflación (%.1f%%): $%.2f\n", entrada.Inflacion*100, r.PerdidaInflacion)
+					fmt.Printf("Rendimiento real anual: $%.2f (%.2f%%)\n", r.Real, r.RealPorcentaje)
+					cuenta := TarjetaDebito{Tramos: entrada.Tramos, ComisionAnual: entrada.ComisionAnual, SaldoMinimo: entrada.SaldoMinimo}
+					if equilibrio, ok := calc.SaldoEquilibrio(cuenta, entrada.Inflacion, isr); ok {
+						fmt.Printf("Saldo de equilibrio: $%.2f\n", equilibrio)
+					}
+					fmt.Printf("Saldo real después de un año: $%.2f\n", r.SaldoFinal)
+					return nil
+				},
+			},
+		}, comandosCalculadoras()...),
+	}
+}
+
+// comandosCalculadoras crea un subcomando por cada calculadora registrada en el paquete
+// calculadora, con un flag por parámetro
+func comandosCalculadoras() []*cli.Command {
+	var comandos []*cli.Command
+	for _, calc := range calculadora.Registradas() {
+		calc := calc
+		var flags []cli.Flag
+		for _, p := range calc.Parametros() {
+			flags = append(flags, &cli.Float64Flag{Name: p.Nombre, Value: p.Predeterminado, Required: p.Requerido, Usage: p.Descripcion})
+		}
+
+		comandos = append(comandos, &cli.Command{
+			Name:  calc.Nombre(),
+			Usage: calc.Descripcion(),
+			Flags: flags,
+			Action: func(c *cli.Context) error {
+				valores := map[string]float64{}
+				for _, p := range calc.Parametros() {
+					valor := c.Float64(p.Nombre)
+					if p.Tipo == calculadora.Monto {
+						if err := validarLimites(valor, limitesMonto); err != nil {
+							return fmt.Errorf("--%s: %w", p.Nombre, err)
+						}
+					}
+					valores[p.Nombre] = valor
+				}
+
+				resultados, err := calc.Calcular(valores)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("=== %s ===\n", calc.Descripcion())
+				for _, r := range resultados {
+					fmt.Printf("%s: %s\n", r.Concepto, formatearValorCalculadora(r.Valor, r.Tipo))
+				}
+				return nil
+			},
+		})
+	}
+	return comandos
+}
+
+// formatearValorCalculadora muestra un resultado según su tipo
+func formatearValorCalculadora(valor float64, tipo calculadora.TipoValor) string {
+	switch tipo {
+	case calculadora.Monto:
+		return fmt.Sprintf("$%.2f", valor)
+	case calculadora.Tasa:
+		return fmt.Sprintf("%.2f%%", valor*100)
+	case calculadora.Periodos:
+		return fmt.Sprintf("%.0f", valor)
+	}
+	return fmt.Sprintf("%g", valor)
+}
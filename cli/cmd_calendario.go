@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCalendario muestra los pagos programados de todos los productos registrados
+func comandoCalendario() *cli.Command {
+	return &cli.Command{
+		Name:  "calendario",
+		Usage: "Mostrar los próximos pagos programados",
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "dias", Value: 60, Usage: "Días hacia adelante a mostrar"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			hoy := time.Now().Truncate(24 * time.Hour)
+			hasta := hoy.AddDate(0, 0, c.Int("dias"))
+
+			pagos, err := PagosProgramados(tarjetas, hoy, hasta)
+			if err != nil {
+				return err
+			}
+
+			if len(pagos) == 0 {
+				fmt.Printf("No hay pagos programados en los próximos %d días\n", c.Int("dias"))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Fecha\tTipo\tConcepto\tMonto")
+			fmt.Fprintln(w, "-----\t----\t--------\t-----")
+
+			pagar, cobrar := 0.0, 0.0
+			for _, p := range pagos {
+				signo := ""
+				if p.Cobro {
+					signo = "+"
+					cobrar += p.Monto
+				} else {
+					pagar += p.Monto
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s$%.2f\n", p.Fecha.Format("2006-01-02"), p.Tipo, p.Concepto, signo, p.Monto)
+			}
+
+			w.Flush()
+			fmt.Printf("\nTotal a pagar en los próximos %d días: $%.2f\n", c.Int("dias"), pagar)
+			if cobrar > 0 {
+				fmt.Printf("Total por cobrar: $%.2f\n", cobrar)
+				fmt.Printf("Flujo neto: $%.2f\n", cobrar-pagar)
+			}
+
+			if len(tarjetas.Monederos) > 0 {
+				faltante := pagar - cobrar
+				liquidez := LiquidezMonederos(tarjetas.Monederos, hoy)
+				fmt.Printf("Saldo disponible en monederos: $%.2f", liquidez)
+				if liquidez >= faltante {
+					fmt.Println(" (cubre todos los pagos)")
+				} else {
+					fmt.Printf(" (faltan $%.2f)\n", faltante-liquidez)
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCreditoCancelar aconseja cuándo cancelar una tarjeta de crédito
+func comandoCreditoCancelar() *cli.Command {
+	return &cli.Command{
+		Name:  "cancelar",
+		Usage: "Saber cuándo conviene cancelar una tarjeta de crédito",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tarjeta", Required: true, Usage: "Nombre de la tarjeta"},
+			&cli.StringFlag{Name: "renovacion", Usage: "Fecha del próximo cobro de anualidad (AAAA-MM-DD); se guarda en la tarjeta"},
+			conLimites(&cli.Float64Flag{Name: "beneficios", Usage: "Valor de los beneficios ya pagados que faltan por usar"}, limitesMonto),
+			&cli.StringFlag{Name: "beneficios-hasta", Usage: "Fecha en que terminas de usar esos beneficios (AAAA-MM-DD)"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, ok := indiceCredito(tarjetas, c.String("tarjeta"))
+			if !ok {
+				return errTarjetaNoEncontrada("credito", c.String("tarjeta"))
+			}
+
+			if c.String("renovacion") != "" {
+				tarjetas.Credito[i].FechaAnualidad = c.String("renovacion")
+			}
+			tarjeta := tarjetas.Credito[i]
+			if tarjeta.FechaAnualidad == "" {
+				return fmt.Errorf("Indica con --renovacion la fecha del próximo cobro de anualidad de %s", tarjeta.Nombre)
+			}
+			renovacion, err := time.Parse("2006-01-02", tarjeta.FechaAnualidad)
+			if err != nil {
+				return fmt.Errorf("Fecha de renovación inválida: %s (usa AAAA-MM-DD)", tarjeta.FechaAnualidad)
+			}
+
+			var beneficiosHasta time.Time
+			if c.String("beneficios-hasta") != "" {
+				if beneficiosHasta, err = time.Parse("2006-01-02", c.String("beneficios-hasta")); err != nil {
+					return fmt.Errorf("Fecha inválida: %s (usa AAAA-MM-DD)", c.String("beneficios-hasta"))
+				}
+			}
+
+			hoy := time.Now().Truncate(24 * time.Hour)
+			a, err := AsesorarCancelacion(tarjeta, hoy, renovacion, c.Float64("beneficios"), beneficiosHasta)
+			if err != nil {
+				return err
+			}
+
+			if c.String("renovacion") != "" {
+				if err := GuardarTarjetas(tarjetas); err != nil {
+					return fmt.Errorf("Error al guardar tarjeta: %w", err)
+				}
+			}
+
+			fmt.Printf("=== Cancelación de %s ===\n", tarjeta.Nombre)
+			fmt.Printf("Próximo cobro de anualidad: %s ($%.2f)\n", a.Renovacion.Format("2006-01-02"), a.Anualidad)
+			if a.Revolvente > 0 {
+				fmt.Printf("Saldo revolvente por liquidar: $%.2f\n", a.Revolvente)
+			}
+			if a.MSIPendiente > 0 {
+				fmt.Printf("MSI pendientes: $%.2f (última mensualidad a más tardar %s)\n", a.MSIPendiente, a.FinMSI.Format("2006-01-02"))
+			}
+			if a.Beneficios > 0 {
+				fmt.Printf("Beneficios pagados sin usar: $%.2f", a.Beneficios)
+				if !a.BeneficiosHasta.IsZero() {
+					fmt.Printf(" (hasta %s)", a.BeneficiosHasta.Format("2006-01-02"))
+				}
+				fmt.Println()
+			}
+
+			switch {
+			case a.AntesRenovacion:
+				fmt.Printf("RESULTADO: Cancela entre el %s y el %s, antes del cobro de la anualidad\n",
+					a.Desde.Format("2006-01-02"), a.Renovacion.AddDate(0, 0, -1).Format("2006-01-02"))
+			case a.Esperar:
+				fmt.Printf("RESULTADO: Conviene pagar la anualidad y cancelar a partir del %s: los beneficios sin usar ($%.2f) valen más que la anualidad\n",
+					a.Desde.Format("2006-01-02"), a.Beneficios)
+			default:
+				fmt.Printf("RESULTADO: Cancela antes del %s; la anualidad ($%.2f) vale más que lo que dejas de usar\n",
+					a.Renovacion.Format("2006-01-02"), a.Anualidad)
+				if a.MSIPendiente > 0 {
+					fmt.Printf("Al cancelar tendrás que liquidar de golpe los $%.2f de MSI pendientes\n", a.MSIPendiente)
+				}
+			}
+			if a.Revolvente > 0 {
+				fmt.Printf("Antes de cancelar liquida los $%.2f de saldo revolvente y pide tu carta de no adeudo\n", a.Revolvente)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCreditoCashback estima el cashback anual de las tarjetas de crédito con el gasto
+// por categoría y permite registrar el programa de bonificación de cada una
+func comandoCreditoCashback() *cli.Command {
+	return &cli.Command{
+		Name:      "cashback",
+		Usage:     "Estimar el beneficio anual del cashback con tu gasto por categoría",
+		ArgsUsage: "[tarjeta]",
+		Description: "Cada categoría usa su tasa o la general de la tarjeta, la bonificación se limita al tope\n" +
+			"mensual y los puntos se valúan a su valor de canje. Categorías: " + strings.Join(CategoriasGasto, ", ") + ".\n" +
+			"Ejemplo: finmex credito cashback --gasto \"supermercado=6000,gasolina=2500,otros=4000\"",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "gasto", Usage: "Gasto mensual por categoría sin preguntar, ej: \"supermercado=6000,otros=4000\""},
+		},
+		Subcommands: []*cli.Command{
+			comandoConfigurarCashback(),
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			if len(tarjetas.Credito) == 0 {
+				return fmt.Errorf("No hay tarjetas de crédito registradas")
+			}
+			evaluadas := tarjetas.Credito
+			if c.NArg() > 0 {
+				i, err := seleccionarTarjeta(c, nombresCredito(tarjetas), "credito", "tarjetas de crédito")
+				if err != nil {
+					return err
+				}
+				evaluadas = tarjetas.Credito[i : i+1]
+			}
+
+			var gasto map[string]float64
+			if c.IsSet("gasto") {
+				gasto, err = parsearPorCategoria("gasto", c.String("gasto"), limitesMonto)
+			} else {
+				gasto, err = leerGastoCategorias()
+			}
+			if err != nil {
+				return err
+			}
+
+			estimaciones := make([]EstimacionCashback, len(evaluadas))
+			for i, t := range evaluadas {
+				estimaciones[i] = estimarCashback(t, gasto)
+			}
+			sort.SliceStable(estimaciones, func(i, j int) bool { return estimaciones[i].Neto > estimaciones[j].Neto })
+
+			if salidaEstructurada() {
+				return emitirDatos(estimaciones)
+			}
+
+			fmt.Println("\n=== Cashback Anual Estimado ===")
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Tarjeta\tBonificación\tTope Mensual\tForma\tValor Real\tAnualidad\tNeto Anual")
+			fmt.Fprintln(w, "-------\t------------\t------------\t-----\t----------\t---------\t----------")
+			for _, e := range estimaciones {
+				tope := "Sin tope"
+				if e.TopeMensual > 0 {
+					tope = fmt.Sprintf("$%.2f", e.TopeMensual)
+				}
+				fmt.Fprintf(w, "%s\t$%.2f\t%s\t%s\t$%.2f\t$%.2f\t$%.2f\n",
+					e.Nombre, e.Bruto, tope, e.Forma, e.Valor, e.Anualidad, e.Neto)
+			}
+			w.Flush()
+
+			if len(estimaciones) == 1 {
+				e := estimaciones[0]
+				fmt.Println()
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+				fmt.Fprintln(w, "Categoría\tGasto Mensual\tTasa\tBonificación Mensual")
+				fmt.Fprintln(w, "---------\t-------------\t----\t--------------------")
+				for _, cat := range e.Categorias {
+					fmt.Fprintf(w, "%s\t$%.2f\t%.2f%%\t$%.2f\n", cat.Categoria, cat.Gasto, cat.Tasa*100, cat.Bonificacion)
+				}
+				w.Flush()
+			}
+
+			mejor := estimaciones[0]
+			fmt.Printf("\nRESULTADO: %s te devuelve $%.2f al año; descontando la anualidad quedan $%.2f\n", mejor.Nombre, mejor.Valor, mejor.Neto)
+			for _, e := range estimaciones {
+				if e.Topado < e.Bruto {
+					fmt.Printf("AVISO: En %s el tope mensual te deja sin $%.2f de bonificación al año\n", e.Nombre, e.Bruto-e.Topado)
+				}
+				if e.Forma == calc.CashbackPuntos && e.Valor < e.Topado {
+					fmt.Printf("AVISO: %s paga en puntos; al canjearlos pierdes $%.2f al año frente a un abono\n", e.Nombre, e.Topado-e.Valor)
+				}
+				if e.Neto < 0 {
+					fmt.Printf("ALERTA: %s no recupera su anualidad con tu gasto (pierdes $%.2f al año)\n", e.Nombre, -e.Neto)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// comandoConfigurarCashback registra las tasas por categoría, el tope y la forma de pago del
+// programa de bonificación de una tarjeta
+func comandoConfigurarCashback() *cli.Command {
+	return &cli.Command{
+		Name:      "configurar",
+		Usage:     "Registrar el programa de cashback de una tarjeta",
+		ArgsUsage: "<tarjeta>",
+		Description: "Sin flags pregunta cada dato mostrando el valor guardado; con flags solo cambia los indicados.\n" +
+			"Ejemplo: finmex credito cashback configurar --categorias \"supermercado=5%,gasolina=3%\" --tope 500 --forma puntos Oro",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "categorias", Usage: "Cashback por categoría, ej: \"supermercado=0.05,gasolina=0.03\"; \"ninguna\" las borra"},
+			&cli.Float64Flag{Name: "general", Usage: "Cashback del gasto que no tiene categoría propia (decimal)"},
+			&cli.Float64Flag{Name: "tope", Usage: "Bonificación máxima al mes; 0 sin tope"},
+			&cli.StringFlag{Name: "forma", Usage: "Cómo se entrega: abono o puntos"},
+			&cli.Float64Flag{Name: "valor-punto", Usage: fmt.Sprintf("Pesos que vale cada peso en puntos al canjearlo; 0 usa el típico (%.2f)", calc.VALOR_PUNTO_TIPICO)},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, err := seleccionarTarjeta(c, nombresCredito(tarjetas), "credito", "tarjetas de crédito")
+			if err != nil {
+				return err
+			}
+			t := tarjetas.Credito[i]
+
+			k := nuevaCaptura(c)
+			if k.interactiva {
+				fmt.Printf("Categorías: %s. Deja vacío un dato para conservar el valor actual.\n", strings.Join(CategoriasGasto, ", "))
+			}
+			texto, err := k.EditarTexto("categorias", "Cashback por categoría (categoría=tasa, separadas por comas)", textoPorCategoria(t.Categorias))
+			if err != nil {
+				return err
+			}
+			if t.Categorias, err = parsearPorCategoria("categorias", texto, limitesFraccion); err != nil {
+				return err
+			}
+			if t.BeneficiosCashback, err = k.EditarNumero("general", "Cashback general (decimal)", t.BeneficiosCashback, limitesFraccion); err != nil {
+				return err
+			}
+			if t.TopeCashback, err = k.EditarNumero("tope", "Tope mensual de bonificación (0 sin tope)", t.TopeCashback, limitesMonto); err != nil {
+				return err
+			}
+			forma := t.FormaCashback
+			if forma == "" {
+				forma = calc.CashbackAbono
+			}
+			if forma, err = k.EditarTexto("forma", "Forma de pago (abono o puntos)", forma); err != nil {
+				return err
+			}
+			switch t.FormaCashback = normalizarClave(forma); t.FormaCashback {
+			case calc.CashbackAbono:
+				t.FormaCashback, t.ValorPunto = "", 0
+			case calc.CashbackPuntos:
+				if t.ValorPunto, err = k.EditarNumero("valor-punto", "Valor en pesos de cada peso en puntos (0 usa el típico)", t.ValorPunto, LimitesNumero{Min: 0, Max: 5}); err != nil {
+					return err
+				}
+			default:
+				return errDatosInvalidos(
+					fmt.Sprintf("Forma de cashback inválida '%s' (usa abono o puntos)", forma),
+					fmt.Sprintf("Invalid cashback form '%s' (use abono or puntos)", forma))
+			}
+
+			tarjetas.Credito[i] = t
+			if err := GuardarTarjetas(tarjetas); err != nil {
+				return fmt.Errorf("Error al guardar tarjeta: %w", err)
+			}
+			fmt.Printf("Programa de cashback de '%s' actualizado; estímalo con 'finmex credito cashback %s'\n", t.Nombre, t.Nombre)
+			return nil
+		},
+	}
+}
+
+// EstimacionCashback es el cashback de un año de gasto con una tarjeta
+type EstimacionCashback struct {
+	Nombre      string                   `json:"nombre"`
+	Banco       string                   `json:"banco"`
+	Categorias  []calc.CashbackCategoria `json:"categorias"` // Bonificación mensual por categoría
+	TopeMensual float64                  `json:"tope_mensual"`
+	Forma       string                   `json:"forma"`
+	Bruto       float64                  `json:"bruto"`  // Antes del tope
+	Topado      float64                  `json:"topado"` // Después del tope, antes del canje
+	Valor       float64                  `json:"valor"`  // En pesos
+	Anualidad   float64                  `json:"anualidad"` // Cero si el gasto alcanza para que la condonen
+	Neto        float64                  `json:"neto"`
+}
+
+// estimarCashback anualiza el cashback mensual de la tarjeta y le resta la anualidad, que no
+// cuenta si el gasto alcanza para que la condonen
+func estimarCashback(t TarjetaCredito, gasto map[string]float64) EstimacionCashback {
+	m := calc.CashbackMensual(t, gasto)
+	total := 0.0
+	for _, monto := range gasto {
+		total += monto
+	}
+	e := EstimacionCashback{
+		Nombre: t.Nombre, Banco: t.Banco, Categorias: m.Categorias, TopeMensual: t.TopeCashback,
+		Forma: calc.CashbackAbono, Bruto: m.Bruto * 12, Topado: m.Topado * 12, Valor: m.Valor * 12,
+		Anualidad: t.AnualidadEfectiva(total),
+	}
+	if t.FormaCashback == calc.CashbackPuntos {
+		e.Forma = calc.CashbackPuntos
+	}
+	e.Neto = e.Valor - e.Anualidad
+	return e
+}
+
+// leerGastoCategorias pregunta el gasto mensual con tarjeta de cada categoría
+func leerGastoCategorias() (map[string]float64, error) {
+	gasto := make(map[string]float64)
+	fmt.Println("Gasto mensual con tarjeta por categoría:")
+	for _, categoria := range CategoriasGasto {
+		monto, err := leerNumero(fmt.Sprintf("  %s: ", strings.ReplaceAll(categoria, "_", " ")), limitesMonto)
+		if err != nil {
+			return nil, err
+		}
+		if monto > 0 {
+			gasto[categoria] = monto
+		}
+	}
+	return gasto, nil
+}
+
+// parsearPorCategoria interpreta una lista categoría=valor separada por comas; "ninguna" o
+// una lista vacía no regresan categorías
+func parsearPorCategoria(flag, texto string, limites LimitesNumero) (map[string]float64, error) {
+	valores := make(map[string]float64)
+	if clave := normalizarClave(texto); clave == "" || clave == "ninguna" {
+		return valores, nil
+	}
+	for _, parte := range strings.Split(texto, ",") {
+		categoria, valor, ok := strings.Cut(parte, "=")
+		if !ok {
+			return nil, errDatosInvalidos(
+				fmt.Sprintf("--%s: '%s' inválido; usa categoría=valor separados por comas", flag, parte),
+				fmt.Sprintf("--%s: invalid '%s'; use category=value separated by commas", flag, parte))
+		}
+		categoria = strings.ReplaceAll(normalizarClave(categoria), " ", "_")
+		if !contieneClave(CategoriasGasto, categoria) {
+			return nil, errDatosInvalidos(
+				fmt.Sprintf("--%s: categoría desconocida '%s' (usa %s)", flag, categoria, strings.Join(CategoriasGasto, ", ")),
+				fmt.Sprintf("--%s: unknown category '%s' (use %s)", flag, categoria, strings.Join(CategoriasGasto, ", ")))
+		}
+		numero, err := parsearDecimal(strings.TrimSpace(valor), limites)
+		if err != nil {
+			return nil, fmt.Errorf("--%s %s: %w", flag, categoria, err)
+		}
+		valores[categoria] = numero
+	}
+	return valores, nil
+}
+
+// textoPorCategoria escribe las tasas por categoría en el formato de parsearPorCategoria
+func textoPorCategoria(valores map[string]float64) string {
+	var partes []string
+	for categoria, valor := range valores {
+		partes = append(partes, fmt.Sprintf("%s=%g", categoria, valor))
+	}
+	sort.Strings(partes)
+	return strings.Join(partes, ",")
+}
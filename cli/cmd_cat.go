@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCreditoCAT calcula el CAT de una tarjeta con sus flujos y lo compara con el anunciado
+func comandoCreditoCAT() *cli.Command {
+	return &cli.Command{
+		Name:      "cat",
+		Usage:     "Calcular el CAT con los flujos del plan de pagos y compararlo con el anunciado",
+		ArgsUsage: "[nombre o número]",
+		Description: "El CAT se calcula como Banxico: la TIR de lo que recibes y pagas cada mes, incluyendo la\n" +
+			"anualidad y otras comisiones, anualizada y sin IVA. El plan de pagos se simula con el pago\n" +
+			"indicado o, si no se indica, con el pago mínimo.",
+		Flags: []cli.Flag{
+			conLimites(&cli.Float64Flag{Name: "monto", Usage: "Monto del crédito; por defecto la deuda actual o, sin deuda, el límite"}, limitesMonto),
+			conLimites(&cli.Float64Flag{Name: "pago", Usage: "Pago mensual; por defecto el mínimo"}, limitesMonto),
+			conLimites(&cli.Float64Flag{Name: "comision-mensual", Usage: "Otras comisiones fijas cada mes (seguros, cuotas de administración)"}, limitesMonto),
+			&cli.BoolFlag{Name: "flujos", Usage: "Mostrar los flujos de cada mes"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, err := seleccionarTarjeta(c, nombresCredito(tarjetas), "credito", "tarjetas de crédito")
+			if err != nil {
+				return err
+			}
+			tarjeta := tarjetas.Credito[i]
+
+			deuda := c.Float64("monto")
+			if deuda == 0 {
+				deuda = tarjeta.Saldo
+			}
+			if deuda == 0 {
+				deuda = tarjeta.LimiteCredito
+			}
+			if deuda == 0 {
+				return fmt.Errorf("%s no tiene deuda ni límite registrados; indica el monto con --monto", tarjeta.Nombre)
+			}
+
+			v, err := VerificarCAT(tarjeta, deuda, c.Float64("pago"), c.Float64("comision-mensual"))
+			if err != nil {
+				return err
+			}
+
+			if salidaEstructurada() {
+				return emitirDatos(v)
+			}
+
+			fmt.Printf("=== CAT de %s ===\n", tarjeta.Nombre)
+			fmt.Printf("Monto: $%.2f, pago mensual: $%.2f, %d pagos\n", v.Deuda, v.Pago, v.Pagos)
+			fmt.Printf("Intereses sin IVA: $%.2f\n", v.Intereses)
+			fmt.Printf("Comisiones y anualidades: $%.2f\n", v.Comisiones)
+			fmt.Printf("TIR mensual: %.4f%%\n", v.TIRMensual*100)
+			fmt.Printf("CAT calculado: %.2f%% (solo la tasa: %.2f%%)\n", v.CAT*100, v.SoloTasa*100)
+			fmt.Printf("CAT capturado: %.2f%%\n", v.Capturado*100)
+
+			if c.Bool("flujos") {
+				fmt.Println()
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+				fmt.Fprintln(w, "Mes\tFlujo")
+				fmt.Fprintln(w, "---\t-----")
+				for mes, flujo := range v.Flujos {
+					fmt.Fprintf(w, "%d\t$%.2f\n", mes, flujo)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				fmt.Println()
+			}
+
+			switch {
+			case v.Capturado == 0:
+				fmt.Println("AVISO: La tarjeta no tiene CAT capturado; registra el que anuncia el banco para compararlo")
+			case v.Discrepancia() && v.Diferencia > 0:
+				fmt.Printf("ALERTA: El CAT calculado es %.2f puntos mayor al anunciado; revisa la tasa y las comisiones que te cobran\n", v.Diferencia*100)
+			case v.Discrepancia():
+				fmt.Printf("AVISO: El CAT calculado es %.2f puntos menor al anunciado; puede haber comisiones que no tienes registradas\n", -v.Diferencia*100)
+			default:
+				fmt.Println("RESULTADO: El CAT calculado coincide con el anunciado")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ResumenCatalogo es lo que trae el catálogo en uso
+type ResumenCatalogo struct {
+	Version string `json:"version"`
+	Local   bool   `json:"local"` // Falso si se usa el embebido en el binario
+	Credito int    `json:"credito"`
+	Debito  int    `json:"debito"`
+}
+
+func resumirCatalogo(catalogo Catalogo) ResumenCatalogo {
+	return ResumenCatalogo{Version: catalogo.Version, Local: CatalogoLocal(), Credito: len(catalogo.Credito), Debito: len(catalogo.Debito)}
+}
+
+// origenCatalogo describe de dónde viene el catálogo en uso
+func origenCatalogo() string {
+	if CatalogoLocal() {
+		return "copia local en " + rutaComun(ARCHIVO_CATALOGO)
+	}
+	return "embebido en el binario"
+}
+
+// comandoCatalogo consulta, actualiza y compara contra el catálogo de tarjetas del mercado
+func comandoCatalogo() *cli.Command {
+	return &cli.Command{
+		Name:  "catalogo",
+		Usage: "Catálogo de tarjetas populares del mercado mexicano",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "listar",
+				Usage: "Ver las tarjetas del catálogo con sus tasas, CAT y anualidad",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "tipo", Value: "credito", Usage: "credito o debito"},
+					&cli.StringFlag{Name: "segmento", Usage: "Solo un segmento (clasica, oro, platino, digital, tradicional)"},
+				},
+				Action: func(c *cli.Context) error {
+					catalogo, err := CargarCatalogo()
+					if err != nil {
+						return err
+					}
+					tipo := c.String("tipo")
+					if tipo != "credito" && tipo != "debito" {
+						return errDatosInvalidos(fmt.Sprintf("--tipo debe ser credito o debito, no '%s'", tipo), fmt.Sprintf("--tipo must be credito or debito, not '%s'", tipo))
+					}
+					segmento := normalizarClave(c.String("segmento"))
+
+					var credito []CreditoCatalogo
+					for _, t := range catalogo.Credito {
+						if segmento == "" || normalizarClave(t.Segmento) == segmento {
+							credito = append(credito, t)
+						}
+					}
+					var debito []DebitoCatalogo
+					for _, t := range catalogo.Debito {
+						if segmento == "" || normalizarClave(t.Segmento) == segmento {
+							debito = append(debito, t)
+						}
+					}
+					if salidaEstructurada() {
+						if tipo == "debito" {
+							return emitirDatos(debito)
+						}
+						return emitirDatos(credito)
+					}
+
+					fmt.Printf("=== Catálogo %s (%s) ===\n\n", catalogo.Version, origenCatalogo())
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					if tipo == "debito" {
+						fmt.Fprintln(w, "Cuenta\tBanco\tSegmento\tRendimiento\tSaldo mínimo\tAnualidad")
+						fmt.Fprintln(w, "------\t-----\t--------\t-----------\t------------\t---------")
+						for _, t := range debito {
+							fmt.Fprintf(w, "%s\t%s\t%s\t%s\t$%.2f\t$%.2f\n", t.Nombre, t.Banco, SegmentoMercado(t.Segmento).Nombre(), DescribirTramos(t.TarjetaDebito), t.SaldoMinimo, t.ComisionAnual)
+						}
+					} else {
+						fmt.Fprintln(w, "Tarjeta\tBanco\tSegmento\tTasa anual\tCAT\tAnualidad")
+						fmt.Fprintln(w, "-------\t-----\t--------\t----------\t---\t---------")
+						for _, t := range credito {
+							fmt.Fprintf(w, "%s\t%s\t%s\t%.2f%%\t%.2f%%\t$%.2f\n", t.Nombre, t.Banco, SegmentoMercado(t.Segmento).Nombre(), t.TasaInteres*100, t.CAT*100, t.ComisionAnual)
+						}
+					}
+					w.Flush()
+					if len(credito) == 0 && tipo == "credito" || len(debito) == 0 && tipo == "debito" {
+						fmt.Println("\nAVISO: No hay productos de ese segmento en el catálogo")
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "comparar",
+				Usage:     "Comparar tus tarjetas de crédito contra las del catálogo",
+				ArgsUsage: "[nombre o número]",
+				Description: "Ubica el CAT de cada tarjeta en su segmento y busca en el catálogo las que te costarían\n" +
+					"menos en un año: los intereses de la deuda más la anualidad, con IVA. Sin --deuda se usa el\n" +
+					"saldo registrado de cada tarjeta.\n" +
+					"Ejemplo: finmex catalogo comparar --deuda 15000 Oro",
+				BashComplete: completarTarjetas(nombresCredito),
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "deuda", Usage: "Deuda que se mantiene durante el año, por defecto el saldo de cada tarjeta"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if len(tarjetas.Credito) == 0 {
+						return fmt.Errorf("No hay tarjetas de crédito registradas")
+					}
+					catalogo, err := CargarCatalogo()
+					if err != nil {
+						return err
+					}
+
+					credito := tarjetas.Credito
+					if c.NArg() > 0 {
+						i, err := seleccionarTarjeta(c, nombresCredito(tarjetas), "credito", "tarjetas de crédito")
+						if err != nil {
+							return err
+						}
+						credito = credito[i : i+1]
+					}
+
+					var comparaciones []ComparacionMercado
+					for _, t := range credito {
+						deuda := t.Saldo
+						if c.IsSet("deuda") {
+							deuda = c.Float64("deuda")
+						}
+						comparaciones = append(comparaciones, CompararConMercado(t, deuda, catalogo))
+					}
+					if salidaEstructurada() {
+						return emitirDatos(comparaciones)
+					}
+
+					fmt.Printf("=== Tus tarjetas contra el catálogo %s ===\n\n", catalogo.Version)
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Tarjeta\tSegmento\tCAT\tPercentil\tDeuda\tCosto anual")
+					fmt.Fprintln(w, "-------\t--------\t---\t---------\t-----\t-----------")
+					for _, r := range comparaciones {
+						fmt.Fprintf(w, "%s\t%s\t%.2f%%\t%.0f\t$%.2f\t$%.2f\n", r.Tarjeta, r.Segmento, r.CAT*100, r.Percentil, r.Deuda, r.CostoAnual)
+					}
+					w.Flush()
+					for _, r := range comparaciones {
+						if r.Deuda == 0 {
+							fmt.Printf("AVISO: %s no tiene saldo; solo se compara la anualidad. Usa --deuda para incluir los intereses\n", r.Tarjeta)
+						}
+					}
+
+					mejorable := false
+					for _, r := range comparaciones {
+						if len(r.Alternativas) == 0 {
+							continue
+						}
+						mejorable = true
+						fmt.Printf("\nMás baratas que %s:\n", r.Tarjeta)
+						w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+						fmt.Fprintln(w, "Tarjeta\tBanco\tCAT\tAnualidad\tCosto anual\tAhorro al año")
+						fmt.Fprintln(w, "-------\t-----\t---\t---------\t-----------\t-------------")
+						for _, a := range r.Alternativas {
+							fmt.Fprintf(w, "%s\t%s\t%.2f%%\t$%.2f\t$%.2f\t$%.2f\n", a.Nombre, a.Banco, a.CAT*100, a.ComisionAnual, a.CostoAnual, a.Ahorro)
+						}
+						w.Flush()
+					}
+
+					if !mejorable {
+						fmt.Println("\nRESULTADO: Ninguna tarjeta del catálogo te costaría menos en tu segmento")
+						return nil
+					}
+					mejor := comparaciones[0]
+					for _, r := range comparaciones[1:] {
+						if len(r.Alternativas) > 0 && (len(mejor.Alternativas) == 0 || r.Alternativas[0].Ahorro > mejor.Alternativas[0].Ahorro) {
+							mejor = r
+						}
+					}
+					a := mejor.Alternativas[0]
+					fmt.Printf("\nRESULTADO: Cambiando %s por %s (%s) te ahorrarías $%.2f al año\n", mejor.Tarjeta, a.Nombre, a.Banco, a.Ahorro)
+					return nil
+				},
+			},
+			{
+				Name:  "actualizar",
+				Usage: "Descargar el catálogo publicado o instalar uno desde un archivo JSON",
+				Description: "El catálogo se valida antes de reemplazar la copia local; si trae errores se conserva el anterior.\n" +
+					"Ejemplo: finmex catalogo actualizar --archivo mi_catalogo.json",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "url", Usage: "Dirección del catálogo, por defecto " + URL_CATALOGO},
+					&cli.StringFlag{Name: "archivo", Usage: "Archivo JSON local en lugar de descargarlo"},
+				},
+				Action: func(c *cli.Context) error {
+					var catalogo Catalogo
+					var err error
+					if archivo := c.String("archivo"); archivo != "" {
+						data, errLeer := ioutil.ReadFile(archivo)
+						if errLeer != nil {
+							return fmt.Errorf("Error al leer %s: %w", archivo, errLeer)
+						}
+						catalogo, err = InstalarCatalogo(archivo, data)
+					} else {
+						url := urlCatalogo()
+						if c.IsSet("url") {
+							url = c.String("url")
+						}
+						catalogo, err = DescargarCatalogo(url)
+					}
+					if err != nil {
+						return err
+					}
+
+					r := resumirCatalogo(catalogo)
+					if salidaEstructurada() {
+						return emitirDatos(r)
+					}
+					fmt.Printf("RESULTADO: Catálogo %s instalado con %d tarjetas de crédito y %d cuentas de débito\n", r.Version, r.Credito, r.Debito)
+					return nil
+				},
+			},
+			{
+				Name:  "restaurar",
+				Usage: "Borrar la copia local y volver al catálogo embebido en el binario",
+				Action: func(c *cli.Context) error {
+					if !CatalogoLocal() {
+						fmt.Println("AVISO: No hay copia local; ya se usa el catálogo embebido")
+						return nil
+					}
+					if err := RestaurarCatalogo(); err != nil {
+						return err
+					}
+					catalogo, err := CargarCatalogo()
+					if err != nil {
+						return err
+					}
+					fmt.Printf("RESULTADO: Se usa de nuevo el catálogo embebido %s\n", catalogo.Version)
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCetes agrupa las operaciones con valores gubernamentales de cetesdirecto
+func comandoCetes() *cli.Command {
+	return &cli.Command{
+		Name:  "cetes",
+		Usage: "Operaciones con CETES, BONDES y UDIBONOS de cetesdirecto",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar una inversión en cetesdirecto",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "nombre", Usage: "Nombre de la inversión"},
+					&cli.StringFlag{Name: "instrumento", Usage: "cetes, bondes o udibonos"},
+					conLimites(&cli.Float64Flag{Name: "monto", Usage: "Monto invertido"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "tasa", Usage: "Tasa anual en decimal; real en UDIBONOS (0.11 para 11%)"}, limitesTasa),
+					&cli.IntFlag{Name: "plazo", Usage: "Plazo en días (28, 91, 182, 364...)"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var inversion InversionCetes
+					captura := nuevaCaptura(c)
+
+					if inversion.Nombre, err = captura.Texto("nombre", "Nombre de la inversión: "); err != nil {
+						return err
+					}
+					instrumento, err := captura.Texto("instrumento", "Instrumento (cetes, bondes o udibonos): ")
+					if err != nil {
+						return err
+					}
+					if inversion.Instrumento, err = ParsearInstrumento(instrumento); err != nil {
+						return err
+					}
+					if inversion.Monto, err = captura.Numero("monto", "Monto invertido: ", limitesMonto); err != nil {
+						return err
+					}
+					pregunta := "Tasa de rendimiento anual (decimal, ej: 0.11 para 11%): "
+					if inversion.Instrumento == InstrumentoUdibonos {
+						pregunta = "Tasa real anual sobre UDIs (decimal, ej: 0.045 para 4.5%): "
+					}
+					if inversion.Tasa, err = captura.Numero("tasa", pregunta, limitesTasa); err != nil {
+						return err
+					}
+					if c.IsSet("plazo") {
+						inversion.PlazoDias = c.Int("plazo")
+						if inversion.PlazoDias < 1 || inversion.PlazoDias > 365*30 {
+							return errDatosInvalidos("--plazo debe estar entre 1 y 10950 días", "--plazo must be between 1 and 10950 days")
+						}
+					} else if inversion.PlazoDias, err = leerEntero("Plazo en días (28, 91, 182, 364...): ", 1, 365*30); err != nil {
+						return err
+					}
+
+					tarjetas.Cetes = append(tarjetas.Cetes, inversion)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar inversión: %w", err)
+					}
+
+					fmt.Printf("Inversión '%s' agregada exitosamente\n", inversion.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar inversiones en cetesdirecto",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Cetes) == 0 && !salidaEstructurada() {
+						fmt.Println("No hay inversiones en cetesdirecto registradas")
+						return nil
+					}
+
+					inflacion := InflacionVigente()
+					if salidaEstructurada() {
+						filas := []FilaCetes{}
+						for _, inv := range tarjetas.Cetes {
+							filas = append(filas, FilaCetes{InversionCetes: inv, RendimientoCetes: inv.Rendimiento(inflacion), Inflacion: inflacion})
+						}
+						return emitirDatos(filas)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tInstrumento\tMonto\tTasa\tPlazo\tRendimiento Neto\tTasa Neta\tTasa Real")
+					fmt.Fprintln(w, "------\t-----------\t-----\t----\t-----\t----------------\t---------\t---------")
+
+					for _, inv := range tarjetas.Cetes {
+						r := inv.Rendimiento(inflacion)
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%.2f%%\t%d días\t$%.2f\t%.2f%%\t%.2f%%\n",
+							inv.Nombre, inv.Instrumento, inv.Monto, inv.Tasa*100, inv.PlazoDias,
+							r.Neto, r.TasaNeta*100, r.TasaReal*100)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:      "analizar",
+				Usage:     "Analizar el rendimiento real de una inversión",
+				ArgsUsage: "<nombre o número>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "udis", Usage: "Mostrar también el monto al vencimiento en pesos constantes (UDIS)"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					nombres := make([]string, len(tarjetas.Cetes))
+					for i, inv := range tarjetas.Cetes {
+						nombres[i] = inv.Nombre
+					}
+					i, err := seleccionarTarjeta(c, nombres, "cetes", "inversiones en cetesdirecto")
+					if err != nil {
+						return err
+					}
+
+					inv := tarjetas.Cetes[i]
+					inflacion := InflacionVigente()
+					r := inv.Rendimiento(inflacion)
+					var vencimiento *MontoUDIS
+					if c.Bool("udis") {
+						m := enUDIS(r.MontoFinal, float64(inv.PlazoDias)*12/365)
+						vencimiento = &m
+					}
+					if salidaEstructurada() {
+						return emitirDatos(FilaCetes{InversionCetes: inv, RendimientoCetes: r, Inflacion: inflacion, EnUDIS: vencimiento})
+					}
+
+					fmt.Println("\n=== Análisis de Inversión en cetesdirecto ===")
+					fmt.Printf("Inversión: %s (%s a %d días)\n", inv.Nombre, inv.Instrumento, inv.PlazoDias)
+					fmt.Printf("Monto invertido: $%.2f\n", inv.Monto)
+					if inv.Instrumento == InstrumentoUdibonos {
+						fmt.Printf("Tasa real sobre UDIs: %.2f%% (%.2f%% en pesos con inflación de %.2f%%)\n", inv.Tasa*100, r.TasaNominal*100, inflacion*100)
+					} else {
+						fmt.Printf("Tasa anual: %.2f%%\n", inv.Tasa*100)
+					}
+					if inv.Instrumento == InstrumentoCetes {
+						titulos, sobrante := TitulosCetes(inv.Monto, inv.Tasa, inv.PlazoDias)
+						fmt.Printf("Precio por título: $%.6f (%d títulos, $%.2f sin invertir)\n", PrecioCete(inv.Tasa, inv.PlazoDias), titulos, sobrante)
+					}
+					fmt.Printf("Interés bruto del plazo: $%.2f\n", r.Bruto)
+					fmt.Printf("ISR (%s): $%.2f\n", ISRVigente().Descripcion(), r.Retencion)
+					fmt.Printf("Interés neto: $%.2f\n", r.Neto)
+					fmt.Printf("Rendimiento neto anualizado: %.2f%%\n", r.TasaNeta*100)
+					fmt.Printf("Inflación: %.2f%%\n", inflacion*100)
+					fmt.Printf("Monto al vencimiento: $%.2f\n", r.MontoFinal)
+					if vencimiento != nil {
+						imprimirEnUDIS("Monto al vencimiento", *vencimiento)
+					}
+
+					if r.TasaReal > 0 {
+						fmt.Printf("RESULTADO: Tu inversión GANA valor real (%.2f%% anual sobre la inflación)\n", r.TasaReal*100)
+					} else {
+						fmt.Printf("RESULTADO: Tu inversión PIERDE valor real (%.2f%% anual contra la inflación)\n", r.TasaReal*100)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "comparar",
+				Usage: "Comparar tus inversiones en cetesdirecto contra tus tarjetas de débito",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "monto", Usage: "Monto a comparar; por defecto el de cada inversión"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if len(tarjetas.Cetes) == 0 {
+						return fmt.Errorf("No hay inversiones en cetesdirecto registradas")
+					}
+
+					monto := c.Float64("monto")
+					if c.IsSet("monto") {
+						if err := validarLimites(monto, limitesMonto); err != nil {
+							return fmt.Errorf("--monto: %w", err)
+						}
+					}
+
+					type opcion struct {
+						Nombre   string  `json:"nombre"`
+						Tipo     string  `json:"tipo"`
+						Monto    float64 `json:"monto"`
+						TasaReal float64 `json:"tasa_real"`
+						Real     float64 `json:"ganancia_real"` // Pesos al año por encima de la inflación
+					}
+					inflacion := InflacionVigente()
+					var opciones []opcion
+					for _, inv := range tarjetas.Cetes {
+						if c.IsSet("monto") {
+							inv.Monto = monto
+						}
+						r := inv.Rendimiento(inflacion)
+						opciones = append(opciones, opcion{inv.Nombre, inv.Instrumento, inv.Monto, r.TasaReal, inv.Monto * r.TasaReal})
+					}
+					for _, t := range tarjetas.Debito {
+						saldo := monto
+						if !c.IsSet("monto") {
+							saldo = tarjetas.Cetes[0].Monto
+						}
+						if saldo <= 0 {
+							continue
+						}
+						real, pct, _ := CalcularRendimientoReal(t, saldo)
+						opciones = append(opciones, opcion{t.Nombre, "débito", saldo, pct / 100, real})
+					}
+					sort.SliceStable(opciones, func(i, j int) bool { return opciones[i].TasaReal > opciones[j].TasaReal })
+					if salidaEstructurada() {
+						return emitirDatos(opciones)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tTipo\tMonto\tTasa Real\tGanancia Real Anual")
+					fmt.Fprintln(w, "------\t----\t-----\t---------\t-------------------")
+					for _, o := range opciones {
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%.2f%%\t$%.2f\n", o.Nombre, o.Tipo, o.Monto, o.TasaReal*100, o.Real)
+					}
+					w.Flush()
+
+					fmt.Printf("\nTasa real: rendimiento después de ISR y comisiones menos la inflación (%.2f%%)\n", inflacion*100)
+					if !c.IsSet("monto") && len(tarjetas.Debito) > 0 {
+						fmt.Printf("Las tarjetas de débito se evalúan con el monto de '%s'; usa --monto para comparar todo con el mismo monto\n", tarjetas.Cetes[0].Nombre)
+					}
+					fmt.Printf("RESULTADO: La mejor opción es %s (%s)\n", opciones[0].Nombre, opciones[0].Tipo)
+					return nil
+				},
+			},
+		},
+	}
+}
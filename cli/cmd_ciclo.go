@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCreditoCiclo muestra el periodo de facturación de una tarjeta de crédito
+func comandoCreditoCiclo() *cli.Command {
+	return &cli.Command{
+		Name:  "ciclo",
+		Usage: "Ver fecha de corte, fecha límite de pago y días de financiamiento de una compra",
+		Description: "El interés se calcula como lo hacen los bancos: saldo promedio diario del periodo por la\n" +
+			"tasa anual entre 360 por los días del periodo, más IVA. El saldo promedio parte de\n" +
+			"--saldo-anterior (o el saldo de la tarjeta) y suma los movimientos registrados con --tarjeta.",
+		BashComplete: completarFlagTarjeta("tarjeta", nombresCredito),
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tarjeta", Required: true, Usage: "Nombre de la tarjeta"},
+			&cli.IntFlag{Name: "corte", Usage: "Día de corte (1-31); se guarda en la tarjeta"},
+			&cli.IntFlag{Name: "pago", Usage: "Día límite de pago (1-31); se guarda en la tarjeta"},
+			&cli.StringFlag{Name: "fecha", Usage: "Fecha de la compra (AAAA-MM-DD), por defecto hoy"},
+			conLimites(&cli.Float64Flag{Name: "saldo-anterior", Usage: "Saldo al corte anterior, por defecto el saldo de la tarjeta"}, limitesLibre),
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, ok := indiceCredito(tarjetas, c.String("tarjeta"))
+			if !ok {
+				return errTarjetaNoEncontrada("credito", c.String("tarjeta"))
+			}
+
+			if err := aplicarDiasCiclo(c, &tarjetas.Credito[i]); err != nil {
+				return err
+			}
+			tarjeta := tarjetas.Credito[i]
+			if tarjeta.DiaCorte == 0 || tarjeta.DiaLimitePago == 0 {
+				return fmt.Errorf("Indica con --corte y --pago el día de corte y el día límite de pago de %s", tarjeta.Nombre)
+			}
+
+			compra := time.Now().Truncate(24 * time.Hour)
+			if c.String("fecha") != "" {
+				if compra, err = time.Parse("2006-01-02", c.String("fecha")); err != nil {
+					return fmt.Errorf("Fecha inválida: %s (usa AAAA-MM-DD)", c.String("fecha"))
+				}
+			}
+			saldo := tarjeta.Saldo
+			if c.IsSet("saldo-anterior") {
+				saldo = c.Float64("saldo-anterior")
+			}
+
+			e, err := EvaluarCiclo(tarjeta, compra, saldo)
+			if err != nil {
+				return fmt.Errorf("Error al leer movimientos: %w", err)
+			}
+
+			if c.IsSet("corte") || c.IsSet("pago") {
+				if err := GuardarTarjetas(tarjetas); err != nil {
+					return fmt.Errorf("Error al guardar tarjeta: %w", err)
+				}
+			}
+
+			if salidaEstructurada() {
+				return emitirDatos(e)
+			}
+
+			fmt.Printf("=== Ciclo de Facturación: %s ===\n", tarjeta.Nombre)
+			fmt.Printf("Periodo: %s a %s (%d días)\n", e.Inicio.Format("2006-01-02"), e.Corte.Format("2006-01-02"), e.Dias)
+			fmt.Printf("Fecha de corte: %s\n", e.Corte.Format("2006-01-02"))
+			fmt.Printf("Fecha límite de pago: %s\n", e.LimitePago.Format("2006-01-02"))
+			fmt.Printf("\nRESULTADO: Si compras el %s tienes %d días de financiamiento sin intereses, pagando el total antes del %s\n",
+				e.Compra.Format("2006-01-02"), e.DiasFinanciamiento, e.LimitePago.Format("2006-01-02"))
+			if e.DiasMejorDia > e.DiasFinanciamiento {
+				fmt.Printf("Si esperas al %s, el día siguiente al corte, tendrás %d días\n", e.MejorDia.Format("2006-01-02"), e.DiasMejorDia)
+			}
+
+			fmt.Println("\nIntereses del periodo si no pagas el total:")
+			fmt.Printf("Saldo al corte anterior: $%.2f\n", e.SaldoInicial)
+			if e.Movimientos > 0 {
+				fmt.Printf("Movimientos registrados en el periodo: %d\n", e.Movimientos)
+			}
+			fmt.Printf("Saldo promedio diario: $%.2f\n", e.SaldoPromedio)
+			fmt.Printf("Interés (%.2f%% anual, %d días): $%.2f + IVA $%.2f = $%.2f\n",
+				tarjeta.TasaInteres*100, e.Dias, e.Interes, e.IVAInteres, e.Interes+e.IVAInteres)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"finmex/storage"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCifrar cifra los datos del perfil activo con una passphrase
+func comandoCifrar() *cli.Command {
+	return &cli.Command{
+		Name:  "cifrar",
+		Usage: "Cifrar tus tarjetas y movimientos con una passphrase (AES-256-GCM)",
+		Description: "Los datos pasan a " + ARCHIVO_CIFRADO + " y se borran los archivos en texto plano. Después\n" +
+			"todos los comandos piden la passphrase, o la toman de " + VARIABLE_PASSPHRASE + ", por ejemplo\n" +
+			"desde el llavero del sistema: export " + VARIABLE_PASSPHRASE + "=\"$(secret-tool lookup app finmex)\".\n" +
+			"Si pierdes la passphrase no hay forma de recuperar los datos.",
+		Action: func(c *cli.Context) error {
+			if almacenCifrado() {
+				return fmt.Errorf("Tus datos ya están cifrados en %s", rutaDatos(ARCHIVO_CIFRADO))
+			}
+			if err := revisarSinDaemon(); err != nil {
+				return err
+			}
+			// Asegura que haya documento aunque todavía no se registre nada
+			if _, err := cargarTarjetasAlmacen(); err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			origen, err := almacenDatos()
+			if err != nil {
+				return err
+			}
+
+			passphrase, err := pedirPassphraseNueva()
+			if err != nil {
+				return err
+			}
+			if err := copiarAlmacen(rutaDatos(ARCHIVO_CIFRADO), origen, func(ruta string) (storage.Storage, error) {
+				return storage.AbrirCifrado(ruta, passphrase)
+			}); err != nil {
+				return fmt.Errorf("Error al cifrar los datos: %w", err)
+			}
+
+			cerrarAlmacen()
+			for _, archivo := range archivosEnClaro() {
+				if err := os.Remove(archivo); err != nil {
+					fmt.Printf("AVISO: No se pudo borrar %s, que sigue en texto plano: %v\n", archivo, err)
+				}
+			}
+			fmt.Printf("Datos cifrados en %s\n", rutaDatos(ARCHIVO_CIFRADO))
+			if copias := copiasEnClaro(); len(copias) > 0 {
+				fmt.Printf("ALERTA: Estas copias anteriores siguen en texto plano; bórralas si ya no las necesitas: %s\n", strings.Join(copias, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// comandoDescifrar regresa los datos cifrados al almacén elegido con --almacen
+func comandoDescifrar() *cli.Command {
+	return &cli.Command{
+		Name:  "descifrar",
+		Usage: "Quitar el cifrado y volver a guardar los datos en texto plano",
+		Action: func(c *cli.Context) error {
+			if !almacenCifrado() {
+				return fmt.Errorf("Tus datos no están cifrados")
+			}
+			if err := revisarSinDaemon(); err != nil {
+				return err
+			}
+			if archivos := archivosEnClaro(); len(archivos) > 0 {
+				return fmt.Errorf("Ya existen %s; muévelos antes de descifrar para no sobrescribirlos", strings.Join(archivos, ", "))
+			}
+			origen, err := almacenDatos()
+			if err != nil {
+				return err
+			}
+
+			destino := rutaDatos(ARCHIVO_BASE_DATOS)
+			abrir := func(ruta string) (storage.Storage, error) { return storage.AbrirSQLite(ruta) }
+			if tipoAlmacen == AlmacenJSON {
+				destino = rutaDatos(ARCHIVO_TARJETAS)
+				abrir = func(string) (storage.Storage, error) {
+					return storage.NuevoArchivosJSON(rutaDatos(ARCHIVO_TARJETAS), rutaDatos(ARCHIVO_MOVIMIENTOS)), nil
+				}
+			}
+			destinoAlmacen, err := abrir(destino)
+			if err != nil {
+				return err
+			}
+			err = storage.Copiar(destinoAlmacen, origen)
+			destinoAlmacen.Close()
+			if err != nil {
+				for _, archivo := range archivosEnClaro() {
+					os.Remove(archivo)
+				}
+				return fmt.Errorf("Error al descifrar los datos: %w", err)
+			}
+
+			cerrarAlmacen()
+			if err := os.Remove(rutaDatos(ARCHIVO_CIFRADO)); err != nil {
+				return fmt.Errorf("Los datos quedaron en %s pero no se pudo borrar %s: %w", destino, rutaDatos(ARCHIVO_CIFRADO), err)
+			}
+			fmt.Printf("Datos descifrados en %s\n", destino)
+			return nil
+		},
+	}
+}
+
+// copiarAlmacen copia origen a un almacén nuevo en ruta. Se escribe primero en un archivo
+// temporal para que una falla no deje un almacén a medias.
+func copiarAlmacen(ruta string, origen storage.Storage, abrir func(string) (storage.Storage, error)) error {
+	temporal := ruta + ".nuevo"
+	os.Remove(temporal)
+	destino, err := abrir(temporal)
+	if err != nil {
+		return err
+	}
+	err = storage.Copiar(destino, origen)
+	destino.Close()
+	if err != nil {
+		os.Remove(temporal)
+		return err
+	}
+	return os.Rename(temporal, ruta)
+}
+
+// revisarSinDaemon impide cambiar el almacén mientras un daemon lo tiene abierto
+func revisarSinDaemon() error {
+	if cliente, ok := conectarDaemon(); ok {
+		cliente.Close()
+		return fmt.Errorf("Hay un daemon activo en %s; detenlo antes de cambiar el cifrado", rutaDatos(ARCHIVO_SOCKET))
+	}
+	return nil
+}
+
+// copiasEnClaro son las copias de migraciones anteriores que siguen con los datos sin cifrar
+func copiasEnClaro() []string {
+	var copias []string
+	for _, nombre := range []string{ARCHIVO_TARJETAS, ARCHIVO_MOVIMIENTOS} {
+		if ruta := rutaDatos(nombre + SUFIJO_MIGRADO); storage.Existe(ruta) {
+			copias = append(copias, ruta)
+		}
+	}
+	return copias
+}
@@ -0,0 +1,369 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// compararCreditoDetalle muestra dos tarjetas, registradas o del catálogo, dimensión por
+// dimensión y con un veredicto por escenario de deuda
+func compararCreditoDetalle(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return fmt.Errorf("Indica las dos tarjetas a comparar, p. ej. comparar credito --detalle \"Oro\" \"Platinum\"")
+	}
+
+	tarjetas, err := CargarTarjetas()
+	if err != nil {
+		return fmt.Errorf("Error al cargar tarjetas: %w", err)
+	}
+	catalogo, err := CargarCatalogo()
+	if err != nil {
+		return err
+	}
+
+	candidatas := candidatasRecomendacion(tarjetas, catalogo)
+	par, err := parComparacion(candidatas, c.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	deuda := c.Float64("deuda")
+	if deuda <= 0 {
+		if deuda, err = leerNumero("Ingresa el monto de la deuda para los escenarios: ", limitesMonto); err != nil {
+			return err
+		}
+	}
+
+	d := CompararDetalle(par[0], par[1], deuda, c.Float64("gasto-mensual"))
+	if salidaEstructurada() {
+		return emitirDatos(d)
+	}
+	a, b := d.Tarjetas[0], d.Tarjetas[1]
+
+	fmt.Println("\n=== Comparación Detallada ===")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintf(w, "Dimensión\t%s\t%s\n", a.Nombre, b.Nombre)
+	fmt.Fprintln(w, "---------\t---\t---")
+
+	origen := func(t CreditoCatalogo) string {
+		if t.Segmento == segmentoRegistrada {
+			return "Tuya"
+		}
+		return "Catálogo (" + t.Segmento + ")"
+	}
+	siNo := func(v bool) string {
+		if v {
+			return "Sí"
+		}
+		return "No"
+	}
+
+	fmt.Fprintf(w, "Banco\t%s\t%s\n", a.Banco, b.Banco)
+	fmt.Fprintf(w, "Origen\t%s\t%s\n", origen(a), origen(b))
+	fmt.Fprintf(w, "Tasa de interés\t%.2f%%\t%.2f%%\n", a.TasaInteres*100, b.TasaInteres*100)
+	fmt.Fprintf(w, "CAT\t%.2f%%\t%.2f%%\n", a.CAT*100, b.CAT*100)
+	fmt.Fprintf(w, "Comisión anual\t$%.2f\t$%.2f\n", a.ComisionAnual, b.ComisionAnual)
+	condonacion := func(t CreditoCatalogo) string {
+		switch {
+		case t.FacturacionParaCondonar <= 0:
+			return "No"
+		case t.AnualidadCondonada(d.GastoMensual):
+			return "Sí, con tu gasto"
+		}
+		return fmt.Sprintf("Facturando $%.2f al año", t.FacturacionParaCondonar)
+	}
+	fmt.Fprintf(w, "Anualidad condonable\t%s\t%s\n", condonacion(a), condonacion(b))
+	fmt.Fprintf(w, "Límite de crédito\t$%.2f\t$%.2f\n", a.LimiteCredito, b.LimiteCredito)
+	fmt.Fprintf(w, "Cashback\t%.2f%%\t%.2f%%\n", a.BeneficiosCashback*100, b.BeneficiosCashback*100)
+	fmt.Fprintf(w, "MSI\t%s\t%s\n", siNo(a.MesesSinIntereses), siNo(b.MesesSinIntereses))
+	fmt.Fprintf(w, "Beneficios de viaje\t$%.2f\t$%.2f\n", a.ValorViaje, b.ValorViaje)
+	fmt.Fprintf(w, "Sin comisión en el extranjero\t%s\t%s\n", siNo(a.SinComisionExtranjero), siNo(b.SinComisionExtranjero))
+	fmt.Fprintf(w, "Beneficios valuados al año\t$%.2f\t$%.2f\n", d.BeneficiosAnuales[0], d.BeneficiosAnuales[1])
+
+	for _, e := range d.Escenarios {
+		fmt.Fprintf(w, "\t\t\n")
+		fmt.Fprintf(w, "Deuda a %d meses: pago mensual\t$%.2f\t$%.2f\n", e.Meses, e.Pago[0], e.Pago[1])
+		fmt.Fprintf(w, "  intereses\t$%.2f\t$%.2f\n", e.Intereses[0], e.Intereses[1])
+		fmt.Fprintf(w, "  anualidades\t$%.2f\t$%.2f\n", e.Anualidades[0], e.Anualidades[1])
+		fmt.Fprintf(w, "  beneficios\t-$%.2f\t-$%.2f\n", e.Beneficios[0], e.Beneficios[1])
+		fmt.Fprintf(w, "  costo neto\t$%.2f\t$%.2f\n", e.CostoNeto(0), e.CostoNeto(1))
+	}
+	w.Flush()
+
+	fmt.Printf("\nDeuda: $%.2f | Gasto mensual para beneficios: $%.2f\n", d.Deuda, d.GastoMensual)
+	for _, e := range d.Escenarios {
+		if e.Ganadora < 0 {
+			fmt.Printf("VEREDICTO %d meses: empate ($%.2f)\n", e.Meses, e.CostoNeto(0))
+			continue
+		}
+		otra := 1 - e.Ganadora
+		fmt.Printf("VEREDICTO %d meses: conviene %s, ahorras $%.2f\n",
+			e.Meses, d.Tarjetas[e.Ganadora].Nombre, e.CostoNeto(otra)-e.CostoNeto(e.Ganadora))
+	}
+	return nil
+}
+
+// resultadoComparacionDebito calcula el rendimiento real de una cuenta para la comparación con
+// un saldo en pesos
+func resultadoComparacionDebito(t TarjetaDebito, saldo float64) ResultadoComparacionDebito {
+	rendimiento, rendimientoPct, saldoFinal := CalcularRendimientoReal(t, saldo)
+	return ResultadoComparacionDebito{
+		Nombre:             t.Nombre,
+		Banco:              t.Banco,
+		Moneda:             MonedaCuenta(t),
+		Saldo:              saldo,
+		TasaRendimiento:    t.TasaPonderada(saldoEnMoneda(t, saldo)),
+		RendimientoReal:    rendimiento,
+		RendimientoRealPct: rendimientoPct,
+		SaldoFinal:         saldoFinal,
+		Gana:               rendimiento > 0,
+		RiesgoCambiario:    riesgoCambiario(t, saldo),
+	}
+}
+
+// resultadoComparacionCredito calcula el costo de liquidar la deuda con una tarjeta
+func resultadoComparacionCredito(t TarjetaCredito, deuda, pago float64, frecuencia Frecuencia) ResultadoComparacionCredito {
+	costo, pagos, _ := calc.CostoCreditoFrecuencia(t, deuda, pago, frecuencia)
+	return ResultadoComparacionCredito{
+		Nombre:     t.Nombre,
+		Banco:      t.Banco,
+		Deuda:      deuda,
+		Pago:       pago,
+		Frecuencia: frecuencia,
+		CAT:        t.CAT,
+		CostoTotal: costo,
+		Pagos:      pagos,
+		Cashback:   t.BeneficiosCashback,
+		MSI:        t.MesesSinIntereses,
+	}
+}
+
+// parComparacion encuentra las dos tarjetas a comparar. Con nombres sin comillas, como
+// Nu Morada Platinum, se prueba cada forma de partir los argumentos hasta que las dos
+// partes sean tarjetas conocidas.
+func parComparacion(candidatas []CreditoCatalogo, args []string) ([2]CreditoCatalogo, error) {
+	for i := 1; i < len(args); i++ {
+		primera, ok := BuscarTarjetaCredito(candidatas, argumentoNombre(args[:i]))
+		if !ok {
+			continue
+		}
+		if segunda, ok := BuscarTarjetaCredito(candidatas, argumentoNombre(args[i:])); ok {
+			return [2]CreditoCatalogo{primera, segunda}, nil
+		}
+	}
+
+	faltante := argumentoNombre(args)
+	if len(args) == 2 {
+		faltante = args[1]
+		if _, ok := BuscarTarjetaCredito(candidatas, args[0]); !ok {
+			faltante = args[0]
+		}
+	}
+	return [2]CreditoCatalogo{}, &ErrorFinmex{
+		Codigo:  CodigoTarjetaNoEncontrada,
+		Mensaje: fmt.Sprintf("No se encontró la tarjeta '%s' entre tus tarjetas ni en el catálogo", faltante),
+		Message: fmt.Sprintf("Card '%s' not found among your cards or in the catalog", faltante),
+	}
+}
+
+// comandoCompararMejor recomienda la tarjeta que más conviene para un patrón de uso
+func comandoCompararMejor() *cli.Command {
+	return &cli.Command{
+		Name:  "mejor",
+		Usage: "Recomendar la tarjeta óptima para un escenario de uso",
+		Description: "Con crédito pondera en un año el cashback del gasto, la anualidad, los intereses a la tasa\n" +
+			"(o el CAT) según cómo pagas y el ahorro de diferir compras a MSI. Con débito ordena las\n" +
+			"cuentas por rendimiento real sobre el saldo.\n" +
+			"Ejemplo: finmex comparar mejor --tipo credito --gasto-mensual 15000 --pago total",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tipo", Value: "credito", Usage: "Tipo de tarjeta: credito o debito"},
+			conLimites(&cli.Float64Flag{Name: "gasto-mensual", Usage: "Gasto mensual con la tarjeta"}, limitesMonto),
+			&cli.StringFlag{Name: "pago", Value: PagoUsoTotal, Usage: "Cómo pagas la tarjeta: total, minimo o un monto mensual"},
+			conLimites(&cli.Float64Flag{Name: "compras-msi", Usage: "Compras al año que difieres a meses sin intereses"}, limitesMonto),
+			&cli.IntFlag{Name: "meses-msi", Value: 12, Usage: "Plazo de las compras a MSI"},
+			&cli.BoolFlag{Name: "catalogo", Usage: "Incluir las tarjetas del catálogo además de las tuyas"},
+			conLimites(&cli.Float64Flag{Name: "saldo", Usage: "Saldo promedio a mantener, solo con --tipo debito"}, limitesMonto),
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			switch normalizarClave(c.String("tipo")) {
+			case "credito", "crédito":
+				return compararMejorCredito(c, tarjetas)
+			case "debito", "débito":
+				return compararMejorDebito(c, tarjetas)
+			}
+			return errDatosInvalidos(
+				fmt.Sprintf("Tipo inválido '%s' (usa credito o debito)", c.String("tipo")),
+				fmt.Sprintf("Invalid type '%s' (use credito or debito)", c.String("tipo")))
+		},
+	}
+}
+
+// RecomendacionUso es el resultado de comparar mejor con tarjetas de crédito
+type RecomendacionUso struct {
+	Escenario EscenarioUso   `json:"escenario"`
+	Tarjetas  []ValuacionUso `json:"tarjetas"`
+}
+
+// compararMejorCredito valúa las tarjetas de crédito con el escenario y justifica la ganadora
+// contra la segunda
+func compararMejorCredito(c *cli.Context, tarjetas Tarjetas) error {
+	pago, pagoMensual, err := ParsearPagoUso(c.String("pago"))
+	if err != nil {
+		return err
+	}
+	if c.Int("meses-msi") < 1 || c.Int("meses-msi") > 48 {
+		return errDatosInvalidos("--meses-msi debe estar entre 1 y 48", "--meses-msi must be between 1 and 48")
+	}
+
+	candidatas := candidatasRecomendacion(tarjetas, Catalogo{})
+	if c.Bool("catalogo") {
+		catalogo, err := CargarCatalogo()
+		if err != nil {
+			return err
+		}
+		candidatas = candidatasRecomendacion(tarjetas, catalogo)
+	}
+	if len(candidatas) == 0 {
+		return fmt.Errorf("No hay tarjetas de crédito registradas; agrega una o usa --catalogo")
+	}
+
+	gasto, err := valorRequeridoNDJSON(c, false, "gasto-mensual", "Ingresa tu gasto mensual con tarjeta: ", limitesMonto)
+	if err != nil {
+		return err
+	}
+	tasaOportunidad, _ := MejorTasaOportunidad(tarjetas)
+	e := EscenarioUso{
+		GastoMensual: gasto, Pago: pago, PagoMensual: pagoMensual,
+		ComprasMSI: c.Float64("compras-msi"), MesesMSI: c.Int("meses-msi"), TasaOportunidad: tasaOportunidad,
+	}
+	r := RecomendacionUso{Escenario: e, Tarjetas: ElegirTarjetaUso(candidatas, e)}
+
+	if salidaEstructurada() {
+		if formatoDatos == FormatoCSV {
+			return emitirDatos(r.Tarjetas)
+		}
+		return emitirDatos(r)
+	}
+
+	descripcionPago := map[string]string{
+		PagoUsoTotal:  "total al corte",
+		PagoUsoMinimo: "mínimo",
+		PagoUsoFijo:   fmt.Sprintf("$%.2f al mes", pagoMensual),
+	}
+	fmt.Println("\n=== Mejor Tarjeta para tu Uso ===")
+	fmt.Printf("Gasto mensual: $%.2f | Pago: %s | Compras a MSI: $%.2f al año\n\n", gasto, descripcionPago[pago], e.ComprasMSI)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Tarjeta\tBanco\tCAT\tCashback\tViajes\tMSI\tAnualidad\tIntereses\tDeuda al Año\tValor Neto")
+	fmt.Fprintln(w, "-------\t-----\t---\t--------\t------\t---\t---------\t---------\t------------\t----------")
+	for _, v := range r.Tarjetas {
+		nombre := v.Nombre
+		if !v.Registrada {
+			nombre += " (catálogo)"
+		}
+		anualidad := fmt.Sprintf("$%.2f", v.Anualidad)
+		if v.AnualidadCondonada {
+			anualidad = "condonada"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.2f%%\t$%.2f\t$%.2f\t$%.2f\t%s\t$%.2f\t$%.2f\t$%.2f\n",
+			nombre, v.Banco, v.CAT*100, v.Cashback, v.Viajes, v.MSI, anualidad, v.Intereses, v.DeudaFinal, v.Neto)
+	}
+	w.Flush()
+
+	mejor := r.Tarjetas[0]
+	fmt.Printf("\nRESULTADO: Usa %s (%s): valor neto de $%.2f al año con este patrón\n", mejor.Nombre, mejor.Banco, mejor.Neto)
+	if len(r.Tarjetas) > 1 {
+		segunda := r.Tarjetas[1]
+		fmt.Printf("Frente a %s ganas $%.2f más:\n", segunda.Nombre, mejor.Neto-segunda.Neto)
+		justificacion := []struct {
+			concepto   string
+			diferencia float64
+		}{
+			{"cashback", mejor.Cashback - segunda.Cashback},
+			{"beneficios de viaje", mejor.Viajes - segunda.Viajes},
+			{"ahorro por MSI", mejor.MSI - segunda.MSI},
+			{"anualidad", segunda.Anualidad - mejor.Anualidad},
+			{"intereses", segunda.Intereses - mejor.Intereses},
+		}
+		for _, j := range justificacion {
+			if math.Abs(j.diferencia) < 0.005 {
+				continue
+			}
+			sentido := "a favor"
+			if j.diferencia < 0 {
+				sentido = "en contra"
+			}
+			fmt.Printf("  %s: $%.2f %s\n", j.concepto, math.Abs(j.diferencia), sentido)
+		}
+		if math.Abs(mejor.Neto-segunda.Neto) < 0.005 {
+			fmt.Printf("  Empatan en valor; se prefiere la de menor CAT (%.2f%% contra %.2f%%)\n", mejor.CAT*100, segunda.CAT*100)
+		}
+	}
+	if mejor.Intereses > 0 {
+		fmt.Printf("AVISO: Con este patrón pagas $%.2f de intereses y terminas el año debiendo $%.2f; pagar el total pesa más que cualquier beneficio\n",
+			mejor.Intereses, mejor.DeudaFinal)
+	}
+	return nil
+}
+
+// compararMejorDebito ordena las cuentas de débito y cajas de ahorro por rendimiento real
+func compararMejorDebito(c *cli.Context, tarjetas Tarjetas) error {
+	cuentas := append([]TarjetaDebito{}, tarjetas.Debito...)
+	for _, caja := range tarjetas.Cajas {
+		cuentas = append(cuentas, caja.ComoDebito())
+	}
+	if len(cuentas) == 0 {
+		return fmt.Errorf("No hay tarjetas de débito ni cajas de ahorro registradas")
+	}
+
+	saldo, err := valorRequeridoNDJSON(c, false, "saldo", "Ingresa el saldo promedio a mantener: ", limitesMonto)
+	if err != nil {
+		return err
+	}
+
+	var resultados []ResultadoComparacionDebito
+	for _, t := range cuentas {
+		resultados = append(resultados, resultadoComparacionDebito(t, saldo))
+	}
+	sort.SliceStable(resultados, func(i, j int) bool { return resultados[i].RendimientoReal > resultados[j].RendimientoReal })
+
+	if salidaEstructurada() {
+		return emitirDatos(resultados)
+	}
+
+	fmt.Println("\n=== Mejor Cuenta para tu Saldo ===")
+	fmt.Printf("Saldo a mantener: $%.2f\n", saldo)
+	imprimirTipoCambio(cuentas)
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Nombre\tBanco\tMoneda\tRend. Nominal\tRend. Real\tGanancia Real")
+	fmt.Fprintln(w, "------\t-----\t------\t------------\t----------\t-------------")
+	for _, r := range resultados {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f%%\t%.2f%%\t$%.2f\n", r.Nombre, r.Banco, r.Moneda, r.TasaRendimiento*100, r.RendimientoRealPct, r.RendimientoReal)
+	}
+	w.Flush()
+
+	mejor := resultados[0]
+	fmt.Printf("\nRESULTADO: Usa %s (%s): ganas $%.2f reales al año sobre $%.2f\n", mejor.Nombre, mejor.Banco, mejor.RendimientoReal, saldo)
+	if len(resultados) > 1 {
+		fmt.Printf("Frente a %s ganas $%.2f más\n", resultados[1].Nombre, mejor.RendimientoReal-resultados[1].RendimientoReal)
+	}
+	if mejor.RendimientoReal <= 0 {
+		fmt.Println("AVISO: Ninguna cuenta le gana a la inflación con este saldo")
+	}
+	for _, r := range resultados {
+		imprimirRiesgoCambiario(r.Nombre, r.RiesgoCambiario)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// FilaConfig es una clave en la salida de config get con --output
+type FilaConfig struct {
+	Clave       string `json:"clave"`
+	Valor       string `json:"valor"`
+	Descripcion string `json:"descripcion"`
+}
+
+// comandoConfig consulta y cambia los valores por defecto de config.yaml
+func comandoConfig() *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Consultar y cambiar los valores por defecto de config.yaml",
+		Description: "config.yaml vive en $XDG_CONFIG_HOME/finmex (~/.config/finmex en Linux) o en\n" +
+			"FINMEX_CONFIG_DIR. Las opciones globales y sus variables de entorno tienen prioridad.",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "get",
+				Usage:     "Mostrar una clave o, sin argumentos, todas",
+				ArgsUsage: "[clave]",
+				Action: func(c *cli.Context) error {
+					if c.NArg() > 1 {
+						return fmt.Errorf("Uso: finmex config get [clave]")
+					}
+					if c.NArg() == 1 {
+						valor, err := configuracion.Valor(c.Args().First())
+						if err != nil {
+							return err
+						}
+						if salidaEstructurada() {
+							clave, _ := claveConfig(c.Args().First())
+							return emitirDatos(filasConfig(clave))
+						}
+						fmt.Println(valor)
+						return nil
+					}
+
+					filas := filasConfig("")
+					if salidaEstructurada() {
+						return emitirDatos(filas)
+					}
+					ruta, err := rutaConfig()
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Archivo: %s\n\n", ruta)
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Clave\tValor\tDescripción")
+					fmt.Fprintln(w, "-----\t-----\t-----------")
+					for _, f := range filas {
+						valor := f.Valor
+						if valor == "" {
+							valor = "-"
+						}
+						fmt.Fprintf(w, "%s\t%s\t%s\n", f.Clave, valor, f.Descripcion)
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:      "set",
+				Usage:     "Cambiar una clave; con un valor vacío se quita",
+				ArgsUsage: "<clave> <valor>",
+				Description: "Claves: inflacion, anio_fiscal, datos, output y perfil.\n" +
+					"Ejemplo: finmex config set inflacion 0.045\n" +
+					"         finmex config set datos xdg",
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 2 {
+						return fmt.Errorf("Uso: finmex config set <clave> <valor>")
+					}
+					clave, err := claveConfig(c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+					config := configuracion
+					if err := config.Fijar(clave, c.Args().Get(1)); err != nil {
+						return err
+					}
+					if clave == "perfil" && config.Perfil != "" && config.Perfil != PERFIL_PRINCIPAL && !existePerfil(config.Perfil) {
+						return fmt.Errorf("El perfil '%s' no existe; créalo con 'finmex perfil crear %s'", config.Perfil, config.Perfil)
+					}
+					if err := GuardarConfiguracion(config); err != nil {
+						return fmt.Errorf("Error al guardar la configuración: %w", err)
+					}
+					configuracion = config
+
+					valor, _ := config.Valor(clave)
+					if valor == "" {
+						fmt.Printf("'%s' quitada; se usa el valor predeterminado\n", clave)
+						return nil
+					}
+					fmt.Printf("%s = %s\n", clave, valor)
+					if clave == "datos" {
+						dir, err := config.DirectorioDatos()
+						if err != nil {
+							return err
+						}
+						fmt.Printf("AVISO: Los datos se leerán de %s; los del directorio anterior no se mueven\n", dir)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// filasConfig regresa el valor de una clave o, con la clave vacía, de todas
+func filasConfig(clave string) []FilaConfig {
+	var filas []FilaConfig
+	for _, k := range ClavesConfig {
+		if clave != "" && k.Clave != clave {
+			continue
+		}
+		valor, _ := configuracion.Valor(k.Clave)
+		filas = append(filas, FilaConfig{k.Clave, valor, k.Descripcion})
+	}
+	return filas
+}
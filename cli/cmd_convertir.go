@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// ConversionUDIS es el resultado de convertir udis con --output
+type ConversionUDIS struct {
+	ValorUDI        float64 `json:"valor_udi"` // Valor de hoy
+	Origen          string  `json:"origen"`
+	Meses           int     `json:"meses"`
+	ValorUDIFecha   float64 `json:"valor_udi_fecha"` // Valor proyectado a la fecha
+	Inflacion       float64 `json:"inflacion"`
+	UDIS            float64 `json:"udis"`
+	Pesos           float64 `json:"pesos"`            // Pesos corrientes a la fecha
+	PesosConstantes float64 `json:"pesos_constantes"` // Los mismos pesos con el poder de compra de hoy
+}
+
+// ConversionUSD es el resultado de convertir dólares con --output
+type ConversionUSD struct {
+	TipoCambio float64 `json:"tipo_cambio"`
+	Origen     string  `json:"origen"`
+	USD        float64 `json:"usd"`
+	Pesos      float64 `json:"pesos"`
+}
+
+// comandoConvertir convierte montos entre unidades
+func comandoConvertir() *cli.Command {
+	return &cli.Command{
+		Name:  "convertir",
+		Usage: "Convertir montos entre pesos y otras unidades o monedas",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "udis",
+				Usage: "Convertir entre UDIS y pesos, hoy o en una fecha futura",
+				Description: "El valor de la UDI es el último publicado por Banxico (requiere FINMEX_BANXICO_TOKEN) o el de --valor-udi.\n" +
+					"Con --meses se proyecta la UDI con la inflación vigente para convertir un monto futuro.",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "udis", Usage: "Monto en UDIS a convertir a pesos"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "pesos", Usage: "Monto en pesos a convertir a UDIS"}, limitesMonto),
+					&cli.IntFlag{Name: "meses", Usage: "Meses a futuro de la conversión; 0 es hoy"},
+				},
+				Action: func(c *cli.Context) error {
+					if c.IsSet("udis") && c.IsSet("pesos") {
+						return errDatosInvalidos("Indica --udis o --pesos, no ambos", "Use either --udis or --pesos, not both")
+					}
+					meses := c.Int("meses")
+					if meses < 0 || meses > 600 {
+						return errDatosInvalidos(fmt.Sprintf("--meses debe estar entre 0 y 600, no %d", meses), fmt.Sprintf("--meses must be between 0 and 600, not %d", meses))
+					}
+
+					valor, origen := ValorUDIVigente()
+					inflacion := InflacionVigente()
+					r := ConversionUDIS{
+						ValorUDI:      valor,
+						Origen:        origen,
+						Meses:         meses,
+						ValorUDIFecha: calc.ValorUDIProyectado(valor, inflacion, float64(meses)),
+						Inflacion:     inflacion,
+					}
+
+					captura := nuevaCaptura(c)
+					if c.IsSet("pesos") {
+						pesos, err := captura.Numero("pesos", "Monto en pesos: ", limitesMonto)
+						if err != nil {
+							return err
+						}
+						r.Pesos = pesos
+						r.UDIS = calc.PesosAUDIs(pesos, r.ValorUDIFecha)
+					} else {
+						udis, err := captura.Numero("udis", "Monto en UDIS: ", limitesMonto)
+						if err != nil {
+							return err
+						}
+						r.UDIS = udis
+						r.Pesos = calc.UDIsAPesos(udis, r.ValorUDIFecha)
+					}
+					r.PesosConstantes = calc.UDIsAPesos(r.UDIS, valor)
+
+					if salidaEstructurada() {
+						return emitirDatos(r)
+					}
+
+					fmt.Println("=== Conversión de UDIS ===")
+					fmt.Printf("Valor de la UDI hoy: $%.6f (%s)\n", valor, origen)
+					if meses > 0 {
+						fmt.Printf("Valor proyectado en %d meses: $%.6f (inflación de %.2f%%)\n", meses, r.ValorUDIFecha, inflacion*100)
+					}
+					if c.IsSet("pesos") {
+						fmt.Printf("RESULTADO: $%.2f = %.2f UDIS\n", r.Pesos, r.UDIS)
+					} else {
+						fmt.Printf("RESULTADO: %.2f UDIS = $%.2f\n", r.UDIS, r.Pesos)
+					}
+					if meses > 0 {
+						fmt.Printf("En pesos de hoy: $%.2f\n", r.PesosConstantes)
+					}
+					return nil
+				},
+			},
+			{
+				Name:        "usd",
+				Usage:       "Convertir entre dólares y pesos",
+				Description: "El tipo de cambio es el FIX publicado por Banxico (requiere FINMEX_BANXICO_TOKEN) o el de --tipo-cambio.",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "usd", Usage: "Monto en dólares a convertir a pesos"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "pesos", Usage: "Monto en pesos a convertir a dólares"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					if c.IsSet("usd") && c.IsSet("pesos") {
+						return errDatosInvalidos("Indica --usd o --pesos, no ambos", "Use either --usd or --pesos, not both")
+					}
+					tipoCambio, origen := TipoCambioVigente()
+					r := ConversionUSD{TipoCambio: tipoCambio, Origen: origen}
+
+					captura := nuevaCaptura(c)
+					if c.IsSet("pesos") {
+						pesos, err := captura.Numero("pesos", "Monto en pesos: ", limitesMonto)
+						if err != nil {
+							return err
+						}
+						r.Pesos, r.USD = pesos, pesos/tipoCambio
+					} else {
+						usd, err := captura.Numero("usd", "Monto en dólares: ", limitesMonto)
+						if err != nil {
+							return err
+						}
+						r.USD, r.Pesos = usd, usd*tipoCambio
+					}
+
+					if salidaEstructurada() {
+						return emitirDatos(r)
+					}
+
+					fmt.Println("=== Conversión de dólares ===")
+					fmt.Printf("Tipo de cambio: $%.4f por dólar (%s)\n", tipoCambio, origen)
+					if c.IsSet("pesos") {
+						fmt.Printf("RESULTADO: $%.2f = US$%.2f\n", r.Pesos, r.USD)
+					} else {
+						fmt.Printf("RESULTADO: US$%.2f = $%.2f\n", r.USD, r.Pesos)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoDaemon inicia el proceso que concentra todas las escrituras de datos
+func comandoDaemon() *cli.Command {
+	return &cli.Command{
+		Name:  "daemon",
+		Usage: "Iniciar un daemon local que sea el único escritor de los archivos de datos",
+		Action: func(c *cli.Context) error {
+			daemon, err := NuevoDaemon()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			listener, err := EscucharSocket()
+			if err != nil {
+				return err
+			}
+			defer os.Remove(rutaDatos(ARCHIVO_SOCKET))
+
+			// Al recibir Ctrl+C o SIGTERM cerramos el socket para salir limpiamente
+			señales := make(chan os.Signal, 1)
+			signal.Notify(señales, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				s := <-señales
+				registro.Info("deteniendo daemon", "señal", s.String())
+				listener.Close()
+			}()
+
+			registro.Info("daemon iniciado", "socket", rutaDatos(ARCHIVO_SOCKET), "pid", os.Getpid())
+			fmt.Printf("Daemon escuchando en %s (Ctrl+C para detener)\n", rutaDatos(ARCHIVO_SOCKET))
+			return daemon.Escuchar(listener)
+		},
+	}
+}
@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoDeuda agrupa las herramientas para liquidar deudas de varias tarjetas
+func comandoDeuda() *cli.Command {
+	return &cli.Command{
+		Name:  "deuda",
+		Usage: "Planear la liquidación de deudas",
+		Subcommands: []*cli.Command{
+			comandoDeudaPlan(),
+			{
+				Name:  "timeline",
+				Usage: "Mostrar mes a mes qué deuda se paga y cuándo se liquida cada una",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "presupuesto", Usage: "Monto mensual total para pagar deudas"}, limitesMonto),
+					&cli.StringFlag{Name: "estrategia", Value: EstrategiaAvalancha, Usage: "Estrategia: avalancha o bola-de-nieve"},
+					flagSalida(),
+				},
+				Action: func(c *cli.Context) error {
+					ndjson, err := salidaNDJSON(c)
+					if err != nil {
+						return err
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					deudas := DeudasRegistradas(tarjetas)
+					if len(deudas) == 0 {
+						return fmt.Errorf("No hay tarjetas de crédito con deuda registrada")
+					}
+
+					presupuesto := c.Float64("presupuesto")
+					if presupuesto <= 0 {
+						if presupuesto, err = valorRequeridoNDJSON(c, ndjson, "presupuesto", "Presupuesto mensual para deudas: ", limitesMonto); err != nil {
+							return err
+						}
+					}
+
+					plan, err := SimularPlanDeuda(deudas, presupuesto, c.String("estrategia"))
+					if err != nil {
+						return err
+					}
+
+					if ndjson {
+						return emitirTimelineDeuda(plan, time.Now())
+					}
+					imprimirTimelineDeuda(plan, time.Now())
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// comandoDeudaPlan captura los saldos de las tarjetas y compara las estrategias de pago
+func comandoDeudaPlan() *cli.Command {
+	return &cli.Command{
+		Name:  "plan",
+		Usage: "Comparar bola de nieve y avalancha para liquidar varias tarjetas con un presupuesto mensual",
+		Flags: []cli.Flag{
+			conLimites(&cli.Float64Flag{Name: "presupuesto", Usage: "Monto mensual total para pagar deudas"}, limitesMonto),
+			&cli.StringFlag{Name: "saldos", Usage: "Saldo por tarjeta sin preguntar, ej: \"Oro=12000,Azul=4500\"; las tarjetas que no aparecen no entran al plan"},
+			&cli.BoolFlag{Name: "guardar", Usage: "Guardar en las tarjetas los saldos capturados"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			if len(tarjetas.Credito) == 0 {
+				return fmt.Errorf("No hay tarjetas de crédito registradas")
+			}
+
+			saldos, err := capturarSaldosDeuda(c, tarjetas)
+			if err != nil {
+				return err
+			}
+			var deudas []DeudaPlan
+			for i, t := range tarjetas.Credito {
+				if saldos[i] > 0 {
+					deudas = append(deudas, DeudaPlan{Nombre: t.Nombre, Saldo: saldos[i], TasaAnual: t.TasaInteres, LimiteCredito: t.LimiteCredito})
+				}
+			}
+			if len(deudas) == 0 {
+				return fmt.Errorf("Ninguna tarjeta tiene saldo por pagar")
+			}
+
+			if c.Bool("guardar") {
+				for i := range tarjetas.Credito {
+					tarjetas.Credito[i].Saldo = saldos[i]
+				}
+				if err := GuardarTarjetas(tarjetas); err != nil {
+					return fmt.Errorf("Error al guardar saldos: %w", err)
+				}
+			}
+
+			presupuesto, err := nuevaCaptura(c).Numero("presupuesto", "Presupuesto mensual total para deudas: ", limitesMonto)
+			if err != nil {
+				return err
+			}
+
+			planes, mejor, err := CompararEstrategiasDeuda(deudas, presupuesto)
+			if err != nil {
+				return err
+			}
+
+			if salidaEstructurada() {
+				return emitirDatos(resumenesPlanDeuda(planes, mejor))
+			}
+			imprimirPlanDeuda(planes, mejor, time.Now())
+			return nil
+		},
+	}
+}
+
+// capturarSaldosDeuda regresa el saldo de cada tarjeta de crédito, en el orden registrado.
+// Con --saldos se toman de la lista; si no, se pregunta por cada tarjeta proponiendo el
+// saldo registrado.
+func capturarSaldosDeuda(c *cli.Context, tarjetas Tarjetas) ([]float64, error) {
+	saldos := make([]float64, len(tarjetas.Credito))
+
+	if c.IsSet("saldos") {
+		for _, parte := range strings.Split(c.String("saldos"), ",") {
+			nombre, texto, ok := strings.Cut(parte, "=")
+			if !ok {
+				return nil, errDatosInvalidos(
+					fmt.Sprintf("Saldo inválido '%s': usa Tarjeta=monto separados por comas", parte),
+					fmt.Sprintf("Invalid balance '%s': use Card=amount separated by commas", parte))
+			}
+			i, ok := indiceCredito(tarjetas, strings.TrimSpace(nombre))
+			if !ok {
+				return nil, errTarjetaNoEncontrada("credito", strings.TrimSpace(nombre))
+			}
+			saldo, err := ParsearNumero(strings.TrimSpace(texto))
+			if err == nil {
+				err = validarLimites(saldo, limitesMonto)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("--saldos %s: %w", strings.TrimSpace(nombre), err)
+			}
+			saldos[i] = saldo
+		}
+		return saldos, nil
+	}
+
+	fmt.Println("Captura el saldo actual de cada tarjeta (Enter conserva el registrado):")
+	for i, t := range tarjetas.Credito {
+		saldo, err := leerNumeroConValor(fmt.Sprintf("  %s (%.2f%% anual)", t.Nombre, t.TasaInteres*100), t.Saldo, limitesMonto)
+		if err != nil {
+			return nil, err
+		}
+		saldos[i] = saldo
+	}
+	return saldos, nil
+}
+
+// imprimirPlanDeuda muestra la comparación de estrategias y los pagos de la recomendada
+func imprimirPlanDeuda(planes []PlanDeuda, mejor int, inicio time.Time) {
+	fmt.Printf("=== Plan de pago de deudas ($%.2f al mes) ===\n", planes[mejor].Presupuesto)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Estrategia\tMeses\tIntereses totales\tTotal pagado\tOrden de liquidación")
+	fmt.Fprintln(w, "----------\t-----\t-----------------\t------------\t--------------------")
+	for _, p := range planes {
+		fmt.Fprintf(w, "%s\t%d\t$%.2f\t$%.2f\t%s\n",
+			p.Estrategia, len(p.Meses), p.InteresTotal, p.TotalPagado(), strings.Join(p.Orden, " → "))
+	}
+	w.Flush()
+
+	p := planes[mejor]
+	fmt.Printf("\n=== Pagos con %s ===\n", p.Estrategia)
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Tarjeta\tSaldo\tTasa\tPago del primer mes\tSe liquida")
+	fmt.Fprintln(w, "-------\t-----\t----\t-------------------\t----------")
+	for i, d := range p.Deudas {
+		mes := p.MesLiquidacion(d.Nombre)
+		fmt.Fprintf(w, "%s\t$%.2f\t%.2f%%\t$%.2f\t%s (mes %d)\n",
+			d.Nombre, d.Saldo, d.TasaAnual*100, p.Meses[0].Pagos[i], calc.SumarMeses(inicio, mes).Format("2006-01"), mes)
+	}
+	w.Flush()
+
+	otra := planes[1-mejor]
+	ahorro := otra.InteresTotal - p.InteresTotal
+	if ahorro > 0.005 {
+		fmt.Printf("\nRESULTADO: Con %s liquidas todo en %d meses y pagas $%.2f menos de intereses que con %s\n",
+			p.Estrategia, len(p.Meses), ahorro, otra.Estrategia)
+	} else {
+		fmt.Printf("\nRESULTADO: Ambas estrategias cuestan lo mismo; liquidas todo en %d meses\n", len(p.Meses))
+	}
+	// Los dos planes terminan de liquidar todo, así que Orden nunca está vacío
+	primera, primeraOtra := p.MesLiquidacion(p.Orden[0]), otra.MesLiquidacion(otra.Orden[0])
+	if primeraOtra < primera {
+		fmt.Printf("Con %s liquidas la primera tarjeta (%s) en el mes %d en lugar del %d, si te motiva ver avances pronto\n",
+			otra.Estrategia, otra.Orden[0], primeraOtra, primera)
+	}
+}
+
+// ResumenPlanDeuda es el resultado de una estrategia en la salida estructurada
+type ResumenPlanDeuda struct {
+	Estrategia   string   `json:"estrategia"`
+	Meses        int      `json:"meses"`
+	InteresTotal float64  `json:"interes_total"`
+	TotalPagado  float64  `json:"total_pagado"`
+	Orden        []string `json:"orden"`
+	Recomendada  bool     `json:"recomendada"`
+}
+
+// resumenesPlanDeuda convierte los planes comparados a su forma estructurada
+func resumenesPlanDeuda(planes []PlanDeuda, mejor int) []ResumenPlanDeuda {
+	resumenes := make([]ResumenPlanDeuda, len(planes))
+	for i, p := range planes {
+		resumenes[i] = ResumenPlanDeuda{
+			Estrategia:   p.Estrategia,
+			Meses:        len(p.Meses),
+			InteresTotal: p.InteresTotal,
+			TotalPagado:  p.TotalPagado(),
+			Orden:        p.Orden,
+			Recomendada:  i == mejor,
+		}
+	}
+	return resumenes
+}
+
+// imprimirTimelineDeuda muestra el plan como línea de tiempo con el saldo restante en barra
+func imprimirTimelineDeuda(plan PlanDeuda, inicio time.Time) {
+	total := 0.0
+	for _, d := range plan.Deudas {
+		total += d.Saldo
+	}
+
+	fmt.Printf("=== Línea de tiempo (%s, $%.2f al mes) ===\n", plan.Estrategia, plan.Presupuesto)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Mes\tPagando\tSaldo restante\t\tIntereses acumulados\tEvento")
+	fmt.Fprintln(w, "---\t-------\t--------------\t\t--------------------\t------")
+
+	for _, m := range plan.Meses {
+		evento := ""
+		if len(m.Liquidadas) > 0 {
+			evento = "Liquidas " + strings.Join(m.Liquidadas, ", ")
+		}
+		foco := m.Foco
+		if foco == "" {
+			foco = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t$%.2f\t$%.2f\t%s\n",
+			inicio.AddDate(0, m.Mes, 0).Format("2006-01"), foco,
+			BarraProgreso(m.SaldoTotal()/total, 20), m.SaldoTotal(), m.InteresAcumulado, evento)
+	}
+
+	w.Flush()
+	fmt.Printf("\nLiquidas todo en %d meses pagando $%.2f de intereses\n", len(plan.Meses), plan.InteresTotal)
+	fmt.Printf("Orden de liquidación: %s\n", strings.Join(plan.Orden, " → "))
+}
+
+// MesTimelineDeuda es un mes del plan de deudas en salida NDJSON
+type MesTimelineDeuda struct {
+	Mes              string             `json:"mes"` // AAAA-MM
+	Foco             string             `json:"foco,omitempty"`
+	Pagos            map[string]float64 `json:"pagos"`
+	Saldos           map[string]float64 `json:"saldos"`
+	Interes          float64            `json:"interes"`
+	InteresAcumulado float64            `json:"interes_acumulado"`
+	SaldoTotal       float64            `json:"saldo_total"`
+	Liquidadas       []string           `json:"liquidadas,omitempty"`
+}
+
+// emitirTimelineDeuda escribe cada mes del plan como una línea JSON
+func emitirTimelineDeuda(plan PlanDeuda, inicio time.Time) error {
+	emisor := NuevoEmisorNDJSON(salidaDatos())
+	for _, m := range plan.Meses {
+		mes := MesTimelineDeuda{
+			Mes:              inicio.AddDate(0, m.Mes, 0).Format("2006-01"),
+			Foco:             m.Foco,
+			Pagos:            map[string]float64{},
+			Saldos:           map[string]float64{},
+			Interes:          m.Interes,
+			InteresAcumulado: m.InteresAcumulado,
+			SaldoTotal:       m.SaldoTotal(),
+			Liquidadas:       m.Liquidadas,
+		}
+		for i, d := range plan.Deudas {
+			mes.Pagos[d.Nombre] = m.Pagos[i]
+			mes.Saldos[d.Nombre] = m.Saldos[i]
+		}
+		if err := emisor.Emitir(mes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
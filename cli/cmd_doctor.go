@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoDoctor revisa la consistencia de los datos registrados y sugiere correcciones
+func comandoDoctor() *cli.Command {
+	return &cli.Command{
+		Name:  "doctor",
+		Usage: "Revisar los datos registrados en busca de inconsistencias",
+		Description: "Detecta tasas capturadas como porcentaje en lugar de decimal (36 en vez de 0.36), CAT\n" +
+			"menor a la tasa de interés, nombres repetidos, límites de crédito en cero, días de corte\n" +
+			"y fechas que no se pueden interpretar, entre otras cosas, y sugiere cómo corregirlas.\n" +
+			"Con --corregir se aplican las correcciones seguras, como pasar los porcentajes a decimal;\n" +
+			"las demás se muestran para hacerlas a mano.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "corregir", Usage: "Aplicar y guardar las correcciones seguras"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			hallazgos := DiagnosticarDatos(tarjetas)
+			corregidos := 0
+			if c.Bool("corregir") {
+				if corregidos = CorregirHallazgos(&tarjetas, hallazgos); corregidos > 0 {
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar tarjetas: %w", err)
+					}
+				}
+				hallazgos = DiagnosticarDatos(tarjetas)
+			}
+			if salidaEstructurada() {
+				return emitirDatos(hallazgos)
+			}
+
+			if corregidos > 0 {
+				fmt.Printf("Se corrigieron %d datos.\n\n", corregidos)
+			}
+			errores, avisos, corregibles := 0, 0, 0
+			for _, h := range hallazgos {
+				etiqueta := "AVISO"
+				if h.Severidad == SeveridadError {
+					etiqueta = "ERROR"
+					errores++
+				} else {
+					avisos++
+				}
+				if h.Corregible {
+					corregibles++
+				}
+				fmt.Printf("%s: [%s] %s: %s\n", etiqueta, h.Producto, h.Nombre, h.Problema)
+				fmt.Printf("  Sugerencia: %s\n", h.Sugerencia)
+			}
+
+			if len(hallazgos) == 0 {
+				fmt.Println("RESULTADO: No se encontraron inconsistencias en los datos")
+				return nil
+			}
+			var partes []string
+			if errores > 0 {
+				partes = append(partes, fmt.Sprintf("%d errores", errores))
+			}
+			if avisos > 0 {
+				partes = append(partes, fmt.Sprintf("%d avisos", avisos))
+			}
+			fmt.Printf("\nRESULTADO: %s\n", strings.Join(partes, " y "))
+			if corregibles > 0 {
+				fmt.Printf("%d se pueden corregir solos con 'finmex doctor --corregir'\n", corregibles)
+			}
+			if errores > 0 {
+				return errDatosInvalidos(
+					fmt.Sprintf("Los datos tienen %d errores", errores),
+					fmt.Sprintf("The data has %d errors", errores))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoEscenario agrupa las simulaciones "¿qué pasa si?" con otros supuestos
+func comandoEscenario() *cli.Command {
+	return &cli.Command{
+		Name:  "escenario",
+		Usage: "Simular \"¿qué pasa si?\" con otra inflación, ISR o tasas",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "ejecutar",
+				Usage:     "Correr cualquier análisis con los supuestos del escenario",
+				ArgsUsage: "<comando> [argumentos]",
+				Description: "Los flags del escenario van antes del comando. Nada se guarda mientras corre el escenario.\n" +
+					"Ejemplo: finmex escenario ejecutar --inflacion 0.06 --isr 0.0115 comparar debito --saldo 50000",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "inflacion", Usage: "Inflación anual (decimal) en lugar de la vigente"}, limitesInflacion),
+					conLimites(&cli.Float64Flag{Name: "isr", Usage: "Retención anual de ISR sobre el capital (decimal) en lugar de la de la LIF"}, limitesRetencionEscenario),
+					conLimites(&cli.Float64Flag{Name: "ajuste-tasas", Usage: "Suma a las tasas de rendimiento de tus cuentas y del catálogo; negativo si bajan"}, limitesAjusteTasas),
+				},
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return fmt.Errorf("Indica el comando a simular, p. ej. 'finmex escenario ejecutar --inflacion 0.06 debito listar'")
+					}
+					nombre := c.Args().First()
+					comando := c.App.Command(nombre)
+					if comando == nil || comando.Name == "escenario" {
+						return errDatosInvalidos(
+							fmt.Sprintf("'%s' no es un comando que se pueda simular", nombre),
+							fmt.Sprintf("'%s' is not a command that can be simulated", nombre))
+					}
+
+					e := Escenario{Nombre: "escenario", AjusteTasas: c.Float64("ajuste-tasas")}
+					if c.IsSet("inflacion") {
+						inflacion := c.Float64("inflacion")
+						e.Inflacion = &inflacion
+					}
+					if c.IsSet("isr") {
+						isr := c.Float64("isr")
+						e.RetencionISR = &isr
+					}
+					defer activarEscenario(e)()
+					if !salidaEstructurada() {
+						fmt.Printf("AVISO: Escenario con %s\n", e.Descripcion())
+					}
+
+					// El comando corre bajo la raíz para conservar las opciones globales ya aplicadas
+					linaje := c.Lineage()
+					raiz := linaje[len(linaje)-1]
+					return comando.Run(cli.NewContext(c.App, nil, raiz), c.Args().Slice()...)
+				},
+			},
+			{
+				Name:      "comparar",
+				Usage:     "Comparar el rendimiento real de tus cuentas en dos o tres escenarios",
+				ArgsUsage: "[nombre:supuesto=valor,... ...]",
+				Description: "Cada escenario es un nombre y, opcionalmente, los supuestos inflacion, isr y ajuste-tasas.\n" +
+					"Sin escenarios se comparan optimista (inflación un punto menor), base (supuestos vigentes)\n" +
+					"y pesimista (inflación dos puntos mayor y tasas un punto más bajas).\n" +
+					"Ejemplo: finmex escenario comparar base \"pesimista:inflacion=0.06,isr=0.0115,ajuste-tasas=-0.01\"",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "saldo", Usage: "Saldo para las cuentas de débito que no tienen uno registrado"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					escenarios := EscenariosPredefinidos()
+					if c.NArg() > 0 {
+						escenarios = nil
+						for _, texto := range c.Args().Slice() {
+							e, err := ParsearEscenario(texto)
+							if err != nil {
+								return err
+							}
+							escenarios = append(escenarios, e)
+						}
+					}
+					if len(escenarios) < 2 || len(escenarios) > 3 {
+						return errDatosInvalidos(
+							fmt.Sprintf("Se comparan dos o tres escenarios, no %d", len(escenarios)),
+							fmt.Sprintf("Compare two or three scenarios, not %d", len(escenarios)))
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					resultados := make([]ResultadoEscenario, len(escenarios))
+					for i, e := range escenarios {
+						resultados[i] = EvaluarEscenario(e, tarjetas, c.Float64("saldo"))
+					}
+					if len(resultados[0].Cuentas) == 0 {
+						return fmt.Errorf("No hay cuentas con saldo que comparar; registra saldos o usa --saldo")
+					}
+
+					if salidaEstructurada() {
+						if formatoDatos == FormatoCSV {
+							var filas []FilaEscenario
+							for _, r := range resultados {
+								for _, cuenta := range r.Cuentas {
+									filas = append(filas, FilaEscenario{r.Nombre, cuenta})
+								}
+							}
+							return emitirDatos(filas)
+						}
+						return emitirDatos(resultados)
+					}
+					imprimirEscenarios(resultados)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// imprimirEscenarios muestra los escenarios lado a lado, una columna cada uno
+func imprimirEscenarios(resultados []ResultadoEscenario) {
+	fmt.Println("\n=== Comparación de Escenarios ===")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fila := func(concepto string, valor func(r ResultadoEscenario) string) {
+		celdas := []string{concepto}
+		for _, r := range resultados {
+			celdas = append(celdas, valor(r))
+		}
+		fmt.Fprintln(w, strings.Join(celdas, "\t"))
+	}
+	fila("Concepto", func(r ResultadoEscenario) string { return r.Nombre })
+	fila("--------", func(r ResultadoEscenario) string { return strings.Repeat("-", len([]rune(r.Nombre))) })
+	fila("Inflación", func(r ResultadoEscenario) string { return fmt.Sprintf("%.2f%%", r.InflacionUsada*100) })
+	fila("ISR", func(r ResultadoEscenario) string { return r.ISR })
+	fila("Ajuste de tasas", func(r ResultadoEscenario) string { return fmt.Sprintf("%+.2f pts", r.AjusteTasas*100) })
+	for i, cuenta := range resultados[0].Cuentas {
+		fila(fmt.Sprintf("%s ($%.2f)", cuenta.Cuenta, cuenta.Saldo), func(r ResultadoEscenario) string {
+			c := r.Cuentas[i]
+			return fmt.Sprintf("$%.2f (%.2f%%)", c.RendimientoReal, c.RendimientoRealPct)
+		})
+	}
+	fila("Rendimiento real total", func(r ResultadoEscenario) string { return fmt.Sprintf("$%.2f", r.RendimientoReal) })
+	w.Flush()
+
+	mejor, peor := resultados[0], resultados[0]
+	for _, r := range resultados[1:] {
+		if r.RendimientoReal > mejor.RendimientoReal {
+			mejor = r
+		}
+		if r.RendimientoReal < peor.RendimientoReal {
+			peor = r
+		}
+	}
+	fmt.Printf("\nRESULTADO: Entre %s y %s tu rendimiento real cambia $%.2f al año\n",
+		mejor.Nombre, peor.Nombre, mejor.RendimientoReal-peor.RendimientoReal)
+	if peor.RendimientoReal < 0 {
+		fmt.Printf("ALERTA: En el escenario %s tus cuentas pierden $%.2f de poder de compra al año\n", peor.Nombre, -peor.RendimientoReal)
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ResultadoImportacionMovimientos es el resultado de movimientos importar con --output
+type ResultadoImportacionMovimientos struct {
+	Archivo    string       `json:"archivo"`
+	Banco      string       `json:"banco"`
+	Tarjeta    string       `json:"tarjeta"`
+	Renglones  int          `json:"renglones"`
+	Importados int          `json:"importados"`
+	Repetidos  int          `json:"repetidos"` // Ya estaban en el historial
+	Cargos     float64      `json:"cargos"`
+	Abonos     float64      `json:"abonos"`
+	Rechazos   []RechazoCSV `json:"rechazos"`
+	Guardado   bool         `json:"guardado"`
+}
+
+// comandoMovimientosImportar agrega al historial los movimientos del CSV de un banco
+func comandoMovimientosImportar() *cli.Command {
+	return &cli.Command{
+		Name:      "importar",
+		Usage:     "Importar los movimientos del estado de cuenta CSV de un banco",
+		ArgsUsage: "<estado.csv>",
+		Description: "Reconoce los CSV que exportan " + clavesBanco() + "; sin --banco el formato se detecta por el encabezado.\n" +
+			"Los movimientos que ya están en el historial no se duplican, así que se puede importar el mismo periodo otra vez.\n" +
+			"Ejemplo: finmex movimientos importar --banco bbva --tarjeta \"BBVA Azul\" estado.csv",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "banco", Usage: "Banco que generó el archivo: " + clavesBanco()},
+			&cli.StringFlag{Name: "tarjeta", Usage: "Tarjeta registrada a la que pertenecen los movimientos; por defecto la única del banco"},
+			&cli.BoolFlag{Name: "simular", Usage: "Validar el archivo y mostrar qué se importaría sin guardar nada"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("Indica el archivo CSV a importar")
+			}
+			ruta := c.Args().First()
+
+			archivo, err := os.Open(ruta)
+			if err != nil {
+				return fmt.Errorf("Error al abrir %s: %v", ruta, err)
+			}
+			estado, err := LeerEstadoBancarioCSV(archivo, c.String("banco"))
+			archivo.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", ruta, err)
+			}
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			tarjeta, err := TarjetaEstadoBancario(tarjetas, c.String("tarjeta"), estado.Banco)
+			if err != nil {
+				return err
+			}
+			scripts, err := CargarScripts()
+			if err != nil {
+				return err
+			}
+			for i := range estado.Movimientos {
+				m := &estado.Movimientos[i]
+				m.Tarjeta = tarjeta
+				if m.Categoria, err = scripts.Categorizar(*m); err != nil {
+					return err
+				}
+			}
+
+			nuevos, repetidos, err := DescartarMovimientosRegistrados(estado.Movimientos)
+			if err != nil {
+				return fmt.Errorf("Error al leer movimientos: %w", err)
+			}
+			r := ResultadoImportacionMovimientos{
+				Archivo: ruta, Banco: estado.Banco.Nombre, Tarjeta: tarjeta, Renglones: estado.Renglones,
+				Importados: len(nuevos), Repetidos: repetidos, Rechazos: estado.Rechazos,
+			}
+			for _, m := range nuevos {
+				if m.Monto > 0 {
+					r.Cargos += m.Monto
+				} else {
+					r.Abonos -= m.Monto
+				}
+			}
+			if len(nuevos) > 0 && !c.Bool("simular") {
+				if err := AgregarMovimientos(nuevos); err != nil {
+					return fmt.Errorf("Error al guardar movimientos: %w", err)
+				}
+				r.Guardado = true
+			}
+
+			if salidaEstructurada() {
+				if r.Rechazos == nil {
+					r.Rechazos = []RechazoCSV{}
+				}
+				return emitirDatos(r)
+			}
+
+			if len(r.Rechazos) > 0 {
+				fmt.Printf("Filas rechazadas (%d):\n", len(r.Rechazos))
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+				fmt.Fprintln(w, "Fila\tDescripción\tMotivo")
+				fmt.Fprintln(w, "----\t-----------\t------")
+				for _, rechazo := range r.Rechazos {
+					fmt.Fprintf(w, "%d\t%s\t%s\n", rechazo.Fila, rechazo.Nombre, rechazo.Motivo)
+				}
+				w.Flush()
+			}
+
+			fmt.Printf("Formato: %s, tarjeta: %s\n", r.Banco, r.Tarjeta)
+			if r.Repetidos > 0 {
+				fmt.Printf("AVISO: %d movimientos ya estaban en el historial y no se duplicaron\n", r.Repetidos)
+			}
+			verbo := "importaron"
+			if c.Bool("simular") {
+				verbo = "importarían"
+			}
+			fmt.Printf("RESULTADO: Se %s %d movimientos de %d filas (cargos por $%.2f, abonos por $%.2f)\n",
+				verbo, r.Importados, r.Renglones, r.Cargos, r.Abonos)
+			if r.Importados+r.Repetidos == 0 && r.Renglones > 0 {
+				return errDatosInvalidos(
+					fmt.Sprintf("Ninguna fila de %s se pudo importar", ruta),
+					fmt.Sprintf("No row of %s could be imported", ruta))
+			}
+			return nil
+		},
+	}
+}
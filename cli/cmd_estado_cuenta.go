@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCreditoInferirTasa deduce la tasa cobrada a partir de los datos del estado de cuenta
+func comandoCreditoInferirTasa() *cli.Command {
+	return &cli.Command{
+		Name:  "inferir-tasa",
+		Usage: "Inferir la tasa que cobra el banco a partir del estado de cuenta",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tarjeta", Required: true, Usage: "Nombre de la tarjeta"},
+			conLimites(&cli.Float64Flag{Name: "saldo-anterior", Required: true, Usage: "Saldo al corte anterior"}, limitesMonto),
+			conLimites(&cli.Float64Flag{Name: "pagos", Usage: "Pagos del periodo"}, limitesMonto),
+			conLimites(&cli.Float64Flag{Name: "compras", Usage: "Compras y cargos del periodo"}, limitesMonto),
+			conLimites(&cli.Float64Flag{Name: "interes", Required: true, Usage: "Interés cobrado en el periodo"}, limitesMonto),
+			&cli.BoolFlag{Name: "con-iva", Usage: "El interés capturado ya incluye IVA"},
+			&cli.IntFlag{Name: "dias", Value: 30, Usage: "Días del periodo"},
+			&cli.BoolFlag{Name: "actualizar", Usage: "Guardar la tasa inferida como la tasa de la tarjeta"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, ok := indiceCredito(tarjetas, c.String("tarjeta"))
+			if !ok {
+				return errTarjetaNoEncontrada("credito", c.String("tarjeta"))
+			}
+			tarjeta := tarjetas.Credito[i]
+
+			estado := EstadoCuenta{
+				SaldoAnterior: c.Float64("saldo-anterior"),
+				Pagos:         c.Float64("pagos"),
+				Compras:       c.Float64("compras"),
+				Interes:       c.Float64("interes"),
+				InteresConIVA: c.Bool("con-iva"),
+				Dias:          c.Int("dias"),
+			}
+			t, err := InferirTasa(tarjeta, estado)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("=== Tasa Inferida (%s) ===\n", tarjeta.Nombre)
+			fmt.Printf("Saldo promedio estimado: $%.2f\n", t.SaldoPromedio)
+			fmt.Printf("Interés sin IVA: $%.2f\n", t.InteresSinIVA)
+			fmt.Printf("Tasa mensual cobrada: %.4f%%\n", t.Mensual*100)
+			fmt.Printf("Tasa anual cobrada: %.2f%%\n", t.Anual*100)
+			fmt.Printf("Tasa capturada: %.2f%%\n", t.Capturada*100)
+
+			switch {
+			case t.Aumento():
+				fmt.Printf("ALERTA: El banco te cobra %.2f puntos más de lo que tienes registrado; revisa si subió tu tasa\n", t.Diferencia*100)
+			case t.Disminucion():
+				fmt.Printf("RESULTADO: Te cobran %.2f puntos menos de lo registrado; tu tasa capturada puede estar desactualizada\n", -t.Diferencia*100)
+			default:
+				fmt.Println("RESULTADO: La tasa cobrada coincide con la registrada")
+			}
+
+			if c.Bool("actualizar") {
+				tarjetas.Credito[i].TasaInteres = t.Anual
+				if err := GuardarTarjetas(tarjetas); err != nil {
+					return fmt.Errorf("Error al guardar tarjeta: %w", err)
+				}
+				fmt.Printf("Tasa de %s actualizada a %.2f%%\n", tarjeta.Nombre, t.Anual*100)
+			}
+			return nil
+		},
+	}
+}
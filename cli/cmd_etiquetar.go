@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoEtiquetar reemplaza las etiquetas de una tarjeta de débito o de crédito
+func comandoEtiquetar() *cli.Command {
+	return &cli.Command{
+		Name:      "etiquetar",
+		Usage:     "Asignar etiquetas a una tarjeta para filtrar comparaciones",
+		ArgsUsage: "<tarjeta> <etiquetas separadas por comas>",
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				return fmt.Errorf("Uso: finmex etiquetar <tarjeta> \"viajes,familia\" (sin etiquetas para quitarlas)")
+			}
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			// Las etiquetas son el último argumento, salvo que todos juntos sean el nombre de
+			// una tarjeta con espacios, como en etiquetar Nu Morada para quitarle las etiquetas
+			args := c.Args().Slice()
+			nombre, etiquetas := argumentoNombre(args), []string(nil)
+			_, esDebito := indiceDebito(tarjetas, nombre)
+			_, esCredito := indiceCredito(tarjetas, nombre)
+			if !esDebito && !esCredito && len(args) > 1 {
+				nombre, etiquetas = argumentoNombre(args[:len(args)-1]), ParsearLista(args[len(args)-1])
+			}
+
+			encontradas := 0
+			for i := range tarjetas.Debito {
+				if normalizarClave(tarjetas.Debito[i].Nombre) == normalizarClave(nombre) {
+					tarjetas.Debito[i].Tags = etiquetas
+					encontradas++
+				}
+			}
+			for i := range tarjetas.Credito {
+				if normalizarClave(tarjetas.Credito[i].Nombre) == normalizarClave(nombre) {
+					tarjetas.Credito[i].Tags = etiquetas
+					encontradas++
+				}
+			}
+			if encontradas == 0 {
+				return errTarjetaNoEncontrada("", nombre)
+			}
+
+			if err := GuardarTarjetas(tarjetas); err != nil {
+				return fmt.Errorf("Error al guardar tarjeta: %w", err)
+			}
+			fmt.Printf("Etiquetas de '%s' actualizadas (%d tarjeta(s))\n", nombre, encontradas)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoExportar escribe los datos registrados como JSON, o las tarjetas como CSV,
+// opcionalmente anonimizados
+func comandoExportar() *cli.Command {
+	return &cli.Command{
+		Name:  "exportar",
+		Usage: "Exportar tus tarjetas, productos y movimientos como JSON, o tus tarjetas como CSV",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "archivo", Usage: "Archivo de salida; por defecto se imprime en pantalla"},
+			&cli.StringFlag{Name: "formato", Value: "json", Usage: "json (todos los datos) o csv (tarjetas de débito y crédito, para editarlas en Excel e importarlas)"},
+			&cli.BoolFlag{Name: "anonimizar", Usage: "Reemplazar nombres por hashes y escalar los montos para poder compartir"},
+			&cli.StringFlag{Name: "movimientos", Usage: "Escribir también el historial de movimientos como NDJSON en este archivo"},
+		},
+		Action: func(c *cli.Context) error {
+			formato := c.String("formato")
+			if formato != "json" && formato != "csv" {
+				return fmt.Errorf("Formato inválido '%s' (usa json o csv)", formato)
+			}
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			var anonimizador *Anonimizador
+			if c.Bool("anonimizar") {
+				if anonimizador, err = NuevoAnonimizador(); err != nil {
+					return fmt.Errorf("Error al preparar la anonimización: %v", err)
+				}
+				tarjetas = anonimizador.Tarjetas(tarjetas)
+			}
+
+			if archivo := c.String("movimientos"); archivo != "" {
+				n, err := exportarMovimientos(archivo, anonimizador)
+				if err != nil {
+					return fmt.Errorf("Error al exportar movimientos: %w", err)
+				}
+				fmt.Fprintf(os.Stderr, "%d movimientos exportados a %s\n", n, archivo)
+			}
+
+			var data []byte
+			if formato == "csv" {
+				var b bytes.Buffer
+				if err := EscribirTarjetasCSV(&b, tarjetas); err != nil {
+					return err
+				}
+				data = bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+			} else if data, err = json.MarshalIndent(tarjetas, "", "  "); err != nil {
+				return err
+			}
+
+			if c.String("archivo") == "" {
+				fmt.Println(string(data))
+				return nil
+			}
+			if err := ioutil.WriteFile(c.String("archivo"), append(data, '\n'), 0644); err != nil {
+				return fmt.Errorf("Error al escribir %s: %v", c.String("archivo"), err)
+			}
+			fmt.Printf("Datos exportados a %s\n", c.String("archivo"))
+			if c.Bool("anonimizar") {
+				fmt.Println("Los nombres se reemplazaron por hashes y los montos se escalaron; las tasas, plazos y fechas se conservan.")
+			}
+			return nil
+		},
+	}
+}
+
+// ResultadoImportacion es el resultado de importar con --output
+type ResultadoImportacion struct {
+	Archivo      string       `json:"archivo"`
+	Renglones    int          `json:"renglones"`
+	Nuevas       int          `json:"nuevas"`
+	Actualizadas int          `json:"actualizadas"`
+	Rechazos     []RechazoCSV `json:"rechazos"`
+	Guardado     bool         `json:"guardado"`
+}
+
+// comandoImportar agrega las tarjetas de un CSV como el de exportar --formato csv
+func comandoImportar() *cli.Command {
+	return &cli.Command{
+		Name:      "importar",
+		Usage:     "Importar tarjetas de débito y crédito desde un CSV",
+		ArgsUsage: "<archivo.csv>",
+		Description: "El encabezado debe tener las columnas tipo y nombre; las demás son opcionales y en cualquier orden:\n" +
+			"  " + strings.Join(ColumnasCSVTarjetas, ", ") + "\n" +
+			"Las tarjetas con el mismo nombre que una registrada la reemplazan. Las filas inválidas se reportan y no se importan.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "reemplazar", Usage: "Descartar las tarjetas de débito y crédito registradas antes de importar"},
+			&cli.BoolFlag{Name: "simular", Usage: "Validar el archivo y mostrar qué se importaría sin guardar nada"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("Indica el archivo CSV a importar")
+			}
+			ruta := c.Args().First()
+
+			archivo, err := os.Open(ruta)
+			if err != nil {
+				return fmt.Errorf("Error al abrir %s: %v", ruta, err)
+			}
+			leidas, err := LeerTarjetasCSV(archivo)
+			archivo.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", ruta, err)
+			}
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			r := ResultadoImportacion{Archivo: ruta, Renglones: leidas.Renglones, Rechazos: leidas.Rechazos}
+			r.Nuevas, r.Actualizadas = CombinarTarjetasCSV(&tarjetas, leidas, c.Bool("reemplazar"))
+
+			if r.Nuevas+r.Actualizadas > 0 && !c.Bool("simular") {
+				if err := GuardarTarjetas(tarjetas); err != nil {
+					return fmt.Errorf("Error al guardar tarjetas: %w", err)
+				}
+				r.Guardado = true
+			}
+
+			if salidaEstructurada() {
+				if r.Rechazos == nil {
+					r.Rechazos = []RechazoCSV{}
+				}
+				return emitirDatos(r)
+			}
+
+			if len(r.Rechazos) > 0 {
+				fmt.Printf("Filas rechazadas (%d):\n", len(r.Rechazos))
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+				fmt.Fprintln(w, "Fila\tNombre\tMotivo")
+				fmt.Fprintln(w, "----\t------\t------")
+				for _, rechazo := range r.Rechazos {
+					fmt.Fprintf(w, "%d\t%s\t%s\n", rechazo.Fila, rechazo.Nombre, rechazo.Motivo)
+				}
+				w.Flush()
+			}
+
+			verbo := "importaron"
+			if c.Bool("simular") {
+				verbo = "importarían"
+			}
+			fmt.Printf("RESULTADO: Se %s %d tarjetas de %d filas (%d nuevas, %d actualizadas)\n",
+				verbo, r.Nuevas+r.Actualizadas, r.Renglones, r.Nuevas, r.Actualizadas)
+			if r.Nuevas+r.Actualizadas == 0 && r.Renglones > 0 {
+				return errDatosInvalidos(
+					fmt.Sprintf("Ninguna fila de %s se pudo importar", ruta),
+					fmt.Sprintf("No row of %s could be imported", ruta))
+			}
+			return nil
+		},
+	}
+}
+
+// exportarMovimientos escribe el historial en el archivo, un movimiento por línea, y
+// regresa cuántos se escribieron. Con anonimizador los movimientos salen anonimizados.
+func exportarMovimientos(ruta string, anonimizador *Anonimizador) (int, error) {
+	archivo, err := os.Create(ruta)
+	if err != nil {
+		return 0, err
+	}
+	defer archivo.Close()
+
+	w := bufio.NewWriter(archivo)
+	encoder := json.NewEncoder(w)
+	n := 0
+	err = RecorrerMovimientos(func(m Movimiento) error {
+		if anonimizador != nil {
+			m = anonimizador.Movimiento(m)
+		}
+		n++
+		return encoder.Encode(m)
+	})
+	if err != nil {
+		return n, err
+	}
+	if err := w.Flush(); err != nil {
+		return n, err
+	}
+	return n, archivo.Close()
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoFondoAhorro simula el fondo de ahorro de la empresa contra invertir por cuenta propia
+func comandoFondoAhorro() *cli.Command {
+	return &cli.Command{
+		Name:  "fondo-ahorro",
+		Usage: "Simular el fondo de ahorro de la empresa",
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			f := FondoAhorro{TopePatronal: TOPE_FONDO_AHORRO}
+
+			if f.SalarioMensual, err = leerNumero("Salario mensual bruto: ", limitesMonto); err != nil {
+				return err
+			}
+			if f.Aportacion, err = leerNumero("Porcentaje que aportas al fondo (decimal, ej. 0.13): ", limitesFraccion); err != nil {
+				return err
+			}
+			if f.TasaRendimiento, err = leerNumero("Tasa anual que paga el fondo (decimal, 0 si no paga): ", limitesTasa); err != nil {
+				return err
+			}
+			if f.TasaMarginal, err = leerNumero("Tasa marginal de ISR (decimal): ", limitesTasaMarginal); err != nil {
+				return err
+			}
+
+			_, cuenta := MejorTasaDebitoNeta(tarjetas)
+			pregunta := "Tasa anual bruta a la que invertirías por tu cuenta, ej. CETES (decimal): "
+			if cuenta != "" {
+				pregunta = fmt.Sprintf("Tasa anual bruta a la que invertirías por tu cuenta, ej. CETES o %s (decimal): ", cuenta)
+			}
+			tasaAlternativa, err := leerNumero(pregunta, limitesTasa)
+			if err != nil {
+				return err
+			}
+
+			año := time.Now().Year()
+			s, err := SimularFondoAhorro(f, tasaAlternativa, año)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("\n=== Fondo de Ahorro (12 meses) ===")
+			fmt.Printf("Tu aportación: $%.2f\n", s.AportacionTrabajador)
+			fmt.Printf("Aportación de la empresa: $%.2f (exenta: $%.2f)\n", s.AportacionPatronal, s.Exento)
+			if s.ISR > 0 {
+				fmt.Printf("ISR por la parte gravada: $%.2f\n", s.ISR)
+			}
+			fmt.Printf("Rendimientos del fondo: $%.2f\n", s.Intereses)
+			fmt.Printf("Total al cierre del fondo: $%.2f\n", s.Acumulado)
+
+			fmt.Println("\n=== Invirtiendo tu aportación por tu cuenta ===")
+			fmt.Printf("Tasa neta después de ISR: %.2f%%\n", TasaNetaISR(tasaAlternativa)*100)
+			fmt.Printf("Rendimientos: $%.2f\n", s.PropioIntereses)
+			fmt.Printf("Total: $%.2f\n", s.PropioAcumulado)
+
+			if s.Ventaja() > 0 {
+				fmt.Printf("\nRESULTADO: El fondo de ahorro te deja $%.2f más al año\n", s.Ventaja())
+			} else {
+				fmt.Printf("\nRESULTADO: Invertir por tu cuenta te deja $%.2f más al año\n", -s.Ventaja())
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoHipoteca agrupa los análisis de créditos hipotecarios
+func comandoHipoteca() *cli.Command {
+	return &cli.Command{
+		Name:  "hipoteca",
+		Usage: "Análisis de créditos hipotecarios de banco, Infonavit y Fovissste",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "simular",
+				Usage: "Calcular mensualidad, tabla de amortización, CAT y el efecto de pagos anticipados",
+				Description: "Modos:\n" +
+					"  banco              crédito bancario en pesos (--tasa obligatoria)\n" +
+					"  infonavit          crédito Infonavit en pesos; --salario calcula la aportación patronal que abona al crédito\n" +
+					"  vsm                crédito Infonavit o Fovissste en veces salario mínimo: saldo y mensualidad suben cada año\n" +
+					"  cofinanciamiento   parte Infonavit (--monto-infonavit) y el resto con el banco (--tasa)\n" +
+					"Los seguros se capturan como en la oferta del banco: vida sobre el saldo cada mes y daños sobre el valor cada año.\n" +
+					"Ejemplo: finmex hipoteca simular --valor 2500000 --enganche 250000 --anios 20 --tasa 0.105 --seguro-vida 0.0003",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "modo", Value: ModoHipotecaBanco, Usage: "Tipo de crédito: " + strings.Join(modosHipoteca, ", ")},
+					conLimites(&cli.Float64Flag{Name: "valor", Required: true, Usage: "Valor de la vivienda"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "enganche", Usage: "Enganche en pesos"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "subcuenta", Usage: "Saldo de la subcuenta de vivienda que se usa como enganche"}, limitesMonto),
+					&cli.IntFlag{Name: "anios", Aliases: []string{"años"}, Value: 20, Usage: "Plazo en años"},
+					conLimites(&cli.Float64Flag{Name: "tasa", Usage: "Tasa anual del crédito en decimal; en infonavit y vsm, por defecto la máxima del Infonavit"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "tasa-infonavit", Value: TASA_MAXIMA_INFONAVIT, Usage: "Tasa de la parte Infonavit en cofinanciamiento"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "monto-infonavit", Usage: "Monto que presta el Infonavit en cofinanciamiento"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "incremento-vsm", Value: 0.05, Usage: "Aumento anual estimado del salario mínimo en modo vsm"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "salario", Usage: "Salario mensual, para la aportación patronal del 5% en los modos Infonavit"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "seguro-vida", Usage: "Seguro de vida y desempleo: fracción mensual del saldo (ej. 0.0003)"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "seguro-danos", Aliases: []string{"seguro-daños"}, Usage: "Seguro de daños: fracción anual del valor de la vivienda (ej. 0.0015)"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "comision-apertura", Usage: "Comisión por apertura: fracción del monto financiado"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "gastos-iniciales", Usage: "Avalúo, estudio de crédito y otros cargos del banco al disponer"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "comision-mensual", Usage: "Comisión mensual de administración"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "abono-mensual", Usage: "Pago anticipado a capital cada mes"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "abono-anual", Usage: "Pago anticipado a capital cada año, por ejemplo el aguinaldo"}, limitesMonto),
+					&cli.BoolFlag{Name: "reducir-pago", Usage: "Los pagos anticipados bajan la mensualidad en lugar del plazo"},
+					&cli.BoolFlag{Name: "mensual", Usage: "Mostrar la tabla de amortización mes por mes en lugar de por año"},
+				},
+				Action: func(c *cli.Context) error {
+					cotizacion, err := cotizacionHipoteca(c)
+					if err != nil {
+						return err
+					}
+					a, err := AnalizarHipoteca(cotizacion)
+					if err != nil {
+						return err
+					}
+
+					if salidaEstructurada() {
+						// En CSV la tabla de amortización es lo que se puede poner en renglones
+						if formatoDatos == FormatoCSV {
+							if a.ConAbonos != nil {
+								return emitirDatos(a.ConAbonos.Amortizacion)
+							}
+							return emitirDatos(a.Simulacion.Amortizacion)
+						}
+						return emitirDatos(a)
+					}
+					imprimirHipoteca(a, cotizacion, c.Bool("mensual"))
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// cotizacionHipoteca lee y valida las banderas de hipoteca simular
+func cotizacionHipoteca(c *cli.Context) (CotizacionHipoteca, error) {
+	h := CotizacionHipoteca{
+		Modo:             strings.ToLower(c.String("modo")),
+		ValorVivienda:    c.Float64("valor"),
+		Enganche:         c.Float64("enganche"),
+		Subcuenta:        c.Float64("subcuenta"),
+		Años:             c.Int("anios"),
+		Tasa:             c.Float64("tasa"),
+		TasaInfonavit:    c.Float64("tasa-infonavit"),
+		MontoInfonavit:   c.Float64("monto-infonavit"),
+		IncrementoVSM:    c.Float64("incremento-vsm"),
+		Salario:          c.Float64("salario"),
+		SeguroVida:       c.Float64("seguro-vida"),
+		SeguroDaños:      c.Float64("seguro-danos"),
+		ComisionApertura: c.Float64("comision-apertura"),
+		GastosIniciales:  c.Float64("gastos-iniciales"),
+		ComisionMensual:  c.Float64("comision-mensual"),
+		AbonoMensual:     c.Float64("abono-mensual"),
+		AbonoAnual:       c.Float64("abono-anual"),
+		ReducirPago:      c.Bool("reducir-pago"),
+	}
+	if !contieneClave(modosHipoteca, h.Modo) {
+		return h, errDatosInvalidos(
+			fmt.Sprintf("Modo inválido '%s' (usa %s)", h.Modo, strings.Join(modosHipoteca, ", ")),
+			fmt.Sprintf("Invalid mode '%s' (use %s)", h.Modo, strings.Join(modosHipoteca, ", ")))
+	}
+	if h.Años < 1 || h.Años > 30 {
+		return h, errDatosInvalidos("El plazo debe ser de 1 a 30 años", "The term must be 1 to 30 years")
+	}
+	if !c.IsSet("tasa") {
+		switch h.Modo {
+		case ModoHipotecaInfonavit, ModoHipotecaVSM:
+			h.Tasa = TASA_MAXIMA_INFONAVIT
+		default:
+			return h, errDatosInvalidos("Indica la tasa anual del banco con --tasa", "Pass the bank's annual rate with --tasa")
+		}
+	}
+
+	if h.ValorVivienda <= 0 {
+		return h, errDatosInvalidos("El valor de la vivienda debe ser mayor a cero", "The home value must be greater than zero")
+	}
+	return h, nil
+}
+
+// imprimirHipoteca muestra la simulación, la tabla de amortización y el efecto de los abonos
+func imprimirHipoteca(a AnalisisHipoteca, cotizacion CotizacionHipoteca, mensual bool) {
+	s := a.Simulacion
+	fmt.Printf("\n=== Simulación de Hipoteca (%s) ===\n", a.Modo)
+	fmt.Printf("Valor de la vivienda: $%.2f\n", a.ValorVivienda)
+	fmt.Printf("Enganche: $%.2f (%.1f%%)", a.Enganche, a.Enganche/a.ValorVivienda*100)
+	if cotizacion.Subcuenta > 0 {
+		fmt.Printf(", incluye $%.2f de la subcuenta de vivienda", cotizacion.Subcuenta)
+	}
+	fmt.Println()
+	fmt.Printf("Monto financiado: $%.2f a %d años\n", s.Financiado, cotizacion.Años)
+	for _, t := range a.Tramos {
+		fmt.Printf("  %s: $%.2f al %.2f%%", t.Nombre, t.Monto, t.TasaAnual*100)
+		if t.Actualizacion > 0 {
+			fmt.Printf(", saldo y mensualidad suben %.2f%% cada año", t.Actualizacion*100)
+		}
+		fmt.Println()
+	}
+	if s.CostoInicial > 0 {
+		fmt.Printf("Comisión por apertura y gastos iniciales: $%.2f\n", s.CostoInicial)
+	}
+	fmt.Printf("Primera mensualidad: $%.2f (incluye seguros y comisiones)\n", s.Mensualidad)
+	if a.AportacionPatronal > 0 {
+		fmt.Printf("Aportación patronal al crédito: $%.2f; de tu bolsillo pagas $%.2f\n",
+			a.AportacionPatronal, s.Mensualidad-a.AportacionPatronal)
+	}
+	fmt.Printf("CAT efectivo: %.2f%% sin IVA\n", a.CAT*100)
+	fmt.Printf("Total pagado: $%.2f (intereses $%.2f, seguros $%.2f, comisiones $%.2f)\n",
+		s.TotalPagado, s.Intereses, s.Seguros, s.Comisiones+s.CostoInicial)
+
+	if a.Modo == ModoHipotecaBanco && a.Enganche < a.ValorVivienda*ENGANCHE_MINIMO_BANCO {
+		fmt.Printf("AVISO: Los bancos suelen pedir al menos %.0f%% de enganche\n", ENGANCHE_MINIMO_BANCO*100)
+	}
+	if a.Modo == ModoHipotecaVSM {
+		fmt.Printf("AVISO: En VSM la deuda crece con el salario mínimo; la última mensualidad sería de $%.2f\n",
+			s.Amortizacion[len(s.Amortizacion)-1].Pago)
+	}
+
+	tabla := s
+	if a.ConAbonos != nil {
+		tabla = *a.ConAbonos
+	}
+	imprimirAmortizacionHipoteca(tabla.Amortizacion, mensual)
+
+	if c := a.ConAbonos; c != nil {
+		fmt.Println("\n=== Efecto de los pagos anticipados ===")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+		fmt.Fprintln(w, "Concepto\tSin Abonos\tCon Abonos")
+		fmt.Fprintln(w, "--------\t----------\t----------")
+		fmt.Fprintf(w, "Plazo\t%d meses\t%d meses\n", s.Plazo, c.Plazo)
+		fmt.Fprintf(w, "Intereses\t$%.2f\t$%.2f\n", s.Intereses, c.Intereses)
+		fmt.Fprintf(w, "Seguros y comisiones\t$%.2f\t$%.2f\n", s.Seguros+s.Comisiones, c.Seguros+c.Comisiones)
+		fmt.Fprintf(w, "Abonos a capital\t$0.00\t$%.2f\n", c.Abonos)
+		fmt.Fprintf(w, "Total pagado\t$%.2f\t$%.2f\n", s.TotalPagado, c.TotalPagado)
+		w.Flush()
+
+		ahorro := s.TotalPagado - c.TotalPagado
+		if cotizacion.ReducirPago {
+			ultima := c.Amortizacion[len(c.Amortizacion)-1]
+			fmt.Printf("RESULTADO: Los abonos te ahorran $%.2f y la mensualidad baja hasta $%.2f al final del plazo\n",
+				ahorro, ultima.Pago-ultima.Abono)
+		} else {
+			fmt.Printf("RESULTADO: Los abonos te ahorran $%.2f y liquidas %d meses antes\n", ahorro, s.Plazo-c.Plazo)
+		}
+		return
+	}
+	fmt.Printf("RESULTADO: Pagarás $%.2f por un crédito de $%.2f, %.2f veces lo prestado\n",
+		s.TotalPagado, s.Financiado, s.TotalPagado/s.Financiado)
+}
+
+// imprimirAmortizacionHipoteca muestra la tabla por año o, con mensual, mes por mes
+func imprimirAmortizacionHipoteca(meses []MesHipoteca, mensual bool) {
+	fmt.Println("\n=== Tabla de Amortización ===")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	if mensual {
+		fmt.Fprintln(w, "Mes\tSaldo Inicial\tInterés\tCapital\tAbono\tSeguros\tComisiones\tPago\tSaldo Final")
+		fmt.Fprintln(w, "---\t-------------\t-------\t-------\t-----\t-------\t----------\t----\t-----------")
+		for _, m := range meses {
+			fmt.Fprintf(w, "%d\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+				m.Mes, m.SaldoInicial, m.Interes, m.Capital, m.Abono, m.Seguros, m.Comisiones, m.Pago, m.SaldoFinal)
+		}
+		w.Flush()
+		return
+	}
+
+	fmt.Fprintln(w, "Año\tPagado\tIntereses\tCapital\tAbonos\tSeguros y Comisiones\tSaldo Final")
+	fmt.Fprintln(w, "---\t------\t---------\t-------\t------\t--------------------\t-----------")
+	for _, a := range ResumenAnualHipoteca(meses) {
+		fmt.Fprintf(w, "%d\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+			a.Año, a.Pagado, a.Intereses, a.Capital, a.Abonos, a.Accesorios, a.SaldoFinal)
+	}
+	w.Flush()
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoInformal agrupa las operaciones con préstamos entre personas
+func comandoInformal() *cli.Command {
+	return &cli.Command{
+		Name:  "informal",
+		Usage: "Préstamos informales entre personas, por cobrar o por pagar",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar un préstamo informal",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var p PrestamoInformal
+
+					if p.Persona, err = leerTextoRequerido("Persona: "); err != nil {
+						return err
+					}
+					if p.Concepto, err = leerTexto("Concepto (opcional): "); err != nil {
+						return err
+					}
+					if p.PorCobrar, err = leerSiNo("¿Tú prestaste el dinero? (s = por cobrar, n = por pagar): "); err != nil {
+						return err
+					}
+					if p.Monto, err = leerNumero("Monto prestado: ", limitesMonto); err != nil {
+						return err
+					}
+					if p.Interes, err = leerNumero("Interés total acordado en pesos (0 si no hay): ", limitesMonto); err != nil {
+						return err
+					}
+					if p.Pagos, err = leerEntero("Número de pagos acordados: ", 1, 520); err != nil {
+						return err
+					}
+
+					texto, err := leerTexto("Frecuencia de pago (mensual, quincenal, catorcenal, semanal) [mensual]: ")
+					if err != nil {
+						return err
+					}
+					if texto == "" {
+						texto = string(FrecuenciaMensual)
+					}
+					if p.Frecuencia, err = calc.ParsearFrecuencia(texto); err != nil {
+						return err
+					}
+
+					if p.PrimerPago, err = leerTextoRequerido("Fecha del primer pago (AAAA-MM-DD): "); err != nil {
+						return err
+					}
+					if _, err := p.FechasPago(); err != nil {
+						return err
+					}
+
+					tarjetas.Informales = append(tarjetas.Informales, p)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar préstamo: %w", err)
+					}
+
+					fmt.Printf("Préstamo con %s registrado: %d pagos de $%.2f\n", p.Persona, p.Pagos, p.Pago())
+					return nil
+				},
+			},
+			{
+				Name:      "abonar",
+				Usage:     "Registrar un abono a un préstamo informal",
+				ArgsUsage: "<persona> <monto>",
+				Action: func(c *cli.Context) error {
+					persona, monto, err := argumentosNombreMonto(c)
+					if err != nil {
+						return err
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					for i, p := range tarjetas.Informales {
+						if normalizarClave(p.Persona) != normalizarClave(persona) || p.Pendiente() <= 0 {
+							continue
+						}
+						tarjetas.Informales[i].Abonado += monto
+						if err := GuardarTarjetas(tarjetas); err != nil {
+							return fmt.Errorf("Error al guardar préstamo: %w", err)
+						}
+						fmt.Printf("Abono de $%.2f registrado. Pendiente: $%.2f\n", monto, tarjetas.Informales[i].Pendiente())
+						return nil
+					}
+
+					return fmt.Errorf("No hay un préstamo pendiente con '%s'", persona)
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar préstamos informales",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Informales) == 0 && !salidaEstructurada() {
+						fmt.Println("No hay préstamos informales registrados")
+						return nil
+					}
+
+					hoy := time.Now()
+					filas := []FilaInformal{}
+					for _, p := range tarjetas.Informales {
+						pendientes, err := p.PagosPendientes(hoy)
+						if err != nil {
+							return err
+						}
+						fila := FilaInformal{PrestamoInformal: p, Sentido: p.Sentido(), Total: p.Total(), Pendiente: p.Pendiente()}
+						for _, pendiente := range pendientes {
+							if pendiente.Vencido {
+								fila.Vencidos++
+							} else if fila.ProximoPago == "" {
+								fila.ProximoPago = pendiente.Fecha.Format("2006-01-02")
+							}
+						}
+						filas = append(filas, fila)
+					}
+
+					if salidaEstructurada() {
+						return emitirDatos(filas)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Persona\tTipo\tMonto\tPagos\tAbonado\tPendiente\tPróximo Pago\tVencidos")
+					fmt.Fprintln(w, "-------\t----\t-----\t-----\t-------\t---------\t------------\t--------")
+
+					for _, f := range filas {
+						proximo := f.ProximoPago
+						if proximo == "" {
+							proximo = "-"
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%d %s\t$%.2f\t$%.2f\t%s\t%d\n",
+							f.Persona, f.Sentido, f.Total, f.Pagos, f.frecuencia(),
+							f.Abonado, f.Pendiente, proximo, f.Vencidos)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:  "recordatorios",
+				Usage: "Mostrar pagos vencidos y próximos de préstamos informales",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "dias", Value: 7, Usage: "Días hacia adelante a revisar"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					hoy := time.Now().Truncate(24 * time.Hour)
+					limite := hoy.AddDate(0, 0, c.Int("dias"))
+					avisos := 0
+
+					for _, p := range tarjetas.Informales {
+						pendientes, err := p.PagosPendientes(hoy)
+						if err != nil {
+							return err
+						}
+						for _, pendiente := range pendientes {
+							if pendiente.Fecha.After(limite) {
+								break
+							}
+							accion := "Cobrar a"
+							if !p.PorCobrar {
+								accion = "Pagar a"
+							}
+							estado := "vence el " + pendiente.Fecha.Format("2006-01-02")
+							if pendiente.Vencido {
+								estado = "VENCIDO desde el " + pendiente.Fecha.Format("2006-01-02")
+							}
+							fmt.Printf("%s %s $%.2f (pago %d de %d, %s)\n", accion, p.Persona, pendiente.Monto, pendiente.Numero, p.Pagos, estado)
+							avisos++
+						}
+					}
+
+					if avisos == 0 {
+						fmt.Printf("No hay pagos de préstamos informales en los próximos %d días\n", c.Int("dias"))
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
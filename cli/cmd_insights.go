@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoInsights muestra observaciones accionables sobre los productos registrados
+func comandoInsights() *cli.Command {
+	return &cli.Command{
+		Name:  "insights",
+		Usage: "Mostrar hallazgos sobre tus productos ordenados por impacto",
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			catalogo, err := CargarCatalogo()
+			if err != nil {
+				return err
+			}
+
+			gasto, err := GastoAnualPorTarjeta(time.Now())
+			if err != nil {
+				return fmt.Errorf("Error al leer movimientos: %w", err)
+			}
+
+			insights := GenerarInsights(tarjetas, catalogo, gasto)
+			if len(insights) == 0 {
+				fmt.Println("No hay hallazgos: tus productos no muestran costos evitables")
+				return nil
+			}
+
+			for i, in := range insights {
+				fmt.Printf("%d. %s\n", i+1, in.Mensaje)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoListar muestra las tarjetas de débito y de crédito en una sola tabla
+func comandoListar() *cli.Command {
+	return &cli.Command{
+		Name:  "listar",
+		Usage: "Listar tarjetas de débito y crédito juntas, con filtros y orden",
+		Description: "La tasa es el rendimiento en las cuentas de débito y el interés en las tarjetas de crédito.\n" +
+			"Ejemplo: finmex listar --tipo todo --ordenar-por tasa --banco BBVA",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{Name: "tipo", Value: TipoListadoTodo, Usage: "Productos a mostrar: todo, debito o credito"},
+			&cli.StringFlag{Name: "banco", Usage: "Solo productos de este banco"},
+			conLimites(&cli.Float64Flag{Name: "tasa-min", Usage: "Tasa mínima en decimal"}, limitesTasa),
+			conLimites(&cli.Float64Flag{Name: "tasa-max", Usage: "Tasa máxima en decimal"}, limitesTasa),
+			conLimites(&cli.Float64Flag{Name: "anualidad-max", Usage: "Comisión anual máxima"}, limitesMonto),
+			&cli.StringFlag{Name: "ordenar-por", Usage: "Ordenar por " + strings.Join(ordenesListado, ", ")},
+			&cli.BoolFlag{Name: "desc", Usage: "Orden descendente"},
+		}, flagsFiltroTarjetas()...),
+		Action: func(c *cli.Context) error {
+			criterios := CriteriosListado{
+				Tipo:        strings.ToLower(c.String("tipo")),
+				Banco:       c.String("banco"),
+				TasaMinima:  c.Float64("tasa-min"),
+				TasaMaxima:  c.Float64("tasa-max"),
+				Filtro:      filtroTarjetas(c),
+				OrdenarPor:  c.String("ordenar-por"),
+				Descendente: c.Bool("desc"),
+			}
+			if c.IsSet("anualidad-max") {
+				maxima := c.Float64("anualidad-max")
+				criterios.AnualidadMax = &maxima
+			}
+			if err := ValidarCriteriosListado(criterios); err != nil {
+				return err
+			}
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			if err := criterios.Filtro.Verificar(append(nombresDebito(tarjetas), nombresCredito(tarjetas)...)); err != nil {
+				return err
+			}
+			productos := ListarProductos(tarjetas, criterios)
+
+			if salidaEstructurada() {
+				return emitirDatos(productos)
+			}
+			if len(productos) == 0 {
+				fmt.Println("No hay tarjetas que cumplan los filtros")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Tipo\tNombre\tBanco\tTasa\tCAT\tAnualidad\tSaldo/Deuda\tLímite\tEtiquetas")
+			fmt.Fprintln(w, "----\t------\t-----\t----\t---\t---------\t-----------\t------\t---------")
+			for _, p := range productos {
+				tipo, cat, limite := "Débito", "-", "-"
+				if p.Tipo == TipoListadoCredito {
+					tipo = "Crédito"
+					cat = fmt.Sprintf("%.2f%%", p.CAT*100)
+					limite = fmt.Sprintf("$%.2f", p.Limite)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%.2f%%\t%s\t$%.2f\t$%.2f\t%s\t%s\n",
+					tipo, p.Nombre, p.Banco, p.Tasa*100, cat, p.Anualidad, p.Saldo, limite, strings.Join(p.Tags, ", "))
+			}
+			return w.Flush()
+		},
+	}
+}
@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoMetas agrupa las operaciones con metas de ahorro
+func comandoMetas() *cli.Command {
+	return &cli.Command{
+		Name:    "metas",
+		Aliases: []string{"meta"},
+		Usage:   "Metas de ahorro y su avance",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "definir",
+				Usage: "Definir una meta de ahorro",
+				Description: "Con --producto el ahorro se guarda en una cuenta de débito, de SOFIPO, inversión en\n" +
+					"cetesdirecto o pagaré registrado: la tasa es su rendimiento real, después de ISR y\n" +
+					"menos la inflación, y los montos quedan en pesos de hoy. --emergencia calcula el\n" +
+					"objetivo como esos meses de gasto del presupuesto.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "nombre", Usage: "Nombre de la meta"},
+					conLimites(&cli.Float64Flag{Name: "objetivo", Usage: "Monto objetivo"}, limitesMonto),
+					&cli.StringFlag{Name: "fecha", Usage: "Fecha objetivo (AAAA-MM-DD)"},
+					&cli.StringFlag{Name: "producto", Usage: "Producto registrado donde guardarás el ahorro"},
+					conLimites(&cli.Float64Flag{Name: "tasa", Usage: "Tasa anual neta donde guardarás el ahorro, si no está registrado"}, limitesTasa),
+					&cli.IntFlag{Name: "emergencia", Usage: "Fondo de emergencia: meses de gasto del presupuesto a cubrir"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					m := Meta{FechaInicio: time.Now().Format("2006-01-02")}
+					captura := nuevaCaptura(c)
+
+					if c.IsSet("emergencia") && !c.IsSet("nombre") {
+						m.Nombre = "Fondo de emergencia"
+					} else if m.Nombre, err = captura.Texto("nombre", "Nombre de la meta: "); err != nil {
+						return err
+					}
+					if _, existe := buscarMeta(tarjetas.Metas, m.Nombre); existe {
+						return fmt.Errorf("Ya existe una meta llamada '%s'", m.Nombre)
+					}
+					if c.IsSet("emergencia") {
+						if m.Objetivo, err = objetivoEmergencia(tarjetas, c.Int("emergencia")); err != nil {
+							return err
+						}
+					} else if m.Objetivo, err = captura.Numero("objetivo", "Monto objetivo: ", limitesMonto); err != nil {
+						return err
+					}
+					if m.FechaObjetivo, err = captura.Texto("fecha", "Fecha objetivo (AAAA-MM-DD): "); err != nil {
+						return err
+					}
+
+					if c.IsSet("producto") {
+						m.Producto = c.String("producto")
+					} else if captura.interactiva {
+						if m.Producto, err = leerTexto("Producto registrado donde guardarás el ahorro (vacío para capturar la tasa): "); err != nil {
+							return err
+						}
+					}
+					if m.Producto != "" {
+						if !m.ActualizarTasa(tarjetas) {
+							return fmt.Errorf("No hay una cuenta de débito, de SOFIPO, inversión en cetesdirecto ni pagaré llamado '%s'", m.Producto)
+						}
+					} else if m.TasaRendimiento, err = captura.Numero("tasa", "Tasa anual neta donde guardarás el ahorro (decimal): ", limitesTasa); err != nil {
+						return err
+					}
+
+					estado, err := m.Estado(time.Now())
+					if err != nil {
+						return err
+					}
+
+					tarjetas.Metas = append(tarjetas.Metas, m)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar meta: %w", err)
+					}
+
+					fmt.Printf("Meta '%s' definida: aporta $%.2f al mes durante %d meses\n", m.Nombre, estado.AportacionPlaneada, estado.MesesRestantes)
+					if m.Producto != "" {
+						fmt.Printf("Rendimiento real de %s: %.2f%% anual; los montos son en pesos de hoy\n", m.Producto, m.TasaRendimiento*100)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "aportar",
+				Usage:     "Registrar un aporte real a una meta",
+				ArgsUsage: "<meta> <monto>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "fecha", Usage: "Fecha del aporte (AAAA-MM-DD, por omisión hoy)"},
+				},
+				Action: func(c *cli.Context) error {
+					nombre, monto, err := argumentosNombreMonto(c)
+					if err != nil {
+						return err
+					}
+
+					fecha := c.String("fecha")
+					if fecha == "" {
+						fecha = time.Now().Format("2006-01-02")
+					}
+					if _, err := time.Parse("2006-01-02", fecha); err != nil {
+						return fmt.Errorf("Fecha inválida: %v", err)
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					i, existe := buscarMeta(tarjetas.Metas, nombre)
+					if !existe {
+						return fmt.Errorf("No existe la meta '%s'", nombre)
+					}
+					tarjetas.Metas[i].Aportes = append(tarjetas.Metas[i].Aportes, AporteMeta{Fecha: fecha, Monto: monto})
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar meta: %w", err)
+					}
+
+					fmt.Printf("Aporte de $%.2f registrado en '%s'\n", monto, tarjetas.Metas[i].Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "estado",
+				Usage: "Comparar el avance real de cada meta contra su plan",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Metas) == 0 {
+						fmt.Println("No hay metas definidas")
+						return nil
+					}
+
+					hoy := time.Now()
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Meta\tObjetivo\tFecha\tPlaneado\tReal\tAvance\tAporte Plan\tAporte Necesario\tEstado")
+					fmt.Fprintln(w, "----\t--------\t-----\t--------\t----\t------\t-----------\t----------------\t------")
+
+					for _, m := range tarjetas.Metas {
+						m.ActualizarTasa(tarjetas)
+						e, err := m.Estado(hoy)
+						if err != nil {
+							return err
+						}
+						estado := "En tiempo"
+						if e.SaldoReal >= m.Objetivo {
+							estado = "Cumplida"
+						} else if e.Atrasada() {
+							estado = "Atrasada"
+						}
+						fmt.Fprintf(w, "%s\t$%.2f\t%s\t$%.2f\t$%.2f\t%.1f%%\t$%.2f\t$%.2f\t%s\n",
+							m.Nombre, m.Objetivo, m.FechaObjetivo, e.SaldoPlaneado, e.SaldoReal,
+							e.Avance*100, e.AportacionPlaneada, e.AportacionNecesaria, estado)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:      "proyeccion",
+				Usage:     "Ver la curva de avance proyectada de una meta y cuándo se cumpliría",
+				ArgsUsage: "<meta>",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "aporte", Usage: "Aporte mensual a proyectar; por defecto el necesario para llegar a tiempo"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return fmt.Errorf("Uso: %s %s", c.Command.HelpName, c.Command.ArgsUsage)
+					}
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					i, existe := buscarMeta(tarjetas.Metas, argumentoNombre(c.Args().Slice()))
+					if !existe {
+						return fmt.Errorf("No existe la meta '%s'", argumentoNombre(c.Args().Slice()))
+					}
+
+					m := tarjetas.Metas[i]
+					conProducto := m.ActualizarTasa(tarjetas)
+					hoy := time.Now()
+					e, err := m.Estado(hoy)
+					if err != nil {
+						return err
+					}
+					aporte := e.AportacionNecesaria
+					if c.IsSet("aporte") {
+						aporte = c.Float64("aporte")
+					}
+					puntos := m.Proyeccion(e, aporte, hoy)
+					meses := calc.MesesParaObjetivo(e.SaldoReal, aporte, m.TasaRendimiento, m.Objetivo)
+					if salidaEstructurada() {
+						return emitirDatos(puntos)
+					}
+
+					fmt.Printf("=== Proyección de la meta: %s ===\n", m.Nombre)
+					fmt.Printf("Objetivo: $%.2f para el %s\n", m.Objetivo, m.FechaObjetivo)
+					if conProducto {
+						fmt.Printf("Producto: %s (rendimiento real %.2f%% anual; montos en pesos de hoy)\n", m.Producto, m.TasaRendimiento*100)
+					} else {
+						if m.Producto != "" {
+							fmt.Printf("AVISO: '%s' ya no está registrado; se usa la última tasa conocida\n", m.Producto)
+						}
+						fmt.Printf("Tasa anual neta: %.2f%%\n", m.TasaRendimiento*100)
+					}
+					fmt.Printf("Saldo actual: $%.2f (%.1f%%)\n", e.SaldoReal, e.Avance*100)
+					fmt.Printf("Aporte mensual proyectado: $%.2f\n\n", aporte)
+
+					if len(puntos) > 0 {
+						w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+						fmt.Fprintln(w, "Mes\tSaldo\tAvance\t")
+						fmt.Fprintln(w, "---\t-----\t------\t")
+						for _, p := range puntos {
+							fmt.Fprintf(w, "%s\t$%.2f\t%.1f%%\t%s\n", p.Mes, p.Saldo, p.Avance*100, BarraProgreso(p.Avance, 30))
+						}
+						w.Flush()
+						fmt.Println()
+					}
+
+					limite, _ := time.Parse("2006-01-02", m.FechaObjetivo)
+					switch {
+					case meses == 0:
+						fmt.Println("RESULTADO: La meta ya está cumplida")
+					case meses < 0:
+						fmt.Printf("RESULTADO: Con $%.2f al mes la meta no se cumple; necesitas aportar $%.2f al mes\n", aporte, e.AportacionNecesaria)
+					default:
+						llegada := calc.SumarMeses(hoy, meses)
+						if llegada.After(limite) {
+							fmt.Printf("RESULTADO: Llegarías en %d meses (%s), después de la fecha objetivo; aporta $%.2f al mes para llegar a tiempo\n",
+								meses, llegada.Format("2006-01"), e.AportacionNecesaria)
+						} else {
+							fmt.Printf("RESULTADO: Llegarías en %d meses (%s)\n", meses, llegada.Format("2006-01"))
+						}
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// buscarMeta regresa la posición de la meta con el nombre dado
+func buscarMeta(metas []Meta, nombre string) (int, bool) {
+	for i, m := range metas {
+		if normalizarClave(m.Nombre) == normalizarClave(nombre) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// objetivoEmergencia regresa el monto de un fondo de emergencia que cubre los meses de
+// gasto del presupuesto
+func objetivoEmergencia(tarjetas Tarjetas, meses int) (float64, error) {
+	if meses < 1 || meses > 24 {
+		return 0, errDatosInvalidos("--emergencia debe estar entre 1 y 24 meses", "--emergencia must be between 1 and 24 months")
+	}
+	if tarjetas.Presupuesto == nil || tarjetas.Presupuesto.LimiteMensual() <= 0 {
+		return 0, fmt.Errorf("Define los límites de gasto de tu presupuesto o indica el monto con --objetivo")
+	}
+	return tarjetas.Presupuesto.LimiteMensual() * float64(meses), nil
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoMicrocredito agrupa las operaciones con préstamos de apps y fintech
+func comandoMicrocredito() *cli.Command {
+	return &cli.Command{
+		Name:  "microcredito",
+		Usage: "Operaciones con microcréditos y préstamos de apps",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Agregar un microcrédito",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var m Microcredito
+
+					if m.Nombre, err = leerTextoRequerido("Nombre del préstamo: "); err != nil {
+						return err
+					}
+					if m.Proveedor, err = leerTextoRequerido("App o proveedor (Kueski, Baubap, etc.): "); err != nil {
+						return err
+					}
+					if m.Monto, err = leerNumero("Monto que recibes: ", limitesMonto); err != nil {
+						return err
+					}
+					if m.Comision, err = leerNumero("Comisiones fijas en pesos (apertura, servicio): ", limitesMonto); err != nil {
+						return err
+					}
+					if m.Interes, err = leerNumero("Interés total en pesos (con IVA): ", limitesMonto); err != nil {
+						return err
+					}
+					if m.PlazoDias, err = leerEntero("Plazo total en días: ", 1, 3650); err != nil {
+						return err
+					}
+					if m.Pagos, err = leerEntero("Número de pagos iguales (1 si es pago único): ", 1, 520); err != nil {
+						return err
+					}
+
+					tarjetas.Microcreditos = append(tarjetas.Microcreditos, m)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar microcrédito: %w", err)
+					}
+
+					fmt.Printf("Microcrédito '%s' agregado exitosamente\n", m.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar microcréditos registrados con su tasa anual equivalente",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if salidaEstructurada() {
+						filas := []FilaMicrocredito{}
+						for _, m := range tarjetas.Microcreditos {
+							costo, err := AnalizarMicrocredito(m)
+							if err != nil {
+								return err
+							}
+							filas = append(filas, FilaMicrocredito{m, costo})
+						}
+						return emitirDatos(filas)
+					}
+
+					if len(tarjetas.Microcreditos) == 0 {
+						fmt.Println("No hay microcréditos registrados")
+						return nil
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tProveedor\tMonto\tCosto\tPlazo\tPagos\tTasa Anual\tTasa Efectiva")
+					fmt.Fprintln(w, "------\t---------\t-----\t-----\t-----\t-----\t----------\t-------------")
+
+					for _, m := range tarjetas.Microcreditos {
+						costo, err := AnalizarMicrocredito(m)
+						if err != nil {
+							return err
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t%d días\t%d\t%.1f%%\t%.1f%%\n",
+							m.Nombre, m.Proveedor, m.Monto, costo.CostoTotal, m.PlazoDias, m.Pagos,
+							costo.TasaAnualSimple*100, costo.TasaAnualEfectiva*100)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:  "analizar",
+				Usage: "Calcular la tasa anual real de un microcrédito y compararla con tus tarjetas",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Microcreditos) == 0 {
+						return fmt.Errorf("No hay microcréditos registrados")
+					}
+
+					var nombres []string
+					for _, m := range tarjetas.Microcreditos {
+						nombres = append(nombres, fmt.Sprintf("%s (%s)", m.Nombre, m.Proveedor))
+					}
+					seleccion, err := elegirOpcion("Microcréditos disponibles:", nombres)
+					if err != nil {
+						return err
+					}
+
+					m := tarjetas.Microcreditos[seleccion]
+					costo, err := AnalizarMicrocredito(m)
+					if err != nil {
+						return err
+					}
+
+					if salidaEstructurada() {
+						analisis := AnalisisMicrocredito{FilaMicrocredito: FilaMicrocredito{m, costo}, Tarjetas: []CostoMicrocreditoTarjeta{}}
+						for _, t := range tarjetas.Credito {
+							costoTarjeta := CostoEnTarjeta(m, t)
+							analisis.Tarjetas = append(analisis.Tarjetas, CostoMicrocreditoTarjeta{t.Nombre, t.Banco, t.TasaInteres, costoTarjeta, costo.CostoTotal - costoTarjeta})
+						}
+						return emitirDatos(analisis)
+					}
+
+					fmt.Println("\n=== Análisis de Microcrédito ===")
+					fmt.Printf("Préstamo: %s (%s)\n", m.Nombre, m.Proveedor)
+					fmt.Printf("Monto recibido: $%.2f\n", m.Monto)
+					fmt.Printf("Comisiones: $%.2f | Intereses: $%.2f\n", m.Comision, m.Interes)
+					fmt.Printf("Total a pagar: $%.2f en %d pago(s) de $%.2f\n", costo.TotalPagado, m.Pagos, costo.PagoPorPeriodo)
+					fmt.Printf("Plazo: %d días\n", m.PlazoDias)
+					fmt.Printf("Tasa por periodo de pago: %.2f%%\n", costo.TasaPeriodo*100)
+					fmt.Printf("Tasa anual equivalente: %.1f%%\n", costo.TasaAnualSimple*100)
+					fmt.Printf("Tasa anual efectiva (renovándolo todo el año): %.1f%%\n", costo.TasaAnualEfectiva*100)
+
+					if len(tarjetas.Credito) == 0 {
+						return nil
+					}
+
+					fmt.Println("\n=== Mismo monto y plazo con tus tarjetas de crédito ===")
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Tarjeta\tBanco\tTasa Anual\tCosto Estimado\tAhorro\tVeces más caro")
+					fmt.Fprintln(w, "-------\t-----\t----------\t--------------\t------\t--------------")
+
+					for _, t := range tarjetas.Credito {
+						costoTarjeta := CostoEnTarjeta(m, t)
+						veces := "-"
+						if costoTarjeta > 0 {
+							veces = fmt.Sprintf("%.1fx", costo.CostoTotal/costoTarjeta)
+						}
+						fmt.Fprintf(w, "%s\t%s\t%.2f%%\t$%.2f\t$%.2f\t%s\n",
+							t.Nombre, t.Banco, t.TasaInteres*100, costoTarjeta, costo.CostoTotal-costoTarjeta, veces)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+		},
+	}
+}
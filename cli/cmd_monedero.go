@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoMonedero agrupa las operaciones con monederos electrónicos y saldos de tiendas
+func comandoMonedero() *cli.Command {
+	return &cli.Command{
+		Name:  "monedero",
+		Usage: "Operaciones con monederos electrónicos, tarjetas de regalo y puntos",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Agregar un monedero",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var m Monedero
+
+					if m.Nombre, err = leerTextoRequerido("Nombre del monedero: "); err != nil {
+						return err
+					}
+					if m.Proveedor, err = leerTextoRequerido("Proveedor (Mercado Pago, Liverpool, etc.): "); err != nil {
+						return err
+					}
+
+					tipo, err := leerTexto("Tipo (saldo, regalo, puntos) [saldo]: ")
+					if err != nil {
+						return err
+					}
+					m.Tipo = strings.ToLower(tipo)
+					if m.Tipo == "" {
+						m.Tipo = MonederoSaldo
+					}
+					if err := ValidarTipoMonedero(m.Tipo); err != nil {
+						return err
+					}
+
+					if m.Tipo == MonederoPuntos {
+						if m.Saldo, err = leerNumero("Puntos acumulados: ", limitesMonto); err != nil {
+							return err
+						}
+						if m.ValorPunto, err = leerNumero("Valor de cada punto en pesos: ", limitesMonto); err != nil {
+							return err
+						}
+					} else if m.Saldo, err = leerNumero("Saldo en pesos: ", limitesMonto); err != nil {
+						return err
+					}
+
+					if m.TasaRendimiento, err = leerNumero("Tasa de rendimiento anual (decimal, 0 si no rinde): ", limitesTasa); err != nil {
+						return err
+					}
+
+					if m.Vencimiento, err = leerTexto("Fecha de vencimiento (AAAA-MM-DD, vacío si no vence): "); err != nil {
+						return err
+					}
+					if m.Vencimiento != "" {
+						if _, err := time.Parse("2006-01-02", m.Vencimiento); err != nil {
+							return fmt.Errorf("Fecha de vencimiento inválida: %v", err)
+						}
+					}
+
+					tarjetas.Monederos = append(tarjetas.Monederos, m)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar monedero: %w", err)
+					}
+
+					fmt.Printf("Monedero '%s' agregado exitosamente\n", m.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:      "saldo",
+				Usage:     "Actualizar el saldo de un monedero",
+				ArgsUsage: "<nombre> <saldo>",
+				Action: func(c *cli.Context) error {
+					nombre, saldo, err := argumentosNombreMonto(c)
+					if err != nil {
+						return err
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					for i, m := range tarjetas.Monederos {
+						if normalizarClave(m.Nombre) != normalizarClave(nombre) {
+							continue
+						}
+						tarjetas.Monederos[i].Saldo = saldo
+						if err := GuardarTarjetas(tarjetas); err != nil {
+							return fmt.Errorf("Error al guardar monedero: %w", err)
+						}
+						fmt.Printf("Saldo de '%s' actualizado a %.2f\n", m.Nombre, saldo)
+						return nil
+					}
+
+					return fmt.Errorf("No existe el monedero '%s'", nombre)
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar monederos registrados",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					hoy := time.Now()
+					if salidaEstructurada() {
+						filas := []FilaMonedero{}
+						for _, m := range tarjetas.Monederos {
+							filas = append(filas, FilaMonedero{m, m.ValorPesos(), m.RendimientoAnualNeto(), m.Vigente(hoy)})
+						}
+						return emitirDatos(filas)
+					}
+
+					if len(tarjetas.Monederos) == 0 {
+						fmt.Println("No hay monederos registrados")
+						return nil
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tProveedor\tTipo\tValor\tRendimiento\tRendimiento Neto Anual\tVencimiento")
+					fmt.Fprintln(w, "------\t---------\t----\t-----\t-----------\t----------------------\t-----------")
+
+					total := 0.0
+					for _, m := range tarjetas.Monederos {
+						vencimiento := m.Vencimiento
+						if vencimiento == "" {
+							vencimiento = "-"
+						} else if !m.Vigente(hoy) {
+							vencimiento += " (vencido)"
+						}
+						fmt.Fprintf(w, "%s\t%s\t%s\t$%.2f\t%.2f%%\t$%.2f\t%s\n",
+							m.Nombre, m.Proveedor, m.Tipo, m.ValorPesos(), m.TasaRendimiento*100,
+							m.RendimientoAnualNeto(), vencimiento)
+						if m.Vigente(hoy) {
+							total += m.ValorPesos()
+						}
+					}
+
+					w.Flush()
+					fmt.Printf("\nSaldo disponible en monederos: $%.2f\n", total)
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoMovimientos agrupa las operaciones sobre el historial de movimientos
+func comandoMovimientos() *cli.Command {
+	return &cli.Command{
+		Name:  "movimientos",
+		Usage: "Registro y consulta de movimientos de tus tarjetas",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar un movimiento sin reescribir el historial",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "fecha", Usage: "Fecha del movimiento (AAAA-MM-DD), por defecto hoy"},
+					&cli.StringFlag{Name: "descripcion", Usage: "Descripción del movimiento", Required: true},
+					&cli.Float64Flag{Name: "monto", Usage: "Monto (positivo para cargos, negativo para abonos)", Required: true},
+					&cli.StringFlag{Name: "categoria", Usage: "Categoría de gasto"},
+					&cli.StringFlag{Name: "tarjeta", Usage: "Nombre de la tarjeta asociada"},
+				},
+				Action: func(c *cli.Context) error {
+					fecha := c.String("fecha")
+					if fecha == "" {
+						fecha = time.Now().Format("2006-01-02")
+					}
+					if _, err := time.Parse("2006-01-02", fecha); err != nil {
+						return fmt.Errorf("Fecha inválida '%s', usa el formato AAAA-MM-DD", fecha)
+					}
+
+					m := Movimiento{
+						Fecha:       fecha,
+						Descripcion: c.String("descripcion"),
+						Monto:       c.Float64("monto"),
+						Categoria:   c.String("categoria"),
+						Tarjeta:     c.String("tarjeta"),
+					}
+
+					// Sin categoría explícita, las reglas de los scripts pueden asignarla
+					if m.Categoria == "" {
+						scripts, err := CargarScripts()
+						if err != nil {
+							return err
+						}
+						if m.Categoria, err = scripts.Categorizar(m); err != nil {
+							return err
+						}
+						if m.Categoria != "" {
+							fmt.Printf("Categoría asignada por script: %s\n", m.Categoria)
+						}
+					}
+
+					if err := AgregarMovimientos([]Movimiento{m}); err != nil {
+						return fmt.Errorf("Error al guardar movimiento: %w", err)
+					}
+
+					fmt.Printf("Movimiento '%s' registrado\n", m.Descripcion)
+					return nil
+				},
+			},
+			comandoMovimientosImportar(),
+			{
+				Name:  "listar",
+				Usage: "Listar movimientos por páginas",
+				Flags: flagsConsultaMovimientos(),
+				Action: func(c *cli.Context) error {
+					filtro := FiltroMovimientos{
+						Categoria: c.String("categoria"),
+						Tarjeta:   c.String("tarjeta"),
+					}
+					return imprimirPaginaMovimientos(c, filtro)
+				},
+			},
+			{
+				Name:      "buscar",
+				Usage:     "Buscar movimientos por descripción",
+				ArgsUsage: "<texto>",
+				Flags:     flagsConsultaMovimientos(),
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return fmt.Errorf("Indica el texto a buscar")
+					}
+
+					filtro := FiltroMovimientos{
+						Texto:     strings.Join(c.Args().Slice(), " "),
+						Categoria: c.String("categoria"),
+						Tarjeta:   c.String("tarjeta"),
+					}
+					return imprimirPaginaMovimientos(c, filtro)
+				},
+			},
+			{
+				Name:  "indexar",
+				Usage: "Reconstruir el índice de búsqueda de movimientos",
+				Action: func(c *cli.Context) error {
+					if !almacenEsArchivoJSON() {
+						fmt.Println("El índice solo se usa con --almacen json; la base SQLite no lo necesita")
+						return nil
+					}
+					indice, err := ReconstruirIndiceMovimientos()
+					if err != nil {
+						return fmt.Errorf("Error al indexar movimientos: %w", err)
+					}
+
+					fmt.Printf("Índice actualizado: %d categorías, %d tarjetas, %d palabras\n",
+						len(indice.Categorias), len(indice.Tarjetas), len(indice.Palabras))
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// flagsConsultaMovimientos son los filtros y la paginación comunes a listar y buscar
+func flagsConsultaMovimientos() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "categoria", Usage: "Filtrar por categoría"},
+		&cli.StringFlag{Name: "tarjeta", Usage: "Filtrar por tarjeta"},
+		&cli.IntFlag{Name: "pagina", Value: 1, Usage: "Número de página"},
+		&cli.IntFlag{Name: "por-pagina", Value: 50, Usage: "Movimientos por página"},
+		flagSalida(),
+	}
+}
+
+// imprimirPaginaMovimientos muestra una página de movimientos que cumplen el filtro
+func imprimirPaginaMovimientos(c *cli.Context, filtro FiltroMovimientos) error {
+	ndjson, err := salidaNDJSON(c)
+	if err != nil {
+		return err
+	}
+	// Sin --pagina, NDJSON recorre el archivo completo sin cargarlo en memoria
+	if ndjson && !c.IsSet("pagina") {
+		emisor := NuevoEmisorNDJSON(salidaDatos())
+		err := RecorrerMovimientosFiltrados(filtro, func(m Movimiento) error {
+			return emisor.Emitir(m)
+		})
+		if err != nil {
+			return fmt.Errorf("Error al leer movimientos: %w", err)
+		}
+		return nil
+	}
+
+	pagina, porPagina := c.Int("pagina"), c.Int("por-pagina")
+	if pagina < 1 {
+		pagina = 1
+	}
+	if porPagina < 1 {
+		porPagina = 50
+	}
+	movimientos, err := BuscarMovimientosIndexados(filtro, pagina, porPagina)
+	if err != nil {
+		return fmt.Errorf("Error al leer movimientos: %w", err)
+	}
+
+	if ndjson || salidaEstructurada() {
+		emisor := nuevoEmisor(ndjson)
+		for _, m := range movimientos {
+			if err := emisor.Emitir(m); err != nil {
+				return err
+			}
+		}
+		return emisor.Cerrar()
+	}
+
+	if len(movimientos) == 0 {
+		fmt.Println("No hay movimientos que mostrar")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Fecha\tDescripción\tMonto\tCategoría\tTarjeta")
+	fmt.Fprintln(w, "-----\t-----------\t-----\t---------\t-------")
+
+	for _, m := range movimientos {
+		fmt.Fprintf(w, "%s\t%s\t$%.2f\t%s\t%s\n", m.Fecha, m.Descripcion, m.Monto, m.Categoria, m.Tarjeta)
+	}
+
+	w.Flush()
+	fmt.Printf("\nPágina %d (%d movimientos).", pagina, len(movimientos))
+	hayMas := false
+	if len(movimientos) == porPagina {
+		// Una página de un movimiento que empieza justo después de esta dice si quedan más
+		siguiente, err := BuscarMovimientosIndexados(filtro, pagina*porPagina+1, 1)
+		if err != nil {
+			return fmt.Errorf("Error al leer movimientos: %w", err)
+		}
+		hayMas = len(siguiente) > 0
+	}
+	if hayMas {
+		fmt.Printf(" Usa --pagina %d para ver los siguientes.", pagina+1)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,342 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// indiceCredito busca una tarjeta de crédito registrada por nombre
+func indiceCredito(tarjetas Tarjetas, nombre string) (int, bool) {
+	for i, t := range tarjetas.Credito {
+		if normalizarClave(t.Nombre) == normalizarClave(nombre) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// indiceDebito busca una tarjeta de débito registrada por nombre
+func indiceDebito(tarjetas Tarjetas, nombre string) (int, bool) {
+	for i, t := range tarjetas.Debito {
+		if normalizarClave(t.Nombre) == normalizarClave(nombre) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// comandoCreditoPlanes administra los planes de meses sin intereses de las tarjetas
+func comandoCreditoPlanes() *cli.Command {
+	return &cli.Command{
+		Name:  "planes",
+		Usage: "Planes de meses sin intereses vigentes",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "agregar",
+				Usage:     "Registrar una compra a MSI en una tarjeta",
+				ArgsUsage: "<tarjeta>",
+				Action: func(c *cli.Context) error {
+					if c.NArg() == 0 {
+						return fmt.Errorf("Indica la tarjeta, p. ej. credito planes agregar Oro")
+					}
+					nombre := argumentoNombre(c.Args().Slice())
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					i, ok := indiceCredito(tarjetas, nombre)
+					if !ok {
+						return errTarjetaNoEncontrada("credito", nombre)
+					}
+
+					var p PlanMSI
+					if p.Concepto, err = leerTextoRequerido("Concepto de la compra: "); err != nil {
+						return err
+					}
+					if p.Monto, err = leerNumero("Monto de la compra: ", limitesMonto); err != nil {
+						return err
+					}
+					if p.Meses, err = leerEntero("Meses sin intereses: ", 1, 48); err != nil {
+						return err
+					}
+					if p.Inicio, err = leerTextoRequerido("Mes de la primera mensualidad (AAAA-MM): "); err != nil {
+						return err
+					}
+					if _, err := time.Parse("2006-01", p.Inicio); err != nil {
+						return fmt.Errorf("Mes inválido: %s (usa AAAA-MM)", p.Inicio)
+					}
+
+					tarjetas.Credito[i].Planes = append(tarjetas.Credito[i].Planes, p)
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar tarjeta: %w", err)
+					}
+					fmt.Printf("Plan '%s' agregado a %s: %d mensualidades de $%.2f\n", p.Concepto, tarjetas.Credito[i].Nombre, p.Meses, p.Mensualidad())
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar los planes de MSI de todas las tarjetas",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if salidaEstructurada() {
+						type fila struct {
+							Tarjeta string `json:"tarjeta"`
+							PlanMSI
+							Mensualidad float64 `json:"mensualidad"`
+						}
+						filas := []fila{}
+						for _, t := range tarjetas.Credito {
+							for _, p := range t.Planes {
+								filas = append(filas, fila{t.Nombre, p, p.Mensualidad()})
+							}
+						}
+						return emitirDatos(filas)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Tarjeta\tConcepto\tMonto\tMeses\tMensualidad\tInicio")
+					fmt.Fprintln(w, "-------\t--------\t-----\t-----\t-----------\t------")
+					planes := 0
+					for _, t := range tarjetas.Credito {
+						for _, p := range t.Planes {
+							fmt.Fprintf(w, "%s\t%s\t$%.2f\t%d\t$%.2f\t%s\n", t.Nombre, p.Concepto, p.Monto, p.Meses, p.Mensualidad(), p.Inicio)
+							planes++
+						}
+					}
+					if planes == 0 {
+						fmt.Println("No hay planes de meses sin intereses registrados")
+						return nil
+					}
+					w.Flush()
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// PLAZOS_MSI son los plazos de meses sin intereses más comunes en tiendas y bancos
+const PLAZOS_MSI = "3,6,12,18"
+
+// parsearPlazos convierte una lista de meses separados por comas
+func parsearPlazos(texto string) ([]int, error) {
+	var plazos []int
+	for _, parte := range strings.Split(texto, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(parte))
+		if err != nil || n < 1 || n > 48 {
+			return nil, errDatosInvalidos(
+				fmt.Sprintf("Plazo inválido '%s': usa meses entre 1 y 48 separados por comas", parte),
+				fmt.Sprintf("Invalid term '%s': use months between 1 and 48 separated by commas", parte))
+		}
+		plazos = append(plazos, n)
+	}
+	return plazos, nil
+}
+
+// etiquetaOpcionCompra describe una forma de pago en la tabla
+func etiquetaOpcionCompra(o calc.OpcionCompra, descuento float64) string {
+	switch o.Forma {
+	case calc.PagoMSI:
+		return fmt.Sprintf("%d MSI", o.Meses)
+	case calc.PagoIntereses:
+		return fmt.Sprintf("%d meses con intereses", o.Meses)
+	}
+	if descuento > 0 {
+		return fmt.Sprintf("Contado (%.1f%% de descuento)", descuento*100)
+	}
+	return "Contado"
+}
+
+// comandoCreditoMSI simula una compra a meses sin intereses contra pagarla de contado o
+// diferirla con intereses, considerando lo que rinde el dinero mientras no se paga
+func comandoCreditoMSI() *cli.Command {
+	return &cli.Command{
+		Name:  "msi",
+		Usage: "Simular una compra a meses sin intereses contra pagar de contado o con intereses",
+		Flags: []cli.Flag{
+			conLimites(&cli.Float64Flag{Name: "monto", Usage: "Precio de la compra"}, limitesMonto),
+			&cli.StringFlag{Name: "plazos", Value: PLAZOS_MSI, Usage: "Plazos de MSI a simular, separados por comas"},
+			conLimites(&cli.Float64Flag{Name: "descuento", Usage: "Descuento por pagar de contado en decimal (0.05 para 5%)"}, LimitesNumero{Min: 0, Max: 0.99, Porcentaje: true}),
+			&cli.StringFlag{Name: "tarjeta", Usage: "Tarjeta de crédito para diferir con intereses; por defecto la de menor tasa"},
+			conLimites(&cli.Float64Flag{Name: "tasa-oportunidad", Usage: "Tasa anual neta que rinde tu dinero; por defecto la mejor de tus cuentas de débito y CETES"}, limitesTasa),
+		},
+		Action: func(c *cli.Context) error {
+			plazos, err := parsearPlazos(c.String("plazos"))
+			if err != nil {
+				return err
+			}
+			descuento := c.Float64("descuento")
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			monto := c.Float64("monto")
+			if !c.IsSet("monto") {
+				if monto, err = leerNumero("Precio de la compra: ", limitesMonto); err != nil {
+					return err
+				}
+			}
+
+			// La tarjeta solo se usa para la opción de diferir con intereses
+			var tarjeta *TarjetaCredito
+			if nombre := c.String("tarjeta"); nombre != "" {
+				i, ok := indiceCredito(tarjetas, nombre)
+				if !ok {
+					return errTarjetaNoEncontrada("credito", nombre)
+				}
+				tarjeta = &tarjetas.Credito[i]
+			} else {
+				for i, t := range tarjetas.Credito {
+					if tarjeta == nil || t.TasaInteres < tarjeta.TasaInteres {
+						tarjeta = &tarjetas.Credito[i]
+					}
+				}
+			}
+			tasaCredito := 0.0
+			if tarjeta != nil {
+				tasaCredito = tarjeta.TasaInteres
+				if !tarjeta.MesesSinIntereses {
+					fmt.Printf("AVISO: La tarjeta %s no tiene registrado que ofrezca meses sin intereses\n", tarjeta.Nombre)
+				}
+			}
+
+			tasaOportunidad, fuente := MejorTasaOportunidad(tarjetas)
+			if c.IsSet("tasa-oportunidad") {
+				tasaOportunidad, fuente = c.Float64("tasa-oportunidad"), "indicada"
+			} else if fuente == "" {
+				if tasaOportunidad, err = leerNumero("Tasa anual neta que rinde tu dinero mientras no pagas (decimal): ", limitesTasa); err != nil {
+					return err
+				}
+				fuente = "capturada"
+			}
+
+			opciones := calc.SimularCompraMSI(monto, descuento, tasaOportunidad, tasaCredito, plazos)
+			if salidaEstructurada() {
+				return emitirDatos(opciones)
+			}
+
+			fmt.Println("\n=== Meses Sin Intereses vs Contado ===")
+			fmt.Printf("Compra: $%.2f\n", monto)
+			fmt.Printf("Tasa de oportunidad: %.2f%% neta (%s)\n", tasaOportunidad*100, fuente)
+			if tarjeta != nil {
+				fmt.Printf("Tasa para diferir con intereses: %.2f%% anual más IVA (%s)\n", tasaCredito*100, tarjeta.Nombre)
+			}
+			fmt.Println()
+
+			contado := opciones[0]
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Opción\tPago Mensual\tTotal Pagado\tIntereses\tIVA\tValor Presente\tVs Contado")
+			fmt.Fprintln(w, "------\t------------\t------------\t---------\t---\t--------------\t----------")
+			for _, o := range opciones {
+				pago, diferencia, intereses, iva := "-", "-", "-", "-"
+				if o.Forma == calc.PagoIntereses {
+					intereses, iva = fmt.Sprintf("$%.2f", o.Intereses), fmt.Sprintf("$%.2f", o.IVA)
+				}
+				if o.Forma != calc.PagoContado {
+					pago = fmt.Sprintf("$%.2f", o.Pago)
+					if d := o.ValorPresente - contado.ValorPresente; d < 0 {
+						diferencia = fmt.Sprintf("-$%.2f", -d)
+					} else {
+						diferencia = fmt.Sprintf("+$%.2f", d)
+					}
+				}
+				fmt.Fprintf(w, "%s\t%s\t$%.2f\t%s\t%s\t$%.2f\t%s\n", etiquetaOpcionCompra(o, descuento), pago, o.TotalPagado, intereses, iva, o.ValorPresente, diferencia)
+			}
+			w.Flush()
+
+			fmt.Println("\nValor presente: lo que cuestan hoy los pagos si el dinero sigue invertido a la tasa de oportunidad.")
+			mejor := opciones[calc.MejorOpcionCompra(opciones)]
+			if mejor.Forma == calc.PagoContado {
+				fmt.Println("RESULTADO: Conviene pagar de contado; lo que rinde tu dinero no compensa el descuento")
+			} else {
+				fmt.Printf("RESULTADO: Conviene pagar a %s: ahorras $%.2f en valor presente frente a pagar de contado\n",
+					etiquetaOpcionCompra(mejor, descuento), contado.ValorPresente-mejor.ValorPresente)
+			}
+			return nil
+		},
+	}
+}
+
+// comandoCreditoAplicarPago simula el reparto de un pago entre MSI y saldo revolvente
+func comandoCreditoAplicarPago() *cli.Command {
+	return &cli.Command{
+		Name:  "aplicar-pago",
+		Usage: "Simular cómo se reparte un pago entre mensualidades MSI y saldo revolvente",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tarjeta", Required: true, Usage: "Nombre de la tarjeta"},
+			conLimites(&cli.Float64Flag{Name: "pago", Usage: "Pago que planeas hacer este mes"}, limitesMonto),
+			&cli.StringFlag{Name: "mes", Usage: "Mes del pago (AAAA-MM), por defecto el actual"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, ok := indiceCredito(tarjetas, c.String("tarjeta"))
+			if !ok {
+				return errTarjetaNoEncontrada("credito", c.String("tarjeta"))
+			}
+			tarjeta := tarjetas.Credito[i]
+
+			mes := time.Now()
+			if c.String("mes") != "" {
+				if mes, err = time.Parse("2006-01", c.String("mes")); err != nil {
+					return fmt.Errorf("Mes inválido: %s (usa AAAA-MM)", c.String("mes"))
+				}
+			}
+
+			pago := c.Float64("pago")
+			if !c.IsSet("pago") {
+				if pago, err = leerNumero("Pago que planeas hacer este mes: ", limitesMonto); err != nil {
+					return err
+				}
+			}
+
+			a, err := calc.AplicarPago(tarjeta, pago, mes)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\n=== Aplicación del Pago (%s, %s) ===\n", tarjeta.Nombre, mes.Format("2006-01"))
+			fmt.Printf("Mensualidades MSI del mes: $%.2f\n", a.MensualidadesMSI)
+			fmt.Printf("Saldo revolvente: $%.2f\n", a.Revolvente)
+			fmt.Printf("PAGO PARA NO GENERAR INTERESES: $%.2f\n\n", a.PagoParaNoGenerarInt)
+
+			fmt.Printf("Tu pago de $%.2f se aplica así:\n", a.Pago)
+			fmt.Printf("  1. Mensualidades MSI: $%.2f\n", a.AplicadoMSI)
+			fmt.Printf("  2. Saldo revolvente: $%.2f\n", a.AplicadoRevolvente)
+			if a.Excedente > 0 {
+				fmt.Printf("  Saldo a favor: $%.2f\n", a.Excedente)
+			}
+			if a.MSISinCubrir > 0 {
+				fmt.Printf("AVISO: Quedan $%.2f de mensualidades MSI sin cubrir; pasan al saldo revolvente\n", a.MSISinCubrir)
+			}
+
+			if a.RevolventeRestante > 0 {
+				fmt.Printf("\nSaldo que genera intereses: $%.2f\n", a.RevolventeRestante)
+				fmt.Printf("Intereses del siguiente mes: $%.2f + IVA $%.2f = $%.2f\n", a.Interes, a.IVAInteres, a.Interes+a.IVAInteres)
+				fmt.Printf("RESULTADO: Te faltan $%.2f para no pagar intereses\n", a.PagoParaNoGenerarInt-a.Pago)
+			} else {
+				fmt.Println("\nRESULTADO: Con este pago no generas intereses")
+			}
+			if a.SaldoMSIPendiente > 0 {
+				fmt.Printf("Mensualidades MSI de meses siguientes: $%.2f\n", a.SaldoMSIPendiente)
+			}
+			return nil
+		},
+	}
+}
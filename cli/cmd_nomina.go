@@ -0,0 +1,366 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoNomina agrupa las operaciones con cuentas de nómina
+func comandoNomina() *cli.Command {
+	return &cli.Command{
+		Name:  "nomina",
+		Usage: "Operaciones con cuentas de nómina y cálculo del sueldo neto",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "recibo",
+				Usage: "Calcular el sueldo neto con ISR, subsidio para el empleo y cuotas del IMSS",
+				Description: "El salario base de cotización se integra con las prestaciones mínimas de ley (aguinaldo de 15 días\n" +
+					"y vacaciones con 25% de prima) salvo que indiques --factor-integracion. No incluye otras percepciones\n" +
+					"ni deducciones como vales, fondo de ahorro o crédito Infonavit.",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "sueldo", Required: true, Usage: "Sueldo bruto mensual"}, limitesMonto),
+					&cli.IntFlag{Name: "anio", Aliases: []string{"año"}, Usage: "Año de las tarifas, por defecto el actual"},
+					&cli.IntFlag{Name: "mes", Usage: "Mes del recibo (1 a 12), por defecto el actual; en 2024 el subsidio cambió en mayo"},
+					conLimites(&cli.Float64Flag{Name: "factor-integracion", Usage: "Factor de integración del salario base de cotización (ej. 1.0493)"}, LimitesNumero{Min: 0, Max: 3}),
+				},
+				Action: func(c *cli.Context) error {
+					sueldo := c.Float64("sueldo")
+					año := c.Int("anio")
+					if año == 0 {
+						año = time.Now().Year()
+					}
+					mes := time.Month(c.Int("mes"))
+					if !c.IsSet("mes") {
+						mes = time.Now().Month()
+					}
+					if mes < time.January || mes > time.December {
+						return errDatosInvalidos(fmt.Sprintf("--mes debe estar entre 1 y 12, no %d", mes), fmt.Sprintf("--mes must be between 1 and 12, not %d", mes))
+					}
+					r, err := calc.CalcularReciboNomina(sueldo, año, mes, c.Float64("factor-integracion"))
+					if err != nil {
+						return err
+					}
+
+					if salidaEstructurada() {
+						return emitirDatos(r)
+					}
+
+					fmt.Printf("\n=== Recibo de Nómina Mensual (tarifas %02d/%d) ===\n", int(r.Mes), r.Año)
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Percepciones\t\t")
+					fmt.Fprintf(w, "  Sueldo\t$%.2f\t\n", r.SueldoBruto)
+					if r.SubsidioEntregado > 0 {
+						fmt.Fprintf(w, "  Subsidio para el empleo entregado\t$%.2f\t\n", r.SubsidioEntregado)
+					}
+					fmt.Fprintln(w, "Deducciones\t\t")
+					fmt.Fprintf(w, "  ISR según tarifa (tasa marginal %.2f%%)\t$%.2f\t\n", r.TasaMarginal*100, r.ISRTarifa)
+					if r.Subsidio > 0 {
+						fmt.Fprintf(w, "  Subsidio para el empleo\t-$%.2f\t\n", r.Subsidio-r.SubsidioEntregado)
+					}
+					fmt.Fprintf(w, "  ISR retenido\t$%.2f\t\n", r.ISR)
+					for _, concepto := range r.IMSS {
+						fmt.Fprintf(w, "  IMSS %s\t$%.2f\t\n", strings.ToLower(concepto.Concepto[:1])+concepto.Concepto[1:], concepto.Importe)
+					}
+					fmt.Fprintf(w, "  Total IMSS\t$%.2f\t\n", r.TotalIMSS)
+					fmt.Fprintf(w, "Neto a pagar\t$%.2f\t\n", r.Neto)
+					w.Flush()
+
+					fmt.Printf("Salario base de cotización: $%.2f diarios\n", r.SBCDiario)
+					if r.SalarioMinimo {
+						fmt.Println("AVISO: Con el salario mínimo no se retiene ISR y el patrón paga tus cuotas del IMSS")
+					}
+					fmt.Printf("RESULTADO: Recibes $%.2f de $%.2f (%.1f%%); te descuentan $%.2f al mes y $%.2f al año\n",
+						r.Neto, r.SueldoBruto, r.Neto/r.SueldoBruto*100, r.SueldoBruto-r.Neto, (r.SueldoBruto-r.Neto)*12)
+					return nil
+				},
+			},
+			{
+				Name:  "agregar",
+				Usage: "Agregar una cuenta de nómina",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var cuenta CuentaNomina
+
+					if cuenta.Nombre, err = leerTextoRequerido("Nombre de la cuenta: "); err != nil {
+						return err
+					}
+					if cuenta.Banco, err = leerTextoRequerido("Banco: "); err != nil {
+						return err
+					}
+					if cuenta.TasaRendimiento, err = leerNumero("Tasa de rendimiento anual (decimal, ej: 0.05 para 5%): ", limitesTasa); err != nil {
+						return err
+					}
+					if cuenta.ComisionMensual, err = leerNumero("Comisión mensual por manejo de cuenta (0 si no cobra): ", limitesMonto); err != nil {
+						return err
+					}
+					if cuenta.AnticipoNomina, err = leerSiNo("¿Ofrece anticipo de nómina? (s/n): "); err != nil {
+						return err
+					}
+					if cuenta.AnticipoNomina {
+						if cuenta.TasaAnticipo, err = leerNumero("Tasa anual del anticipo (decimal): ", limitesTasa); err != nil {
+							return err
+						}
+					}
+					if cuenta.PromocionTraspaso, err = leerNumero("Bono por traer tu nómina (0 si no hay): ", limitesMonto); err != nil {
+						return err
+					}
+					if cuenta.BeneficioAnual, err = leerNumero("Otros beneficios valuados al año (seguros, descuentos): ", limitesMonto); err != nil {
+						return err
+					}
+
+					tarjetas.Nomina = append(tarjetas.Nomina, cuenta)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar cuenta: %w", err)
+					}
+
+					fmt.Printf("Cuenta de nómina '%s' agregada exitosamente\n", cuenta.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar cuentas de nómina registradas",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if salidaEstructurada() {
+						return emitirDatos(tarjetas.Nomina)
+					}
+
+					if len(tarjetas.Nomina) == 0 {
+						fmt.Println("No hay cuentas de nómina registradas")
+						return nil
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tBanco\tRendimiento\tComisión Mensual\tAnticipo\tBono Traspaso\tOtros Beneficios")
+					fmt.Fprintln(w, "------\t-----\t-----------\t----------------\t--------\t-------------\t----------------")
+
+					for _, n := range tarjetas.Nomina {
+						anticipo := "No"
+						if n.AnticipoNomina {
+							anticipo = fmt.Sprintf("Sí (%.1f%%)", n.TasaAnticipo*100)
+						}
+						fmt.Fprintf(w, "%s\t%s\t%.2f%%\t$%.2f\t%s\t$%.2f\t$%.2f\n",
+							n.Nombre, n.Banco, n.TasaRendimiento*100, n.ComisionMensual,
+							anticipo, n.PromocionTraspaso, n.BeneficioAnual)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:  "portabilidad",
+				Usage: "Comparar cuánto ganas o pierdes al cambiar tu nómina de banco",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Nomina) < 2 {
+						return fmt.Errorf("Se necesitan al menos 2 cuentas de nómina para comparar")
+					}
+
+					var nombres []string
+					for _, n := range tarjetas.Nomina {
+						nombres = append(nombres, fmt.Sprintf("%s (%s)", n.Nombre, n.Banco))
+					}
+					seleccion, err := elegirOpcion("¿Cuál es tu cuenta de nómina actual?", nombres)
+					if err != nil {
+						return err
+					}
+
+					saldo, err := leerNumero("Saldo promedio que mantienes en la cuenta: ", limitesMonto)
+					if err != nil {
+						return err
+					}
+
+					var uso UsoAnticipo
+					if uso.VecesAlAño, err = leerEntero("¿Cuántas veces al año pides anticipo de nómina? (0 si nunca): ", 0, 24); err != nil {
+						return err
+					}
+					if uso.VecesAlAño > 0 {
+						if uso.Monto, err = leerNumero("Monto promedio de cada anticipo: ", limitesMonto); err != nil {
+							return err
+						}
+					}
+
+					actual := tarjetas.Nomina[seleccion]
+					var opciones []CuentaNomina
+					for i, n := range tarjetas.Nomina {
+						if i != seleccion {
+							opciones = append(opciones, n)
+						}
+					}
+
+					valorActual, comparacion := CompararPortabilidad(actual, opciones, saldo, uso)
+
+					fmt.Println("\n=== Portabilidad de Nómina ===")
+					fmt.Printf("Cuenta actual: %s (%s)\n", actual.Nombre, actual.Banco)
+					fmt.Printf("Valor anual de tu cuenta actual: $%.2f\n\n", valorActual.Total)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Destino\tBanco\tValor Anual\tDiferencia Anual\tBono Traspaso\tPrimer Año\tResultado")
+					fmt.Fprintln(w, "-------\t-----\t-----------\t----------------\t-------------\t----------\t---------")
+
+					for _, p := range comparacion {
+						resultado := "PIERDES"
+						if p.DiferenciaAnual > 0 {
+							resultado = "GANAS"
+						}
+						if p.Valor.SinAnticipo {
+							resultado += " (sin anticipo)"
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t%s\n",
+							p.Destino.Nombre, p.Destino.Banco, p.Valor.Total, p.DiferenciaAnual,
+							p.Destino.PromocionTraspaso, p.PrimerAño, resultado)
+					}
+
+					w.Flush()
+
+					if valorActual.SinAnticipo {
+						fmt.Println("\nAVISO: Tu cuenta actual no ofrece anticipo de nómina aunque indicaste que lo usas.")
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "credito",
+				Usage: "Comparar un crédito de nómina contra cargar el monto a una tarjeta de crédito",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "flujo", Usage: "Mostrar el flujo de pagos quincena por quincena"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Credito) == 0 {
+						return fmt.Errorf("No hay tarjetas de crédito registradas para comparar")
+					}
+
+					var credito CreditoNomina
+					if credito.Monto, err = leerNumero("Monto del crédito de nómina: ", limitesMonto); err != nil {
+						return err
+					}
+					if credito.TasaAnual, err = leerNumero("Tasa de interés anual (decimal, ej: 0.24 para 24%): ", limitesTasa); err != nil {
+						return err
+					}
+					if credito.Quincenas, err = leerEntero("Plazo en quincenas: ", 1, 720); err != nil {
+						return err
+					}
+					if credito.ComisionApertura, err = leerNumero("Comisión por apertura (decimal, 0 si no cobra): ", limitesTasa); err != nil {
+						return err
+					}
+
+					var nombres []string
+					for _, t := range tarjetas.Credito {
+						nombres = append(nombres, fmt.Sprintf("%s (%s)", t.Nombre, t.Banco))
+					}
+					seleccion, err := elegirOpcion("Tarjeta con la que comparar:", nombres)
+					if err != nil {
+						return err
+					}
+					tarjeta := tarjetas.Credito[seleccion]
+
+					pagoTarjeta, err := leerNumero(fmt.Sprintf("Pago quincenal que harías a la tarjeta (el crédito de nómina descuenta $%.2f): ",
+						credito.PagoQuincenal()), limitesMonto)
+					if err != nil {
+						return err
+					}
+
+					desempleo, err := leerEntero("¿En qué quincena quieres evaluar perder el empleo? (0 para omitir): ", 0, 720)
+					if err != nil {
+						return err
+					}
+
+					r := CompararNominaTarjeta(credito, tarjeta, pagoTarjeta, desempleo)
+
+					fmt.Println("\n=== Crédito de Nómina vs Tarjeta de Crédito ===")
+					fmt.Printf("Monto: $%.2f\n\n", credito.Monto)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Opción\tPago Quincenal\tQuincenas\tCosto Total\tTotal Pagado")
+					fmt.Fprintln(w, "------\t--------------\t---------\t-----------\t------------")
+					fmt.Fprintf(w, "Crédito de nómina (%.1f%%)\t$%.2f\t%d\t$%.2f\t$%.2f\n",
+						credito.TasaAnual*100, r.PagoNomina, credito.Quincenas, r.CostoNomina, credito.Monto+r.CostoNomina)
+					fmt.Fprintf(w, "%s (%.1f%%)\t$%.2f\t%d\t$%.2f\t$%.2f\n",
+						tarjeta.Nombre, tarjeta.TasaInteres*100, r.PagoTarjeta, r.QuincenasTarjeta, r.CostoTarjeta, credito.Monto+r.CostoTarjeta)
+					w.Flush()
+
+					if r.CostoNomina < r.CostoTarjeta {
+						fmt.Printf("\nRESULTADO: El crédito de nómina cuesta $%.2f menos\n", r.CostoTarjeta-r.CostoNomina)
+					} else {
+						fmt.Printf("\nRESULTADO: La tarjeta %s cuesta $%.2f menos\n", tarjeta.Nombre, r.CostoNomina-r.CostoTarjeta)
+					}
+
+					if desempleo > 0 {
+						fmt.Printf("\n=== Riesgo si pierdes el empleo en la quincena %d ===\n", desempleo)
+						fmt.Printf("Crédito de nómina: debes $%.2f y ya no se descuenta de tu sueldo; sigues obligado al pago de $%.2f por quincena\n",
+							r.SaldoNominaRiesgo, r.PagoNomina)
+						fmt.Printf("Tarjeta %s: debes $%.2f y puedes bajar al pago mínimo de $%.2f al mes mientras te recuperas\n",
+							tarjeta.Nombre, r.SaldoTarjetaRiesgo, r.MinimoTarjeta)
+					}
+
+					if c.Bool("flujo") {
+						imprimirFlujoQuincenal(credito, tarjeta, r)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// imprimirFlujoQuincenal muestra los pagos y saldos de ambas opciones quincena por quincena
+func imprimirFlujoQuincenal(credito CreditoNomina, tarjeta TarjetaCredito, r ComparacionNominaTarjeta) {
+	quincenas := credito.Quincenas
+	if r.QuincenasTarjeta > quincenas {
+		quincenas = r.QuincenasTarjeta
+	}
+
+	tasaTarjeta := calc.TasaConIVA(tarjeta.TasaInteres) / float64(FrecuenciaQuincenal.PeriodosPorAño())
+	fechas := calc.CalendarioPagos(time.Now(), FrecuenciaQuincenal, quincenas)
+
+	fmt.Println("\n=== Flujo Quincenal ===")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Quincena\tFecha\tPago Nómina\tSaldo Nómina\tPago Tarjeta\tSaldo Tarjeta")
+	fmt.Fprintln(w, "--------\t-----\t-----------\t------------\t------------\t-------------")
+
+	saldoTarjeta := credito.Monto
+	for q := 1; q <= quincenas; q++ {
+		pagoNomina := 0.0
+		if q <= credito.Quincenas {
+			pagoNomina = r.PagoNomina
+		}
+
+		interes := saldoTarjeta * tasaTarjeta
+		pagoTarjeta := math.Min(r.PagoTarjeta, saldoTarjeta+interes)
+		saldoTarjeta = saldoTarjeta + interes - pagoTarjeta
+		if saldoTarjeta < 0.01 {
+			saldoTarjeta = 0
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n", q, fechas[q-1].Format("2006-01-02"),
+			pagoNomina, credito.SaldoDespues(q), pagoTarjeta, saldoTarjeta)
+	}
+	w.Flush()
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoPagar recomienda con qué tarjeta de crédito pagar una compra
+func comandoPagar() *cli.Command {
+	return &cli.Command{
+		Name:  "pagar",
+		Usage: "Elegir con qué tarjeta de crédito pagar una compra y por qué",
+		Description: "Evalúa cada tarjeta de crédito con las reglas del motor: el cashback de la categoría,\n" +
+			"los meses sin intereses (o el costo de diferir con intereses si la tarjeta no los ofrece),\n" +
+			"los días hasta la fecha límite de pago y la deuda revolvente, que quita el periodo de\n" +
+			"gracia. Los plazos se valúan contra la tasa de oportunidad; se descartan las tarjetas sin\n" +
+			"crédito disponible para la compra. Las categorías sin tasa propia usan el cashback general.\n" +
+			"Ejemplo: finmex pagar --monto 8000 --categoria electronica --msi 12",
+		Flags: []cli.Flag{
+			conLimites(&cli.Float64Flag{Name: "monto", Usage: "Precio de la compra"}, limitesMonto),
+			&cli.StringFlag{Name: "categoria", Value: categoriaGeneral, Usage: "Categoría de gasto de la compra (" + strings.Join(CategoriasGasto, ", ") + " u otra)"},
+			&cli.IntFlag{Name: "msi", Usage: "Meses en que quieres pagarla; 0 es de contado"},
+			&cli.StringFlag{Name: "fecha", Usage: "Fecha de la compra (AAAA-MM-DD), por defecto hoy"},
+			conLimites(&cli.Float64Flag{Name: "tasa-oportunidad", Usage: "Tasa anual neta que rinde tu dinero; por defecto la mejor de tus cuentas de débito y CETES"}, limitesTasa),
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			if len(tarjetas.Credito) == 0 {
+				return fmt.Errorf("No hay tarjetas de crédito registradas")
+			}
+
+			msi := c.Int("msi")
+			if msi < 0 || msi > 60 {
+				return errDatosInvalidos(fmt.Sprintf("--msi debe estar entre 0 y 60, no %d", msi), fmt.Sprintf("--msi must be between 0 and 60, not %d", msi))
+			}
+			compra := CompraPago{MSI: msi, Fecha: time.Now().Truncate(24 * time.Hour), Categoria: normalizarClave(c.String("categoria"))}
+			if compra.Categoria == "" {
+				compra.Categoria = categoriaGeneral
+			}
+			if c.String("fecha") != "" {
+				if compra.Fecha, err = time.Parse("2006-01-02", c.String("fecha")); err != nil {
+					return fmt.Errorf("Fecha inválida: %s (usa AAAA-MM-DD)", c.String("fecha"))
+				}
+			}
+
+			tasaOportunidad, fuente := MejorTasaOportunidad(tarjetas)
+			if c.IsSet("tasa-oportunidad") {
+				tasaOportunidad, fuente = c.Float64("tasa-oportunidad"), "indicada"
+			} else if fuente == "" {
+				fuente = "sin cuentas registradas"
+			}
+			compra.TasaOportunidad = tasaOportunidad
+
+			if compra.Monto, err = nuevaCaptura(c).Numero("monto", "Precio de la compra: ", limitesMonto); err != nil {
+				return err
+			}
+
+			evaluaciones := EscogerTarjetaPago(tarjetas.Credito, compra)
+			if salidaEstructurada() {
+				return emitirDatos(evaluaciones)
+			}
+
+			forma := "de contado"
+			if msi > 0 {
+				forma = fmt.Sprintf("a %d meses", msi)
+			}
+			fmt.Println("=== ¿Con qué tarjeta pagar? ===")
+			fmt.Printf("Compra: $%.2f en %s, %s, el %s\n", compra.Monto, compra.Categoria, forma, compra.Fecha.Format("2006-01-02"))
+			fmt.Printf("Tasa de oportunidad: %.2f%% neta (%s)\n\n", tasaOportunidad*100, fuente)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Tarjeta\tBanco\tDisponible\tValor Neto")
+			fmt.Fprintln(w, "-------\t-----\t----------\t----------")
+			for _, e := range evaluaciones {
+				disponible := "-"
+				if e.Disponible != 0 {
+					disponible = fmt.Sprintf("$%.2f", e.Disponible)
+				}
+				neto := fmt.Sprintf("$%.2f", e.Neto)
+				if e.Descartada {
+					neto = "sin crédito disponible"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Tarjeta, e.Banco, disponible, neto)
+			}
+			w.Flush()
+
+			mejor := evaluaciones[0]
+			if mejor.Descartada {
+				fmt.Printf("\nALERTA: Ninguna tarjeta tiene $%.2f de crédito disponible para esta compra\n", compra.Monto)
+				return nil
+			}
+			fmt.Printf("\nRESULTADO: Paga con %s: la compra te deja $%.2f netos\n", mejor.Tarjeta, mejor.Neto)
+			for _, r := range mejor.Razones {
+				fmt.Printf("  %+.2f  %s\n", r.Valor, r.Detalle)
+			}
+			if len(mejor.Razones) == 0 {
+				fmt.Println("  Ninguna regla le da ventaja; es la de menor tasa de interés")
+			}
+			if len(evaluaciones) > 1 && !evaluaciones[1].Descartada {
+				fmt.Printf("Le sigue %s con $%.2f ($%.2f menos)\n", evaluaciones[1].Tarjeta, evaluaciones[1].Neto, mejor.Neto-evaluaciones[1].Neto)
+			}
+			if msi > 0 {
+				for _, r := range mejor.Razones {
+					if r.Regla == "msi" && r.Valor < 0 {
+						fmt.Printf("AVISO: %s no ofrece meses sin intereses; diferirla con intereses cuesta más que pagar de contado\n", mejor.Tarjeta)
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
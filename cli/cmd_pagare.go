@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoPagare agrupa las operaciones con pagarés bancarios a plazo fijo
+func comandoPagare() *cli.Command {
+	return &cli.Command{
+		Name:  "pagare",
+		Usage: "Pagarés bancarios a plazo fijo (PRLV)",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar un pagaré bancario",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "nombre", Usage: "Nombre del pagaré"},
+					&cli.StringFlag{Name: "banco", Usage: "Banco emisor"},
+					conLimites(&cli.Float64Flag{Name: "monto", Usage: "Monto invertido"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "tasa", Usage: "Tasa anual bruta en decimal (0.095 para 9.5%)"}, limitesTasa),
+					&cli.IntFlag{Name: "plazo", Usage: "Plazo en días: " + plazosPagareTexto()},
+					&cli.BoolFlag{Name: "renovacion", Usage: "Se renueva automáticamente con capital e intereses al vencer"},
+					conLimites(&cli.Float64Flag{Name: "penalizacion", Usage: "Parte del interés que se pierde al retirar antes del vencimiento (1 para todo)"}, limitesPenalizacion),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var pagare PagareBancario
+					captura := nuevaCaptura(c)
+
+					if pagare.Nombre, err = captura.Texto("nombre", "Nombre del pagaré: "); err != nil {
+						return err
+					}
+					if pagare.Banco, err = captura.Texto("banco", "Banco emisor: "); err != nil {
+						return err
+					}
+					if pagare.Monto, err = captura.Numero("monto", "Monto invertido: ", limitesMonto); err != nil {
+						return err
+					}
+					if pagare.Tasa, err = captura.Numero("tasa", "Tasa anual bruta (decimal, ej: 0.095 para 9.5%): ", limitesTasa); err != nil {
+						return err
+					}
+					if c.IsSet("plazo") {
+						pagare.PlazoDias = c.Int("plazo")
+					} else if pagare.PlazoDias, err = leerEntero(fmt.Sprintf("Plazo en días (%s): ", plazosPagareTexto()), 1, 364); err != nil {
+						return err
+					}
+					if err := ValidarPlazoPagare(pagare.PlazoDias); err != nil {
+						return err
+					}
+					if pagare.Renovacion, err = captura.SiNo("renovacion", "¿Se renueva automáticamente al vencer? (s/n): "); err != nil {
+						return err
+					}
+					if pagare.Penalizacion, err = captura.NumeroOpcional("penalizacion", "Parte del interés que se pierde al retirar antes (decimal, 1 para todo): ", limitesPenalizacion); err != nil {
+						return err
+					}
+
+					tarjetas.Pagares = append(tarjetas.Pagares, pagare)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar pagaré: %w", err)
+					}
+
+					fmt.Printf("Pagaré '%s' agregado exitosamente\n", pagare.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar pagarés bancarios",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Pagares) == 0 && !salidaEstructurada() {
+						fmt.Println("No hay pagarés registrados")
+						return nil
+					}
+
+					inflacion := InflacionVigente()
+					if salidaEstructurada() {
+						filas := []FilaPagare{}
+						for _, p := range tarjetas.Pagares {
+							filas = append(filas, FilaPagare{PagareBancario: p, RendimientoPagare: p.Rendimiento(inflacion), Inflacion: inflacion})
+						}
+						return emitirDatos(filas)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tBanco\tMonto\tTasa\tPlazo\tRenovación\tRendimiento Neto\tTasa Neta\tTasa Real")
+					fmt.Fprintln(w, "------\t-----\t-----\t----\t-----\t----------\t----------------\t---------\t---------")
+					for _, p := range tarjetas.Pagares {
+						r := p.Rendimiento(inflacion)
+						renovacion := "No"
+						if p.Renovacion {
+							renovacion = "Automática"
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%.2f%%\t%d días\t%s\t$%.2f\t%.2f%%\t%.2f%%\n",
+							p.Nombre, p.Banco, p.Monto, p.Tasa*100, p.PlazoDias, renovacion, r.Neto, r.TasaNeta*100, r.TasaReal*100)
+					}
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:         "analizar",
+				Usage:        "Analizar el rendimiento real de un pagaré y el costo de retirarlo antes",
+				ArgsUsage:    "<nombre o número>",
+				BashComplete: completarTarjetas(nombresPagare),
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "retiro-dia", Usage: "Día del plazo en que retirarías el dinero; por defecto a la mitad"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					i, err := seleccionarTarjeta(c, nombresPagare(tarjetas), "pagare", "inversiones en pagarés")
+					if err != nil {
+						return err
+					}
+
+					p := tarjetas.Pagares[i]
+					dia, err := diaRetiroPagare(c, p)
+					if err != nil {
+						return err
+					}
+					inflacion := InflacionVigente()
+					r := p.Rendimiento(inflacion)
+					retiro := p.RetiroAnticipado(dia, inflacion)
+					if salidaEstructurada() {
+						return emitirDatos(FilaPagare{PagareBancario: p, RendimientoPagare: r, Inflacion: inflacion, Retiro: &retiro})
+					}
+
+					fmt.Println("\n=== Análisis de Pagaré Bancario ===")
+					fmt.Printf("Pagaré: %s (%s a %d días)\n", p.Nombre, p.Banco, p.PlazoDias)
+					fmt.Printf("Monto invertido: $%.2f\n", p.Monto)
+					fmt.Printf("Tasa anual bruta: %.2f%%\n", p.Tasa*100)
+					fmt.Printf("Interés bruto del plazo: $%.2f\n", r.Bruto)
+					fmt.Printf("ISR (%s): $%.2f\n", ISRVigente().Descripcion(), r.Retencion)
+					fmt.Printf("Interés neto: $%.2f\n", r.Neto)
+					if p.Renovacion {
+						fmt.Printf("Rendimiento neto anual renovando cada %d días: %.2f%%\n", p.PlazoDias, r.TasaNeta*100)
+					} else {
+						fmt.Printf("Rendimiento neto anualizado: %.2f%%\n", r.TasaNeta*100)
+					}
+					fmt.Printf("Inflación: %.2f%%\n", inflacion*100)
+					fmt.Printf("Monto al vencimiento: $%.2f\n", r.MontoFinal)
+					fmt.Printf("Cobertura IPAB: %d UDIS ($%.2f) por persona en %s\n", COBERTURA_IPAB_UDIS, CoberturaIPAB(), p.Banco)
+
+					fmt.Printf("\nSi retiras el día %d:\n", dia)
+					fmt.Printf("Penalización: %.0f%% del interés ganado\n", p.Penalizacion*100)
+					fmt.Printf("Interés neto: $%.2f (tasa real %.2f%%)\n", retiro.Neto, retiro.TasaReal*100)
+					fmt.Printf("Dejas de ganar $%.2f respecto al vencimiento\n", r.Neto-retiro.Neto)
+
+					if r.TasaReal > 0 {
+						fmt.Printf("RESULTADO: Tu inversión GANA valor real (%.2f%% anual sobre la inflación)\n", r.TasaReal*100)
+					} else {
+						fmt.Printf("RESULTADO: Tu inversión PIERDE valor real (%.2f%% anual contra la inflación)\n", r.TasaReal*100)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "comparar",
+				Usage: "Comparar tus pagarés contra CETES y tus tarjetas de débito a rendimiento neto real",
+				Description: "Sin CETES registrados se compara contra la tasa de CETES del catálogo. Con --retiro-dia\n" +
+					"los pagarés se evalúan retirando ese día, con su penalización; los CETES y las cuentas\n" +
+					"de débito se pueden vender o retirar en cualquier momento.",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "monto", Usage: "Monto a comparar; por defecto el de cada pagaré"}, limitesMonto),
+					&cli.IntFlag{Name: "retiro-dia", Usage: "Día en que necesitarías el dinero"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if len(tarjetas.Pagares) == 0 {
+						return fmt.Errorf("No hay pagarés registrados")
+					}
+					if c.IsSet("retiro-dia") && c.Int("retiro-dia") < 1 {
+						return errDatosInvalidos("--retiro-dia debe ser al menos 1", "--retiro-dia must be at least 1")
+					}
+					monto := c.Float64("monto")
+					if !c.IsSet("monto") {
+						monto = tarjetas.Pagares[0].Monto
+					}
+
+					type opcion struct {
+						Nombre    string  `json:"nombre"`
+						Tipo      string  `json:"tipo"`
+						Monto     float64 `json:"monto"`
+						TasaReal  float64 `json:"tasa_real"`
+						Real      float64 `json:"ganancia_real"` // Pesos al año por encima de la inflación
+						Protegido bool    `json:"protegido"`     // Todo el monto tiene cobertura del IPAB o del gobierno federal
+					}
+					inflacion := InflacionVigente()
+					var opciones []opcion
+					for _, p := range tarjetas.Pagares {
+						if c.IsSet("monto") {
+							p.Monto = monto
+						}
+						r := p.Rendimiento(inflacion)
+						tipo := fmt.Sprintf("pagaré %d días", p.PlazoDias)
+						if c.IsSet("retiro-dia") && c.Int("retiro-dia") < p.PlazoDias {
+							r = p.RetiroAnticipado(c.Int("retiro-dia"), inflacion)
+							tipo += fmt.Sprintf(", retiro día %d", c.Int("retiro-dia"))
+						}
+						opciones = append(opciones, opcion{p.Nombre, tipo, p.Monto, r.TasaReal, p.Monto * r.TasaReal, p.Monto <= CoberturaIPAB()})
+					}
+					if monto > 0 {
+						cetes := tarjetas.Cetes
+						if len(cetes) == 0 {
+							cetes = cetesReferencia()
+						}
+						for _, inv := range cetes {
+							inv.Monto = monto
+							r := inv.Rendimiento(inflacion)
+							opciones = append(opciones, opcion{inv.Nombre, inv.Instrumento, monto, r.TasaReal, monto * r.TasaReal, true})
+						}
+						for _, t := range tarjetas.Debito {
+							real, pct, _ := CalcularRendimientoReal(t, monto)
+							opciones = append(opciones, opcion{t.Nombre, "débito", monto, pct / 100, real, monto <= CoberturaIPAB()})
+						}
+					}
+					sort.SliceStable(opciones, func(i, j int) bool { return opciones[i].TasaReal > opciones[j].TasaReal })
+					if salidaEstructurada() {
+						return emitirDatos(opciones)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tTipo\tMonto\tTasa Real\tGanancia Real Anual\tProtegido")
+					fmt.Fprintln(w, "------\t----\t-----\t---------\t-------------------\t---------")
+					for _, o := range opciones {
+						protegido := "Sí"
+						if !o.Protegido {
+							protegido = "Parcial"
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%.2f%%\t$%.2f\t%s\n", o.Nombre, o.Tipo, o.Monto, o.TasaReal*100, o.Real, protegido)
+					}
+					w.Flush()
+
+					fmt.Printf("\nTasa real: rendimiento después de ISR y comisiones menos la inflación (%.2f%%)\n", inflacion*100)
+					if !c.IsSet("monto") && len(opciones) > len(tarjetas.Pagares) {
+						fmt.Printf("CETES y débito se evalúan con el monto de '%s'; usa --monto para comparar todo con el mismo monto\n", tarjetas.Pagares[0].Nombre)
+					}
+					fmt.Printf("RESULTADO: La mejor opción es %s (%s)\n", opciones[0].Nombre, opciones[0].Tipo)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// diaRetiroPagare regresa el día de --retiro-dia o, si no se indica, la mitad del plazo
+func diaRetiroPagare(c *cli.Context, p PagareBancario) (int, error) {
+	if !c.IsSet("retiro-dia") {
+		return (p.PlazoDias + 1) / 2, nil
+	}
+	dia := c.Int("retiro-dia")
+	if dia < 1 || dia >= p.PlazoDias {
+		return 0, errDatosInvalidos(
+			fmt.Sprintf("--retiro-dia debe estar entre 1 y %d, antes del vencimiento", p.PlazoDias-1),
+			fmt.Sprintf("--retiro-dia must be between 1 and %d, before maturity", p.PlazoDias-1))
+	}
+	return dia, nil
+}
+
+// cetesReferencia regresa los CETES del catálogo como inversiones a 28 días para comparar
+// cuando no hay CETES registrados
+func cetesReferencia() []InversionCetes {
+	catalogo, err := CargarCatalogo()
+	if err != nil {
+		return nil
+	}
+	var cetes []InversionCetes
+	for _, b := range catalogo.Benchmarks {
+		if b.Tipo == "gubernamental" && strings.Contains(strings.ToLower(b.Nombre), InstrumentoCetes) {
+			cetes = append(cetes, InversionCetes{Nombre: b.Nombre, Instrumento: InstrumentoCetes, Tasa: b.Tasa, PlazoDias: 28})
+		}
+	}
+	return cetes
+}
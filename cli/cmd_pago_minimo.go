@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"finmex/calc"
+
+	"github.com/urfave/cli/v2"
+)
+
+// ResumenPagoMinimo compara pagar solo el mínimo contra liquidar la deuda con pago fijo
+type ResumenPagoMinimo struct {
+	Tarjeta       string                    `json:"tarjeta"`
+	Deuda         float64                   `json:"deuda"`
+	Minimo        calc.ProyeccionPagoMinimo `json:"minimo"`
+	MesesFijo     int                       `json:"meses_fijo"`
+	PagoFijo      float64                   `json:"pago_fijo"`
+	InteresesFijo float64                   `json:"intereses_fijo"` // Con IVA
+}
+
+// comandoCreditoMinimo muestra lo que cuesta pagar solo el mínimo de una tarjeta de crédito
+func comandoCreditoMinimo() *cli.Command {
+	return &cli.Command{
+		Name:         "minimo",
+		Usage:        "Ver cuánto tiempo e intereses cuesta pagar solo el mínimo",
+		ArgsUsage:    "<nombre o número>",
+		BashComplete: completarTarjetas(nombresCredito),
+		Description: "El pago mínimo sigue la regla de Banxico y la CNBV: el mayor entre el 1.5% del saldo\n" +
+			"más los intereses del mes con IVA y el 1.25% del límite de crédito. Se supone que no hay\n" +
+			"compras nuevas, como en el recuadro de advertencia de los estados de cuenta, y se compara\n" +
+			"con el pago fijo que liquida la deuda en --meses.\n" +
+			"Ejemplo: finmex credito minimo --deuda 20000 Oro",
+		Flags: []cli.Flag{
+			conLimites(&cli.Float64Flag{Name: "deuda", Usage: "Deuda revolvente, por defecto el saldo de la tarjeta"}, limitesMonto),
+			&cli.IntFlag{Name: "meses", Value: 12, Usage: "Plazo del pago fijo con que se compara"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, err := seleccionarTarjeta(c, nombresCredito(tarjetas), "credito", "tarjetas de crédito")
+			if err != nil {
+				return err
+			}
+			tarjeta := tarjetas.Credito[i]
+
+			meses := c.Int("meses")
+			if meses < 1 || meses > 120 {
+				return errDatosInvalidos(fmt.Sprintf("--meses debe estar entre 1 y 120, no %d", meses), fmt.Sprintf("--meses must be between 1 and 120, not %d", meses))
+			}
+			deuda := tarjeta.Saldo
+			if c.IsSet("deuda") {
+				deuda = c.Float64("deuda")
+			}
+			if deuda <= 0 {
+				return fmt.Errorf("%s no tiene deuda registrada; indica el monto con --deuda", tarjeta.Nombre)
+			}
+
+			r := ResumenPagoMinimo{Tarjeta: tarjeta.Nombre, Deuda: deuda, Minimo: calc.ProyectarPagoMinimo(tarjeta, deuda), MesesFijo: meses}
+			r.PagoFijo = calc.PagoFijo(deuda, calc.TasaConIVA(tarjeta.TasaInteres)/12, meses)
+			r.InteresesFijo = r.PagoFijo*float64(meses) - deuda
+			if salidaEstructurada() {
+				return emitirDatos(r)
+			}
+
+			fmt.Printf("=== Pago mínimo: %s ===\n", tarjeta.Nombre)
+			fmt.Printf("Deuda: $%.2f al %.2f%% anual más IVA, límite de crédito $%.2f\n", deuda, tarjeta.TasaInteres*100, tarjeta.LimiteCredito)
+			fmt.Printf("Pago mínimo este mes: $%.2f\n\n", r.Minimo.PagoInicial)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Forma de pago\tPago mensual\tMeses\tIntereses con IVA\tTotal pagado")
+			fmt.Fprintln(w, "-------------\t------------\t-----\t-----------------\t------------")
+			fmt.Fprintf(w, "Solo el mínimo\t$%.2f al inicio\t%d\t$%.2f\t$%.2f\n", r.Minimo.PagoInicial, r.Minimo.Meses, r.Minimo.Intereses, r.Minimo.TotalPagado)
+			fmt.Fprintf(w, "Pago fijo\t$%.2f\t%d\t$%.2f\t$%.2f\n", r.PagoFijo, meses, r.InteresesFijo, r.PagoFijo*float64(meses))
+			w.Flush()
+
+			if !r.Minimo.Liquidada {
+				fmt.Printf("\nALERTA: Pagando solo el mínimo la deuda no se liquida en %d meses; ya habrías pagado $%.2f de intereses\n",
+					r.Minimo.Meses, r.Minimo.Intereses)
+				return nil
+			}
+			fmt.Printf("\nRESULTADO: Pagando solo el mínimo tardarías %d meses (%.1f años) en liquidar la deuda y pagarías $%.2f de intereses\n",
+				r.Minimo.Meses, float64(r.Minimo.Meses)/12, r.Minimo.Intereses)
+			if ahorro := r.Minimo.Intereses - r.InteresesFijo; ahorro > 0 {
+				fmt.Printf("Pagando $%.2f al mes la liquidas en %d meses y te ahorras $%.2f\n", r.PagoFijo, meses, ahorro)
+			}
+			return nil
+		},
+	}
+}
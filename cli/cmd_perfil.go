@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"finmex/storage"
+	"github.com/urfave/cli/v2"
+)
+
+// FilaPerfil es un perfil en la salida de perfil listar con --output
+type FilaPerfil struct {
+	Nombre         string `json:"nombre"`
+	Directorio     string `json:"directorio"`
+	Activo         bool   `json:"activo"`
+	Predeterminado bool   `json:"predeterminado"`
+}
+
+// comandoPerfil administra los perfiles que separan los datos de cada persona
+func comandoPerfil() *cli.Command {
+	return &cli.Command{
+		Name:  "perfil",
+		Usage: "Administrar perfiles con datos separados para cada persona",
+		Description: "Cada perfil guarda sus tarjetas, movimientos y base de datos en perfiles/<nombre>.\n" +
+			"El perfil 'principal' usa los archivos del directorio de datos: el actual, salvo que\n" +
+			"'finmex config set datos' elija otro. Elige el perfil con --perfil o FINMEX_PERFIL; sin\n" +
+			"ellos se usa el predeterminado de 'finmex perfil usar' o el perfil de config.yaml.",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "listar",
+				Usage: "Listar los perfiles",
+				Action: func(c *cli.Context) error {
+					perfiles, err := Perfiles()
+					if err != nil {
+						return fmt.Errorf("Error al leer los perfiles: %w", err)
+					}
+					predeterminado := PerfilPredeterminado()
+					filas := make([]FilaPerfil, len(perfiles))
+					for i, nombre := range perfiles {
+						directorio := rutaComun(".")
+						if nombre != PERFIL_PRINCIPAL {
+							directorio = directorioPerfil(nombre)
+						}
+						filas[i] = FilaPerfil{nombre, directorio, nombre == perfilActivo, nombre == predeterminado}
+					}
+
+					if salidaEstructurada() {
+						return emitirDatos(filas)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Perfil\tDirectorio\tActivo\tPredeterminado")
+					fmt.Fprintln(w, "------\t----------\t------\t--------------")
+					for _, f := range filas {
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Nombre, f.Directorio, marcaPerfil(f.Activo), marcaPerfil(f.Predeterminado))
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:      "crear",
+				Usage:     "Crear un perfil vacío",
+				ArgsUsage: "<nombre>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "usar", Usage: "Dejar el perfil nuevo como predeterminado"},
+				},
+				Action: func(c *cli.Context) error {
+					nombre, err := argumentoPerfil(c)
+					if err != nil {
+						return err
+					}
+					if nombre == PERFIL_PRINCIPAL || existePerfil(nombre) {
+						return fmt.Errorf("El perfil '%s' ya existe", nombre)
+					}
+					if err := os.MkdirAll(directorioPerfil(nombre), 0755); err != nil {
+						return fmt.Errorf("Error al crear el perfil: %w", err)
+					}
+					fmt.Printf("Perfil '%s' creado en %s\n", nombre, directorioPerfil(nombre))
+					if c.Bool("usar") {
+						if err := GuardarPerfilPredeterminado(nombre); err != nil {
+							return fmt.Errorf("Error al guardar el perfil predeterminado: %w", err)
+						}
+						fmt.Printf("'%s' es ahora el perfil predeterminado\n", nombre)
+					} else {
+						fmt.Printf("Úsalo con 'finmex --perfil %s ...' o 'finmex perfil usar %s'\n", nombre, nombre)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "usar",
+				Usage:     "Elegir el perfil predeterminado",
+				ArgsUsage: "<nombre>",
+				Action: func(c *cli.Context) error {
+					nombre, err := argumentoPerfil(c)
+					if err != nil {
+						return err
+					}
+					if nombre != PERFIL_PRINCIPAL && !existePerfil(nombre) {
+						return fmt.Errorf("El perfil '%s' no existe; créalo con 'finmex perfil crear %s'", nombre, nombre)
+					}
+					if err := GuardarPerfilPredeterminado(nombre); err != nil {
+						return fmt.Errorf("Error al guardar el perfil predeterminado: %w", err)
+					}
+					fmt.Printf("'%s' es ahora el perfil predeterminado\n", nombre)
+					return nil
+				},
+			},
+			{
+				Name:      "eliminar",
+				Usage:     "Eliminar un perfil con todos sus datos",
+				ArgsUsage: "<nombre>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "forzar", Usage: "No pedir confirmación"},
+				},
+				Action: func(c *cli.Context) error {
+					nombre, err := argumentoPerfil(c)
+					if err != nil {
+						return err
+					}
+					if nombre == PERFIL_PRINCIPAL {
+						return fmt.Errorf("El perfil '%s' no se puede eliminar", PERFIL_PRINCIPAL)
+					}
+					if !existePerfil(nombre) {
+						return fmt.Errorf("El perfil '%s' no existe", nombre)
+					}
+					if storage.Existe(filepath.Join(directorioPerfil(nombre), ARCHIVO_SOCKET)) {
+						return fmt.Errorf("El perfil '%s' tiene un daemon activo; detenlo antes de eliminarlo", nombre)
+					}
+					ok, err := confirmarEliminacion(c, fmt.Sprintf("el perfil '%s' con todas sus tarjetas y movimientos", nombre))
+					if err != nil || !ok {
+						return err
+					}
+					if nombre == PerfilPredeterminado() {
+						if err := GuardarPerfilPredeterminado(PERFIL_PRINCIPAL); err != nil {
+							return fmt.Errorf("Error al restablecer el perfil predeterminado: %w", err)
+						}
+					}
+					if nombre == configuracion.Perfil {
+						configuracion.Perfil = ""
+						if err := GuardarConfiguracion(configuracion); err != nil {
+							return fmt.Errorf("Error al quitar el perfil de la configuración: %w", err)
+						}
+					}
+					if err := os.RemoveAll(directorioPerfil(nombre)); err != nil {
+						return fmt.Errorf("Error al eliminar el perfil: %w", err)
+					}
+					fmt.Printf("Perfil '%s' eliminado\n", nombre)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// argumentoPerfil toma y valida el nombre de perfil del argumento del comando
+func argumentoPerfil(c *cli.Context) (string, error) {
+	if c.NArg() != 1 {
+		return "", fmt.Errorf("Uso: finmex perfil %s <nombre>", c.Command.Name)
+	}
+	nombre := c.Args().First()
+	if nombre == PERFIL_PRINCIPAL {
+		return nombre, nil
+	}
+	return nombre, ValidarNombrePerfil(nombre)
+}
+
+func marcaPerfil(si bool) string {
+	if si {
+		return "*"
+	}
+	return ""
+}
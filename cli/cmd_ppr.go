@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoPPR agrupa las operaciones con planes personales de retiro
+func comandoPPR() *cli.Command {
+	return &cli.Command{
+		Name:  "ppr",
+		Usage: "Planes personales de retiro con beneficio fiscal",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar un plan personal de retiro",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var p PlanRetiro
+
+					if p.Nombre, err = leerTextoRequerido("Nombre del plan: "); err != nil {
+						return err
+					}
+					if p.Institucion, err = leerTextoRequerido("Institución: "); err != nil {
+						return err
+					}
+					if p.Saldo, err = leerNumero("Saldo actual: ", limitesMonto); err != nil {
+						return err
+					}
+					if p.AportacionAnual, err = leerNumero("Aportación anual: ", limitesMonto); err != nil {
+						return err
+					}
+					if p.TasaRendimiento, err = leerNumero("Rendimiento anual estimado (decimal): ", limitesTasa); err != nil {
+						return err
+					}
+					if p.Comision, err = leerNumero("Comisión anual sobre saldo (decimal): ", limitesFraccion); err != nil {
+						return err
+					}
+
+					tarjetas.PPR = append(tarjetas.PPR, p)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar plan: %w", err)
+					}
+
+					fmt.Printf("Plan '%s' agregado exitosamente\n", p.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar planes personales de retiro",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if salidaEstructurada() {
+						filas := []FilaPPR{}
+						for _, p := range tarjetas.PPR {
+							filas = append(filas, FilaPPR{p, p.TasaRendimiento - p.Comision})
+						}
+						return emitirDatos(filas)
+					}
+
+					if len(tarjetas.PPR) == 0 {
+						fmt.Println("No hay planes personales de retiro registrados")
+						return nil
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tInstitución\tSaldo\tAportación Anual\tRendimiento\tComisión\tRendimiento Neto")
+					fmt.Fprintln(w, "------\t-----------\t-----\t---------------\t-----------\t--------\t----------------")
+
+					for _, p := range tarjetas.PPR {
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t%.2f%%\t%.2f%%\t%.2f%%\n",
+							p.Nombre, p.Institucion, p.Saldo, p.AportacionAnual,
+							p.TasaRendimiento*100, p.Comision*100, (p.TasaRendimiento-p.Comision)*100)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:  "proyectar",
+				Usage: "Proyectar un plan al retiro incluyendo la devolución de ISR",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "ingreso", Usage: "Ingreso anual acumulable"}, limitesMonto),
+					&cli.IntFlag{Name: "anios", Value: 20, Usage: "Años que faltan para el retiro"},
+					&cli.BoolFlag{Name: "reinvertir", Usage: "Aportar al plan la devolución de ISR de cada año"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.PPR) == 0 {
+						return fmt.Errorf("No hay planes personales de retiro registrados")
+					}
+
+					var nombres []string
+					for _, p := range tarjetas.PPR {
+						nombres = append(nombres, fmt.Sprintf("%s (%s)", p.Nombre, p.Institucion))
+					}
+					seleccion, err := elegirOpcion("Planes disponibles:", nombres)
+					if err != nil {
+						return err
+					}
+					p := tarjetas.PPR[seleccion]
+
+					ingreso, err := ingresoAnualPPR(c)
+					if err != nil {
+						return err
+					}
+
+					año := time.Now().Year()
+					proyeccion, err := ProyectarPPR(p, ingreso, c.Int("anios"), año, c.Bool("reinvertir"))
+					if err != nil {
+						return err
+					}
+					tope, err := TopeDeduccionPPR(ingreso, año)
+					if err != nil {
+						return err
+					}
+
+					fmt.Println("\n=== Proyección de Retiro ===")
+					fmt.Printf("Plan: %s | Tope deducible: $%.2f al año\n\n", p.Nombre, tope)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Año\tAportación\tDeducible\tDevolución ISR\tSaldo")
+					fmt.Fprintln(w, "---\t----------\t---------\t--------------\t-----")
+
+					aportado, devuelto := p.Saldo, 0.0
+					for _, r := range proyeccion {
+						fmt.Fprintf(w, "%d\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n", r.Año, r.Aportacion, r.Deducible, r.Devolucion, r.Saldo)
+						aportado += r.Aportacion
+						devuelto += r.Devolucion
+					}
+					w.Flush()
+
+					final := proyeccion[len(proyeccion)-1].Saldo
+					fmt.Printf("\nSaldo al retiro: $%.2f (saldo inicial más aportaciones: $%.2f)\n", final, aportado)
+					fmt.Printf("Devoluciones de ISR en el periodo: $%.2f\n", devuelto)
+					if !c.Bool("reinvertir") {
+						fmt.Println("Usa --reinvertir para aportar cada devolución al plan")
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "declaracion",
+				Usage: "Estimar la devolución de ISR por las aportaciones del año",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "ingreso", Usage: "Ingreso anual acumulable"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					ingreso, err := ingresoAnualPPR(c)
+					if err != nil {
+						return err
+					}
+
+					aportaciones := 0.0
+					for _, p := range tarjetas.PPR {
+						aportaciones += p.AportacionAnual
+					}
+
+					tope, err := TopeDeduccionPPR(ingreso, time.Now().Year())
+					if err != nil {
+						return err
+					}
+					deducible := math.Min(aportaciones, tope)
+
+					fmt.Println("\n=== Estimación de Declaración Anual ===")
+					fmt.Printf("Ingreso acumulable: $%.2f\n", ingreso)
+					fmt.Printf("Aportaciones a PPR: $%.2f (deducibles: $%.2f, tope $%.2f)\n", aportaciones, deducible, tope)
+					fmt.Printf("ISR sin deducir: $%.2f\n", calc.ISRAnual(ingreso))
+					fmt.Printf("ISR deduciendo el PPR: $%.2f\n", calc.ISRAnual(ingreso-deducible))
+					fmt.Printf("\nRESULTADO: Devolución estimada de ISR: $%.2f\n", DevolucionISR(ingreso, deducible))
+					if aportaciones < tope {
+						fmt.Printf("Podrías aportar $%.2f más este año y seguir deduciendo\n", tope-aportaciones)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// ingresoAnualPPR toma el ingreso anual de la bandera --ingreso o lo pide
+func ingresoAnualPPR(c *cli.Context) (float64, error) {
+	if c.IsSet("ingreso") {
+		ingreso := c.Float64("ingreso")
+		return ingreso, validarLimites(ingreso, limitesMonto)
+	}
+	return leerNumero("Ingreso anual acumulable: ", limitesMonto)
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoPrestamo agrupa los análisis para decidir dónde pedir prestado
+func comandoPrestamo() *cli.Command {
+	return &cli.Command{
+		Name:  "prestamo",
+		Usage: "Análisis de préstamos",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "donde",
+				Usage: "Comparar dónde conviene pedir prestado un monto",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var monto float64
+					if monto, err = leerNumero("Monto que necesitas: ", limitesMonto); err != nil {
+						return err
+					}
+					meses, err := leerEntero("Plazo en meses: ", 1, 360)
+					if err != nil {
+						return err
+					}
+
+					opciones := OpcionesPrestamo(tarjetas, monto, meses)
+					if len(opciones) == 0 {
+						return fmt.Errorf("No hay tarjetas de crédito, cajas de ahorro ni microcréditos registrados")
+					}
+
+					fmt.Println("\n=== ¿Dónde pedir prestado? ===")
+					fmt.Printf("Monto: $%.2f a %d meses\n\n", monto, meses)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Producto\tNombre\tTasa Anual\tPago Mensual\tCosto Total\tNota")
+					fmt.Fprintln(w, "--------\t------\t----------\t------------\t-----------\t----")
+
+					for _, o := range opciones {
+						fmt.Fprintf(w, "%s\t%s\t%.2f%%\t$%.2f\t$%.2f\t%s\n",
+							o.Producto, o.Nombre, o.TasaAnual*100, o.Pago, o.CostoTotal, o.Nota)
+					}
+
+					w.Flush()
+
+					if mejor := opciones[0]; mejor.Disponible {
+						fmt.Printf("\nRESULTADO: La opción más barata es %s (%s), con un costo de $%.2f\n", mejor.Nombre, mejor.Producto, mejor.CostoTotal)
+					} else {
+						fmt.Println("\nRESULTADO: Ninguna opción registrada cubre el monto completo")
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
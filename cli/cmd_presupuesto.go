@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoPresupuesto define el presupuesto mensual y compara los gastos contra él
+func comandoPresupuesto() *cli.Command {
+	return &cli.Command{
+		Name:  "presupuesto",
+		Usage: "Presupuesto mensual con ingresos, límites por categoría y registro de gastos",
+		Description: "Los gastos son los movimientos del historial con la categoría correspondiente, así que\n" +
+			"también cuentan los registrados con 'movimientos agregar' o importados de un estado de cuenta.",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "ingreso",
+				Usage:     "Agregar o cambiar un ingreso mensual; con monto 0 se quita",
+				ArgsUsage: "<nombre> <monto>",
+				Action: func(c *cli.Context) error {
+					nombre, monto, err := argumentosNombreMonto(c)
+					if err != nil {
+						return err
+					}
+					return modificarPresupuesto(func(p *Presupuesto) string {
+						existia := p.FijarIngreso(nombre, monto)
+						return mensajeFijado("el ingreso", nombre, monto, existia)
+					})
+				},
+			},
+			{
+				Name:      "categoria",
+				Usage:     "Agregar o cambiar el límite mensual de una categoría; con límite 0 se quita",
+				ArgsUsage: "<categoria> <limite>",
+				Action: func(c *cli.Context) error {
+					nombre, limite, err := argumentosNombreMonto(c)
+					if err != nil {
+						return err
+					}
+					return modificarPresupuesto(func(p *Presupuesto) string {
+						existia := p.FijarCategoria(nombre, limite)
+						return mensajeFijado("la categoría", nombre, limite, existia)
+					})
+				},
+			},
+			{
+				Name:      "gasto",
+				Usage:     "Registrar un gasto rápido en una categoría",
+				ArgsUsage: "<monto> <categoria> [descripcion...]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "fecha", Usage: "Fecha del gasto (AAAA-MM-DD), por defecto hoy"},
+					&cli.StringFlag{Name: "tarjeta", Usage: "Tarjeta con que se pagó"},
+				},
+				Action: func(c *cli.Context) error {
+					if c.NArg() < 2 {
+						return fmt.Errorf("Uso: finmex presupuesto gasto <monto> <categoria> [descripcion...]")
+					}
+					monto, err := ParsearNumero(c.Args().Get(0))
+					if err == nil {
+						err = validarLimites(monto, limitesMonto)
+					}
+					if err != nil {
+						return err
+					}
+					fecha := c.String("fecha")
+					if fecha == "" {
+						fecha = time.Now().Format("2006-01-02")
+					}
+					if _, err := time.Parse("2006-01-02", fecha); err != nil {
+						return fmt.Errorf("Fecha inválida '%s', usa el formato AAAA-MM-DD", fecha)
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if tarjetas.Presupuesto == nil || len(tarjetas.Presupuesto.Categorias) == 0 {
+						return fmt.Errorf("No hay categorías en el presupuesto; agrégalas con 'finmex presupuesto categoria <categoria> <limite>'")
+					}
+					categoria := c.Args().Get(1)
+					if nombre := categoriaPresupuesto(*tarjetas.Presupuesto, categoria); nombre != CATEGORIA_SIN_PRESUPUESTO {
+						categoria = nombre
+					} else {
+						fmt.Printf("AVISO: '%s' no tiene límite en el presupuesto; el gasto cuenta como %s\n", categoria, CATEGORIA_SIN_PRESUPUESTO)
+					}
+
+					descripcion := strings.Join(c.Args().Slice()[2:], " ")
+					if descripcion == "" {
+						descripcion = categoria
+					}
+					m := Movimiento{Fecha: fecha, Descripcion: descripcion, Monto: monto, Categoria: categoria, Tarjeta: c.String("tarjeta")}
+					if err := AgregarMovimientos([]Movimiento{m}); err != nil {
+						return fmt.Errorf("Error al guardar movimiento: %w", err)
+					}
+					fmt.Printf("Gasto de $%.2f en %s registrado\n", monto, categoria)
+
+					mes := fecha[:7]
+					movimientos, err := MovimientosDelMes(mes)
+					if err != nil {
+						return err
+					}
+					avance := CalcularAvancePresupuesto(*tarjetas.Presupuesto, mes, movimientos)
+					for _, a := range avance.Categorias {
+						if a.Categoria == categoria && a.Avance != nil {
+							fmt.Printf("%s: $%.2f de $%.2f (%.0f%%)\n", a.Categoria, a.Gastado, a.Limite, *a.Avance*100)
+						}
+					}
+					avisarPresupuesto(avance)
+					return nil
+				},
+			},
+			{
+				Name:  "avance",
+				Usage: "Ver lo gastado en el mes contra el presupuesto",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "mes", Usage: "Mes a revisar (AAAA-MM), por defecto el actual"},
+				},
+				Action: func(c *cli.Context) error {
+					mes := c.String("mes")
+					if mes == "" {
+						mes = time.Now().Format("2006-01")
+					}
+					if _, err := time.Parse("2006-01", mes); err != nil {
+						return fmt.Errorf("Mes inválido '%s', usa el formato AAAA-MM", mes)
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if tarjetas.Presupuesto == nil {
+						return fmt.Errorf("No hay presupuesto definido; empieza con 'finmex presupuesto ingreso' y 'finmex presupuesto categoria'")
+					}
+					movimientos, err := MovimientosDelMes(mes)
+					if err != nil {
+						return err
+					}
+					avance := CalcularAvancePresupuesto(*tarjetas.Presupuesto, mes, movimientos)
+
+					if salidaEstructurada() {
+						return emitirDatos(avance)
+					}
+
+					fmt.Printf("=== Presupuesto de %s ===\n", mes)
+					fmt.Printf("Ingreso mensual: $%.2f\n", avance.Ingreso)
+					fmt.Printf("Límite de gasto: $%.2f", avance.Limite)
+					if avance.Limite > avance.Ingreso && avance.Ingreso > 0 {
+						fmt.Printf(" (AVISO: supera el ingreso por $%.2f)", avance.Limite-avance.Ingreso)
+					}
+					fmt.Println()
+
+					if len(avance.Categorias) > 0 {
+						fmt.Println()
+						w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+						fmt.Fprintln(w, "Categoría\tLímite\tGastado\tDisponible\tAvance")
+						fmt.Fprintln(w, "---------\t------\t-------\t----------\t------")
+						for _, a := range avance.Categorias {
+							limite, disponible, porcentaje := "-", "-", "-"
+							if a.Avance != nil {
+								limite = fmt.Sprintf("$%.2f", a.Limite)
+								disponible = fmt.Sprintf("$%.2f", a.Disponible)
+								porcentaje = fmt.Sprintf("%.0f%%", *a.Avance*100)
+							}
+							fmt.Fprintf(w, "%s\t%s\t$%.2f\t%s\t%s\n", a.Categoria, limite, a.Gastado, disponible, porcentaje)
+						}
+						if err := w.Flush(); err != nil {
+							return err
+						}
+						fmt.Println()
+					}
+
+					fmt.Printf("Gastado en el mes: $%.2f\n", avance.Gastado)
+					avisarPresupuesto(avance)
+					if avance.Sobrante >= 0 {
+						fmt.Printf("RESULTADO: Te quedan $%.2f del ingreso del mes\n", avance.Sobrante)
+					} else {
+						fmt.Printf("RESULTADO: Gastaste $%.2f más que tu ingreso del mes\n", -avance.Sobrante)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// modificarPresupuesto carga las tarjetas, aplica el cambio al presupuesto y las guarda
+func modificarPresupuesto(cambio func(*Presupuesto) string) error {
+	tarjetas, err := CargarTarjetas()
+	if err != nil {
+		return fmt.Errorf("Error al cargar tarjetas: %w", err)
+	}
+	if tarjetas.Presupuesto == nil {
+		tarjetas.Presupuesto = &Presupuesto{}
+	}
+	mensaje := cambio(tarjetas.Presupuesto)
+	if len(tarjetas.Presupuesto.Ingresos) == 0 && len(tarjetas.Presupuesto.Categorias) == 0 {
+		tarjetas.Presupuesto = nil
+	}
+	if err := GuardarTarjetas(tarjetas); err != nil {
+		return fmt.Errorf("Error al guardar el presupuesto: %w", err)
+	}
+	fmt.Println(mensaje)
+	return nil
+}
+
+// mensajeFijado describe el cambio; el tipo lleva su artículo ("el ingreso", "la categoría")
+func mensajeFijado(tipo, nombre string, monto float64, existia bool) string {
+	switch {
+	case monto == 0 && existia:
+		return fmt.Sprintf("Se quitó %s '%s' del presupuesto", tipo, nombre)
+	case monto == 0:
+		return fmt.Sprintf("No se encontró %s '%s' en el presupuesto", tipo, nombre)
+	case existia:
+		return fmt.Sprintf("Se cambió %s '%s' a $%.2f", tipo, nombre, monto)
+	}
+	return fmt.Sprintf("Se agregó %s '%s' con $%.2f", tipo, nombre, monto)
+}
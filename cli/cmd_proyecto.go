@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoProyecto agrupa la evaluación de proyectos de inversión
+func comandoProyecto() *cli.Command {
+	return &cli.Command{
+		Name:  "proyecto",
+		Usage: "Evaluación de proyectos de inversión",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "evaluar",
+				Usage: "Calcular VPN, TIR y payback descontado de un proyecto",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "flujos", Usage: "Flujos separados por espacio o punto y coma, empezando por la inversión (ej. \"-100k 30k 40k 50k\")"},
+					&cli.StringFlag{Name: "terminal", Usage: "Valor terminal que se suma al último flujo"},
+					&cli.StringFlag{Name: "periodo", Value: "anual", Usage: "Periodo de los flujos: mensual, bimestral, trimestral, semestral o anual"},
+					&cli.StringFlag{Name: "tasa", Usage: "Tasa de oportunidad anual (por omisión tu mejor cuenta de débito)"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var p Proyecto
+					if p.PeriodosAño, err = ParsearPeriodoProyecto(c.String("periodo")); err != nil {
+						return err
+					}
+
+					if c.IsSet("flujos") {
+						for _, texto := range strings.Fields(strings.ReplaceAll(c.String("flujos"), ";", " ")) {
+							flujo, err := ParsearNumero(texto)
+							if err != nil {
+								return err
+							}
+							p.Flujos = append(p.Flujos, flujo)
+						}
+						if c.IsSet("terminal") {
+							if p.ValorTerminal, err = ParsearNumero(c.String("terminal")); err != nil {
+								return err
+							}
+						}
+					} else if p, err = leerProyecto(p.PeriodosAño, c.String("periodo")); err != nil {
+						return err
+					}
+
+					tasa, fuente := MejorTasaDebitoNeta(tarjetas)
+					if c.IsSet("tasa") {
+						if tasa, err = ParsearNumero(c.String("tasa")); err != nil {
+							return err
+						}
+						fuente = "capturada"
+					} else if fuente == "" {
+						if tasa, err = leerNumero("Tasa de oportunidad anual, ej. CETES (decimal): ", limitesTasa); err != nil {
+							return err
+						}
+						fuente = "capturada"
+					}
+
+					e, err := EvaluarProyecto(p, tasa)
+					if err != nil {
+						return err
+					}
+
+					fmt.Println("\n=== Evaluación del Proyecto ===")
+					fmt.Printf("Tasa de oportunidad: %.2f%% anual (%s), %.4f%% por periodo %s\n\n", tasa*100, fuente, e.TasaPeriodo*100, c.String("periodo"))
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Periodo\tFlujo\tFlujo Descontado\tAcumulado")
+					fmt.Fprintln(w, "-------\t-----\t----------------\t---------")
+					acumulado := 0.0
+					for i, flujo := range p.flujosTotales() {
+						descontado := calc.VPN(e.TasaPeriodo, append(make([]float64, i), flujo))
+						acumulado += descontado
+						fmt.Fprintf(w, "%d\t$%.2f\t$%.2f\t$%.2f\n", i, flujo, descontado, acumulado)
+					}
+					w.Flush()
+
+					fmt.Printf("\nVPN: $%.2f\n", e.VPN)
+					if e.TIRValida {
+						fmt.Printf("TIR: %.2f%% por periodo (%.2f%% anual efectiva)\n", e.TIRPeriodo*100, e.TIRAnual*100)
+					} else {
+						fmt.Println("TIR: no existe para estos flujos")
+					}
+					if e.Recupera {
+						fmt.Printf("Payback descontado: %.2f periodos\n", e.Payback)
+					} else {
+						fmt.Println("Payback descontado: la inversión no se recupera en el horizonte")
+					}
+
+					if e.VPN > 0 {
+						fmt.Printf("\nRESULTADO: El proyecto CONVIENE: genera $%.2f más que tu tasa de oportunidad\n", e.VPN)
+					} else {
+						fmt.Printf("\nRESULTADO: El proyecto NO CONVIENE: pierde $%.2f contra tu tasa de oportunidad\n", -e.VPN)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// leerProyecto pide de forma interactiva la inversión inicial, los flujos y el valor terminal
+func leerProyecto(periodosAño int, periodo string) (Proyecto, error) {
+	p := Proyecto{PeriodosAño: periodosAño}
+
+	inversion, err := leerNumero("Inversión inicial: ", limitesMonto)
+	if err != nil {
+		return p, err
+	}
+	p.Flujos = append(p.Flujos, -inversion)
+
+	n, err := leerEntero(fmt.Sprintf("Número de periodos (%s): ", periodo), 1, 600)
+	if err != nil {
+		return p, err
+	}
+	for i := 1; i <= n; i++ {
+		flujo, err := leerNumero(fmt.Sprintf("Flujo del periodo %d (negativo si es salida): ", i), LimitesNumero{Min: -limitesMonto.Max, Max: limitesMonto.Max})
+		if err != nil {
+			return p, err
+		}
+		p.Flujos = append(p.Flujos, flujo)
+	}
+
+	if p.ValorTerminal, err = leerNumero("Valor terminal al final (0 si no hay): ", limitesMonto); err != nil {
+		return p, err
+	}
+	return p, nil
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoRecomendar agrupa los asistentes de recomendación de productos
+func comandoRecomendar() *cli.Command {
+	return &cli.Command{
+		Name:  "recomendar",
+		Usage: "Asistentes para elegir productos financieros",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "perfil",
+				Usage: "Cuestionario para saber qué tarjeta de crédito te conviene",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "top", Value: 5, Usage: "Número de tarjetas a mostrar"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					catalogo, err := CargarCatalogo()
+					if err != nil {
+						return err
+					}
+
+					perfil, err := leerPerfilGasto()
+					if err != nil {
+						return err
+					}
+
+					candidatas := candidatasRecomendacion(tarjetas, catalogo)
+					individuales, par := RecomendarTarjetas(candidatas, perfil)
+
+					fmt.Println("\n=== Tarjetas que más te convienen ===")
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Tarjeta\tOrigen\tCashback\tViajes\tAnualidad\tIntereses\tComisiones\tValor Neto Anual")
+					fmt.Fprintln(w, "-------\t------\t--------\t------\t---------\t---------\t----------\t----------------")
+
+					for i, p := range individuales {
+						if i >= c.Int("top") {
+							break
+						}
+						origen := "Catálogo"
+						if p.Tarjetas[0].Segmento == segmentoRegistrada {
+							origen = "Tuya"
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+							p.Nombres(), origen, p.Cashback, p.Viajes, p.Anualidades, p.Intereses, p.Comisiones, p.Valor())
+					}
+					w.Flush()
+
+					if len(individuales) == 0 {
+						return fmt.Errorf("No hay tarjetas para evaluar")
+					}
+					mejor := individuales[0]
+					fmt.Printf("\nRESULTADO: La mejor tarjeta para tu perfil es %s, con un valor neto de $%.2f al año\n", mejor.Nombres(), mejor.Valor())
+					if len(par.Tarjetas) == 2 && par.Valor() > mejor.Valor() {
+						fmt.Printf("Combinando %s ganarías $%.2f al año ($%.2f más)\n", par.Nombres(), par.Valor(), par.Valor()-mejor.Valor())
+					}
+					if perfil.SaldoRevolvente > 0 {
+						fmt.Println("Como mantienes deuda, la tasa de interés pesa más que cualquier beneficio: prioriza liquidarla.")
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// segmentoRegistrada distingue las tarjetas del usuario dentro de las candidatas
+const segmentoRegistrada = "registrada"
+
+// candidatasRecomendacion combina las tarjetas registradas con las del catálogo, sin repetir
+// las que el usuario ya tiene
+func candidatasRecomendacion(tarjetas Tarjetas, catalogo Catalogo) []CreditoCatalogo {
+	var candidatas []CreditoCatalogo
+	registradas := make(map[string]bool)
+	for _, t := range tarjetas.Credito {
+		candidatas = append(candidatas, CreditoCatalogo{TarjetaCredito: t, Segmento: segmentoRegistrada})
+		registradas[normalizarClave(t.Nombre+" "+t.Banco)] = true
+	}
+	for _, t := range catalogo.Credito {
+		if !registradas[normalizarClave(t.Nombre+" "+t.Banco)] {
+			candidatas = append(candidatas, t)
+		}
+	}
+	return candidatas
+}
+
+// leerPerfilGasto hace el cuestionario de gasto, pago y viajes
+func leerPerfilGasto() (PerfilGasto, error) {
+	perfil := PerfilGasto{Mensual: make(map[string]float64)}
+
+	fmt.Println("Gasto mensual con tarjeta por categoría:")
+	for _, categoria := range CategoriasGasto {
+		gasto, err := leerNumero(fmt.Sprintf("  %s: ", strings.ReplaceAll(categoria, "_", " ")), limitesMonto)
+		if err != nil {
+			return perfil, err
+		}
+		perfil.Mensual[categoria] = gasto
+	}
+
+	pagaTotal, err := leerSiNo("¿Pagas el total de tu tarjeta cada mes? (s/n): ")
+	if err != nil {
+		return perfil, err
+	}
+	if !pagaTotal {
+		if perfil.SaldoRevolvente, err = leerNumero("Deuda promedio que arrastras de un mes a otro: ", limitesMonto); err != nil {
+			return perfil, err
+		}
+	}
+
+	viaja, err := leerSiNo("¿Viajas en avión? (s/n): ")
+	if err != nil {
+		return perfil, err
+	}
+	if viaja {
+		if perfil.ViajesAño, err = leerEntero("Viajes al año: ", 0, 100); err != nil {
+			return perfil, err
+		}
+		if perfil.GastoExtranjeroAnual, err = leerNumero("Gasto anual en el extranjero o en moneda extranjera: ", limitesMonto); err != nil {
+			return perfil, err
+		}
+	}
+	return perfil, nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoRecordatorios muestra los próximos cortes y pagos de las tarjetas de crédito
+func comandoRecordatorios() *cli.Command {
+	return &cli.Command{
+		Name:  "recordatorios",
+		Usage: "Mostrar las próximas fechas de corte y de pago de tus tarjetas de crédito",
+		Description: "Registra los días de cada tarjeta con 'finmex credito editar --corte 15 --pago 5 <tarjeta>'.\n" +
+			"Con --ical se genera un archivo .ics para importar en tu calendario; los eventos conservan\n" +
+			"su identificador, así que volver a importarlo actualiza los recordatorios en lugar de duplicarlos.",
+		Flags: []cli.Flag{
+			&cli.IntFlag{Name: "dias", Value: 45, Usage: "Días hacia adelante a mostrar"},
+			&cli.StringFlag{Name: "ical", Usage: "Guardar los recordatorios en un archivo .ics"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Int("dias") < 1 {
+				return errDatosInvalidos("--dias debe ser al menos 1", "--dias must be at least 1")
+			}
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			hoy := time.Now().Truncate(24 * time.Hour)
+			recordatorios, sinDias, err := Recordatorios(tarjetas, hoy, hoy.AddDate(0, 0, c.Int("dias")))
+			if err != nil {
+				return err
+			}
+
+			if c.String("ical") != "" {
+				var b bytes.Buffer
+				if err := EscribirICal(&b, recordatorios, time.Now()); err != nil {
+					return err
+				}
+				if err := os.WriteFile(c.String("ical"), b.Bytes(), 0644); err != nil {
+					return fmt.Errorf("Error al escribir %s: %v", c.String("ical"), err)
+				}
+				fmt.Printf("%d recordatorios guardados en %s\n", len(recordatorios), c.String("ical"))
+				return nil
+			}
+
+			if salidaEstructurada() {
+				return emitirDatos(recordatorios)
+			}
+
+			if len(recordatorios) == 0 {
+				fmt.Printf("No hay cortes ni pagos en los próximos %d días\n", c.Int("dias"))
+			} else {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+				fmt.Fprintln(w, "Fecha\tTarjeta\tEvento\tDías restantes\tPago mínimo estimado")
+				fmt.Fprintln(w, "-----\t-------\t------\t--------------\t--------------------")
+				for _, r := range recordatorios {
+					evento, minimo := "Corte", "-"
+					if r.Evento == EventoPago {
+						evento, minimo = "Límite de pago", fmt.Sprintf("$%.2f", r.PagoMinimo)
+					}
+					dias := fmt.Sprintf("%d", r.DiasRestantes)
+					if r.DiasRestantes == 0 {
+						dias = "hoy"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Fecha.Format("2006-01-02"), r.Tarjeta, evento, dias, minimo)
+				}
+				w.Flush()
+
+				for _, r := range recordatorios {
+					if r.Evento == EventoPago && r.DiasRestantes <= 3 && r.PagoMinimo > 0 {
+						fmt.Printf("\nALERTA: El pago de %s vence el %s; paga al menos $%.2f (el total evita intereses)\n",
+							r.Tarjeta, r.Fecha.Format("2006-01-02"), r.PagoMinimo)
+					}
+				}
+			}
+
+			if len(sinDias) > 0 {
+				fmt.Printf("\nAVISO: Sin fechas de corte y pago: %s. Regístralas con 'finmex credito editar --corte N --pago N <tarjeta>'\n", strings.Join(sinDias, ", "))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoReporte genera el reporte mensual en Markdown o HTML
+func comandoReporte() *cli.Command {
+	return &cli.Command{
+		Name:  "reporte",
+		Usage: "Generar el reporte mensual en Markdown o HTML",
+		Description: "Incluye el resumen de productos, el rendimiento de débito, el costo de los créditos activos,\n" +
+			"el gasto por categoría del mes y las recomendaciones. Con --plantilla se usa una plantilla\n" +
+			"de Go propia con los mismos datos y las funciones pesos, pct y siguiente.\n" +
+			"Ejemplo: finmex reporte --mes 2024-05 --archivo mayo.html",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "mes", Usage: "Mes del reporte en formato AAAA-MM; por omisión el actual"},
+			&cli.StringFlag{Name: "formato", Usage: "md o html; por omisión según la extensión de --archivo, o md"},
+			&cli.StringFlag{Name: "archivo", Usage: "Archivo de salida; por defecto se imprime en pantalla"},
+			&cli.StringFlag{Name: "plantilla", Usage: "Plantilla de Go a usar en lugar de la incluida"},
+		},
+		Action: func(c *cli.Context) error {
+			hoy := time.Now()
+			mes := hoy.Format("2006-01")
+			if c.IsSet("mes") {
+				mes = c.String("mes")
+			}
+
+			formato := strings.ToLower(c.String("formato"))
+			if formato == "" {
+				formato = FormatoReporteMarkdown
+				switch strings.ToLower(filepath.Ext(c.String("archivo"))) {
+				case ".html", ".htm":
+					formato = FormatoReporteHTML
+				}
+			}
+			if formato == "markdown" {
+				formato = FormatoReporteMarkdown
+			}
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			catalogo, err := CargarCatalogo()
+			if err != nil {
+				return err
+			}
+			r, err := GenerarReporteMensual(tarjetas, catalogo, mes, hoy)
+			if err != nil {
+				return err
+			}
+
+			var b bytes.Buffer
+			if err := EscribirReporte(&b, r, formato, c.String("plantilla")); err != nil {
+				return err
+			}
+			if c.String("archivo") == "" {
+				fmt.Print(b.String())
+				return nil
+			}
+			if err := os.WriteFile(c.String("archivo"), b.Bytes(), 0644); err != nil {
+				return fmt.Errorf("Error al escribir %s: %v", c.String("archivo"), err)
+			}
+			fmt.Printf("Reporte de %s guardado en %s\n", mes, c.String("archivo"))
+			return nil
+		},
+	}
+}
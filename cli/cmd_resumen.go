@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoResumen muestra el resumen patrimonial con activos, pasivos y patrimonio neto
+func comandoResumen() *cli.Command {
+	return &cli.Command{
+		Name:  "resumen",
+		Usage: "Mostrar el resumen patrimonial",
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+
+			resumen, err := CalcularResumenPatrimonial(tarjetas, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if len(resumen.Partidas) == 0 {
+				fmt.Println("No hay activos ni pasivos registrados")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Tipo\tCategoría\tConcepto\tMonto")
+			fmt.Fprintln(w, "----\t---------\t--------\t-----")
+
+			for _, p := range resumen.Partidas {
+				tipo := "Activo"
+				if p.Pasivo {
+					tipo = "Pasivo"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t$%.2f\n", tipo, p.Categoria, p.Concepto, p.Monto)
+			}
+
+			w.Flush()
+
+			fmt.Println("\n=== Resumen Patrimonial ===")
+			fmt.Printf("Activos: $%.2f (líquidos: $%.2f)\n", resumen.Activos, resumen.Liquidez)
+			fmt.Printf("Pasivos: $%.2f\n", resumen.Pasivos)
+			fmt.Printf("Patrimonio neto: $%.2f\n", resumen.Patrimonio())
+
+			catalogo, err := CargarCatalogo()
+			if err != nil {
+				return err
+			}
+			if alerta := RevisarInflacion(tarjetas, catalogo.Benchmarks, InflacionVigente()); alerta.Activa {
+				fmt.Printf("\n%s\n", alerta.Mensaje())
+				for _, b := range alerta.Benchmarks {
+					fmt.Printf("  %s rinde %.2f%% neto de ISR (%.2f%% arriba de la inflación)\n", b.Nombre, TasaNetaISR(b.Tasa)*100, (TasaNetaISR(b.Tasa)-InflacionVigente())*100)
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoRetiro agrupa la proyección de la Afore y el plan de retiro
+func comandoRetiro() *cli.Command {
+	return &cli.Command{
+		Name:  "retiro",
+		Usage: "Proyección de la Afore y plan de retiro bajo la Ley 97",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "configurar",
+				Usage: "Capturar edad, salario y datos de la Afore",
+				Description: "Sin flags pregunta cada dato mostrando el valor guardado; con flags solo cambia los indicados.\n" +
+					"Ejemplo: finmex retiro configurar --edad 35 --salario 25000 --saldo 180000 --voluntaria 1000",
+				Flags: []cli.Flag{
+					&cli.Float64Flag{Name: "edad", Usage: "Edad actual"},
+					&cli.Float64Flag{Name: "edad-retiro", Usage: fmt.Sprintf("Edad a la que te quieres retirar (%d a 75)", EDAD_RETIRO_MINIMA)},
+					&cli.Float64Flag{Name: "salario", Usage: "Salario mensual bruto"},
+					&cli.Float64Flag{Name: "saldo", Usage: "Saldo actual de la Afore (retiro, cesantía y vejez)"},
+					&cli.Float64Flag{Name: "comision", Usage: "Comisión anual de la Afore sobre saldo (decimal)"},
+					&cli.Float64Flag{Name: "voluntaria", Usage: "Aportación voluntaria mensual"},
+					&cli.Float64Flag{Name: "semanas", Usage: "Semanas cotizadas al IMSS hasta hoy"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					año := time.Now().Year()
+					d := DatosRetiro{Nacimiento: año - 30, EdadRetiro: EDAD_RETIRO_VEJEZ, Comision: COMISION_AFORE}
+					if tarjetas.Retiro != nil {
+						d = *tarjetas.Retiro
+					}
+
+					k := nuevaCaptura(c)
+					edad, err := k.EditarNumero("edad", "Edad actual", float64(año-d.Nacimiento), LimitesNumero{Min: 15, Max: 90})
+					if err != nil {
+						return err
+					}
+					edadRetiro, err := k.EditarNumero("edad-retiro", "Edad de retiro", float64(d.EdadRetiro), LimitesNumero{Min: EDAD_RETIRO_MINIMA, Max: 75})
+					if err != nil {
+						return err
+					}
+					d.Nacimiento, d.EdadRetiro = año-int(edad), int(edadRetiro)
+					if d.Salario, err = k.EditarNumero("salario", "Salario mensual bruto", d.Salario, limitesMonto); err != nil {
+						return err
+					}
+					if d.SaldoAfore, err = k.EditarNumero("saldo", "Saldo actual de la Afore", d.SaldoAfore, limitesMonto); err != nil {
+						return err
+					}
+					if d.Comision, err = k.EditarNumero("comision", "Comisión anual de la Afore (decimal)", d.Comision, LimitesNumero{Min: 0, Max: 0.05, Porcentaje: true}); err != nil {
+						return err
+					}
+					if d.Voluntaria, err = k.EditarNumero("voluntaria", "Aportación voluntaria mensual", d.Voluntaria, limitesMonto); err != nil {
+						return err
+					}
+					semanas, err := k.EditarNumero("semanas", "Semanas cotizadas al IMSS", float64(d.Semanas), LimitesNumero{Min: 0, Max: 4000})
+					if err != nil {
+						return err
+					}
+					d.Semanas = int(semanas)
+
+					if d.EdadRetiro <= año-d.Nacimiento {
+						return errDatosInvalidos(
+							fmt.Sprintf("La edad de retiro (%d) debe ser mayor a tu edad actual (%d)", d.EdadRetiro, año-d.Nacimiento),
+							fmt.Sprintf("The retirement age (%d) must be greater than your current age (%d)", d.EdadRetiro, año-d.Nacimiento))
+					}
+
+					tarjetas.Retiro = &d
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar los datos de retiro: %w", err)
+					}
+					fmt.Println("Datos de retiro guardados; consulta la proyección con 'finmex retiro proyectar'")
+					return nil
+				},
+			},
+			{
+				Name:  "proyectar",
+				Usage: "Proyectar el saldo de la Afore y la pensión al retiro",
+				Description: "Los montos se expresan en pesos de hoy: el rendimiento y el incremento de salario son reales,\n" +
+					"es decir, sobre la inflación. La pensión se estima como retiro programado hasta los " +
+					fmt.Sprint(EDAD_FIN_RETIRO_PROGRAMADO) + " años.",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "rendimiento-real", Value: RENDIMIENTO_REAL_AFORE, Usage: "Rendimiento anual de la Siefore sobre la inflación, antes de comisión"}, limitesRealRetiro),
+					conLimites(&cli.Float64Flag{Name: "incremento-salario", Usage: "Aumento anual del salario sobre la inflación"}, limitesRealRetiro),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if tarjetas.Retiro == nil {
+						return fmt.Errorf("No hay datos de retiro; captúralos con 'finmex retiro configurar'")
+					}
+
+					aportacionPPR := 0.0
+					for _, p := range tarjetas.PPR {
+						aportacionPPR += p.AportacionAnual
+					}
+					d := *tarjetas.Retiro
+					p, err := ProyectarRetiro(d, c.Float64("rendimiento-real"), c.Float64("incremento-salario"), aportacionPPR, time.Now().Year())
+					if err != nil {
+						return err
+					}
+
+					if salidaEstructurada() {
+						if formatoDatos == FormatoCSV {
+							return emitirDatos(p.Años)
+						}
+						return emitirDatos(p)
+					}
+
+					fmt.Println("\n=== Proyección de Retiro (Ley 97) ===")
+					fmt.Printf("Edad: %d, retiro a los %d (%d años) | Rendimiento real %.2f%% menos comisión %.2f%%\n",
+						p.Edad, p.EdadRetiro, p.EdadRetiro-p.Edad, p.RendimientoReal*100, d.Comision*100)
+					fmt.Printf("Montos en pesos de hoy\n\n")
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Edad\tAño\tObligatorias\tVoluntarias\tSaldo\tSin Voluntarias")
+					fmt.Fprintln(w, "----\t---\t------------\t-----------\t-----\t---------------")
+					for _, a := range p.Años {
+						fmt.Fprintf(w, "%d\t%d\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n", a.Edad, a.Año, a.Obligatorias, a.Voluntarias, a.Saldo, a.SinVoluntarias)
+					}
+					w.Flush()
+
+					fmt.Printf("\nSaldo al retiro: $%.2f\n", p.Saldo)
+					fmt.Printf("RESULTADO: Pensión mensual estimada: $%.2f (%.1f%% de tu último salario)\n", p.Pension, p.TasaReemplazo*100)
+					if d.Voluntaria > 0 {
+						fmt.Printf("Sin aportaciones voluntarias: saldo $%.2f, pensión $%.2f al mes\n", p.SaldoSinVoluntarias, p.PensionSinVoluntarias)
+						fmt.Printf("Las voluntarias suman $%.2f a tu pensión mensual\n", p.Pension-p.PensionSinVoluntarias)
+						fmt.Printf("Deduciéndolas: $%.2f deducibles al año, devolución de ISR de $%.2f al año", p.DeducibleAnual, p.DevolucionAnual)
+						fmt.Printf(" (costo neto $%.2f al mes)\n", d.Voluntaria-p.DevolucionAnual/12)
+						if p.DeducibleAnual < d.Voluntaria*12 {
+							fmt.Println("AVISO: Parte de las voluntarias excede el tope deducible, que comparten con tus PPR")
+						}
+					}
+
+					if p.Semanas < p.SemanasRequeridas {
+						fmt.Printf("ALERTA: Tendrías %d semanas cotizadas y se piden %d; sin ellas no hay pensión y solo puedes retirar el saldo\n",
+							p.Semanas, p.SemanasRequeridas)
+					} else {
+						fmt.Printf("Semanas cotizadas al retiro: %d de %d requeridas\n", p.Semanas, p.SemanasRequeridas)
+					}
+					fmt.Println("Si el saldo no alcanza la pensión mínima garantizada, el gobierno la complementa.")
+					return nil
+				},
+			},
+		},
+	}
+}
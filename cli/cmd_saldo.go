@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoSaldo agrupa el historial mensual de saldos de las tarjetas
+func comandoSaldo() *cli.Command {
+	return &cli.Command{
+		Name:  "saldo",
+		Usage: "Historial mensual de saldos de tus tarjetas",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "registrar",
+				Usage:     "Registrar el saldo de una tarjeta en un mes",
+				ArgsUsage: "<tarjeta> <monto>",
+				Description: "El saldo del mes en curso también actualiza el saldo actual de la tarjeta.\n" +
+					"Ejemplo: finmex saldo registrar --mes 2026-09 \"Nu Cuenta\" 48500",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "mes", Usage: "Mes del saldo en formato AAAA-MM; por omisión el actual"},
+					&cli.StringFlag{Name: "tipo", Usage: "debito o credito, si hay una tarjeta de cada tipo con el mismo nombre"},
+				},
+				Action: func(c *cli.Context) error {
+					nombre, saldo, err := argumentosNombreMonto(c)
+					if err != nil {
+						return err
+					}
+					hoy := time.Now()
+					mes := hoy.Format("2006-01")
+					if c.IsSet("mes") {
+						mes = c.String("mes")
+					}
+					if err := validarMesSaldo(mes, hoy); err != nil {
+						return err
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					tipo, i, err := buscarTarjetaSaldo(tarjetas, nombre, c.String("tipo"))
+					if err != nil {
+						return err
+					}
+
+					nombre = nombreTarjetaSaldo(tarjetas, tipo, i)
+					if mes == hoy.Format("2006-01") {
+						if tipo == "debito" {
+							tarjetas.Debito[i].Saldo = saldo
+						} else {
+							tarjetas.Credito[i].Saldo = saldo
+						}
+					}
+					tarjetas.RegistrarSaldo(RegistroSaldo{Tipo: tipo, Tarjeta: nombre, Mes: mes, Saldo: saldo})
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar el saldo: %w", err)
+					}
+					fmt.Printf("Saldo de '%s' en %s registrado: $%.2f\n", nombre, mes, saldo)
+					return nil
+				},
+			},
+			{
+				Name:      "historial",
+				Usage:     "Ver la evolución del saldo con su crecimiento real",
+				ArgsUsage: "[tarjeta]",
+				Description: "Sin tarjeta resume todas las que tienen historial. El crecimiento anualizado incluye\n" +
+					"depósitos y retiros, y el real descuenta la inflación vigente.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "tipo", Usage: "debito o credito, si hay una tarjeta de cada tipo con el mismo nombre"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					inflacion := InflacionVigente()
+
+					if c.NArg() == 0 {
+						return imprimirResumenSaldos(tarjetas, inflacion)
+					}
+
+					tipo, i, err := buscarTarjetaSaldo(tarjetas, argumentoNombre(c.Args().Slice()), c.String("tipo"))
+					if err != nil {
+						return err
+					}
+					nombre := nombreTarjetaSaldo(tarjetas, tipo, i)
+					registros := tarjetas.HistorialSaldos(tipo, nombre)
+					if len(registros) == 0 {
+						return fmt.Errorf("'%s' no tiene saldos registrados; usa 'finmex saldo registrar'", nombre)
+					}
+					e := EvolucionarSaldo(registros, inflacion)
+
+					if salidaEstructurada() {
+						if formatoDatos == FormatoCSV {
+							return emitirDatos(e.Registros)
+						}
+						return emitirDatos(e)
+					}
+
+					fmt.Printf("\n=== Historial de Saldo: %s ===\n", e.Tarjeta)
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Mes\tSaldo\tCambio")
+					fmt.Fprintln(w, "---\t-----\t------")
+					valores := make([]float64, len(e.Registros))
+					for n, r := range e.Registros {
+						valores[n] = r.Saldo
+						cambio := "-"
+						if n > 0 {
+							cambio = fmt.Sprintf("%+.2f", r.Saldo-e.Registros[n-1].Saldo)
+						}
+						fmt.Fprintf(w, "%s\t$%.2f\t%s\n", r.Mes, r.Saldo, cambio)
+					}
+					w.Flush()
+
+					fmt.Printf("\nEvolución: %s\n", Sparkline(valores))
+					imprimirCrecimientoSaldo(e)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// buscarTarjetaSaldo encuentra la tarjeta de débito o de crédito por nombre; con el mismo
+// nombre en ambos tipos se pide --tipo
+func buscarTarjetaSaldo(tarjetas Tarjetas, nombre, tipo string) (string, int, error) {
+	debito, enDebito := indiceDebito(tarjetas, nombre)
+	credito, enCredito := indiceCredito(tarjetas, nombre)
+	switch normalizarClave(tipo) {
+	case "":
+	case "debito", "débito":
+		enCredito = false
+	case "credito", "crédito":
+		enDebito = false
+	default:
+		return "", 0, errDatosInvalidos(
+			fmt.Sprintf("Tipo inválido '%s' (usa debito o credito)", tipo),
+			fmt.Sprintf("Invalid type '%s' (use debito or credito)", tipo))
+	}
+
+	switch {
+	case enDebito && enCredito:
+		return "", 0, errDatosInvalidos(
+			fmt.Sprintf("Hay una tarjeta de débito y una de crédito llamadas '%s'; indica --tipo", nombre),
+			fmt.Sprintf("There is a debit and a credit card named '%s'; use --tipo", nombre))
+	case enDebito:
+		return "debito", debito, nil
+	case enCredito:
+		return "credito", credito, nil
+	}
+	return "", 0, &ErrorFinmex{
+		Codigo:  CodigoTarjetaNoEncontrada,
+		Mensaje: fmt.Sprintf("No se encontró la tarjeta '%s' entre las de débito ni las de crédito", nombre),
+		Message: fmt.Sprintf("Card '%s' not found among debit or credit cards", nombre),
+	}
+}
+
+// nombreTarjetaSaldo regresa el nombre registrado de la tarjeta que encontró buscarTarjetaSaldo
+func nombreTarjetaSaldo(tarjetas Tarjetas, tipo string, i int) string {
+	if tipo == "debito" {
+		return tarjetas.Debito[i].Nombre
+	}
+	return tarjetas.Credito[i].Nombre
+}
+
+// imprimirResumenSaldos muestra una fila por tarjeta con historial
+func imprimirResumenSaldos(tarjetas Tarjetas, inflacion float64) error {
+	var evoluciones []EvolucionSaldo
+	for _, t := range tarjetas.Debito {
+		if registros := tarjetas.HistorialSaldos("debito", t.Nombre); len(registros) > 0 {
+			evoluciones = append(evoluciones, EvolucionarSaldo(registros, inflacion))
+		}
+	}
+	for _, t := range tarjetas.Credito {
+		if registros := tarjetas.HistorialSaldos("credito", t.Nombre); len(registros) > 0 {
+			evoluciones = append(evoluciones, EvolucionarSaldo(registros, inflacion))
+		}
+	}
+
+	if salidaEstructurada() {
+		if formatoDatos == FormatoCSV {
+			var registros []RegistroSaldo
+			for _, e := range evoluciones {
+				registros = append(registros, e.Registros...)
+			}
+			return emitirDatos(registros)
+		}
+		return emitirDatos(evoluciones)
+	}
+	if len(evoluciones) == 0 {
+		return fmt.Errorf("No hay saldos registrados; usa 'finmex saldo registrar <tarjeta> <monto>'")
+	}
+
+	fmt.Println("\n=== Evolución de Saldos ===")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Tarjeta\tTipo\tDesde\tHasta\tEvolución\tInicial\tFinal\tCrec. Anual\tCrec. Real")
+	fmt.Fprintln(w, "-------\t----\t-----\t-----\t---------\t-------\t-----\t-----------\t----------")
+	for _, e := range evoluciones {
+		valores := make([]float64, len(e.Registros))
+		for n, r := range e.Registros {
+			valores[n] = r.Saldo
+		}
+		anual, anualReal := "-", "-"
+		if e.Meses > 0 && e.Inicial > 0 {
+			anual, anualReal = fmt.Sprintf("%.2f%%", e.CrecimientoAnual*100), fmt.Sprintf("%.2f%%", e.CrecimientoReal*100)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t$%.2f\t$%.2f\t%s\t%s\n",
+			e.Tarjeta, e.Tipo, e.Registros[0].Mes, e.Registros[len(e.Registros)-1].Mes, Sparkline(valores),
+			e.Inicial, e.Final, anual, anualReal)
+	}
+	w.Flush()
+	fmt.Printf("\nInflación usada: %.2f%% anual\n", inflacion*100)
+	return nil
+}
+
+// imprimirCrecimientoSaldo explica el crecimiento nominal y real del historial de una tarjeta
+func imprimirCrecimientoSaldo(e EvolucionSaldo) {
+	if e.Meses == 0 {
+		fmt.Println("Registra al menos dos meses para calcular el crecimiento.")
+		return
+	}
+	if e.Inicial <= 0 {
+		fmt.Printf("Cambio en %d meses: $%.2f (el saldo inicial en cero no permite calcular una tasa)\n", e.Meses, e.Final-e.Inicial)
+		return
+	}
+	fmt.Printf("Cambio en %d meses: $%.2f\n", e.Meses, e.Final-e.Inicial)
+	fmt.Printf("RESULTADO: Crecimiento anualizado de %.2f%%, real de %.2f%% con inflación de %.2f%%\n",
+		e.CrecimientoAnual*100, e.CrecimientoReal*100, e.Inflacion*100)
+	switch {
+	case e.Tipo == "credito" && e.CrecimientoAnual > 0:
+		fmt.Println("ALERTA: Tu deuda crece; revisa un plan para liquidarla con 'finmex deuda plan'")
+	case e.Tipo == "debito" && e.CrecimientoReal < 0:
+		fmt.Println("AVISO: Tu saldo pierde poder de compra frente a la inflación")
+	}
+}
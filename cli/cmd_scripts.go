@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoScripts muestra y ejecuta las reglas personalizadas escritas en Starlark
+func comandoScripts() *cli.Command {
+	return &cli.Command{
+		Name:  "scripts",
+		Usage: "Reglas y cálculos personalizados escritos en Starlark",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "listar",
+				Usage: "Mostrar los scripts cargados y los hooks que definen",
+				Action: func(c *cli.Context) error {
+					scripts, err := CargarScripts()
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("Directorio de scripts: %s\n", scripts.Directorio)
+					if len(scripts.Archivos) == 0 {
+						fmt.Println("No hay scripts. Crea archivos .star en ese directorio.")
+						fmt.Printf("Hooks disponibles: %s(tarjeta), %s(movimiento)\n", hookPuntuarTarjeta, hookCategorizar)
+						return nil
+					}
+					for _, archivo := range scripts.Archivos {
+						fmt.Printf("  %s\n", archivo)
+					}
+
+					hooks := scripts.Hooks()
+					nombres := make([]string, 0, len(hooks))
+					for nombre := range hooks {
+						nombres = append(nombres, nombre)
+					}
+					sort.Strings(nombres)
+					fmt.Println("Hooks definidos:")
+					for _, nombre := range nombres {
+						fmt.Printf("  %s: %s\n", nombre, strings.Join(hooks[nombre], ", "))
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "puntuar",
+				Usage: "Ordenar tus tarjetas de crédito con la fórmula de puntaje de tus scripts",
+				Flags: flagsFiltroTarjetas(),
+				Action: func(c *cli.Context) error {
+					scripts, err := CargarScripts()
+					if err != nil {
+						return err
+					}
+					if !scripts.TienePuntaje() {
+						return fmt.Errorf("Ningún script en %s define %s(tarjeta)", scripts.Directorio, hookPuntuarTarjeta)
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					filtro := filtroTarjetas(c)
+
+					type puntuada struct {
+						tarjeta TarjetaCredito
+						puntaje float64
+					}
+					var lista []puntuada
+					var nombres []string
+					for _, t := range tarjetas.Credito {
+						nombres = append(nombres, t.Nombre)
+						if !filtro.Incluye(t.Nombre, t.Tags) {
+							continue
+						}
+						puntaje, err := scripts.PuntuarTarjeta(t)
+						if err != nil {
+							return err
+						}
+						lista = append(lista, puntuada{t, puntaje})
+					}
+					if err := filtro.Verificar(nombres); err != nil {
+						return err
+					}
+					if len(lista) == 0 {
+						fmt.Println("No hay tarjetas de crédito para puntuar")
+						return nil
+					}
+					sort.SliceStable(lista, func(i, j int) bool { return lista[i].puntaje > lista[j].puntaje })
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "#\tNombre\tBanco\tPuntaje")
+					fmt.Fprintln(w, "-\t------\t-----\t-------")
+					for i, p := range lista {
+						fmt.Fprintf(w, "%d\t%s\t%s\t%.2f\n", i+1, p.tarjeta.Nombre, p.tarjeta.Banco, p.puntaje)
+					}
+					w.Flush()
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoSeguro agrupa las operaciones con pólizas de seguro
+func comandoSeguro() *cli.Command {
+	return &cli.Command{
+		Name:  "seguro",
+		Usage: "Cotizaciones de seguros de auto y gastos médicos mayores",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar una cotización de seguro",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var p Poliza
+
+					if p.Nombre, err = leerTextoRequerido("Nombre de la póliza: "); err != nil {
+						return err
+					}
+					if p.Aseguradora, err = leerTextoRequerido("Aseguradora: "); err != nil {
+						return err
+					}
+
+					tipo, err := leerTextoRequerido("Tipo (auto, gmm): ")
+					if err != nil {
+						return err
+					}
+					p.Tipo = strings.ToLower(tipo)
+					if err := ValidarTipoSeguro(p.Tipo); err != nil {
+						return err
+					}
+
+					if p.PrimaAnual, err = leerNumero("Prima anual: ", limitesMonto); err != nil {
+						return err
+					}
+					if p.Deducible, err = leerNumero("Deducible en pesos: ", limitesMonto); err != nil {
+						return err
+					}
+					if p.Coaseguro, err = leerNumero("Coaseguro (decimal, ej. 0.10): ", limitesFraccion); err != nil {
+						return err
+					}
+					if p.Coaseguro > 0 {
+						if p.TopeCoaseguro, err = leerNumero("Tope de coaseguro en pesos (0 si no tiene tope): ", limitesMonto); err != nil {
+							return err
+						}
+					}
+					if p.SumaAsegurada, err = leerNumero("Suma asegurada: ", limitesMonto); err != nil {
+						return err
+					}
+
+					tarjetas.Polizas = append(tarjetas.Polizas, p)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar póliza: %w", err)
+					}
+
+					fmt.Printf("Póliza '%s' agregada exitosamente\n", p.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar cotizaciones de seguro registradas",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if salidaEstructurada() {
+						return emitirDatos(tarjetas.Polizas)
+					}
+
+					if len(tarjetas.Polizas) == 0 {
+						fmt.Println("No hay pólizas registradas")
+						return nil
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tAseguradora\tTipo\tPrima Anual\tDeducible\tCoaseguro\tTope Coaseguro\tSuma Asegurada")
+					fmt.Fprintln(w, "------\t-----------\t----\t-----------\t---------\t---------\t--------------\t--------------")
+
+					for _, p := range tarjetas.Polizas {
+						tope := "Sin tope"
+						if p.TopeCoaseguro > 0 {
+							tope = fmt.Sprintf("$%.2f", p.TopeCoaseguro)
+						}
+						fmt.Fprintf(w, "%s\t%s\t%s\t$%.2f\t$%.2f\t%.0f%%\t%s\t$%.2f\n",
+							p.Nombre, p.Aseguradora, p.Tipo, p.PrimaAnual, p.Deducible,
+							p.Coaseguro*100, tope, p.SumaAsegurada)
+					}
+
+					w.Flush()
+					return nil
+				},
+			},
+			{
+				Name:  "comparar",
+				Usage: "Comparar el costo esperado anual de las pólizas de un tipo",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "tipo", Value: SeguroAuto, Usage: "Tipo de póliza: auto o gmm"},
+					conLimites(&cli.Float64Flag{Name: "probabilidad", Usage: "Probabilidad anual de siniestro (decimal)"}, limitesFraccion),
+					conLimites(&cli.Float64Flag{Name: "costo", Usage: "Costo promedio de un siniestro"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					tipo := strings.ToLower(c.String("tipo"))
+					if err := ValidarTipoSeguro(tipo); err != nil {
+						return err
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var polizas []Poliza
+					for _, p := range tarjetas.Polizas {
+						if p.Tipo == tipo {
+							polizas = append(polizas, p)
+						}
+					}
+					if len(polizas) == 0 {
+						return fmt.Errorf("No hay pólizas de tipo %s registradas", tipo)
+					}
+
+					s := SiniestralidadDefault(tipo)
+					if c.IsSet("probabilidad") {
+						s.Probabilidad = c.Float64("probabilidad")
+					}
+					if c.IsSet("costo") {
+						s.CostoPromedio = c.Float64("costo")
+					}
+					if err := validarLimites(s.Probabilidad, limitesFraccion); err != nil {
+						return err
+					}
+
+					if salidaEstructurada() {
+						type fila struct {
+							Nombre          string  `json:"nombre"`
+							Aseguradora     string  `json:"aseguradora"`
+							PrimaAnual      float64 `json:"prima_anual"`
+							PagoDeBolsa     float64 `json:"pago_de_bolsa"`
+							CostoEsperado   float64 `json:"costo_esperado"`
+							ApartadoMensual float64 `json:"apartado_mensual"`
+						}
+						filas := []fila{}
+						for _, p := range polizas {
+							esperado := p.CostoEsperado(s)
+							filas = append(filas, fila{p.Nombre, p.Aseguradora, p.PrimaAnual, p.PagoDeBolsa(s.CostoPromedio), esperado, esperado / 12})
+						}
+						return emitirDatos(filas)
+					}
+
+					fmt.Println("\n=== Comparación de Seguros ===")
+					fmt.Printf("Supuestos: %.0f%% de probabilidad de siniestro al año, costo promedio $%.2f\n\n", s.Probabilidad*100, s.CostoPromedio)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tAseguradora\tPrima Anual\tPago de Bolsa por Siniestro\tCosto Esperado Anual\tApartado Mensual")
+					fmt.Fprintln(w, "------\t-----------\t-----------\t---------------------------\t--------------------\t----------------")
+
+					mejor := polizas[0]
+					for _, p := range polizas {
+						esperado := p.CostoEsperado(s)
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+							p.Nombre, p.Aseguradora, p.PrimaAnual, p.PagoDeBolsa(s.CostoPromedio), esperado, esperado/12)
+						if esperado < mejor.CostoEsperado(s) {
+							mejor = p
+						}
+					}
+
+					w.Flush()
+					fmt.Printf("\nRESULTADO: %s tiene el menor costo esperado: aparta $%.2f al mes en tu presupuesto\n", mejor.Nombre, mejor.CostoEsperado(s)/12)
+					return nil
+				},
+			},
+			{
+				Name:      "gasto",
+				Usage:     "Calcular cuánto pagarías de tu bolsa por un gasto médico con cada póliza",
+				ArgsUsage: "<monto>",
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return fmt.Errorf("Uso: finmex seguro gasto <monto>")
+					}
+					gasto, err := ParsearNumero(c.Args().First())
+					if err != nil {
+						return err
+					}
+					if err := validarLimites(gasto, limitesMonto); err != nil {
+						return err
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var polizas []Poliza
+					for _, p := range tarjetas.Polizas {
+						if p.Tipo == SeguroGMM {
+							polizas = append(polizas, p)
+						}
+					}
+					if len(polizas) == 0 {
+						return fmt.Errorf("No hay pólizas de gastos médicos registradas")
+					}
+
+					fmt.Println("\n=== Gasto Médico Cubierto por Póliza ===")
+					fmt.Printf("Gasto hospitalario estimado: $%.2f\n\n", gasto)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Póliza\tDeducible\tCoaseguro\tExcedente\tPaga la Aseguradora\tPagas Tú")
+					fmt.Fprintln(w, "------\t---------\t---------\t---------\t-------------------\t--------")
+
+					menor, mayor := polizas[0], polizas[0]
+					for _, p := range polizas {
+						d := p.Desglosar(gasto)
+						fmt.Fprintf(w, "%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+							p.Nombre, d.Deducible, d.Coaseguro, d.Excedente, d.Aseguradora, d.Total())
+						if d.Total() < menor.PagoDeBolsa(gasto) {
+							menor = p
+						}
+						if d.Total() > mayor.PagoDeBolsa(gasto) {
+							mayor = p
+						}
+					}
+
+					w.Flush()
+					fmt.Printf("\nRESULTADO: Con %s pagarías lo menos de tu bolsa: $%.2f\n", menor.Nombre, menor.PagoDeBolsa(gasto))
+					fmt.Printf("Para un gasto así, tu fondo de emergencia médico necesita $%.2f con %s y $%.2f con %s\n",
+						menor.PagoDeBolsa(gasto), menor.Nombre, mayor.PagoDeBolsa(gasto), mayor.Nombre)
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoServe levanta la API HTTP para frontends web e integraciones como Home Assistant
+func comandoServe() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Iniciar un servidor HTTP con una API JSON de tarjetas y cálculos",
+		Description: "Rutas:\n" +
+			"  GET  /api/salud                                    versión, perfil, inflación e ISR vigentes\n" +
+			"  GET  /api/tarjetas                                 todos los productos registrados\n" +
+			"  GET  /api/debito, POST /api/debito                 listar o agregar cuentas de débito\n" +
+			"  GET  /api/debito/{nombre}/rendimiento?saldo=       rendimiento real de un año\n" +
+			"  GET  /api/credito, POST /api/credito               listar o agregar tarjetas de crédito\n" +
+			"  GET  /api/credito/{nombre}/costo?deuda=&pago=&frecuencia=\n" +
+			"  GET  /api/calcular/rendimiento?tasa=&saldo=&comision=&saldo-minimo= (o tramos=25000:0.15,0.08 en lugar de tasa)\n" +
+			"  GET  /api/calcular/credito?tasa=&deuda=&pago=&comision=&frecuencia=\n" +
+			"  GET  /api/comparar/debito?saldo=                   rendimiento de todas las cuentas con el mismo saldo\n" +
+			"  GET  /api/comparar/credito?deuda=&pago=&frecuencia= costo de liquidar la misma deuda con cada tarjeta\n" +
+			"Los cuerpos de POST usan los mismos campos que tarjetas.json. Los errores responden\n" +
+			"{\"error\", \"codigo\", \"message\"} con estado 400, 404 o 500.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "direccion", Value: DIRECCION_SERVIDOR, Usage: "Dirección y puerto donde escuchar", EnvVars: []string{"FINMEX_SERVE_DIRECCION"}},
+			&cli.StringFlag{Name: "token", Usage: "Exigir este token en el encabezado Authorization: Bearer", EnvVars: []string{"FINMEX_SERVE_TOKEN"}},
+			&cli.StringFlag{Name: "cors", Usage: "Origen permitido para llamadas desde un navegador (p. ej. http://localhost:3000 o *)"},
+		},
+		Action: func(c *cli.Context) error {
+			direccion := c.String("direccion")
+			host, _, err := net.SplitHostPort(direccion)
+			if err != nil {
+				return errDatosInvalidos(fmt.Sprintf("Dirección inválida '%s': usa host:puerto", direccion),
+					fmt.Sprintf("Invalid address '%s': use host:port", direccion))
+			}
+			if ip := net.ParseIP(host); c.String("token") == "" && (ip == nil || !ip.IsLoopback()) && host != "localhost" {
+				fmt.Fprintf(os.Stderr, "AVISO: El servidor acepta conexiones de otras máquinas sin token; cualquiera en tu red podrá ver y modificar tus tarjetas. Usa --token.\n")
+			}
+
+			// El almacén se abre antes de atender solicitudes para que dos de ellas no lo abran a la vez
+			if _, err := almacenDatos(); err != nil {
+				return err
+			}
+
+			listener, err := net.Listen("tcp", direccion)
+			if err != nil {
+				return fmt.Errorf("No se pudo escuchar en %s: %w", direccion, err)
+			}
+			servidor := &http.Server{
+				Handler:           NuevoServidor(c.String("token"), c.String("cors")).Handler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			// Al recibir Ctrl+C o SIGTERM terminamos las solicitudes en curso y salimos
+			señales := make(chan os.Signal, 1)
+			signal.Notify(señales, os.Interrupt, syscall.SIGTERM)
+			terminado := make(chan struct{})
+			go func() {
+				defer close(terminado)
+				s := <-señales
+				registro.Info("deteniendo servidor", "señal", s.String())
+				ctx, cancelar := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancelar()
+				servidor.Shutdown(ctx)
+			}()
+
+			registro.Info("servidor iniciado", "direccion", listener.Addr().String(), "perfil", perfilActivo)
+			fmt.Printf("Servidor escuchando en http://%s (Ctrl+C para detener)\n", listener.Addr())
+			if err := servidor.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			<-terminado
+			return nil
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"finmex/calc"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoSimular corre un simulador de calc con entrada y salida en JSON
+func comandoSimular() *cli.Command {
+	return &cli.Command{
+		Name:      "simular",
+		Usage:     "Correr un simulador determinista con entrada y salida en JSON",
+		ArgsUsage: "<" + strings.Join(calc.NombresSimulaciones(), "|") + ">",
+		Description: "La entrada trae todas las tasas, la inflación y el régimen de ISR, así que no se leen\n" +
+			"tus tarjetas ni se consulta Banxico: la misma entrada siempre da la misma salida.\n" +
+			"Ejemplo: echo '{\"deuda\": 10000, \"tasa_anual\": 0.36, \"pago\": 500}' | finmex simular credito",
+		BashComplete: func(c *cli.Context) {
+			if c.NArg() == 0 {
+				for _, nombre := range calc.NombresSimulaciones() {
+					fmt.Println(nombre)
+				}
+			}
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "entrada", Usage: "Archivo JSON con la entrada; por defecto se lee de la entrada estándar"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("Indica el simulador: %s", strings.Join(calc.NombresSimulaciones(), ", "))
+			}
+			simular, ok := calc.Simulaciones[c.Args().First()]
+			if !ok {
+				return errDatosInvalidos(
+					fmt.Sprintf("No hay simulador '%s' (usa %s)", c.Args().First(), strings.Join(calc.NombresSimulaciones(), ", ")),
+					fmt.Sprintf("There is no simulator '%s' (use %s)", c.Args().First(), strings.Join(calc.NombresSimulaciones(), ", ")))
+			}
+
+			var entrada []byte
+			var err error
+			if archivo := c.String("entrada"); archivo != "" && archivo != "-" {
+				entrada, err = os.ReadFile(archivo)
+			} else {
+				entrada, err = io.ReadAll(entradaEstandar)
+			}
+			if err != nil {
+				return fmt.Errorf("Error al leer la entrada: %w", err)
+			}
+
+			resultado, err := simular(entrada)
+			if err != nil {
+				return errDatosInvalidos(err.Error(), err.Error())
+			}
+			return emitirDatos(resultado)
+		},
+	}
+}
@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoSofipo agrupa las operaciones con cuentas de Sociedades Financieras Populares
+func comandoSofipo() *cli.Command {
+	return &cli.Command{
+		Name:  "sofipo",
+		Usage: "Cuentas de alto rendimiento en SOFIPOs (Nu, Stori, Klar, SuperTasas...)",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar una cuenta de SOFIPO con sus tramos de tasa",
+				Description: "Los tramos son plazo:tasa o plazo:tasa:hasta separados por comas. El plazo es en días (0 a la vista)\n" +
+					"y hasta es el monto hasta el que aplica la tasa. Por ejemplo \"0:0.15:25000,0:0.09,28:0.10,90:0.11\"\n" +
+					"paga 15% a la vista hasta $25,000 y 9% por el resto, 10% a 28 días y 11% a 90 días.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "nombre", Usage: "Nombre de la cuenta"},
+					&cli.StringFlag{Name: "institucion", Usage: "SOFIPO (Nu, Stori, Klar, SuperTasas...)"},
+					conLimites(&cli.Float64Flag{Name: "saldo", Usage: "Saldo en la cuenta"}, limitesMonto),
+					&cli.IntFlag{Name: "plazo", Usage: "Plazo elegido en días; 0 es a la vista"},
+					&cli.StringFlag{Name: "tramos", Usage: "Tramos de tasa plazo:tasa[:hasta] separados por comas"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var cuenta CuentaSofipo
+					captura := nuevaCaptura(c)
+
+					if cuenta.Nombre, err = captura.Texto("nombre", "Nombre de la cuenta: "); err != nil {
+						return err
+					}
+					if cuenta.Institucion, err = captura.Texto("institucion", "SOFIPO (Nu, Stori, Klar, SuperTasas...): "); err != nil {
+						return err
+					}
+					if cuenta.Saldo, err = captura.Numero("saldo", "Saldo en la cuenta: ", limitesMonto); err != nil {
+						return err
+					}
+					tramos, err := captura.Texto("tramos", "Tramos de tasa (plazo:tasa[:hasta], ej: 0:0.15:25000,0:0.09,28:0.10): ")
+					if err != nil {
+						return err
+					}
+					if cuenta.Tramos, err = ParsearTramosSofipo(tramos); err != nil {
+						return err
+					}
+					if c.IsSet("plazo") {
+						cuenta.PlazoDias = c.Int("plazo")
+						if cuenta.PlazoDias < 0 || cuenta.PlazoDias > 365*30 {
+							return errDatosInvalidos("--plazo debe estar entre 0 y 10950 días", "--plazo must be between 0 and 10950 days")
+						}
+					} else if captura.interactiva {
+						if cuenta.PlazoDias, err = leerEntero("Plazo elegido en días (0 a la vista): ", 0, 365*30); err != nil {
+							return err
+						}
+					}
+
+					tarjetas.Sofipos = append(tarjetas.Sofipos, cuenta)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar cuenta: %w", err)
+					}
+
+					fmt.Printf("Cuenta de SOFIPO '%s' agregada exitosamente\n", cuenta.Nombre)
+					avisarCoberturaSofipo(tarjetas.Sofipos)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar cuentas de SOFIPO",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Sofipos) == 0 && !salidaEstructurada() {
+						fmt.Println("No hay cuentas de SOFIPO registradas")
+						return nil
+					}
+
+					inflacion := InflacionVigente()
+					if salidaEstructurada() {
+						filas := []FilaSofipo{}
+						for _, s := range tarjetas.Sofipos {
+							filas = append(filas, FilaSofipo{s, s.Rendimiento(inflacion), inflacion})
+						}
+						return emitirDatos(filas)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tInstitución\tSaldo\tPlazo\tTasa Efectiva\tTasa Neta\tTasa Real")
+					fmt.Fprintln(w, "------\t-----------\t-----\t-----\t-------------\t---------\t---------")
+					for _, s := range tarjetas.Sofipos {
+						r := s.Rendimiento(inflacion)
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%s\t%.2f%%\t%.2f%%\t%.2f%%\n",
+							s.Nombre, s.Institucion, s.Saldo, plazoSofipo(s.PlazoDias), r.TasaEfectiva*100, r.TasaNeta*100, r.TasaReal*100)
+					}
+					w.Flush()
+
+					avisarCoberturaSofipo(tarjetas.Sofipos)
+					return nil
+				},
+			},
+			{
+				Name:         "analizar",
+				Usage:        "Analizar el rendimiento real y la cobertura de una cuenta de SOFIPO",
+				ArgsUsage:    "<nombre o número>",
+				BashComplete: completarTarjetas(nombresSofipo),
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					i, err := seleccionarTarjeta(c, nombresSofipo(tarjetas), "sofipo", "cuentas de SOFIPO")
+					if err != nil {
+						return err
+					}
+
+					cuenta := tarjetas.Sofipos[i]
+					inflacion := InflacionVigente()
+					r := cuenta.Rendimiento(inflacion)
+					if salidaEstructurada() {
+						return emitirDatos(FilaSofipo{cuenta, r, inflacion})
+					}
+
+					fmt.Println("\n=== Análisis de Cuenta de SOFIPO ===")
+					fmt.Printf("Cuenta: %s (%s, %s)\n", cuenta.Nombre, cuenta.Institucion, plazoSofipo(cuenta.PlazoDias))
+					fmt.Printf("Saldo: $%.2f\n", cuenta.Saldo)
+					if len(r.Porciones) > 1 {
+						w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+						fmt.Fprintln(w, "Tramo\tTasa\tMonto\tRendimiento")
+						fmt.Fprintln(w, "-----\t----\t-----\t-----------")
+						for _, p := range r.Porciones {
+							fmt.Fprintf(w, "$%.2f - $%.2f\t%.2f%%\t$%.2f\t$%.2f\n", p.Desde, p.Hasta, p.Tasa*100, p.Monto, p.Monto*p.Tasa)
+						}
+						w.Flush()
+					}
+					if sinTasa := cuenta.Saldo - sumaPorciones(r.Porciones); sinTasa > 0 {
+						fmt.Printf("AVISO: $%.2f pasan del último tope de los tramos y no generan rendimiento\n", sinTasa)
+					}
+					fmt.Printf("Tasa efectiva: %.2f%%\n", r.TasaEfectiva*100)
+					fmt.Printf("Rendimiento bruto anual: $%.2f\n", r.Bruto)
+					fmt.Printf("Impuestos (%s): $%.2f\n", ISRVigente().Descripcion(), r.Impuestos)
+					fmt.Printf("Rendimiento neto anual: $%.2f (%.2f%%)\n", r.Neto, r.TasaNeta*100)
+					fmt.Printf("Inflación: %.2f%%\n", inflacion*100)
+					fmt.Printf("Cobertura PROSOFIPO: %d UDIS ($%.2f) por persona en %s\n", COBERTURA_PROSOFIPO_UDIS, CoberturaPROSOFIPO(), cuenta.Institucion)
+					avisarCoberturaSofipo(tarjetas.Sofipos)
+
+					if r.TasaReal > 0 {
+						fmt.Printf("RESULTADO: Tu dinero GANA valor real (%.2f%% anual sobre la inflación)\n", r.TasaReal*100)
+					} else {
+						fmt.Printf("RESULTADO: Tu dinero PIERDE valor real (%.2f%% anual contra la inflación)\n", r.TasaReal*100)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "comparar",
+				Usage: "Comparar tus cuentas de SOFIPO contra tus tarjetas de débito y CETES",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "monto", Usage: "Monto a comparar; por defecto el saldo de cada cuenta"}, limitesMonto),
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					if len(tarjetas.Sofipos) == 0 {
+						return fmt.Errorf("No hay cuentas de SOFIPO registradas")
+					}
+
+					monto := c.Float64("monto")
+					if c.IsSet("monto") {
+						if err := validarLimites(monto, limitesMonto); err != nil {
+							return fmt.Errorf("--monto: %w", err)
+						}
+					} else {
+						monto = tarjetas.Sofipos[0].Saldo
+					}
+
+					type opcion struct {
+						Nombre    string  `json:"nombre"`
+						Tipo      string  `json:"tipo"`
+						Monto     float64 `json:"monto"`
+						TasaReal  float64 `json:"tasa_real"`
+						Real      float64 `json:"ganancia_real"` // Pesos al año por encima de la inflación
+						Protegido bool    `json:"protegido"`     // Todo el monto tiene cobertura (IPAB, PROSOFIPO o gobierno federal)
+					}
+					inflacion := InflacionVigente()
+					cobertura := CoberturaPROSOFIPO()
+					var opciones []opcion
+					for _, s := range tarjetas.Sofipos {
+						if c.IsSet("monto") {
+							s.Saldo = monto
+						}
+						r := s.Rendimiento(inflacion)
+						opciones = append(opciones, opcion{s.Nombre, "sofipo", s.Saldo, r.TasaReal, s.Saldo * r.TasaReal, s.Saldo <= cobertura})
+					}
+					if monto > 0 {
+						for _, t := range tarjetas.Debito {
+							real, pct, _ := CalcularRendimientoReal(t, monto)
+							opciones = append(opciones, opcion{t.Nombre, "débito", monto, pct / 100, real, monto <= CoberturaIPAB()})
+						}
+						for _, inv := range tarjetas.Cetes {
+							inv.Monto = monto
+							r := inv.Rendimiento(inflacion)
+							opciones = append(opciones, opcion{inv.Nombre, inv.Instrumento, monto, r.TasaReal, monto * r.TasaReal, true})
+						}
+					}
+					sort.SliceStable(opciones, func(i, j int) bool { return opciones[i].TasaReal > opciones[j].TasaReal })
+					if salidaEstructurada() {
+						return emitirDatos(opciones)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tTipo\tMonto\tTasa Real\tGanancia Real Anual\tProtegido")
+					fmt.Fprintln(w, "------\t----\t-----\t---------\t-------------------\t---------")
+					for _, o := range opciones {
+						protegido := "Sí"
+						if !o.Protegido {
+							protegido = "Parcial"
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t%.2f%%\t$%.2f\t%s\n", o.Nombre, o.Tipo, o.Monto, o.TasaReal*100, o.Real, protegido)
+					}
+					w.Flush()
+
+					fmt.Printf("\nTasa real: rendimiento después de ISR y comisiones menos la inflación (%.2f%%)\n", inflacion*100)
+					if !c.IsSet("monto") && len(tarjetas.Debito)+len(tarjetas.Cetes) > 0 {
+						fmt.Printf("Las tarjetas de débito y CETES se evalúan con el saldo de '%s'; usa --monto para comparar todo con el mismo monto\n", tarjetas.Sofipos[0].Nombre)
+					}
+					fmt.Printf("RESULTADO: La mejor opción es %s (%s)\n", opciones[0].Nombre, opciones[0].Tipo)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// plazoSofipo describe el plazo de una cuenta
+func plazoSofipo(dias int) string {
+	if dias == 0 {
+		return "a la vista"
+	}
+	return fmt.Sprintf("%d días", dias)
+}
+
+// sumaPorciones regresa el saldo que cubren los tramos
+func sumaPorciones(porciones []PorcionSofipo) float64 {
+	total := 0.0
+	for _, p := range porciones {
+		total += p.Monto
+	}
+	return total
+}
@@ -0,0 +1,344 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// flagsTarjetaDebito son los datos de una tarjeta de débito que se pueden pasar como flags
+func flagsTarjetaDebito() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "nombre", Usage: "Nombre de la tarjeta"},
+		&cli.StringFlag{Name: "banco", Usage: "Banco emisor"},
+		&cli.Float64Flag{Name: "tasa", Usage: "Tasa de rendimiento anual en decimal (0.05 para 5%)"},
+		&cli.StringFlag{Name: "tramos", Usage: "Tasas escalonadas hasta:tasa separadas por comas, con el excedente al final (25000:0.15,0.08)"},
+		&cli.Float64Flag{Name: "saldo-minimo", Usage: "Saldo mínimo requerido"},
+		&cli.Float64Flag{Name: "comision-anual", Usage: "Comisión anual"},
+		&cli.Float64Flag{Name: "comision-inactividad", Usage: "Comisión mensual por inactividad"},
+		&cli.Float64Flag{Name: "saldo", Usage: "Saldo actual, en la moneda de la cuenta"},
+		&cli.StringFlag{Name: "etiquetas", Usage: "Etiquetas separadas por comas"},
+		&cli.StringFlag{Name: "moneda", Usage: "Moneda de la cuenta: MXN o USD; saldos, tramos y comisiones van en ella"},
+	}
+}
+
+// flagsTarjetaCredito son los datos de una tarjeta de crédito que se pueden pasar como flags
+func flagsTarjetaCredito() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "nombre", Usage: "Nombre de la tarjeta"},
+		&cli.StringFlag{Name: "banco", Usage: "Banco emisor"},
+		&cli.Float64Flag{Name: "tasa", Usage: "Tasa de interés anual en decimal (0.36 para 36%)"},
+		&cli.Float64Flag{Name: "cat", Usage: "CAT en decimal (0.45 para 45%)"},
+		&cli.Float64Flag{Name: "comision-anual", Usage: "Comisión anual"},
+		&cli.Float64Flag{Name: "facturacion-condonar", Usage: "Gasto anual con el que el banco bonifica la anualidad"},
+		&cli.Float64Flag{Name: "limite", Usage: "Límite de crédito"},
+		&cli.Float64Flag{Name: "cashback", Usage: "Porcentaje de cashback en decimal (0.02 para 2%)"},
+		&cli.BoolFlag{Name: "msi", Usage: "La tarjeta ofrece meses sin intereses"},
+		&cli.Float64Flag{Name: "saldo", Usage: "Deuda actual"},
+		&cli.StringFlag{Name: "etiquetas", Usage: "Etiquetas separadas por comas"},
+		&cli.IntFlag{Name: "corte", Usage: "Día de corte (1-31)"},
+		&cli.IntFlag{Name: "pago", Usage: "Día límite de pago (1-31)"},
+	}
+}
+
+// aplicarDiasCiclo guarda en la tarjeta el día de corte y el día límite de pago de
+// --corte y --pago, si se indican
+func aplicarDiasCiclo(c *cli.Context, t *TarjetaCredito) error {
+	for _, flag := range []string{"corte", "pago"} {
+		if c.IsSet(flag) && (c.Int(flag) < 1 || c.Int(flag) > 31) {
+			return errDatosInvalidos(fmt.Sprintf("--%s debe ser un día entre 1 y 31", flag),
+				fmt.Sprintf("--%s must be a day between 1 and 31", flag))
+		}
+	}
+	if c.IsSet("corte") {
+		t.DiaCorte = c.Int("corte")
+	}
+	if c.IsSet("pago") {
+		t.DiaLimitePago = c.Int("pago")
+	}
+	return nil
+}
+
+// buscarTarjeta encuentra una tarjeta por nombre o por su número en el listado (desde 1).
+// El nombre tiene prioridad por si una tarjeta se llama como un número.
+func buscarTarjeta(nombres []string, tipo, referencia string) (int, error) {
+	for i, nombre := range nombres {
+		if normalizarClave(nombre) == normalizarClave(referencia) {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(referencia); err == nil && n >= 1 && n <= len(nombres) {
+		return n - 1, nil
+	}
+	return 0, errTarjetaNoEncontrada(tipo, referencia)
+}
+
+// seleccionarTarjeta toma el producto del argumento del comando o, si no se indicó, lo
+// pregunta mostrando la lista. La descripción va en plural, p. ej. "tarjetas de débito".
+func seleccionarTarjeta(c *cli.Context, nombres []string, tipo, descripcion string) (int, error) {
+	if len(nombres) == 0 {
+		return 0, fmt.Errorf("No hay %s registradas", descripcion)
+	}
+	if c.NArg() > 0 {
+		return buscarTarjeta(nombres, tipo, argumentoNombre(c.Args().Slice()))
+	}
+
+	fmt.Printf("%s%s disponibles:\n", strings.ToUpper(descripcion[:1]), descripcion[1:])
+	for i, nombre := range nombres {
+		fmt.Printf("%d. %s\n", i+1, nombre)
+	}
+	seleccion, err := leerEntero("Selecciona una opción (número): ", 1, len(nombres))
+	if err != nil {
+		return 0, err
+	}
+	return seleccion - 1, nil
+}
+
+// confirmarEliminacion pide confirmación antes de borrar, salvo que se use --forzar
+func confirmarEliminacion(c *cli.Context, descripcion string) (bool, error) {
+	if c.Bool("forzar") {
+		return true, nil
+	}
+	return leerSiNo(fmt.Sprintf("¿Eliminar %s? Esta acción no se puede deshacer (s/n): ", descripcion))
+}
+
+// nombresDebito regresa los nombres de las tarjetas de débito en orden
+func nombresDebito(tarjetas Tarjetas) []string {
+	nombres := make([]string, len(tarjetas.Debito))
+	for i, t := range tarjetas.Debito {
+		nombres[i] = t.Nombre
+	}
+	return nombres
+}
+
+// nombresCredito regresa los nombres de las tarjetas de crédito en orden
+func nombresCredito(tarjetas Tarjetas) []string {
+	nombres := make([]string, len(tarjetas.Credito))
+	for i, t := range tarjetas.Credito {
+		nombres[i] = t.Nombre
+	}
+	return nombres
+}
+
+// comandoEditarDebito corrige los datos de una tarjeta de débito registrada
+func comandoEditarDebito() *cli.Command {
+	return &cli.Command{
+		Name:         "editar",
+		Usage:        "Corregir los datos de una tarjeta de débito",
+		ArgsUsage:    "<nombre o número>",
+		BashComplete: completarTarjetas(nombresDebito),
+		Flags:        flagsTarjetaDebito(),
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, err := seleccionarTarjeta(c, nombresDebito(tarjetas), "debito", "tarjetas de débito")
+			if err != nil {
+				return err
+			}
+
+			tarjeta := tarjetas.Debito[i]
+			captura := nuevaCaptura(c)
+			if captura.interactiva {
+				fmt.Println("Deja vacío un dato para conservar el valor actual.")
+			}
+
+			if tarjeta.Nombre, err = captura.EditarTexto("nombre", "Nombre de la tarjeta", tarjeta.Nombre); err != nil {
+				return err
+			}
+			if tarjeta.Banco, err = captura.EditarTexto("banco", "Banco emisor", tarjeta.Banco); err != nil {
+				return err
+			}
+			if tarjeta.Moneda, err = captura.Moneda("moneda", "Moneda (MXN o USD)", tarjeta.Moneda); err != nil {
+				return err
+			}
+			if tarjeta.Tramos, err = captura.EditarTramos("Tasa de rendimiento anual (decimal, o tramos hasta:tasa separados por comas)", tarjeta); err != nil {
+				return err
+			}
+			if tarjeta.SaldoMinimo, err = captura.EditarNumero("saldo-minimo", "Saldo mínimo requerido", tarjeta.SaldoMinimo, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.ComisionAnual, err = captura.EditarNumero("comision-anual", "Comisión anual", tarjeta.ComisionAnual, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.ComisionInactividad, err = captura.EditarNumero("comision-inactividad", "Comisión por inactividad (mensual)", tarjeta.ComisionInactividad, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.Saldo, err = captura.EditarNumero("saldo", "Saldo actual", tarjeta.Saldo, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.Tags, err = captura.EditarLista("etiquetas", "Etiquetas separadas por comas", tarjeta.Tags); err != nil {
+				return err
+			}
+
+			anterior := tarjetas.Debito[i].Nombre
+			tarjetas.Debito[i] = tarjeta
+			tarjetas.RenombrarHistorial("debito", anterior, tarjeta.Nombre)
+			if err := GuardarTarjetas(tarjetas); err != nil {
+				return fmt.Errorf("Error al guardar tarjeta: %w", err)
+			}
+			fmt.Printf("Tarjeta de débito '%s' actualizada\n", anterior)
+			return nil
+		},
+	}
+}
+
+// comandoEliminarDebito borra una tarjeta de débito registrada
+func comandoEliminarDebito() *cli.Command {
+	return &cli.Command{
+		Name:         "eliminar",
+		Usage:        "Eliminar una tarjeta de débito",
+		ArgsUsage:    "<nombre o número>",
+		BashComplete: completarTarjetas(nombresDebito),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "forzar", Usage: "Eliminar sin pedir confirmación"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, err := seleccionarTarjeta(c, nombresDebito(tarjetas), "debito", "tarjetas de débito")
+			if err != nil {
+				return err
+			}
+
+			tarjeta := tarjetas.Debito[i]
+			confirmado, err := confirmarEliminacion(c, fmt.Sprintf("la tarjeta de débito '%s' (%s)", tarjeta.Nombre, tarjeta.Banco))
+			if err != nil {
+				return err
+			}
+			if !confirmado {
+				fmt.Println("No se eliminó ninguna tarjeta")
+				return nil
+			}
+
+			tarjetas.Debito = append(tarjetas.Debito[:i], tarjetas.Debito[i+1:]...)
+			if err := GuardarTarjetas(tarjetas); err != nil {
+				return fmt.Errorf("Error al guardar tarjetas: %w", err)
+			}
+			fmt.Printf("Tarjeta de débito '%s' eliminada\n", tarjeta.Nombre)
+			return nil
+		},
+	}
+}
+
+// comandoEditarCredito corrige los datos de una tarjeta de crédito registrada
+func comandoEditarCredito() *cli.Command {
+	return &cli.Command{
+		Name:         "editar",
+		Usage:        "Corregir los datos de una tarjeta de crédito",
+		ArgsUsage:    "<nombre o número>",
+		BashComplete: completarTarjetas(nombresCredito),
+		Flags:        flagsTarjetaCredito(),
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, err := seleccionarTarjeta(c, nombresCredito(tarjetas), "credito", "tarjetas de crédito")
+			if err != nil {
+				return err
+			}
+
+			tarjeta := tarjetas.Credito[i]
+			captura := nuevaCaptura(c)
+			if captura.interactiva {
+				fmt.Println("Deja vacío un dato para conservar el valor actual.")
+			}
+
+			if tarjeta.Nombre, err = captura.EditarTexto("nombre", "Nombre de la tarjeta", tarjeta.Nombre); err != nil {
+				return err
+			}
+			if tarjeta.Banco, err = captura.EditarTexto("banco", "Banco emisor", tarjeta.Banco); err != nil {
+				return err
+			}
+			if tarjeta.TasaInteres, err = captura.EditarNumero("tasa", "Tasa de interés anual (decimal)", tarjeta.TasaInteres, limitesTasa); err != nil {
+				return err
+			}
+			if tarjeta.CAT, err = captura.EditarNumero("cat", "CAT (decimal)", tarjeta.CAT, limitesTasa); err != nil {
+				return err
+			}
+			if tarjeta.ComisionAnual, err = captura.EditarNumero("comision-anual", "Comisión anual", tarjeta.ComisionAnual, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.FacturacionParaCondonar, err = captura.EditarNumero("facturacion-condonar", "Facturación anual con la que te bonifican la anualidad", tarjeta.FacturacionParaCondonar, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.LimiteCredito, err = captura.EditarNumero("limite", "Límite de crédito", tarjeta.LimiteCredito, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.BeneficiosCashback, err = captura.EditarNumero("cashback", "Porcentaje de cashback (decimal)", tarjeta.BeneficiosCashback, limitesTasa); err != nil {
+				return err
+			}
+			if tarjeta.MesesSinIntereses, err = captura.EditarSiNo("msi", "¿Ofrece meses sin intereses? (s/n)", tarjeta.MesesSinIntereses); err != nil {
+				return err
+			}
+			if tarjeta.Saldo, err = captura.EditarNumero("saldo", "Deuda actual", tarjeta.Saldo, limitesMonto); err != nil {
+				return err
+			}
+			if tarjeta.Tags, err = captura.EditarLista("etiquetas", "Etiquetas separadas por comas", tarjeta.Tags); err != nil {
+				return err
+			}
+			if err := aplicarDiasCiclo(c, &tarjeta); err != nil {
+				return err
+			}
+
+			anterior := tarjetas.Credito[i].Nombre
+			tarjetas.Credito[i] = tarjeta
+			tarjetas.RenombrarHistorial("credito", anterior, tarjeta.Nombre)
+			if err := GuardarTarjetas(tarjetas); err != nil {
+				return fmt.Errorf("Error al guardar tarjeta: %w", err)
+			}
+			fmt.Printf("Tarjeta de crédito '%s' actualizada\n", anterior)
+			return nil
+		},
+	}
+}
+
+// comandoEliminarCredito borra una tarjeta de crédito registrada
+func comandoEliminarCredito() *cli.Command {
+	return &cli.Command{
+		Name:         "eliminar",
+		Usage:        "Eliminar una tarjeta de crédito",
+		ArgsUsage:    "<nombre o número>",
+		BashComplete: completarTarjetas(nombresCredito),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "forzar", Usage: "Eliminar sin pedir confirmación"},
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, err := seleccionarTarjeta(c, nombresCredito(tarjetas), "credito", "tarjetas de crédito")
+			if err != nil {
+				return err
+			}
+
+			tarjeta := tarjetas.Credito[i]
+			descripcion := fmt.Sprintf("la tarjeta de crédito '%s' (%s)", tarjeta.Nombre, tarjeta.Banco)
+			if tarjeta.Saldo > 0 {
+				fmt.Printf("AVISO: '%s' tiene una deuda registrada de $%.2f\n", tarjeta.Nombre, tarjeta.Saldo)
+			}
+			confirmado, err := confirmarEliminacion(c, descripcion)
+			if err != nil {
+				return err
+			}
+			if !confirmado {
+				fmt.Println("No se eliminó ninguna tarjeta")
+				return nil
+			}
+
+			tarjetas.Credito = append(tarjetas.Credito[:i], tarjetas.Credito[i+1:]...)
+			if err := GuardarTarjetas(tarjetas); err != nil {
+				return fmt.Errorf("Error al guardar tarjetas: %w", err)
+			}
+			fmt.Printf("Tarjeta de crédito '%s' eliminada\n", tarjeta.Nombre)
+			return nil
+		},
+	}
+}
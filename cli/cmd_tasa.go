@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoTasa agrupa las calculadoras de tasas
+func comandoTasa() *cli.Command {
+	return &cli.Command{
+		Name:  "tasa",
+		Usage: "Calculadoras de tasas de interés y rendimiento",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "convertir",
+				Usage: "Convertir una tasa nominal o efectiva a tasa efectiva anual, mensual y diaria",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "nominal", Usage: "Tasa nominal anual (decimal)"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "efectiva", Usage: "Tasa efectiva anual (decimal), en lugar de --nominal"}, limitesTasa),
+					&cli.StringFlag{Name: "capitalizacion", Value: "mensual", Usage: "Capitalización de la tasa nominal: diaria, semanal, quincenal, mensual, trimestral, anual..."},
+				},
+				Action: func(c *cli.Context) error {
+					periodos, err := calc.ParsearCapitalizacion(c.String("capitalizacion"))
+					if err != nil {
+						return err
+					}
+
+					var conversion ConversionTasa
+					switch {
+					case c.IsSet("nominal") && c.IsSet("efectiva"):
+						return fmt.Errorf("Usa --nominal o --efectiva, no ambas")
+					case c.IsSet("efectiva"):
+						conversion = calc.ConvertirTasa(calc.NominalDesdeEfectiva(c.Float64("efectiva"), periodos), periodos)
+					case c.IsSet("nominal"):
+						conversion = calc.ConvertirTasa(c.Float64("nominal"), periodos)
+					default:
+						return fmt.Errorf("Indica la tasa con --nominal o --efectiva")
+					}
+
+					fmt.Println("=== Conversión de Tasa ===")
+					fmt.Printf("Tasa nominal anual (capitalización %s): %.4f%%\n", c.String("capitalizacion"), conversion.Nominal*100)
+					fmt.Printf("Tasa por periodo de capitalización: %.4f%%\n", conversion.Nominal/float64(conversion.Periodos)*100)
+					fmt.Printf("Tasa efectiva anual: %.4f%%\n", conversion.EfectivaAnual*100)
+					fmt.Printf("Tasa efectiva mensual: %.4f%%\n", conversion.Mensual*100)
+					fmt.Printf("Tasa efectiva diaria: %.6f%%\n", conversion.Diaria*100)
+					return nil
+				},
+			},
+			{
+				Name:  "cat",
+				Usage: "Convertir un CAT a la tasa mensual y anual que cobra la deuda, sin y con IVA",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "cat", Usage: "CAT publicado (decimal)"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "mensual", Usage: "Tasa mensual sin IVA (decimal), para obtener el CAT"}, limitesTasa),
+					conLimites(&cli.Float64Flag{Name: "nominal", Usage: "Tasa anual sin IVA (decimal, mensual por 12), para obtener el CAT"}, limitesTasa),
+				},
+				Action: func(c *cli.Context) error {
+					var e EquivalenciaCAT
+					dadas := 0
+					for _, nombre := range []string{"cat", "mensual", "nominal"} {
+						if c.IsSet(nombre) {
+							dadas++
+						}
+					}
+					if dadas != 1 {
+						return fmt.Errorf("Indica solo una de --cat, --mensual o --nominal")
+					}
+
+					switch {
+					case c.IsSet("cat"):
+						e = calc.EquivalenciaDesdeCAT(c.Float64("cat"))
+					case c.IsSet("mensual"):
+						e = calc.EquivalenciaDesdeMensual(c.Float64("mensual"))
+					default:
+						e = calc.EquivalenciaDesdeMensual(c.Float64("nominal") / 12)
+					}
+
+					fmt.Println("=== Equivalencia CAT ===")
+					fmt.Printf("CAT (sin IVA): %.2f%%\n", e.CAT*100)
+					fmt.Println("\nSin IVA:")
+					fmt.Printf("  Tasa mensual: %.4f%%\n", e.MensualSinIVA*100)
+					fmt.Printf("  Tasa anual (mensual x 12): %.2f%%\n", e.NominalSinIVA*100)
+					fmt.Printf("\nCon IVA de %.0f%% sobre intereses:\n", IVA*100)
+					fmt.Printf("  Tasa mensual: %.4f%%\n", e.MensualConIVA*100)
+					fmt.Printf("  Tasa anual (mensual x 12): %.2f%%\n", e.NominalConIVA*100)
+					fmt.Printf("  Costo efectivo anual: %.2f%%\n", e.EfectivaConIVA*100)
+					fmt.Printf("\nUna deuda de $10,000 genera $%.2f de intereses más $%.2f de IVA el primer mes\n",
+						10000*e.MensualSinIVA, 10000*e.MensualSinIVA*IVA)
+					return nil
+				},
+			},
+			{
+				Name:  "requerida",
+				Usage: "Calcular la tasa real necesaria para alcanzar un objetivo de ahorro",
+				Flags: []cli.Flag{
+					conLimites(&cli.Float64Flag{Name: "inicial", Usage: "Monto inicial"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "aportacion", Usage: "Aportación mensual"}, limitesMonto),
+					conLimites(&cli.Float64Flag{Name: "objetivo", Usage: "Monto objetivo en pesos de hoy"}, limitesMonto),
+					&cli.IntFlag{Name: "anios", Usage: "Años para alcanzar el objetivo"},
+				},
+				Action: func(c *cli.Context) error {
+					inicial, aportacion, objetivo := c.Float64("inicial"), c.Float64("aportacion"), c.Float64("objetivo")
+					años := c.Int("anios")
+
+					var err error
+					if !c.IsSet("inicial") {
+						if inicial, err = leerNumero("Monto inicial: ", limitesMonto); err != nil {
+							return err
+						}
+					}
+					if !c.IsSet("aportacion") {
+						if aportacion, err = leerNumero("Aportación mensual: ", limitesMonto); err != nil {
+							return err
+						}
+					}
+					if objetivo <= 0 {
+						if objetivo, err = leerNumero("Monto objetivo (en pesos de hoy): ", limitesMonto); err != nil {
+							return err
+						}
+					}
+					if años <= 0 {
+						if años, err = leerEntero("Años para alcanzarlo: ", 1, 100); err != nil {
+							return err
+						}
+					}
+
+					// El objetivo está en pesos de hoy, así que la tasa que resulta es real
+					tasaReal, err := calc.TasaRequerida(inicial, aportacion, objetivo, años*12)
+					if err != nil {
+						return err
+					}
+
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+					catalogo, err := CargarCatalogo()
+					if err != nil {
+						return err
+					}
+
+					aportado := inicial + aportacion*float64(años*12)
+					fmt.Println("\n=== Tasa Requerida ===")
+					fmt.Printf("Aportas $%.2f en total para llegar a $%.2f en %d años\n", aportado, objetivo, años)
+					fmt.Printf("Tasa real necesaria: %.2f%% anual\n", tasaReal*100)
+					fmt.Printf("Equivale a una tasa bruta de %.2f%% con %s e inflación de %.1f%%\n\n",
+						ISRVigente().TasaBruta(tasaReal+InflacionVigente(), InflacionVigente())*100, ISRVigente().Descripcion(), InflacionVigente()*100)
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Producto\tOrigen\tTasa\tNeta ISR\tReal\tAlcanza")
+					fmt.Fprintln(w, "--------\t------\t----\t--------\t----\t-------")
+
+					alcanzan := 0
+					for _, p := range ProductosRendimiento(tarjetas, catalogo) {
+						alcanza := "No"
+						if p.Real(InflacionVigente()) >= tasaReal {
+							alcanza = "Sí"
+							alcanzan++
+						}
+						fmt.Fprintf(w, "%s\t%s\t%.2f%%\t%.2f%%\t%.2f%%\t%s\n",
+							p.Nombre, p.Origen, p.Tasa*100, p.Neta()*100, p.Real(InflacionVigente())*100, alcanza)
+					}
+					w.Flush()
+
+					if alcanzan == 0 {
+						fmt.Println("\nRESULTADO: Ningún producto alcanza la tasa necesaria; aumenta la aportación o el plazo")
+					} else {
+						fmt.Printf("\nRESULTADO: %d producto(s) alcanzan la tasa real necesaria después de impuestos\n", alcanzan)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoCreditoTransferencia simula traspasar la deuda de una tarjeta a otra con promoción
+func comandoCreditoTransferencia() *cli.Command {
+	return &cli.Command{
+		Name:  "transferencia",
+		Usage: "Comparar quedarte con tu deuda contra transferirla a otra tarjeta con promoción",
+		Description: "La comisión del traspaso, más IVA, se carga al saldo transferido. Durante los meses de\n" +
+			"promoción aplica --tasa-promocional y después la tasa de la tarjeta destino. En los dos\n" +
+			"casos se paga lo mismo cada mes (--pago), ajustado al pago mínimo.\n" +
+			"Ejemplo: finmex credito transferencia --origen Oro --destino Azul --comision 0.03 --meses 12 --pago 2000",
+		BashComplete: func(c *cli.Context) {
+			if previo := argumentoPrevioCompletado(); previo == "--origen" || previo == "--destino" {
+				imprimirNombresCompletado(c, nombresCredito)
+				return
+			}
+			cli.DefaultCompleteWithFlags(c.Command)(c)
+		},
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "origen", Required: true, Usage: "Tarjeta con la deuda"},
+			&cli.StringFlag{Name: "destino", Required: true, Usage: "Tarjeta que recibe el traspaso"},
+			conLimites(&cli.Float64Flag{Name: "deuda", Usage: "Monto a transferir, por defecto el saldo de la tarjeta origen"}, limitesMonto),
+			conLimites(&cli.Float64Flag{Name: "comision", Usage: "Comisión por el traspaso en decimal, sin IVA (0.03 para 3%)"}, limitesFraccion),
+			conLimites(&cli.Float64Flag{Name: "tasa-promocional", Usage: "Tasa anual durante la promoción en decimal; 0 es sin intereses"}, limitesTasa),
+			&cli.IntFlag{Name: "meses", Value: 12, Usage: "Meses de la promoción"},
+			conLimites(&cli.Float64Flag{Name: "pago", Usage: "Pago mensual que harás en cualquiera de las dos tarjetas"}, limitesMonto),
+		},
+		Action: func(c *cli.Context) error {
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			i, ok := indiceCredito(tarjetas, c.String("origen"))
+			if !ok {
+				return errTarjetaNoEncontrada("credito", c.String("origen"))
+			}
+			j, ok := indiceCredito(tarjetas, c.String("destino"))
+			if !ok {
+				return errTarjetaNoEncontrada("credito", c.String("destino"))
+			}
+			if i == j {
+				return errDatosInvalidos("La tarjeta destino debe ser distinta de la de origen", "The destination card must differ from the source card")
+			}
+			origen, destino := tarjetas.Credito[i], tarjetas.Credito[j]
+
+			meses := c.Int("meses")
+			if meses < 1 || meses > 60 {
+				return errDatosInvalidos(fmt.Sprintf("--meses debe estar entre 1 y 60, no %d", meses), fmt.Sprintf("--meses must be between 1 and 60, not %d", meses))
+			}
+			deuda := origen.Saldo
+			if c.IsSet("deuda") {
+				deuda = c.Float64("deuda")
+			}
+			if deuda <= 0 {
+				return fmt.Errorf("%s no tiene deuda registrada; indica el monto con --deuda", origen.Nombre)
+			}
+			promocion := TransferenciaSaldo{Comision: c.Float64("comision"), TasaPromocional: c.Float64("tasa-promocional"), MesesPromocion: meses}
+
+			pago, err := nuevaCaptura(c).Numero("pago", "Pago mensual que harás: ", limitesMonto)
+			if err != nil {
+				return err
+			}
+
+			s := SimularTransferencia(origen, destino, deuda, pago, promocion)
+			if salidaEstructurada() {
+				return emitirDatos(s)
+			}
+
+			fmt.Printf("=== Transferencia de saldo: %s a %s ===\n", origen.Nombre, destino.Nombre)
+			fmt.Printf("Deuda a transferir: $%.2f, pago mensual de $%.2f\n", deuda, pago)
+			fmt.Printf("Promoción: %d meses al %.2f%% con comisión de %.2f%% más IVA, después %.2f%%\n\n",
+				meses, promocion.TasaPromocional*100, promocion.Comision*100, destino.TasaInteres*100)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+			fmt.Fprintln(w, "Opción\tTasa\tComisión\tIntereses\tPagos\tCosto Total")
+			fmt.Fprintln(w, "------\t----\t--------\t---------\t-----\t-----------")
+			fmt.Fprintf(w, "Quedarte en %s\t%.2f%%\t$%.2f\t$%.2f\t%d\t$%.2f\n",
+				origen.Nombre, origen.TasaInteres*100, s.Actual.Comision, s.Actual.Intereses, s.Actual.Pagos, s.Actual.Total)
+			fmt.Fprintf(w, "Transferir a %s\t%.2f%%\t$%.2f\t$%.2f\t%d\t$%.2f\n",
+				destino.Nombre, promocion.TasaPromocional*100, s.Transferencia.Comision, s.Transferencia.Intereses, s.Transferencia.Pagos, s.Transferencia.Total)
+			w.Flush()
+
+			if s.Conviene {
+				fmt.Printf("\nRESULTADO: Transfiere a %s: ahorras $%.2f netos en intereses\n", destino.Nombre, s.Ahorro)
+			} else {
+				fmt.Printf("\nRESULTADO: Quédate en %s: transferir te cuesta $%.2f más\n", origen.Nombre, -s.Ahorro)
+			}
+			if s.Transferencia.SaldoFinPromocion > 0 {
+				fmt.Printf("AVISO: Al terminar la promoción quedarán $%.2f al %.2f%%; con $%.2f al mes lo liquidas dentro de la promoción\n",
+					s.Transferencia.SaldoFinPromocion, destino.TasaInteres*100, s.PagoPromocion)
+			}
+			if destino.LimiteCredito > 0 {
+				if disponible := destino.LimiteCredito - destino.Saldo; deuda+s.Transferencia.Comision > disponible {
+					fmt.Printf("ALERTA: %s solo tiene $%.2f disponibles; el traspaso con comisión es de $%.2f\n",
+						destino.Nombre, disponible, deuda+s.Transferencia.Comision)
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"finmex/calc"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/urfave/cli/v2"
+)
+
+// terminalTUI es la salida estándar con la que arrancó el programa. --privado y --output
+// redirigen os.Stdout, pero la interfaz necesita la terminal y oculta los montos ella misma.
+var terminalTUI = os.Stdout
+
+// comandoTUI abre la interfaz de terminal
+func comandoTUI() *cli.Command {
+	return &cli.Command{
+		Name:  "tui",
+		Usage: "Abrir una interfaz de terminal para agregar, analizar y comparar tarjetas",
+		Action: func(c *cli.Context) error {
+			if salidaEstructurada() {
+				return fmt.Errorf("La interfaz de terminal no se puede usar con --output %s", formatoDatos)
+			}
+
+			tarjetas, err := CargarTarjetas()
+			if err != nil {
+				return fmt.Errorf("Error al cargar tarjetas: %w", err)
+			}
+			catalogo, err := CargarCatalogo()
+			if err != nil {
+				return err
+			}
+
+			m := &modeloTUI{
+				tarjetas: tarjetas,
+				catalogo: catalogo,
+				privado:  c.Bool("privado"),
+				color:    os.Getenv("NO_COLOR") == "",
+			}
+			m.abrir(menuPrincipalTUI())
+
+			programa := tea.NewProgram(m, tea.WithAltScreen(), tea.WithInput(os.Stdin), tea.WithOutput(terminalTUI))
+			_, err = programa.Run()
+			return err
+		},
+	}
+}
+
+// menuPrincipalTUI es la primera pantalla de la interfaz
+func menuPrincipalTUI() *menuTUI {
+	return &menuTUI{
+		titulo: "¿Qué quieres hacer?",
+		opciones: []opcionMenu{
+			{"Tarjetas de débito", tablaDebitoTUI},
+			{"Tarjetas de crédito", tablaCreditoTUI},
+			{"Agregar tarjeta de débito", func(*modeloTUI) vistaTUI { return formularioDebitoTUI() }},
+			{"Agregar tarjeta de crédito", func(*modeloTUI) vistaTUI { return formularioCreditoTUI() }},
+			{"Comparar tarjetas de débito", compararDebitoTUI},
+			{"Comparar tarjetas de crédito", compararCreditoTUI},
+			{"Salir", nil},
+		},
+	}
+}
+
+// montoTUI da formato a un monto como en las tablas de la línea de comandos
+func montoTUI(monto float64) string {
+	return fmt.Sprintf("$%.2f", monto)
+}
+
+// porcentajeTUI da formato a una tasa en decimal
+func porcentajeTUI(tasa float64) string {
+	return fmt.Sprintf("%.2f%%", tasa*100)
+}
+
+// valorInicialTUI propone un monto registrado en un formulario, vacío si es cero
+func valorInicialTUI(monto float64) string {
+	if monto == 0 {
+		return ""
+	}
+	return formatearNumero(monto)
+}
+
+// tablaDebitoTUI lista las cuentas de débito; enter pide el saldo para analizarla
+func tablaDebitoTUI(m *modeloTUI) vistaTUI {
+	tabla := &tablaTUI{
+		titulo:      "Tarjetas de débito",
+		encabezados: []string{"Nombre", "Banco", "Rendimiento", "Saldo", "Comisión Anual", "Vs Mercado"},
+		detalle: func(m *modeloTUI, i int) vistaTUI {
+			return formularioAnalisisDebitoTUI(m.tarjetas.Debito[i])
+		},
+	}
+	for _, t := range m.tarjetas.Debito {
+		tabla.filas = append(tabla.filas, []string{
+			t.Nombre, t.Banco, porcentajeTUI(t.TasaPonderada(t.Saldo)), montoTUI(t.Saldo), montoTUI(t.ComisionAnual),
+			PosicionDebito(t, m.catalogo).Descripcion(),
+		})
+	}
+	return tabla
+}
+
+// tablaCreditoTUI lista las tarjetas de crédito; enter pide la deuda y el pago para analizarla
+func tablaCreditoTUI(m *modeloTUI) vistaTUI {
+	tabla := &tablaTUI{
+		titulo:      "Tarjetas de crédito",
+		encabezados: []string{"Nombre", "Banco", "Interés", "CAT", "Límite", "Deuda", "MSI", "Vs Mercado"},
+		detalle: func(m *modeloTUI, i int) vistaTUI {
+			return formularioAnalisisCreditoTUI(m.tarjetas.Credito[i])
+		},
+	}
+	for _, t := range m.tarjetas.Credito {
+		msi := "No"
+		if t.MesesSinIntereses {
+			msi = "Sí"
+		}
+		tabla.filas = append(tabla.filas, []string{
+			t.Nombre, t.Banco, porcentajeTUI(t.TasaInteres), porcentajeTUI(t.CAT), montoTUI(t.LimiteCredito),
+			montoTUI(t.Saldo), msi, PosicionCredito(t, m.catalogo).Descripcion(),
+		})
+	}
+	return tabla
+}
+
+// formularioDebitoTUI captura una cuenta de débito nueva con los datos de debito agregar
+func formularioDebitoTUI() *formularioTUI {
+	return nuevoFormularioTUI("Agregar tarjeta de débito", []campoTUI{
+		{Etiqueta: "Nombre", Tipo: campoTexto},
+		{Etiqueta: "Banco", Tipo: campoTexto},
+		{Etiqueta: "Tasa anual (0.05 = 5%)", Tipo: campoNumero, Limites: limitesTasa},
+		{Etiqueta: "Saldo mínimo", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+		{Etiqueta: "Comisión anual", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+		{Etiqueta: "Comisión por inactividad", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+		{Etiqueta: "Saldo actual", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+		{Etiqueta: "Etiquetas", Tipo: campoLista},
+	}, func(m *modeloTUI, f *formularioTUI) error {
+		t := TarjetaDebito{
+			Nombre:              f.Texto(0),
+			Banco:               f.Texto(1),
+			Tramos:              calc.TasaUnica(f.Numero(2)),
+			SaldoMinimo:         f.Numero(3),
+			ComisionAnual:       f.Numero(4),
+			ComisionInactividad: f.Numero(5),
+			Saldo:               f.Numero(6),
+			Tags:                f.Lista(7),
+		}
+		tarjetas := m.tarjetas
+		tarjetas.Debito = append(append([]TarjetaDebito(nil), tarjetas.Debito...), t)
+		if err := GuardarTarjetas(tarjetas); err != nil {
+			return fmt.Errorf("Error al guardar tarjeta: %v", err)
+		}
+		m.tarjetas = tarjetas
+		m.aviso = fmt.Sprintf("Tarjeta de débito '%s' agregada exitosamente", t.Nombre)
+		m.regresar()
+		return nil
+	})
+}
+
+// formularioCreditoTUI captura una tarjeta de crédito nueva con los datos de credito agregar
+func formularioCreditoTUI() *formularioTUI {
+	return nuevoFormularioTUI("Agregar tarjeta de crédito", []campoTUI{
+		{Etiqueta: "Nombre", Tipo: campoTexto},
+		{Etiqueta: "Banco", Tipo: campoTexto},
+		{Etiqueta: "Tasa de interés anual (0.36 = 36%)", Tipo: campoNumero, Limites: limitesTasa},
+		{Etiqueta: "CAT (0.45 = 45%)", Tipo: campoNumero, Limites: limitesTasa},
+		{Etiqueta: "Comisión anual", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+		{Etiqueta: "Límite de crédito", Tipo: campoNumero, Limites: limitesMonto},
+		{Etiqueta: "Cashback (0.02 = 2%)", Tipo: campoNumeroOpcional, Limites: limitesTasa},
+		{Etiqueta: "¿Meses sin intereses? (s/n)", Tipo: campoSiNo},
+		{Etiqueta: "Deuda actual", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+		{Etiqueta: "Etiquetas", Tipo: campoLista},
+	}, func(m *modeloTUI, f *formularioTUI) error {
+		t := TarjetaCredito{
+			Nombre:             f.Texto(0),
+			Banco:              f.Texto(1),
+			TasaInteres:        f.Numero(2),
+			CAT:                f.Numero(3),
+			ComisionAnual:      f.Numero(4),
+			LimiteCredito:      f.Numero(5),
+			BeneficiosCashback: f.Numero(6),
+			MesesSinIntereses:  f.SiNo(7),
+			Saldo:              f.Numero(8),
+			Tags:               f.Lista(9),
+		}
+		tarjetas := m.tarjetas
+		tarjetas.Credito = append(append([]TarjetaCredito(nil), tarjetas.Credito...), t)
+		if err := GuardarTarjetas(tarjetas); err != nil {
+			return fmt.Errorf("Error al guardar tarjeta: %v", err)
+		}
+		m.tarjetas = tarjetas
+		m.aviso = fmt.Sprintf("Tarjeta de crédito '%s' agregada exitosamente", t.Nombre)
+		m.regresar()
+		return nil
+	})
+}
+
+// formularioAnalisisDebitoTUI pide el saldo y muestra el rendimiento real de un año
+func formularioAnalisisDebitoTUI(t TarjetaDebito) vistaTUI {
+	f := nuevoFormularioTUI("Analizar "+t.Nombre, []campoTUI{
+		{Etiqueta: "Saldo a analizar", Tipo: campoNumero, Limites: limitesMonto},
+	}, func(m *modeloTUI, f *formularioTUI) error {
+		m.reemplazar(nuevoTextoTUI("Análisis de rendimiento", textoAnalisisDebito(analisisDebito(t, f.Numero(0)))))
+		return nil
+	})
+	return f.conValor(0, valorInicialTUI(t.Saldo))
+}
+
+// textoAnalisisDebito presenta el análisis con los mismos renglones que debito analizar
+func textoAnalisisDebito(a AnalisisDebito) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tarjeta: %s (%s)\n", a.Nombre, a.Banco)
+	fmt.Fprintf(&b, "Tasa nominal: %s\n", DescribirTramos(TarjetaDebito{Tramos: a.Tramos}))
+	if tramosEscalonados(a.Tramos) {
+		fmt.Fprintf(&b, "Tasa ponderada con el saldo: %.2f%%\n", a.TasaRendimiento*100)
+	}
+	fmt.Fprintf(&b, "Saldo inicial: $%.2f\n", a.Saldo)
+	fmt.Fprintf(&b, "Rendimiento bruto anual: $%.2f\n", a.RendimientoBruto)
+	fmt.Fprintf(&b, "Impuestos (%s): $%.2f\n", ISRVigente().Descripcion(), a.Impuestos)
+	fmt.Fprintf(&b, "Pérdida por inflación (%.1f%%): $%.2f\n", a.Inflacion*100, a.PerdidaInflacion)
+	fmt.Fprintf(&b, "Comisión anual: $%.2f\n", a.ComisionAnual)
+	fmt.Fprintf(&b, "Rendimiento real anual: $%.2f (%.2f%%)\n", a.RendimientoReal, a.RendimientoRealPct)
+	if a.SaldoEquilibrio != nil {
+		fmt.Fprintf(&b, "Saldo de equilibrio: $%.2f (a partir de ahí el rendimiento real es positivo)\n", *a.SaldoEquilibrio)
+	} else {
+		fmt.Fprintln(&b, "Saldo de equilibrio: ninguno, la tasa neta de ISR no supera la inflación")
+	}
+	if a.Gana {
+		fmt.Fprintf(&b, "\nRESULTADO: Tu dinero GANA valor real ($%.2f después de un año)\n", a.SaldoFinal)
+	} else {
+		fmt.Fprintf(&b, "\nRESULTADO: Tu dinero PIERDE valor real ($%.2f después de un año)\n", a.SaldoFinal)
+	}
+	return b.String()
+}
+
+// formularioAnalisisCreditoTUI pide la deuda y el pago mensual y muestra el costo
+func formularioAnalisisCreditoTUI(t TarjetaCredito) vistaTUI {
+	f := nuevoFormularioTUI("Analizar "+t.Nombre, []campoTUI{
+		{Etiqueta: "Deuda o compra", Tipo: campoNumero, Limites: limitesMonto},
+		{Etiqueta: "Pago mensual (vacío = mínimo)", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+	}, func(m *modeloTUI, f *formularioTUI) error {
+		deuda := f.Numero(0)
+		pago, ajustado := pagoConMinimo(deuda, f.Numero(1), t)
+		_, pagos, _ := calc.CostoCreditoFrecuencia(t, deuda, pago, FrecuenciaMensual)
+		calendario := calc.CalendarioPagos(time.Now(), FrecuenciaMensual, pagos)
+		a := analisisCredito(t, deuda, pago, FrecuenciaMensual, calendario)
+		texto := textoAnalisisCredito(a)
+		if ajustado {
+			texto = fmt.Sprintf("AVISO: El pago es menor al pago mínimo. Se ajustó a $%.2f\n\n", pago) + texto
+		}
+		m.reemplazar(nuevoTextoTUI("Análisis de crédito", texto))
+		return nil
+	})
+	return f.conValor(0, valorInicialTUI(t.Saldo))
+}
+
+// pagoConMinimo sube el pago mensual al pago mínimo si no lo alcanza. Con varias tarjetas se
+// usa el mínimo más alto, para compararlas con el mismo pago.
+func pagoConMinimo(deuda, pago float64, tarjetas ...TarjetaCredito) (float64, bool) {
+	minimo := 0.0
+	for _, t := range tarjetas {
+		minimo = math.Max(minimo, calc.PagoMinimoPeriodo(t, deuda, FrecuenciaMensual))
+	}
+	if pago < minimo {
+		return minimo, pago > 0
+	}
+	return pago, false
+}
+
+// textoAnalisisCredito presenta el análisis con los mismos renglones que credito analizar
+func textoAnalisisCredito(a AnalisisCredito) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tarjeta: %s (%s)\n", a.Nombre, a.Banco)
+	fmt.Fprintf(&b, "Deuda/Compra: $%.2f\n", a.Deuda)
+	fmt.Fprintf(&b, "Tasa de interés anual: %.2f%% (efectiva %.2f%% con capitalización mensual)\n", a.TasaInteres*100, a.TasaEfectiva*100)
+	fmt.Fprintf(&b, "CAT: %.2f%%\n", a.CAT*100)
+	fmt.Fprintf(&b, "Pago %s: $%.2f\n", a.Frecuencia, a.Pago)
+	fmt.Fprintf(&b, "Tiempo para liquidar: %d meses (%.1f años)\n", a.Pagos, a.Meses/12)
+	if a.PrimerPago != "" {
+		fmt.Fprintf(&b, "Primer pago: %s | Último pago: %s\n", a.PrimerPago, a.UltimoPago)
+	}
+	fmt.Fprintf(&b, "Costo total del crédito: $%.2f (%.2f%% del monto original)\n", a.CostoTotal, a.CostoPct)
+	fmt.Fprintf(&b, "Monto total pagado: $%.2f\n", a.MontoTotal)
+	return b.String()
+}
+
+// compararDebitoTUI pide el saldo y ordena las cuentas por rendimiento real
+func compararDebitoTUI(m *modeloTUI) vistaTUI {
+	if len(m.tarjetas.Debito) < 2 {
+		m.aviso = "Se necesitan al menos 2 tarjetas de débito para comparar"
+		return nil
+	}
+	return nuevoFormularioTUI("Comparar tarjetas de débito", []campoTUI{
+		{Etiqueta: "Saldo a comparar", Tipo: campoNumero, Limites: limitesMonto},
+	}, func(m *modeloTUI, f *formularioTUI) error {
+		saldo := f.Numero(0)
+		resultados := make([]ResultadoComparacionDebito, len(m.tarjetas.Debito))
+		for i, t := range m.tarjetas.Debito {
+			resultados[i] = resultadoComparacionDebito(t, saldo)
+		}
+		sort.SliceStable(resultados, func(i, j int) bool { return resultados[i].RendimientoReal > resultados[j].RendimientoReal })
+
+		tabla := &tablaTUI{
+			titulo:      fmt.Sprintf("Comparación con saldo de $%.2f", saldo),
+			encabezados: []string{"Tarjeta", "Banco", "Tasa", "Rendimiento Real", "Saldo Final"},
+			pie:         fmt.Sprintf("RESULTADO: %s da el mayor rendimiento real", resultados[0].Nombre),
+		}
+		for _, r := range resultados {
+			tabla.filas = append(tabla.filas, []string{
+				r.Nombre, r.Banco, porcentajeTUI(r.TasaRendimiento),
+				fmt.Sprintf("$%.2f (%.2f%%)", r.RendimientoReal, r.RendimientoRealPct), montoTUI(r.SaldoFinal),
+			})
+		}
+		m.reemplazar(tabla)
+		return nil
+	})
+}
+
+// compararCreditoTUI pide la deuda y el pago y ordena las tarjetas por costo total
+func compararCreditoTUI(m *modeloTUI) vistaTUI {
+	if len(m.tarjetas.Credito) < 2 {
+		m.aviso = "Se necesitan al menos 2 tarjetas de crédito para comparar"
+		return nil
+	}
+	return nuevoFormularioTUI("Comparar tarjetas de crédito", []campoTUI{
+		{Etiqueta: "Deuda o compra", Tipo: campoNumero, Limites: limitesMonto},
+		{Etiqueta: "Pago mensual (vacío = mínimo)", Tipo: campoNumeroOpcional, Limites: limitesMonto},
+	}, func(m *modeloTUI, f *formularioTUI) error {
+		deuda := f.Numero(0)
+		pago, _ := pagoConMinimo(deuda, f.Numero(1), m.tarjetas.Credito...)
+		resultados := make([]ResultadoComparacionCredito, len(m.tarjetas.Credito))
+		for i, t := range m.tarjetas.Credito {
+			resultados[i] = resultadoComparacionCredito(t, deuda, pago, FrecuenciaMensual)
+		}
+		sort.SliceStable(resultados, func(i, j int) bool { return resultados[i].CostoTotal < resultados[j].CostoTotal })
+
+		tabla := &tablaTUI{
+			titulo:      fmt.Sprintf("Comparación de $%.2f pagando $%.2f al mes", deuda, pago),
+			encabezados: []string{"Tarjeta", "Banco", "CAT", "Costo Total", "Meses", "Cashback"},
+			pie:         fmt.Sprintf("RESULTADO: %s es la más barata para esta deuda", resultados[0].Nombre),
+		}
+		for _, r := range resultados {
+			tabla.filas = append(tabla.filas, []string{
+				r.Nombre, r.Banco, porcentajeTUI(r.CAT), montoTUI(r.CostoTotal), strconv.Itoa(r.Pagos), porcentajeTUI(r.Cashback),
+			})
+		}
+		m.reemplazar(tabla)
+		return nil
+	})
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"finmex/calc"
+	"github.com/urfave/cli/v2"
+)
+
+// comandoVales agrupa las operaciones con vales de despensa
+func comandoVales() *cli.Command {
+	return &cli.Command{
+		Name:  "vales",
+		Usage: "Operaciones con vales de despensa",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "agregar",
+				Usage: "Registrar vales de despensa",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					var v ValeDespensa
+
+					if v.Nombre, err = leerTextoRequerido("Nombre: "); err != nil {
+						return err
+					}
+					if v.Emisor, err = leerTextoRequerido("Emisor (Edenred, Sodexo, Si Vale, etc.): "); err != nil {
+						return err
+					}
+					if v.MontoMensual, err = leerNumero("Monto mensual depositado: ", limitesMonto); err != nil {
+						return err
+					}
+
+					texto, err := leerTexto("Tope de exención en UMAs mensuales [1]: ")
+					if err != nil {
+						return err
+					}
+					v.TopeUMAs = 1
+					if texto != "" {
+						if v.TopeUMAs, err = ParsearNumero(texto); err != nil {
+							return err
+						}
+						if err := validarLimites(v.TopeUMAs, LimitesNumero{Min: 0, Max: 100}); err != nil {
+							return err
+						}
+					}
+
+					if v.TasaMarginal, err = leerNumero("Tasa marginal de ISR sobre lo que exceda el tope (decimal): ", limitesTasaMarginal); err != nil {
+						return err
+					}
+
+					tarjetas.Vales = append(tarjetas.Vales, v)
+
+					if err := GuardarTarjetas(tarjetas); err != nil {
+						return fmt.Errorf("Error al guardar vales: %w", err)
+					}
+
+					fmt.Printf("Vales '%s' agregados exitosamente\n", v.Nombre)
+					return nil
+				},
+			},
+			{
+				Name:  "listar",
+				Usage: "Listar vales registrados con su efecto en el ingreso neto",
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					if len(tarjetas.Vales) == 0 && !salidaEstructurada() {
+						fmt.Println("No hay vales de despensa registrados")
+						return nil
+					}
+
+					año := time.Now().Year()
+					uma, err := calc.UMAMensual(año)
+					if err != nil {
+						return err
+					}
+
+					if salidaEstructurada() {
+						filas := []FilaVales{}
+						for _, v := range tarjetas.Vales {
+							efecto, err := CalcularEfectoVales(v, año)
+							if err != nil {
+								return err
+							}
+							filas = append(filas, FilaVales{v, v.TopeUMAs * uma, efecto})
+						}
+						return emitirDatos(filas)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+					fmt.Fprintln(w, "Nombre\tEmisor\tMensual\tTope\tExento\tGravado\tISR\tNeto")
+					fmt.Fprintln(w, "------\t------\t-------\t----\t------\t-------\t---\t----")
+
+					neto := 0.0
+					for _, v := range tarjetas.Vales {
+						efecto, err := CalcularEfectoVales(v, año)
+						if err != nil {
+							return err
+						}
+						fmt.Fprintf(w, "%s\t%s\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+							v.Nombre, v.Emisor, efecto.Bruto, v.TopeUMAs*uma, efecto.Exento,
+							efecto.Gravado, efecto.ISR, efecto.Neto)
+						neto += efecto.Neto
+					}
+
+					w.Flush()
+					fmt.Printf("\nUMA mensual %d: $%.2f\n", año, uma)
+					fmt.Printf("Los vales suman $%.2f netos al mes ($%.2f al año) a tu ingreso\n", neto, neto*12)
+					return nil
+				},
+			},
+			{
+				Name:  "presupuesto",
+				Usage: "Comparar el gasto en despensa del mes contra los vales",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "mes", Usage: "Mes a revisar (AAAA-MM, por omisión el actual)"},
+					&cli.StringFlag{Name: "categoria", Value: CATEGORIA_DESPENSA, Usage: "Categoría de movimientos que cubren los vales"},
+				},
+				Action: func(c *cli.Context) error {
+					tarjetas, err := CargarTarjetas()
+					if err != nil {
+						return fmt.Errorf("Error al cargar tarjetas: %w", err)
+					}
+
+					mes := c.String("mes")
+					if mes == "" {
+						mes = time.Now().Format("2006-01")
+					}
+					inicio, err := time.Parse("2006-01", mes)
+					if err != nil {
+						return fmt.Errorf("Mes inválido '%s': usa AAAA-MM", mes)
+					}
+
+					vales, err := ValesNetosMensuales(tarjetas.Vales, inicio.Year())
+					if err != nil {
+						return err
+					}
+					gasto, err := TotalMovimientos(FiltroMovimientos{Categoria: c.String("categoria")}, mes)
+					if err != nil {
+						return fmt.Errorf("Error al leer movimientos: %w", err)
+					}
+
+					fmt.Printf("\n=== Presupuesto de %s (%s) ===\n", c.String("categoria"), mes)
+					fmt.Printf("Vales disponibles: $%.2f\n", vales)
+					fmt.Printf("Gasto registrado: $%.2f\n", gasto)
+
+					if gasto <= vales {
+						fmt.Printf("RESULTADO: Los vales cubren todo el gasto y sobran $%.2f\n", vales-gasto)
+					} else {
+						fmt.Printf("RESULTADO: Pagaste $%.2f de tu bolsa además de los vales\n", gasto-vales)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
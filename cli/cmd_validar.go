@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+)
+
+// comandoValidar revisa un archivo de datos contra su esquema
+func comandoValidar() *cli.Command {
+	return &cli.Command{
+		Name:      "validar",
+		Usage:     "Validar un archivo de datos contra su JSON Schema",
+		ArgsUsage: "<archivo>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "esquema", Usage: "Esquema a usar si no se deduce del nombre del archivo: tarjetas, catalogo o movimientos"},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("Indica el archivo a validar")
+			}
+			ruta := c.Args().First()
+
+			errores, err := ValidarArchivo(ruta, c.String("esquema"))
+			if err != nil {
+				return err
+			}
+			if len(errores) == 0 {
+				fmt.Printf("RESULTADO: %s es válido\n", ruta)
+				return nil
+			}
+
+			for _, e := range errores {
+				fmt.Printf("  %s\n", e)
+			}
+			return errDatosInvalidos(
+				fmt.Sprintf("%s tiene %d errores", ruta, len(errores)),
+				fmt.Sprintf("%s has %d errors", ruta, len(errores)))
+		},
+	}
+}
+
+// comandoEsquema muestra o exporta los JSON Schemas de los archivos de datos
+func comandoEsquema() *cli.Command {
+	return &cli.Command{
+		Name:      "esquema",
+		Usage:     "Mostrar el JSON Schema de un archivo de datos",
+		ArgsUsage: "<tarjetas|catalogo|movimientos>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "directorio", Usage: "Escribir todos los esquemas en este directorio"},
+		},
+		Action: func(c *cli.Context) error {
+			if dir := c.String("directorio"); dir != "" {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return err
+				}
+				for _, nombre := range NombresEsquemas() {
+					esquema, err := EsquemaArchivo(nombre)
+					if err != nil {
+						return err
+					}
+					data, err := json.MarshalIndent(esquema, "", "  ")
+					if err != nil {
+						return err
+					}
+					archivo := filepath.Join(dir, fmt.Sprintf("%s.v%d.schema.json", nombre, VERSION_ESQUEMA))
+					if err := os.WriteFile(archivo, append(data, '\n'), 0644); err != nil {
+						return err
+					}
+					fmt.Printf("Esquema escrito en %s\n", archivo)
+				}
+				return nil
+			}
+
+			if c.NArg() != 1 {
+				return fmt.Errorf("Indica el esquema: tarjetas, catalogo o movimientos")
+			}
+			esquema, err := EsquemaArchivo(c.Args().First())
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(esquema, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+}
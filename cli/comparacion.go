@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"finmex/calc"
+)
+
+// maxValoresRango limita cuántos valores puede generar un barrido
+const maxValoresRango = 100000
+
+// PlazosComparacion son los plazos de liquidación que se comparan cara a cara
+var PlazosComparacion = []int{6, 12, 24}
+
+// EscenarioComparacion es el costo de liquidar la misma deuda con dos tarjetas en un plazo
+type EscenarioComparacion struct {
+	Meses       int        `json:"meses"`
+	Pago        [2]float64 `json:"pago"` // Pago mensual fijo que liquida la deuda en el plazo
+	Intereses   [2]float64 `json:"intereses"`
+	Anualidades [2]float64 `json:"anualidades"` // Anualidades cobradas durante el plazo
+	Beneficios  [2]float64 `json:"beneficios"`  // Cashback y beneficios de viaje recibidos durante el plazo
+	Ganadora    int        `json:"ganadora"`    // 0 o 1; -1 si empatan
+}
+
+// CostoNeto regresa intereses más anualidades menos beneficios de la tarjeta i
+func (e EscenarioComparacion) CostoNeto(i int) float64 {
+	return e.Intereses[i] + e.Anualidades[i] - e.Beneficios[i]
+}
+
+// DetalleComparacion compara dos tarjetas en todas sus dimensiones y en varios escenarios
+type DetalleComparacion struct {
+	Tarjetas          [2]CreditoCatalogo     `json:"tarjetas"`
+	Deuda             float64                `json:"deuda"`
+	GastoMensual      float64                `json:"gasto_mensual"`
+	BeneficiosAnuales [2]float64             `json:"beneficios_anuales"`
+	Escenarios        []EscenarioComparacion `json:"escenarios"`
+}
+
+// BuscarTarjetaCredito busca una tarjeta por nombre, o por banco y nombre, entre las candidatas
+func BuscarTarjetaCredito(candidatas []CreditoCatalogo, nombre string) (CreditoCatalogo, bool) {
+	clave := normalizarClave(nombre)
+	for _, t := range candidatas {
+		if clave == normalizarClave(t.Nombre) || clave == normalizarClave(t.Banco+" "+t.Nombre) || clave == normalizarClave(t.Nombre+" "+t.Banco) {
+			return t, true
+		}
+	}
+	return CreditoCatalogo{}, false
+}
+
+// beneficioAnual valúa el cashback general sobre el gasto mensual más los beneficios de viaje
+func beneficioAnual(t CreditoCatalogo, gastoMensual float64) float64 {
+	return cashbackAnualGeneral(t.TarjetaCredito, gastoMensual) + t.ValorViaje
+}
+
+// cashbackAnualGeneral valúa un año del gasto mensual sin categoría, con el tope y el valor de
+// canje de la tarjeta
+func cashbackAnualGeneral(t TarjetaCredito, gastoMensual float64) float64 {
+	return calc.CashbackMensual(t, map[string]float64{categoriaGeneral: gastoMensual}).Valor * 12
+}
+
+// CompararDetalle arma la comparación cara a cara: para cada plazo calcula el pago fijo que
+// liquida la deuda, los intereses, las anualidades de los años que dura y los beneficios
+// obtenidos con el gasto mensual en ese tiempo
+func CompararDetalle(a, b CreditoCatalogo, deuda, gastoMensual float64) DetalleComparacion {
+	d := DetalleComparacion{Tarjetas: [2]CreditoCatalogo{a, b}, Deuda: deuda, GastoMensual: gastoMensual}
+	for i, t := range d.Tarjetas {
+		d.BeneficiosAnuales[i] = beneficioAnual(t, gastoMensual)
+	}
+
+	for _, meses := range PlazosComparacion {
+		e := EscenarioComparacion{Meses: meses, Ganadora: -1}
+		años := math.Ceil(float64(meses) / 12)
+		for i, t := range d.Tarjetas {
+			e.Pago[i] = calc.PagoFijo(deuda, calc.TasaConIVA(t.TasaInteres)/12, meses)
+			e.Intereses[i] = e.Pago[i]*float64(meses) - deuda
+			e.Anualidades[i] = t.AnualidadEfectiva(gastoMensual) * años
+			e.Beneficios[i] = d.BeneficiosAnuales[i] * float64(meses) / 12
+		}
+		switch {
+		case e.CostoNeto(0) < e.CostoNeto(1)-0.005:
+			e.Ganadora = 0
+		case e.CostoNeto(1) < e.CostoNeto(0)-0.005:
+			e.Ganadora = 1
+		}
+		d.Escenarios = append(d.Escenarios, e)
+	}
+	return d
+}
+
+// ParsearRango interpreta un barrido "desde:hasta:paso" (p. ej. 500:5000:250) y regresa
+// todos los valores, incluidos los extremos
+func ParsearRango(texto string) ([]float64, error) {
+	partes := strings.Split(texto, ":")
+	if len(partes) != 3 {
+		return nil, fmt.Errorf("Rango inválido '%s' (usa desde:hasta:paso)", texto)
+	}
+	var numeros [3]float64
+	for i, parte := range partes {
+		n, err := ParsearNumero(parte)
+		if err != nil {
+			return nil, fmt.Errorf("Rango inválido '%s': %w", texto, err)
+		}
+		numeros[i] = n
+	}
+	desde, hasta, paso := numeros[0], numeros[1], numeros[2]
+	if paso <= 0 || hasta < desde {
+		return nil, fmt.Errorf("Rango inválido '%s': el paso debe ser positivo y hasta no puede ser menor que desde", texto)
+	}
+	if (hasta-desde)/paso+1 > maxValoresRango {
+		return nil, fmt.Errorf("El rango '%s' genera más de %d valores", texto, maxValoresRango)
+	}
+
+	var valores []float64
+	for i := 0; ; i++ {
+		v := desde + float64(i)*paso
+		if v > hasta+paso*1e-9 {
+			break
+		}
+		valores = append(valores, math.Round(v*100)/100)
+	}
+	return valores, nil
+}
+
+// Formas de pagar la tarjeta en el escenario de comparar mejor
+const (
+	PagoUsoTotal  = "total"  // Se paga el total al corte y no se generan intereses
+	PagoUsoMinimo = "minimo" // Se paga el mínimo cada mes
+	PagoUsoFijo   = "fijo"   // Se paga un monto fijo cada mes
+)
+
+// MESES_ESCENARIO_USO es el horizonte con el que se valúa el uso de cada tarjeta
+const MESES_ESCENARIO_USO = 12
+
+// EscenarioUso es el patrón de uso con el que comparar mejor elige la tarjeta de crédito
+type EscenarioUso struct {
+	GastoMensual    float64 `json:"gasto_mensual"`
+	Pago            string  `json:"pago"`                   // total, minimo o fijo
+	PagoMensual     float64 `json:"pago_mensual,omitempty"` // Solo con pago fijo
+	ComprasMSI      float64 `json:"compras_msi"`            // Compras al año que se difieren a MSI
+	MesesMSI        int     `json:"meses_msi"`
+	TasaOportunidad float64 `json:"tasa_oportunidad"` // Para valuar el diferimiento de los MSI
+}
+
+// ParsearPagoUso interpreta --pago: total, minimo o un monto mensual
+func ParsearPagoUso(texto string) (string, float64, error) {
+	switch normalizarClave(texto) {
+	case PagoUsoTotal:
+		return PagoUsoTotal, 0, nil
+	case PagoUsoMinimo, "mínimo":
+		return PagoUsoMinimo, 0, nil
+	}
+	monto, err := parsearDecimal(texto, limitesMonto)
+	if err != nil || monto <= 0 {
+		return "", 0, errDatosInvalidos(
+			fmt.Sprintf("Pago inválido '%s' (usa total, minimo o un monto mensual)", texto),
+			fmt.Sprintf("Invalid payment '%s' (use total, minimo or a monthly amount)", texto))
+	}
+	return PagoUsoFijo, monto, nil
+}
+
+// ValuacionUso es el valor neto de usar una tarjeta un año con el escenario
+type ValuacionUso struct {
+	Nombre     string  `json:"nombre"`
+	Banco      string  `json:"banco"`
+	Registrada bool    `json:"registrada"`
+	CAT        float64 `json:"cat"`
+	Tasa       float64 `json:"tasa"` // Tasa con la que se cobran los intereses
+	Cashback   float64 `json:"cashback"`
+	Viajes     float64 `json:"viajes"`
+	MSI        float64 `json:"msi"`       // Ahorro por diferir compras a MSI
+	Anualidad  float64 `json:"anualidad"` // Cero si el gasto alcanza para que la condonen
+	Intereses  float64 `json:"intereses"`
+	DeudaFinal float64 `json:"deuda_final"` // Deuda revolvente al terminar el año
+	Neto       float64 `json:"neto"`
+	// AnualidadCondonada indica que el gasto del escenario alcanza la facturación con la que
+	// se bonifica la anualidad
+	AnualidadCondonada bool `json:"anualidad_condonada"`
+}
+
+// ValuarUso simula mes por mes un año de gasto y pagos con la tarjeta. Los intereses se
+// cobran sobre la deuda que queda del mes anterior con la tasa de la tarjeta, o con su CAT si
+// no tiene tasa registrada.
+func ValuarUso(t CreditoCatalogo, e EscenarioUso) ValuacionUso {
+	v := ValuacionUso{
+		Nombre: t.Nombre, Banco: t.Banco, Registrada: t.Segmento == segmentoRegistrada,
+		CAT: t.CAT, Tasa: t.TasaInteres, Anualidad: t.AnualidadEfectiva(e.GastoMensual), Viajes: t.ValorViaje,
+		AnualidadCondonada: t.AnualidadCondonada(e.GastoMensual),
+	}
+	if v.Tasa <= 0 {
+		v.Tasa = t.CAT
+	}
+	v.Cashback = cashbackAnualGeneral(t.TarjetaCredito, e.GastoMensual)
+	if t.MesesSinIntereses && e.ComprasMSI > 0 {
+		v.MSI = e.ComprasMSI - ValorPresenteMSI(e.ComprasMSI, e.MesesMSI, e.TasaOportunidad)
+	}
+
+	deuda := 0.0
+	for mes := 0; mes < MESES_ESCENARIO_USO; mes++ {
+		interes := deuda * v.Tasa / 12
+		v.Intereses += interes
+		deuda += interes + e.GastoMensual
+		pago := deuda
+		switch e.Pago {
+		case PagoUsoMinimo:
+			pago = math.Min(calc.PagoMinimoRegulatorio(deuda-interes, interes, t.LimiteCredito), deuda)
+		case PagoUsoFijo:
+			pago = math.Min(e.PagoMensual, deuda)
+		}
+		deuda -= pago
+	}
+	v.DeudaFinal = deuda
+	v.Neto = v.Cashback + v.Viajes + v.MSI - v.Anualidad - v.Intereses
+	return v
+}
+
+// ElegirTarjetaUso valúa cada candidata con el escenario y las ordena de mayor a menor valor
+// neto; en empate gana la de menor CAT
+func ElegirTarjetaUso(candidatas []CreditoCatalogo, e EscenarioUso) []ValuacionUso {
+	var valuaciones []ValuacionUso
+	for _, t := range candidatas {
+		valuaciones = append(valuaciones, ValuarUso(t, e))
+	}
+	sort.SliceStable(valuaciones, func(i, j int) bool {
+		a, b := valuaciones[i], valuaciones[j]
+		if math.Abs(a.Neto-b.Neto) >= 0.005 {
+			return a.Neto > b.Neto
+		}
+		return a.CAT < b.CAT
+	})
+	return valuaciones
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"finmex/storage"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Scripts de autocompletado. Cada uno vuelve a llamar a finmex con los argumentos escritos
+// y --generate-bash-completion, así que las sugerencias siempre corresponden a la versión
+// instalada y a las tarjetas registradas.
+const (
+	completadoBash = `# Autocompletado de finmex para bash: source <(finmex completion bash)
+_finmex_completion() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" opts IFS=$'\n'
+	if [[ "$cur" == -* ]]; then
+		opts=$("${COMP_WORDS[@]:0:$COMP_CWORD}" "$cur" --generate-bash-completion 2>/dev/null)
+	else
+		opts=$("${COMP_WORDS[@]:0:$COMP_CWORD}" --generate-bash-completion 2>/dev/null)
+	fi
+	COMPREPLY=($(compgen -W "$opts" -- "$cur"))
+	local i
+	for i in "${!COMPREPLY[@]}"; do
+		COMPREPLY[$i]=$(printf '%q' "${COMPREPLY[$i]}")
+	done
+}
+complete -o default -F _finmex_completion finmex
+`
+	completadoZsh = `#compdef finmex
+# Autocompletado de finmex para zsh: source <(finmex completion zsh)
+_finmex() {
+	local -a opts
+	local cur=${words[-1]}
+	if [[ "$cur" == -* ]]; then
+		opts=("${(@f)$(${words[@]:0:#words[@]-1} "$cur" --generate-bash-completion 2>/dev/null)}")
+	else
+		opts=("${(@f)$(${words[@]:0:#words[@]-1} --generate-bash-completion 2>/dev/null)}")
+	fi
+	if [[ -n "${opts[1]}" ]]; then
+		_describe 'valores' opts
+	else
+		_files
+	fi
+}
+compdef _finmex finmex
+`
+	completadoFish = `# Autocompletado de finmex para fish: finmex completion fish | source
+function __finmex_completar
+	set -l palabras (commandline -opc)
+	set -l actual (commandline -ct)
+	if string match -q -- '-*' $actual
+		eval (string escape -- $palabras $actual) --generate-bash-completion 2>/dev/null
+	else
+		eval (string escape -- $palabras) --generate-bash-completion 2>/dev/null
+	end
+end
+complete -c finmex -f -a '(__finmex_completar)'
+`
+)
+
+// comandoCompletion imprime el script de autocompletado de un shell
+func comandoCompletion() *cli.Command {
+	return &cli.Command{
+		Name:      "completion",
+		Usage:     "Generar el script de autocompletado para bash, zsh o fish",
+		ArgsUsage: "<bash|zsh|fish>",
+		Description: "Completa comandos, opciones y los nombres de tus tarjetas en analizar, editar y eliminar.\n" +
+			"bash: agrega 'source <(finmex completion bash)' a ~/.bashrc\n" +
+			"zsh:  agrega 'source <(finmex completion zsh)' a ~/.zshrc, después de compinit\n" +
+			"fish: finmex completion fish > ~/.config/fish/completions/finmex.fish",
+		BashComplete: func(c *cli.Context) {
+			if c.NArg() == 0 {
+				fmt.Println("bash\nzsh\nfish")
+			}
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("Indica el shell: bash, zsh o fish")
+			}
+			script, ok := map[string]string{"bash": completadoBash, "zsh": completadoZsh, "fish": completadoFish}[strings.ToLower(c.Args().First())]
+			if !ok {
+				return errDatosInvalidos(
+					fmt.Sprintf("Shell no soportado '%s' (usa bash, zsh o fish)", c.Args().First()),
+					fmt.Sprintf("Unsupported shell '%s' (use bash, zsh or fish)", c.Args().First()))
+			}
+			fmt.Print(script)
+			return nil
+		},
+	}
+}
+
+// completarTarjetas sugiere los nombres que regresa nombres para el argumento del comando.
+// Las opciones se completan como siempre.
+func completarTarjetas(nombres func(Tarjetas) []string) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		if anterior := argumentoPrevioCompletado(); strings.HasPrefix(anterior, "-") || c.NArg() > 0 {
+			cli.DefaultCompleteWithFlags(c.Command)(c)
+			return
+		}
+		imprimirNombresCompletado(c, nombres)
+	}
+}
+
+// completarFlagTarjeta sugiere los nombres para el valor de la opción flag, como
+// --tarjeta en credito analizar
+func completarFlagTarjeta(flag string, nombres func(Tarjetas) []string) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		if argumentoPrevioCompletado() == "--"+flag {
+			imprimirNombresCompletado(c, nombres)
+			return
+		}
+		cli.DefaultCompleteWithFlags(c.Command)(c)
+	}
+}
+
+// argumentoPrevioCompletado es la palabra antes de --generate-bash-completion, como la
+// toma urfave/cli para decidir si completa opciones
+func argumentoPrevioCompletado() string {
+	if len(os.Args) > 2 {
+		return os.Args[len(os.Args)-2]
+	}
+	return ""
+}
+
+// imprimirNombresCompletado imprime un nombre por línea. Durante el autocompletado no corre
+// el Before de la aplicación, así que aquí se aplican la configuración, el almacén y el
+// perfil; cualquier error deja la lista vacía para no ensuciar la terminal.
+func imprimirNombresCompletado(c *cli.Context, nombres func(Tarjetas) []string) {
+	if aplicarConfiguracion(c) != nil || elegirAlmacen(c.String("almacen")) != nil || activarPerfil(c.String("perfil")) != nil {
+		return
+	}
+	// No se crea un almacén vacío solo por presionar tabulador
+	if !storage.Existe(rutaDatos(ARCHIVO_BASE_DATOS)) && !storage.Existe(rutaDatos(ARCHIVO_TARJETAS)) && !almacenCifrado() {
+		return
+	}
+	// Tampoco se pide la passphrase de los datos cifrados
+	if almacenCifrado() && os.Getenv(VARIABLE_PASSPHRASE) == "" {
+		return
+	}
+	tarjetas, err := CargarTarjetas()
+	cerrarAlmacen()
+	if err != nil {
+		return
+	}
+	zsh := strings.HasSuffix(os.Getenv("SHELL"), "zsh")
+	for _, nombre := range nombres(tarjetas) {
+		if zsh {
+			// _describe separa la descripción con dos puntos
+			nombre = strings.ReplaceAll(nombre, ":", `\:`)
+		}
+		fmt.Println(nombre)
+	}
+}
+
+// nombresSofipo regresa los nombres de las cuentas de SOFIPO en orden
+func nombresSofipo(tarjetas Tarjetas) []string {
+	nombres := make([]string, len(tarjetas.Sofipos))
+	for i, s := range tarjetas.Sofipos {
+		nombres[i] = s.Nombre
+	}
+	return nombres
+}
+
+// nombresPagare regresa los nombres de los pagarés en orden
+func nombresPagare(tarjetas Tarjetas) []string {
+	nombres := make([]string, len(tarjetas.Pagares))
+	for i, p := range tarjetas.Pagares {
+		nombres[i] = p.Nombre
+	}
+	return nombres
+}
@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ARCHIVO_CONFIG guarda los valores por defecto del usuario en el directorio de configuración
+const ARCHIVO_CONFIG = "config.yaml"
+
+// DATOS_XDG como ruta de datos guarda los datos en el directorio de datos del usuario
+// ($XDG_DATA_HOME/finmex o ~/.local/share/finmex)
+const DATOS_XDG = "xdg"
+
+// Configuracion son los valores por defecto de config.yaml. Las opciones globales y sus
+// variables de entorno tienen prioridad sobre ellos.
+type Configuracion struct {
+	Inflacion *float64 `yaml:"inflacion,omitempty" json:"inflacion,omitempty"`     // Se usa si no hay dato de Banxico
+	AñoFiscal int      `yaml:"anio_fiscal,omitempty" json:"anio_fiscal,omitempty"` // Año de la retención de ISR de la LIF
+	Datos     string   `yaml:"datos,omitempty" json:"datos,omitempty"`             // Directorio de los datos; vacío es el actual
+	Output    string   `yaml:"output,omitempty" json:"output,omitempty"`
+	Perfil    string   `yaml:"perfil,omitempty" json:"perfil,omitempty"` // Si no se eligió uno con 'finmex perfil usar'
+}
+
+// ClaveConfig describe una clave de config.yaml para config get y config set
+type ClaveConfig struct {
+	Clave       string `json:"clave"`
+	Descripcion string `json:"descripcion"`
+}
+
+// ClavesConfig son las claves que acepta config.yaml, en el orden en que se muestran
+var ClavesConfig = []ClaveConfig{
+	{"inflacion", "Inflación anual (decimal) cuando no hay dato de Banxico"},
+	{"anio_fiscal", "Año fiscal de la retención de ISR"},
+	{"datos", "Directorio de los datos; 'xdg' usa el directorio de datos del usuario"},
+	{"output", "Formato de salida: texto, json, csv o ndjson"},
+	{"perfil", "Perfil cuando no se eligió uno con 'finmex perfil usar'"},
+}
+
+// configuracion es la que se leyó de config.yaml al iniciar
+var configuracion Configuracion
+
+// rutaConfig regresa la ruta de config.yaml
+func rutaConfig() (string, error) {
+	dir, err := directorioConfig()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ARCHIVO_CONFIG), nil
+}
+
+// LeerConfiguracion lee config.yaml; si no existe, no hay valores por defecto
+func LeerConfiguracion() (Configuracion, error) {
+	var config Configuracion
+	ruta, err := rutaConfig()
+	if err != nil {
+		return config, err
+	}
+	data, err := os.ReadFile(ruta)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return config, errArchivoCorrupto(ruta, err)
+	}
+	if err := config.Validar(); err != nil {
+		return config, errArchivoCorrupto(ruta, err)
+	}
+	return config, nil
+}
+
+// GuardarConfiguracion escribe config.yaml, creando el directorio de configuración si hace
+// falta
+func GuardarConfiguracion(config Configuracion) error {
+	ruta, err := rutaConfig()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ruta), 0755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ruta, data, 0644)
+}
+
+// Validar revisa los valores leídos de config.yaml
+func (config Configuracion) Validar() error {
+	if config.Inflacion != nil {
+		if err := validarDecimal(*config.Inflacion, limitesInflacion); err != nil {
+			return fmt.Errorf("inflacion: %w", err)
+		}
+	}
+	if config.AñoFiscal != 0 && (config.AñoFiscal < 1900 || config.AñoFiscal > 2200) {
+		return fmt.Errorf("anio_fiscal: año fiscal inválido %d", config.AñoFiscal)
+	}
+	switch config.Output {
+	case "", FormatoTexto, FormatoJSON, FormatoCSV, FormatoNDJSON:
+	default:
+		return fmt.Errorf("output: formato inválido '%s' (usa texto, json, csv o ndjson)", config.Output)
+	}
+	if config.Perfil != "" && config.Perfil != PERFIL_PRINCIPAL {
+		if err := ValidarNombrePerfil(config.Perfil); err != nil {
+			return fmt.Errorf("perfil: %w", err)
+		}
+	}
+	return nil
+}
+
+// claveConfig acepta las claves con guion, como los flags (anio-fiscal), o con guion bajo
+func claveConfig(clave string) (string, error) {
+	clave = strings.ReplaceAll(normalizarClave(clave), "-", "_")
+	for _, k := range ClavesConfig {
+		if k.Clave == clave {
+			return clave, nil
+		}
+	}
+	nombres := make([]string, len(ClavesConfig))
+	for i, k := range ClavesConfig {
+		nombres[i] = k.Clave
+	}
+	return "", errDatosInvalidos(
+		fmt.Sprintf("Clave de configuración desconocida '%s' (usa %s)", clave, strings.Join(nombres, ", ")),
+		fmt.Sprintf("Unknown configuration key '%s' (use %s)", clave, strings.Join(nombres, ", ")))
+}
+
+// Valor regresa el valor de una clave como se escribe en config set; vacío si no está definida
+func (config Configuracion) Valor(clave string) (string, error) {
+	clave, err := claveConfig(clave)
+	if err != nil {
+		return "", err
+	}
+	switch clave {
+	case "inflacion":
+		if config.Inflacion != nil {
+			return strconv.FormatFloat(*config.Inflacion, 'f', -1, 64), nil
+		}
+	case "anio_fiscal":
+		if config.AñoFiscal != 0 {
+			return strconv.Itoa(config.AñoFiscal), nil
+		}
+	case "datos":
+		return config.Datos, nil
+	case "output":
+		return config.Output, nil
+	case "perfil":
+		return config.Perfil, nil
+	}
+	return "", nil
+}
+
+// Fijar cambia el valor de una clave; un valor vacío la quita para volver al predeterminado
+func (config *Configuracion) Fijar(clave, valor string) error {
+	clave, err := claveConfig(clave)
+	if err != nil {
+		return err
+	}
+	valor = strings.TrimSpace(valor)
+	nueva := *config
+	switch clave {
+	case "inflacion":
+		nueva.Inflacion = nil
+		if valor != "" {
+			inflacion, err := parsearDecimal(valor, limitesInflacion)
+			if err != nil {
+				return fmt.Errorf("inflacion: %w", err)
+			}
+			nueva.Inflacion = &inflacion
+		}
+	case "anio_fiscal":
+		nueva.AñoFiscal = 0
+		if valor != "" {
+			if nueva.AñoFiscal, err = strconv.Atoi(valor); err != nil || nueva.AñoFiscal == 0 {
+				return errDatosInvalidos(
+					fmt.Sprintf("Año fiscal inválido '%s'", valor),
+					fmt.Sprintf("Invalid fiscal year '%s'", valor))
+			}
+		}
+	case "datos":
+		nueva.Datos = valor
+	case "output":
+		nueva.Output = strings.ToLower(valor)
+	case "perfil":
+		nueva.Perfil = strings.ToLower(valor)
+	}
+	if err := nueva.Validar(); err != nil {
+		return errDatosInvalidos(err.Error(), err.Error())
+	}
+	*config = nueva
+	return nil
+}
+
+// DirectorioDatos regresa el directorio de datos configurado, con ~ y las variables de
+// entorno expandidas; vacío es el directorio actual
+func (config Configuracion) DirectorioDatos() (string, error) {
+	if config.Datos == "" {
+		return "", nil
+	}
+	if config.Datos == DATOS_XDG {
+		if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+			return filepath.Join(dir, "finmex"), nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("No se pudo ubicar el directorio de datos del usuario: %v", err)
+		}
+		return filepath.Join(home, ".local", "share", "finmex"), nil
+	}
+	ruta := os.ExpandEnv(config.Datos)
+	if ruta == "~" || strings.HasPrefix(ruta, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("No se pudo ubicar el directorio del usuario: %v", err)
+		}
+		ruta = filepath.Join(home, strings.TrimPrefix(ruta, "~"))
+	}
+	return ruta, nil
+}
+
+// aplicarConfiguracion lee config.yaml y usa sus valores en las opciones globales que no
+// se dieron en la línea de comandos ni en variables de entorno
+func aplicarConfiguracion(c *cli.Context) error {
+	config, err := LeerConfiguracion()
+	if err != nil {
+		return err
+	}
+	configuracion = config
+
+	if config.Output != "" && !c.IsSet("output") {
+		if err := c.Set("output", config.Output); err != nil {
+			return err
+		}
+	}
+	if config.AñoFiscal != 0 && !c.IsSet("anio-fiscal") {
+		if err := c.Set("anio-fiscal", strconv.Itoa(config.AñoFiscal)); err != nil {
+			return err
+		}
+	}
+	if config.Inflacion != nil {
+		inflacionPredeterminada = *config.Inflacion
+	}
+	if directorioDatos, err = config.DirectorioDatos(); err != nil {
+		return err
+	}
+	if directorioDatos != "" {
+		if err := os.MkdirAll(directorioDatos, 0755); err != nil {
+			return fmt.Errorf("No se pudo crear el directorio de datos %s: %v", directorioDatos, err)
+		}
+		registro.Debug("directorio de datos", "directorio", directorioDatos)
+	}
+	return nil
+}
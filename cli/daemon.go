@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// ARCHIVO_SOCKET es el socket Unix donde escucha el daemon de finmex
+const ARCHIVO_SOCKET = "finmex.sock"
+
+// Operaciones que entiende el daemon
+const (
+	opCargarTarjetas     = "cargar_tarjetas"
+	opGuardarTarjetas    = "guardar_tarjetas"
+	opAgregarMovimientos = "agregar_movimientos"
+)
+
+// versionDaemon es la versión de los datos que este proceso leyó del daemon. Se envía al
+// guardar para detectar si otro proceso modificó las tarjetas mientras tanto.
+var versionDaemon int64
+
+// solicitudDaemon es un mensaje de un cliente hacia el daemon
+type solicitudDaemon struct {
+	Operacion   string       `json:"operacion"`
+	Version     int64        `json:"version"`
+	Tarjetas    *Tarjetas    `json:"tarjetas,omitempty"`
+	Movimientos []Movimiento `json:"movimientos,omitempty"`
+}
+
+// respuestaDaemon es la respuesta del daemon a una solicitud
+type respuestaDaemon struct {
+	Error    string      `json:"error,omitempty"`
+	Codigo   CodigoError `json:"codigo,omitempty"`  // Código del error tipado, si lo hay
+	Message  string      `json:"message,omitempty"` // Mensaje del error en inglés
+	Version  int64       `json:"version"`
+	Tarjetas *Tarjetas   `json:"tarjetas,omitempty"`
+}
+
+// respuestaError arma la respuesta de un error conservando su código para el cliente
+func respuestaError(contexto string, err error, version int64) respuestaDaemon {
+	respuesta := respuestaDaemon{Error: fmt.Sprintf("%s: %v", contexto, err), Version: version}
+	var e *ErrorFinmex
+	if errors.As(err, &e) {
+		respuesta.Codigo = e.Codigo
+		respuesta.Message = e.Localizado("en")
+	}
+	return respuesta
+}
+
+// Daemon es el único proceso que escribe los archivos de datos mientras está activo.
+// Mantiene las tarjetas en memoria y atiende a los clientes de uno en uno.
+type Daemon struct {
+	mu       sync.Mutex
+	tarjetas Tarjetas
+	version  int64
+	modTime  time.Time
+	alerta   string // Última alerta mostrada, para no repetirla en cada cambio
+}
+
+// NuevoDaemon crea un daemon con las tarjetas del archivo de datos
+func NuevoDaemon() (*Daemon, error) {
+	d := &Daemon{}
+	if err := d.recargar(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Escuchar atiende conexiones en el socket hasta que ocurra un error
+func (d *Daemon) Escuchar(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		registro.Debug("cliente conectado al daemon")
+		go d.atender(conn)
+	}
+}
+
+// atender procesa las solicitudes de una conexión, una por línea
+func (d *Daemon) atender(conn net.Conn) {
+	defer conn.Close()
+
+	decoder := json.NewDecoder(conn)
+	encoder := json.NewEncoder(conn)
+	for {
+		var solicitud solicitudDaemon
+		if err := decoder.Decode(&solicitud); err != nil {
+			if !errors.Is(err, io.EOF) {
+				registro.Warn("solicitud inválida al daemon", "error", err)
+			}
+			return
+		}
+		respuesta := d.procesar(solicitud)
+		if respuesta.Error != "" {
+			registro.Warn("solicitud rechazada", "operacion", solicitud.Operacion, "error", respuesta.Error)
+		} else {
+			registro.Debug("solicitud atendida", "operacion", solicitud.Operacion, "version", respuesta.Version)
+		}
+		if err := encoder.Encode(respuesta); err != nil {
+			registro.Warn("no se pudo responder al cliente", "error", err)
+			return
+		}
+	}
+}
+
+// procesar ejecuta una solicitud con acceso exclusivo a los datos
+func (d *Daemon) procesar(solicitud solicitudDaemon) respuestaDaemon {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Si alguien editó los datos por fuera, los volvemos a leer
+	if info, err := os.Stat(rutaAlmacen()); err == nil && !info.ModTime().Equal(d.modTime) {
+		registro.Info("datos modificados por fuera, recargando", "archivo", rutaAlmacen())
+		if err := d.recargar(); err != nil {
+			return respuestaError("Error al recargar tarjetas", err, d.version)
+		}
+	}
+
+	switch solicitud.Operacion {
+	case opCargarTarjetas:
+		tarjetas := d.tarjetas
+		return respuestaDaemon{Version: d.version, Tarjetas: &tarjetas}
+
+	case opGuardarTarjetas:
+		if solicitud.Tarjetas == nil {
+			return respuestaDaemon{Error: "Solicitud sin tarjetas", Version: d.version}
+		}
+		// El daemon empieza en la versión 1, así que un cliente que no cargó las tarjetas
+		// a través de él manda 0 y también se rechaza
+		if solicitud.Version != d.version {
+			return respuestaDaemon{
+				Error:   "Las tarjetas fueron modificadas por otro proceso; vuelve a intentar la operación",
+				Version: d.version,
+			}
+		}
+		if err := guardarTarjetasAlmacen(*solicitud.Tarjetas); err != nil {
+			return respuestaError("Error al guardar tarjetas", err, d.version)
+		}
+		d.tarjetas = *solicitud.Tarjetas
+		d.version++
+		d.actualizarModTime()
+		d.revisarAlertas()
+		return respuestaDaemon{Version: d.version}
+
+	case opAgregarMovimientos:
+		if err := agregarMovimientosAlmacen(solicitud.Movimientos); err != nil {
+			return respuestaError("Error al guardar movimientos", err, d.version)
+		}
+		// En SQLite los movimientos cambian el mismo archivo que las tarjetas
+		d.actualizarModTime()
+		return respuestaDaemon{Version: d.version}
+	}
+
+	return respuestaDaemon{Error: fmt.Sprintf("Operación desconocida '%s'", solicitud.Operacion), Version: d.version}
+}
+
+// recargar lee de nuevo el archivo de tarjetas y registra una nueva versión
+func (d *Daemon) recargar() error {
+	tarjetas, err := cargarTarjetasAlmacen()
+	if err != nil {
+		return err
+	}
+	d.tarjetas = tarjetas
+	d.version++
+	d.actualizarModTime()
+	registro.Debug("tarjetas cargadas", "version", d.version, "debito", len(tarjetas.Debito), "credito", len(tarjetas.Credito))
+	d.revisarAlertas()
+	return nil
+}
+
+// revisarAlertas muestra en la salida del daemon las alertas nuevas sobre los datos
+func (d *Daemon) revisarAlertas() {
+	catalogo, err := CargarCatalogo()
+	if err != nil {
+		registro.Warn("no se pudo cargar el catálogo para revisar alertas", "error", err)
+		return
+	}
+	alerta := RevisarInflacion(d.tarjetas, catalogo.Benchmarks, InflacionVigente()).Mensaje()
+	if alerta != "" && alerta != d.alerta {
+		fmt.Printf("%s %s\n", time.Now().Format("2006-01-02 15:04"), alerta)
+		registro.Info("alerta nueva", "alerta", alerta)
+	}
+	d.alerta = alerta
+}
+
+// actualizarModTime recuerda la fecha de modificación del archivo que escribimos
+func (d *Daemon) actualizarModTime() {
+	if info, err := os.Stat(rutaAlmacen()); err == nil {
+		d.modTime = info.ModTime()
+	}
+}
+
+// EscucharSocket crea el socket Unix del daemon. Si existe un socket abandonado por un
+// daemon anterior lo elimina; si hay otro daemon activo regresa un error.
+func EscucharSocket() (net.Listener, error) {
+	if _, err := os.Stat(rutaDatos(ARCHIVO_SOCKET)); err == nil {
+		if conn, err := net.DialTimeout("unix", rutaDatos(ARCHIVO_SOCKET), time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("Ya hay un daemon escuchando en %s", rutaDatos(ARCHIVO_SOCKET))
+		}
+		registro.Info("eliminando socket abandonado", "socket", rutaDatos(ARCHIVO_SOCKET))
+		os.Remove(rutaDatos(ARCHIVO_SOCKET))
+	}
+	return net.Listen("unix", rutaDatos(ARCHIVO_SOCKET))
+}
+
+// clienteDaemon es una conexión de un comando hacia el daemon
+type clienteDaemon struct {
+	conn    net.Conn
+	encoder *json.Encoder
+	decoder *json.Decoder
+}
+
+// conectarDaemon se conecta al daemon si hay uno escuchando
+func conectarDaemon() (*clienteDaemon, bool) {
+	if _, err := os.Stat(rutaDatos(ARCHIVO_SOCKET)); err != nil {
+		return nil, false
+	}
+
+	conn, err := net.DialTimeout("unix", rutaDatos(ARCHIVO_SOCKET), 500*time.Millisecond)
+	if err != nil {
+		return nil, false
+	}
+
+	return &clienteDaemon{
+		conn:    conn,
+		encoder: json.NewEncoder(conn),
+		decoder: json.NewDecoder(conn),
+	}, true
+}
+
+// Close cierra la conexión con el daemon
+func (c *clienteDaemon) Close() error {
+	return c.conn.Close()
+}
+
+// enviar manda una solicitud y espera la respuesta del daemon
+func (c *clienteDaemon) enviar(solicitud solicitudDaemon) (respuestaDaemon, error) {
+	var respuesta respuestaDaemon
+	if err := c.encoder.Encode(solicitud); err != nil {
+		return respuesta, fmt.Errorf("Error al comunicarse con el daemon: %v", err)
+	}
+	if err := c.decoder.Decode(&respuesta); err != nil {
+		return respuesta, fmt.Errorf("Error al leer respuesta del daemon: %v", err)
+	}
+	if respuesta.Error != "" {
+		if respuesta.Codigo != "" {
+			return respuesta, &ErrorFinmex{Codigo: respuesta.Codigo, Mensaje: respuesta.Error, Message: respuesta.Message}
+		}
+		return respuesta, errors.New(respuesta.Error)
+	}
+	return respuesta, nil
+}
+
+// cargarTarjetas pide al daemon las tarjetas en memoria
+func (c *clienteDaemon) cargarTarjetas() (Tarjetas, error) {
+	respuesta, err := c.enviar(solicitudDaemon{Operacion: opCargarTarjetas})
+	if err != nil {
+		return Tarjetas{}, err
+	}
+	versionDaemon = respuesta.Version
+	return *respuesta.Tarjetas, nil
+}
+
+// guardarTarjetas pide al daemon que guarde las tarjetas
+func (c *clienteDaemon) guardarTarjetas(tarjetas Tarjetas) error {
+	respuesta, err := c.enviar(solicitudDaemon{Operacion: opGuardarTarjetas, Version: versionDaemon, Tarjetas: &tarjetas})
+	if err != nil {
+		return err
+	}
+	versionDaemon = respuesta.Version
+	return nil
+}
+
+// agregarMovimientos pide al daemon que agregue movimientos al historial
+func (c *clienteDaemon) agregarMovimientos(movimientos []Movimiento) error {
+	_, err := c.enviar(solicitudDaemon{Operacion: opAgregarMovimientos, Movimientos: movimientos})
+	return err
+}
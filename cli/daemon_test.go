@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDaemonRechazaGuardarSinVersion(t *testing.T) {
+	conAlmacenTemporal(t)
+	d, err := NuevoDaemon()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cargadas := d.procesar(solicitudDaemon{Operacion: opCargarTarjetas})
+
+	// Otro proceso guarda con la versión que cargó
+	otro := Tarjetas{Debito: []TarjetaDebito{{Nombre: "Azul"}}, Credito: []TarjetaCredito{}}
+	if r := d.procesar(solicitudDaemon{Operacion: opGuardarTarjetas, Version: cargadas.Version, Tarjetas: &otro}); r.Error != "" {
+		t.Fatalf("guardar con la versión cargada: %s", r.Error)
+	}
+
+	// Sin versión o con la anterior no se pisa ese cambio
+	viejas := *cargadas.Tarjetas
+	for _, version := range []int64{0, cargadas.Version} {
+		r := d.procesar(solicitudDaemon{Operacion: opGuardarTarjetas, Version: version, Tarjetas: &viejas})
+		if !strings.Contains(r.Error, "modificadas por otro proceso") {
+			t.Errorf("versión %d: se esperaba el conflicto, no %q", version, r.Error)
+		}
+	}
+	guardadas, err := cargarTarjetasAlmacen()
+	if err != nil || !reflect.DeepEqual(guardadas.Debito, otro.Debito) {
+		t.Errorf("se perdió el cambio del otro proceso: %+v, %v", guardadas.Debito, err)
+	}
+}
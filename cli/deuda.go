@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"finmex/calc"
+)
+
+// Estrategias para repartir el pago entre varias deudas
+const (
+	EstrategiaAvalancha = "avalancha"     // Primero la deuda con mayor tasa
+	EstrategiaBolaNieve = "bola-de-nieve" // Primero la deuda con menor saldo
+)
+
+// limiteMesesPlan corta la simulación si el presupuesto no alcanza a liquidar las deudas
+const limiteMesesPlan = 1000
+
+// DeudaPlan es una deuda que participa en el plan de pagos
+type DeudaPlan struct {
+	Nombre        string
+	Saldo         float64
+	TasaAnual     float64
+	LimiteCredito float64 // Para el pago mínimo regulatorio; cero si no se conoce
+}
+
+// MesPlan es el estado del plan al terminar un mes
+type MesPlan struct {
+	Mes              int // Empezando en 1
+	Foco             string
+	Pagos            []float64 // Pago aplicado a cada deuda, en el orden de PlanDeuda.Deudas
+	Saldos           []float64 // Saldo de cada deuda al cierre del mes
+	Interes          float64   // Con IVA
+	InteresAcumulado float64
+	Liquidadas       []string // Deudas que se terminan de pagar este mes
+}
+
+// SaldoTotal regresa la suma de los saldos al cierre del mes
+func (m MesPlan) SaldoTotal() float64 {
+	total := 0.0
+	for _, s := range m.Saldos {
+		total += s
+	}
+	return total
+}
+
+// PlanDeuda es el resultado de simular el pago de varias deudas con un presupuesto mensual
+type PlanDeuda struct {
+	Estrategia   string
+	Presupuesto  float64
+	Deudas       []DeudaPlan
+	Meses        []MesPlan
+	InteresTotal float64
+	Orden        []string // Deudas en el orden en que se liquidan
+}
+
+// ValidarEstrategia verifica que la estrategia sea una de las soportadas
+func ValidarEstrategia(estrategia string) error {
+	switch estrategia {
+	case EstrategiaAvalancha, EstrategiaBolaNieve:
+		return nil
+	}
+	return fmt.Errorf("Estrategia no soportada: %s (usa avalancha o bola-de-nieve)", estrategia)
+}
+
+// DeudasRegistradas regresa las tarjetas de crédito con deuda como deudas del plan
+func DeudasRegistradas(tarjetas Tarjetas) []DeudaPlan {
+	var deudas []DeudaPlan
+	for _, t := range tarjetas.Credito {
+		if t.Saldo > 0 {
+			deudas = append(deudas, DeudaPlan{Nombre: t.Nombre, Saldo: t.Saldo, TasaAnual: t.TasaInteres, LimiteCredito: t.LimiteCredito})
+		}
+	}
+	return deudas
+}
+
+// SimularPlanDeuda simula mes a mes el pago de las deudas: cada mes se cobra el interés con IVA, se
+// cubre el pago mínimo regulatorio de todas y el resto del presupuesto se aplica a la deuda
+// prioritaria de la estrategia. Lo que sobra al liquidar una deuda pasa a la siguiente.
+func SimularPlanDeuda(deudas []DeudaPlan, presupuesto float64, estrategia string) (PlanDeuda, error) {
+	plan := PlanDeuda{Estrategia: estrategia, Presupuesto: presupuesto, Deudas: deudas}
+	if err := ValidarEstrategia(estrategia); err != nil {
+		return plan, err
+	}
+
+	// Orden de prioridad según la estrategia
+	prioridad := make([]int, len(deudas))
+	for i := range prioridad {
+		prioridad[i] = i
+	}
+	sort.SliceStable(prioridad, func(a, b int) bool {
+		da, db := deudas[prioridad[a]], deudas[prioridad[b]]
+		if estrategia == EstrategiaAvalancha {
+			return da.TasaAnual > db.TasaAnual
+		}
+		return da.Saldo < db.Saldo
+	})
+
+	// El mínimo del primer mes es el más alto; después baja junto con los saldos
+	saldos := make([]float64, len(deudas))
+	minimo := 0.0
+	for i, d := range deudas {
+		saldos[i] = d.Saldo
+		minimo += calc.PagoMinimoRegulatorio(d.Saldo, d.Saldo*d.TasaAnual/12, d.LimiteCredito)
+	}
+	if presupuesto < minimo-0.005 {
+		return plan, fmt.Errorf("El presupuesto de $%.2f no cubre los pagos mínimos ($%.2f)", presupuesto, minimo)
+	}
+
+	for mes := 1; ; mes++ {
+		pendiente := false
+		for _, s := range saldos {
+			if s > 0.005 {
+				pendiente = true
+			}
+		}
+		if !pendiente {
+			break
+		}
+		if mes > limiteMesesPlan {
+			return plan, fmt.Errorf("Con $%.2f al mes las deudas no se liquidan en %d meses", presupuesto, limiteMesesPlan)
+		}
+
+		m := MesPlan{Mes: mes, Pagos: make([]float64, len(deudas)), InteresAcumulado: plan.InteresTotal}
+		minimos := make([]float64, len(deudas))
+		for i, d := range deudas {
+			interes := saldos[i] * d.TasaAnual / 12
+			minimos[i] = calc.PagoMinimoRegulatorio(saldos[i], interes, d.LimiteCredito)
+			saldos[i] += interes * (1 + IVA)
+			m.Interes += interes * (1 + IVA)
+		}
+
+		// Pagos mínimos, sin pasarse del presupuesto
+		disponible := presupuesto
+		for i := range deudas {
+			pago := math.Min(minimos[i], disponible)
+			m.Pagos[i] = pago
+			saldos[i] -= pago
+			disponible -= pago
+		}
+
+		// El resto va a las deudas por orden de prioridad
+		for _, i := range prioridad {
+			if disponible <= 0 || saldos[i] <= 0 {
+				continue
+			}
+			if m.Foco == "" {
+				m.Foco = deudas[i].Nombre
+			}
+			pago := disponible
+			if pago > saldos[i] {
+				pago = saldos[i]
+			}
+			m.Pagos[i] += pago
+			saldos[i] -= pago
+			disponible -= pago
+		}
+
+		for i, d := range deudas {
+			if saldos[i] < 0.005 && m.Pagos[i] > 0 {
+				saldos[i] = 0
+				m.Liquidadas = append(m.Liquidadas, d.Nombre)
+				plan.Orden = append(plan.Orden, d.Nombre)
+			}
+		}
+
+		plan.InteresTotal += m.Interes
+		m.InteresAcumulado = plan.InteresTotal
+		m.Saldos = append([]float64(nil), saldos...)
+		plan.Meses = append(plan.Meses, m)
+	}
+
+	return plan, nil
+}
+
+// TotalPagado suma todos los pagos del plan: los saldos iniciales más los intereses
+func (p PlanDeuda) TotalPagado() float64 {
+	total := 0.0
+	for _, m := range p.Meses {
+		for _, pago := range m.Pagos {
+			total += pago
+		}
+	}
+	return total
+}
+
+// MesLiquidacion regresa el mes del plan (empezando en 1) en que se termina de pagar la
+// deuda, o 0 si no se liquida
+func (p PlanDeuda) MesLiquidacion(nombre string) int {
+	for _, m := range p.Meses {
+		for _, l := range m.Liquidadas {
+			if l == nombre {
+				return m.Mes
+			}
+		}
+	}
+	return 0
+}
+
+// EstrategiasDeuda son las estrategias que compara deuda plan, en el orden en que se muestran
+var EstrategiasDeuda = []string{EstrategiaAvalancha, EstrategiaBolaNieve}
+
+// CompararEstrategiasDeuda simula el plan con cada estrategia y regresa los planes junto
+// con el índice del más barato: el de menos intereses y, si empatan, el más corto
+func CompararEstrategiasDeuda(deudas []DeudaPlan, presupuesto float64) ([]PlanDeuda, int, error) {
+	planes := make([]PlanDeuda, 0, len(EstrategiasDeuda))
+	mejor := 0
+	for i, estrategia := range EstrategiasDeuda {
+		plan, err := SimularPlanDeuda(deudas, presupuesto, estrategia)
+		if err != nil {
+			return nil, 0, err
+		}
+		planes = append(planes, plan)
+
+		b := planes[mejor]
+		if plan.InteresTotal < b.InteresTotal-0.005 ||
+			(math.Abs(plan.InteresTotal-b.InteresTotal) <= 0.005 && len(plan.Meses) < len(b.Meses)) {
+			mejor = i
+		}
+	}
+	return planes, mejor, nil
+}
+
+// BarraProgreso dibuja una barra de ancho fijo con la fracción indicada rellena
+func BarraProgreso(fraccion float64, ancho int) string {
+	if fraccion < 0 {
+		fraccion = 0
+	}
+	if fraccion > 1 {
+		fraccion = 1
+	}
+	llenos := int(fraccion*float64(ancho) + 0.5)
+	return strings.Repeat("█", llenos) + strings.Repeat("░", ancho-llenos)
+}
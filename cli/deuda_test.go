@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPlanDeudaNoPagaMasQueElPresupuesto(t *testing.T) {
+	deudas := []DeudaPlan{{Nombre: "Oro", Saldo: 10000, TasaAnual: 0.36}, {Nombre: "Azul", Saldo: 3000, TasaAnual: 0.5}}
+	for _, estrategia := range EstrategiasDeuda {
+		plan, err := SimularPlanDeuda(deudas, 1000, estrategia)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range plan.Meses {
+			total := 0.0
+			for _, pago := range m.Pagos {
+				if pago < 0 {
+					t.Fatalf("%s mes %d: pago negativo %v", estrategia, m.Mes, m.Pagos)
+				}
+				total += pago
+			}
+			if total > 1000+1e-6 {
+				t.Fatalf("%s mes %d: se pagaron $%.2f con un presupuesto de $1000", estrategia, m.Mes, total)
+			}
+		}
+		if math.Abs(plan.TotalPagado()-13000-plan.InteresTotal) > 1e-6 {
+			t.Errorf("%s: lo pagado (%.2f) debe ser la deuda más los intereses con IVA (%.2f)", estrategia, plan.TotalPagado(), plan.InteresTotal)
+		}
+	}
+}
+
+func TestPlanDeudaPresupuestoInsuficiente(t *testing.T) {
+	// Mínimo regulatorio del primer mes: 1.5% de $10,000 más $300 de interés con IVA son $498,
+	// pero con un límite de $50,000 manda el 1.25% del límite, $625
+	deudas := []DeudaPlan{{Nombre: "Oro", Saldo: 10000, TasaAnual: 0.36}}
+	if _, err := SimularPlanDeuda(deudas, 497, EstrategiaAvalancha); err == nil {
+		t.Error("un presupuesto menor al mínimo con intereses debe rechazarse")
+	}
+	if _, err := SimularPlanDeuda(deudas, 498, EstrategiaAvalancha); err != nil {
+		t.Errorf("el presupuesto que cubre justo el mínimo debe aceptarse: %v", err)
+	}
+	deudas[0].LimiteCredito = 50000
+	if _, err := SimularPlanDeuda(deudas, 600, EstrategiaAvalancha); err == nil {
+		t.Error("el mínimo por límite de crédito también debe cubrirse")
+	}
+	if _, err := SimularPlanDeuda(deudas, 625, EstrategiaAvalancha); err != nil {
+		t.Errorf("el presupuesto que cubre justo el mínimo debe aceptarse: %v", err)
+	}
+}
+
+func TestPlanDeudaOrdenPorEstrategia(t *testing.T) {
+	deudas := []DeudaPlan{{Nombre: "Cara", Saldo: 20000, TasaAnual: 0.6}, {Nombre: "Chica", Saldo: 2000, TasaAnual: 0.3}}
+	avalancha, err := SimularPlanDeuda(deudas, 3000, EstrategiaAvalancha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bola, err := SimularPlanDeuda(deudas, 3000, EstrategiaBolaNieve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avalancha.Meses[0].Foco != "Cara" || bola.Meses[0].Foco != "Chica" {
+		t.Errorf("foco del primer mes: avalancha %s, bola de nieve %s", avalancha.Meses[0].Foco, bola.Meses[0].Foco)
+	}
+	if avalancha.InteresTotal > bola.InteresTotal {
+		t.Errorf("la avalancha (%.2f) no debe pagar más intereses que la bola de nieve (%.2f)", avalancha.InteresTotal, bola.InteresTotal)
+	}
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"finmex/banxico"
+	"finmex/calc"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Monedas de las cuentas de débito
+const (
+	MonedaMXN = "MXN"
+	MonedaUSD = "USD"
+)
+
+// TIPO_CAMBIO_USD es el tipo de cambio estimado en pesos por dólar que se usa si no se indica
+// otro y no hay dato de Banxico
+const TIPO_CAMBIO_USD = 18.50
+
+// SENSIBILIDAD_CAMBIARIA es el movimiento del tipo de cambio con el que se muestra el riesgo
+// cambiario de una cuenta en dólares
+const SENSIBILIDAD_CAMBIARIA = 0.10
+
+// limitesVariacionCambiaria acota el cambio esperado del tipo de cambio en un año
+var limitesVariacionCambiaria = LimitesNumero{Min: -0.5, Max: 0.5, Porcentaje: true}
+
+var (
+	// tipoCambioConfigurado es el valor de --tipo-cambio; en cero se consulta Banxico
+	tipoCambioConfigurado float64
+	// variacionCambiaria es el valor de --variacion-cambiaria
+	variacionCambiaria float64
+)
+
+var tipoCambioVigente struct {
+	sync.Once
+	valor  float64
+	origen string
+}
+
+// flagsTipoCambio son las opciones globales para convertir las cuentas en dólares a pesos
+func flagsTipoCambio() []cli.Flag {
+	return []cli.Flag{
+		conLimites(&cli.Float64Flag{Name: "tipo-cambio", Usage: "Pesos por dólar; por defecto el FIX publicado por Banxico", EnvVars: []string{"FINMEX_TIPO_CAMBIO"}}, limitesMonto),
+		conLimites(&cli.Float64Flag{Name: "variacion-cambiaria", Usage: "Cambio esperado del dólar frente al peso en un año (0.05 si sube 5%)", EnvVars: []string{"FINMEX_VARIACION_CAMBIARIA"}}, limitesVariacionCambiaria),
+	}
+}
+
+// configurarTipoCambio aplica --tipo-cambio y --variacion-cambiaria
+func configurarTipoCambio(tipoCambio, variacion float64) error {
+	if tipoCambio < 0 {
+		return fmt.Errorf("El tipo de cambio no puede ser negativo: %.4f", tipoCambio)
+	}
+	if variacion <= -1 {
+		return fmt.Errorf("La variación cambiaria debe ser mayor a -1: %.4f", variacion)
+	}
+	tipoCambioConfigurado, variacionCambiaria = tipoCambio, variacion
+	return nil
+}
+
+// TipoCambioVigente regresa los pesos por dólar y de dónde salieron: --tipo-cambio, el último
+// FIX de Banxico o TIPO_CAMBIO_USD como estimación. Banxico se consulta una sola vez por
+// ejecución.
+func TipoCambioVigente() (float64, string) {
+	tipoCambioVigente.Do(func() {
+		if tipoCambioConfigurado > 0 {
+			tipoCambioVigente.valor, tipoCambioVigente.origen = tipoCambioConfigurado, "--tipo-cambio"
+			return
+		}
+		tipoCambioVigente.valor, tipoCambioVigente.origen = TIPO_CAMBIO_USD, "estimado"
+		datos, err := clienteBanxico().Oportuno(banxico.SerieFIX)
+		if err != nil && !errors.Is(err, banxico.ErrSinToken) {
+			registro.Warn("no se pudo actualizar el tipo de cambio desde Banxico", "error", err)
+		}
+		if len(datos) > 0 {
+			tipoCambioVigente.valor = datos[0].Valor
+			tipoCambioVigente.origen = "FIX Banxico " + datos[0].Fecha
+		}
+	})
+	return tipoCambioVigente.valor, tipoCambioVigente.origen
+}
+
+// VariacionCambiaria es el cambio esperado del tipo de cambio en un año; 0 supone que se queda
+// igual
+func VariacionCambiaria() float64 {
+	return variacionCambiaria
+}
+
+// ValidarMoneda normaliza la moneda de una cuenta; vacía es MXN
+func ValidarMoneda(moneda string) (string, error) {
+	switch m := strings.ToUpper(strings.TrimSpace(moneda)); m {
+	case "", MonedaMXN:
+		return MonedaMXN, nil
+	case MonedaUSD:
+		return MonedaUSD, nil
+	default:
+		return "", errDatosInvalidos(
+			fmt.Sprintf("Moneda no válida: %s (usa %s o %s)", moneda, MonedaMXN, MonedaUSD),
+			fmt.Sprintf("Invalid currency: %s (use %s or %s)", moneda, MonedaMXN, MonedaUSD))
+	}
+}
+
+// MonedaCuenta regresa la moneda de la cuenta, MXN si no tiene
+func MonedaCuenta(t TarjetaDebito) string {
+	if t.Moneda == "" {
+		return MonedaMXN
+	}
+	return t.Moneda
+}
+
+// enDolares indica si la cuenta está en dólares
+func enDolares(t TarjetaDebito) bool {
+	return MonedaCuenta(t) == MonedaUSD
+}
+
+// tipoCambioMoneda regresa los pesos que vale una unidad de la moneda
+func tipoCambioMoneda(moneda string) float64 {
+	if moneda == MonedaUSD {
+		tipoCambio, _ := TipoCambioVigente()
+		return tipoCambio
+	}
+	return 1
+}
+
+// SaldoEnPesos regresa el saldo registrado de la cuenta convertido a pesos
+func SaldoEnPesos(t TarjetaDebito) float64 {
+	return t.Saldo * tipoCambioMoneda(MonedaCuenta(t))
+}
+
+// saldoEnMoneda convierte un saldo en pesos a la moneda de la cuenta
+func saldoEnMoneda(t TarjetaDebito, pesos float64) float64 {
+	return pesos / tipoCambioMoneda(MonedaCuenta(t))
+}
+
+// cuentaEnPesos expresa una cuenta en dólares como una cuenta en pesos equivalente: los topes,
+// el saldo mínimo y las comisiones al tipo de cambio de hoy y cada tasa con la variación
+// cambiaria esperada. Sirve para proyecciones de varios años; las cuentas en pesos no cambian.
+func cuentaEnPesos(t TarjetaDebito) TarjetaDebito {
+	if !enDolares(t) {
+		return t
+	}
+	tipoCambio, variacion := tipoCambioMoneda(MonedaUSD), VariacionCambiaria()
+	equivalente := t
+	equivalente.Moneda = ""
+	equivalente.Tramos = nil
+	for _, tramo := range t.TramosOrdenados() {
+		equivalente.Tramos = append(equivalente.Tramos, TramoRendimiento{Hasta: tramo.Hasta * tipoCambio, Tasa: calc.TasaEnPesos(tramo.Tasa, variacion)})
+	}
+	equivalente.TasaRendimiento = 0
+	equivalente.SaldoMinimo *= tipoCambio
+	equivalente.ComisionAnual *= tipoCambio * (1 + variacion)
+	equivalente.ComisionInactividad *= tipoCambio * (1 + variacion)
+	equivalente.Saldo *= tipoCambio
+	return equivalente
+}
+
+// formatoMoneda muestra un monto con el símbolo de su moneda
+func formatoMoneda(moneda string, monto float64) string {
+	if moneda == MonedaUSD {
+		return fmt.Sprintf("US$%.2f", monto)
+	}
+	return fmt.Sprintf("$%.2f", monto)
+}
+
+// riesgoCambiario calcula el riesgo cambiario de la cuenta con un saldo en pesos; nil si la
+// cuenta está en pesos
+func riesgoCambiario(t TarjetaDebito, saldo float64) *RiesgoCambiario {
+	if !enDolares(t) {
+		return nil
+	}
+	tipoCambio := tipoCambioMoneda(MonedaUSD)
+	r := &RiesgoCambiario{Moneda: MonedaUSD, TipoCambio: tipoCambio, Variacion: VariacionCambiaria(), Sensibilidad: SENSIBILIDAD_CAMBIARIA}
+	escenario := func(variacion float64) float64 {
+		real, _, _ := calc.RendimientoRealDivisa(t, saldo/tipoCambio, tipoCambio, variacion, InflacionVigente(), ISRVigente())
+		return real
+	}
+	r.RealSiApreciaMXN = escenario(r.Variacion - SENSIBILIDAD_CAMBIARIA)
+	r.RealSiDepreciaMXN = escenario(r.Variacion + SENSIBILIDAD_CAMBIARIA)
+	return r
+}
+
+// imprimirRiesgoCambiario avisa cuánto cambia la ganancia real de una cuenta en dólares si el
+// tipo de cambio se mueve
+func imprimirRiesgoCambiario(nombre string, r *RiesgoCambiario) {
+	if r == nil {
+		return
+	}
+	fmt.Printf("AVISO: %s está en dólares (riesgo cambiario): con el dólar %.0f%% más barato ganarías $%.2f reales; %.0f%% más caro, $%.2f\n",
+		nombre, r.Sensibilidad*100, r.RealSiApreciaMXN, r.Sensibilidad*100, r.RealSiDepreciaMXN)
+}
+
+// imprimirTipoCambio muestra el tipo de cambio de la comparación si alguna cuenta está en
+// dólares
+func imprimirTipoCambio(cuentas []TarjetaDebito) {
+	for _, t := range cuentas {
+		if enDolares(t) {
+			tipoCambio, origen := TipoCambioVigente()
+			fmt.Printf("Montos en pesos; las cuentas en dólares se convierten a $%.4f por dólar (%s), variación esperada de %.1f%%\n",
+				tipoCambio, origen, VariacionCambiaria()*100)
+			return
+		}
+	}
+}
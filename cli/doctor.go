@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"finmex/calc"
+)
+
+// Severidades de un hallazgo del diagnóstico
+const (
+	SeveridadError = "error" // El dato está mal y los cálculos salen equivocados
+	SeveridadAviso = "aviso" // El dato es sospechoso o incompleto; conviene revisarlo
+)
+
+// Hallazgo es una inconsistencia en los datos registrados con la corrección sugerida
+type Hallazgo struct {
+	Severidad  string `json:"severidad"`
+	Producto   string `json:"producto"` // debito, credito, cetes, sofipo o pagare
+	Nombre     string `json:"nombre"`
+	Problema   string `json:"problema"`
+	Sugerencia string `json:"sugerencia"`
+	// Corregible indica que la corrección es segura y la aplica 'doctor --corregir'
+	Corregible bool `json:"corregible"`
+	corregir   func(tarjetas *Tarjetas)
+}
+
+// CorregirHallazgos aplica las correcciones seguras de los hallazgos y regresa cuántas aplicó
+func CorregirHallazgos(tarjetas *Tarjetas, hallazgos []Hallazgo) int {
+	aplicadas := 0
+	for _, h := range hallazgos {
+		if h.Corregible && h.corregir != nil {
+			h.corregir(tarjetas)
+			aplicadas++
+		}
+	}
+	return aplicadas
+}
+
+// diagnostico junta los hallazgos mientras se revisan los productos
+type diagnostico struct {
+	hallazgos []Hallazgo
+}
+
+func (d *diagnostico) agregar(severidad, producto, nombre, problema, sugerencia string) {
+	d.hallazgos = append(d.hallazgos, Hallazgo{Severidad: severidad, Producto: producto, Nombre: nombre, Problema: problema, Sugerencia: sugerencia})
+}
+
+// tasaPorcentaje revisa una tasa que debería estar en decimal. Si parece capturada como
+// porcentaje (36 en lugar de 0.36) agrega un hallazgo corregible; fijar recibe las tarjetas y
+// el valor corregido.
+func (d *diagnostico) tasaPorcentaje(severidad, producto, nombre, campo string, valor float64, limites LimitesNumero, fijar func(*Tarjetas, float64)) {
+	if !parecePorcentaje(valor, limites) {
+		return
+	}
+	decimal := comoDecimal(valor)
+	d.hallazgos = append(d.hallazgos, Hallazgo{
+		Severidad:  severidad,
+		Producto:   producto,
+		Nombre:     nombre,
+		Problema:   fmt.Sprintf("%s es %g, parece un porcentaje y no un decimal", campo, valor),
+		Sugerencia: fmt.Sprintf("Cambia el valor a %g para %g%%", decimal, valor),
+		Corregible: true,
+		corregir:   func(t *Tarjetas) { fijar(t, decimal) },
+	})
+}
+
+// DiagnosticarDatos revisa la consistencia de los datos registrados: tasas capturadas como
+// porcentaje, CAT menor a la tasa, nombres repetidos, límites en cero y fechas o días que no
+// se pueden interpretar. Los hallazgos salen por producto en el orden en que están guardados.
+func DiagnosticarDatos(tarjetas Tarjetas) []Hallazgo {
+	d := &diagnostico{}
+	for i, t := range tarjetas.Debito {
+		d.revisarDebito(i, t)
+	}
+	for i, t := range tarjetas.Credito {
+		d.revisarCredito(i, t)
+	}
+	for i, inv := range tarjetas.Cetes {
+		i := i
+		d.tasaPorcentaje(SeveridadError, "cetes", inv.Nombre, "La tasa", inv.Tasa, limitesTasa,
+			func(t *Tarjetas, v float64) { t.Cetes[i].Tasa = v })
+	}
+	for i, s := range tarjetas.Sofipos {
+		for j, tramo := range s.Tramos {
+			i, j := i, j
+			d.tasaPorcentaje(SeveridadError, "sofipo", s.Nombre, fmt.Sprintf("La tasa del tramo %d", j+1), tramo.Tasa, limitesTasa,
+				func(t *Tarjetas, v float64) { t.Sofipos[i].Tramos[j].Tasa = v })
+		}
+	}
+	for i, p := range tarjetas.Pagares {
+		i := i
+		d.tasaPorcentaje(SeveridadError, "pagare", p.Nombre, "La tasa", p.Tasa, limitesTasa,
+			func(t *Tarjetas, v float64) { t.Pagares[i].Tasa = v })
+	}
+	d.revisarNombres(tarjetas)
+	return d.hallazgos
+}
+
+// revisarDebito revisa una cuenta de débito
+func (d *diagnostico) revisarDebito(i int, t TarjetaDebito) {
+	if len(t.Tramos) == 0 {
+		d.agregar(SeveridadAviso, "debito", t.Nombre, "No tiene tasa de rendimiento",
+			fmt.Sprintf("Si la cuenta paga intereses agrégalos con 'finmex debito editar --tasa <tasa> \"%s\"'", t.Nombre))
+	}
+	sinTope := 0
+	for j, tramo := range t.Tramos {
+		j := j
+		d.tasaPorcentaje(SeveridadError, "debito", t.Nombre, fmt.Sprintf("La tasa del tramo %d", j+1), tramo.Tasa, limitesTasa,
+			func(t *Tarjetas, v float64) { t.Debito[i].Tramos[j].Tasa = v })
+		if tramo.Hasta == 0 {
+			sinTope++
+		}
+	}
+	if sinTope > 1 {
+		d.agregar(SeveridadError, "debito", t.Nombre, fmt.Sprintf("Tiene %d tramos sin tope; solo el último puede no tenerlo", sinTope),
+			"Indica hasta qué monto aplica cada tramo menos el último")
+	}
+	if _, err := ValidarMoneda(t.Moneda); err != nil {
+		d.agregar(SeveridadError, "debito", t.Nombre, fmt.Sprintf("La moneda '%s' no es válida", t.Moneda),
+			fmt.Sprintf("Usa %s o %s", MonedaMXN, MonedaUSD))
+	}
+	if t.Saldo < 0 {
+		d.agregar(SeveridadError, "debito", t.Nombre, fmt.Sprintf("El saldo es negativo ($%.2f)", t.Saldo),
+			"Una cuenta de débito no puede quedar en negativo; registra el saldo actual")
+	}
+}
+
+// revisarCredito revisa una tarjeta de crédito
+func (d *diagnostico) revisarCredito(i int, t TarjetaCredito) {
+	if t.TasaInteres == 0 && t.CAT == 0 {
+		d.agregar(SeveridadAviso, "credito", t.Nombre, "No tiene tasa de interés ni CAT",
+			fmt.Sprintf("Captúralos del estado de cuenta con 'finmex credito editar \"%s\"'", t.Nombre))
+	}
+	// Una tasa de crédito arriba de 100% existe, así que en la tasa y el CAT es aviso y no error
+	d.tasaPorcentaje(SeveridadAviso, "credito", t.Nombre, "La tasa de interés", t.TasaInteres, limitesTasa,
+		func(t *Tarjetas, v float64) { t.Credito[i].TasaInteres = v })
+	d.tasaPorcentaje(SeveridadAviso, "credito", t.Nombre, "El CAT", t.CAT, limitesTasa,
+		func(t *Tarjetas, v float64) { t.Credito[i].CAT = v })
+	d.tasaPorcentaje(SeveridadError, "credito", t.Nombre, "El cashback", t.BeneficiosCashback, limitesFraccion,
+		func(t *Tarjetas, v float64) { t.Credito[i].BeneficiosCashback = v })
+	categorias := make([]string, 0, len(t.Categorias))
+	for categoria := range t.Categorias {
+		categorias = append(categorias, categoria)
+	}
+	sort.Strings(categorias)
+	for _, categoria := range categorias {
+		categoria := categoria
+		d.tasaPorcentaje(SeveridadError, "credito", t.Nombre, fmt.Sprintf("El cashback de %s", categoria), t.Categorias[categoria], limitesFraccion,
+			func(t *Tarjetas, v float64) { t.Credito[i].Categorias[categoria] = v })
+	}
+
+	// El CAT incluye la tasa más comisiones e IVA, así que nunca es menor; solo se compara si
+	// ninguno de los dos parece porcentaje, porque entonces el problema es otro
+	if t.CAT > 0 && t.CAT < t.TasaInteres && !parecePorcentaje(t.TasaInteres, limitesTasa) && !parecePorcentaje(t.CAT, limitesTasa) {
+		d.agregar(SeveridadError, "credito", t.Nombre,
+			fmt.Sprintf("El CAT (%.2f%%) es menor a la tasa de interés (%.2f%%)", t.CAT*100, t.TasaInteres*100),
+			"El CAT suma la tasa, comisiones e IVA; revisa si están invertidos o si la tasa es la mensual")
+	}
+
+	if t.LimiteCredito == 0 {
+		d.agregar(SeveridadAviso, "credito", t.Nombre, "El límite de crédito es cero",
+			fmt.Sprintf("Sin límite no se calcula la utilización ni el crédito disponible; captúralo con 'finmex credito editar --limite <monto> \"%s\"'", t.Nombre))
+	} else if t.Saldo > t.LimiteCredito {
+		d.agregar(SeveridadAviso, "credito", t.Nombre,
+			fmt.Sprintf("La deuda ($%.2f) es mayor al límite de crédito ($%.2f)", t.Saldo, t.LimiteCredito),
+			"Revisa si el límite cambió o si la deuda incluye los MSI, que van en planes aparte")
+	}
+
+	switch {
+	case (t.DiaCorte == 0) != (t.DiaLimitePago == 0):
+		d.agregar(SeveridadAviso, "credito", t.Nombre, "Solo tiene uno de los días de corte y de límite de pago",
+			"Captura los dos para calcular el periodo de gracia")
+	case t.DiaCorte < 0 || t.DiaCorte > 31 || t.DiaLimitePago < 0 || t.DiaLimitePago > 31:
+		d.agregar(SeveridadError, "credito", t.Nombre,
+			fmt.Sprintf("Los días de corte (%d) y de límite de pago (%d) deben estar entre 1 y 31", t.DiaCorte, t.DiaLimitePago),
+			"Captura el día del mes que aparece en el estado de cuenta")
+	}
+
+	if t.FechaAnualidad != "" {
+		if _, err := time.Parse("2006-01-02", t.FechaAnualidad); err != nil {
+			d.agregar(SeveridadError, "credito", t.Nombre, fmt.Sprintf("La fecha de anualidad '%s' no es válida", t.FechaAnualidad),
+				"Usa el formato AAAA-MM-DD")
+		}
+	}
+	if t.FacturacionParaCondonar > 0 && t.ComisionAnual == 0 {
+		d.agregar(SeveridadAviso, "credito", t.Nombre, "Tiene meta de facturación para condonar la anualidad pero no tiene anualidad",
+			"Captura la anualidad o quita la meta de facturación")
+	}
+	if t.FormaCashback != "" && t.FormaCashback != calc.CashbackAbono && t.FormaCashback != calc.CashbackPuntos {
+		d.agregar(SeveridadError, "credito", t.Nombre, fmt.Sprintf("La forma de cashback '%s' no es válida", t.FormaCashback),
+			fmt.Sprintf("Usa %s o %s con 'finmex credito cashback configurar --forma <forma> \"%s\"'", calc.CashbackAbono, calc.CashbackPuntos, t.Nombre))
+	}
+	if t.ValorPunto > 1 {
+		d.agregar(SeveridadAviso, "credito", t.Nombre, fmt.Sprintf("Cada peso en puntos vale $%.2f al canjearlo", t.ValorPunto),
+			"El valor del punto suele ser menor a 1; revisa si se capturó el de un punto y no el de un peso")
+	}
+
+	for j, p := range t.Planes {
+		plan := fmt.Sprintf("El plan %d (%s)", j+1, p.Concepto)
+		if p.Monto <= 0 || p.Meses <= 0 {
+			d.agregar(SeveridadError, "credito", t.Nombre, fmt.Sprintf("%s tiene monto $%.2f a %d meses", plan, p.Monto, p.Meses),
+				"El monto y los meses deben ser mayores a cero; quita el plan si ya no existe")
+		}
+		if _, err := time.Parse("2006-01", p.Inicio); err != nil {
+			d.agregar(SeveridadError, "credito", t.Nombre, fmt.Sprintf("%s empieza en '%s', que no es un mes válido", plan, p.Inicio),
+				"Usa el formato AAAA-MM del mes de la primera mensualidad")
+		}
+	}
+}
+
+// revisarNombres busca nombres repetidos sin distinguir mayúsculas ni acentos. Un nombre
+// repetido en la misma familia deja inalcanzable al segundo; entre débito y crédito obliga a
+// indicar el tipo en los comandos que buscan en ambas.
+func (d *diagnostico) revisarNombres(tarjetas Tarjetas) {
+	productos := []struct {
+		producto string
+		nombres  []string
+	}{
+		{"debito", nombresDebito(tarjetas)},
+		{"credito", nombresCredito(tarjetas)},
+		{"cetes", nombresLista(len(tarjetas.Cetes), func(i int) string { return tarjetas.Cetes[i].Nombre })},
+		{"sofipo", nombresLista(len(tarjetas.Sofipos), func(i int) string { return tarjetas.Sofipos[i].Nombre })},
+		{"pagare", nombresLista(len(tarjetas.Pagares), func(i int) string { return tarjetas.Pagares[i].Nombre })},
+	}
+	for _, p := range productos {
+		vistos := map[string]bool{}
+		for _, nombre := range p.nombres {
+			clave := normalizarClave(nombre)
+			if clave == "" {
+				d.agregar(SeveridadError, p.producto, nombre, "No tiene nombre", "Ponle un nombre para poder consultarla")
+				continue
+			}
+			if vistos[clave] {
+				d.agregar(SeveridadError, p.producto, nombre, "El nombre está repetido",
+					"Renombra una de las dos; los comandos solo encuentran la primera")
+			}
+			vistos[clave] = true
+		}
+	}
+
+	debito := map[string]bool{}
+	for _, nombre := range productos[0].nombres {
+		debito[normalizarClave(nombre)] = true
+	}
+	for _, nombre := range productos[1].nombres {
+		if clave := normalizarClave(nombre); clave != "" && debito[clave] {
+			d.agregar(SeveridadAviso, "credito", nombre, "Hay una cuenta de débito con el mismo nombre",
+				"Renómbrala o indica el tipo (debito o credito) en los comandos que buscan en ambas")
+		}
+	}
+}
+
+// nombresLista regresa los nombres de una lista de productos
+func nombresLista(n int, nombre func(int) string) []string {
+	nombres := make([]string, n)
+	for i := range nombres {
+		nombres[i] = nombre(i)
+	}
+	return nombres
+}
@@ -0,0 +1,537 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// entradaEstandar es el lector compartido por todas las preguntas interactivas. Se lee
+// línea por línea para que un valor inválido no deje basura para la siguiente pregunta.
+var entradaEstandar = bufio.NewReader(os.Stdin)
+
+// ErrEntradaTerminada indica que se cerró la entrada antes de obtener un valor válido
+var ErrEntradaTerminada = errors.New("La entrada terminó antes de capturar todos los datos")
+
+// LimitesNumero define el rango aceptado para un valor capturado
+type LimitesNumero struct {
+	Min float64
+	Max float64
+	// Porcentaje indica una tasa o fracción en decimal, que es fácil capturar por error
+	// como porcentaje (36 en lugar de 0.36)
+	Porcentaje bool
+}
+
+// Rangos de uso común al capturar datos
+var (
+	limitesMonto        = LimitesNumero{Min: 0, Max: 1e11}                   // Montos en pesos
+	limitesTasa         = LimitesNumero{Min: 0, Max: 10, Porcentaje: true}   // Tasas en decimal (hasta 1000%)
+	limitesFraccion     = LimitesNumero{Min: 0, Max: 1, Porcentaje: true}    // Fracciones de un monto, como el enganche
+	limitesTasaMarginal = LimitesNumero{Min: 0, Max: 0.35, Porcentaje: true} // Tasa marginal de ISR
+	limitesLibre        = LimitesNumero{Min: -1e11, Max: 1e11}               // Montos que pueden ser negativos
+)
+
+// multiplicadores reconocidos al final de un monto (1.5k, 2mil, 3m)
+var multiplicadores = []struct {
+	sufijo string
+	factor float64
+}{
+	{"millones", 1e6},
+	{"millon", 1e6},
+	{"millón", 1e6},
+	{"mil", 1e3},
+	{"mm", 1e6},
+	{"k", 1e3},
+	{"m", 1e6},
+}
+
+// ParsearNumero interpreta un número capturado por una persona: acepta signo de pesos,
+// separadores de miles (1,500.50 o 1 500), coma decimal (1500,50), sufijos de miles y
+// millones (1.5k, 2mil, 3m) y porcentajes (36% se convierte en 0.36).
+func ParsearNumero(texto string) (float64, error) {
+	original := texto
+	texto = strings.ToLower(strings.TrimSpace(texto))
+	texto = strings.TrimPrefix(texto, "mxn")
+	texto = strings.TrimSuffix(texto, "mxn")
+	texto = strings.TrimSpace(texto)
+
+	if texto == "" {
+		return 0, errDatosInvalidos("no se capturó ningún valor", "no value was entered")
+	}
+
+	negativo := false
+	if strings.HasPrefix(texto, "-") {
+		negativo = true
+		texto = strings.TrimSpace(texto[1:])
+	}
+	texto = strings.TrimSpace(strings.TrimPrefix(texto, "$"))
+
+	factor := 1.0
+	if strings.HasSuffix(texto, "%") {
+		factor = 0.01
+		texto = strings.TrimSpace(strings.TrimSuffix(texto, "%"))
+	} else {
+		for _, m := range multiplicadores {
+			if strings.HasSuffix(texto, m.sufijo) {
+				factor = m.factor
+				texto = strings.TrimSpace(strings.TrimSuffix(texto, m.sufijo))
+				break
+			}
+		}
+	}
+
+	texto = strings.ReplaceAll(texto, " ", "")
+	texto, ok := normalizarSeparadores(texto)
+	if !ok {
+		return 0, errNumeroAmbiguo(original)
+	}
+
+	valor, err := strconv.ParseFloat(texto, 64)
+	if err != nil || texto == "" || strings.ContainsAny(texto, "eE") {
+		return 0, errNumeroInvalido(original)
+	}
+	if math.IsNaN(valor) || math.IsInf(valor, 0) {
+		return 0, errNumeroInvalido(original)
+	}
+
+	valor *= factor
+	if negativo {
+		valor = -valor
+	}
+	return valor, nil
+}
+
+// normalizarSeparadores deja el número con punto decimal y sin separadores de miles. La coma
+// y el punto siguen la misma regla:
+//   - Si aparecen los dos, el último es el decimal y el otro separa miles: 1.234,56 y
+//     1,234.56 son 1234.56.
+//   - Un solo separador seguido de exactamente tres dígitos, con parte entera distinta de
+//     cero, separa miles: 1,234 y 1.234 son 1234. En otro caso es el decimal: 0,5, 1.25, 0.125.
+//   - Un separador repetido separa miles: 1.500.000.
+//
+// Los separadores de miles deben dejar grupos de tres dígitos; si no, como en 1,23,4 o
+// 1.234.5, el número es ambiguo y se regresa falso.
+func normalizarSeparadores(texto string) (string, bool) {
+	comas, puntos := strings.Count(texto, ","), strings.Count(texto, ".")
+	switch {
+	case comas == 0 && puntos == 0:
+		return texto, true
+	case comas > 0 && puntos > 0:
+		miles, decimal := ",", "."
+		if strings.LastIndex(texto, ",") > strings.LastIndex(texto, ".") {
+			miles, decimal = ".", ","
+		}
+		i := strings.LastIndex(texto, decimal)
+		if strings.Count(texto, decimal) > 1 || !agrupaMiles(texto[:i], miles) {
+			return "", false
+		}
+		return strings.ReplaceAll(texto[:i], miles, "") + "." + texto[i+1:], true
+	}
+
+	separador := ","
+	if puntos > 0 {
+		separador = "."
+	}
+	if strings.Count(texto, separador) > 1 {
+		if !agrupaMiles(texto, separador) {
+			return "", false
+		}
+		return strings.ReplaceAll(texto, separador, ""), true
+	}
+	entera, decimales, _ := strings.Cut(texto, separador)
+	if len(decimales) == 3 && strings.Trim(entera, "0") != "" {
+		return entera + decimales, true
+	}
+	return entera + "." + decimales, true
+}
+
+// agrupaMiles indica si el separador deja grupos de tres dígitos después del primero
+func agrupaMiles(texto, separador string) bool {
+	grupos := strings.Split(texto, separador)
+	if len(grupos[0]) == 0 || len(grupos[0]) > 3 {
+		return false
+	}
+	for _, g := range grupos[1:] {
+		if len(g) != 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// formatearNumero escribe un número que ParsearNumero lee igual: 1.125 se escribe 1.1250
+// para que el punto no se tome como separador de miles
+func formatearNumero(v float64) string {
+	texto := strconv.FormatFloat(v, 'f', -1, 64)
+	entera, decimales, ok := strings.Cut(strings.TrimPrefix(texto, "-"), ".")
+	if ok && len(decimales) == 3 && strings.Trim(entera, "0") != "" {
+		texto += "0"
+	}
+	return texto
+}
+
+// leerLinea lee la siguiente línea de la entrada estándar sin el salto de línea
+func leerLinea() (string, error) {
+	linea, err := entradaEstandar.ReadString('\n')
+	if err != nil && (err != io.EOF || linea == "") {
+		return "", ErrEntradaTerminada
+	}
+	return normalizarTexto(strings.TrimRight(linea, "\r\n")), nil
+}
+
+// normalizarTexto deja un texto capturado en UTF-8 con los acentos compuestos. Las consolas
+// configuradas en Latin-1 mandan bytes que no son UTF-8 válido y algunas terminales mandan
+// la vocal y el acento por separado; así "Azúl" se guarda y se busca igual sin importar
+// desde dónde se escribió.
+func normalizarTexto(texto string) string {
+	if !utf8.ValidString(texto) {
+		texto = desdeLatin1([]byte(texto))
+	}
+	return norm.NFC.String(strings.TrimPrefix(texto, "\ufeff"))
+}
+
+// argumentoNombre une los argumentos del comando en un nombre, para que "Nu Morada" funcione
+// aunque no se escriba entre comillas
+func argumentoNombre(args []string) string {
+	return normalizarTexto(strings.Join(strings.Fields(strings.Join(args, " ")), " "))
+}
+
+// argumentosNombreMonto separa los argumentos de un comando <nombre> <monto>: el monto es el
+// último y el nombre es todo lo anterior, con o sin comillas
+func argumentosNombreMonto(c *cli.Context) (string, float64, error) {
+	args := c.Args().Slice()
+	if len(args) < 2 {
+		return "", 0, fmt.Errorf("Uso: %s %s", c.Command.HelpName, c.Command.ArgsUsage)
+	}
+	monto, err := parsearDecimal(args[len(args)-1], limitesMonto)
+	if err != nil {
+		return "", 0, err
+	}
+	nombre := argumentoNombre(args[:len(args)-1])
+	if nombre == "" {
+		return "", 0, fmt.Errorf("Uso: %s %s", c.Command.HelpName, c.Command.ArgsUsage)
+	}
+	return nombre, monto, nil
+}
+
+// leerTexto muestra la pregunta y regresa la línea capturada, sin espacios a los lados
+func leerTexto(pregunta string) (string, error) {
+	fmt.Print(pregunta)
+	linea, err := leerLinea()
+	return strings.TrimSpace(linea), err
+}
+
+// leerTextoRequerido repite la pregunta hasta que se capture un texto no vacío
+func leerTextoRequerido(pregunta string) (string, error) {
+	for {
+		texto, err := leerTexto(pregunta)
+		if err != nil || texto != "" {
+			return texto, err
+		}
+		fmt.Println("  Este dato es obligatorio. Intenta de nuevo.")
+	}
+}
+
+// leerNumero repite la pregunta hasta obtener un número válido dentro de los límites
+func leerNumero(pregunta string, limites LimitesNumero) (float64, error) {
+	for {
+		texto, err := leerTexto(pregunta)
+		if err != nil {
+			return 0, err
+		}
+
+		valor, err := interpretarNumero(texto, limites)
+		if err == nil {
+			return valor, nil
+		}
+
+		fmt.Printf("  Valor inválido: %v. Intenta de nuevo.\n", err)
+	}
+}
+
+// leerEntero repite la pregunta hasta obtener un entero entre min y max
+func leerEntero(pregunta string, min, max int) (int, error) {
+	for {
+		texto, err := leerTexto(pregunta)
+		if err != nil {
+			return 0, err
+		}
+
+		valor, err := strconv.Atoi(strings.TrimSpace(texto))
+		if err == nil && valor >= min && valor <= max {
+			return valor, nil
+		}
+
+		fmt.Printf("  Valor inválido: escribe un número entero entre %d y %d. Intenta de nuevo.\n", min, max)
+	}
+}
+
+// leerSiNo repite la pregunta hasta obtener una respuesta de sí o no
+func leerSiNo(pregunta string) (bool, error) {
+	for {
+		texto, err := leerTexto(pregunta)
+		if err != nil {
+			return false, err
+		}
+
+		if valor, ok := parsearSiNo(texto); ok {
+			return valor, nil
+		}
+
+		fmt.Println("  Responde 's' o 'n'.")
+	}
+}
+
+// parsearSiNo interpreta una respuesta de sí o no
+func parsearSiNo(texto string) (bool, bool) {
+	switch strings.ToLower(texto) {
+	case "s", "si", "sí", "y", "yes":
+		return true, true
+	case "n", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// leerTextoConValor muestra el valor actual entre corchetes y lo conserva si no se captura nada
+func leerTextoConValor(pregunta, actual string) (string, error) {
+	texto, err := leerTexto(fmt.Sprintf("%s [%s]: ", pregunta, actual))
+	if err != nil || texto == "" {
+		return actual, err
+	}
+	return texto, nil
+}
+
+// leerNumeroConValor es como leerNumero, pero conserva el valor actual si no se captura nada
+func leerNumeroConValor(pregunta string, actual float64, limites LimitesNumero) (float64, error) {
+	for {
+		texto, err := leerTexto(fmt.Sprintf("%s [%s]: ", pregunta, strconv.FormatFloat(actual, 'f', -1, 64)))
+		if err != nil || texto == "" {
+			return actual, err
+		}
+
+		valor, err := interpretarNumero(texto, limites)
+		if err == nil {
+			return valor, nil
+		}
+
+		fmt.Printf("  Valor inválido: %v. Intenta de nuevo.\n", err)
+	}
+}
+
+// leerSiNoConValor es como leerSiNo, pero conserva el valor actual si no se captura nada
+func leerSiNoConValor(pregunta string, actual bool) (bool, error) {
+	opcion := "n"
+	if actual {
+		opcion = "s"
+	}
+	for {
+		texto, err := leerTexto(fmt.Sprintf("%s [%s]: ", pregunta, opcion))
+		if err != nil || texto == "" {
+			return actual, err
+		}
+
+		if valor, ok := parsearSiNo(texto); ok {
+			return valor, nil
+		}
+
+		fmt.Println("  Responde 's' o 'n'.")
+	}
+}
+
+// errNumeroInvalido crea el error de un texto que no se pudo interpretar como número
+func errNumeroInvalido(texto string) error {
+	texto = strings.TrimSpace(texto)
+	return errDatosInvalidos(fmt.Sprintf("'%s' no es un número válido", texto), fmt.Sprintf("'%s' is not a valid number", texto))
+}
+
+// errNumeroAmbiguo crea el error de un número cuyos separadores no siguen la regla de
+// normalizarSeparadores, con la forma de escribirlo sin ambigüedad
+func errNumeroAmbiguo(texto string) error {
+	texto = strings.TrimSpace(texto)
+	return errDatosInvalidos(
+		fmt.Sprintf("'%s' es ambiguo; escribe los miles sin separador y los decimales con punto, p. ej. 1234.56", texto),
+		fmt.Sprintf("'%s' is ambiguous; write it without thousands separators and with a decimal point, e.g. 1234.56", texto))
+}
+
+// interpretarNumero convierte la respuesta a una pregunta y la valida. Si se pide una tasa en
+// decimal y el valor parece un porcentaje, se pregunta si se quiso decir el porcentaje.
+func interpretarNumero(texto string, limites LimitesNumero) (float64, error) {
+	valor, err := ParsearNumero(texto)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasSuffix(strings.TrimSpace(texto), "%") && puedeSerPorcentaje(valor, limites) {
+		porcentaje, err := leerSiNo(fmt.Sprintf("  ¿Quisiste decir %g%% (%g en decimal)? (s/n): ", valor, comoDecimal(valor)))
+		if err != nil {
+			return 0, err
+		}
+		if porcentaje {
+			valor = comoDecimal(valor)
+		}
+	}
+	return valor, validarLimites(valor, limites)
+}
+
+// parecePorcentaje indica si un valor en decimal se escribió como porcentaje: se sale del
+// rango y dividido entre cien sí cabe. Dentro del rango no se supone nada, porque un CAT de
+// 1.25 (125%) es común.
+func parecePorcentaje(valor float64, limites LimitesNumero) bool {
+	if !limites.Porcentaje || valor <= limites.Max {
+		return false
+	}
+	return cabeComoPorcentaje(valor, limites)
+}
+
+// puedeSerPorcentaje es la sospecha más amplia que se confirma con una pregunta: también un
+// valor de 1 o más que dividido entre cien cabe en el rango
+func puedeSerPorcentaje(valor float64, limites LimitesNumero) bool {
+	return parecePorcentaje(valor, limites) || limites.Porcentaje && valor >= 1 && cabeComoPorcentaje(valor, limites)
+}
+
+func cabeComoPorcentaje(valor float64, limites LimitesNumero) bool {
+	return valor/100 >= limites.Min && valor/100 <= limites.Max
+}
+
+// comoDecimal convierte un porcentaje a decimal sin el ruido del punto flotante (0.57 da
+// 0.0057 y no 0.005699999999999999)
+func comoDecimal(porcentaje float64) float64 {
+	return math.Round(porcentaje*1e8) / 1e10
+}
+
+// validarDecimal valida un valor que no se puede confirmar con una pregunta, como el de un
+// flag o un archivo: si parece un porcentaje se rechaza indicando cómo escribirlo
+func validarDecimal(valor float64, limites LimitesNumero) error {
+	if parecePorcentaje(valor, limites) {
+		return errDatosInvalidos(
+			fmt.Sprintf("%g parece un porcentaje; escribe %g para %g%%", valor, comoDecimal(valor), valor),
+			fmt.Sprintf("%g looks like a percentage; write %g for %g%%", valor, comoDecimal(valor), valor))
+	}
+	return validarLimites(valor, limites)
+}
+
+// parsearDecimal interpreta un número de un texto que no se puede confirmar, como una columna
+// de CSV o un parámetro de la API; con el signo de porcentaje no hay ambigüedad
+func parsearDecimal(texto string, limites LimitesNumero) (float64, error) {
+	valor, err := ParsearNumero(texto)
+	if err != nil {
+		return 0, err
+	}
+	if strings.HasSuffix(strings.TrimSpace(texto), "%") {
+		return valor, validarLimites(valor, limites)
+	}
+	return valor, validarDecimal(valor, limites)
+}
+
+// validarFlag valida el valor de un flag numérico con el nombre del flag en el error
+func validarFlag(nombre string, valor float64, limites LimitesNumero) error {
+	if err := validarDecimal(valor, limites); err != nil {
+		return fmt.Errorf("--%s: %w", nombre, err)
+	}
+	return nil
+}
+
+// conLimites valida el flag en cuanto se indica, antes de la acción del comando, para que
+// un monto negativo o una tasa escrita como porcentaje no lleguen a los cálculos. Las tasas
+// aceptan además el signo de porcentaje, p. ej. --tasa 120%.
+func conLimites(f *cli.Float64Flag, limites LimitesNumero) cli.Flag {
+	if !limites.Porcentaje {
+		f.Action = func(c *cli.Context, valor float64) error {
+			return validarFlag(f.Name, valor, limites)
+		}
+		return f
+	}
+	tasa := &flagTasa{Float64Flag: f}
+	f.Action = func(c *cli.Context, valor float64) error {
+		if tasa.valor != nil && tasa.valor.porcentaje {
+			if err := validarLimites(valor, limites); err != nil {
+				return fmt.Errorf("--%s: %w", f.Name, err)
+			}
+			return nil
+		}
+		return validarFlag(f.Name, valor, limites)
+	}
+	return tasa
+}
+
+// flagTasa es un Float64Flag que también acepta el valor con signo de porcentaje. urfave
+// lee el valor como texto al consultarlo, así que c.Float64 sigue funcionando.
+type flagTasa struct {
+	*cli.Float64Flag
+	valor *valorTasa
+}
+
+// Apply registra el flag con valorTasa en lugar del float64 de la biblioteca estándar
+func (f *flagTasa) Apply(set *flag.FlagSet) error {
+	f.valor = &valorTasa{valor: f.Value}
+	for _, variable := range f.EnvVars {
+		if texto, ok := os.LookupEnv(variable); ok && texto != "" {
+			if err := f.valor.Set(texto); err != nil {
+				return fmt.Errorf("no se pudo interpretar %q de %s para --%s: %w", texto, variable, f.Name, err)
+			}
+			f.HasBeenSet = true
+			break
+		}
+	}
+	for _, nombre := range f.Names() {
+		set.Var(f.valor, nombre, f.Usage)
+	}
+	return nil
+}
+
+// valorTasa guarda una tasa en decimal y recuerda si se escribió como porcentaje
+type valorTasa struct {
+	valor      float64
+	porcentaje bool
+}
+
+func (v *valorTasa) Set(texto string) error {
+	valor, err := ParsearNumero(texto)
+	if err != nil {
+		return err
+	}
+	v.valor = valor
+	v.porcentaje = strings.HasSuffix(strings.TrimSpace(texto), "%")
+	return nil
+}
+
+func (v *valorTasa) String() string {
+	return strconv.FormatFloat(v.valor, 'g', -1, 64)
+}
+
+// validarLimites verifica que el valor esté dentro del rango permitido
+func validarLimites(valor float64, limites LimitesNumero) error {
+	if valor < limites.Min {
+		if limites.Min == 0 {
+			return errDatosInvalidos("el valor no puede ser negativo", "the value cannot be negative")
+		}
+		return errDatosInvalidos(fmt.Sprintf("el valor debe ser al menos %g", limites.Min),
+			fmt.Sprintf("the value must be at least %g", limites.Min))
+	}
+	if valor > limites.Max {
+		return errDatosInvalidos(fmt.Sprintf("el valor %g es demasiado grande (máximo %g)", valor, limites.Max),
+			fmt.Sprintf("the value %g is too large (maximum %g)", valor, limites.Max))
+	}
+	return nil
+}
+
+// elegirOpcion muestra una lista numerada y regresa la posición (desde 0) de la opción elegida
+func elegirOpcion(titulo string, opciones []string) (int, error) {
+	fmt.Println(titulo)
+	for i, opcion := range opciones {
+		fmt.Printf("%d. %s\n", i+1, opcion)
+	}
+
+	seleccion, err := leerEntero("Selecciona una opción (número): ", 1, len(opciones))
+	return seleccion - 1, err
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsearNumeroSeparadores(t *testing.T) {
+	casos := []struct {
+		texto string
+		valor float64
+	}{
+		// Un solo separador con tres dígitos y parte entera separa miles, sea coma o punto
+		{"1,234", 1234},
+		{"1.234", 1234},
+		{"$12,500", 12500},
+		// Con otra cantidad de dígitos o sin parte entera es el decimal
+		{"0,5", 0.5},
+		{"1.25", 1.25},
+		{"1500,50", 1500.50},
+		{"0.125", 0.125},
+		{"0,125", 0.125},
+		// Con los dos separadores el último es el decimal
+		{"1.234,56", 1234.56},
+		{"1,234.56", 1234.56},
+		{"1.234.567,8", 1234567.8},
+		{"1.500.000", 1500000},
+		{"2,000,000", 2000000},
+		{"-1,234.5", -1234.5},
+	}
+	for _, c := range casos {
+		valor, err := ParsearNumero(c.texto)
+		if err != nil || valor != c.valor {
+			t.Errorf("%s: %g, %v; se esperaba %g", c.texto, valor, err, c.valor)
+		}
+	}
+
+	for _, texto := range []string{"1,23,4", "1.234.5", "12,34.5", "1.2,5", "1,234,5.6", "1234.567,8", ".234,5"} {
+		if valor, err := ParsearNumero(texto); !errors.Is(err, ErrDatosInvalidos) {
+			t.Errorf("%s: se esperaba un error por ambigüedad, salió %g, %v", texto, valor, err)
+		}
+	}
+}
+
+func TestFormatearNumeroSeLeeIgual(t *testing.T) {
+	for _, v := range []float64{1.125, 0.125, 1234.5, 25000, -2.375, 1.25, 1000.001} {
+		texto := formatearNumero(v)
+		if leido, err := ParsearNumero(texto); err != nil || leido != v {
+			t.Errorf("%g se escribió %s y se leyó %g, %v", v, texto, leido, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Códigos de salida del proceso según la clase de error
+const (
+	SalidaError               = 1
+	SalidaDatosInvalidos      = 3
+	SalidaTarjetaNoEncontrada = 4
+	SalidaArchivoCorrupto     = 5
+	SalidaVersionFutura       = 6
+)
+
+// codigoSalida regresa el código de salida del proceso para el error
+func codigoSalida(e *ErrorFinmex) int {
+	switch e.Codigo {
+	case CodigoDatosInvalidos:
+		return SalidaDatosInvalidos
+	case CodigoTarjetaNoEncontrada:
+		return SalidaTarjetaNoEncontrada
+	case CodigoArchivoCorrupto:
+		return SalidaArchivoCorrupto
+	case CodigoVersionFutura:
+		return SalidaVersionFutura
+	}
+	return SalidaError
+}
+
+// errTarjetaNoEncontrada crea el error de una tarjeta que no existe. El tipo es "debito",
+// "credito" o vacío si se buscó entre todas las tarjetas.
+func errTarjetaNoEncontrada(tipo, nombre string) error {
+	e := &ErrorFinmex{Codigo: CodigoTarjetaNoEncontrada}
+	switch tipo {
+	case "credito":
+		e.Mensaje = fmt.Sprintf("No se encontró la tarjeta de crédito '%s'", nombre)
+		e.Message = fmt.Sprintf("Credit card '%s' not found", nombre)
+	case "debito":
+		e.Mensaje = fmt.Sprintf("No se encontró la tarjeta de débito '%s'", nombre)
+		e.Message = fmt.Sprintf("Debit card '%s' not found", nombre)
+	case "cetes":
+		e.Mensaje = fmt.Sprintf("No se encontró la inversión en cetesdirecto '%s'", nombre)
+		e.Message = fmt.Sprintf("cetesdirecto investment '%s' not found", nombre)
+	case "sofipo":
+		e.Mensaje = fmt.Sprintf("No se encontró la cuenta de SOFIPO '%s'", nombre)
+		e.Message = fmt.Sprintf("SOFIPO account '%s' not found", nombre)
+	case "pagare":
+		e.Mensaje = fmt.Sprintf("No se encontró el pagaré '%s'", nombre)
+		e.Message = fmt.Sprintf("Promissory note '%s' not found", nombre)
+	default:
+		e.Mensaje = fmt.Sprintf("No se encontró la tarjeta '%s'", nombre)
+		e.Message = fmt.Sprintf("Card '%s' not found", nombre)
+	}
+	return e
+}
+
+// errDatosInvalidos crea el error de un dato capturado que no es válido
+func errDatosInvalidos(mensaje, message string) error {
+	return &ErrorFinmex{Codigo: CodigoDatosInvalidos, Mensaje: mensaje, Message: message}
+}
+
+// errArchivoCorrupto crea el error de un archivo de datos que no se puede interpretar
+func errArchivoCorrupto(archivo string, causa error) error {
+	return &ErrorFinmex{
+		Codigo:  CodigoArchivoCorrupto,
+		Mensaje: fmt.Sprintf("El archivo %s está dañado: %v", archivo, causa),
+		Message: fmt.Sprintf("File %s is corrupted: %v", archivo, causa),
+		Causa:   causa,
+	}
+}
+
+// errVersionFutura crea el error de un archivo de datos escrito por una versión más nueva de
+// finmex, que esta no sabe leer sin perder campos
+func errVersionFutura(archivo string, version int) error {
+	return &ErrorFinmex{
+		Codigo: CodigoVersionFutura,
+		Mensaje: fmt.Sprintf("El archivo %s es de la versión %d del formato y esta versión de finmex solo conoce hasta la %d; actualiza finmex con 'finmex actualizar'",
+			archivo, version, VERSION_ESQUEMA),
+		Message: fmt.Sprintf("File %s uses format version %d but this finmex only supports up to %d; upgrade with 'finmex actualizar'",
+			archivo, version, VERSION_ESQUEMA),
+	}
+}
+
+// idiomaMensajes es el idioma en que el CLI muestra los errores
+var idiomaMensajes = "es"
+
+// ValidarIdioma verifica que el idioma de los mensajes sea uno de los soportados
+func ValidarIdioma(idioma string) error {
+	if idioma != "es" && idioma != "en" {
+		return fmt.Errorf("Idioma inválido '%s' (usa es o en)", idioma)
+	}
+	return nil
+}
+
+// mensajeError regresa el texto con el que el CLI reporta un error y el código de salida
+func mensajeError(err error) (string, int) {
+	var e *ErrorFinmex
+	if !errors.As(err, &e) {
+		return "Error: " + err.Error(), SalidaError
+	}
+
+	// El mensaje completo conserva el contexto ("Error al cargar tarjetas: ..."), que solo
+	// existe en español; en inglés se muestra el mensaje del error tipado
+	mensaje := err.Error()
+	if idiomaMensajes == "en" {
+		mensaje = e.Localizado("en")
+	}
+	return fmt.Sprintf("Error [%s]: %s", e.Codigo, mensaje), codigoSalida(e)
+}
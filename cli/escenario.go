@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Escenario reemplaza los supuestos de los análisis para simular "¿qué pasa si?". Los
+// supuestos sin valor conservan el vigente.
+type Escenario struct {
+	Nombre       string   `json:"nombre"`
+	Inflacion    *float64 `json:"inflacion,omitempty"`
+	RetencionISR *float64 `json:"retencion_isr,omitempty"` // Retención anual sobre el capital
+	AjusteTasas  float64  `json:"ajuste_tasas,omitempty"`  // Se suma a las tasas de las cuentas y del catálogo
+}
+
+var (
+	limitesInflacion          = LimitesNumero{Min: -0.1, Max: 1, Porcentaje: true}
+	limitesRetencionEscenario = LimitesNumero{Min: 0.0001, Max: 0.1, Porcentaje: true}
+	limitesAjusteTasas        = LimitesNumero{Min: -1, Max: 1, Porcentaje: true}
+)
+
+// escenarioActivo es el escenario con el que corren los análisis; nil usa los supuestos
+// vigentes
+var escenarioActivo *Escenario
+
+// activarEscenario hace que los análisis usen el escenario y regresa la función que
+// restaura los supuestos vigentes
+func activarEscenario(e Escenario) func() {
+	anterior := escenarioActivo
+	escenarioActivo = &e
+	return func() { escenarioActivo = anterior }
+}
+
+// EscenariosPredefinidos son los que compara escenario comparar sin argumentos: los
+// supuestos vigentes, una inflación un punto menor y una dos puntos mayor con tasas un
+// punto más bajas
+func EscenariosPredefinidos() []Escenario {
+	inflacion := InflacionVigente()
+	optimista, pesimista := math.Max(0, inflacion-0.01), inflacion+0.02
+	return []Escenario{
+		{Nombre: "optimista", Inflacion: &optimista},
+		{Nombre: "base"},
+		{Nombre: "pesimista", Inflacion: &pesimista, AjusteTasas: -0.01},
+	}
+}
+
+// ParsearEscenario lee un escenario nombre[:supuesto=valor,...], p. ej.
+// "pesimista:inflacion=0.06,isr=0.0115,ajuste-tasas=-0.01"
+func ParsearEscenario(texto string) (Escenario, error) {
+	nombre, supuestos, _ := strings.Cut(texto, ":")
+	e := Escenario{Nombre: strings.TrimSpace(nombre)}
+	if e.Nombre == "" {
+		return e, errDatosInvalidos(
+			fmt.Sprintf("El escenario '%s' necesita un nombre antes de los supuestos", texto),
+			fmt.Sprintf("Scenario '%s' needs a name before its assumptions", texto))
+	}
+	for _, parte := range ParsearLista(supuestos) {
+		clave, valor, ok := strings.Cut(parte, "=")
+		if !ok {
+			return e, errDatosInvalidos(
+				fmt.Sprintf("Supuesto inválido '%s' en el escenario %s: usa supuesto=valor", parte, e.Nombre),
+				fmt.Sprintf("Invalid assumption '%s' in scenario %s: use name=value", parte, e.Nombre))
+		}
+		var err error
+		switch normalizarClave(clave) {
+		case "inflacion", "inflación":
+			e.Inflacion = new(float64)
+			*e.Inflacion, err = parsearDecimal(valor, limitesInflacion)
+		case "isr":
+			e.RetencionISR = new(float64)
+			*e.RetencionISR, err = parsearDecimal(valor, limitesRetencionEscenario)
+		case "ajuste-tasas":
+			e.AjusteTasas, err = parsearDecimal(valor, limitesAjusteTasas)
+		default:
+			return e, errDatosInvalidos(
+				fmt.Sprintf("Supuesto desconocido '%s' (usa inflacion, isr o ajuste-tasas)", clave),
+				fmt.Sprintf("Unknown assumption '%s' (use inflacion, isr or ajuste-tasas)", clave))
+		}
+		if err != nil {
+			return e, fmt.Errorf("Escenario %s, %s: %w", e.Nombre, clave, err)
+		}
+	}
+	return e, nil
+}
+
+// Descripcion resume los supuestos que cambia el escenario
+func (e Escenario) Descripcion() string {
+	var partes []string
+	if e.Inflacion != nil {
+		partes = append(partes, fmt.Sprintf("inflación %.2f%%", *e.Inflacion*100))
+	}
+	if e.RetencionISR != nil {
+		partes = append(partes, fmt.Sprintf("retención ISR %.2f%%", *e.RetencionISR*100))
+	}
+	if e.AjusteTasas != 0 {
+		partes = append(partes, fmt.Sprintf("tasas %+.2f puntos", e.AjusteTasas*100))
+	}
+	if len(partes) == 0 {
+		return "supuestos vigentes"
+	}
+	return strings.Join(partes, ", ")
+}
+
+// ajustarTramos suma el ajuste a cada tasa sin dejarla negativa
+func ajustarTramos(tramos []TramoRendimiento, ajuste float64) []TramoRendimiento {
+	ajustados := make([]TramoRendimiento, len(tramos))
+	for i, t := range tramos {
+		ajustados[i] = TramoRendimiento{Hasta: t.Hasta, Tasa: math.Max(0, t.Tasa+ajuste)}
+	}
+	return ajustados
+}
+
+// AjustarTarjetas aplica el ajuste de tasas a las cuentas de débito y de SOFIPO. Las
+// listas se copian para no tocar las tarjetas originales.
+func (e Escenario) AjustarTarjetas(tarjetas Tarjetas) Tarjetas {
+	if e.AjusteTasas == 0 {
+		return tarjetas
+	}
+	tarjetas.Debito = append([]TarjetaDebito(nil), tarjetas.Debito...)
+	for i := range tarjetas.Debito {
+		tarjetas.Debito[i].Tramos = ajustarTramos(tarjetas.Debito[i].TramosOrdenados(), e.AjusteTasas)
+	}
+	tarjetas.Sofipos = append([]CuentaSofipo(nil), tarjetas.Sofipos...)
+	for i, s := range tarjetas.Sofipos {
+		tramos := append([]TramoSofipo(nil), s.Tramos...)
+		for j := range tramos {
+			tramos[j].Tasa = math.Max(0, tramos[j].Tasa+e.AjusteTasas)
+		}
+		tarjetas.Sofipos[i].Tramos = tramos
+	}
+	return tarjetas
+}
+
+// AjustarCatalogo aplica el ajuste de tasas a las cuentas y las tasas de referencia del
+// catálogo, que se mueven con el mercado
+func (e Escenario) AjustarCatalogo(catalogo Catalogo) Catalogo {
+	if e.AjusteTasas == 0 {
+		return catalogo
+	}
+	catalogo.Debito = append([]DebitoCatalogo(nil), catalogo.Debito...)
+	for i := range catalogo.Debito {
+		catalogo.Debito[i].Tramos = ajustarTramos(catalogo.Debito[i].TramosOrdenados(), e.AjusteTasas)
+	}
+	catalogo.Benchmarks = append([]Benchmark(nil), catalogo.Benchmarks...)
+	for i := range catalogo.Benchmarks {
+		catalogo.Benchmarks[i].Tasa = math.Max(0, catalogo.Benchmarks[i].Tasa+e.AjusteTasas)
+	}
+	return catalogo
+}
+
+// CuentaEscenario es lo que deja una cuenta en un año con los supuestos de un escenario
+type CuentaEscenario struct {
+	Cuenta             string  `json:"cuenta"`
+	Tipo               string  `json:"tipo"` // debito o sofipo
+	Saldo              float64 `json:"saldo"`
+	Tasa               float64 `json:"tasa"`
+	RendimientoReal    float64 `json:"rendimiento_real"`
+	RendimientoRealPct float64 `json:"rendimiento_real_pct"`
+}
+
+// ResultadoEscenario es el rendimiento real de las cuentas con los supuestos de un escenario
+type ResultadoEscenario struct {
+	Escenario
+	InflacionUsada  float64           `json:"inflacion_usada"`
+	ISR             string            `json:"isr"`
+	Cuentas         []CuentaEscenario `json:"cuentas"`
+	Saldo           float64           `json:"saldo"`
+	RendimientoReal float64           `json:"rendimiento_real"`
+}
+
+// FilaEscenario es una cuenta en un escenario en la salida CSV de escenario comparar
+type FilaEscenario struct {
+	Escenario string `json:"escenario"`
+	CuentaEscenario
+}
+
+// EvaluarEscenario calcula el rendimiento real de un año de las cuentas de débito y de
+// SOFIPO con saldo. Las cuentas de débito sin saldo registrado se evalúan con saldo.
+func EvaluarEscenario(e Escenario, tarjetas Tarjetas, saldo float64) ResultadoEscenario {
+	defer activarEscenario(e)()
+	tarjetas = e.AjustarTarjetas(tarjetas)
+	inflacion := InflacionVigente()
+	r := ResultadoEscenario{Escenario: e, InflacionUsada: inflacion, ISR: ISRVigente().Descripcion()}
+
+	agregar := func(c CuentaEscenario) {
+		r.Cuentas = append(r.Cuentas, c)
+		r.Saldo += c.Saldo
+		r.RendimientoReal += c.RendimientoReal
+	}
+	for _, t := range tarjetas.Debito {
+		s := SaldoEnPesos(t)
+		if s <= 0 {
+			s = saldo
+		}
+		if s <= 0 {
+			continue
+		}
+		rendimiento, pct, _ := CalcularRendimientoReal(t, s)
+		agregar(CuentaEscenario{t.Nombre, "debito", s, t.TasaPonderada(saldoEnMoneda(t, s)), rendimiento, pct})
+	}
+	for _, s := range tarjetas.Sofipos {
+		if s.Saldo <= 0 {
+			continue
+		}
+		rs := s.Rendimiento(inflacion)
+		agregar(CuentaEscenario{s.Nombre, "sofipo", s.Saldo, rs.TasaEfectiva, rs.Neto - s.Saldo*inflacion, rs.TasaReal * 100})
+	}
+	return r
+}
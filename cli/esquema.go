@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// VERSION_ESQUEMA es la versión de los esquemas de los archivos de datos y la que se guarda en
+// el campo version del archivo de tarjetas. Se incrementa cuando un cambio en el formato deja
+// de ser compatible con archivos anteriores, junto con una migración en migracionesDatos.
+const VERSION_ESQUEMA = 2
+
+// Esquema es el subconjunto de JSON Schema (draft 2020-12) que describe los archivos de finmex
+type Esquema struct {
+	Schema                 string              `json:"$schema,omitempty"`
+	ID                     string              `json:"$id,omitempty"`
+	Titulo                 string              `json:"title,omitempty"`
+	Descripcion            string              `json:"description,omitempty"`
+	Tipo                   string              `json:"type"`
+	Propiedades            map[string]*Esquema `json:"properties,omitempty"`
+	Requeridos             []string            `json:"required,omitempty"`
+	Elementos              *Esquema            `json:"items,omitempty"`
+	PropiedadesAdicionales interface{}         `json:"additionalProperties,omitempty"` // false o un esquema
+}
+
+// MarshalJSON publica las listas como lista o null, porque así guarda encoding/json las
+// listas vacías de Go
+func (e *Esquema) MarshalJSON() ([]byte, error) {
+	type esquemaJSON Esquema
+	if e.Tipo != "array" {
+		return json.Marshal((*esquemaJSON)(e))
+	}
+	return json.Marshal(struct {
+		*esquemaJSON
+		Tipo []string `json:"type"`
+	}{(*esquemaJSON)(e), []string{"array", "null"}})
+}
+
+// archivoEsquema relaciona un archivo de datos con el tipo que guarda
+type archivoEsquema struct {
+	Nombre      string
+	Archivo     string
+	Descripcion string
+	Tipo        reflect.Type
+	PorLinea    bool // El archivo es NDJSON y cada línea sigue el esquema
+}
+
+// archivosEsquema son los archivos de datos con esquema publicado
+var archivosEsquema = []archivoEsquema{
+	{"tarjetas", ARCHIVO_TARJETAS, "Tarjetas y demás productos registrados", reflect.TypeOf(Tarjetas{}), false},
+	{"catalogo", ARCHIVO_CATALOGO, "Catálogo de productos de referencia del mercado", reflect.TypeOf(Catalogo{}), false},
+	{"movimientos", ARCHIVO_MOVIMIENTOS, "Un movimiento por línea del historial", reflect.TypeOf(Movimiento{}), true},
+}
+
+// buscarArchivoEsquema encuentra el esquema por su nombre o por el nombre del archivo
+func buscarArchivoEsquema(nombre string) (archivoEsquema, bool) {
+	base := filepath.Base(nombre)
+	for _, a := range archivosEsquema {
+		if nombre == a.Nombre || base == a.Archivo {
+			return a, true
+		}
+	}
+	return archivoEsquema{}, false
+}
+
+// NombresEsquemas regresa los nombres de los esquemas publicados
+func NombresEsquemas() []string {
+	nombres := make([]string, len(archivosEsquema))
+	for i, a := range archivosEsquema {
+		nombres[i] = a.Nombre
+	}
+	return nombres
+}
+
+// EsquemaArchivo genera el JSON Schema versionado de un archivo de datos a partir de sus
+// tipos de Go, para que nunca se desfase del formato que escribe finmex
+func EsquemaArchivo(nombre string) (*Esquema, error) {
+	a, ok := buscarArchivoEsquema(nombre)
+	if !ok {
+		return nil, fmt.Errorf("No hay esquema '%s' (usa %s)", nombre, strings.Join(NombresEsquemas(), ", "))
+	}
+	e := esquemaDeTipo(a.Tipo)
+	e.Schema = "https://json-schema.org/draft/2020-12/schema"
+	e.ID = fmt.Sprintf("urn:finmex:%s:v%d", a.Nombre, VERSION_ESQUEMA)
+	e.Titulo = a.Archivo
+	e.Descripcion = a.Descripcion
+	return e, nil
+}
+
+// esquemaDeTipo describe un tipo de Go. Los campos sin omitempty son requeridos porque
+// finmex siempre los escribe; los campos desconocidos no se permiten para detectar errores
+// de captura.
+func esquemaDeTipo(t reflect.Type) *Esquema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return esquemaDeTipo(t.Elem())
+	case reflect.String:
+		return &Esquema{Tipo: "string"}
+	case reflect.Bool:
+		return &Esquema{Tipo: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Esquema{Tipo: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Esquema{Tipo: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Esquema{Tipo: "array", Elementos: esquemaDeTipo(t.Elem())}
+	case reflect.Map:
+		return &Esquema{Tipo: "object", PropiedadesAdicionales: esquemaDeTipo(t.Elem())}
+	case reflect.Struct:
+		e := &Esquema{Tipo: "object", Propiedades: map[string]*Esquema{}, PropiedadesAdicionales: false}
+		agregarCamposEsquema(e, t)
+		sort.Strings(e.Requeridos)
+		return e
+	}
+	return &Esquema{}
+}
+
+// agregarCamposEsquema agrega las propiedades de un struct, incluidas las de los structs
+// embebidos, que encoding/json aplana
+func agregarCamposEsquema(e *Esquema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		campo := t.Field(i)
+		if !campo.IsExported() {
+			continue
+		}
+		etiqueta := campo.Tag.Get("json")
+		if etiqueta == "-" {
+			continue
+		}
+		nombre, opciones, _ := strings.Cut(etiqueta, ",")
+		if campo.Anonymous && nombre == "" && campo.Type.Kind() == reflect.Struct {
+			agregarCamposEsquema(e, campo.Type)
+			continue
+		}
+		if nombre == "" {
+			nombre = campo.Name
+		}
+		e.Propiedades[nombre] = esquemaDeTipo(campo.Type)
+		if !strings.Contains(opciones, "omitempty") {
+			e.Requeridos = append(e.Requeridos, nombre)
+		}
+	}
+}
+
+// ErrorValidacion es un problema encontrado en un campo del archivo
+type ErrorValidacion struct {
+	Linea   int    // Línea del archivo en archivos NDJSON; cero en los demás
+	Ruta    string // Ruta del campo, p. ej. credito[0].tasa_interes
+	Mensaje string
+}
+
+func (e ErrorValidacion) String() string {
+	ubicacion := e.Ruta
+	if e.Linea > 0 {
+		ubicacion = strings.TrimSuffix(fmt.Sprintf("línea %d, %s", e.Linea, e.Ruta), ", ")
+	}
+	if ubicacion == "" {
+		return e.Mensaje
+	}
+	return ubicacion + ": " + e.Mensaje
+}
+
+// Validar revisa un valor decodificado contra el esquema y regresa todos los errores
+func (e *Esquema) Validar(valor interface{}) []ErrorValidacion {
+	var errores []ErrorValidacion
+	e.validar(valor, "", &errores)
+	return errores
+}
+
+// tipoJSON regresa el nombre del tipo JSON de un valor decodificado con UseNumber
+func tipoJSON(valor interface{}) string {
+	switch v := valor.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "desconocido"
+}
+
+// nombresTipo muestra los tipos de JSON Schema en español
+var nombresTipo = map[string]string{
+	"null":    "nulo",
+	"boolean": "booleano",
+	"string":  "texto",
+	"integer": "entero",
+	"number":  "número",
+	"array":   "lista",
+	"object":  "objeto",
+}
+
+func (e *Esquema) validar(valor interface{}, ruta string, errores *[]ErrorValidacion) {
+	tipo := tipoJSON(valor)
+	coincide := tipo == e.Tipo || (e.Tipo == "number" && tipo == "integer") || e.Tipo == ""
+	// Las listas vacías de Go se guardan como null
+	if tipo == "null" && e.Tipo == "array" {
+		return
+	}
+	if !coincide {
+		*errores = append(*errores, ErrorValidacion{Ruta: ruta, Mensaje: fmt.Sprintf("se esperaba %s y se encontró %s", nombresTipo[e.Tipo], nombresTipo[tipo])})
+		return
+	}
+
+	switch v := valor.(type) {
+	case []interface{}:
+		for i, elemento := range v {
+			e.Elementos.validar(elemento, fmt.Sprintf("%s[%d]", ruta, i), errores)
+		}
+	case map[string]interface{}:
+		for _, requerido := range e.Requeridos {
+			if _, ok := v[requerido]; !ok {
+				*errores = append(*errores, ErrorValidacion{Ruta: unirRuta(ruta, requerido), Mensaje: "campo requerido ausente"})
+			}
+		}
+		claves := make([]string, 0, len(v))
+		for clave := range v {
+			claves = append(claves, clave)
+		}
+		sort.Strings(claves)
+		for _, clave := range claves {
+			if propiedad, ok := e.Propiedades[clave]; ok {
+				propiedad.validar(v[clave], unirRuta(ruta, clave), errores)
+				continue
+			}
+			switch adicional := e.PropiedadesAdicionales.(type) {
+			case *Esquema:
+				adicional.validar(v[clave], unirRuta(ruta, clave), errores)
+			case bool:
+				if !adicional {
+					*errores = append(*errores, ErrorValidacion{Ruta: unirRuta(ruta, clave), Mensaje: "campo desconocido"})
+				}
+			}
+		}
+	}
+}
+
+// unirRuta agrega un campo a la ruta de validación
+func unirRuta(ruta, campo string) string {
+	if ruta == "" {
+		return campo
+	}
+	return ruta + "." + campo
+}
+
+// decodificarParaValidar lee un documento JSON conservando los números tal como vienen
+func decodificarParaValidar(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var valor interface{}
+	if err := decoder.Decode(&valor); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("hay contenido después del documento JSON")
+	}
+	return valor, nil
+}
+
+// ValidarArchivo revisa un archivo contra su esquema. Si no se indica el esquema se deduce
+// del nombre del archivo.
+func ValidarArchivo(ruta, nombreEsquema string) ([]ErrorValidacion, error) {
+	if nombreEsquema == "" {
+		nombreEsquema = ruta
+	}
+	a, ok := buscarArchivoEsquema(nombreEsquema)
+	if !ok {
+		return nil, fmt.Errorf("No se puede deducir el esquema de '%s'; indícalo con --esquema (%s)", ruta, strings.Join(NombresEsquemas(), ", "))
+	}
+	esquema, err := EsquemaArchivo(a.Nombre)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(ruta)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.PorLinea {
+		valor, err := decodificarParaValidar(data)
+		if err != nil {
+			return []ErrorValidacion{{Mensaje: fmt.Sprintf("JSON inválido: %v", err)}}, nil
+		}
+		// El archivo de tarjetas se valida como queda después de migrarlo, igual que al cargarlo
+		if documento, ok := valor.(map[string]interface{}); ok && a.Nombre == "tarjetas" {
+			if _, err := migrarTarjetas(ruta, documento, esquema); err != nil {
+				return nil, err
+			}
+		}
+		return esquema.Validar(valor), nil
+	}
+
+	var errores []ErrorValidacion
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxLineaMovimiento)
+	for linea := 1; scanner.Scan(); linea++ {
+		texto := bytes.TrimSpace(scanner.Bytes())
+		if len(texto) == 0 {
+			continue
+		}
+		valor, err := decodificarParaValidar(texto)
+		if err != nil {
+			errores = append(errores, ErrorValidacion{Linea: linea, Mensaje: fmt.Sprintf("JSON inválido: %v", err)})
+			continue
+		}
+		for _, e := range esquema.Validar(valor) {
+			e.Linea = linea
+			errores = append(errores, e)
+		}
+	}
+	return errores, scanner.Err()
+}
@@ -0,0 +1,415 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Renglones al inicio del archivo en los que se busca el encabezado; los bancos suelen poner
+// antes los datos de la cuenta y el periodo
+const maxRenglonesPreambulo = 20
+
+// FormatoBanco describe las columnas del CSV de movimientos que exporta un banco. Los nombres
+// de columna se comparan en minúsculas y sin acentos; cada campo acepta varias variantes.
+type FormatoBanco struct {
+	Clave       string
+	Nombre      string
+	Fecha       []string
+	Descripcion []string
+	Cargo       []string // Columnas separadas de cargo y abono
+	Abono       []string
+	Importe     []string // Columna única con el monto
+	Signo       []string // Columna que indica si el importe es cargo ("-") o abono ("+")
+	// ImporteNegativoCargo indica que en la columna de importe los cargos vienen negativos,
+	// al revés que en los movimientos de finmex
+	ImporteNegativoCargo bool
+}
+
+// FormatosBanco son los formatos que se reconocen, en el orden en que se prueban al detectar
+// el banco: primero los de encabezado más específico
+var FormatosBanco = []FormatoBanco{
+	{
+		Clave: "santander", Nombre: "Santander",
+		Fecha:       []string{"fecha", "fecha de operacion"},
+		Descripcion: []string{"descripcion", "concepto"},
+		Importe:     []string{"importe"},
+		Signo:       []string{"cargo/abono", "cargo / abono"},
+	},
+	{
+		Clave: "bbva", Nombre: "BBVA",
+		Fecha:       []string{"fecha", "fecha de operacion"},
+		Descripcion: []string{"descripcion", "concepto", "concepto / referencia"},
+		Cargo:       []string{"cargo", "cargos"},
+		Abono:       []string{"abono", "abonos"},
+	},
+	{
+		Clave: "banorte", Nombre: "Banorte",
+		Fecha:       []string{"fecha", "fecha de operacion"},
+		Descripcion: []string{"descripcion", "concepto"},
+		Cargo:       []string{"retiros", "retiro"},
+		Abono:       []string{"depositos", "deposito"},
+	},
+	{
+		Clave: "hsbc", Nombre: "HSBC",
+		Fecha:                []string{"fecha", "fecha de operacion"},
+		Descripcion:          []string{"descripcion", "concepto"},
+		Importe:              []string{"importe"},
+		ImporteNegativoCargo: true,
+	},
+	{
+		Clave: "nu", Nombre: "Nu",
+		Fecha:       []string{"fecha", "fecha de compra"},
+		Descripcion: []string{"descripcion", "concepto", "comercio"},
+		Importe:     []string{"monto", "cantidad"},
+	},
+}
+
+// formatosFechaBanco son los formatos de fecha que usan los estados de cuenta
+var formatosFechaBanco = []string{"02/01/2006", "2/1/2006", "02-01-2006", "2006-01-02", "2006/01/02", "02/01/06"}
+
+// mesesAbreviados convierte las fechas como 05/ene/2024 a 05/01/2024
+var mesesAbreviados = strings.NewReplacer(
+	"ene", "01", "feb", "02", "mar", "03", "abr", "04", "may", "05", "jun", "06",
+	"jul", "07", "ago", "08", "sep", "09", "oct", "10", "nov", "11", "dic", "12")
+
+// BuscarFormatoBanco regresa el formato de un banco por su clave o nombre
+func BuscarFormatoBanco(banco string) (FormatoBanco, bool) {
+	for _, f := range FormatosBanco {
+		if normalizarClave(f.Clave) == normalizarClave(banco) || normalizarClave(f.Nombre) == normalizarClave(banco) {
+			return f, true
+		}
+	}
+	return FormatoBanco{}, false
+}
+
+// clavesBanco lista las claves de los bancos reconocidos, para los mensajes de ayuda
+func clavesBanco() string {
+	claves := make([]string, len(FormatosBanco))
+	for i, f := range FormatosBanco {
+		claves[i] = f.Clave
+	}
+	return strings.Join(claves, ", ")
+}
+
+// columnasBanco son las posiciones de cada campo en el encabezado; -1 si no está
+type columnasBanco struct {
+	fecha, descripcion, cargo, abono, importe, signo int
+}
+
+// ubicar busca las columnas del formato en un encabezado y dice si están todas las necesarias
+func (f FormatoBanco) ubicar(encabezado []string) (columnasBanco, bool) {
+	normalizado := make([]string, len(encabezado))
+	for i, c := range encabezado {
+		normalizado[i] = normalizarClave(quitarAcentos(strings.Trim(c, " '\"")))
+	}
+	buscar := func(nombres []string) int {
+		for i, c := range normalizado {
+			if contieneClave(nombres, c) {
+				return i
+			}
+		}
+		return -1
+	}
+	c := columnasBanco{
+		fecha:       buscar(f.Fecha),
+		descripcion: buscar(f.Descripcion),
+		cargo:       buscar(f.Cargo),
+		abono:       buscar(f.Abono),
+		importe:     buscar(f.Importe),
+		signo:       buscar(f.Signo),
+	}
+	if c.fecha < 0 || c.descripcion < 0 {
+		return c, false
+	}
+	switch {
+	case f.Signo != nil:
+		return c, c.importe >= 0 && c.signo >= 0
+	case f.Importe != nil:
+		return c, c.importe >= 0
+	}
+	return c, c.cargo >= 0 && c.abono >= 0
+}
+
+// EstadoBancarioCSV son los movimientos leídos del CSV de un banco
+type EstadoBancarioCSV struct {
+	Banco       FormatoBanco
+	Movimientos []Movimiento
+	Rechazos    []RechazoCSV
+	Renglones   int // Filas de datos leídas, sin el preámbulo ni el encabezado
+}
+
+// LeerEstadoBancarioCSV lee los movimientos del CSV que exporta la banca en línea. Con banco
+// vacío el formato se detecta por el encabezado, que puede venir después de unos renglones
+// con los datos de la cuenta. Acepta coma, punto y coma o tabulador como separador y archivos
+// en Latin-1, como los que genera Excel. Los montos quedan positivos para cargos y negativos
+// para abonos; las filas que no se pueden leer se reportan en Rechazos.
+func LeerEstadoBancarioCSV(r io.Reader, banco string) (EstadoBancarioCSV, error) {
+	var resultado EstadoBancarioCSV
+	candidatos := FormatosBanco
+	if banco != "" {
+		formato, ok := BuscarFormatoBanco(banco)
+		if !ok {
+			return resultado, errDatosInvalidos(
+				fmt.Sprintf("Banco '%s' no reconocido (usa %s)", banco, clavesBanco()),
+				fmt.Sprintf("Unknown bank '%s' (use %s)", banco, clavesBanco()))
+		}
+		candidatos = []FormatoBanco{formato}
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return resultado, err
+	}
+	texto := string(data)
+	if !utf8.ValidString(texto) {
+		texto = desdeLatin1(data)
+	}
+	texto = strings.TrimPrefix(texto, "\ufeff")
+
+	for _, separador := range []rune{',', ';', '\t'} {
+		lector := csv.NewReader(strings.NewReader(texto))
+		lector.Comma = separador
+		lector.FieldsPerRecord = -1
+		lector.LazyQuotes = true
+		// Con tabulador, TrimLeadingSpace se comería las columnas vacías; los campos se
+		// recortan después de todos modos
+		lector.TrimLeadingSpace = separador != '\t'
+
+		for renglon := 0; renglon < maxRenglonesPreambulo; renglon++ {
+			encabezado, err := lector.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				continue
+			}
+			for _, formato := range candidatos {
+				if columnas, ok := formato.ubicar(encabezado); ok {
+					resultado.Banco = formato
+					return resultado, resultado.leerFilas(lector, columnas)
+				}
+			}
+		}
+	}
+
+	if banco != "" {
+		f := candidatos[0]
+		esperadas := []string{f.Fecha[0], f.Descripcion[0]}
+		switch {
+		case f.Signo != nil:
+			esperadas = append(esperadas, f.Importe[0], f.Signo[0])
+		case f.Importe != nil:
+			esperadas = append(esperadas, f.Importe[0])
+		default:
+			esperadas = append(esperadas, f.Cargo[0], f.Abono[0])
+		}
+		return resultado, errDatosInvalidos(
+			fmt.Sprintf("El archivo no tiene el encabezado de %s; se esperaban las columnas %s", f.Nombre, strings.Join(esperadas, ", ")),
+			fmt.Sprintf("The file does not have the %s header; expected columns %s", f.Nombre, strings.Join(esperadas, ", ")))
+	}
+	return resultado, errDatosInvalidos(
+		fmt.Sprintf("No se reconoció el formato del archivo; indica el banco con --banco (%s)", clavesBanco()),
+		fmt.Sprintf("Unrecognized file format; pass the bank with --banco (%s)", clavesBanco()))
+}
+
+// leerFilas convierte en movimientos las filas que siguen al encabezado
+func (e *EstadoBancarioCSV) leerFilas(lector *csv.Reader, columnas columnasBanco) error {
+	for {
+		registro, err := lector.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var formato *csv.ParseError
+			if errors.As(err, &formato) {
+				e.Rechazos = append(e.Rechazos, RechazoCSV{Fila: formato.Line, Motivo: formato.Err.Error()})
+				continue
+			}
+			return err
+		}
+		linea, _ := lector.FieldPos(0)
+		if renglonVacio(registro) {
+			continue
+		}
+		e.Renglones++
+
+		campo := func(i int) string {
+			if i >= 0 && i < len(registro) {
+				return strings.Trim(strings.TrimSpace(registro[i]), "'")
+			}
+			return ""
+		}
+		descripcion := strings.Join(strings.Fields(campo(columnas.descripcion)), " ")
+		m, err := e.Banco.movimiento(campo, columnas)
+		if err != nil {
+			e.Rechazos = append(e.Rechazos, RechazoCSV{Fila: linea, Nombre: descripcion, Motivo: err.Error()})
+			continue
+		}
+		m.Descripcion = descripcion
+		e.Movimientos = append(e.Movimientos, m)
+	}
+}
+
+// movimiento arma el movimiento de una fila con la fecha y el monto normalizados
+func (f FormatoBanco) movimiento(campo func(int) string, columnas columnasBanco) (Movimiento, error) {
+	var m Movimiento
+	fecha, err := parsearFechaBanco(campo(columnas.fecha))
+	if err != nil {
+		return m, err
+	}
+	m.Fecha = fecha
+
+	monto := func(i int) (float64, error) {
+		texto := strings.Trim(campo(i), "()")
+		if texto == "" || texto == "-" {
+			return 0, nil
+		}
+		valor, err := ParsearNumero(texto)
+		if err != nil {
+			return 0, err
+		}
+		if strings.HasPrefix(campo(i), "(") {
+			valor = -valor
+		}
+		return valor, validarLimites(valor, limitesLibre)
+	}
+
+	if columnas.importe >= 0 {
+		if campo(columnas.importe) == "" {
+			return m, fmt.Errorf("falta el importe")
+		}
+		if m.Monto, err = monto(columnas.importe); err != nil {
+			return m, err
+		}
+		if f.ImporteNegativoCargo {
+			m.Monto = -m.Monto
+		}
+		if columnas.signo >= 0 {
+			switch normalizarClave(campo(columnas.signo)) {
+			case "-", "cargo", "c":
+				m.Monto = math.Abs(m.Monto)
+			case "+", "abono", "a":
+				m.Monto = -math.Abs(m.Monto)
+			default:
+				return m, fmt.Errorf("'%s' no indica cargo ni abono", campo(columnas.signo))
+			}
+		}
+		return m, nil
+	}
+
+	cargo, err := monto(columnas.cargo)
+	if err != nil {
+		return m, fmt.Errorf("cargo: %v", err)
+	}
+	abono, err := monto(columnas.abono)
+	if err != nil {
+		return m, fmt.Errorf("abono: %v", err)
+	}
+	if cargo == 0 && abono == 0 {
+		return m, fmt.Errorf("no tiene cargo ni abono")
+	}
+	m.Monto = math.Abs(cargo) - math.Abs(abono)
+	return m, nil
+}
+
+// parsearFechaBanco lee las fechas de los estados de cuenta y las deja como AAAA-MM-DD
+func parsearFechaBanco(texto string) (string, error) {
+	normal := mesesAbreviados.Replace(strings.ToLower(strings.TrimSpace(texto)))
+	if i := strings.IndexAny(normal, " T"); i > 0 {
+		// Algunos bancos agregan la hora
+		normal = normal[:i]
+	}
+	for _, formato := range formatosFechaBanco {
+		if t, err := time.Parse(formato, normal); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("fecha '%s' no reconocida", texto)
+}
+
+// desdeLatin1 convierte un texto en Latin-1 a UTF-8; cada byte es su propio carácter
+func desdeLatin1(data []byte) string {
+	runas := make([]rune, len(data))
+	for i, b := range data {
+		runas[i] = rune(b)
+	}
+	return string(runas)
+}
+
+// TarjetaEstadoBancario regresa el nombre registrado de la tarjeta a la que se asocian los
+// movimientos: la indicada o, si no se indicó, la única del banco del estado de cuenta
+func TarjetaEstadoBancario(tarjetas Tarjetas, nombre string, banco FormatoBanco) (string, error) {
+	if nombre != "" {
+		if i, ok := indiceDebito(tarjetas, nombre); ok {
+			return tarjetas.Debito[i].Nombre, nil
+		}
+		if i, ok := indiceCredito(tarjetas, nombre); ok {
+			return tarjetas.Credito[i].Nombre, nil
+		}
+		return "", errDatosInvalidos(fmt.Sprintf("No existe la tarjeta '%s'", nombre), fmt.Sprintf("Card '%s' does not exist", nombre))
+	}
+
+	var candidatas []string
+	delBanco := func(nombreTarjeta, bancoTarjeta string) {
+		if contieneClave(palabrasClave(quitarAcentos(bancoTarjeta)), banco.Clave) {
+			candidatas = append(candidatas, nombreTarjeta)
+		}
+	}
+	for _, t := range tarjetas.Debito {
+		delBanco(t.Nombre, t.Banco)
+	}
+	for _, t := range tarjetas.Credito {
+		delBanco(t.Nombre, t.Banco)
+	}
+	switch len(candidatas) {
+	case 1:
+		return candidatas[0], nil
+	case 0:
+		return "", errDatosInvalidos(
+			fmt.Sprintf("No hay tarjetas de %s registradas; indica la tarjeta con --tarjeta", banco.Nombre),
+			fmt.Sprintf("No %s cards are registered; pass the card with --tarjeta", banco.Nombre))
+	}
+	return "", errDatosInvalidos(
+		fmt.Sprintf("Hay varias tarjetas de %s (%s); indica cuál con --tarjeta", banco.Nombre, strings.Join(candidatas, ", ")),
+		fmt.Sprintf("There are several %s cards (%s); pass one with --tarjeta", banco.Nombre, strings.Join(candidatas, ", ")))
+}
+
+// DescartarMovimientosRegistrados quita los movimientos que ya están en el historial de la
+// tarjeta, para poder importar el mismo estado de cuenta otra vez o periodos que se enciman.
+// Se comparan fecha, descripción y monto; dos compras idénticas el mismo día solo se
+// descartan si el historial ya tiene las dos.
+func DescartarMovimientosRegistrados(movimientos []Movimiento) (nuevos []Movimiento, repetidos int, err error) {
+	clave := func(m Movimiento) string {
+		return fmt.Sprintf("%s|%s|%s|%.2f", normalizarClave(m.Tarjeta), m.Fecha, normalizarClave(m.Descripcion), m.Monto)
+	}
+	buscadas := map[string]bool{}
+	for _, m := range movimientos {
+		buscadas[clave(m)] = true
+	}
+	registrados := map[string]int{}
+	err = RecorrerMovimientos(func(m Movimiento) error {
+		if k := clave(m); buscadas[k] {
+			registrados[k]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, m := range movimientos {
+		if k := clave(m); registrados[k] > 0 {
+			registrados[k]--
+			repetidos++
+			continue
+		}
+		nuevos = append(nuevos, m)
+	}
+	return nuevos, repetidos, nil
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"finmex/storage"
+)
+
+// leerEstadoFixture lee un CSV de testdata/estados_bancarios detectando el banco
+func leerEstadoFixture(t *testing.T, archivo string) EstadoBancarioCSV {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "estados_bancarios", archivo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	estado, err := LeerEstadoBancarioCSV(f, "")
+	if err != nil {
+		t.Fatalf("%s: %v", archivo, err)
+	}
+	return estado
+}
+
+func TestLeerEstadoBancarioPorBanco(t *testing.T) {
+	casos := []struct {
+		archivo     string
+		banco       string
+		movimientos []Movimiento
+		rechazos    []int // Líneas rechazadas
+	}{
+		// Latin-1 con el encabezado después de los datos de la cuenta
+		{"santander.csv", "santander", []Movimiento{
+			{Fecha: "2024-01-05", Descripcion: "OXXO PERIFÉRICO", Monto: 1250.50},
+			{Fecha: "2024-01-10", Descripcion: "DEPÓSITO NÓMINA", Monto: -15000},
+		}, []int{7}},
+		// Punto y coma y fechas con el mes abreviado; un cargo entre paréntesis sigue siendo cargo
+		{"bbva.csv", "bbva", []Movimiento{
+			{Fecha: "2024-01-03", Descripcion: "SPEI RECIBIDO BANORTE", Monto: -5000},
+			{Fecha: "2024-02-15", Descripcion: "WALMART INSURGENTES", Monto: 845.30},
+			{Fecha: "2023-12-20", Descripcion: "PAGO TDC", Monto: 1000},
+		}, []int{5}},
+		// Tabulador con BOM, fecha con hora y columnas vacías
+		{"banorte.csv", "banorte", []Movimiento{
+			{Fecha: "2024-03-01", Descripcion: "RETIRO CAJERO", Monto: 2000},
+			{Fecha: "2024-03-02", Descripcion: "DEPOSITO EFECTIVO", Monto: -3500},
+		}, nil},
+		// Los cargos vienen negativos y se invierten
+		{"hsbc.csv", "hsbc", []Movimiento{
+			{Fecha: "2024-04-07", Descripcion: "LIVERPOOL SANTA FE", Monto: 2399},
+			{Fecha: "2024-04-08", Descripcion: "ABONO TRANSFERENCIA", Monto: -500},
+		}, []int{4}},
+		{"nu.csv", "nu", []Movimiento{
+			{Fecha: "2024-05-10", Descripcion: "UBER *TRIP", Monto: 189.90},
+			{Fecha: "2024-05-12", Descripcion: "PAGO RECIBIDO", Monto: -3000},
+		}, nil},
+	}
+
+	for _, c := range casos {
+		estado := leerEstadoFixture(t, c.archivo)
+		if estado.Banco.Clave != c.banco {
+			t.Errorf("%s: se detectó %s, se esperaba %s", c.archivo, estado.Banco.Clave, c.banco)
+			continue
+		}
+		if !reflect.DeepEqual(estado.Movimientos, c.movimientos) {
+			t.Errorf("%s: movimientos\n%+v\nse esperaban\n%+v", c.archivo, estado.Movimientos, c.movimientos)
+		}
+		var lineas []int
+		for _, r := range estado.Rechazos {
+			lineas = append(lineas, r.Fila)
+		}
+		if !reflect.DeepEqual(lineas, c.rechazos) {
+			t.Errorf("%s: rechazos %+v, se esperaban las líneas %v", c.archivo, estado.Rechazos, c.rechazos)
+		}
+		if estado.Renglones != len(c.movimientos)+len(c.rechazos) {
+			t.Errorf("%s: %d renglones leídos", c.archivo, estado.Renglones)
+		}
+	}
+}
+
+func TestLeerEstadoBancarioEncabezadoDesconocido(t *testing.T) {
+	nu, err := os.ReadFile(filepath.Join("testdata", "estados_bancarios", "nu.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LeerEstadoBancarioCSV(strings.NewReader(string(nu)), "bbva"); err == nil || !strings.Contains(err.Error(), "cargo, abono") {
+		t.Errorf("con --banco se deben indicar las columnas esperadas: %v", err)
+	}
+	if _, err := LeerEstadoBancarioCSV(strings.NewReader("a,b,c\n1,2,3\n"), ""); err == nil || !strings.Contains(err.Error(), "--banco") {
+		t.Errorf("un formato desconocido debe sugerir --banco: %v", err)
+	}
+	if _, err := LeerEstadoBancarioCSV(strings.NewReader(string(nu)), "bancomer-x"); err == nil {
+		t.Error("un banco desconocido debe ser un error")
+	}
+}
+
+// conAlmacenTemporal guarda los movimientos de la prueba en un directorio temporal
+func conAlmacenTemporal(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FINMEX_CONFIG_DIR", dir)
+	anterior, anteriorTipo, anteriorDirectorio := almacen, tipoAlmacen, directorioDatos
+	almacen = storage.NuevoArchivosJSON(filepath.Join(dir, ARCHIVO_TARJETAS), filepath.Join(dir, ARCHIVO_MOVIMIENTOS))
+	tipoAlmacen, directorioDatos = AlmacenJSON, dir
+	t.Cleanup(func() { almacen, tipoAlmacen, directorioDatos = anterior, anteriorTipo, anteriorDirectorio })
+}
+
+func TestDescartarMovimientosRegistradosAlReimportar(t *testing.T) {
+	conAlmacenTemporal(t)
+	estado := leerEstadoFixture(t, "bbva.csv")
+	for i := range estado.Movimientos {
+		estado.Movimientos[i].Tarjeta = "Azul"
+	}
+	if err := AgregarMovimientos(estado.Movimientos); err != nil {
+		t.Fatal(err)
+	}
+
+	// El mismo archivo otra vez no agrega nada, aunque cambien mayúsculas y espacios
+	otraVez := leerEstadoFixture(t, "bbva.csv")
+	for i := range otraVez.Movimientos {
+		otraVez.Movimientos[i].Tarjeta = "AZUL"
+	}
+	otraVez.Movimientos[1].Descripcion = "walmart insurgentes"
+	nuevos, repetidos, err := DescartarMovimientosRegistrados(otraVez.Movimientos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nuevos) != 0 || repetidos != 3 {
+		t.Errorf("reimportar: %d nuevos y %d repetidos", len(nuevos), repetidos)
+	}
+
+	// Una segunda compra idéntica el mismo día y la misma compra en otra tarjeta sí son nuevas
+	compra := estado.Movimientos[1]
+	enOtra := compra
+	enOtra.Tarjeta = "Oro"
+	nuevos, repetidos, err = DescartarMovimientosRegistrados([]Movimiento{compra, compra, enOtra})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repetidos != 1 || !reflect.DeepEqual(nuevos, []Movimiento{compra, enOtra}) {
+		t.Errorf("compra repetida: %d repetidos, nuevos %+v", repetidos, nuevos)
+	}
+}
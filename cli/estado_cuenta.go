@@ -0,0 +1,64 @@
+package cli
+
+import "fmt"
+
+// toleranciaTasa es la diferencia relativa a partir de la cual la tasa inferida se considera
+// distinta de la capturada, para absorber redondeos y la aproximación del saldo promedio
+const toleranciaTasa = 0.05
+
+// EstadoCuenta son los datos de un periodo tal como aparecen en el estado de cuenta
+type EstadoCuenta struct {
+	SaldoAnterior float64
+	Pagos         float64
+	Compras       float64
+	Interes       float64 // Interés cobrado en el periodo
+	InteresConIVA bool    // El interés reportado ya incluye IVA
+	Dias          int     // Días del periodo
+}
+
+// SaldoPromedio aproxima el saldo promedio diario del periodo como el promedio entre el saldo
+// inicial y el saldo antes de intereses al cierre, suponiendo pagos y compras repartidos
+func (e EstadoCuenta) SaldoPromedio() float64 {
+	final := e.SaldoAnterior - e.Pagos + e.Compras
+	return (e.SaldoAnterior + final) / 2
+}
+
+// TasaInferida es la tasa que el banco está cobrando según el estado de cuenta
+type TasaInferida struct {
+	SaldoPromedio float64
+	InteresSinIVA float64
+	Mensual       float64 // Tasa de un mes de 30 días
+	Anual         float64 // Mensual por 12, comparable con la tasa capturada
+	Capturada     float64
+	Diferencia    float64 // Anual menos capturada
+}
+
+// Aumento indica si la tasa inferida supera a la capturada más allá de la tolerancia
+func (t TasaInferida) Aumento() bool {
+	return t.Anual > t.Capturada*(1+toleranciaTasa)
+}
+
+// Disminucion indica si la tasa inferida es menor a la capturada más allá de la tolerancia
+func (t TasaInferida) Disminucion() bool {
+	return t.Anual < t.Capturada*(1-toleranciaTasa)
+}
+
+// InferirTasa calcula la tasa anual implícita en el interés cobrado sobre el saldo promedio
+// del periodo y la compara contra la tasa registrada de la tarjeta
+func InferirTasa(tarjeta TarjetaCredito, e EstadoCuenta) (TasaInferida, error) {
+	t := TasaInferida{SaldoPromedio: e.SaldoPromedio(), InteresSinIVA: e.Interes, Capturada: tarjeta.TasaInteres}
+	if e.Dias <= 0 {
+		return t, fmt.Errorf("El periodo debe tener al menos un día")
+	}
+	if t.SaldoPromedio <= 0 {
+		return t, fmt.Errorf("El saldo promedio del periodo es cero o negativo; no se puede inferir la tasa")
+	}
+	if e.InteresConIVA {
+		t.InteresSinIVA = e.Interes / (1 + IVA)
+	}
+
+	t.Mensual = t.InteresSinIVA / t.SaldoPromedio * 30 / float64(e.Dias)
+	t.Anual = t.Mensual * 12
+	t.Diferencia = t.Anual - t.Capturada
+	return t, nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// FiltroTarjetas selecciona un subconjunto de tarjetas por nombre o etiqueta
+type FiltroTarjetas struct {
+	Nombres []string // Si no está vacío, solo se incluyen estas tarjetas
+	Tags    []string // Si no está vacío, solo se incluyen tarjetas con alguna de estas etiquetas
+	Excluir []string // Nombres o etiquetas a sacar
+}
+
+// ParsearLista separa una lista capturada con comas, ignorando espacios y elementos vacíos
+func ParsearLista(texto string) []string {
+	var lista []string
+	for _, elemento := range strings.Split(texto, ",") {
+		if elemento = strings.TrimSpace(elemento); elemento != "" {
+			lista = append(lista, elemento)
+		}
+	}
+	return lista
+}
+
+// contieneClave indica si la lista contiene el texto sin distinguir mayúsculas ni espacios extra
+func contieneClave(lista []string, texto string) bool {
+	for _, l := range lista {
+		if normalizarClave(l) == normalizarClave(texto) {
+			return true
+		}
+	}
+	return false
+}
+
+// Incluye indica si una tarjeta con el nombre y etiquetas dados pasa el filtro
+func (f FiltroTarjetas) Incluye(nombre string, tags []string) bool {
+	if len(f.Nombres) > 0 && !contieneClave(f.Nombres, nombre) {
+		return false
+	}
+	if contieneClave(f.Excluir, nombre) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		encontrada := false
+		for _, t := range tags {
+			if contieneClave(f.Tags, t) {
+				encontrada = true
+			}
+		}
+		if !encontrada {
+			return false
+		}
+	}
+	for _, t := range tags {
+		if contieneClave(f.Excluir, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Verificar regresa un error si alguno de los nombres pedidos no corresponde a ninguna tarjeta
+func (f FiltroTarjetas) Verificar(nombres []string) error {
+	for _, pedido := range f.Nombres {
+		if !contieneClave(nombres, pedido) {
+			return errTarjetaNoEncontrada("", pedido)
+		}
+	}
+	return nil
+}
+
+// flagsFiltroTarjetas son los flags comunes para elegir qué tarjetas participan en un comando
+func flagsFiltroTarjetas() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "tarjetas", Usage: "Solo estas tarjetas, separadas por comas"},
+		&cli.StringFlag{Name: "tag", Usage: "Solo tarjetas con alguna de estas etiquetas, separadas por comas"},
+		&cli.StringFlag{Name: "excluir", Usage: "Nombres o etiquetas a excluir, separados por comas"},
+	}
+}
+
+// filtroTarjetas arma el filtro a partir de los flags de flagsFiltroTarjetas
+func filtroTarjetas(c *cli.Context) FiltroTarjetas {
+	return FiltroTarjetas{
+		Nombres: ParsearLista(c.String("tarjetas")),
+		Tags:    ParsearLista(c.String("tag")),
+		Excluir: ParsearLista(c.String("excluir")),
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+// MejorTasaDebitoNeta regresa la mayor tasa de rendimiento después de ISR entre las tarjetas
+// de débito registradas y el nombre de la tarjeta; se usa como tasa de oportunidad
+func MejorTasaDebitoNeta(tarjetas Tarjetas) (float64, string) {
+	mejor, nombre := 0.0, ""
+	for _, t := range tarjetas.Debito {
+		if neta := TasaNetaISR(t.TasaPonderada(t.Saldo)); neta > mejor || nombre == "" {
+			mejor, nombre = neta, t.Nombre
+		}
+	}
+	return mejor, nombre
+}
+
+// MejorTasaOportunidad regresa la mayor tasa neta anual entre las tarjetas de débito, las
+// inversiones en cetesdirecto, los pagarés y las cuentas de SOFIPO registradas, con el nombre del producto
+func MejorTasaOportunidad(tarjetas Tarjetas) (float64, string) {
+	mejor, nombre := MejorTasaDebitoNeta(tarjetas)
+	inflacion := InflacionVigente()
+	for _, inv := range tarjetas.Cetes {
+		if neta := inv.Rendimiento(inflacion).TasaNeta; neta > mejor || nombre == "" {
+			mejor, nombre = neta, inv.Nombre
+		}
+	}
+	for _, p := range tarjetas.Pagares {
+		if neta := p.Rendimiento(inflacion).TasaNeta; neta > mejor || nombre == "" {
+			mejor, nombre = neta, p.Nombre
+		}
+	}
+	for _, s := range tarjetas.Sofipos {
+		if s.Saldo <= 0 {
+			continue
+		}
+		if neta := s.Rendimiento(inflacion).TasaNeta; neta > mejor || nombre == "" {
+			mejor, nombre = neta, s.Nombre
+		}
+	}
+	return mejor, nombre
+}
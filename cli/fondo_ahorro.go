@@ -0,0 +1,79 @@
+package cli
+
+import "finmex/calc"
+
+// Límites de exención del fondo de ahorro (LISR art. 27 y 93)
+const (
+	TOPE_FONDO_AHORRO      = 0.13 // Aportación patronal máxima deducible: 13% del salario
+	TOPE_FONDO_AHORRO_UMAS = 1.3  // Y sin exceder 1.3 veces la UMA anual
+)
+
+// FondoAhorro describe el fondo de ahorro de la empresa, donde el patrón iguala la aportación
+type FondoAhorro struct {
+	SalarioMensual  float64
+	Aportacion      float64 // Porcentaje del salario que aporta el trabajador
+	TopePatronal    float64 // Porcentaje máximo que iguala la empresa
+	TasaRendimiento float64 // Tasa anual que paga el fondo
+	TasaMarginal    float64 // Tasa de ISR para la parte patronal que no está exenta
+}
+
+// SimulacionFondoAhorro resume lo acumulado en un año en el fondo y la alternativa de invertir por cuenta propia
+type SimulacionFondoAhorro struct {
+	AportacionTrabajador float64
+	AportacionPatronal   float64
+	Exento               float64
+	ISR                  float64 // Impuesto por la parte patronal que excede el límite exento
+	Intereses            float64
+	Acumulado            float64 // Al final del año, después de ISR
+	PropioIntereses      float64 // Intereses netos de invertir solo la aportación del trabajador
+	PropioAcumulado      float64
+}
+
+// Ventaja regresa cuánto más se acumula en el fondo que invirtiendo por cuenta propia
+func (s SimulacionFondoAhorro) Ventaja() float64 {
+	return s.Acumulado - s.PropioAcumulado
+}
+
+// SimularFondoAhorro acumula doce aportaciones mensuales en el fondo y en la alternativa.
+// La tasa alternativa es anual y antes de ISR; los rendimientos del fondo se consideran exentos.
+func SimularFondoAhorro(f FondoAhorro, tasaAlternativa float64, año int) (SimulacionFondoAhorro, error) {
+	umaDiario, err := calc.UMADiaria(año)
+	if err != nil {
+		return SimulacionFondoAhorro{}, err
+	}
+
+	trabajador := f.SalarioMensual * f.Aportacion
+	patronal := trabajador
+	if tope := f.SalarioMensual * f.TopePatronal; patronal > tope {
+		patronal = tope
+	}
+
+	s := SimulacionFondoAhorro{
+		AportacionTrabajador: trabajador * 12,
+		AportacionPatronal:   patronal * 12,
+	}
+
+	limite := f.SalarioMensual * 12 * TOPE_FONDO_AHORRO
+	if umas := umaDiario * 365 * TOPE_FONDO_AHORRO_UMAS; umas < limite {
+		limite = umas
+	}
+	s.Exento = s.AportacionPatronal
+	if s.Exento > limite {
+		s.Exento = limite
+	}
+	s.ISR = (s.AportacionPatronal - s.Exento) * f.TasaMarginal
+
+	tasaFondo := f.TasaRendimiento / 12
+	tasaPropia := TasaNetaISR(tasaAlternativa) / 12
+	fondo, propio := 0.0, 0.0
+	for mes := 0; mes < 12; mes++ {
+		fondo = fondo*(1+tasaFondo) + trabajador + patronal
+		propio = propio*(1+tasaPropia) + trabajador
+	}
+
+	s.Intereses = fondo - s.AportacionTrabajador - s.AportacionPatronal
+	s.Acumulado = fondo - s.ISR
+	s.PropioIntereses = propio - s.AportacionTrabajador
+	s.PropioAcumulado = propio
+	return s, nil
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"finmex/calc"
+)
+
+// Modos de la calculadora de hipotecas
+const (
+	ModoHipotecaBanco            = "banco"            // Crédito bancario en pesos
+	ModoHipotecaInfonavit        = "infonavit"        // Crédito Infonavit en pesos
+	ModoHipotecaVSM              = "vsm"              // Crédito Infonavit o Fovissste en veces salario mínimo
+	ModoHipotecaCofinanciamiento = "cofinanciamiento" // Parte Infonavit y parte banco (Cofinavit)
+)
+
+var modosHipoteca = []string{ModoHipotecaBanco, ModoHipotecaInfonavit, ModoHipotecaVSM, ModoHipotecaCofinanciamiento}
+
+const (
+	// TASA_MAXIMA_INFONAVIT es la tasa más alta de los créditos Infonavit en pesos; se usa si
+	// no se indica la que corresponde al salario
+	TASA_MAXIMA_INFONAVIT = 0.1045
+	// APORTACION_PATRONAL_VIVIENDA es la aportación del patrón a la subcuenta de vivienda, que
+	// con un crédito vigente se abona a la deuda
+	APORTACION_PATRONAL_VIVIENDA = 0.05
+	// ENGANCHE_MINIMO_BANCO es el enganche que suelen pedir los bancos
+	ENGANCHE_MINIMO_BANCO = 0.10
+)
+
+// CotizacionHipoteca son los datos de la simulación tal como los captura el usuario
+type CotizacionHipoteca struct {
+	Modo           string
+	ValorVivienda  float64
+	Enganche       float64
+	Subcuenta      float64 // Saldo de la subcuenta de vivienda que se suma al enganche
+	Años           int
+	Tasa           float64 // Tasa del banco, o del Infonavit en los modos sin banco
+	TasaInfonavit  float64 // Solo cofinanciamiento
+	MontoInfonavit float64 // Solo cofinanciamiento
+	IncrementoVSM  float64 // Aumento anual del salario mínimo, solo en VSM
+	Salario        float64 // Salario mensual, para la aportación patronal en los modos Infonavit
+
+	SeguroVida       float64
+	SeguroDaños      float64
+	ComisionApertura float64
+	GastosIniciales  float64
+	ComisionMensual  float64
+	AbonoMensual     float64
+	AbonoAnual       float64
+	ReducirPago      bool
+}
+
+// AnalisisHipoteca es el resultado de hipoteca simular
+type AnalisisHipoteca struct {
+	Modo               string              `json:"modo"`
+	ValorVivienda      float64             `json:"valor_vivienda"`
+	Enganche           float64             `json:"enganche"` // Incluye la subcuenta de vivienda
+	Tramos             []TramoHipoteca     `json:"tramos"`
+	CAT                float64             `json:"cat"`
+	AportacionPatronal float64             `json:"aportacion_patronal,omitempty"` // Mensual
+	Simulacion         SimulacionHipoteca  `json:"simulacion"`
+	ConAbonos          *SimulacionHipoteca `json:"con_abonos,omitempty"`
+}
+
+// Hipoteca arma las condiciones del crédito según el modo
+func (c CotizacionHipoteca) Hipoteca() (Hipoteca, error) {
+	h := Hipoteca{
+		ValorVivienda:    c.ValorVivienda,
+		Meses:            c.Años * 12,
+		SeguroVida:       c.SeguroVida,
+		SeguroDaños:      c.SeguroDaños,
+		ComisionApertura: c.ComisionApertura,
+		GastosIniciales:  c.GastosIniciales,
+		ComisionMensual:  c.ComisionMensual,
+		AbonoMensual:     c.AbonoMensual,
+		AbonoAnual:       c.AbonoAnual,
+		ReducirPago:      c.ReducirPago,
+	}
+	financiado := c.ValorVivienda - c.Enganche - c.Subcuenta
+	if financiado <= 0 {
+		return h, errDatosInvalidos("El enganche cubre el valor de la vivienda; no hay nada que financiar",
+			"The down payment covers the home value; there is nothing to finance")
+	}
+
+	switch c.Modo {
+	case ModoHipotecaBanco:
+		h.Tramos = []TramoHipoteca{{Nombre: "Banco", Monto: financiado, TasaAnual: c.Tasa}}
+	case ModoHipotecaInfonavit:
+		h.Tramos = []TramoHipoteca{{Nombre: "Infonavit", Monto: financiado, TasaAnual: c.Tasa}}
+	case ModoHipotecaVSM:
+		h.Tramos = []TramoHipoteca{{Nombre: "Crédito en VSM", Monto: financiado, TasaAnual: c.Tasa, Actualizacion: c.IncrementoVSM}}
+	case ModoHipotecaCofinanciamiento:
+		if c.MontoInfonavit <= 0 {
+			return h, errDatosInvalidos("El cofinanciamiento necesita el monto que presta el Infonavit (--monto-infonavit)",
+				"Co-financing needs the amount lent by Infonavit (--monto-infonavit)")
+		}
+		if c.MontoInfonavit >= financiado {
+			return h, errDatosInvalidos(
+				fmt.Sprintf("El Infonavit cubre los $%.2f a financiar; usa el modo infonavit", financiado),
+				fmt.Sprintf("Infonavit covers the $%.2f to finance; use the infonavit mode", financiado))
+		}
+		h.Tramos = []TramoHipoteca{
+			{Nombre: "Infonavit", Monto: c.MontoInfonavit, TasaAnual: c.TasaInfonavit},
+			{Nombre: "Banco", Monto: financiado - c.MontoInfonavit, TasaAnual: c.Tasa},
+		}
+	default:
+		return h, errDatosInvalidos(
+			fmt.Sprintf("Modo inválido '%s' (usa %s)", c.Modo, strings.Join(modosHipoteca, ", ")),
+			fmt.Sprintf("Invalid mode '%s' (use %s)", c.Modo, strings.Join(modosHipoteca, ", ")))
+	}
+	return h, nil
+}
+
+// AnalizarHipoteca simula el crédito con el plan contratado y, si hay pagos anticipados,
+// también con ellos para medir su efecto
+func AnalizarHipoteca(c CotizacionHipoteca) (AnalisisHipoteca, error) {
+	a := AnalisisHipoteca{Modo: c.Modo, ValorVivienda: c.ValorVivienda, Enganche: c.Enganche + c.Subcuenta}
+	h, err := c.Hipoteca()
+	if err != nil {
+		return a, err
+	}
+	a.Tramos = h.Tramos
+	if c.Modo != ModoHipotecaBanco {
+		a.AportacionPatronal = c.Salario * APORTACION_PATRONAL_VIVIENDA
+	}
+
+	if a.CAT, err = calc.CATHipoteca(h); err != nil {
+		return a, err
+	}
+	contratado := h
+	contratado.AbonoMensual, contratado.AbonoAnual, contratado.ReducirPago = 0, 0, false
+	if a.Simulacion, err = calc.SimularHipoteca(contratado); err != nil {
+		return a, err
+	}
+	if h.AbonoMensual > 0 || h.AbonoAnual > 0 {
+		conAbonos, err := calc.SimularHipoteca(h)
+		if err != nil {
+			return a, err
+		}
+		a.ConAbonos = &conAbonos
+	}
+	return a, nil
+}
+
+// AñoHipoteca resume un año de la tabla de amortización
+type AñoHipoteca struct {
+	Año        int
+	Pagado     float64
+	Intereses  float64
+	Capital    float64
+	Abonos     float64
+	Accesorios float64 // Seguros y comisiones
+	SaldoFinal float64
+}
+
+// ResumenAnualHipoteca agrupa la tabla mensual por año
+func ResumenAnualHipoteca(meses []MesHipoteca) []AñoHipoteca {
+	var años []AñoHipoteca
+	for _, m := range meses {
+		año := (m.Mes-1)/12 + 1
+		if len(años) < año {
+			años = append(años, AñoHipoteca{Año: año})
+		}
+		a := &años[año-1]
+		a.Pagado += m.Pago
+		a.Intereses += m.Interes
+		a.Capital += m.Capital
+		a.Abonos += m.Abono
+		a.Accesorios += m.Seguros + m.Comisiones
+		a.SaldoFinal = m.SaldoFinal
+	}
+	return años
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// validarMesSaldo verifica que el mes tenga formato AAAA-MM y no sea futuro
+func validarMesSaldo(mes string, hoy time.Time) error {
+	fecha, err := time.Parse("2006-01", mes)
+	if err != nil {
+		return errDatosInvalidos(
+			fmt.Sprintf("Mes inválido '%s' (usa AAAA-MM)", mes),
+			fmt.Sprintf("Invalid month '%s' (use YYYY-MM)", mes))
+	}
+	if fecha.After(hoy) {
+		return errDatosInvalidos(
+			fmt.Sprintf("El mes %s todavía no llega", mes),
+			fmt.Sprintf("The month %s has not arrived yet", mes))
+	}
+	return nil
+}
+
+// RegistrarSaldo guarda el saldo del mes de la tarjeta, reemplazando el que ya hubiera, y
+// mantiene el historial ordenado por tarjeta y mes
+func (t *Tarjetas) RegistrarSaldo(r RegistroSaldo) {
+	for i, s := range t.Saldos {
+		if s.Tipo == r.Tipo && normalizarClave(s.Tarjeta) == normalizarClave(r.Tarjeta) && s.Mes == r.Mes {
+			t.Saldos[i] = r
+			return
+		}
+	}
+	t.Saldos = append(t.Saldos, r)
+	sort.SliceStable(t.Saldos, func(i, j int) bool {
+		a, b := t.Saldos[i], t.Saldos[j]
+		if a.Tipo != b.Tipo {
+			return a.Tipo < b.Tipo
+		}
+		if ka, kb := normalizarClave(a.Tarjeta), normalizarClave(b.Tarjeta); ka != kb {
+			return ka < kb
+		}
+		return a.Mes < b.Mes
+	})
+}
+
+// RenombrarHistorial pasa el historial de saldos de una tarjeta a su nuevo nombre
+func (t *Tarjetas) RenombrarHistorial(tipo, anterior, nuevo string) {
+	for i, s := range t.Saldos {
+		if s.Tipo == tipo && normalizarClave(s.Tarjeta) == normalizarClave(anterior) {
+			t.Saldos[i].Tarjeta = nuevo
+		}
+	}
+}
+
+// HistorialSaldos regresa los registros de una tarjeta ordenados por mes
+func (t Tarjetas) HistorialSaldos(tipo, tarjeta string) []RegistroSaldo {
+	var registros []RegistroSaldo
+	for _, s := range t.Saldos {
+		if s.Tipo == tipo && normalizarClave(s.Tarjeta) == normalizarClave(tarjeta) {
+			registros = append(registros, s)
+		}
+	}
+	sort.SliceStable(registros, func(i, j int) bool { return registros[i].Mes < registros[j].Mes })
+	return registros
+}
+
+// EvolucionSaldo resume cómo cambió el saldo de una tarjeta entre el primer y el último
+// registro. El crecimiento incluye depósitos y retiros: mide el saldo, no el rendimiento.
+type EvolucionSaldo struct {
+	Tipo      string          `json:"tipo"`
+	Tarjeta   string          `json:"tarjeta"`
+	Registros []RegistroSaldo `json:"registros"`
+	Meses     int             `json:"meses"` // Entre el primer y el último registro
+	Inicial   float64         `json:"inicial"`
+	Final     float64         `json:"final"`
+	// Tasas anualizadas; cero si hay un solo mes o el saldo inicial no es positivo
+	CrecimientoAnual float64 `json:"crecimiento_anual"`
+	CrecimientoReal  float64 `json:"crecimiento_real"` // Descontando la inflación
+	Inflacion        float64 `json:"inflacion"`
+}
+
+// EvolucionarSaldo calcula el crecimiento anualizado del saldo y su equivalente real con la
+// inflación anual dada
+func EvolucionarSaldo(registros []RegistroSaldo, inflacion float64) EvolucionSaldo {
+	e := EvolucionSaldo{Registros: registros, Inflacion: inflacion}
+	if len(registros) == 0 {
+		return e
+	}
+	primero, ultimo := registros[0], registros[len(registros)-1]
+	e.Tipo, e.Tarjeta = ultimo.Tipo, ultimo.Tarjeta
+	e.Inicial, e.Final = primero.Saldo, ultimo.Saldo
+
+	desde, err1 := time.Parse("2006-01", primero.Mes)
+	hasta, err2 := time.Parse("2006-01", ultimo.Mes)
+	if err1 != nil || err2 != nil {
+		return e
+	}
+	e.Meses = (hasta.Year()-desde.Year())*12 + int(hasta.Month()-desde.Month())
+	if e.Meses > 0 && e.Inicial > 0 && e.Final >= 0 {
+		e.CrecimientoAnual = math.Pow(e.Final/e.Inicial, 12/float64(e.Meses)) - 1
+		e.CrecimientoReal = (1+e.CrecimientoAnual)/(1+inflacion) - 1
+	}
+	return e
+}
+
+// nivelesSparkline son los bloques de menor a mayor altura
+var nivelesSparkline = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline dibuja los valores en una línea con bloques de ocho alturas, del mínimo al máximo
+func Sparkline(valores []float64) string {
+	if len(valores) == 0 {
+		return ""
+	}
+	minimo, maximo := valores[0], valores[0]
+	for _, v := range valores {
+		minimo, maximo = math.Min(minimo, v), math.Max(maximo, v)
+	}
+	var b strings.Builder
+	for _, v := range valores {
+		nivel := len(nivelesSparkline) / 2
+		if maximo > minimo {
+			nivel = int(math.Round((v - minimo) / (maximo - minimo) * float64(len(nivelesSparkline)-1)))
+		}
+		b.WriteRune(nivelesSparkline[nivel])
+	}
+	return b.String()
+}
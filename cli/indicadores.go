@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	"finmex/banxico"
+)
+
+// ARCHIVO_CACHE_BANXICO guarda los últimos indicadores consultados en el SIE
+const ARCHIVO_CACHE_BANXICO = "banxico.json"
+
+// Indicadores que se consultan en Banxico, en el orden en que se muestran
+var indicadoresBanxico = []struct {
+	Clave       string
+	Serie       string
+	Descripcion string
+	Porcentaje  bool
+}{
+	{"inflacion", banxico.SerieInflacion, "Inflación anual", true},
+	{"tiie", banxico.SerieTIIE28, "TIIE 28 días", true},
+	{"cetes", banxico.SerieCetes28, "CETES 28 días", true},
+	{"fix", banxico.SerieFIX, "Tipo de cambio FIX", false},
+	{"udis", banxico.SerieUDIS, "Valor de la UDI", false},
+}
+
+// clienteBanxico crea el cliente del SIE con el token de FINMEX_BANXICO_TOKEN. Sin token
+// solo se usa la copia local.
+func clienteBanxico() *banxico.Cliente {
+	cliente := banxico.NuevoCliente(os.Getenv("FINMEX_BANXICO_TOKEN"), rutaComun(ARCHIVO_CACHE_BANXICO))
+	if url := os.Getenv("FINMEX_URL_BANXICO"); url != "" {
+		cliente.URL = url
+	}
+	return cliente
+}
+
+// inflacionPredeterminada se usa cuando Banxico no da la inflación; config.yaml puede
+// reemplazar a INFLACION_ANUAL
+var inflacionPredeterminada = INFLACION_ANUAL
+
+var inflacionVigente struct {
+	sync.Once
+	valor float64
+}
+
+// InflacionVigente regresa la inflación anual más reciente publicada por Banxico, en
+// decimal. Se consulta una sola vez por ejecución; sin token ni copia local, o si el SIE
+// no responde, se usa la inflación de config.yaml o INFLACION_ANUAL.
+func InflacionVigente() float64 {
+	if escenarioActivo != nil && escenarioActivo.Inflacion != nil {
+		return *escenarioActivo.Inflacion
+	}
+	inflacionVigente.Do(func() {
+		inflacionVigente.valor = inflacionPredeterminada
+		datos, err := clienteBanxico().Oportuno(banxico.SerieInflacion)
+		if err != nil && !errors.Is(err, banxico.ErrSinToken) {
+			registro.Warn("no se pudo actualizar la inflación desde Banxico", "error", err)
+		}
+		if len(datos) > 0 {
+			inflacionVigente.valor = datos[0].Valor / 100
+			registro.Debug("inflación de Banxico", "fecha", datos[0].Fecha, "valor", datos[0].Valor)
+		}
+	})
+	return inflacionVigente.valor
+}
@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ARCHIVO_INDICE_MOVIMIENTOS guarda el índice de búsqueda del archivo de movimientos
+const ARCHIVO_INDICE_MOVIMIENTOS = "movimientos.idx"
+
+// IndiceMovimientos relaciona categorías, tarjetas y palabras de la descripción con la
+// posición (en bytes) de cada movimiento dentro del archivo NDJSON
+type IndiceMovimientos struct {
+	Tamaño     int64  // Bytes del archivo de movimientos ya indexados
+	ModTime    int64  // Fecha de modificación del archivo al momento de indexar
+	Hash       []byte // SHA-256 de los bytes ya indexados
+	Categorias map[string][]int64
+	Tarjetas   map[string][]int64
+	Palabras   map[string][]int64
+}
+
+// nuevoIndiceMovimientos crea un índice vacío
+func nuevoIndiceMovimientos() *IndiceMovimientos {
+	return &IndiceMovimientos{
+		Categorias: map[string][]int64{},
+		Tarjetas:   map[string][]int64{},
+		Palabras:   map[string][]int64{},
+	}
+}
+
+// CargarIndiceMovimientos lee el índice persistido y lo pone al día con el archivo de
+// movimientos. Como el archivo solo crece, únicamente se indexan las líneas nuevas;
+// si el archivo fue reescrito el índice se reconstruye completo.
+func CargarIndiceMovimientos() (*IndiceMovimientos, error) {
+	info, err := os.Stat(rutaDatos(ARCHIVO_MOVIMIENTOS))
+	if os.IsNotExist(err) {
+		return nuevoIndiceMovimientos(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	indice := leerIndiceMovimientos()
+	if indice == nil || !indice.vigente(info) {
+		registro.Info("reconstruyendo índice de movimientos", "archivo", rutaDatos(ARCHIVO_INDICE_MOVIMIENTOS))
+		indice = nuevoIndiceMovimientos()
+	}
+
+	if info.Size() == indice.Tamaño && info.ModTime().UnixNano() == indice.ModTime {
+		return indice, nil
+	}
+
+	desde := indice.Tamaño
+	if err := indice.indexarDesde(desde); err != nil {
+		return nil, err
+	}
+	registro.Debug("movimientos indexados", "desde", desde, "hasta", indice.Tamaño)
+	indice.ModTime = info.ModTime().UnixNano()
+	if indice.Hash, err = hashMovimientos(indice.Tamaño); err != nil {
+		return nil, err
+	}
+
+	// Si no se puede guardar el índice seguimos con el que está en memoria
+	if err := guardarIndiceMovimientos(indice); err != nil {
+		registro.Warn("no se pudo guardar el índice de movimientos", "error", err)
+	}
+	return indice, nil
+}
+
+// vigente indica si el índice corresponde todavía al archivo de movimientos. Con el mismo
+// tamaño y fecha de modificación no se lee nada; si cambiaron, los bytes ya indexados deben
+// ser los mismos, para distinguir movimientos agregados al final de un archivo reescrito.
+func (ix *IndiceMovimientos) vigente(info os.FileInfo) bool {
+	if info.Size() < ix.Tamaño {
+		return false
+	}
+	if info.Size() == ix.Tamaño && info.ModTime().UnixNano() == ix.ModTime {
+		return true
+	}
+	hash, err := hashMovimientos(ix.Tamaño)
+	return err == nil && bytes.Equal(hash, ix.Hash)
+}
+
+// hashMovimientos calcula el SHA-256 de los primeros bytes del archivo de movimientos
+func hashMovimientos(tamaño int64) ([]byte, error) {
+	archivo, err := os.Open(rutaDatos(ARCHIVO_MOVIMIENTOS))
+	if err != nil {
+		return nil, err
+	}
+	defer archivo.Close()
+
+	h := sha256.New()
+	if _, err := io.CopyN(h, archivo, tamaño); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// ReconstruirIndiceMovimientos descarta el índice guardado y lo genera de nuevo
+func ReconstruirIndiceMovimientos() (*IndiceMovimientos, error) {
+	os.Remove(rutaDatos(ARCHIVO_INDICE_MOVIMIENTOS))
+	return CargarIndiceMovimientos()
+}
+
+// indexarDesde agrega al índice los movimientos a partir de la posición dada
+func (ix *IndiceMovimientos) indexarDesde(posicion int64) error {
+	archivo, err := os.Open(rutaDatos(ARCHIVO_MOVIMIENTOS))
+	if err != nil {
+		return err
+	}
+	defer archivo.Close()
+
+	if _, err := archivo.Seek(posicion, io.SeekStart); err != nil {
+		return err
+	}
+
+	lector := bufio.NewReader(archivo)
+	for {
+		linea, err := lector.ReadBytes('\n')
+		if len(linea) > 0 {
+			var m Movimiento
+			if err := json.Unmarshal(linea, &m); err == nil {
+				ix.agregar(m, posicion)
+			} else if len(bytes.TrimSpace(linea)) > 0 {
+				registro.Warn("línea de movimientos inválida", "posicion", posicion, "error", err)
+			}
+			posicion += int64(len(linea))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	ix.Tamaño = posicion
+	return nil
+}
+
+// agregar registra un movimiento ubicado en la posición dada
+func (ix *IndiceMovimientos) agregar(m Movimiento, posicion int64) {
+	if m.Categoria != "" {
+		clave := normalizarClave(m.Categoria)
+		ix.Categorias[clave] = append(ix.Categorias[clave], posicion)
+	}
+	if m.Tarjeta != "" {
+		clave := normalizarClave(m.Tarjeta)
+		ix.Tarjetas[clave] = append(ix.Tarjetas[clave], posicion)
+	}
+	vistas := map[string]bool{}
+	for _, palabra := range palabrasClave(m.Descripcion) {
+		if !vistas[palabra] {
+			vistas[palabra] = true
+			ix.Palabras[palabra] = append(ix.Palabras[palabra], posicion)
+		}
+	}
+}
+
+// Candidatos regresa, en orden de archivo, las posiciones de los movimientos que pueden
+// cumplir el filtro. La búsqueda de texto usa las palabras indexadas que contienen cada
+// término, así que el resultado debe verificarse con FiltroMovimientos.Coincide. Regresa
+// false si el filtro no tiene nada que buscar en el índice, como un texto sin letras ni
+// números, y entonces hay que recorrer todos los movimientos.
+func (ix *IndiceMovimientos) Candidatos(filtro FiltroMovimientos) ([]int64, bool) {
+	var conjuntos [][]int64
+
+	if filtro.Categoria != "" {
+		conjuntos = append(conjuntos, ix.Categorias[normalizarClave(filtro.Categoria)])
+	}
+	if filtro.Tarjeta != "" {
+		conjuntos = append(conjuntos, ix.Tarjetas[normalizarClave(filtro.Tarjeta)])
+	}
+	for _, termino := range palabrasClave(filtro.Texto) {
+		var union []int64
+		for palabra, posiciones := range ix.Palabras {
+			if strings.Contains(palabra, termino) {
+				union = append(union, posiciones...)
+			}
+		}
+		conjuntos = append(conjuntos, ordenarUnicos(union))
+	}
+
+	if len(conjuntos) == 0 {
+		return nil, false
+	}
+
+	resultado := conjuntos[0]
+	for _, otro := range conjuntos[1:] {
+		resultado = intersectar(resultado, otro)
+	}
+	return resultado, true
+}
+
+// BuscarMovimientosIndexados regresa una página de movimientos que cumplen el filtro,
+// leyendo del archivo solo las líneas que el índice señala como candidatas. El índice
+// apunta a posiciones de movimientos.ndjson; con SQLite el filtro lo resuelven los
+// índices de la base.
+func BuscarMovimientosIndexados(filtro FiltroMovimientos, pagina, porPagina int) ([]Movimiento, error) {
+	if filtro == (FiltroMovimientos{}) || !almacenEsArchivoJSON() {
+		return PaginaMovimientos(filtro, pagina, porPagina)
+	}
+	if pagina < 1 {
+		pagina = 1
+	}
+	if porPagina < 1 {
+		porPagina = 50
+	}
+
+	indice, err := CargarIndiceMovimientos()
+	if err != nil {
+		return nil, err
+	}
+
+	candidatos, ok := indice.Candidatos(filtro)
+	if !ok {
+		return PaginaMovimientos(filtro, pagina, porPagina)
+	}
+	if len(candidatos) == 0 {
+		return nil, nil
+	}
+
+	archivo, err := os.Open(rutaDatos(ARCHIVO_MOVIMIENTOS))
+	if err != nil {
+		return nil, err
+	}
+	defer archivo.Close()
+
+	omitir := (pagina - 1) * porPagina
+	resultado := make([]Movimiento, 0, porPagina)
+	for _, posicion := range candidatos {
+		m, err := leerMovimientoEn(archivo, posicion)
+		if err != nil {
+			return nil, err
+		}
+		if !filtro.Coincide(m) {
+			continue
+		}
+		if omitir > 0 {
+			omitir--
+			continue
+		}
+		resultado = append(resultado, m)
+		if len(resultado) == porPagina {
+			break
+		}
+	}
+	return resultado, nil
+}
+
+// leerMovimientoEn lee el movimiento que empieza en la posición dada del archivo
+func leerMovimientoEn(archivo *os.File, posicion int64) (Movimiento, error) {
+	var m Movimiento
+	lector := bufio.NewReader(io.NewSectionReader(archivo, posicion, maxLineaMovimiento))
+	linea, err := lector.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return m, err
+	}
+	err = json.Unmarshal(linea, &m)
+	return m, err
+}
+
+// leerIndiceMovimientos lee el índice guardado; regresa nil si no existe o está dañado
+func leerIndiceMovimientos() *IndiceMovimientos {
+	archivo, err := os.Open(rutaDatos(ARCHIVO_INDICE_MOVIMIENTOS))
+	if err != nil {
+		return nil
+	}
+	defer archivo.Close()
+
+	indice := nuevoIndiceMovimientos()
+	if err := gob.NewDecoder(bufio.NewReader(archivo)).Decode(indice); err != nil {
+		return nil
+	}
+	return indice
+}
+
+// guardarIndiceMovimientos persiste el índice en disco
+func guardarIndiceMovimientos(indice *IndiceMovimientos) error {
+	archivo, err := os.Create(rutaDatos(ARCHIVO_INDICE_MOVIMIENTOS))
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(archivo)
+	if err := gob.NewEncoder(w).Encode(indice); err != nil {
+		archivo.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		archivo.Close()
+		return err
+	}
+	return archivo.Close()
+}
+
+// IndiceTarjetas permite buscar tarjetas por nombre o banco sin recorrer las listas
+type IndiceTarjetas struct {
+	debitoPorNombre  map[string]int
+	creditoPorNombre map[string]int
+	debitoPorBanco   map[string][]int
+	creditoPorBanco  map[string][]int
+}
+
+// NuevoIndiceTarjetas indexa las tarjetas de débito y crédito por nombre y banco
+func NuevoIndiceTarjetas(tarjetas Tarjetas) *IndiceTarjetas {
+	ix := &IndiceTarjetas{
+		debitoPorNombre:  map[string]int{},
+		creditoPorNombre: map[string]int{},
+		debitoPorBanco:   map[string][]int{},
+		creditoPorBanco:  map[string][]int{},
+	}
+	for i, t := range tarjetas.Debito {
+		ix.debitoPorNombre[normalizarClave(t.Nombre)] = i
+		banco := normalizarClave(t.Banco)
+		ix.debitoPorBanco[banco] = append(ix.debitoPorBanco[banco], i)
+	}
+	for i, t := range tarjetas.Credito {
+		ix.creditoPorNombre[normalizarClave(t.Nombre)] = i
+		banco := normalizarClave(t.Banco)
+		ix.creditoPorBanco[banco] = append(ix.creditoPorBanco[banco], i)
+	}
+	return ix
+}
+
+// Debito regresa la posición de la tarjeta de débito con ese nombre
+func (ix *IndiceTarjetas) Debito(nombre string) (int, bool) {
+	i, ok := ix.debitoPorNombre[normalizarClave(nombre)]
+	return i, ok
+}
+
+// Credito regresa la posición de la tarjeta de crédito con ese nombre
+func (ix *IndiceTarjetas) Credito(nombre string) (int, bool) {
+	i, ok := ix.creditoPorNombre[normalizarClave(nombre)]
+	return i, ok
+}
+
+// DebitoPorBanco regresa las posiciones de las tarjetas de débito del banco
+func (ix *IndiceTarjetas) DebitoPorBanco(banco string) []int {
+	return ix.debitoPorBanco[normalizarClave(banco)]
+}
+
+// CreditoPorBanco regresa las posiciones de las tarjetas de crédito del banco
+func (ix *IndiceTarjetas) CreditoPorBanco(banco string) []int {
+	return ix.creditoPorBanco[normalizarClave(banco)]
+}
+
+// normalizarClave unifica mayúsculas y espacios para usar el texto como clave de índice
+func normalizarClave(texto string) string {
+	return strings.Join(strings.Fields(strings.ToLower(norm.NFC.String(texto))), " ")
+}
+
+// palabrasClave separa un texto en palabras en minúsculas, sin signos de puntuación
+func palabrasClave(texto string) []string {
+	return strings.FieldsFunc(strings.ToLower(texto), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// ordenarUnicos ordena las posiciones y elimina repetidas
+func ordenarUnicos(posiciones []int64) []int64 {
+	sort.Slice(posiciones, func(i, j int) bool { return posiciones[i] < posiciones[j] })
+	resultado := posiciones[:0]
+	for i, p := range posiciones {
+		if i == 0 || p != posiciones[i-1] {
+			resultado = append(resultado, p)
+		}
+	}
+	return resultado
+}
+
+// intersectar regresa las posiciones presentes en ambas listas ordenadas
+func intersectar(a, b []int64) []int64 {
+	var resultado []int64
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			resultado = append(resultado, a[i])
+			i++
+			j++
+		}
+	}
+	return resultado
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"finmex/storage"
+)
+
+func TestBuscarTextoSinPalabrasRecorreTodo(t *testing.T) {
+	conAlmacenTemporal(t)
+	movimientos := []Movimiento{
+		{Fecha: "2024-01-05", Descripcion: "OXXO PERIFÉRICO", Monto: 50, Tarjeta: "Azul"},
+		{Fecha: "2024-01-06", Descripcion: "PAGO #123 - SPEI", Monto: 100, Tarjeta: "Azul"},
+	}
+	if err := AgregarMovimientos(movimientos); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sin letras ni números el índice no tiene qué buscar, pero el texto sí aparece
+	for _, texto := range []string{"#", " - "} {
+		encontrados, err := BuscarMovimientosIndexados(FiltroMovimientos{Texto: texto}, 1, 10)
+		if err != nil || !reflect.DeepEqual(encontrados, movimientos[1:]) {
+			t.Errorf("%q: %+v, %v", texto, encontrados, err)
+		}
+	}
+}
+
+func TestIndiceSeReconstruyeSiSeReescribeElArchivo(t *testing.T) {
+	conAlmacenTemporal(t)
+	if err := AgregarMovimientos([]Movimiento{{Fecha: "2024-01-05", Descripcion: "OXXO", Monto: 50}}); err != nil {
+		t.Fatal(err)
+	}
+	if encontrados, _ := BuscarMovimientosIndexados(FiltroMovimientos{Texto: "oxxo"}, 1, 10); len(encontrados) != 1 {
+		t.Fatalf("antes de reescribir: %+v", encontrados)
+	}
+
+	// Otro contenido que deja el archivo más grande que lo indexado
+	ruta := rutaDatos(ARCHIVO_MOVIMIENTOS)
+	contenido := `{"fecha":"2024-02-01","descripcion":"WALMART","monto":75}` + "\n" +
+		`{"fecha":"2024-02-02","descripcion":"OXXO ROMA","monto":20}` + "\n"
+	if err := os.WriteFile(ruta, []byte(contenido), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	encontrados, err := BuscarMovimientosIndexados(FiltroMovimientos{Texto: "walmart"}, 1, 10)
+	if err != nil || len(encontrados) != 1 || encontrados[0].Monto != 75 {
+		t.Errorf("walmart después de reescribir: %+v, %v", encontrados, err)
+	}
+	encontrados, err = BuscarMovimientosIndexados(FiltroMovimientos{Texto: "oxxo"}, 1, 10)
+	if err != nil || len(encontrados) != 1 || encontrados[0].Descripcion != "OXXO ROMA" {
+		t.Errorf("oxxo después de reescribir: %+v, %v", encontrados, err)
+	}
+}
+
+func TestBuscarMovimientosEnSQLite(t *testing.T) {
+	conAlmacenTemporal(t)
+	db, err := storage.AbrirSQLite(filepath.Join(t.TempDir(), ARCHIVO_BASE_DATOS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	almacen, tipoAlmacen = db, AlmacenSQLite
+	t.Cleanup(func() { db.Close() })
+
+	var movimientos []Movimiento
+	for i := 0; i < 30; i++ {
+		m := Movimiento{Fecha: fmt.Sprintf("2024-01-%02d", i+1), Descripcion: fmt.Sprintf("OXXO %d", i), Monto: float64(i), Tarjeta: "Azul"}
+		if i%3 == 0 {
+			m.Descripcion, m.Categoria, m.Tarjeta = fmt.Sprintf("Farmacia %d", i), "Salud", "Oro"
+		}
+		movimientos = append(movimientos, m)
+	}
+	if err := AgregarMovimientos(movimientos); err != nil {
+		t.Fatal(err)
+	}
+
+	// La segunda página de cinco cuenta solo los que cumplen el filtro
+	encontrados, err := BuscarMovimientosIndexados(FiltroMovimientos{Texto: "oxxo", Tarjeta: "AZUL"}, 2, 5)
+	if err != nil || len(encontrados) != 5 || encontrados[0].Monto != 8 || encontrados[4].Monto != 14 {
+		t.Errorf("oxxo en Azul: %+v, %v", encontrados, err)
+	}
+	encontrados, err = BuscarMovimientosIndexados(FiltroMovimientos{Categoria: "salud", Desde: "2024-01-10", Hasta: "2024-01-20"}, 1, 10)
+	if err != nil || len(encontrados) != 4 || encontrados[0].Monto != 9 {
+		t.Errorf("salud a mediados de enero: %+v, %v", encontrados, err)
+	}
+	if total, err := TotalMovimientos(FiltroMovimientos{Tarjeta: "oro"}, "2024-01"); err != nil || total != 135 {
+		t.Errorf("total de Oro: %g, %v", total, err)
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import "fmt"
+
+// CuentaContraInflacion es el resultado de una cuenta de débito frente a la inflación
+type CuentaContraInflacion struct {
+	Cuenta        TarjetaDebito
+	TasaNeta      float64 // Rendimiento después de ISR
+	Brecha        float64 // Pesos al año que se pierden contra la inflación; cero si gana
+	GanaInflacion bool
+}
+
+// AlertaInflacion resume si alguna cuenta de débito le gana a la inflación
+type AlertaInflacion struct {
+	Activa     bool // Ninguna cuenta supera la inflación después de ISR
+	Inflacion  float64
+	Cuentas    []CuentaContraInflacion
+	Brecha     float64     // Suma de lo que pierden las cuentas en pesos al año
+	Benchmarks []Benchmark // Referencias que sí superan la inflación después de ISR
+}
+
+// RevisarInflacion compara cada cuenta de débito contra la inflación. La brecha usa el saldo
+// registrado, las comisiones y el ISR; sin saldo solo se comparan las tasas.
+func RevisarInflacion(tarjetas Tarjetas, benchmarks []Benchmark, inflacion float64) AlertaInflacion {
+	a := AlertaInflacion{Inflacion: inflacion, Activa: len(tarjetas.Debito) > 0}
+
+	for _, t := range tarjetas.Debito {
+		c := CuentaContraInflacion{Cuenta: t, TasaNeta: TasaNetaISR(t.TasaPonderada(t.Saldo))}
+		c.GanaInflacion = c.TasaNeta > inflacion
+		if t.Saldo > 0 {
+			neto := t.Saldo*c.TasaNeta - t.ComisionAnual
+			if t.Saldo < t.SaldoMinimo {
+				neto = -t.ComisionAnual
+			}
+			c.GanaInflacion = neto > t.Saldo*inflacion
+			if !c.GanaInflacion {
+				c.Brecha = t.Saldo*inflacion - neto
+			}
+		}
+		if c.GanaInflacion {
+			a.Activa = false
+		}
+		a.Brecha += c.Brecha
+		a.Cuentas = append(a.Cuentas, c)
+	}
+
+	for _, b := range benchmarks {
+		if TasaNetaISR(b.Tasa) > inflacion {
+			a.Benchmarks = append(a.Benchmarks, b)
+		}
+	}
+	return a
+}
+
+// Mensaje describe la alerta en una línea, o regresa vacío si no está activa
+func (a AlertaInflacion) Mensaje() string {
+	if !a.Activa {
+		return ""
+	}
+	mensaje := fmt.Sprintf("ALERTA: Ninguna de tus cuentas de débito supera la inflación de %.2f%% después de ISR", a.Inflacion*100)
+	if a.Brecha > 0 {
+		mensaje += fmt.Sprintf("; pierdes $%.2f al año", a.Brecha)
+	}
+	return mensaje
+}
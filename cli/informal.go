@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"finmex/api"
+	"finmex/calc"
+)
+
+// PrestamoInformal representa dinero prestado entre personas, por cobrar o por pagar
+type PrestamoInformal api.PrestamoInformal
+
+// FilaInformal es un préstamo en la salida de informal listar con --output
+type FilaInformal struct {
+	PrestamoInformal
+	Sentido     string  `json:"sentido"`
+	Total       float64 `json:"total"`
+	Pendiente   float64 `json:"pendiente"`
+	ProximoPago string  `json:"proximo_pago,omitempty"`
+	Vencidos    int     `json:"vencidos"`
+}
+
+// PagoInformal es un pago del calendario acordado con el estado que guarda a una fecha
+type PagoInformal struct {
+	Numero  int
+	Fecha   time.Time
+	Monto   float64 // Lo que falta cubrir de este pago
+	Vencido bool
+}
+
+// Total regresa lo que se acordó pagar en total, incluyendo el interés
+func (p PrestamoInformal) Total() float64 {
+	return p.Monto + p.Interes
+}
+
+// Pago regresa el monto acordado de cada pago
+func (p PrestamoInformal) Pago() float64 {
+	if p.Pagos <= 0 {
+		return p.Total()
+	}
+	return p.Total() / float64(p.Pagos)
+}
+
+// Pendiente regresa lo que falta por pagar del préstamo
+func (p PrestamoInformal) Pendiente() float64 {
+	return math.Max(0, p.Total()-p.Abonado)
+}
+
+// Sentido describe el préstamo desde el punto de vista del usuario
+func (p PrestamoInformal) Sentido() string {
+	if p.PorCobrar {
+		return "Por cobrar"
+	}
+	return "Por pagar"
+}
+
+// FechasPago regresa las fechas del calendario acordado; el primer pago cae en PrimerPago
+func (p PrestamoInformal) FechasPago() ([]time.Time, error) {
+	primero, err := time.Parse("2006-01-02", p.PrimerPago)
+	if err != nil {
+		return nil, fmt.Errorf("Fecha del primer pago inválida para '%s': %v", p.Persona, err)
+	}
+	pagos := p.Pagos
+	if pagos < 1 {
+		pagos = 1
+	}
+	fechas := []time.Time{primero}
+	return append(fechas, calc.CalendarioPagos(primero, p.frecuencia(), pagos-1)...), nil
+}
+
+// PagosPendientes regresa los pagos del calendario que los abonos aún no cubren, marcando
+// como vencidos los que tenían fecha antes del día dado. Los abonos cubren los pagos en orden.
+func (p PrestamoInformal) PagosPendientes(hoy time.Time) ([]PagoInformal, error) {
+	fechas, err := p.FechasPago()
+	if err != nil {
+		return nil, err
+	}
+
+	var pendientes []PagoInformal
+	cubierto := p.Abonado
+	pago := p.Pago()
+	for i, fecha := range fechas {
+		falta := pago
+		if cubierto > 0 {
+			aplicado := math.Min(cubierto, pago)
+			falta -= aplicado
+			cubierto -= aplicado
+		}
+		if falta < 0.005 {
+			continue
+		}
+		pendientes = append(pendientes, PagoInformal{Numero: i + 1, Fecha: fecha, Monto: falta, Vencido: fecha.Before(hoy)})
+	}
+	return pendientes, nil
+}
+
+// frecuencia regresa la frecuencia acordada, mensual si no se capturó
+func (p PrestamoInformal) frecuencia() Frecuencia {
+	if p.Frecuencia == "" {
+		return FrecuenciaMensual
+	}
+	return p.Frecuencia
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"finmex/calc"
+)
+
+// Insight es una observación accionable sobre los productos registrados
+type Insight struct {
+	Mensaje string
+	Impacto float64 // Pesos al año que se ganan o ahorran si se actúa
+}
+
+// GastoAnualPorTarjeta suma los cargos de los últimos doce meses por tarjeta, con la clave
+// del nombre normalizada
+func GastoAnualPorTarjeta(hoy time.Time) (map[string]float64, error) {
+	filtro := FiltroMovimientos{Desde: hoy.AddDate(-1, 0, 0).Format("2006-01-02")}
+	gasto := map[string]float64{}
+	err := RecorrerMovimientosFiltrados(filtro, func(m Movimiento) error {
+		if m.Monto > 0 && m.Tarjeta != "" {
+			gasto[normalizarClave(m.Tarjeta)] += m.Monto
+		}
+		return nil
+	})
+	return gasto, err
+}
+
+// GenerarInsights recorre los productos registrados y regresa las observaciones ordenadas
+// de mayor a menor impacto en pesos
+func GenerarInsights(tarjetas Tarjetas, catalogo Catalogo, gastoAnual map[string]float64) []Insight {
+	var insights []Insight
+	agregar := func(impacto float64, formato string, args ...interface{}) {
+		if impacto > 0.5 {
+			insights = append(insights, Insight{Mensaje: fmt.Sprintf(formato, args...), Impacto: impacto})
+		}
+	}
+
+	// Anualidades que no se recuperan con cashback
+	perdida := 0.0
+	var tarjetasPerdida []string
+	for _, t := range tarjetas.Credito {
+		gastoMensual := gastoAnual[normalizarClave(t.Nombre)] / 12
+		recuperado := cashbackAnualGeneral(t, gastoMensual)
+		if anualidad := t.AnualidadEfectiva(gastoMensual); anualidad > recuperado {
+			perdida += anualidad - recuperado
+			tarjetasPerdida = append(tarjetasPerdida, t.Nombre)
+		}
+	}
+	agregar(perdida, "Pagas $%.2f/año de anualidades que no recuperas en cashback (%s)",
+		perdida, strings.Join(tarjetasPerdida, ", "))
+
+	mejorTasa, mejorCuenta := MejorTasaDebitoNeta(tarjetas)
+	ahorro := 0.0
+	for _, t := range tarjetas.Debito {
+		ahorro += SaldoEnPesos(t)
+	}
+
+	// Deuda que cuesta más de lo que rinde el ahorro
+	for _, t := range tarjetas.Credito {
+		if t.Saldo <= 0 {
+			continue
+		}
+		tasa := calc.TasaConIVA(t.TasaInteres)
+		if mejorCuenta == "" || mejorTasa <= 0 {
+			agregar(t.Saldo*tasa, "Tu deuda de $%.2f en %s te cuesta $%.2f/año en intereses con IVA",
+				t.Saldo, t.Nombre, t.Saldo*tasa)
+			continue
+		}
+		liquidable := t.Saldo
+		if ahorro < liquidable {
+			liquidable = ahorro
+		}
+		agregar(liquidable*(tasa-mejorTasa),
+			"Tu deuda en %s te cuesta %.1fx lo que rinde tu %s; liquidar $%.2f con tu ahorro te ahorraría $%.2f/año",
+			t.Nombre, tasa/mejorTasa, mejorCuenta, liquidable, liquidable*(tasa-mejorTasa))
+		ahorro -= liquidable
+	}
+
+	// Saldo en cuentas que rinden menos que la mejor registrada
+	for _, t := range tarjetas.Debito {
+		if t.Saldo <= 0 || t.Nombre == mejorCuenta {
+			continue
+		}
+		neta := TasaNetaISR(t.TasaPonderada(t.Saldo))
+		saldo := SaldoEnPesos(t)
+		agregar(saldo*(mejorTasa-neta), "Tienes $%.2f en %s al %.2f%% neto; en %s ganarías $%.2f/año más",
+			saldo, t.Nombre, neta*100, mejorCuenta, saldo*(mejorTasa-neta))
+	}
+
+	// Monederos que no rinden
+	hoy := time.Now()
+	for _, m := range tarjetas.Monederos {
+		if !m.Vigente(hoy) || mejorCuenta == "" {
+			continue
+		}
+		neta := TasaNetaISR(m.TasaRendimiento)
+		agregar(m.ValorPesos()*(mejorTasa-neta), "Tu monedero %s guarda $%.2f que en %s generarían $%.2f/año más",
+			m.Nombre, m.ValorPesos(), mejorCuenta, m.ValorPesos()*(mejorTasa-neta))
+	}
+
+	// Tarjetas con deuda y CAT por encima del promedio de su segmento
+	for _, t := range tarjetas.Credito {
+		posicion := PosicionCredito(t, catalogo)
+		if t.Saldo <= 0 || posicion.Muestra == 0 || t.CAT <= posicion.Promedio {
+			continue
+		}
+		agregar(t.Saldo*(t.CAT-posicion.Promedio), "El CAT de %s (%.2f%%) está arriba del promedio %s (%.2f%%); con tu deuda eso son $%.2f/año de más",
+			t.Nombre, t.CAT*100, posicion.Segmento.Nombre(), posicion.Promedio*100, t.Saldo*(t.CAT-posicion.Promedio))
+	}
+
+	sort.SliceStable(insights, func(i, j int) bool {
+		return insights[i].Impacto > insights[j].Impacto
+	})
+	return insights
+}
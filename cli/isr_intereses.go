@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"finmex/calc"
+
+	"github.com/urfave/cli/v2"
+)
+
+// isrIntereses es el régimen de ISR con el que se calculan los rendimientos; se configura
+// con las opciones globales
+var isrIntereses = calc.ISRIntereses{
+	Regimen:      calc.RegimenRetencion,
+	AñoFiscal:    time.Now().Year(),
+	TasaMarginal: calc.TASA_MARGINAL_ISR,
+}
+
+// flagsISR son las opciones globales del ISR sobre intereses
+func flagsISR() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "regimen-isr", Value: calc.RegimenRetencion, Usage: "ISR sobre intereses: retencion (provisional sobre el capital) o interes-real (definitivo, acumulable)", EnvVars: []string{"FINMEX_REGIMEN_ISR"}},
+		&cli.IntFlag{Name: "anio-fiscal", Value: time.Now().Year(), Usage: "Año fiscal cuya tasa de retención de la LIF se aplica", EnvVars: []string{"FINMEX_ANIO_FISCAL"}},
+		conLimites(&cli.Float64Flag{Name: "tasa-marginal", Value: calc.TASA_MARGINAL_ISR, Usage: "Tasa marginal de ISR con la que se acumula el interés real (decimal)", EnvVars: []string{"FINMEX_TASA_MARGINAL"}}, limitesTasaMarginal),
+	}
+}
+
+// configurarISR aplica las opciones globales del ISR
+func configurarISR(regimen string, año int, tasaMarginal float64) error {
+	if err := calc.ValidarRegimenISR(regimen); err != nil {
+		return err
+	}
+	if año < 1900 || año > 2200 {
+		return fmt.Errorf("Año fiscal inválido: %d", año)
+	}
+	if tasaMarginal < 0 || tasaMarginal > 0.35 {
+		return fmt.Errorf("La tasa marginal de ISR debe estar entre 0 y 0.35, no %.4f", tasaMarginal)
+	}
+	isrIntereses = calc.ISRIntereses{Regimen: regimen, AñoFiscal: año, TasaMarginal: tasaMarginal}
+	return nil
+}
+
+// ISRVigente regresa el régimen de ISR sobre intereses elegido
+func ISRVigente() calc.ISRIntereses {
+	if escenarioActivo != nil && escenarioActivo.RetencionISR != nil {
+		isr := isrIntereses
+		isr.Retencion = *escenarioActivo.RetencionISR
+		return isr
+	}
+	return isrIntereses
+}
+
+// TasaNetaISR regresa la tasa de rendimiento después de ISR con el régimen elegido. La
+// inflación solo se consulta cuando el impuesto es sobre el interés real.
+func TasaNetaISR(tasa float64) float64 {
+	return ISRVigente().TasaNeta(tasa, inflacionISR())
+}
+
+// TasaBrutaISR es la tasa antes de ISR que deja la tasa neta dada
+func TasaBrutaISR(neta float64) float64 {
+	return ISRVigente().TasaBruta(neta, inflacionISR())
+}
+
+func inflacionISR() float64 {
+	if isrIntereses.Regimen != calc.RegimenInteresReal {
+		return 0
+	}
+	return InflacionVigente()
+}
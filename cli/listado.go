@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tipos de producto que muestra el listado unificado
+const (
+	TipoListadoTodo    = "todo"
+	TipoListadoDebito  = "debito"
+	TipoListadoCredito = "credito"
+)
+
+// Campos por los que se puede ordenar el listado unificado
+var ordenesListado = []string{"nombre", "banco", "tasa", "anualidad", "saldo"}
+
+// ProductoListado es una tarjeta de débito o de crédito en el listado unificado. La tasa es
+// el rendimiento de las cuentas de débito y el interés de las tarjetas de crédito.
+type ProductoListado struct {
+	Tipo      string   `json:"tipo"`
+	Nombre    string   `json:"nombre"`
+	Banco     string   `json:"banco"`
+	Tasa      float64  `json:"tasa"`
+	CAT       float64  `json:"cat,omitempty"` // Solo crédito
+	Anualidad float64  `json:"anualidad"`
+	Saldo     float64  `json:"saldo"`            // Saldo de la cuenta en pesos o deuda de la tarjeta
+	Limite    float64  `json:"limite,omitempty"` // Solo crédito
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// CriteriosListado filtra y ordena el listado unificado. Los límites en cero no filtran.
+type CriteriosListado struct {
+	Tipo         string
+	Banco        string
+	TasaMinima   float64
+	TasaMaxima   float64
+	AnualidadMax *float64 // nil no filtra; cero deja solo los productos sin anualidad
+	Filtro       FiltroTarjetas
+	OrdenarPor   string
+	Descendente  bool
+}
+
+// ValidarCriteriosListado revisa el tipo y el campo de orden
+func ValidarCriteriosListado(c CriteriosListado) error {
+	switch c.Tipo {
+	case TipoListadoTodo, TipoListadoDebito, TipoListadoCredito:
+	default:
+		return errDatosInvalidos(fmt.Sprintf("Tipo inválido '%s' (usa todo, debito o credito)", c.Tipo),
+			fmt.Sprintf("Invalid type '%s' (use todo, debito or credito)", c.Tipo))
+	}
+	if c.OrdenarPor != "" && !contieneClave(ordenesListado, c.OrdenarPor) {
+		return errDatosInvalidos(fmt.Sprintf("No se puede ordenar por '%s' (usa %s)", c.OrdenarPor, strings.Join(ordenesListado, ", ")),
+			fmt.Sprintf("Cannot sort by '%s' (use %s)", c.OrdenarPor, strings.Join(ordenesListado, ", ")))
+	}
+	if c.TasaMaxima > 0 && c.TasaMaxima < c.TasaMinima {
+		return errDatosInvalidos("La tasa máxima es menor a la mínima", "The maximum rate is lower than the minimum")
+	}
+	return nil
+}
+
+// ListarProductos junta las tarjetas de débito y de crédito que cumplen los criterios, en el
+// orden pedido. Sin orden quedan primero las de débito y cada tipo en el orden registrado.
+func ListarProductos(tarjetas Tarjetas, c CriteriosListado) []ProductoListado {
+	var productos []ProductoListado
+	if c.Tipo != TipoListadoCredito {
+		for _, t := range tarjetas.Debito {
+			productos = append(productos, ProductoListado{
+				Tipo: TipoListadoDebito, Nombre: t.Nombre, Banco: t.Banco, Tasa: t.TasaPonderada(t.Saldo),
+				Anualidad: t.ComisionAnual * tipoCambioMoneda(MonedaCuenta(t)), Saldo: SaldoEnPesos(t), Tags: t.Tags,
+			})
+		}
+	}
+	if c.Tipo != TipoListadoDebito {
+		for _, t := range tarjetas.Credito {
+			productos = append(productos, ProductoListado{
+				Tipo: TipoListadoCredito, Nombre: t.Nombre, Banco: t.Banco, Tasa: t.TasaInteres, CAT: t.CAT,
+				Anualidad: t.ComisionAnual, Saldo: t.Saldo, Limite: t.LimiteCredito, Tags: t.Tags,
+			})
+		}
+	}
+
+	filtrados := []ProductoListado{}
+	for _, p := range productos {
+		if c.incluye(p) {
+			filtrados = append(filtrados, p)
+		}
+	}
+	if c.OrdenarPor != "" {
+		sort.SliceStable(filtrados, func(i, j int) bool {
+			if c.Descendente {
+				return menorListado(filtrados[j], filtrados[i], c.OrdenarPor)
+			}
+			return menorListado(filtrados[i], filtrados[j], c.OrdenarPor)
+		})
+	}
+	return filtrados
+}
+
+func (c CriteriosListado) incluye(p ProductoListado) bool {
+	if c.Banco != "" && normalizarClave(p.Banco) != normalizarClave(c.Banco) {
+		return false
+	}
+	if p.Tasa < c.TasaMinima || (c.TasaMaxima > 0 && p.Tasa > c.TasaMaxima) {
+		return false
+	}
+	if c.AnualidadMax != nil && p.Anualidad > *c.AnualidadMax {
+		return false
+	}
+	return c.Filtro.Incluye(p.Nombre, p.Tags)
+}
+
+func menorListado(a, b ProductoListado, campo string) bool {
+	switch normalizarClave(campo) {
+	case "banco":
+		return normalizarClave(a.Banco) < normalizarClave(b.Banco)
+	case "tasa":
+		return a.Tasa < b.Tasa
+	case "anualidad":
+		return a.Anualidad < b.Anualidad
+	case "saldo":
+		return a.Saldo < b.Saldo
+	}
+	return normalizarClave(a.Nombre) < normalizarClave(b.Nombre)
+}
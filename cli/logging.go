@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// registro es el logger de diagnóstico. Escribe en stderr o en un archivo, nunca en la
+// salida normal del usuario; por omisión solo muestra advertencias y errores.
+var registro = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+// archivoLog es el archivo abierto con --log-file, si lo hay
+var archivoLog *os.File
+
+// nivelesLog relaciona los nombres aceptados por --log-level con los niveles de slog
+var nivelesLog = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// ParsearNivelLog convierte el nombre de un nivel de log
+func ParsearNivelLog(nombre string) (slog.Level, error) {
+	nivel, ok := nivelesLog[strings.ToLower(strings.TrimSpace(nombre))]
+	if !ok {
+		return slog.LevelWarn, fmt.Errorf("Nivel de log inválido '%s' (usa debug, info, warn o error)", nombre)
+	}
+	return nivel, nil
+}
+
+// configurarLog prepara el logger con el nivel indicado. Si se da un archivo, los registros
+// se agregan al final de ese archivo en lugar de ir a stderr.
+func configurarLog(nivel, archivo string) error {
+	n, err := ParsearNivelLog(nivel)
+	if err != nil {
+		return err
+	}
+
+	var salida io.Writer = os.Stderr
+	if archivo != "" {
+		f, err := os.OpenFile(archivo, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("Error al abrir archivo de log: %v", err)
+		}
+		archivoLog = f
+		salida = f
+	}
+
+	registro = slog.New(slog.NewTextHandler(salida, &slog.HandlerOptions{Level: n}))
+	return nil
+}
+
+// cerrarLog cierra el archivo de log, si se abrió uno
+func cerrarLog() {
+	if archivoLog != nil {
+		archivoLog.Close()
+		archivoLog = nil
+	}
+}
+
+// flagsLog son las opciones globales de diagnóstico
+func flagsLog() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "log-level", Value: "warn", Usage: "Nivel de detalle del log: debug, info, warn o error", EnvVars: []string{"FINMEX_LOG_LEVEL"}},
+		&cli.StringFlag{Name: "log-file", Usage: "Archivo donde escribir el log en lugar de stderr", EnvVars: []string{"FINMEX_LOG_FILE"}},
+	}
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SegmentoMercado es el grupo de productos del catálogo contra el que se compara un
+// producto registrado; vacío significa todo el mercado
+type SegmentoMercado string
+
+// Nombre regresa la etiqueta legible del segmento
+func (s SegmentoMercado) Nombre() string {
+	if s == "" {
+		return "mercado"
+	}
+	return string(s)
+}
+
+// PosicionMercado ubica un producto frente a los productos comparables del catálogo
+type PosicionMercado struct {
+	Segmento  SegmentoMercado
+	Muestra   int     // Productos del catálogo considerados
+	Promedio  float64 // CAT promedio (crédito) o tasa promedio (débito)
+	Percentil float64 // Porcentaje del mercado que el producto supera, de 0 a 100
+}
+
+// Descripcion resume la posición en una línea corta, p. ej. "P80 de oro (prom. 78.50%)"
+func (p PosicionMercado) Descripcion() string {
+	if p.Muestra == 0 {
+		return "sin datos"
+	}
+	return fmt.Sprintf("P%.0f de %s (prom. %.2f%%)", p.Percentil, p.Segmento.Nombre(), p.Promedio*100)
+}
+
+// productoSegmento identifica un producto del catálogo y su segmento
+type productoSegmento struct {
+	Banco, Nombre, Segmento string
+}
+
+// segmentoProducto busca el segmento del producto en el catálogo por banco y nombre. Si no
+// aparece, usa el segmento que se mencione en el nombre (p. ej. "Oro"), luego el segmento
+// común a todos los productos del mismo banco y, al final, todo el mercado.
+func segmentoProducto(nombre, banco string, catalogo []productoSegmento) SegmentoMercado {
+	for _, p := range catalogo {
+		if normalizarClave(p.Banco+" "+p.Nombre) == normalizarClave(banco+" "+nombre) {
+			return SegmentoMercado(p.Segmento)
+		}
+	}
+
+	for _, palabra := range palabrasClave(quitarAcentos(nombre)) {
+		for _, p := range catalogo {
+			if palabra == p.Segmento {
+				return SegmentoMercado(p.Segmento)
+			}
+		}
+	}
+
+	delBanco := ""
+	for _, p := range catalogo {
+		if normalizarClave(p.Banco) != normalizarClave(banco) {
+			continue
+		}
+		if delBanco != "" && delBanco != p.Segmento {
+			return ""
+		}
+		delBanco = p.Segmento
+	}
+	return SegmentoMercado(delBanco)
+}
+
+// quitarAcentos cambia las vocales acentuadas por su versión sin acento para comparar
+// nombres como "Clásica" contra el segmento "clasica"
+func quitarAcentos(texto string) string {
+	return strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u",
+		"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U").Replace(texto)
+}
+
+// posicion calcula promedio y percentil de un valor contra la muestra; mejorSiMenor indica
+// que valores más bajos son mejores (CAT). Los empates cuentan como medio producto superado.
+func posicion(valor float64, muestra []float64, mejorSiMenor bool) (float64, float64) {
+	if len(muestra) == 0 {
+		return 0, 0
+	}
+	suma, superados := 0.0, 0.0
+	for _, m := range muestra {
+		suma += m
+		switch {
+		case m == valor:
+			superados += 0.5
+		case (m > valor) == mejorSiMenor:
+			superados++
+		}
+	}
+	n := float64(len(muestra))
+	return suma / n, superados / n * 100
+}
+
+// PosicionCredito compara el CAT de una tarjeta contra las tarjetas de su segmento en el catálogo
+func PosicionCredito(t TarjetaCredito, catalogo Catalogo) PosicionMercado {
+	var productos []productoSegmento
+	for _, c := range catalogo.Credito {
+		productos = append(productos, productoSegmento{c.Banco, c.Nombre, c.Segmento})
+	}
+
+	segmento := segmentoProducto(t.Nombre, t.Banco, productos)
+	var muestra []float64
+	for _, c := range catalogo.Credito {
+		if segmento == "" || SegmentoMercado(c.Segmento) == segmento {
+			muestra = append(muestra, c.CAT)
+		}
+	}
+
+	promedio, percentil := posicion(t.CAT, muestra, true)
+	return PosicionMercado{Segmento: segmento, Muestra: len(muestra), Promedio: promedio, Percentil: percentil}
+}
+
+// PosicionDebito compara la tasa de rendimiento de una cuenta contra las cuentas de su
+// segmento en el catálogo. Las tasas escalonadas se comparan con el saldo de la cuenta. El
+// catálogo es de cuentas en pesos, así que las cuentas en dólares quedan sin datos.
+func PosicionDebito(t TarjetaDebito, catalogo Catalogo) PosicionMercado {
+	if enDolares(t) {
+		return PosicionMercado{}
+	}
+	var productos []productoSegmento
+	for _, d := range catalogo.Debito {
+		productos = append(productos, productoSegmento{d.Banco, d.Nombre, d.Segmento})
+	}
+
+	segmento := segmentoProducto(t.Nombre, t.Banco, productos)
+	var muestra []float64
+	for _, d := range catalogo.Debito {
+		if segmento == "" || SegmentoMercado(d.Segmento) == segmento {
+			muestra = append(muestra, d.TasaPonderada(t.Saldo))
+		}
+	}
+
+	promedio, percentil := posicion(t.TasaPonderada(t.Saldo), muestra, false)
+	return PosicionMercado{Segmento: segmento, Muestra: len(muestra), Promedio: promedio, Percentil: percentil}
+}
+
+// MAX_ALTERNATIVAS_MERCADO son las tarjetas del catálogo que se sugieren por cada registrada
+const MAX_ALTERNATIVAS_MERCADO = 3
+
+// AlternativaMercado es una tarjeta del catálogo que cuesta menos al año que una registrada
+type AlternativaMercado struct {
+	Nombre        string  `json:"nombre"`
+	Banco         string  `json:"banco"`
+	Segmento      string  `json:"segmento"`
+	CAT           float64 `json:"cat"`
+	ComisionAnual float64 `json:"comision_anual"`
+	CostoAnual    float64 `json:"costo_anual"`
+	Ahorro        float64 `json:"ahorro"` // Al año frente a la tarjeta registrada
+}
+
+// ComparacionMercado ubica una tarjeta registrada frente a las del catálogo
+type ComparacionMercado struct {
+	Tarjeta       string               `json:"tarjeta"`
+	Banco         string               `json:"banco"`
+	CAT           float64              `json:"cat"`
+	ComisionAnual float64              `json:"comision_anual"`
+	Deuda         float64              `json:"deuda"`
+	CostoAnual    float64              `json:"costo_anual"`
+	Segmento      string               `json:"segmento"`
+	Percentil     float64              `json:"percentil"` // Porcentaje del segmento con mayor CAT
+	Alternativas  []AlternativaMercado `json:"alternativas"`
+}
+
+// costoAnualMercado es lo que cuesta la tarjeta en un año manteniendo la deuda: los
+// intereses y la anualidad, los dos con IVA. Es una cuenta simple para ordenar tarjetas de
+// tasas muy distintas, no una tabla de amortización.
+func costoAnualMercado(t TarjetaCredito, deuda float64) float64 {
+	return (deuda*t.TasaInteres + t.ComisionAnual) * (1 + IVA)
+}
+
+// CompararConMercado busca en el segmento de la tarjeta las del catálogo que costarían menos
+// al año con la misma deuda, de la más barata a la más cara, sin contar la misma tarjeta
+func CompararConMercado(t TarjetaCredito, deuda float64, catalogo Catalogo) ComparacionMercado {
+	posicion := PosicionCredito(t, catalogo)
+	c := ComparacionMercado{
+		Tarjeta:       t.Nombre,
+		Banco:         t.Banco,
+		CAT:           t.CAT,
+		ComisionAnual: t.ComisionAnual,
+		Deuda:         deuda,
+		CostoAnual:    costoAnualMercado(t, deuda),
+		Segmento:      posicion.Segmento.Nombre(),
+		Percentil:     posicion.Percentil,
+	}
+	for _, m := range catalogo.Credito {
+		if posicion.Segmento != "" && SegmentoMercado(m.Segmento) != posicion.Segmento {
+			continue
+		}
+		if normalizarClave(m.Banco+" "+m.Nombre) == normalizarClave(t.Banco+" "+t.Nombre) {
+			continue
+		}
+		costo := costoAnualMercado(m.TarjetaCredito, deuda)
+		if ahorro := c.CostoAnual - costo; ahorro > 0.005 {
+			c.Alternativas = append(c.Alternativas, AlternativaMercado{
+				Nombre: m.Nombre, Banco: m.Banco, Segmento: m.Segmento, CAT: m.CAT,
+				ComisionAnual: m.ComisionAnual, CostoAnual: costo, Ahorro: ahorro,
+			})
+		}
+	}
+	sort.SliceStable(c.Alternativas, func(i, j int) bool { return c.Alternativas[i].CostoAnual < c.Alternativas[j].CostoAnual })
+	if len(c.Alternativas) > MAX_ALTERNATIVAS_MERCADO {
+		c.Alternativas = c.Alternativas[:MAX_ALTERNATIVAS_MERCADO]
+	}
+	return c
+}
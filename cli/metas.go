@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"finmex/api"
+	"finmex/calc"
+)
+
+// Meta es un objetivo de ahorro con fecha límite y los aportes reales hechos hacia él
+type Meta api.Meta
+
+// EstadoMeta compara el avance real de una meta contra su plan a una fecha
+type EstadoMeta struct {
+	MesesTranscurridos  int
+	MesesRestantes      int
+	AportacionPlaneada  float64 // Aporte mensual calculado al definir la meta
+	SaldoPlaneado       float64 // Lo que se tendría siguiendo el plan
+	SaldoReal           float64 // Aportes reales con su rendimiento
+	AportacionNecesaria float64 // Aporte mensual que hace falta desde hoy para llegar
+	Avance              float64 // Porcentaje del objetivo alcanzado
+}
+
+// Atrasada indica si el saldo real va por debajo del plan
+func (e EstadoMeta) Atrasada() bool {
+	return e.SaldoReal < e.SaldoPlaneado-0.005
+}
+
+// fechas regresa las fechas de inicio y objetivo de la meta
+func (m Meta) fechas() (time.Time, time.Time, error) {
+	inicio, err := time.Parse("2006-01-02", m.FechaInicio)
+	if err != nil {
+		return inicio, inicio, fmt.Errorf("Fecha de inicio inválida en la meta '%s': %v", m.Nombre, err)
+	}
+	objetivo, err := time.Parse("2006-01-02", m.FechaObjetivo)
+	if err != nil {
+		return inicio, objetivo, fmt.Errorf("Fecha objetivo inválida en la meta '%s': %v", m.Nombre, err)
+	}
+	if !objetivo.After(inicio) {
+		return inicio, objetivo, fmt.Errorf("La fecha objetivo de '%s' debe ser posterior a la de inicio", m.Nombre)
+	}
+	return inicio, objetivo, nil
+}
+
+// mesesEntre regresa los meses completos entre dos fechas, sin negativos
+func mesesEntre(desde, hasta time.Time) int {
+	meses := (hasta.Year()-desde.Year())*12 + int(hasta.Month()) - int(desde.Month())
+	if hasta.Day() < desde.Day() {
+		meses--
+	}
+	if meses < 0 {
+		return 0
+	}
+	return meses
+}
+
+// aportacionParaValorFuturo regresa el aporte mensual que acumula el monto en n meses
+func aportacionParaValorFuturo(monto, tasaAnual float64, meses int) float64 {
+	if monto <= 0 {
+		return 0
+	}
+	if meses <= 0 {
+		return monto
+	}
+	return monto / calc.ValorFuturoAportaciones(1, tasaAnual, meses)
+}
+
+// Estado calcula el avance real de la meta contra el plan a la fecha dada
+func (m Meta) Estado(hoy time.Time) (EstadoMeta, error) {
+	inicio, objetivo, err := m.fechas()
+	if err != nil {
+		return EstadoMeta{}, err
+	}
+
+	e := EstadoMeta{
+		MesesTranscurridos: mesesEntre(inicio, hoy),
+		MesesRestantes:     mesesEntre(hoy, objetivo),
+	}
+	total := mesesEntre(inicio, objetivo)
+	if e.MesesTranscurridos > total {
+		e.MesesTranscurridos = total
+	}
+
+	e.AportacionPlaneada = aportacionParaValorFuturo(m.Objetivo, m.TasaRendimiento, total)
+	e.SaldoPlaneado = calc.ValorFuturoAportaciones(e.AportacionPlaneada, m.TasaRendimiento, e.MesesTranscurridos)
+
+	for _, a := range m.Aportes {
+		fecha, err := time.Parse("2006-01-02", a.Fecha)
+		if err != nil {
+			return e, fmt.Errorf("Fecha de aporte inválida en la meta '%s': %v", m.Nombre, err)
+		}
+		if fecha.After(hoy) {
+			continue
+		}
+		e.SaldoReal += a.Monto * math.Pow(1+m.TasaRendimiento/12, float64(mesesEntre(fecha, hoy)))
+	}
+
+	falta := m.Objetivo - e.SaldoReal*math.Pow(1+m.TasaRendimiento/12, float64(e.MesesRestantes))
+	e.AportacionNecesaria = aportacionParaValorFuturo(falta, m.TasaRendimiento, e.MesesRestantes)
+	if m.Objetivo > 0 {
+		e.Avance = e.SaldoReal / m.Objetivo
+	}
+	return e, nil
+}
+
+// Tipos de producto donde se puede guardar el ahorro de una meta
+const (
+	ProductoDebito = "debito"
+	ProductoSofipo = "sofipo"
+	ProductoCetes  = "cetes"
+	ProductoPagare = "pagare"
+)
+
+// ProductoAhorro es un producto registrado donde se guarda el ahorro de una meta
+type ProductoAhorro struct {
+	Nombre   string
+	Tipo     string  // debito, sofipo, cetes o pagare
+	TasaReal float64 // Rendimiento anual después de ISR y comisiones, menos la inflación
+}
+
+// BuscarProductoAhorro busca por nombre una cuenta de débito, de SOFIPO, inversión en
+// cetesdirecto o pagaré registrado y calcula su rendimiento real con el saldo dado
+func BuscarProductoAhorro(tarjetas Tarjetas, nombre string, saldo float64) (ProductoAhorro, bool) {
+	clave := normalizarClave(nombre)
+	inflacion := InflacionVigente()
+	for _, t := range tarjetas.Debito {
+		if normalizarClave(t.Nombre) == clave {
+			_, pct, _ := CalcularRendimientoReal(t, saldo)
+			return ProductoAhorro{t.Nombre, ProductoDebito, pct / 100}, true
+		}
+	}
+	for _, s := range tarjetas.Sofipos {
+		if normalizarClave(s.Nombre) == clave {
+			s.Saldo = saldo
+			return ProductoAhorro{s.Nombre, ProductoSofipo, s.Rendimiento(inflacion).TasaReal}, true
+		}
+	}
+	for _, inv := range tarjetas.Cetes {
+		if normalizarClave(inv.Nombre) == clave {
+			inv.Monto = saldo
+			return ProductoAhorro{inv.Nombre, ProductoCetes, inv.Rendimiento(inflacion).TasaReal}, true
+		}
+	}
+	for _, p := range tarjetas.Pagares {
+		if normalizarClave(p.Nombre) == clave {
+			p.Monto = saldo
+			return ProductoAhorro{p.Nombre, ProductoPagare, p.Rendimiento(inflacion).TasaReal}, true
+		}
+	}
+	return ProductoAhorro{}, false
+}
+
+// ActualizarTasa toma el rendimiento real vigente del producto de la meta, evaluado con la
+// mitad del objetivo como saldo promedio del plan. Sin producto, o si ya no está registrado,
+// se conserva la tasa guardada.
+func (m *Meta) ActualizarTasa(tarjetas Tarjetas) bool {
+	if m.Producto == "" {
+		return false
+	}
+	producto, ok := BuscarProductoAhorro(tarjetas, m.Producto, m.Objetivo/2)
+	if !ok {
+		return false
+	}
+	m.Producto, m.TipoProducto, m.TasaRendimiento = producto.Nombre, producto.Tipo, producto.TasaReal
+	return true
+}
+
+// MAX_MESES_CURVA_META limita la curva de avance de una meta que tardaría décadas
+const MAX_MESES_CURVA_META = 120
+
+// PuntoMeta es un mes de la curva de avance proyectada de una meta
+type PuntoMeta struct {
+	Mes    string  `json:"mes"` // AAAA-MM
+	Aporte float64 `json:"aporte"`
+	Saldo  float64 `json:"saldo"`
+	Avance float64 `json:"avance"` // Fracción del objetivo
+}
+
+// Proyeccion regresa la curva de avance desde hoy aportando cada mes el monto dado, hasta
+// la fecha objetivo o, si se llega después, hasta cumplir la meta
+func (m Meta) Proyeccion(e EstadoMeta, aporte float64, hoy time.Time) []PuntoMeta {
+	meses := e.MesesRestantes
+	if n := calc.MesesParaObjetivo(e.SaldoReal, aporte, m.TasaRendimiento, m.Objetivo); n > meses {
+		meses = n
+	}
+	if meses > MAX_MESES_CURVA_META {
+		meses = MAX_MESES_CURVA_META
+	}
+	var puntos []PuntoMeta
+	saldo := e.SaldoReal
+	for i := 1; i <= meses; i++ {
+		saldo = saldo*(1+m.TasaRendimiento/12) + aporte
+		p := PuntoMeta{Mes: calc.SumarMeses(hoy, i).Format("2006-01"), Aporte: aporte, Saldo: saldo}
+		if m.Objetivo > 0 {
+			p.Avance = saldo / m.Objetivo
+		}
+		puntos = append(puntos, p)
+	}
+	return puntos
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+
+	"finmex/calc"
+)
+
+// CostoMicrocredito resume lo caro que sale un microcrédito
+type CostoMicrocredito struct {
+	CostoTotal        float64 `json:"costo_total"` // Comisiones más intereses
+	TotalPagado       float64 `json:"total_pagado"`
+	PagoPorPeriodo    float64 `json:"pago_por_periodo"`
+	TasaPeriodo       float64 `json:"tasa_periodo"`        // Tasa interna por periodo de pago
+	TasaAnualSimple   float64 `json:"tasa_anual_simple"`   // Tasa anual equivalente sin capitalizar
+	TasaAnualEfectiva float64 `json:"tasa_anual_efectiva"` // Tasa anual equivalente si se renovara continuamente
+}
+
+// FilaMicrocredito es un microcrédito con su costo en la salida de microcredito listar con
+// --output
+type FilaMicrocredito struct {
+	Microcredito
+	CostoMicrocredito
+}
+
+// AnalisisMicrocredito es el resultado de microcredito analizar con --output
+type AnalisisMicrocredito struct {
+	FilaMicrocredito
+	Tarjetas []CostoMicrocreditoTarjeta `json:"tarjetas"`
+}
+
+// CostoMicrocreditoTarjeta es lo que costaría el mismo monto y plazo con una tarjeta
+type CostoMicrocreditoTarjeta struct {
+	Tarjeta       string  `json:"tarjeta"`
+	Banco         string  `json:"banco"`
+	TasaInteres   float64 `json:"tasa_interes"`
+	CostoEstimado float64 `json:"costo_estimado"`
+	Ahorro        float64 `json:"ahorro"`
+}
+
+// AnalizarMicrocredito calcula la tasa anual equivalente real de un microcrédito a partir
+// de sus flujos: se recibe el monto y se paga el total en pagos iguales a lo largo del plazo
+func AnalizarMicrocredito(m Microcredito) (CostoMicrocredito, error) {
+	if m.Monto <= 0 || m.PlazoDias <= 0 {
+		return CostoMicrocredito{}, fmt.Errorf("El monto y el plazo del microcrédito deben ser mayores a cero")
+	}
+
+	pagos := m.Pagos
+	if pagos < 1 {
+		pagos = 1
+	}
+
+	costo := CostoMicrocredito{
+		CostoTotal:  m.Comision + m.Interes,
+		TotalPagado: m.Monto + m.Comision + m.Interes,
+	}
+	costo.PagoPorPeriodo = costo.TotalPagado / float64(pagos)
+
+	flujos := []float64{m.Monto}
+	for i := 0; i < pagos; i++ {
+		flujos = append(flujos, -costo.PagoPorPeriodo)
+	}
+
+	tasa, err := calc.TIR(flujos)
+	if err != nil {
+		return costo, err
+	}
+
+	diasPeriodo := float64(m.PlazoDias) / float64(pagos)
+	periodosAño := 365 / diasPeriodo
+
+	costo.TasaPeriodo = tasa
+	costo.TasaAnualSimple = tasa * periodosAño
+	costo.TasaAnualEfectiva = calc.TasaAnualEfectiva(tasa, periodosAño)
+	return costo, nil
+}
+
+// CostoEnTarjeta estima lo que costaría financiar el mismo monto y plazo con una tarjeta
+// de crédito. Con varios pagos el saldo baja, así que se usa el saldo promedio del plazo.
+func CostoEnTarjeta(m Microcredito, tarjeta TarjetaCredito) float64 {
+	pagos := m.Pagos
+	if pagos < 1 {
+		pagos = 1
+	}
+	saldoPromedio := m.Monto * float64(pagos+1) / float64(2*pagos)
+	return saldoPromedio * calc.TasaConIVA(tarjeta.TasaInteres) * float64(m.PlazoDias) / 365
+}
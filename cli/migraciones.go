@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MAX_ERRORES_CARGA son los errores de validación que se muestran al rechazar un archivo
+const MAX_ERRORES_CARGA = 3
+
+// migracionDatos lleva el documento de tarjetas de la versión Desde a la siguiente. Trabaja
+// sobre el JSON decodificado y no sobre Tarjetas, porque los campos que cambian de nombre o de
+// forma ya no existen en los tipos de Go.
+type migracionDatos struct {
+	Desde       int
+	Descripcion string
+	Aplicar     func(documento map[string]interface{})
+}
+
+// migracionesDatos son las migraciones en orden de versión. Al cambiar el formato se agrega
+// una aquí y se incrementa VERSION_ESQUEMA.
+var migracionesDatos = []migracionDatos{
+	// Los archivos anteriores al versionado no tienen "version" y se leen como la 1
+	{1, "las cuentas de débito con una sola tasa pasan a tramos", migrarTramosV1},
+}
+
+// migrarTramosV1 convierte tasa_rendimiento de cada cuenta de débito en un tramo sin tope,
+// como calc.TarjetaDebito.MigrarTramos
+func migrarTramosV1(documento map[string]interface{}) {
+	cuentas, _ := documento["debito"].([]interface{})
+	for _, elemento := range cuentas {
+		cuenta, ok := elemento.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tasa, ok := cuenta["tasa_rendimiento"]
+		if !ok {
+			continue
+		}
+		delete(cuenta, "tasa_rendimiento")
+		if tramos, _ := cuenta["tramos"].([]interface{}); len(tramos) > 0 {
+			continue
+		}
+		if numero, ok := tasa.(json.Number); ok && numero.String() != "0" {
+			cuenta["tramos"] = []interface{}{map[string]interface{}{"monto_hasta": json.Number("0"), "tasa": numero}}
+		}
+	}
+}
+
+// versionDocumento lee la versión del documento; los archivos sin versión son la 1
+func versionDocumento(documento map[string]interface{}) (int, error) {
+	valor, ok := documento["version"]
+	if !ok {
+		return 1, nil
+	}
+	numero, ok := valor.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("version debe ser un número entero, no %v", valor)
+	}
+	version, err := strconv.Atoi(numero.String())
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("version debe ser un entero mayor a cero, no %s", numero)
+	}
+	return version, nil
+}
+
+// MigrarDocumento aplica al documento las migraciones de su versión en adelante y lo deja en
+// VERSION_ESQUEMA. Regresa la versión con que venía; rechaza los documentos de una versión
+// más nueva que esta.
+func MigrarDocumento(archivo string, documento map[string]interface{}) (int, error) {
+	version, err := versionDocumento(documento)
+	if err != nil {
+		return 0, errArchivoCorrupto(archivo, err)
+	}
+	if version > VERSION_ESQUEMA {
+		return version, errVersionFutura(archivo, version)
+	}
+	for _, m := range migracionesDatos {
+		if m.Desde >= version {
+			registro.Info("migrando datos", "archivo", archivo, "desde", m.Desde, "migracion", m.Descripcion)
+			m.Aplicar(documento)
+		}
+	}
+	documento["version"] = json.Number(strconv.Itoa(VERSION_ESQUEMA))
+	return version, nil
+}
+
+// MigracionTarjetas es lo que cambió al llevar un documento de tarjetas a la versión actual
+type MigracionTarjetas struct {
+	Desde int
+	// Completados son los campos requeridos que no existían cuando se escribió el archivo y se
+	// agregaron con su valor por omisión, sin índices: debito[].comision_inactividad
+	Completados []string
+}
+
+// migrarTarjetas lleva el documento de tarjetas a VERSION_ESQUEMA. Si venía de una versión
+// anterior también completa los campos que se agregaron al formato después, para que el
+// documento pase la validación y se pueda avisar cuáles quedaron en su valor por omisión.
+func migrarTarjetas(archivo string, documento map[string]interface{}, esquema *Esquema) (MigracionTarjetas, error) {
+	version, err := MigrarDocumento(archivo, documento)
+	m := MigracionTarjetas{Desde: version}
+	if err != nil || version == VERSION_ESQUEMA {
+		return m, err
+	}
+	var completados []string
+	esquema.completarRequeridos(documento, "", &completados)
+	vistos := map[string]bool{}
+	for _, ruta := range completados {
+		ruta = indicesRuta.ReplaceAllString(ruta, "[]")
+		if !vistos[ruta] {
+			vistos[ruta] = true
+			m.Completados = append(m.Completados, ruta)
+		}
+	}
+	return m, nil
+}
+
+// indicesRuta son los índices de lista en una ruta de validación
+var indicesRuta = regexp.MustCompile(`\[\d+\]`)
+
+// completarRequeridos agrega con su valor por omisión los campos requeridos que faltan en el
+// valor y regresa sus rutas
+func (e *Esquema) completarRequeridos(valor interface{}, ruta string, completados *[]string) {
+	switch v := valor.(type) {
+	case []interface{}:
+		if e.Elementos == nil {
+			return
+		}
+		for i, elemento := range v {
+			e.Elementos.completarRequeridos(elemento, fmt.Sprintf("%s[%d]", ruta, i), completados)
+		}
+	case map[string]interface{}:
+		for _, requerido := range e.Requeridos {
+			if _, ok := v[requerido]; !ok {
+				v[requerido] = e.Propiedades[requerido].valorPorOmision()
+				*completados = append(*completados, unirRuta(ruta, requerido))
+			}
+		}
+		claves := make([]string, 0, len(v))
+		for clave := range v {
+			claves = append(claves, clave)
+		}
+		sort.Strings(claves)
+		for _, clave := range claves {
+			if propiedad, ok := e.Propiedades[clave]; ok {
+				propiedad.completarRequeridos(v[clave], unirRuta(ruta, clave), completados)
+			} else if adicional, ok := e.PropiedadesAdicionales.(*Esquema); ok {
+				adicional.completarRequeridos(v[clave], unirRuta(ruta, clave), completados)
+			}
+		}
+	}
+}
+
+// valorPorOmision es el valor cero del tipo como lo escribe encoding/json
+func (e *Esquema) valorPorOmision() interface{} {
+	switch e.Tipo {
+	case "string":
+		return ""
+	case "boolean":
+		return false
+	case "integer", "number":
+		return json.Number("0")
+	case "object":
+		objeto := map[string]interface{}{}
+		var anidados []string
+		e.completarRequeridos(objeto, "", &anidados)
+		return objeto
+	}
+	return nil
+}
+
+// decodificarTarjetas migra el documento guardado a la versión actual, lo valida contra el
+// esquema y lo convierte en Tarjetas. Un campo desconocido o con otro tipo rechaza el archivo
+// en lugar de leerse como cero.
+func decodificarTarjetas(archivo string, data []byte) (Tarjetas, MigracionTarjetas, error) {
+	var tarjetas Tarjetas
+	var m MigracionTarjetas
+	valor, err := decodificarParaValidar(data)
+	if err != nil {
+		return tarjetas, m, errArchivoCorrupto(archivo, err)
+	}
+	documento, ok := valor.(map[string]interface{})
+	if !ok {
+		return tarjetas, m, errArchivoCorrupto(archivo, fmt.Errorf("se esperaba un objeto y se encontró %s", nombresTipo[tipoJSON(valor)]))
+	}
+	esquema, err := EsquemaArchivo("tarjetas")
+	if err != nil {
+		return tarjetas, m, err
+	}
+	if m, err = migrarTarjetas(archivo, documento, esquema); err != nil {
+		return tarjetas, m, err
+	}
+	if errores := esquema.Validar(documento); len(errores) > 0 {
+		return tarjetas, m, errArchivoCorrupto(archivo, resumenErroresValidacion(errores))
+	}
+
+	migrado, err := json.Marshal(documento)
+	if err != nil {
+		return tarjetas, m, err
+	}
+	if err := json.Unmarshal(migrado, &tarjetas); err != nil {
+		return tarjetas, m, errArchivoCorrupto(archivo, err)
+	}
+	if errores := validarPlazos(tarjetas); len(errores) > 0 {
+		return tarjetas, m, errArchivoCorrupto(archivo, resumenErroresValidacion(errores))
+	}
+	return tarjetas, m, nil
+}
+
+// validarPlazos revisa los números de pagos que el esquema no puede restringir: una compra
+// a plazos o un préstamo sin pagos no tiene calendario ni pago por periodo
+func validarPlazos(tarjetas Tarjetas) []ErrorValidacion {
+	var errores []ErrorValidacion
+	for i, b := range tarjetas.BNPL {
+		if b.Pagos < 1 {
+			errores = append(errores, ErrorValidacion{Ruta: fmt.Sprintf("bnpl[%d].pagos", i), Mensaje: fmt.Sprintf("debe ser al menos 1, no %d", b.Pagos)})
+		}
+	}
+	for i, p := range tarjetas.Informales {
+		if p.Pagos < 1 {
+			errores = append(errores, ErrorValidacion{Ruta: fmt.Sprintf("informales[%d].pagos", i), Mensaje: fmt.Sprintf("debe ser al menos 1, no %d", p.Pagos)})
+		}
+	}
+	return errores
+}
+
+// resumenErroresValidacion junta los primeros errores de validación en uno
+func resumenErroresValidacion(errores []ErrorValidacion) error {
+	var textos []string
+	for i, e := range errores {
+		if i == MAX_ERRORES_CARGA {
+			textos = append(textos, fmt.Sprintf("y %d más", len(errores)-i))
+			break
+		}
+		textos = append(textos, e.String())
+	}
+	return fmt.Errorf("%s", strings.Join(textos, "; "))
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// tarjetasV1 es un archivo anterior al versionado: sin version, la tasa de débito en
+// tasa_rendimiento y sin los campos que se agregaron después
+const tarjetasV1 = `{
+  "debito": [
+    {"nombre": "Nu Cuenta", "banco": "Nu", "tasa_rendimiento": 0.12, "saldo_minimo": 0, "comision_anual": 0},
+    {"nombre": "Hey", "banco": "Hey Banco", "tasa_rendimiento": 0.1, "saldo_minimo": 0, "comision_anual": 0}
+  ],
+  "credito": [
+    {"nombre": "Oro", "banco": "BBVA", "tasa_interes": 0.4, "cat": 0.55, "comision_anual": 600, "limite_credito": 30000, "beneficios_cashback": 0, "meses_sin_intereses": true}
+  ]
+}`
+
+func TestDecodificarTarjetasSinVersion(t *testing.T) {
+	tarjetas, m, err := decodificarTarjetas("tarjetas.json", []byte(tarjetasV1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Desde != 1 {
+		t.Errorf("versión de origen %d, se esperaba 1", m.Desde)
+	}
+	if len(tarjetas.Debito) != 2 {
+		t.Fatalf("cuentas de débito %+v", tarjetas.Debito)
+	}
+	for i, tasa := range []float64{0.12, 0.1} {
+		d := tarjetas.Debito[i]
+		if d.TasaRendimiento != 0 || len(d.Tramos) != 1 || d.Tramos[0].Tasa != tasa || d.Tramos[0].Hasta != 0 {
+			t.Errorf("%s: tasa_rendimiento %g, tramos %+v; se esperaba un tramo sin tope al %g", d.Nombre, d.TasaRendimiento, d.Tramos, tasa)
+		}
+	}
+	if c := tarjetas.Credito[0]; c.TasaInteres != 0.4 || c.LimiteCredito != 30000 || !c.MesesSinIntereses {
+		t.Errorf("tarjeta de crédito %+v", c)
+	}
+
+	// El AVISO lista cada campo completado una vez y sin índices, aunque falte en varias cuentas
+	if len(m.Completados) == 0 {
+		t.Fatal("un archivo sin versión debe completar los campos que se agregaron después")
+	}
+	vistos := map[string]bool{}
+	for _, ruta := range m.Completados {
+		if strings.ContainsAny(ruta, "0123456789") || vistos[ruta] {
+			t.Errorf("ruta repetida o con índice en %v", m.Completados)
+		}
+		vistos[ruta] = true
+	}
+	if !vistos["debito[].comision_inactividad"] {
+		t.Errorf("falta debito[].comision_inactividad en %v", m.Completados)
+	}
+}
+
+func TestMigrarDocumentoConservaTramos(t *testing.T) {
+	documento := decodificarDocumento(t, `{"debito": [{"nombre": "Klar", "tasa_rendimiento": 0.15,
+		"tramos": [{"monto_hasta": 25000, "tasa": 0.15}, {"monto_hasta": 0, "tasa": 0.08}]}]}`)
+	desde, err := MigrarDocumento("tarjetas.json", documento)
+	if err != nil || desde != 1 {
+		t.Fatalf("desde %d, %v", desde, err)
+	}
+	cuenta := documento["debito"].([]interface{})[0].(map[string]interface{})
+	if _, ok := cuenta["tasa_rendimiento"]; ok {
+		t.Error("tasa_rendimiento debe quitarse aunque ya haya tramos")
+	}
+	esperado := []interface{}{
+		map[string]interface{}{"monto_hasta": json.Number("25000"), "tasa": json.Number("0.15")},
+		map[string]interface{}{"monto_hasta": json.Number("0"), "tasa": json.Number("0.08")},
+	}
+	if !reflect.DeepEqual(cuenta["tramos"], esperado) {
+		t.Errorf("los tramos existentes cambiaron: %v", cuenta["tramos"])
+	}
+	if documento["version"] != json.Number(strconv.Itoa(VERSION_ESQUEMA)) {
+		t.Errorf("version %v, se esperaba %d", documento["version"], VERSION_ESQUEMA)
+	}
+}
+
+func TestMigrarDocumentoVersionActualNoCambia(t *testing.T) {
+	texto := `{"version": 2, "debito": [{"nombre": "Nu", "tasa_rendimiento": 0.12}]}`
+	documento := decodificarDocumento(t, texto)
+	if desde, err := MigrarDocumento("tarjetas.json", documento); err != nil || desde != VERSION_ESQUEMA {
+		t.Fatalf("desde %d, %v", desde, err)
+	}
+	if !reflect.DeepEqual(documento, decodificarDocumento(t, texto)) {
+		t.Errorf("un documento en la versión actual no debe migrarse: %v", documento)
+	}
+}
+
+func TestMigrarDocumentoVersionFutura(t *testing.T) {
+	documento := decodificarDocumento(t, `{"version": 99, "debito": []}`)
+	_, err := MigrarDocumento("tarjetas.json", documento)
+	if !errors.Is(err, ErrVersionFutura) {
+		t.Errorf("se esperaba VERSION_FUTURA, salió %v", err)
+	}
+	var e *ErrorFinmex
+	if errors.As(err, &e) && codigoSalida(e) != SalidaVersionFutura {
+		t.Errorf("código de salida %d", codigoSalida(e))
+	}
+}
+
+func TestMigrarDocumentoVersionInvalida(t *testing.T) {
+	for _, version := range []string{`1.5`, `"2"`, `0`, `-1`} {
+		documento := decodificarDocumento(t, `{"version": `+version+`}`)
+		if _, err := MigrarDocumento("tarjetas.json", documento); !errors.Is(err, ErrArchivoCorrupto) {
+			t.Errorf("version %s: se esperaba ARCHIVO_CORRUPTO, salió %v", version, err)
+		}
+	}
+}
+
+func TestDecodificarTarjetasRechazaCamposDesconocidos(t *testing.T) {
+	texto := `{"version": 2, "debito": [], "credito": [{"nombre": "Oro", "banco": "BBVA", "tasa_intres": 0.4}]}`
+	if _, _, err := decodificarTarjetas("tarjetas.json", []byte(texto)); !errors.Is(err, ErrArchivoCorrupto) {
+		t.Errorf("un campo mal escrito debe rechazar el archivo, salió %v", err)
+	}
+}
+
+func TestDecodificarTarjetasRechazaPlazosSinPagos(t *testing.T) {
+	texto := `{"version": 2, "debito": [], "credito": [],
+  "bnpl": [{"nombre": "Tenis", "proveedor": "Aplazo", "monto": 1800, "pagos": 0, "frecuencia": "quincenal", "comision": 0, "tasa_interes": 0, "primer_pago_hoy": true, "fecha_compra": "2026-01-10"}],
+  "informales": [{"persona": "Ana", "por_cobrar": true, "monto": 500, "pagos": -2, "frecuencia": "mensual", "primer_pago": "2026-02-01", "abonado": 0}]}`
+	_, _, err := decodificarTarjetas("tarjetas.json", []byte(texto))
+	if !errors.Is(err, ErrArchivoCorrupto) || !strings.Contains(err.Error(), "bnpl[0].pagos") || !strings.Contains(err.Error(), "informales[0].pagos") {
+		t.Errorf("un plazo sin pagos debe rechazar el archivo, salió %v", err)
+	}
+}
+
+func decodificarDocumento(t *testing.T, texto string) map[string]interface{} {
+	t.Helper()
+	valor, err := decodificarParaValidar([]byte(texto))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return valor.(map[string]interface{})
+}
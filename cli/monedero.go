@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"finmex/api"
+)
+
+// Tipos de monedero electrónico
+const (
+	MonederoSaldo  = "saldo"  // Saldo en apps como Mercado Pago
+	MonederoRegalo = "regalo" // Tarjetas de regalo y saldo en tiendas
+	MonederoPuntos = "puntos" // Puntos convertibles a pesos
+)
+
+// Monedero representa saldo líquido fuera del banco: apps de pago, tarjetas de regalo o puntos
+type Monedero api.Monedero
+
+// FilaMonedero es un monedero en la salida de monedero listar con --output
+type FilaMonedero struct {
+	Monedero
+	ValorPesos           float64 `json:"valor_pesos"`
+	RendimientoAnualNeto float64 `json:"rendimiento_anual_neto"`
+	Vigente              bool    `json:"vigente"`
+}
+
+// ValidarTipoMonedero verifica que el tipo de monedero sea uno de los soportados
+func ValidarTipoMonedero(tipo string) error {
+	switch tipo {
+	case MonederoSaldo, MonederoRegalo, MonederoPuntos:
+		return nil
+	}
+	return fmt.Errorf("Tipo de monedero no soportado: %s (usa saldo, regalo o puntos)", tipo)
+}
+
+// ValorPesos regresa el saldo del monedero expresado en pesos
+func (m Monedero) ValorPesos() float64 {
+	if m.Tipo == MonederoPuntos {
+		return m.Saldo * m.ValorPunto
+	}
+	return m.Saldo
+}
+
+// RendimientoAnualNeto regresa lo que rinde el monedero en un año después de ISR
+func (m Monedero) RendimientoAnualNeto() float64 {
+	return m.ValorPesos() * TasaNetaISR(m.TasaRendimiento)
+}
+
+// Vigente indica si el saldo sigue disponible en la fecha dada
+func (m Monedero) Vigente(fecha time.Time) bool {
+	if m.Vencimiento == "" {
+		return true
+	}
+	vence, err := time.Parse("2006-01-02", m.Vencimiento)
+	if err != nil {
+		return true
+	}
+	return !fecha.After(vence)
+}
+
+// LiquidezMonederos suma el saldo en pesos de los monederos vigentes en la fecha dada
+func LiquidezMonederos(monederos []Monedero, fecha time.Time) float64 {
+	total := 0.0
+	for _, m := range monederos {
+		if m.Vigente(fecha) {
+			total += m.ValorPesos()
+		}
+	}
+	return total
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"finmex/storage"
+)
+
+// ARCHIVO_MOVIMIENTOS guarda un movimiento JSON por línea para poder leerlo en streaming
+const ARCHIVO_MOVIMIENTOS = "movimientos.ndjson"
+
+// Tamaño máximo de una línea del archivo de movimientos
+const maxLineaMovimiento = 1024 * 1024
+
+// errDetener permite a un recorrido terminar antes de llegar al final del archivo
+var errDetener = errors.New("detener recorrido")
+
+// Movimiento representa un cargo o abono registrado en una tarjeta
+type Movimiento struct {
+	Fecha       string  `json:"fecha"` // Formato AAAA-MM-DD
+	Descripcion string  `json:"descripcion"`
+	Monto       float64 `json:"monto"` // Positivo para cargos, negativo para abonos
+	Categoria   string  `json:"categoria,omitempty"`
+	Tarjeta     string  `json:"tarjeta,omitempty"`
+}
+
+// FiltroMovimientos indica qué movimientos se incluyen en un recorrido
+type FiltroMovimientos struct {
+	Texto     string // Se busca dentro de la descripción, sin distinguir mayúsculas
+	Categoria string
+	Tarjeta   string
+	Desde     string // Fechas AAAA-MM-DD, incluidas
+	Hasta     string
+}
+
+// Coincide indica si el movimiento cumple con todos los criterios del filtro
+func (f FiltroMovimientos) Coincide(m Movimiento) bool {
+	if f.Texto != "" && !strings.Contains(strings.ToLower(m.Descripcion), strings.ToLower(f.Texto)) {
+		return false
+	}
+	if f.Categoria != "" && !strings.EqualFold(m.Categoria, f.Categoria) {
+		return false
+	}
+	if f.Tarjeta != "" && !strings.EqualFold(m.Tarjeta, f.Tarjeta) {
+		return false
+	}
+	if (f.Desde != "" && m.Fecha < f.Desde) || (f.Hasta != "" && m.Fecha > f.Hasta) {
+		return false
+	}
+	return true
+}
+
+// RecorrerMovimientos lee el historial uno por uno y llama a fn con cada movimiento, sin
+// cargarlo todo a memoria. Si fn regresa errDetener el recorrido termina sin error.
+func RecorrerMovimientos(fn func(Movimiento) error) error {
+	return RecorrerMovimientosFiltrados(FiltroMovimientos{}, fn)
+}
+
+// RecorrerMovimientosFiltrados es como RecorrerMovimientos pero solo llama a fn con los
+// movimientos que cumplen el filtro. Si el almacén sabe filtrar, como SQLite con sus
+// índices, solo se leen los que ya cumplen.
+func RecorrerMovimientosFiltrados(filtro FiltroMovimientos, fn func(Movimiento) error) error {
+	origen, err := almacenDatos()
+	if err != nil {
+		return err
+	}
+
+	numero := 0
+	leer := func(r json.RawMessage) error {
+		numero++
+		var m Movimiento
+		if err := json.Unmarshal(r, &m); err != nil {
+			return errArchivoCorrupto(origen.Ruta(), fmt.Errorf("movimiento %d: %v", numero, err))
+		}
+		if !filtro.Coincide(m) {
+			return nil
+		}
+		return fn(m)
+	}
+	if buscador, ok := origen.(storage.Buscador); ok && filtro != (FiltroMovimientos{}) {
+		err = buscador.BuscarRegistros(storage.FiltroRegistros(filtro), leer)
+	} else {
+		err = origen.RecorrerRegistros(leer)
+	}
+	var formato *storage.ErrorFormato
+	if errors.Is(err, errDetener) {
+		return nil
+	} else if errors.As(err, &formato) {
+		return errArchivoCorrupto(formato.Archivo, formato.Causa)
+	}
+	return err
+}
+
+// PaginaMovimientos regresa los movimientos que cumplen el filtro en la página indicada
+// (empezando en 1). Deja de leer el archivo en cuanto la página está completa.
+func PaginaMovimientos(filtro FiltroMovimientos, pagina, porPagina int) ([]Movimiento, error) {
+	if pagina < 1 {
+		pagina = 1
+	}
+	if porPagina < 1 {
+		porPagina = 50
+	}
+
+	omitir := (pagina - 1) * porPagina
+	resultado := make([]Movimiento, 0, porPagina)
+
+	err := RecorrerMovimientosFiltrados(filtro, func(m Movimiento) error {
+		if omitir > 0 {
+			omitir--
+			return nil
+		}
+		resultado = append(resultado, m)
+		if len(resultado) == porPagina {
+			return errDetener
+		}
+		return nil
+	})
+	return resultado, err
+}
+
+// AgregarMovimientos escribe los movimientos al final del historial sin reescribir los
+// existentes. Si el daemon está activo, es él quien escribe.
+func AgregarMovimientos(movimientos []Movimiento) error {
+	if cliente, ok := conectarDaemon(); ok {
+		defer cliente.Close()
+		registro.Debug("agregando movimientos a través del daemon", "movimientos", len(movimientos))
+		return cliente.agregarMovimientos(movimientos)
+	}
+
+	return agregarMovimientosAlmacen(movimientos)
+}
+
+// agregarMovimientosAlmacen escribe los movimientos directamente al final del historial
+func agregarMovimientosAlmacen(movimientos []Movimiento) error {
+	destino, err := almacenDatos()
+	if err != nil {
+		return err
+	}
+	registros := make([]json.RawMessage, len(movimientos))
+	for i, m := range movimientos {
+		if registros[i], err = json.Marshal(m); err != nil {
+			return err
+		}
+	}
+	registro.Debug("agregando movimientos", "almacen", destino.Ruta(), "movimientos", len(movimientos))
+	return destino.AgregarRegistros(registros)
+}
+
+// TotalMovimientos suma los movimientos que cumplen el filtro dentro de un mes (AAAA-MM);
+// un mes vacío incluye todos
+func TotalMovimientos(filtro FiltroMovimientos, mes string) (float64, error) {
+	total := 0.0
+	err := RecorrerMovimientosFiltrados(filtro, func(m Movimiento) error {
+		if strings.HasPrefix(m.Fecha, mes) {
+			total += m.Monto
+		}
+		return nil
+	})
+	return total, err
+}
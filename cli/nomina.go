@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"math"
+
+	"finmex/calc"
+)
+
+// Días que cubre en promedio un anticipo de nómina (hasta la siguiente quincena)
+const DIAS_ANTICIPO_NOMINA = 15
+
+// UsoAnticipo describe cuántas veces al año y por cuánto se pide un anticipo de nómina
+type UsoAnticipo struct {
+	VecesAlAño int
+	Monto      float64
+}
+
+// ValorCuentaNomina resume lo que deja (o cuesta) una cuenta de nómina en un año
+type ValorCuentaNomina struct {
+	RendimientoNeto float64 // Rendimiento después de ISR
+	Comisiones      float64
+	CostoAnticipos  float64
+	Beneficios      float64
+	Total           float64 // Rendimiento + beneficios - comisiones - anticipos
+	SinAnticipo     bool    // Se necesitan anticipos pero la cuenta no los ofrece
+}
+
+// CalcularValorNomina calcula el beneficio anual neto de una cuenta de nómina para un
+// saldo promedio y un patrón de uso de anticipos
+func CalcularValorNomina(cuenta CuentaNomina, saldoPromedio float64, uso UsoAnticipo) ValorCuentaNomina {
+	valor := ValorCuentaNomina{
+		RendimientoNeto: saldoPromedio * TasaNetaISR(cuenta.TasaRendimiento),
+		Comisiones:      cuenta.ComisionMensual * 12,
+		Beneficios:      cuenta.BeneficioAnual,
+	}
+
+	if uso.VecesAlAño > 0 && uso.Monto > 0 {
+		if cuenta.AnticipoNomina {
+			valor.CostoAnticipos = float64(uso.VecesAlAño) * uso.Monto * cuenta.TasaAnticipo * DIAS_ANTICIPO_NOMINA / 365
+		} else {
+			valor.SinAnticipo = true
+		}
+	}
+
+	valor.Total = valor.RendimientoNeto + valor.Beneficios - valor.Comisiones - valor.CostoAnticipos
+	return valor
+}
+
+// Portabilidad compara la cuenta actual contra otra cuenta de nómina
+type Portabilidad struct {
+	Destino         CuentaNomina
+	Valor           ValorCuentaNomina
+	DiferenciaAnual float64 // Lo que se gana (positivo) o pierde al año frente a la cuenta actual
+	PrimerAño       float64 // Diferencia del primer año incluyendo la promoción por traspaso
+}
+
+// CompararPortabilidad calcula lo que se gana o pierde al portabilizar la nómina desde la
+// cuenta actual hacia cada una de las demás cuentas
+func CompararPortabilidad(actual CuentaNomina, opciones []CuentaNomina, saldoPromedio float64, uso UsoAnticipo) (ValorCuentaNomina, []Portabilidad) {
+	valorActual := CalcularValorNomina(actual, saldoPromedio, uso)
+
+	var resultado []Portabilidad
+	for _, cuenta := range opciones {
+		valor := CalcularValorNomina(cuenta, saldoPromedio, uso)
+		diferencia := valor.Total - valorActual.Total
+		resultado = append(resultado, Portabilidad{
+			Destino:         cuenta,
+			Valor:           valor,
+			DiferenciaAnual: diferencia,
+			PrimerAño:       diferencia + cuenta.PromocionTraspaso,
+		})
+	}
+	return valorActual, resultado
+}
+
+// CreditoNomina es un préstamo que se descuenta directamente de cada quincena
+type CreditoNomina struct {
+	Monto            float64
+	TasaAnual        float64
+	ComisionApertura float64 // Porcentaje del monto cobrado al disponer (decimal)
+	Quincenas        int
+}
+
+// PagoQuincenal calcula el descuento fijo por quincena del crédito de nómina
+func (c CreditoNomina) PagoQuincenal() float64 {
+	return calc.PagoFijo(c.Monto, c.TasaAnual/float64(FrecuenciaQuincenal.PeriodosPorAño()), c.Quincenas)
+}
+
+// CostoTotal regresa los intereses más la comisión por apertura del crédito de nómina
+func (c CreditoNomina) CostoTotal() float64 {
+	return c.PagoQuincenal()*float64(c.Quincenas) - c.Monto + c.Monto*c.ComisionApertura
+}
+
+// SaldoDespues regresa lo que se debe del crédito de nómina después de las quincenas pagadas
+func (c CreditoNomina) SaldoDespues(quincenas int) float64 {
+	if quincenas >= c.Quincenas {
+		return 0
+	}
+	tasa := c.TasaAnual / float64(FrecuenciaQuincenal.PeriodosPorAño())
+	return math.Max(0, calc.SaldoDespuesDePagos(c.Monto, tasa, c.PagoQuincenal(), quincenas))
+}
+
+// ComparacionNominaTarjeta resume el crédito de nómina contra cargar lo mismo a una tarjeta
+type ComparacionNominaTarjeta struct {
+	PagoNomina         float64
+	CostoNomina        float64
+	PagoTarjeta        float64
+	QuincenasTarjeta   int
+	CostoTarjeta       float64
+	SaldoNominaRiesgo  float64 // Saldo del crédito de nómina al perder el empleo
+	SaldoTarjetaRiesgo float64 // Saldo de la tarjeta al perder el empleo
+	MinimoTarjeta      float64 // Pago mínimo mensual de la tarjeta en ese momento
+}
+
+// CompararNominaTarjeta compara un crédito de nómina con pagar el mismo monto con una tarjeta
+// a pagos quincenales, incluyendo los saldos pendientes si se pierde el empleo en la quincena indicada
+func CompararNominaTarjeta(credito CreditoNomina, tarjeta TarjetaCredito, pagoTarjeta float64, quincenaDesempleo int) ComparacionNominaTarjeta {
+	pagoMinimo := calc.PagoMinimoPeriodo(tarjeta, credito.Monto, FrecuenciaQuincenal)
+	if pagoTarjeta < pagoMinimo {
+		pagoTarjeta = pagoMinimo
+	}
+
+	costoTarjeta, quincenas, _ := calc.CostoCreditoFrecuencia(tarjeta, credito.Monto, pagoTarjeta, FrecuenciaQuincenal)
+
+	comparacion := ComparacionNominaTarjeta{
+		PagoNomina:       credito.PagoQuincenal(),
+		CostoNomina:      credito.CostoTotal(),
+		PagoTarjeta:      pagoTarjeta,
+		QuincenasTarjeta: quincenas,
+		CostoTarjeta:     costoTarjeta,
+	}
+
+	if quincenaDesempleo > 0 {
+		tasaTarjeta := calc.TasaConIVA(tarjeta.TasaInteres) / float64(FrecuenciaQuincenal.PeriodosPorAño())
+		comparacion.SaldoNominaRiesgo = credito.SaldoDespues(quincenaDesempleo)
+		comparacion.SaldoTarjetaRiesgo = math.Max(0, calc.SaldoDespuesDePagos(credito.Monto, tasaTarjeta, pagoTarjeta, quincenaDesempleo))
+		comparacion.MinimoTarjeta = calc.PagoMinimoPeriodo(tarjeta, comparacion.SaldoTarjetaRiesgo, FrecuenciaMensual)
+	}
+	return comparacion
+}
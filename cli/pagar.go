@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"finmex/calc"
+)
+
+// CompraPago es una compra por hacer con tarjeta de crédito
+type CompraPago struct {
+	Monto           float64   `json:"monto"`
+	Categoria       string    `json:"categoria"`
+	MSI             int       `json:"msi"` // Meses en que se quiere pagar; 0 es de contado
+	Fecha           time.Time `json:"fecha"`
+	TasaOportunidad float64   `json:"tasa_oportunidad"` // Tasa neta que rinde el dinero mientras no se paga
+}
+
+// RazonPago es una regla del motor aplicada a una tarjeta: lo que suma o resta en pesos pagar
+// la compra con ella y por qué
+type RazonPago struct {
+	Regla   string  `json:"regla"`
+	Valor   float64 `json:"valor"`
+	Detalle string  `json:"detalle"`
+}
+
+// EvaluacionPago es lo que vale pagar la compra con una tarjeta según las reglas del motor
+type EvaluacionPago struct {
+	Tarjeta    string      `json:"tarjeta"`
+	Banco      string      `json:"banco"`
+	Disponible float64     `json:"disponible,omitempty"` // Crédito disponible; cero si no tiene límite registrado
+	Neto       float64     `json:"neto"`
+	Descartada bool        `json:"descartada"` // La compra no cabe en el crédito disponible
+	Razones    []RazonPago `json:"razones"`
+}
+
+// reglaPago evalúa un aspecto de pagar la compra con la tarjeta: su valor en pesos (positivo a
+// favor) y la explicación; false si no aplica a la tarjeta o a la compra
+type reglaPago struct {
+	nombre  string
+	evaluar func(t TarjetaCredito, compra CompraPago) (float64, string, bool)
+}
+
+// reglasPago son las reglas del motor, en el orden en que se muestran las razones
+var reglasPago = []reglaPago{
+	{"cashback", reglaPagoCashback},
+	{"msi", reglaPagoMSI},
+	{"corte", reglaPagoCorte},
+	{"revolvente", reglaPagoRevolvente},
+}
+
+// reglaPagoCashback valúa la bonificación de la compra con la tasa de su categoría, el tope
+// mensual y el valor de canje de los puntos
+func reglaPagoCashback(t TarjetaCredito, compra CompraPago) (float64, string, bool) {
+	m := calc.CashbackMensual(t, map[string]float64{compra.Categoria: compra.Monto})
+	if m.Valor <= 0 {
+		return 0, "", false
+	}
+	tasa := t.TasaCashback(compra.Categoria)
+	detalle := fmt.Sprintf("%.2f%% de cashback general", tasa*100)
+	if _, ok := t.Categorias[compra.Categoria]; ok {
+		detalle = fmt.Sprintf("%.2f%% de cashback en %s", tasa*100, compra.Categoria)
+	}
+	if m.Topado < m.Bruto {
+		detalle += fmt.Sprintf(", topado a $%.2f al mes", t.TopeCashback)
+	}
+	if t.FormaCashback == calc.CashbackPuntos {
+		detalle += fmt.Sprintf(", en puntos a $%.2f por peso", t.ValorCanje())
+	}
+	return m.Valor, detalle, true
+}
+
+// reglaPagoMSI compara pagar a plazos contra pagar de contado en valor presente: a meses sin
+// intereses si la tarjeta los ofrece, o diferida en pagos fijos con su tasa si no
+func reglaPagoMSI(t TarjetaCredito, compra CompraPago) (float64, string, bool) {
+	if compra.MSI <= 0 {
+		return 0, "", false
+	}
+	if t.MesesSinIntereses {
+		valor := compra.Monto - ValorPresenteMSI(compra.Monto, compra.MSI, compra.TasaOportunidad)
+		return valor, fmt.Sprintf("%d MSI de $%.2f mientras tu dinero sigue invertido", compra.MSI, compra.Monto/float64(compra.MSI)), true
+	}
+	pago := calc.PagoFijo(compra.Monto, calc.TasaConIVA(t.TasaInteres)/12, compra.MSI)
+	valor := compra.Monto - calc.ValorPresentePagos(pago, compra.MSI, compra.TasaOportunidad)
+	return valor, fmt.Sprintf("no ofrece MSI: diferir a %d meses al %.2f%% cuesta $%.2f de intereses", compra.MSI, t.TasaInteres*100, pago*float64(compra.MSI)-compra.Monto), true
+}
+
+// reglaPagoCorte valúa los días que el dinero sigue invertido entre la compra de contado y la
+// fecha límite de pago
+func reglaPagoCorte(t TarjetaCredito, compra CompraPago) (float64, string, bool) {
+	if compra.MSI > 0 || t.Saldo > 0 {
+		return 0, "", false
+	}
+	ciclo, ok := t.Ciclo(compra.Fecha)
+	if !ok {
+		return 0, "", false
+	}
+	dias := ciclo.DiasFinanciamiento(compra.Fecha)
+	valor := compra.Monto * compra.TasaOportunidad * float64(dias) / 365
+	return valor, fmt.Sprintf("corte el %s: %d días sin intereses hasta el %s",
+		ciclo.Corte.Format("2006-01-02"), dias, ciclo.LimitePago.Format("2006-01-02")), true
+}
+
+// reglaPagoRevolvente cobra un mes de intereses con IVA a la compra de contado en una tarjeta
+// con deuda revolvente: si no se paga el total, las compras nuevas no tienen periodo de gracia
+func reglaPagoRevolvente(t TarjetaCredito, compra CompraPago) (float64, string, bool) {
+	if compra.MSI > 0 || t.Saldo <= 0 {
+		return 0, "", false
+	}
+	costo := compra.Monto * t.TasaInteres / 12 * (1 + IVA)
+	return -costo, fmt.Sprintf("tiene $%.2f de deuda revolvente: la compra paga intereses desde el primer día", t.Saldo), true
+}
+
+// creditoDisponible es el límite menos la deuda revolvente y las mensualidades de MSI que
+// faltan por pagar
+func creditoDisponible(t TarjetaCredito, hoy time.Time) float64 {
+	disponible := t.LimiteCredito - t.Saldo
+	for _, p := range t.Planes {
+		n, err := p.NumeroMensualidad(hoy)
+		if err != nil {
+			continue
+		}
+		inicio, _ := time.Parse("2006-01", p.Inicio)
+		switch {
+		case n > 0:
+			disponible -= p.Mensualidad() * float64(p.Meses-n+1)
+		case hoy.Before(inicio):
+			disponible -= p.Monto
+		}
+	}
+	return disponible
+}
+
+// EvaluarPago aplica las reglas del motor a una tarjeta
+func EvaluarPago(t TarjetaCredito, compra CompraPago) EvaluacionPago {
+	e := EvaluacionPago{Tarjeta: t.Nombre, Banco: t.Banco}
+	if t.LimiteCredito > 0 {
+		e.Disponible = creditoDisponible(t, compra.Fecha)
+		e.Descartada = compra.Monto > e.Disponible
+	}
+	for _, r := range reglasPago {
+		valor, detalle, ok := r.evaluar(t, compra)
+		if !ok {
+			continue
+		}
+		e.Razones = append(e.Razones, RazonPago{Regla: r.nombre, Valor: valor, Detalle: detalle})
+		e.Neto += valor
+	}
+	return e
+}
+
+// EscogerTarjetaPago evalúa la compra con cada tarjeta de crédito y las ordena de mayor a
+// menor valor neto; las que no tienen crédito disponible para la compra van al final
+func EscogerTarjetaPago(tarjetas []TarjetaCredito, compra CompraPago) []EvaluacionPago {
+	var evaluaciones []EvaluacionPago
+	tasas := map[string]float64{}
+	for _, t := range tarjetas {
+		evaluaciones = append(evaluaciones, EvaluarPago(t, compra))
+		tasas[t.Nombre] = t.TasaInteres
+	}
+	sort.SliceStable(evaluaciones, func(i, j int) bool {
+		a, b := evaluaciones[i], evaluaciones[j]
+		if a.Descartada != b.Descartada {
+			return !a.Descartada
+		}
+		if math.Abs(a.Neto-b.Neto) >= 0.005 {
+			return a.Neto > b.Neto
+		}
+		return tasas[a.Tarjeta] < tasas[b.Tarjeta]
+	})
+	return evaluaciones
+}
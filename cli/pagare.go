@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"finmex/api"
+)
+
+// PlazosPagare son los plazos en días a los que los bancos ofrecen pagarés con rendimiento
+// liquidable al vencimiento
+var PlazosPagare = []int{28, 91, 182, 364}
+
+// limitesPenalizacion acepta 1 para perder todo el interés, que con limitesFraccion se
+// tomaría por un porcentaje mal capturado
+var limitesPenalizacion = LimitesNumero{Min: 0, Max: 1}
+
+// PagareBancario es un pagaré con rendimiento liquidable al vencimiento (PRLV) de un banco
+type PagareBancario api.PagareBancario
+
+// RendimientoPagare desglosa lo que deja un pagaré al vencimiento o al retirarlo antes
+type RendimientoPagare struct {
+	Dias       int     `json:"dias"`  // Días que el dinero estuvo invertido
+	Bruto      float64 `json:"bruto"` // Interés del plazo, después de la penalización
+	Retencion  float64 `json:"retencion"`
+	Neto       float64 `json:"neto"`
+	TasaNeta   float64 `json:"tasa_neta"` // Anualizada; compuesta si el pagaré se renueva
+	TasaReal   float64 `json:"tasa_real"`
+	MontoFinal float64 `json:"monto_final"`
+}
+
+// FilaPagare es un pagaré con su rendimiento en la salida de pagare listar y analizar con
+// --output
+type FilaPagare struct {
+	PagareBancario
+	RendimientoPagare
+	Inflacion float64            `json:"inflacion"`
+	Retiro    *RendimientoPagare `json:"retiro_anticipado,omitempty"`
+}
+
+// ValidarPlazoPagare revisa que el plazo sea uno de los que ofrecen los bancos
+func ValidarPlazoPagare(dias int) error {
+	for _, p := range PlazosPagare {
+		if dias == p {
+			return nil
+		}
+	}
+	return errDatosInvalidos(
+		fmt.Sprintf("Plazo inválido: %d días (usa %s)", dias, plazosPagareTexto()),
+		fmt.Sprintf("Invalid term: %d days (use %s)", dias, plazosPagareTexto()))
+}
+
+func plazosPagareTexto() string {
+	plazos := make([]string, len(PlazosPagare))
+	for i, p := range PlazosPagare {
+		plazos[i] = fmt.Sprint(p)
+	}
+	return strings.Join(plazos, ", ")
+}
+
+// Rendimiento calcula el interés al vencimiento igual que en los CETES: tasa de 360 días e
+// ISR proporcional a los días. Con renovación automática el capital y los intereses se
+// reinvierten a la misma tasa, y la tasa neta anual se compone por plazo.
+func (p PagareBancario) Rendimiento(inflacion float64) RendimientoPagare {
+	r := p.rendimientoDias(p.PlazoDias, 0, inflacion)
+	if p.Renovacion && r.Dias > 0 && p.Monto > 0 {
+		r.TasaNeta = math.Pow(1+r.Neto/p.Monto, 365/float64(r.Dias)) - 1
+		r.TasaReal = r.TasaNeta - inflacion
+	}
+	return r
+}
+
+// RetiroAnticipado calcula lo que deja el pagaré si se retira después de los días dados,
+// antes del vencimiento: el interés ganado hasta entonces menos la penalización. De ahí en
+// adelante se supone que el dinero queda sin invertir, por lo que la tasa es simple.
+func (p PagareBancario) RetiroAnticipado(dias int, inflacion float64) RendimientoPagare {
+	if dias >= p.PlazoDias {
+		return p.rendimientoDias(p.PlazoDias, 0, inflacion)
+	}
+	return p.rendimientoDias(dias, p.Penalizacion, inflacion)
+}
+
+func (p PagareBancario) rendimientoDias(dias int, penalizacion, inflacion float64) RendimientoPagare {
+	r := RendimientoPagare{Dias: dias, MontoFinal: p.Monto}
+	if p.Monto <= 0 || dias <= 0 {
+		return r
+	}
+	d := float64(dias)
+	tasa := p.Tasa * (1 - penalizacion)
+	r.Bruto = p.Monto * tasa * d / DIAS_AÑO_GUBERNAMENTAL
+	r.Retencion = p.Monto * ISRVigente().TasaImpuesto(tasa, inflacion) * d / 365
+	r.Neto = r.Bruto - r.Retencion
+	r.TasaNeta = r.Neto / p.Monto * 365 / d
+	r.TasaReal = r.TasaNeta - inflacion
+	r.MontoFinal = p.Monto + r.Neto
+	return r
+}
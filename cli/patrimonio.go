@@ -0,0 +1,92 @@
+package cli
+
+import "time"
+
+// PartidaPatrimonio es un activo o pasivo dentro del resumen patrimonial
+type PartidaPatrimonio struct {
+	Categoria string
+	Concepto  string
+	Monto     float64
+	Pasivo    bool
+	Liquido   bool // Activo disponible de inmediato para hacer pagos
+}
+
+// ResumenPatrimonial agrupa activos y pasivos de todos los productos registrados
+type ResumenPatrimonial struct {
+	Partidas []PartidaPatrimonio
+	Activos  float64
+	Pasivos  float64
+	Liquidez float64
+}
+
+// Patrimonio regresa el patrimonio neto: activos menos pasivos
+func (r ResumenPatrimonial) Patrimonio() float64 {
+	return r.Activos - r.Pasivos
+}
+
+func (r *ResumenPatrimonial) agregar(p PartidaPatrimonio) {
+	r.Partidas = append(r.Partidas, p)
+	if p.Pasivo {
+		r.Pasivos += p.Monto
+		return
+	}
+	r.Activos += p.Monto
+	if p.Liquido {
+		r.Liquidez += p.Monto
+	}
+}
+
+// CalcularResumenPatrimonial arma el resumen patrimonial a la fecha dada
+func CalcularResumenPatrimonial(tarjetas Tarjetas, fecha time.Time) (ResumenPatrimonial, error) {
+	var r ResumenPatrimonial
+
+	for _, t := range tarjetas.Debito {
+		if t.Saldo > 0 {
+			r.agregar(PartidaPatrimonio{Categoria: "Débito", Concepto: t.Nombre + " (" + t.Banco + ")", Monto: SaldoEnPesos(t), Liquido: true})
+		}
+	}
+
+	for _, t := range tarjetas.Credito {
+		if t.Saldo > 0 {
+			r.agregar(PartidaPatrimonio{Categoria: "Crédito", Concepto: t.Nombre + " (" + t.Banco + ")", Monto: t.Saldo, Pasivo: true})
+		}
+	}
+
+	for _, m := range tarjetas.Monederos {
+		if !m.Vigente(fecha) {
+			continue
+		}
+		r.agregar(PartidaPatrimonio{Categoria: "Monedero", Concepto: m.Nombre + " (" + m.Proveedor + ")", Monto: m.ValorPesos(), Liquido: true})
+	}
+
+	for _, c := range tarjetas.Cajas {
+		r.agregar(PartidaPatrimonio{Categoria: "Caja de ahorro", Concepto: c.Nombre + " (" + c.Entidad + ")", Monto: c.ParteSocial + c.Ahorro})
+		if c.SaldoPrestamo > 0 {
+			r.agregar(PartidaPatrimonio{Categoria: "Caja de ahorro", Concepto: "Préstamo " + c.Nombre, Monto: c.SaldoPrestamo, Pasivo: true})
+		}
+	}
+
+	for _, p := range tarjetas.Informales {
+		if pendiente := p.Pendiente(); pendiente > 0 {
+			r.agregar(PartidaPatrimonio{Categoria: "Préstamo informal", Concepto: p.Persona, Monto: pendiente, Pasivo: !p.PorCobrar})
+		}
+	}
+
+	for _, b := range tarjetas.BNPL {
+		fechas, err := b.FechasPago()
+		if err != nil {
+			return r, err
+		}
+		pendientes := 0
+		for _, f := range fechas {
+			if !f.Before(fecha) {
+				pendientes++
+			}
+		}
+		if pendientes > 0 {
+			r.agregar(PartidaPatrimonio{Categoria: "BNPL", Concepto: b.Nombre + " (" + b.Proveedor + ")", Monto: b.Pago() * float64(pendientes), Pasivo: true})
+		}
+	}
+
+	return r, nil
+}
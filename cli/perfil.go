@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// PERFIL_PRINCIPAL es el perfil que usa los archivos de datos del directorio actual, como
+// antes de que hubiera perfiles
+const PERFIL_PRINCIPAL = "principal"
+
+// DIRECTORIO_PERFILES guarda un subdirectorio con los datos de cada perfil nombrado
+const DIRECTORIO_PERFILES = "perfiles"
+
+// ARCHIVO_PERFIL_PREDETERMINADO guarda el nombre del perfil que se usa sin --perfil
+const ARCHIVO_PERFIL_PREDETERMINADO = "predeterminado"
+
+// perfilActivo es el perfil cuyos datos se leen y escriben en esta ejecución
+var perfilActivo = PERFIL_PRINCIPAL
+
+// directorioDatos es donde están los datos de todos los perfiles, según la ruta de datos de
+// config.yaml; vacío es el directorio actual
+var directorioDatos string
+
+var nombrePerfilValido = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// flagPerfil elige el perfil de datos
+func flagPerfil() cli.Flag {
+	return &cli.StringFlag{Name: "perfil", Usage: "Perfil cuyos datos se usan; por defecto el elegido con 'finmex perfil usar' o el de config.yaml", EnvVars: []string{"FINMEX_PERFIL"}}
+}
+
+// activarPerfil elige el perfil pedido o, si no se pidió ninguno, el predeterminado. Un
+// perfil nombrado debe existir.
+func activarPerfil(nombre string) error {
+	if nombre == "" {
+		nombre = PerfilPredeterminado()
+	}
+	nombre = strings.ToLower(strings.TrimSpace(nombre))
+	if nombre != PERFIL_PRINCIPAL && !existePerfil(nombre) {
+		return fmt.Errorf("El perfil '%s' no existe; créalo con 'finmex perfil crear %s'", nombre, nombre)
+	}
+	perfilActivo = nombre
+	registro.Debug("perfil activo", "perfil", perfilActivo)
+	return nil
+}
+
+// rutaDatos regresa dónde guarda el perfil activo un archivo de datos. El perfil principal
+// usa el directorio de datos; los demás, su subdirectorio en DIRECTORIO_PERFILES.
+func rutaDatos(archivo string) string {
+	if perfilActivo == PERFIL_PRINCIPAL {
+		return rutaComun(archivo)
+	}
+	return filepath.Join(directorioPerfil(perfilActivo), archivo)
+}
+
+// rutaComun regresa la ruta de un archivo que comparten todos los perfiles
+func rutaComun(archivo string) string {
+	return filepath.Join(directorioDatos, archivo)
+}
+
+func directorioPerfil(nombre string) string {
+	return filepath.Join(directorioDatos, DIRECTORIO_PERFILES, nombre)
+}
+
+func existePerfil(nombre string) bool {
+	info, err := os.Stat(directorioPerfil(nombre))
+	return err == nil && info.IsDir()
+}
+
+// ValidarNombrePerfil revisa que el nombre sirva como directorio en cualquier sistema
+func ValidarNombrePerfil(nombre string) error {
+	if !nombrePerfilValido.MatchString(nombre) {
+		return errDatosInvalidos(
+			fmt.Sprintf("Nombre de perfil inválido '%s': usa hasta 32 letras minúsculas, números, - o _", nombre),
+			fmt.Sprintf("Invalid profile name '%s': use up to 32 lowercase letters, digits, - or _", nombre))
+	}
+	return nil
+}
+
+// PerfilPredeterminado regresa el perfil que se usa sin --perfil: el de 'finmex perfil usar'
+// o, si no se eligió ninguno, el de config.yaml
+func PerfilPredeterminado() string {
+	data, err := os.ReadFile(rutaComun(filepath.Join(DIRECTORIO_PERFILES, ARCHIVO_PERFIL_PREDETERMINADO)))
+	if nombre := strings.TrimSpace(string(data)); err == nil && nombre != "" {
+		return nombre
+	}
+	if configuracion.Perfil != "" {
+		return configuracion.Perfil
+	}
+	return PERFIL_PRINCIPAL
+}
+
+// GuardarPerfilPredeterminado fija el perfil que se usa sin --perfil. El principal solo
+// necesita guardarse si config.yaml elige otro.
+func GuardarPerfilPredeterminado(nombre string) error {
+	archivo := rutaComun(filepath.Join(DIRECTORIO_PERFILES, ARCHIVO_PERFIL_PREDETERMINADO))
+	if nombre == PERFIL_PRINCIPAL && (configuracion.Perfil == "" || configuracion.Perfil == PERFIL_PRINCIPAL) {
+		err := os.Remove(archivo)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(archivo), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(archivo, []byte(nombre+"\n"), 0644)
+}
+
+// Perfiles regresa el perfil principal y los perfiles creados, en orden alfabético
+func Perfiles() ([]string, error) {
+	perfiles := []string{PERFIL_PRINCIPAL}
+	entradas, err := os.ReadDir(rutaComun(DIRECTORIO_PERFILES))
+	if os.IsNotExist(err) {
+		return perfiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var nombres []string
+	for _, e := range entradas {
+		if e.IsDir() && nombrePerfilValido.MatchString(e.Name()) {
+			nombres = append(nombres, e.Name())
+		}
+	}
+	sort.Strings(nombres)
+	return append(perfiles, nombres...), nil
+}
@@ -0,0 +1,7 @@
+package cli
+
+// Calculadoras que se compilan dentro de finmex. Para agregar un módulo publicado por
+// terceros, impórtalo en blanco aquí y vuelve a compilar; su función init lo registra.
+import (
+	_ "finmex/calculadora/tanda"
+)
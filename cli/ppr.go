@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"math"
+
+	"finmex/calc"
+)
+
+// Topes de deducción de aportaciones a planes personales de retiro (LISR art. 151 fr. V)
+const (
+	TOPE_PPR_INGRESO = 0.10 // Hasta 10% de los ingresos acumulables del año
+	TOPE_PPR_UMAS    = 5    // Sin exceder cinco UMAs anuales
+)
+
+// FilaPPR es un plan en la salida de ppr listar con --output
+type FilaPPR struct {
+	PlanRetiro
+	RendimientoNeto float64 `json:"rendimiento_neto"` // Rendimiento menos comisión
+}
+
+// AñoProyeccionPPR es el estado del plan al cierre de un año de la proyección
+type AñoProyeccionPPR struct {
+	Año        int
+	Aportacion float64
+	Deducible  float64
+	Devolucion float64 // ISR devuelto por deducir la aportación
+	Saldo      float64
+}
+
+// TopeDeduccionPPR regresa la aportación máxima deducible con el ingreso anual dado
+func TopeDeduccionPPR(ingresoAnual float64, año int) (float64, error) {
+	uma, err := calc.UMADiaria(año)
+	if err != nil {
+		return 0, err
+	}
+	return math.Min(ingresoAnual*TOPE_PPR_INGRESO, uma*365*TOPE_PPR_UMAS), nil
+}
+
+// DevolucionISR regresa el ISR que se deja de pagar al deducir el monto de la base gravable
+func DevolucionISR(ingresoAnual, deduccion float64) float64 {
+	return calc.ISRAnual(ingresoAnual) - calc.ISRAnual(math.Max(0, ingresoAnual-deduccion))
+}
+
+// ProyectarPPR proyecta el plan por los años indicados. Con reinvertir, la devolución de cada
+// año se aporta al plan al año siguiente además de la aportación regular.
+func ProyectarPPR(p PlanRetiro, ingresoAnual float64, años int, año int, reinvertir bool) ([]AñoProyeccionPPR, error) {
+	tope, err := TopeDeduccionPPR(ingresoAnual, año)
+	if err != nil {
+		return nil, err
+	}
+
+	var proyeccion []AñoProyeccionPPR
+	saldo := p.Saldo
+	extra := 0.0
+	for i := 1; i <= años; i++ {
+		r := AñoProyeccionPPR{Año: año + i - 1, Aportacion: p.AportacionAnual + extra}
+		r.Deducible = math.Min(r.Aportacion, tope)
+		r.Devolucion = DevolucionISR(ingresoAnual, r.Deducible)
+
+		saldo = (saldo + r.Aportacion) * (1 + p.TasaRendimiento - p.Comision)
+		r.Saldo = saldo
+		proyeccion = append(proyeccion, r)
+
+		extra = 0
+		if reinvertir {
+			extra = r.Devolucion
+		}
+	}
+	return proyeccion, nil
+}
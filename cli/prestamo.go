@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"finmex/calc"
+)
+
+// OpcionPrestamo es un lugar donde se puede pedir prestado un monto a cierto plazo
+type OpcionPrestamo struct {
+	Producto   string
+	Nombre     string
+	TasaAnual  float64
+	Pago       float64 // Pago mensual
+	CostoTotal float64 // Intereses, comisiones y anualidades netos de beneficios
+	Disponible bool
+	Nota       string
+}
+
+// OpcionesPrestamo compara el costo de pedir prestado el monto a los meses indicados con cada
+// producto registrado, de la más barata a la más cara; las no disponibles van al final
+func OpcionesPrestamo(tarjetas Tarjetas, monto float64, meses int) []OpcionPrestamo {
+	var opciones []OpcionPrestamo
+
+	for _, t := range tarjetas.Credito {
+		pago := calc.PagoFijo(monto, calc.TasaConIVA(t.TasaInteres)/12, meses)
+		costo, _, _ := calc.CostoCredito(t, monto, pago)
+		o := OpcionPrestamo{Producto: "Tarjeta", Nombre: t.Nombre, TasaAnual: t.TasaInteres, Pago: pago, CostoTotal: costo, Disponible: true}
+		if t.LimiteCredito > 0 && monto > t.LimiteCredito {
+			o.Disponible = false
+			o.Nota = fmt.Sprintf("Excede el límite de $%.2f", t.LimiteCredito)
+		}
+		opciones = append(opciones, o)
+	}
+
+	for _, c := range tarjetas.Cajas {
+		pago, costo := c.CostoPrestamo(monto, meses)
+		o := OpcionPrestamo{Producto: "Caja", Nombre: c.Nombre, TasaAnual: c.TasaPrestamo, Pago: pago, CostoTotal: costo, Disponible: true}
+		if maximo := c.PrestamoMaximo(); monto > maximo {
+			o.Disponible = false
+			o.Nota = fmt.Sprintf("Presta hasta $%.2f con tu ahorro actual", maximo)
+		}
+		opciones = append(opciones, o)
+	}
+
+	for _, m := range tarjetas.Microcreditos {
+		costoMicro, err := AnalizarMicrocredito(m)
+		if err != nil {
+			continue
+		}
+		pago := calc.PagoFijo(monto, costoMicro.TasaAnualSimple/12, meses)
+		opciones = append(opciones, OpcionPrestamo{
+			Producto: "Microcrédito", Nombre: m.Nombre, TasaAnual: costoMicro.TasaAnualSimple,
+			Pago: pago, CostoTotal: pago*float64(meses) - monto, Disponible: true,
+			Nota: "Estimado con la tasa equivalente de tu préstamo registrado",
+		})
+	}
+
+	sort.SliceStable(opciones, func(i, j int) bool {
+		if opciones[i].Disponible != opciones[j].Disponible {
+			return opciones[i].Disponible
+		}
+		return opciones[i].CostoTotal < opciones[j].CostoTotal
+	})
+	return opciones
+}
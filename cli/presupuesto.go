@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"finmex/api"
+)
+
+// UMBRAL_AVISO_PRESUPUESTO es la fracción del límite de una categoría a partir de la cual
+// se avisa que se está por agotar
+const UMBRAL_AVISO_PRESUPUESTO = 0.80
+
+// CATEGORIA_SIN_PRESUPUESTO agrupa en el avance los gastos de categorías sin límite
+const CATEGORIA_SIN_PRESUPUESTO = "(sin presupuesto)"
+
+// Presupuesto es el plan mensual de ingresos y límites de gasto por categoría. Los gastos
+// son los movimientos del historial con la categoría correspondiente.
+type Presupuesto api.Presupuesto
+
+// IngresoMensual suma los ingresos del presupuesto
+func (p Presupuesto) IngresoMensual() float64 {
+	total := 0.0
+	for _, i := range p.Ingresos {
+		total += i.Monto
+	}
+	return total
+}
+
+// LimiteMensual suma los límites de todas las categorías
+func (p Presupuesto) LimiteMensual() float64 {
+	total := 0.0
+	for _, c := range p.Categorias {
+		total += c.Limite
+	}
+	return total
+}
+
+// FijarIngreso agrega o reemplaza un ingreso; con monto cero lo quita. Regresa si existía.
+func (p *Presupuesto) FijarIngreso(nombre string, monto float64) bool {
+	for i, ingreso := range p.Ingresos {
+		if normalizarClave(ingreso.Nombre) == normalizarClave(nombre) {
+			if monto == 0 {
+				p.Ingresos = append(p.Ingresos[:i], p.Ingresos[i+1:]...)
+			} else {
+				p.Ingresos[i].Monto = monto
+			}
+			return true
+		}
+	}
+	if monto != 0 {
+		p.Ingresos = append(p.Ingresos, IngresoPresupuesto{Nombre: nombre, Monto: monto})
+	}
+	return false
+}
+
+// FijarCategoria agrega o reemplaza el límite de una categoría; con límite cero la quita.
+// Regresa si existía.
+func (p *Presupuesto) FijarCategoria(nombre string, limite float64) bool {
+	for i, c := range p.Categorias {
+		if normalizarClave(c.Nombre) == normalizarClave(nombre) {
+			if limite == 0 {
+				p.Categorias = append(p.Categorias[:i], p.Categorias[i+1:]...)
+			} else {
+				p.Categorias[i].Limite = limite
+			}
+			return true
+		}
+	}
+	if limite != 0 {
+		p.Categorias = append(p.Categorias, CategoriaPresupuesto{Nombre: nombre, Limite: limite})
+	}
+	return false
+}
+
+// AvanceCategoria compara lo gastado en una categoría durante el mes contra su límite
+type AvanceCategoria struct {
+	Categoria  string   `json:"categoria"`
+	Limite     float64  `json:"limite"` // Cero en los gastos sin presupuesto
+	Gastado    float64  `json:"gastado"`
+	Disponible float64  `json:"disponible"`
+	Avance     *float64 `json:"avance"` // Fracción del límite gastada; null sin límite
+}
+
+// Aviso indica si la categoría ya pasó del umbral de aviso
+func (a AvanceCategoria) Aviso() bool {
+	return a.Avance != nil && *a.Avance >= UMBRAL_AVISO_PRESUPUESTO
+}
+
+// Excedida indica si la categoría ya gastó más que su límite
+func (a AvanceCategoria) Excedida() bool {
+	return a.Avance != nil && *a.Avance > 1
+}
+
+// AvancePresupuesto es el estado del presupuesto en un mes
+type AvancePresupuesto struct {
+	Mes        string            `json:"mes"` // AAAA-MM
+	Ingreso    float64           `json:"ingreso"`
+	Limite     float64           `json:"limite"`
+	Gastado    float64           `json:"gastado"`
+	Sobrante   float64           `json:"sobrante"` // Ingreso menos lo gastado
+	Categorias []AvanceCategoria `json:"categorias"`
+}
+
+// CalcularAvancePresupuesto suma los cargos del mes por categoría. Los abonos en una
+// categoría con límite (devoluciones) restan de lo gastado; los demás abonos, como los pagos
+// a la tarjeta o la nómina, no son gasto.
+func CalcularAvancePresupuesto(p Presupuesto, mes string, movimientos []Movimiento) AvancePresupuesto {
+	a := AvancePresupuesto{Mes: mes, Ingreso: p.IngresoMensual(), Limite: p.LimiteMensual()}
+	gastos := map[string]float64{}
+	for _, m := range movimientos {
+		if !strings.HasPrefix(m.Fecha, mes) {
+			continue
+		}
+		categoria := categoriaPresupuesto(p, m.Categoria)
+		if m.Monto < 0 && categoria == CATEGORIA_SIN_PRESUPUESTO {
+			continue
+		}
+		gastos[categoria] += m.Monto
+	}
+
+	for _, c := range p.Categorias {
+		gastado := gastos[c.Nombre]
+		avance := gastado / c.Limite
+		a.Categorias = append(a.Categorias, AvanceCategoria{c.Nombre, c.Limite, gastado, c.Limite - gastado, &avance})
+		a.Gastado += gastado
+	}
+	if otros, ok := gastos[CATEGORIA_SIN_PRESUPUESTO]; ok {
+		a.Categorias = append(a.Categorias, AvanceCategoria{Categoria: CATEGORIA_SIN_PRESUPUESTO, Gastado: otros, Disponible: -otros})
+		a.Gastado += otros
+	}
+	a.Sobrante = a.Ingreso - a.Gastado
+	return a
+}
+
+// categoriaPresupuesto regresa el nombre con que el presupuesto registra la categoría de un
+// movimiento, o CATEGORIA_SIN_PRESUPUESTO si no tiene límite
+func categoriaPresupuesto(p Presupuesto, categoria string) string {
+	for _, c := range p.Categorias {
+		if normalizarClave(c.Nombre) == normalizarClave(categoria) {
+			return c.Nombre
+		}
+	}
+	return CATEGORIA_SIN_PRESUPUESTO
+}
+
+// MovimientosDelMes lee del historial los movimientos de un mes (AAAA-MM)
+func MovimientosDelMes(mes string) ([]Movimiento, error) {
+	var movimientos []Movimiento
+	err := RecorrerMovimientos(func(m Movimiento) error {
+		if strings.HasPrefix(m.Fecha, mes) {
+			movimientos = append(movimientos, m)
+		}
+		return nil
+	})
+	return movimientos, err
+}
+
+// avisarPresupuesto muestra un aviso por cada categoría que pasó del umbral o del límite
+func avisarPresupuesto(a AvancePresupuesto) {
+	for _, c := range a.Categorias {
+		switch {
+		case c.Excedida():
+			fmt.Printf("ALERTA: %s excede su presupuesto: $%.2f de $%.2f (%.0f%%)\n", c.Categoria, c.Gastado, c.Limite, *c.Avance*100)
+		case c.Aviso():
+			fmt.Printf("AVISO: %s ya usó el %.0f%% de su presupuesto; quedan $%.2f\n", c.Categoria, *c.Avance*100, c.Disponible)
+		}
+	}
+}
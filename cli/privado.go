@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// MASCARA_MONTO es lo que se muestra en lugar de un monto en modo privado
+const MASCARA_MONTO = "$•••••"
+
+// patronMonto reconoce montos en pesos como "$1500.00" o "$-42.75", junto con los espacios
+// de relleno que los siguen en las tablas
+var patronMonto = regexp.MustCompile(`\$-?[0-9][0-9,]*(\.[0-9]+)?( {2,})?`)
+
+// OcultarMontos reemplaza los montos en pesos de un texto por la máscara. Las tasas y
+// porcentajes no llevan "$" y se dejan intactos. Cuando el monto va seguido de relleno se
+// ajustan los espacios para que las columnas de las tablas sigan alineadas.
+func OcultarMontos(texto string) string {
+	return patronMonto.ReplaceAllStringFunc(texto, func(monto string) string {
+		sinRelleno := strings.TrimRight(monto, " ")
+		if sinRelleno == monto {
+			return MASCARA_MONTO
+		}
+		ancho := len([]rune(monto)) - len([]rune(MASCARA_MONTO))
+		if ancho < 1 {
+			ancho = 1
+		}
+		return MASCARA_MONTO + strings.Repeat(" ", ancho)
+	})
+}
+
+// salidaPrivada intercepta la salida estándar para ocultar los montos antes de mostrarlos
+type salidaPrivada struct {
+	original *os.File
+	escritor *os.File
+	listo    chan struct{}
+}
+
+// activarModoPrivado redirige os.Stdout a través del filtro de montos
+func activarModoPrivado() (*salidaPrivada, error) {
+	lector, escritor, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &salidaPrivada{original: os.Stdout, escritor: escritor, listo: make(chan struct{})}
+	go func() {
+		defer close(s.listo)
+		buffer := make([]byte, 32*1024)
+		for {
+			n, err := lector.Read(buffer)
+			if n > 0 {
+				s.original.WriteString(OcultarMontos(string(buffer[:n])))
+			}
+			if err != nil {
+				lector.Close()
+				return
+			}
+		}
+	}()
+
+	os.Stdout = escritor
+	return s, nil
+}
+
+// cerrar restaura la salida estándar después de mostrar todo lo pendiente
+func (s *salidaPrivada) cerrar() {
+	os.Stdout = s.original
+	s.escritor.Close()
+	<-s.listo
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// imprimirProyeccion muestra la proyección de una cuenta año por año
+func imprimirProyeccion(proyeccion []AñoProyeccion, aportacionMensual float64) {
+	if len(proyeccion) == 0 {
+		return
+	}
+	fmt.Printf("\n=== Proyección a %d años (aportación mensual de $%.2f, inflación de %.2f%%) ===\n",
+		len(proyeccion), aportacionMensual, InflacionVigente()*100)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintln(w, "Año\tSaldo Inicial\tAportaciones\tIntereses\tImpuestos\tComisiones\tSaldo Final\tEn Pesos de Hoy\tGanancia Real")
+	fmt.Fprintln(w, "---\t-------------\t------------\t---------\t---------\t----------\t-----------\t---------------\t-------------")
+
+	var aportado, intereses, impuestos, comisiones float64
+	for _, a := range proyeccion {
+		fmt.Fprintf(w, "%d\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t$%.2f\n",
+			a.Año, a.SaldoInicial, a.Aportaciones, a.Intereses, a.Impuestos, a.Comisiones, a.SaldoFinal, a.SaldoReal, a.GananciaReal)
+		aportado += a.Aportaciones
+		intereses += a.Intereses
+		impuestos += a.Impuestos
+		comisiones += a.Comisiones
+	}
+	fmt.Fprintf(w, "Total\t\t$%.2f\t$%.2f\t$%.2f\t$%.2f\t\t\t\n", aportado, intereses, impuestos, comisiones)
+	w.Flush()
+
+	final := proyeccion[len(proyeccion)-1]
+	if final.GananciaReal > 0 {
+		fmt.Printf("RESULTADO: En %d años tu dinero gana $%.2f en pesos de hoy sobre lo que aportaste\n", final.Año, final.GananciaReal)
+	} else {
+		fmt.Printf("RESULTADO: En %d años tu dinero pierde $%.2f en pesos de hoy frente a lo que aportaste\n", final.Año, -final.GananciaReal)
+	}
+}
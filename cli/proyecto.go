@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+
+	"finmex/calc"
+)
+
+// periodosProyecto relaciona el nombre del periodo de un proyecto con los periodos por año
+var periodosProyecto = map[string]int{
+	"mensual":    12,
+	"bimestral":  6,
+	"trimestral": 4,
+	"semestral":  2,
+	"anual":      1,
+}
+
+// Proyecto es una inversión con flujos por periodo: el primero es la inversión inicial
+// (negativa) y al último se le suma el valor terminal
+type Proyecto struct {
+	Flujos        []float64
+	ValorTerminal float64
+	PeriodosAño   int
+}
+
+// EvaluacionProyecto resume la rentabilidad de un proyecto a la tasa de oportunidad
+type EvaluacionProyecto struct {
+	VPN         float64
+	TIRPeriodo  float64
+	TIRAnual    float64
+	TIRValida   bool
+	Payback     float64 // Periodos para recuperar la inversión con flujos descontados
+	Recupera    bool
+	TasaPeriodo float64
+}
+
+// flujosTotales regresa los flujos del proyecto con el valor terminal incluido
+func (p Proyecto) flujosTotales() []float64 {
+	flujos := append([]float64(nil), p.Flujos...)
+	if len(flujos) > 0 {
+		flujos[len(flujos)-1] += p.ValorTerminal
+	}
+	return flujos
+}
+
+// ParsearPeriodoProyecto convierte el nombre de un periodo en periodos por año
+func ParsearPeriodoProyecto(nombre string) (int, error) {
+	if periodos, ok := periodosProyecto[nombre]; ok {
+		return periodos, nil
+	}
+	return 0, fmt.Errorf("Periodo no soportado: %s (usa mensual, bimestral, trimestral, semestral o anual)", nombre)
+}
+
+// EvaluarProyecto calcula VPN, TIR y payback descontado con la tasa de oportunidad anual
+func EvaluarProyecto(p Proyecto, tasaOportunidad float64) (EvaluacionProyecto, error) {
+	if len(p.Flujos) < 2 {
+		return EvaluacionProyecto{}, fmt.Errorf("El proyecto necesita una inversión inicial y al menos un flujo")
+	}
+	if p.PeriodosAño <= 0 {
+		p.PeriodosAño = 1
+	}
+
+	flujos := p.flujosTotales()
+	e := EvaluacionProyecto{TasaPeriodo: tasaOportunidad / float64(p.PeriodosAño)}
+	e.VPN = calc.VPN(e.TasaPeriodo, flujos)
+
+	if tir, err := calc.TIR(flujos); err == nil {
+		e.TIRPeriodo, e.TIRValida = tir, true
+		e.TIRAnual = calc.TasaAnualEfectiva(tir, float64(p.PeriodosAño))
+	}
+
+	e.Payback, e.Recupera = paybackDescontado(flujos, e.TasaPeriodo)
+	return e, nil
+}
+
+// paybackDescontado regresa en cuántos periodos los flujos descontados acumulados dejan de
+// ser negativos, interpolando dentro del periodo en que se recupera la inversión
+func paybackDescontado(flujos []float64, tasaPeriodo float64) (float64, bool) {
+	acumulado := 0.0
+	factor := 1.0
+	for i, flujo := range flujos {
+		descontado := flujo / factor
+		if anterior := acumulado; anterior < 0 && anterior+descontado >= 0 {
+			return float64(i-1) + -anterior/descontado, true
+		}
+		acumulado += descontado
+		factor *= 1 + tasaPeriodo
+	}
+	return 0, acumulado >= 0 && len(flujos) > 0 && flujos[0] >= 0
+}
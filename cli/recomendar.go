@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"finmex/calc"
+)
+
+// COMISION_EXTRANJERO es la comisión típica por compras en moneda extranjera
+const COMISION_EXTRANJERO = 0.03
+
+// CategoriasGasto son las categorías del cuestionario de perfil
+var CategoriasGasto = []string{"supermercado", "restaurantes", "gasolina", "en_linea", "viajes", categoriaGeneral}
+
+// categoriaGeneral es la categoría del gasto que no cae en otra; usa el cashback general
+const categoriaGeneral = "otros"
+
+// PerfilGasto describe cómo usa el usuario sus tarjetas de crédito
+type PerfilGasto struct {
+	Mensual              map[string]float64 // Gasto mensual por categoría
+	SaldoRevolvente      float64            // Deuda promedio que no se paga al corte; cero si paga total
+	ViajesAño            int
+	GastoExtranjeroAnual float64
+}
+
+// PuntajeTarjetas es el valor anual neto de usar una o varias tarjetas con un perfil
+type PuntajeTarjetas struct {
+	Tarjetas    []CreditoCatalogo
+	Cashback    float64
+	Anualidades float64
+	Intereses   float64
+	Comisiones  float64 // Comisiones por compras en el extranjero
+	Viajes      float64 // Beneficios de viaje valuados
+}
+
+// Valor regresa el beneficio neto anual: lo que se gana menos lo que se paga
+func (p PuntajeTarjetas) Valor() float64 {
+	return p.Cashback + p.Viajes - p.Anualidades - p.Intereses - p.Comisiones
+}
+
+// Nombres regresa los nombres de las tarjetas evaluadas separados por " + "
+func (p PuntajeTarjetas) Nombres() string {
+	var nombres []string
+	for _, t := range p.Tarjetas {
+		nombres = append(nombres, t.Nombre+" ("+t.Banco+")")
+	}
+	return strings.Join(nombres, " + ")
+}
+
+// PuntuarTarjetas valúa un conjunto de tarjetas usadas en conjunto: cada categoría se paga con
+// la tarjeta que más devuelve, la deuda se mantiene en la de menor tasa y las compras en el
+// extranjero en la que no cobra comisión. El cashback de cada tarjeta respeta su tope mensual
+// y el valor de canje de sus puntos.
+func PuntuarTarjetas(tarjetas []CreditoCatalogo, perfil PerfilGasto) PuntajeTarjetas {
+	p := PuntajeTarjetas{Tarjetas: tarjetas}
+	if len(tarjetas) == 0 {
+		return p
+	}
+
+	asignado := make([]map[string]float64, len(tarjetas))
+	for i := range asignado {
+		asignado[i] = make(map[string]float64)
+	}
+	for categoria, gasto := range perfil.Mensual {
+		mejor := 0
+		for i, t := range tarjetas {
+			if t.TasaCashback(categoria)*t.ValorCanje() > tarjetas[mejor].TasaCashback(categoria)*tarjetas[mejor].ValorCanje() {
+				mejor = i
+			}
+		}
+		asignado[mejor][categoria] = gasto
+	}
+	for i, t := range tarjetas {
+		p.Cashback += calc.CashbackMensual(t.TarjetaCredito, asignado[i]).Valor * 12
+		// La anualidad se condona si el gasto que se le asigna alcanza la facturación
+		facturado := 0.0
+		for _, gasto := range asignado[i] {
+			facturado += gasto
+		}
+		p.Anualidades += t.AnualidadEfectiva(facturado)
+	}
+
+	tasa, comision, viaje := math.Inf(1), COMISION_EXTRANJERO, 0.0
+	for _, t := range tarjetas {
+		tasa = math.Min(tasa, t.TasaInteres)
+		if t.SinComisionExtranjero {
+			comision = 0
+		}
+		if t.Viajes {
+			viaje = math.Max(viaje, t.ValorViaje)
+		}
+	}
+
+	p.Intereses = perfil.SaldoRevolvente * tasa
+	p.Comisiones = perfil.GastoExtranjeroAnual * comision
+	p.Viajes = float64(perfil.ViajesAño) * viaje
+	return p
+}
+
+// RecomendarTarjetas puntúa cada tarjeta sola, de mejor a peor, y busca el mejor par
+func RecomendarTarjetas(candidatas []CreditoCatalogo, perfil PerfilGasto) ([]PuntajeTarjetas, PuntajeTarjetas) {
+	var individuales []PuntajeTarjetas
+	for _, t := range candidatas {
+		individuales = append(individuales, PuntuarTarjetas([]CreditoCatalogo{t}, perfil))
+	}
+	sort.SliceStable(individuales, func(i, j int) bool { return individuales[i].Valor() > individuales[j].Valor() })
+
+	var par PuntajeTarjetas
+	for i := range candidatas {
+		for j := i + 1; j < len(candidatas); j++ {
+			p := PuntuarTarjetas([]CreditoCatalogo{candidatas[i], candidatas[j]}, perfil)
+			if len(par.Tarjetas) == 0 || p.Valor() > par.Valor() {
+				par = p
+			}
+		}
+	}
+	return individuales, par
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"finmex/calc"
+)
+
+// Eventos de un recordatorio
+const (
+	EventoCorte = "corte"
+	EventoPago  = "pago"
+)
+
+// Recordatorio es una fecha de corte o límite de pago de una tarjeta de crédito
+type Recordatorio struct {
+	Fecha         time.Time `json:"fecha"`
+	Tarjeta       string    `json:"tarjeta"`
+	Evento        string    `json:"evento"` // corte o pago
+	DiasRestantes int       `json:"dias_restantes"`
+	PagoMinimo    float64   `json:"pago_minimo"` // Cero en los cortes
+	Corte         time.Time `json:"corte"`       // Corte del periodo que se paga
+}
+
+// Recordatorios reúne los cortes y pagos de las tarjetas de crédito entre hoy y hasta. El
+// pago mínimo se estima con el saldo revolvente actual y las mensualidades de MSI que tocan
+// en el mes del corte. También regresa las tarjetas que no tienen registrados sus días.
+func Recordatorios(tarjetas Tarjetas, hoy, hasta time.Time) ([]Recordatorio, []string, error) {
+	var recordatorios []Recordatorio
+	var sinDias []string
+	for _, t := range tarjetas.Credito {
+		// Empieza un periodo atrás para incluir el pago pendiente del último corte
+		ciclo, ok := t.Ciclo(hoy.AddDate(0, -1, 0))
+		if !ok {
+			sinDias = append(sinDias, t.Nombre)
+			continue
+		}
+		for ; !ciclo.Corte.After(hasta); ciclo = ciclo.Siguiente(t.DiaCorte, t.DiaLimitePago) {
+			if !ciclo.Corte.Before(hoy) {
+				recordatorios = append(recordatorios, Recordatorio{
+					Fecha: ciclo.Corte, Tarjeta: t.Nombre, Evento: EventoCorte, Corte: ciclo.Corte,
+				})
+			}
+			if ciclo.LimitePago.Before(hoy) || ciclo.LimitePago.After(hasta) {
+				continue
+			}
+			minimo := calc.PagoMinimoPeriodo(t, t.Saldo, FrecuenciaMensual)
+			for _, p := range t.Planes {
+				n, err := p.NumeroMensualidad(ciclo.Corte)
+				if err != nil {
+					return nil, nil, err
+				}
+				if n > 0 {
+					minimo += p.Mensualidad()
+				}
+			}
+			recordatorios = append(recordatorios, Recordatorio{
+				Fecha: ciclo.LimitePago, Tarjeta: t.Nombre, Evento: EventoPago, PagoMinimo: minimo, Corte: ciclo.Corte,
+			})
+		}
+	}
+	for i := range recordatorios {
+		recordatorios[i].DiasRestantes = int(recordatorios[i].Fecha.Sub(hoy).Hours() / 24)
+	}
+	sort.SliceStable(recordatorios, func(i, j int) bool { return recordatorios[i].Fecha.Before(recordatorios[j].Fecha) })
+	return recordatorios, sinDias, nil
+}
+
+// Resumen es el título del recordatorio en el calendario
+func (r Recordatorio) Resumen() string {
+	if r.Evento == EventoCorte {
+		return fmt.Sprintf("Corte de %s", r.Tarjeta)
+	}
+	return fmt.Sprintf("Pagar %s", r.Tarjeta)
+}
+
+// EscribirICal escribe los recordatorios como un calendario iCalendar (RFC 5545) de eventos
+// de día completo. Los pagos avisan un día antes.
+func EscribirICal(w io.Writer, recordatorios []Recordatorio, generado time.Time) error {
+	var b strings.Builder
+	linea := func(texto string) {
+		// Las líneas de más de 75 bytes continúan en la siguiente empezando con un espacio
+		for len(texto) > 75 {
+			corte := 75
+			for !utf8.RuneStart(texto[corte]) {
+				corte--
+			}
+			b.WriteString(texto[:corte] + "\r\n")
+			texto = " " + texto[corte:]
+		}
+		b.WriteString(texto + "\r\n")
+	}
+
+	linea("BEGIN:VCALENDAR")
+	linea("VERSION:2.0")
+	linea("PRODID:-//finmex//recordatorios//ES")
+	linea("CALSCALE:GREGORIAN")
+	for _, r := range recordatorios {
+		descripcion := fmt.Sprintf("Fecha de corte de %s", r.Tarjeta)
+		if r.Evento == EventoPago {
+			descripcion = fmt.Sprintf("Fecha límite de pago del corte del %s. Pago mínimo estimado: $%.2f", r.Corte.Format("2006-01-02"), r.PagoMinimo)
+		}
+		linea("BEGIN:VEVENT")
+		linea(fmt.Sprintf("UID:%s-%s-%s@finmex", r.Fecha.Format("20060102"), r.Evento, uidICal(r.Tarjeta)))
+		linea("DTSTAMP:" + generado.UTC().Format("20060102T150405Z"))
+		linea("DTSTART;VALUE=DATE:" + r.Fecha.Format("20060102"))
+		linea("DTEND;VALUE=DATE:" + r.Fecha.AddDate(0, 0, 1).Format("20060102"))
+		linea("SUMMARY:" + textoICal(r.Resumen()))
+		linea("DESCRIPTION:" + textoICal(descripcion))
+		if r.Evento == EventoPago {
+			linea("BEGIN:VALARM")
+			linea("ACTION:DISPLAY")
+			linea("DESCRIPTION:" + textoICal(r.Resumen()))
+			linea("TRIGGER:-P1D")
+			linea("END:VALARM")
+		}
+		linea("END:VEVENT")
+	}
+	linea("END:VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// textoICal escapa los caracteres especiales de un valor de texto de iCalendar
+func textoICal(texto string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(texto)
+}
+
+// uidICal deja solo letras y números del nombre para que el UID sea estable entre
+// exportaciones y el calendario actualice los eventos en lugar de duplicarlos
+func uidICal(nombre string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, normalizarClave(nombre))
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"sort"
+	"text/template"
+	"time"
+
+	"finmex/calc"
+)
+
+// Formatos del reporte mensual
+const (
+	FormatoReporteMarkdown = "md"
+	FormatoReporteHTML     = "html"
+)
+
+// plantillasReporte son las plantillas embebidas del reporte en cada formato
+//
+//go:embed plantillas/reporte.md.tmpl plantillas/reporte.html.tmpl
+var plantillasReporte embed.FS
+
+// CreditoReporte es una tarjeta de crédito con deuda en el reporte
+type CreditoReporte struct {
+	Nombre         string
+	Banco          string
+	Saldo          float64
+	TasaInteres    float64
+	CAT            float64
+	InteresMensual float64 // Lo que genera el saldo en un mes si no se liquida, con IVA
+	PagoMinimo     float64
+	MensualidadMSI float64 // Suma de las mensualidades de MSI del mes
+}
+
+// GastoReporte es lo gastado en una categoría durante el mes
+type GastoReporte struct {
+	Categoria  string
+	Monto      float64
+	Porcentaje float64 // Fracción del gasto total
+}
+
+// ReporteMensual es el contenido del reporte de un mes
+type ReporteMensual struct {
+	Mes             string // AAAA-MM
+	Generado        string
+	Inflacion       float64
+	Patrimonio      ResumenPatrimonial
+	Debito          []ResultadoComparacionDebito // Cuentas con saldo
+	Creditos        []CreditoReporte
+	InteresMensual  float64 // De todas las tarjetas con deuda
+	Gastos          []GastoReporte
+	GastoTotal      float64
+	Presupuesto     *AvancePresupuesto
+	Recomendaciones []Insight
+}
+
+// GenerarReporteMensual arma el reporte con los productos registrados y los movimientos del
+// mes; las recomendaciones usan el gasto de los doce meses anteriores al fin del mes
+func GenerarReporteMensual(tarjetas Tarjetas, catalogo Catalogo, mes string, hoy time.Time) (ReporteMensual, error) {
+	r := ReporteMensual{Mes: mes, Generado: hoy.Format("2006-01-02 15:04"), Inflacion: InflacionVigente()}
+	inicio, err := time.Parse("2006-01", mes)
+	if err != nil {
+		return r, errDatosInvalidos(
+			fmt.Sprintf("Mes inválido '%s' (usa AAAA-MM)", mes),
+			fmt.Sprintf("Invalid month '%s' (use YYYY-MM)", mes))
+	}
+	fin := inicio.AddDate(0, 1, -1)
+	if fin.After(hoy) {
+		fin = hoy
+	}
+
+	if r.Patrimonio, err = CalcularResumenPatrimonial(tarjetas, fin); err != nil {
+		return r, err
+	}
+
+	for _, t := range tarjetas.Debito {
+		if t.Saldo > 0 {
+			r.Debito = append(r.Debito, resultadoComparacionDebito(t, SaldoEnPesos(t)))
+		}
+	}
+	for _, t := range tarjetas.Credito {
+		c := CreditoReporte{
+			Nombre: t.Nombre, Banco: t.Banco, Saldo: t.Saldo, TasaInteres: t.TasaInteres, CAT: t.CAT,
+			InteresMensual: t.Saldo * calc.TasaConIVA(t.TasaInteres) / 12, PagoMinimo: calc.PagoMinimoPeriodo(t, t.Saldo, FrecuenciaMensual),
+		}
+		for _, p := range t.Planes {
+			if n, err := p.NumeroMensualidad(inicio); err == nil && n > 0 {
+				c.MensualidadMSI += p.Mensualidad()
+			}
+		}
+		if c.Saldo > 0 || c.MensualidadMSI > 0 {
+			r.Creditos = append(r.Creditos, c)
+			r.InteresMensual += c.InteresMensual
+		}
+	}
+
+	movimientos, err := MovimientosDelMes(mes)
+	if err != nil {
+		return r, fmt.Errorf("Error al leer movimientos: %w", err)
+	}
+	gastos := map[string]float64{}
+	for _, m := range movimientos {
+		if m.Monto <= 0 {
+			continue
+		}
+		categoria := m.Categoria
+		if categoria == "" {
+			categoria = "(sin categoría)"
+		}
+		gastos[categoria] += m.Monto
+		r.GastoTotal += m.Monto
+	}
+	for categoria, monto := range gastos {
+		r.Gastos = append(r.Gastos, GastoReporte{Categoria: categoria, Monto: monto, Porcentaje: monto / r.GastoTotal})
+	}
+	sort.Slice(r.Gastos, func(i, j int) bool { return r.Gastos[i].Monto > r.Gastos[j].Monto })
+
+	if tarjetas.Presupuesto != nil {
+		avance := CalcularAvancePresupuesto(*tarjetas.Presupuesto, mes, movimientos)
+		r.Presupuesto = &avance
+	}
+
+	gastoAnual, err := GastoAnualPorTarjeta(fin)
+	if err != nil {
+		return r, fmt.Errorf("Error al leer movimientos: %w", err)
+	}
+	r.Recomendaciones = GenerarInsights(tarjetas, catalogo, gastoAnual)
+	return r, nil
+}
+
+// funcionesReporte son los formatos de montos y tasas disponibles en las plantillas
+var funcionesReporte = map[string]interface{}{
+	"pesos":     func(monto float64) string { return fmt.Sprintf("$%.2f", monto) },
+	"pct":       func(tasa float64) string { return fmt.Sprintf("%.2f%%", tasa*100) },
+	"siguiente": func(i int) int { return i + 1 },
+}
+
+// EscribirReporte aplica la plantilla del formato al reporte. Con ruta de plantilla se usa
+// ese archivo en lugar de la embebida; las plantillas HTML escapan el contenido.
+func EscribirReporte(w io.Writer, r ReporteMensual, formato, rutaPlantilla string) error {
+	if formato != FormatoReporteMarkdown && formato != FormatoReporteHTML {
+		return errDatosInvalidos(
+			fmt.Sprintf("Formato de reporte inválido '%s' (usa md o html)", formato),
+			fmt.Sprintf("Invalid report format '%s' (use md or html)", formato))
+	}
+
+	var texto []byte
+	var err error
+	if rutaPlantilla != "" {
+		texto, err = os.ReadFile(rutaPlantilla)
+	} else {
+		texto, err = plantillasReporte.ReadFile("plantillas/reporte." + formato + ".tmpl")
+	}
+	if err != nil {
+		return fmt.Errorf("Error al leer la plantilla del reporte: %w", err)
+	}
+
+	if formato == FormatoReporteHTML {
+		t, err := htmltemplate.New("reporte").Funcs(funcionesReporte).Parse(string(texto))
+		if err != nil {
+			return fmt.Errorf("Plantilla de reporte inválida: %w", err)
+		}
+		return t.Execute(w, r)
+	}
+	t, err := template.New("reporte").Funcs(funcionesReporte).Parse(string(texto))
+	if err != nil {
+		return fmt.Errorf("Plantilla de reporte inválida: %w", err)
+	}
+	return t.Execute(w, r)
+}
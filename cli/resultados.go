@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"time"
+
+	"finmex/calc"
+)
+
+// filaDebito agrega a la cuenta los datos calculados que muestra la tabla
+func filaDebito(t TarjetaDebito, catalogo Catalogo) FilaDebito {
+	fila := FilaDebito{TarjetaDebito: t, VsMercado: PosicionDebito(t, catalogo).Descripcion()}
+	if saldo, ok := calc.SaldoEquilibrio(t, InflacionVigente(), ISRVigente()); ok {
+		fila.SaldoEquilibrio = &saldo
+	}
+	return fila
+}
+
+// analisisDebito calcula el rendimiento de un año con el saldo dado en pesos
+func analisisDebito(t TarjetaDebito, saldo float64) AnalisisDebito {
+	inflacion := InflacionVigente()
+	rendimiento, rendimientoPct, saldoFinal := CalcularRendimientoReal(t, saldo)
+	tasa := t.TasaPonderada(saldoEnMoneda(t, saldo))
+	// En dólares la ganancia en pesos incluye la cambiaria, que también paga ISR
+	tasaPesos, comision := tasa, t.ComisionAnual
+	if enDolares(t) {
+		tasaPesos = calc.TasaEnPesos(tasa, VariacionCambiaria())
+		comision *= tipoCambioMoneda(MonedaUSD) * (1 + VariacionCambiaria())
+	}
+	a := AnalisisDebito{
+		Nombre:             t.Nombre,
+		Banco:              t.Banco,
+		Moneda:             MonedaCuenta(t),
+		Saldo:              saldo,
+		Tramos:             t.TramosOrdenados(),
+		TasaRendimiento:    tasa,
+		RendimientoBruto:   saldo * tasaPesos,
+		Impuestos:          saldo * ISRVigente().TasaImpuesto(tasaPesos, inflacion),
+		Inflacion:          inflacion,
+		PerdidaInflacion:   saldo * inflacion,
+		ComisionAnual:      comision,
+		RendimientoReal:    rendimiento,
+		RendimientoRealPct: rendimientoPct,
+		SaldoFinal:         saldoFinal,
+		Gana:               rendimiento > 0,
+		RiesgoCambiario:    riesgoCambiario(t, saldo),
+	}
+	if equilibrio, ok := calc.SaldoEquilibrio(t, inflacion, ISRVigente()); ok {
+		a.SaldoEquilibrio = &equilibrio
+	}
+	return a
+}
+
+// renglonesTablaCredito pone a cada renglón la fecha que le toca en el calendario
+func renglonesTablaCredito(tabla []RenglonAmortizacion, calendario []time.Time) []RenglonTablaCredito {
+	renglones := make([]RenglonTablaCredito, len(tabla))
+	for i, r := range tabla {
+		renglones[i].RenglonAmortizacion = r
+		if r.Periodo <= len(calendario) {
+			renglones[i].Fecha = calendario[r.Periodo-1].Format("2006-01-02")
+		}
+	}
+	return renglones
+}
+
+// analisisCredito calcula el costo de liquidar la deuda con el pago y la frecuencia dados
+func analisisCredito(t TarjetaCredito, deuda, pago float64, frecuencia Frecuencia, calendario []time.Time) AnalisisCredito {
+	d := calc.CostoCreditoDesglosado(t, deuda, pago, frecuencia, nil)
+	a := AnalisisCredito{
+		Nombre:       t.Nombre,
+		Banco:        t.Banco,
+		Deuda:        deuda,
+		TasaInteres:  t.TasaInteres,
+		TasaEfectiva: calc.EfectivaDesdeNominal(t.TasaInteres, 12),
+		CAT:          t.CAT,
+		Frecuencia:   frecuencia,
+		Pago:         pago,
+		Pagos:        d.Pagos,
+		Meses:        float64(d.Pagos) * 12 / float64(frecuencia.PeriodosPorAño()),
+		CostoTotal:   d.Total,
+		Desglose:     d,
+		CostoPct:     d.Porcentaje,
+		MontoTotal:   deuda + d.Total,
+	}
+	if len(calendario) > 0 {
+		a.PrimerPago = calendario[0].Format("2006-01-02")
+		a.UltimoPago = calendario[len(calendario)-1].Format("2006-01-02")
+	}
+	return a
+}
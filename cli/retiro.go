@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+
+	"finmex/calc"
+)
+
+const (
+	// EDAD_RETIRO_VEJEZ es la edad de la pensión por vejez; desde los 60 se puede pedir la de
+	// cesantía en edad avanzada (LSS arts. 154 y 162)
+	EDAD_RETIRO_VEJEZ  = 65
+	EDAD_RETIRO_MINIMA = 60
+	// EDAD_FIN_RETIRO_PROGRAMADO es hasta qué edad se reparte el saldo al estimar la pensión por
+	// retiro programado; cubre la esperanza de vida a los 65 con algo de margen
+	EDAD_FIN_RETIRO_PROGRAMADO = 85
+	// RENDIMIENTO_REAL_AFORE es el rendimiento anual sobre la inflación que se supone por omisión
+	RENDIMIENTO_REAL_AFORE = 0.04
+	SEMANAS_POR_AÑO        = 52
+)
+
+// aportacionObligatoriaRetiro regresa la aportación tripartita a la subcuenta de retiro,
+// cesantía y vejez como fracción del salario. La reforma de 2020 la sube en promedio del 6.5%
+// al 15% entre 2023 y 2030; se interpola de forma lineal sin distinguir el rango de salario.
+func aportacionObligatoriaRetiro(año int) float64 {
+	const inicial, final = 0.065, 0.15
+	switch {
+	case año <= 2022:
+		return inicial
+	case año >= 2030:
+		return final
+	}
+	return inicial + (final-inicial)*float64(año-2022)/8
+}
+
+// SemanasRequeridas regresa las semanas cotizadas que pide la Ley 97 para pensionarse en el
+// año indicado: 750 en 2021 y 25 más cada año hasta llegar a 1,000 en 2031
+func SemanasRequeridas(año int) int {
+	if año < 2021 {
+		return 1250
+	}
+	return min(1000, 750+25*(año-2021))
+}
+
+// AñoRetiro es el estado de la cuenta al cumplir una edad de la proyección
+type AñoRetiro struct {
+	Edad           int     `json:"edad"`
+	Año            int     `json:"año"`
+	Obligatorias   float64 `json:"obligatorias"`
+	Voluntarias    float64 `json:"voluntarias"`
+	Saldo          float64 `json:"saldo"`
+	SinVoluntarias float64 `json:"sin_voluntarias"` // Saldo que habría solo con las obligatorias
+}
+
+// ProyeccionRetiro es el resultado de retiro proyectar; los montos están en pesos de hoy
+type ProyeccionRetiro struct {
+	Edad                  int         `json:"edad"`
+	EdadRetiro            int         `json:"edad_retiro"`
+	RendimientoReal       float64     `json:"rendimiento_real"`
+	RendimientoNeto       float64     `json:"rendimiento_neto"` // Real menos la comisión
+	Años                  []AñoRetiro `json:"años"`
+	Saldo                 float64     `json:"saldo"`
+	Pension               float64     `json:"pension"`        // Mensual por retiro programado
+	TasaReemplazo         float64     `json:"tasa_reemplazo"` // Pensión entre el último salario
+	SaldoSinVoluntarias   float64     `json:"saldo_sin_voluntarias"`
+	PensionSinVoluntarias float64     `json:"pension_sin_voluntarias"`
+	// Efecto fiscal de deducir las voluntarias junto con los PPR registrados
+	DeducibleAnual    float64 `json:"deducible_anual"`
+	DevolucionAnual   float64 `json:"devolucion_anual"`
+	Semanas           int     `json:"semanas"` // Semanas cotizadas al retirarse
+	SemanasRequeridas int     `json:"semanas_requeridas"`
+}
+
+// ProyectarRetiro acumula el saldo de la Afore mes por mes hasta la edad de retiro con las
+// aportaciones obligatorias, las voluntarias y el rendimiento real neto de comisión, y estima
+// la pensión de la Ley 97 como un retiro programado hasta EDAD_FIN_RETIRO_PROGRAMADO. El
+// salario crece cada año con incrementoSalario sobre la inflación. aportacionPPR es lo que ya
+// se deduce al año en planes personales de retiro, que comparten el tope con las voluntarias.
+func ProyectarRetiro(d DatosRetiro, rendimientoReal, incrementoSalario, aportacionPPR float64, año int) (ProyeccionRetiro, error) {
+	p := ProyeccionRetiro{
+		Edad: año - d.Nacimiento, EdadRetiro: d.EdadRetiro,
+		RendimientoReal: rendimientoReal, RendimientoNeto: rendimientoReal - d.Comision,
+	}
+	if p.EdadRetiro <= p.Edad {
+		return p, errDatosInvalidos(
+			fmt.Sprintf("La edad de retiro (%d) debe ser mayor a tu edad actual (%d)", p.EdadRetiro, p.Edad),
+			fmt.Sprintf("The retirement age (%d) must be greater than your current age (%d)", p.EdadRetiro, p.Edad))
+	}
+
+	ingresoAnual := d.Salario * 12
+	tope, err := TopeDeduccionPPR(ingresoAnual, año)
+	if err != nil {
+		return p, err
+	}
+	p.DeducibleAnual = math.Min(d.Voluntaria*12, math.Max(0, tope-aportacionPPR))
+	p.DevolucionAnual = DevolucionISR(ingresoAnual-math.Min(aportacionPPR, tope), p.DeducibleAnual)
+
+	// Las aportaciones se calculan sobre el salario base de cotización, topado en 25 UMA
+	umaMensual, err := calc.UMAMensual(año)
+	if err != nil {
+		return p, err
+	}
+	topeSBC := umaMensual * TOPE_SBC_UMA
+
+	tasa := p.RendimientoNeto / 12
+	saldo, sinVoluntarias := d.SaldoAfore, d.SaldoAfore
+	salario, ultimoSalario := d.Salario, d.Salario
+	for edad := p.Edad + 1; edad <= p.EdadRetiro; edad++ {
+		a := AñoRetiro{Edad: edad, Año: año + edad - p.Edad - 1}
+		obligatoria := math.Min(salario, topeSBC) * aportacionObligatoriaRetiro(a.Año)
+		for mes := 0; mes < 12; mes++ {
+			saldo = saldo*(1+tasa) + obligatoria + d.Voluntaria
+			sinVoluntarias = sinVoluntarias*(1+tasa) + obligatoria
+		}
+		a.Obligatorias = obligatoria * 12
+		a.Voluntarias = d.Voluntaria * 12
+		a.Saldo, a.SinVoluntarias = saldo, sinVoluntarias
+		p.Años = append(p.Años, a)
+		ultimoSalario = salario
+		salario *= 1 + incrementoSalario
+	}
+
+	meses := (EDAD_FIN_RETIRO_PROGRAMADO - p.EdadRetiro) * 12
+	if meses < 12 {
+		meses = 12
+	}
+	p.Saldo, p.SaldoSinVoluntarias = saldo, sinVoluntarias
+	p.Pension = calc.PagoFijo(saldo, tasa, meses)
+	p.PensionSinVoluntarias = calc.PagoFijo(sinVoluntarias, tasa, meses)
+	if ultimoSalario > 0 {
+		p.TasaReemplazo = p.Pension / ultimoSalario
+	}
+
+	p.Semanas = d.Semanas + (p.EdadRetiro-p.Edad)*SEMANAS_POR_AÑO
+	p.SemanasRequeridas = SemanasRequeridas(d.Nacimiento + p.EdadRetiro)
+	return p, nil
+}
+
+// limitesRealRetiro acota el rendimiento y el aumento de salario sobre la inflación
+var limitesRealRetiro = LimitesNumero{Min: -0.1, Max: 0.2, Porcentaje: true}
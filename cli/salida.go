@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Valores de --salida, el nombre anterior de --output ndjson
+const (
+	SalidaTabla  = "tabla"
+	SalidaNDJSON = "ndjson"
+)
+
+// flagSalida se conserva oculta para no romper los scripts que todavía usan --salida
+func flagSalida() cli.Flag {
+	return &cli.StringFlag{Name: "salida", Hidden: true, Usage: "Obsoleta: usa --output ndjson"}
+}
+
+// salidaNDJSON indica si el comando debe emitir NDJSON. --salida ndjson se toma como
+// --output ndjson y --salida tabla como la salida de texto, con un aviso.
+func salidaNDJSON(c *cli.Context) (bool, error) {
+	if c.IsSet("salida") {
+		switch c.String("salida") {
+		case SalidaTabla:
+			fmt.Fprintln(os.Stderr, "AVISO: --salida es obsoleta; la tabla es la salida por defecto")
+		case SalidaNDJSON:
+			fmt.Fprintln(os.Stderr, "AVISO: --salida es obsoleta; usa --output ndjson")
+			if destinoDatos == nil {
+				if err := activarOutput(FormatoNDJSON); err != nil {
+					return false, err
+				}
+			}
+			formatoDatos = FormatoNDJSON
+		default:
+			return false, fmt.Errorf("Formato de salida inválido '%s' (usa --output ndjson)", c.String("salida"))
+		}
+	}
+	return formatoDatos == FormatoNDJSON, nil
+}
+
+// Formatos de la opción global --output
+const (
+	FormatoTexto  = "texto"
+	FormatoJSON   = "json"
+	FormatoCSV    = "csv"
+	FormatoNDJSON = "ndjson" // Un objeto JSON por línea
+)
+
+var (
+	formatoDatos = FormatoTexto
+	destinoDatos *os.File // Salida estándar original mientras los mensajes van a stderr
+)
+
+// flagOutput es la opción global para que los comandos de análisis, listado y comparación
+// emitan datos estructurados
+func flagOutput() cli.Flag {
+	return &cli.StringFlag{Name: "output", Value: FormatoTexto, Usage: "Formato de los resultados: texto, json, csv o ndjson (un objeto JSON por línea)", EnvVars: []string{"FINMEX_OUTPUT"}}
+}
+
+// activarOutput prepara la salida estructurada. Con json, csv o ndjson los mensajes, avisos y
+// preguntas se mandan a stderr para que la salida estándar solo lleve los datos.
+func activarOutput(formato string) error {
+	switch formato {
+	case FormatoTexto:
+		return nil
+	case FormatoJSON, FormatoCSV, FormatoNDJSON:
+	default:
+		return fmt.Errorf("Formato de --output inválido '%s' (usa texto, json, csv o ndjson)", formato)
+	}
+	formatoDatos = formato
+	destinoDatos = os.Stdout
+	os.Stdout = os.Stderr
+	return nil
+}
+
+// restaurarOutput regresa la salida estándar original
+func restaurarOutput() {
+	if destinoDatos != nil {
+		os.Stdout = destinoDatos
+		destinoDatos = nil
+	}
+}
+
+// salidaEstructurada indica si se pidió --output json, csv o ndjson
+func salidaEstructurada() bool {
+	return formatoDatos != FormatoTexto
+}
+
+// salidaDatos es donde se escriben los datos: la salida estándar original
+func salidaDatos() io.Writer {
+	if destinoDatos != nil {
+		return destinoDatos
+	}
+	return os.Stdout
+}
+
+// emitirDatos escribe un resultado o una lista de resultados en el formato de --output. En
+// CSV cada struct es un renglón con una columna por campo, nombrada como en JSON; en NDJSON
+// cada elemento de la lista va en su propia línea.
+func emitirDatos(v interface{}) error {
+	switch formatoDatos {
+	case FormatoCSV:
+		return escribirCSV(salidaDatos(), v)
+	case FormatoNDJSON:
+		emisor := NuevoEmisorNDJSON(salidaDatos())
+		if valor := reflect.ValueOf(v); valor.Kind() == reflect.Slice {
+			for i := 0; i < valor.Len(); i++ {
+				if err := emisor.Emitir(valor.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return emisor.Emitir(v)
+	}
+	if valor := reflect.ValueOf(v); valor.Kind() == reflect.Slice && valor.IsNil() {
+		v = []struct{}{} // Una lista vacía se publica como [] y no como null
+	}
+	encoder := json.NewEncoder(salidaDatos())
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// escribirCSV escribe los structs de v, una lista o uno solo, con un encabezado
+func escribirCSV(w io.Writer, v interface{}) error {
+	valor := reflect.ValueOf(v)
+	tipo := valor.Type()
+	var renglones []reflect.Value
+	if valor.Kind() == reflect.Slice {
+		tipo = tipo.Elem()
+		for i := 0; i < valor.Len(); i++ {
+			renglones = append(renglones, reflect.Indirect(valor.Index(i)))
+		}
+	} else {
+		renglones = append(renglones, reflect.Indirect(valor))
+	}
+	if tipo.Kind() == reflect.Ptr {
+		tipo = tipo.Elem()
+	}
+
+	escritor := csv.NewWriter(w)
+	if tipo.Kind() == reflect.Struct {
+		escritor.Write(columnasCSV(tipo))
+	}
+	for _, r := range renglones {
+		escritor.Write(valoresCSV(r))
+	}
+	escritor.Flush()
+	return escritor.Error()
+}
+
+// camposCSV recorre los campos que encoding/json publicaría, aplanando los structs
+// embebidos
+func camposCSV(t reflect.Type, visitar func(nombre string, indice []int)) {
+	for i := 0; i < t.NumField(); i++ {
+		campo := t.Field(i)
+		if !campo.IsExported() {
+			continue
+		}
+		nombre, _, _ := strings.Cut(campo.Tag.Get("json"), ",")
+		if nombre == "-" {
+			continue
+		}
+		if campo.Anonymous && nombre == "" && campo.Type.Kind() == reflect.Struct {
+			camposCSV(campo.Type, func(n string, indice []int) {
+				visitar(n, append([]int{i}, indice...))
+			})
+			continue
+		}
+		if nombre == "" {
+			nombre = campo.Name
+		}
+		visitar(nombre, []int{i})
+	}
+}
+
+// columnasCSV regresa el encabezado para un tipo de renglón
+func columnasCSV(t reflect.Type) []string {
+	var columnas []string
+	camposCSV(t, func(nombre string, _ []int) {
+		columnas = append(columnas, nombre)
+	})
+	return columnas
+}
+
+// valoresCSV convierte un renglón a texto. Las listas de texto se unen con comas y los
+// valores compuestos se escriben como JSON dentro de la celda.
+func valoresCSV(r reflect.Value) []string {
+	var valores []string
+	camposCSV(r.Type(), func(_ string, indice []int) {
+		valores = append(valores, celdaCSV(r.FieldByIndex(indice)))
+	})
+	return valores
+}
+
+func celdaCSV(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.String {
+			return strings.Join(v.Interface().([]string), ", ")
+		}
+		if v.Len() == 0 {
+			return ""
+		}
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Emisor escribe resultados uno por uno: NDJSON los escribe en cuanto llegan y la salida de
+// --output los junta para escribirlos al cerrar
+type Emisor interface {
+	Emitir(v interface{}) error
+	Cerrar() error
+}
+
+// nuevoEmisor crea el emisor de un comando con --output; NDJSON escribe sin acumular
+func nuevoEmisor(ndjson bool) Emisor {
+	if ndjson {
+		return NuevoEmisorNDJSON(salidaDatos())
+	}
+	return &emisorDatos{}
+}
+
+// emisorDatos junta los resultados para emitirDatos
+type emisorDatos struct {
+	resultados []interface{}
+}
+
+func (e *emisorDatos) Emitir(v interface{}) error {
+	e.resultados = append(e.resultados, v)
+	return nil
+}
+
+func (e *emisorDatos) Cerrar() error {
+	if formatoDatos == FormatoCSV && len(e.resultados) > 0 {
+		// Todos los renglones son del mismo tipo; se arma una lista de ese tipo para el encabezado
+		lista := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(e.resultados[0])), 0, len(e.resultados))
+		for _, r := range e.resultados {
+			lista = reflect.Append(lista, reflect.ValueOf(r))
+		}
+		return emitirDatos(lista.Interface())
+	}
+	if e.resultados == nil {
+		e.resultados = []interface{}{}
+	}
+	return emitirDatos(e.resultados)
+}
+
+// EmisorNDJSON escribe cada resultado como una línea JSON en cuanto se calcula, sin
+// acumular la salida en memoria
+type EmisorNDJSON struct {
+	encoder *json.Encoder
+}
+
+// NuevoEmisorNDJSON crea un emisor que escribe en w
+func NuevoEmisorNDJSON(w io.Writer) *EmisorNDJSON {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &EmisorNDJSON{encoder: encoder}
+}
+
+// Emitir escribe un resultado
+func (e *EmisorNDJSON) Emitir(v interface{}) error {
+	return e.encoder.Encode(v)
+}
+
+// Cerrar no tiene nada pendiente: cada resultado ya se escribió
+func (e *EmisorNDJSON) Cerrar() error {
+	return nil
+}
+
+// valorRequeridoNDJSON toma un valor de su flag o lo pregunta. En NDJSON no se pregunta
+// para no mezclar las preguntas con los resultados.
+func valorRequeridoNDJSON(c *cli.Context, ndjson bool, flag, pregunta string, limites LimitesNumero) (float64, error) {
+	if c.IsSet(flag) {
+		valor := c.Float64(flag)
+		return valor, validarFlag(flag, valor, limites)
+	}
+	if ndjson {
+		return 0, fmt.Errorf("Con --output ndjson indica --%s", flag)
+	}
+	return leerNumero(pregunta, limites)
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"finmex/calc"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// DIRECTORIO_SCRIPTS es el subdirectorio de la configuración con los scripts Starlark
+const DIRECTORIO_SCRIPTS = "scripts"
+
+// pasosMaximosScript limita lo que puede ejecutar un script para que un ciclo infinito no
+// deje colgado el comando
+const pasosMaximosScript = 1000000
+
+// Funciones que un script puede definir para personalizar finmex
+const (
+	hookPuntuarTarjeta = "puntuar_tarjeta" // puntuar_tarjeta(tarjeta) -> número
+	hookCategorizar    = "categorizar"     // categorizar(movimiento) -> texto o None
+)
+
+// directorioConfig regresa el directorio de configuración de finmex: FINMEX_CONFIG_DIR si
+// está definido o el directorio de configuración del usuario
+func directorioConfig() (string, error) {
+	if dir := os.Getenv("FINMEX_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("No se pudo ubicar el directorio de configuración: %v", err)
+	}
+	return filepath.Join(base, "finmex"), nil
+}
+
+// funcionScript es un hook definido en un archivo de script
+type funcionScript struct {
+	Archivo string
+	Funcion starlark.Callable
+}
+
+// Scripts son los hooks cargados desde el directorio de scripts
+type Scripts struct {
+	Directorio string
+	Archivos   []string
+	puntuar    *funcionScript
+	categorias []funcionScript // Se prueban en orden hasta que uno asigne categoría
+}
+
+// predeclaradosScript son los valores disponibles en todos los scripts
+func predeclaradosScript() starlark.StringDict {
+	return starlark.StringDict{
+		"struct":          starlark.NewBuiltin("struct", starlarkstruct.Make),
+		"REGIMEN_ISR":     starlark.String(ISRVigente().Regimen),
+		"RETENCION_ISR":   starlark.Float(calc.TasaRetencionISR(ISRVigente().AñoFiscal)),
+		"IVA":             starlark.Float(IVA),
+		"INFLACION_ANUAL": starlark.Float(InflacionVigente()),
+		"PAGO_MINIMO":     starlark.Float(PAGO_MINIMO),
+	}
+}
+
+// nuevoHiloScript crea el hilo de ejecución de Starlark; lo que imprime un script va al log
+func nuevoHiloScript(nombre string) *starlark.Thread {
+	hilo := &starlark.Thread{
+		Name: nombre,
+		Print: func(h *starlark.Thread, mensaje string) {
+			registro.Info("script", "archivo", h.Name, "mensaje", mensaje)
+		},
+	}
+	hilo.SetMaxExecutionSteps(pasosMaximosScript)
+	return hilo
+}
+
+// CargarScripts ejecuta los archivos .star del directorio de scripts, en orden alfabético, y
+// recoge los hooks que definen. Si el directorio no existe no hay hooks.
+func CargarScripts() (*Scripts, error) {
+	config, err := directorioConfig()
+	if err != nil {
+		return nil, err
+	}
+	s := &Scripts{Directorio: filepath.Join(config, DIRECTORIO_SCRIPTS)}
+
+	archivos, err := filepath.Glob(filepath.Join(s.Directorio, "*.star"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(archivos)
+
+	for _, archivo := range archivos {
+		codigo, err := os.ReadFile(archivo)
+		if err != nil {
+			return nil, fmt.Errorf("Error al leer script %s: %v", archivo, err)
+		}
+		globales, err := starlark.ExecFile(nuevoHiloScript(archivo), archivo, codigo, predeclaradosScript())
+		if err != nil {
+			return nil, fmt.Errorf("Error en script %s: %v", archivo, err)
+		}
+		s.Archivos = append(s.Archivos, archivo)
+		registro.Debug("script cargado", "archivo", archivo)
+
+		if fn, ok := globales[hookPuntuarTarjeta].(starlark.Callable); ok {
+			if s.puntuar != nil {
+				return nil, fmt.Errorf("%s está definida en %s y en %s; deja solo una", hookPuntuarTarjeta, s.puntuar.Archivo, archivo)
+			}
+			s.puntuar = &funcionScript{Archivo: archivo, Funcion: fn}
+		}
+		if fn, ok := globales[hookCategorizar].(starlark.Callable); ok {
+			s.categorias = append(s.categorias, funcionScript{Archivo: archivo, Funcion: fn})
+		}
+	}
+	return s, nil
+}
+
+// Hooks regresa los hooks definidos y el archivo de cada uno
+func (s *Scripts) Hooks() map[string][]string {
+	hooks := map[string][]string{}
+	if s.puntuar != nil {
+		hooks[hookPuntuarTarjeta] = []string{s.puntuar.Archivo}
+	}
+	for _, fn := range s.categorias {
+		hooks[hookCategorizar] = append(hooks[hookCategorizar], fn.Archivo)
+	}
+	return hooks
+}
+
+// TienePuntaje indica si algún script define su propia fórmula de puntaje de tarjetas
+func (s *Scripts) TienePuntaje() bool {
+	return s.puntuar != nil
+}
+
+// PuntuarTarjeta calcula el puntaje de una tarjeta de crédito con la fórmula del script
+func (s *Scripts) PuntuarTarjeta(t TarjetaCredito) (float64, error) {
+	if s.puntuar == nil {
+		return 0, fmt.Errorf("Ningún script define %s", hookPuntuarTarjeta)
+	}
+	resultado, err := starlark.Call(nuevoHiloScript(s.puntuar.Archivo), s.puntuar.Funcion, starlark.Tuple{valorTarjetaCredito(t)}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("Error en %s (%s): %v", hookPuntuarTarjeta, s.puntuar.Archivo, err)
+	}
+	puntaje, ok := starlark.AsFloat(resultado)
+	if !ok {
+		return 0, fmt.Errorf("%s debe regresar un número, regresó %s", hookPuntuarTarjeta, resultado.Type())
+	}
+	return puntaje, nil
+}
+
+// Categorizar pide a los scripts la categoría de un movimiento. Regresa vacío si ningún
+// script la asigna.
+func (s *Scripts) Categorizar(m Movimiento) (string, error) {
+	for _, fn := range s.categorias {
+		resultado, err := starlark.Call(nuevoHiloScript(fn.Archivo), fn.Funcion, starlark.Tuple{valorMovimiento(m)}, nil)
+		if err != nil {
+			return "", fmt.Errorf("Error en %s (%s): %v", hookCategorizar, fn.Archivo, err)
+		}
+		switch v := resultado.(type) {
+		case starlark.NoneType:
+			continue
+		case starlark.String:
+			if string(v) != "" {
+				return string(v), nil
+			}
+		default:
+			return "", fmt.Errorf("%s debe regresar un texto o None, regresó %s", hookCategorizar, resultado.Type())
+		}
+	}
+	return "", nil
+}
+
+// listaScript convierte una lista de textos a Starlark
+func listaScript(textos []string) *starlark.List {
+	valores := make([]starlark.Value, len(textos))
+	for i, t := range textos {
+		valores[i] = starlark.String(t)
+	}
+	return starlark.NewList(valores)
+}
+
+// valorTarjetaCredito expone una tarjeta de crédito al script como struct
+func valorTarjetaCredito(t TarjetaCredito) starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"nombre":         starlark.String(t.Nombre),
+		"banco":          starlark.String(t.Banco),
+		"tasa_interes":   starlark.Float(t.TasaInteres),
+		"cat":            starlark.Float(t.CAT),
+		"comision_anual": starlark.Float(t.ComisionAnual),
+		"limite_credito": starlark.Float(t.LimiteCredito),
+		"cashback":       starlark.Float(t.BeneficiosCashback),
+		"msi":            starlark.Bool(t.MesesSinIntereses),
+		"saldo":          starlark.Float(t.Saldo),
+		"tags":           listaScript(t.Tags),
+	})
+}
+
+// valorMovimiento expone un movimiento al script como struct
+func valorMovimiento(m Movimiento) starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"fecha":       starlark.String(m.Fecha),
+		"descripcion": starlark.String(m.Descripcion),
+		"monto":       starlark.Float(m.Monto),
+		"categoria":   starlark.String(m.Categoria),
+		"tarjeta":     starlark.String(m.Tarjeta),
+	})
+}
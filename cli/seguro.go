@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+
+	"finmex/api"
+)
+
+// Tipos de póliza soportados
+const (
+	SeguroAuto = "auto"
+	SeguroGMM  = "gmm" // Gastos médicos mayores
+)
+
+// Supuestos de siniestralidad por omisión: probabilidad anual de un siniestro y su costo promedio
+var siniestralidadDefault = map[string][2]float64{
+	SeguroAuto: {0.10, 60000},
+	SeguroGMM:  {0.05, 150000},
+}
+
+// Poliza representa una cotización de seguro de auto o de gastos médicos mayores
+type Poliza api.Poliza
+
+// Siniestralidad son los supuestos con los que se calcula el costo esperado de una póliza
+type Siniestralidad struct {
+	Probabilidad  float64 // Probabilidad de al menos un siniestro al año
+	CostoPromedio float64
+}
+
+// ValidarTipoSeguro verifica que el tipo de póliza sea uno de los soportados
+func ValidarTipoSeguro(tipo string) error {
+	if _, ok := siniestralidadDefault[tipo]; !ok {
+		return fmt.Errorf("Tipo de seguro no soportado: %s (usa auto o gmm)", tipo)
+	}
+	return nil
+}
+
+// SiniestralidadDefault regresa los supuestos por omisión para un tipo de póliza
+func SiniestralidadDefault(tipo string) Siniestralidad {
+	s := siniestralidadDefault[tipo]
+	return Siniestralidad{Probabilidad: s[0], CostoPromedio: s[1]}
+}
+
+// PagoDeBolsa regresa lo que paga el asegurado por un siniestro del monto dado: el deducible,
+// el coaseguro sobre el resto (hasta su tope) y lo que exceda la suma asegurada
+func (p Poliza) PagoDeBolsa(gasto float64) float64 {
+	return p.Desglosar(gasto).Total()
+}
+
+// CostoEsperado regresa el costo anual esperado de la póliza: prima más el pago de bolsa
+// ponderado por la probabilidad de siniestro
+func (p Poliza) CostoEsperado(s Siniestralidad) float64 {
+	return p.PrimaAnual + s.Probabilidad*p.PagoDeBolsa(s.CostoPromedio)
+}
+
+// DesglosePago separa lo que paga el asegurado por un siniestro
+type DesglosePago struct {
+	Deducible   float64
+	Coaseguro   float64
+	Excedente   float64 // Lo que rebasa la suma asegurada
+	Aseguradora float64
+}
+
+// Total regresa lo que sale de la bolsa del asegurado
+func (d DesglosePago) Total() float64 {
+	return d.Deducible + d.Coaseguro + d.Excedente
+}
+
+// Desglosar reparte un gasto entre el asegurado y la aseguradora
+func (p Poliza) Desglosar(gasto float64) DesglosePago {
+	d := DesglosePago{Deducible: math.Min(gasto, p.Deducible)}
+	cubierto := gasto
+	if p.SumaAsegurada > 0 {
+		cubierto = math.Min(gasto, p.SumaAsegurada)
+	}
+	d.Excedente = gasto - cubierto
+	d.Coaseguro = math.Max(0, cubierto-d.Deducible) * p.Coaseguro
+	if p.TopeCoaseguro > 0 {
+		d.Coaseguro = math.Min(d.Coaseguro, p.TopeCoaseguro)
+	}
+	d.Aseguradora = gasto - d.Total()
+	return d
+}
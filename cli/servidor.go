@@ -0,0 +1,502 @@
+package cli
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"finmex/calc"
+)
+
+// DIRECCION_SERVIDOR es donde escucha finmex serve si no se indica otra. Solo acepta
+// conexiones de la misma máquina.
+const DIRECCION_SERVIDOR = "127.0.0.1:8080"
+
+// maximoCuerpoAPI limita el tamaño de los cuerpos JSON que acepta el servidor
+const maximoCuerpoAPI = 1 << 20
+
+// errorAPI es el cuerpo de las respuestas de error del servidor, con los mismos campos
+// que las respuestas de error del daemon
+type errorAPI struct {
+	Error   string      `json:"error"`
+	Codigo  CodigoError `json:"codigo,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+// Servidor expone las tarjetas y los cálculos de finmex como una API HTTP con JSON
+type Servidor struct {
+	mu     sync.Mutex // Serializa el acceso a las tarjetas; hasta una lectura puede guardar datos migrados
+	token  string     // Si no está vacío, se exige en el encabezado Authorization: Bearer
+	origen string     // Origen permitido para CORS; vacío desactiva CORS
+}
+
+// NuevoServidor crea el servidor con el token y el origen CORS dados
+func NuevoServidor(token, origen string) *Servidor {
+	return &Servidor{token: token, origen: origen}
+}
+
+// Handler regresa las rutas de la API
+func (s *Servidor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/salud", s.salud)
+	mux.HandleFunc("GET /api/tarjetas", s.listarTarjetas)
+	mux.HandleFunc("GET /api/debito", s.listarDebito)
+	mux.HandleFunc("POST /api/debito", s.agregarDebito)
+	mux.HandleFunc("GET /api/debito/{nombre}/rendimiento", s.rendimientoDebito)
+	mux.HandleFunc("GET /api/credito", s.listarCredito)
+	mux.HandleFunc("POST /api/credito", s.agregarCredito)
+	mux.HandleFunc("GET /api/credito/{nombre}/costo", s.costoCredito)
+	mux.HandleFunc("GET /api/calcular/rendimiento", s.calcularRendimiento)
+	mux.HandleFunc("GET /api/calcular/credito", s.calcularCredito)
+	mux.HandleFunc("GET /api/comparar/debito", s.compararDebito)
+	mux.HandleFunc("GET /api/comparar/credito", s.compararCredito)
+	return s.middleware(mux)
+}
+
+// middleware registra cada solicitud, agrega los encabezados CORS y revisa el token
+func (s *Servidor) middleware(siguiente http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inicio := time.Now()
+		if s.origen != "" {
+			w.Header().Set("Access-Control-Allow-Origin", s.origen)
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		if s.token != "" {
+			recibido := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(recibido), []byte(s.token)) != 1 {
+				responderJSON(w, http.StatusUnauthorized, errorAPI{Error: "Token inválido o ausente", Message: "Invalid or missing token"})
+				return
+			}
+		}
+		siguiente.ServeHTTP(w, r)
+		registro.Debug("solicitud HTTP", "metodo", r.Method, "ruta", r.URL.Path, "duracion", time.Since(inicio))
+	})
+}
+
+func (s *Servidor) salud(w http.ResponseWriter, r *http.Request) {
+	responderJSON(w, http.StatusOK, EstadoServidor{
+		Version:   version,
+		Perfil:    perfilActivo,
+		Inflacion: InflacionVigente(),
+		ISR:       ISRVigente().Descripcion(),
+	})
+}
+
+func (s *Servidor) listarTarjetas(w http.ResponseWriter, r *http.Request) {
+	tarjetas, err := s.cargarTarjetas()
+	if err != nil {
+		responderError(w, fmt.Errorf("Error al cargar tarjetas: %w", err))
+		return
+	}
+	responderJSON(w, http.StatusOK, tarjetas)
+}
+
+func (s *Servidor) listarDebito(w http.ResponseWriter, r *http.Request) {
+	tarjetas, catalogo, err := s.cargarTarjetasCatalogo()
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	filas := []FilaDebito{}
+	for _, t := range tarjetas.Debito {
+		filas = append(filas, filaDebito(t, catalogo))
+	}
+	responderJSON(w, http.StatusOK, filas)
+}
+
+func (s *Servidor) listarCredito(w http.ResponseWriter, r *http.Request) {
+	tarjetas, catalogo, err := s.cargarTarjetasCatalogo()
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	filas := []FilaCredito{}
+	for _, t := range tarjetas.Credito {
+		filas = append(filas, FilaCredito{TarjetaCredito: t, VsMercado: PosicionCredito(t, catalogo).Descripcion()})
+	}
+	responderJSON(w, http.StatusOK, filas)
+}
+
+func (s *Servidor) agregarDebito(w http.ResponseWriter, r *http.Request) {
+	var t TarjetaDebito
+	if err := leerCuerpoJSON(r, &t); err != nil {
+		responderError(w, err)
+		return
+	}
+	s.agregar(w, func(tarjetas *Tarjetas) (interface{}, error) {
+		if err := validarTarjetaDebitoAPI(*tarjetas, &t); err != nil {
+			return nil, err
+		}
+		tarjetas.Debito = append(tarjetas.Debito, t)
+		return t, nil
+	})
+}
+
+func (s *Servidor) agregarCredito(w http.ResponseWriter, r *http.Request) {
+	var t TarjetaCredito
+	if err := leerCuerpoJSON(r, &t); err != nil {
+		responderError(w, err)
+		return
+	}
+	s.agregar(w, func(tarjetas *Tarjetas) (interface{}, error) {
+		if err := validarTarjetaCreditoAPI(*tarjetas, &t); err != nil {
+			return nil, err
+		}
+		tarjetas.Credito = append(tarjetas.Credito, t)
+		return t, nil
+	})
+}
+
+// agregar carga las tarjetas, aplica el cambio y las guarda sin que otra solicitud del
+// servidor escriba en medio. Responde 201 con lo que regresa el cambio.
+func (s *Servidor) agregar(w http.ResponseWriter, cambio func(*Tarjetas) (interface{}, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tarjetas, err := CargarTarjetas()
+	if err != nil {
+		responderError(w, fmt.Errorf("Error al cargar tarjetas: %w", err))
+		return
+	}
+	agregada, err := cambio(&tarjetas)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	if err := GuardarTarjetas(tarjetas); err != nil {
+		responderError(w, fmt.Errorf("Error al guardar tarjeta: %w", err))
+		return
+	}
+	responderJSON(w, http.StatusCreated, agregada)
+}
+
+func (s *Servidor) rendimientoDebito(w http.ResponseWriter, r *http.Request) {
+	tarjetas, err := s.cargarTarjetas()
+	if err != nil {
+		responderError(w, fmt.Errorf("Error al cargar tarjetas: %w", err))
+		return
+	}
+	i, ok := indiceDebito(tarjetas, r.PathValue("nombre"))
+	if !ok {
+		responderError(w, errTarjetaNoEncontrada("debito", r.PathValue("nombre")))
+		return
+	}
+	t := tarjetas.Debito[i]
+	saldo, err := parametroNumero(r, "saldo", t.Saldo, limitesMonto)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	responderJSON(w, http.StatusOK, analisisDebito(t, saldo))
+}
+
+func (s *Servidor) costoCredito(w http.ResponseWriter, r *http.Request) {
+	tarjetas, err := s.cargarTarjetas()
+	if err != nil {
+		responderError(w, fmt.Errorf("Error al cargar tarjetas: %w", err))
+		return
+	}
+	i, ok := indiceCredito(tarjetas, r.PathValue("nombre"))
+	if !ok {
+		responderError(w, errTarjetaNoEncontrada("credito", r.PathValue("nombre")))
+		return
+	}
+	t := tarjetas.Credito[i]
+	deuda, err := parametroNumero(r, "deuda", t.Saldo, limitesMonto)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	a, err := analisisCreditoAPI(r, t, deuda)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	responderJSON(w, http.StatusOK, a)
+}
+
+func (s *Servidor) calcularRendimiento(w http.ResponseWriter, r *http.Request) {
+	var cuenta TarjetaDebito
+	var saldo float64
+	var err error
+	// tramos=25000:0.15,0.08 para las tasas escalonadas; si no, una sola tasa
+	if tramos := r.URL.Query().Get("tramos"); tramos != "" {
+		cuenta.Tramos, err = ParsearTramosRendimiento(tramos)
+	} else {
+		var tasa float64
+		tasa, err = parametroRequerido(r, "tasa", limitesTasa)
+		cuenta.Tramos = calc.TasaUnica(tasa)
+	}
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	if saldo, err = parametroRequerido(r, "saldo", limitesMonto); err != nil {
+		responderError(w, err)
+		return
+	}
+	if cuenta.ComisionAnual, err = parametroNumero(r, "comision", 0, limitesMonto); err != nil {
+		responderError(w, err)
+		return
+	}
+	if cuenta.SaldoMinimo, err = parametroNumero(r, "saldo-minimo", 0, limitesMonto); err != nil {
+		responderError(w, err)
+		return
+	}
+	responderJSON(w, http.StatusOK, analisisDebito(cuenta, saldo))
+}
+
+func (s *Servidor) calcularCredito(w http.ResponseWriter, r *http.Request) {
+	var t TarjetaCredito
+	var deuda float64
+	var err error
+	if t.TasaInteres, err = parametroRequerido(r, "tasa", limitesTasa); err != nil {
+		responderError(w, err)
+		return
+	}
+	if deuda, err = parametroRequerido(r, "deuda", limitesMonto); err != nil {
+		responderError(w, err)
+		return
+	}
+	if t.ComisionAnual, err = parametroNumero(r, "comision", 0, limitesMonto); err != nil {
+		responderError(w, err)
+		return
+	}
+	a, err := analisisCreditoAPI(r, t, deuda)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	responderJSON(w, http.StatusOK, a)
+}
+
+// compararDebito compara el rendimiento real de las cuentas y cajas de ahorro con el mismo
+// saldo, como comparar debito
+func (s *Servidor) compararDebito(w http.ResponseWriter, r *http.Request) {
+	tarjetas, err := s.cargarTarjetas()
+	if err != nil {
+		responderError(w, fmt.Errorf("Error al cargar tarjetas: %w", err))
+		return
+	}
+	saldo, err := parametroRequerido(r, "saldo", limitesMonto)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	resultados := []ResultadoComparacionDebito{}
+	for _, t := range tarjetas.Debito {
+		resultados = append(resultados, resultadoComparacionDebito(t, saldo))
+	}
+	for _, caja := range tarjetas.Cajas {
+		resultados = append(resultados, resultadoComparacionDebito(caja.ComoDebito(), saldo))
+	}
+	responderJSON(w, http.StatusOK, resultados)
+}
+
+// compararCredito compara lo que cuesta liquidar la misma deuda con cada tarjeta de crédito,
+// como comparar credito
+func (s *Servidor) compararCredito(w http.ResponseWriter, r *http.Request) {
+	tarjetas, err := s.cargarTarjetas()
+	if err != nil {
+		responderError(w, fmt.Errorf("Error al cargar tarjetas: %w", err))
+		return
+	}
+	deuda, err := parametroRequerido(r, "deuda", limitesMonto)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	pago, err := parametroRequerido(r, "pago", limitesMonto)
+	if err != nil {
+		responderError(w, err)
+		return
+	}
+	frecuencia, err := calc.ParsearFrecuencia(r.URL.Query().Get("frecuencia"))
+	if err != nil {
+		responderError(w, errDatosInvalidos(err.Error(), fmt.Sprintf("Invalid payment frequency '%s'", r.URL.Query().Get("frecuencia"))))
+		return
+	}
+	resultados := []ResultadoComparacionCredito{}
+	for _, t := range tarjetas.Credito {
+		resultados = append(resultados, resultadoComparacionCredito(t, deuda, pago, frecuencia))
+	}
+	responderJSON(w, http.StatusOK, resultados)
+}
+
+// analisisCreditoAPI analiza la deuda con la frecuencia y el pago de la consulta. Sin pago
+// se usa el mínimo, y un pago menor al mínimo se ajusta como en credito analizar.
+func analisisCreditoAPI(r *http.Request, t TarjetaCredito, deuda float64) (AnalisisCredito, error) {
+	if deuda <= 0 {
+		return AnalisisCredito{}, errDatosInvalidos("Indica la deuda con el parámetro deuda", "Set the debt with the deuda parameter")
+	}
+	frecuencia, err := calc.ParsearFrecuencia(r.URL.Query().Get("frecuencia"))
+	if err != nil {
+		return AnalisisCredito{}, errDatosInvalidos(err.Error(), fmt.Sprintf("Invalid payment frequency '%s'", r.URL.Query().Get("frecuencia")))
+	}
+	pago, err := parametroNumero(r, "pago", 0, limitesMonto)
+	if err != nil {
+		return AnalisisCredito{}, err
+	}
+	if minimo := calc.PagoMinimoPeriodo(t, deuda, frecuencia); pago < minimo {
+		pago = minimo
+	}
+	_, pagos, _ := calc.CostoCreditoFrecuencia(t, deuda, pago, frecuencia)
+	return analisisCredito(t, deuda, pago, frecuencia, calc.CalendarioPagos(time.Now(), frecuencia, pagos)), nil
+}
+
+// validarTarjetaDebitoAPI revisa una cuenta recibida por la API con los mismos límites que
+// la captura en la terminal
+func validarTarjetaDebitoAPI(tarjetas Tarjetas, t *TarjetaDebito) error {
+	if err := validarNombreAPI(t.Nombre, t.Banco); err != nil {
+		return err
+	}
+	t.Nombre, t.Banco = strings.TrimSpace(t.Nombre), strings.TrimSpace(t.Banco)
+	if _, ok := indiceDebito(tarjetas, t.Nombre); ok {
+		return errDatosInvalidos(fmt.Sprintf("Ya existe una tarjeta de débito llamada '%s'", t.Nombre),
+			fmt.Sprintf("A debit card named '%s' already exists", t.Nombre))
+	}
+	t.MigrarTramos()
+	tasas := map[string]float64{}
+	montos := map[string]float64{
+		"saldo_minimo": t.SaldoMinimo, "comision_anual": t.ComisionAnual, "comision_inactividad": t.ComisionInactividad, "saldo": t.Saldo,
+	}
+	for i, tramo := range t.Tramos {
+		tasas[fmt.Sprintf("tramos[%d].tasa", i)] = tramo.Tasa
+		montos[fmt.Sprintf("tramos[%d].monto_hasta", i)] = tramo.Hasta
+	}
+	return validarCamposAPI(tasas, montos)
+}
+
+// validarTarjetaCreditoAPI revisa una tarjeta de crédito recibida por la API
+func validarTarjetaCreditoAPI(tarjetas Tarjetas, t *TarjetaCredito) error {
+	if err := validarNombreAPI(t.Nombre, t.Banco); err != nil {
+		return err
+	}
+	t.Nombre, t.Banco = strings.TrimSpace(t.Nombre), strings.TrimSpace(t.Banco)
+	if _, ok := indiceCredito(tarjetas, t.Nombre); ok {
+		return errDatosInvalidos(fmt.Sprintf("Ya existe una tarjeta de crédito llamada '%s'", t.Nombre),
+			fmt.Sprintf("A credit card named '%s' already exists", t.Nombre))
+	}
+	return validarCamposAPI(map[string]float64{
+		"tasa_interes": t.TasaInteres, "cat": t.CAT, "beneficios_cashback": t.BeneficiosCashback,
+	}, map[string]float64{
+		"comision_anual": t.ComisionAnual, "limite_credito": t.LimiteCredito, "saldo": t.Saldo,
+		"facturacion_para_condonar": t.FacturacionParaCondonar,
+	})
+}
+
+func validarNombreAPI(nombre, banco string) error {
+	if strings.TrimSpace(nombre) == "" || strings.TrimSpace(banco) == "" {
+		return errDatosInvalidos("La tarjeta necesita nombre y banco", "The card needs a nombre and a banco")
+	}
+	return nil
+}
+
+// validarCamposAPI revisa tasas y montos por el nombre de su campo JSON
+func validarCamposAPI(tasas, montos map[string]float64) error {
+	for campo, valor := range tasas {
+		if err := validarDecimal(valor, limitesTasa); err != nil {
+			return fmt.Errorf("%s: %w", campo, err)
+		}
+	}
+	for campo, valor := range montos {
+		if err := validarLimites(valor, limitesMonto); err != nil {
+			return fmt.Errorf("%s: %w", campo, err)
+		}
+	}
+	return nil
+}
+
+// cargarTarjetas carga las tarjetas sin que otra solicitud del servidor escriba al mismo tiempo
+func (s *Servidor) cargarTarjetas() (Tarjetas, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return CargarTarjetas()
+}
+
+// cargarTarjetasCatalogo carga las tarjetas y el catálogo para las respuestas que comparan
+// contra el mercado
+func (s *Servidor) cargarTarjetasCatalogo() (Tarjetas, Catalogo, error) {
+	tarjetas, err := s.cargarTarjetas()
+	if err != nil {
+		return Tarjetas{}, Catalogo{}, fmt.Errorf("Error al cargar tarjetas: %w", err)
+	}
+	catalogo, err := CargarCatalogo()
+	if err != nil {
+		return Tarjetas{}, Catalogo{}, err
+	}
+	return tarjetas, catalogo, nil
+}
+
+// parametroNumero lee un número de la consulta; si no viene regresa el valor por defecto
+func parametroNumero(r *http.Request, nombre string, porDefecto float64, limites LimitesNumero) (float64, error) {
+	texto := r.URL.Query().Get(nombre)
+	if texto == "" {
+		return porDefecto, nil
+	}
+	valor, err := parsearDecimal(texto, limites)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", nombre, err)
+	}
+	return valor, nil
+}
+
+// parametroRequerido lee un número obligatorio de la consulta
+func parametroRequerido(r *http.Request, nombre string, limites LimitesNumero) (float64, error) {
+	if r.URL.Query().Get(nombre) == "" {
+		return 0, errDatosInvalidos(fmt.Sprintf("Falta el parámetro %s", nombre), fmt.Sprintf("Missing parameter %s", nombre))
+	}
+	return parametroNumero(r, nombre, 0, limites)
+}
+
+// leerCuerpoJSON decodifica el cuerpo de la solicitud sin aceptar campos desconocidos, para
+// que un error de captura no se pierda en silencio
+func leerCuerpoJSON(r *http.Request, destino interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maximoCuerpoAPI))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(destino); err != nil {
+		return errDatosInvalidos(fmt.Sprintf("JSON inválido: %v", err), fmt.Sprintf("Invalid JSON: %v", err))
+	}
+	return nil
+}
+
+// responderError responde el error con el estado HTTP que corresponde a su código
+func responderError(w http.ResponseWriter, err error) {
+	cuerpo := errorAPI{Error: err.Error()}
+	estado := http.StatusInternalServerError
+	var e *ErrorFinmex
+	if errors.As(err, &e) {
+		cuerpo.Codigo = e.Codigo
+		cuerpo.Message = e.Localizado("en")
+		switch e.Codigo {
+		case CodigoDatosInvalidos:
+			estado = http.StatusBadRequest
+		case CodigoTarjetaNoEncontrada:
+			estado = http.StatusNotFound
+		}
+	}
+	if estado == http.StatusInternalServerError {
+		registro.Warn("error al atender solicitud HTTP", "error", err)
+	}
+	responderJSON(w, estado, cuerpo)
+}
+
+func responderJSON(w http.ResponseWriter, estado int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(estado)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		registro.Warn("no se pudo responder al cliente HTTP", "error", err)
+	}
+}
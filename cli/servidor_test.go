@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Con go test -race revisa que las solicitudes simultáneas no compartan el almacén sin orden
+func TestServidorSolicitudesSimultaneas(t *testing.T) {
+	conAlmacenTemporal(t)
+	almacen = nil // Que lo abra la primera solicitud que lo necesite
+	servidor := httptest.NewServer(NuevoServidor("", "").Handler())
+	t.Cleanup(servidor.Close)
+
+	const cuentas = 10
+	rutas := []string{"/api/tarjetas", "/api/debito", "/api/comparar/debito?saldo=1000"}
+	var grupo sync.WaitGroup
+	errores := make(chan string, cuentas*(len(rutas)+1))
+	for i := 0; i < cuentas; i++ {
+		grupo.Add(1)
+		go func(i int) {
+			defer grupo.Done()
+			cuerpo := fmt.Sprintf(`{"nombre": "Cuenta %d", "banco": "Banco"}`, i)
+			r, err := http.Post(servidor.URL+"/api/debito", "application/json", strings.NewReader(cuerpo))
+			if err != nil {
+				errores <- err.Error()
+				return
+			}
+			r.Body.Close()
+			if r.StatusCode != http.StatusCreated {
+				errores <- fmt.Sprintf("POST cuenta %d: estado %d", i, r.StatusCode)
+			}
+		}(i)
+		for _, ruta := range rutas {
+			grupo.Add(1)
+			go func(ruta string) {
+				defer grupo.Done()
+				r, err := http.Get(servidor.URL + ruta)
+				if err != nil {
+					errores <- err.Error()
+					return
+				}
+				r.Body.Close()
+				if r.StatusCode != http.StatusOK {
+					errores <- fmt.Sprintf("GET %s: estado %d", ruta, r.StatusCode)
+				}
+			}(ruta)
+		}
+	}
+	grupo.Wait()
+	close(errores)
+	for e := range errores {
+		t.Error(e)
+	}
+
+	tarjetas, err := CargarTarjetas()
+	if err != nil || len(tarjetas.Debito) != cuentas {
+		t.Errorf("se esperaban %d cuentas guardadas: %d, %v", cuentas, len(tarjetas.Debito), err)
+	}
+}
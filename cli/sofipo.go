@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"finmex/api"
+	"finmex/calc"
+)
+
+// COBERTURA_PROSOFIPO_UDIS es lo que protege el PROSOFIPO por persona en cada SOFIPO si la
+// institución quiebra
+const COBERTURA_PROSOFIPO_UDIS = 25000
+
+// COBERTURA_IPAB_UDIS es lo que protege el IPAB por persona en cada banco, para comparar las
+// SOFIPOs contra las cuentas bancarias
+const COBERTURA_IPAB_UDIS = 400000
+
+// CuentaSofipo es una cuenta o inversión en una Sociedad Financiera Popular (Nu, Stori,
+// Klar, SuperTasas...)
+type CuentaSofipo api.CuentaSofipo
+
+// PorcionSofipo es la parte del saldo que gana la tasa de un tramo
+type PorcionSofipo struct {
+	Desde float64 `json:"desde"`
+	Hasta float64 `json:"hasta"`
+	Tasa  float64 `json:"tasa"`
+	Monto float64 `json:"monto"`
+}
+
+// RendimientoSofipo desglosa lo que deja una cuenta de SOFIPO en un año
+type RendimientoSofipo struct {
+	TasaEfectiva float64         `json:"tasa_efectiva"` // Promedio de los tramos ponderado por saldo
+	Porciones    []PorcionSofipo `json:"porciones"`
+	Bruto        float64         `json:"bruto"`
+	Impuestos    float64         `json:"impuestos"`
+	Neto         float64         `json:"neto"`
+	TasaNeta     float64         `json:"tasa_neta"`
+	TasaReal     float64         `json:"tasa_real"`
+}
+
+// FilaSofipo es una cuenta con su rendimiento en la salida de sofipo listar y analizar
+// con --output
+type FilaSofipo struct {
+	CuentaSofipo
+	RendimientoSofipo
+	Inflacion float64 `json:"inflacion"`
+}
+
+// ParsearTramosSofipo lee una lista de tramos plazo:tasa[:hasta] separados por comas, como
+// "0:0.15:25000,0:0.09,28:0.10"
+func ParsearTramosSofipo(texto string) ([]TramoSofipo, error) {
+	var tramos []TramoSofipo
+	for _, parte := range ParsearLista(texto) {
+		campos := strings.Split(parte, ":")
+		if len(campos) < 2 || len(campos) > 3 {
+			return nil, errDatosInvalidos(
+				fmt.Sprintf("Tramo inválido '%s': usa plazo:tasa o plazo:tasa:hasta", parte),
+				fmt.Sprintf("Invalid tier '%s': use term:rate or term:rate:upto", parte))
+		}
+		plazo, err := ParsearNumero(campos[0])
+		if err != nil || plazo < 0 || plazo > 365*30 || plazo != float64(int(plazo)) {
+			return nil, errDatosInvalidos(
+				fmt.Sprintf("Plazo inválido en el tramo '%s': debe ser un número de días", parte),
+				fmt.Sprintf("Invalid term in tier '%s': must be a number of days", parte))
+		}
+		tramo := TramoSofipo{PlazoDias: int(plazo)}
+		if tramo.Tasa, err = parsearDecimal(campos[1], limitesTasa); err != nil {
+			return nil, fmt.Errorf("Tramo '%s': %w", parte, err)
+		}
+		if len(campos) == 3 {
+			if tramo.Hasta, err = ParsearNumero(campos[2]); err == nil {
+				err = validarLimites(tramo.Hasta, limitesMonto)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Tramo '%s': %w", parte, err)
+			}
+		}
+		tramos = append(tramos, tramo)
+	}
+	if len(tramos) == 0 {
+		return nil, errDatosInvalidos("Se necesita al menos un tramo de tasa", "At least one rate tier is required")
+	}
+	return tramos, nil
+}
+
+// tramosPlazo regresa los tramos del mayor plazo que no pasa del elegido, ordenados por
+// monto; el tramo sin tope queda al final
+func (s CuentaSofipo) tramosPlazo() []TramoSofipo {
+	plazo := -1
+	for _, t := range s.Tramos {
+		if t.PlazoDias <= s.PlazoDias && t.PlazoDias > plazo {
+			plazo = t.PlazoDias
+		}
+	}
+	var tramos []TramoSofipo
+	for _, t := range s.Tramos {
+		if t.PlazoDias == plazo {
+			tramos = append(tramos, t)
+		}
+	}
+	sort.SliceStable(tramos, func(i, j int) bool {
+		if tramos[i].Hasta == 0 || tramos[j].Hasta == 0 {
+			return tramos[j].Hasta == 0 && tramos[i].Hasta != 0
+		}
+		return tramos[i].Hasta < tramos[j].Hasta
+	})
+	return tramos
+}
+
+// Rendimiento calcula el rendimiento anual del saldo repartido entre los tramos del plazo
+// elegido. El saldo que pasa del último tope no gana nada. El ISR y la tasa real se
+// calculan igual que en las cuentas de débito.
+func (s CuentaSofipo) Rendimiento(inflacion float64) RendimientoSofipo {
+	var r RendimientoSofipo
+	if s.Saldo <= 0 {
+		return r
+	}
+
+	desde := 0.0
+	for _, t := range s.tramosPlazo() {
+		if desde >= s.Saldo {
+			break
+		}
+		hasta := s.Saldo
+		if t.Hasta > 0 && t.Hasta < hasta {
+			hasta = t.Hasta
+		}
+		if hasta <= desde {
+			continue
+		}
+		r.Porciones = append(r.Porciones, PorcionSofipo{Desde: desde, Hasta: hasta, Tasa: t.Tasa, Monto: hasta - desde})
+		r.Bruto += (hasta - desde) * t.Tasa
+		desde = hasta
+	}
+
+	r.TasaEfectiva = r.Bruto / s.Saldo
+	r.Impuestos = s.Saldo * ISRVigente().TasaImpuesto(r.TasaEfectiva, inflacion)
+	r.Neto = r.Bruto - r.Impuestos
+	r.TasaNeta = r.Neto / s.Saldo
+	r.TasaReal = r.TasaNeta - inflacion
+	return r
+}
+
+// CoberturaPROSOFIPO regresa la cobertura del PROSOFIPO en pesos con el valor vigente de
+// la UDI
+func CoberturaPROSOFIPO() float64 {
+	valor, _ := ValorUDIVigente()
+	return calc.UDIsAPesos(COBERTURA_PROSOFIPO_UDIS, valor)
+}
+
+// CoberturaIPAB regresa la cobertura del IPAB en pesos con el valor vigente de la UDI
+func CoberturaIPAB() float64 {
+	valor, _ := ValorUDIVigente()
+	return calc.UDIsAPesos(COBERTURA_IPAB_UDIS, valor)
+}
+
+// ExcedenteSofipo es el saldo en una institución que el PROSOFIPO no protege
+type ExcedenteSofipo struct {
+	Institucion string  `json:"institucion"`
+	Saldo       float64 `json:"saldo"`
+	Cobertura   float64 `json:"cobertura"`
+	Excedente   float64 `json:"excedente"`
+}
+
+// ExcedentesPROSOFIPO suma los saldos por institución, porque la cobertura es por persona
+// en cada SOFIPO, y regresa las que pasan de la cobertura
+func ExcedentesPROSOFIPO(cuentas []CuentaSofipo) []ExcedenteSofipo {
+	cobertura := CoberturaPROSOFIPO()
+	var orden []string
+	saldos := map[string]float64{}
+	nombres := map[string]string{}
+	for _, s := range cuentas {
+		clave := normalizarClave(s.Institucion)
+		if _, ok := saldos[clave]; !ok {
+			orden = append(orden, clave)
+			nombres[clave] = s.Institucion
+		}
+		saldos[clave] += s.Saldo
+	}
+
+	var excedentes []ExcedenteSofipo
+	for _, clave := range orden {
+		if saldos[clave] > cobertura {
+			excedentes = append(excedentes, ExcedenteSofipo{nombres[clave], saldos[clave], cobertura, saldos[clave] - cobertura})
+		}
+	}
+	return excedentes
+}
+
+// avisarCoberturaSofipo muestra un aviso por cada institución con saldo sin proteger
+func avisarCoberturaSofipo(cuentas []CuentaSofipo) {
+	for _, e := range ExcedentesPROSOFIPO(cuentas) {
+		fmt.Printf("AVISO: Tu saldo en %s ($%.2f) excede la cobertura del PROSOFIPO de %d UDIS ($%.2f); $%.2f no está protegido\n",
+			e.Institucion, e.Saldo, COBERTURA_PROSOFIPO_UDIS, e.Cobertura, e.Excedente)
+	}
+}
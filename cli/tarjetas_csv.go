@@ -0,0 +1,374 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnasCSVTarjetas son las columnas de exportar --formato csv e importar. Las que no
+// aplican al tipo de tarjeta quedan vacías; los planes de MSI y los demás productos solo
+// viajan en el JSON. tasa_rendimiento lleva una tasa sola o los tramos hasta:tasa separados
+// por comas.
+var ColumnasCSVTarjetas = []string{
+	"tipo", "nombre", "banco",
+	"tasa_rendimiento", "saldo_minimo", "comision_inactividad",
+	"tasa_interes", "cat", "limite_credito", "beneficios_cashback", "meses_sin_intereses", "fecha_anualidad",
+	"comision_anual", "saldo", "tags", "moneda", "facturacion_para_condonar",
+}
+
+// columnasCSVObligatorias deben estar en el encabezado de un archivo a importar
+var columnasCSVObligatorias = []string{"tipo", "nombre"}
+
+// RechazoCSV es una fila que no se pudo importar
+type RechazoCSV struct {
+	Fila   int    `json:"fila"` // Número de línea en el archivo, contando el encabezado
+	Nombre string `json:"nombre,omitempty"`
+	Motivo string `json:"motivo"`
+}
+
+// EscribirTarjetasCSV escribe las tarjetas de débito y de crédito, una por renglón
+func EscribirTarjetasCSV(w io.Writer, tarjetas Tarjetas) error {
+	escritor := csv.NewWriter(w)
+	escritor.Write(ColumnasCSVTarjetas)
+
+	numero := func(v float64) string {
+		if v == 0 {
+			return ""
+		}
+		return formatearNumero(v)
+	}
+	// Una sola tasa se escribe como número y las escalonadas como tramos hasta:tasa
+	tasa := func(t TarjetaDebito) string {
+		if tramos := t.TramosOrdenados(); tramosEscalonados(tramos) {
+			return FormatearTramos(tramos)
+		}
+		return numero(t.TasaPonderada(0))
+	}
+	for _, t := range tarjetas.Debito {
+		escritor.Write([]string{
+			"debito", t.Nombre, t.Banco,
+			tasa(t), numero(t.SaldoMinimo), numero(t.ComisionInactividad),
+			"", "", "", "", "", "",
+			numero(t.ComisionAnual), numero(t.Saldo), strings.Join(t.Tags, ", "), t.Moneda, "",
+		})
+	}
+	for _, t := range tarjetas.Credito {
+		msi := "no"
+		if t.MesesSinIntereses {
+			msi = "si"
+		}
+		escritor.Write([]string{
+			"credito", t.Nombre, t.Banco,
+			"", "", "",
+			numero(t.TasaInteres), numero(t.CAT), numero(t.LimiteCredito), numero(t.BeneficiosCashback), msi, t.FechaAnualidad,
+			numero(t.ComisionAnual), numero(t.Saldo), strings.Join(t.Tags, ", "), "", numero(t.FacturacionParaCondonar),
+		})
+	}
+	escritor.Flush()
+	return escritor.Error()
+}
+
+// TarjetasCSV es lo que se leyó de un archivo CSV de tarjetas
+type TarjetasCSV struct {
+	Debito    []TarjetaDebito
+	Credito   []TarjetaCredito
+	Rechazos  []RechazoCSV
+	Renglones int             // Filas de datos leídas, sin el encabezado
+	Columnas  map[string]bool // Columnas del encabezado
+}
+
+// LeerTarjetasCSV lee un CSV de tarjetas. El encabezado debe tener al menos tipo y nombre y
+// solo columnas conocidas, en cualquier orden; acepta coma o punto y coma como separador,
+// que es lo que usa Excel en español. Las filas con datos inválidos o nombres repetidos se
+// reportan en Rechazos y no detienen la lectura.
+func LeerTarjetasCSV(r io.Reader) (TarjetasCSV, error) {
+	var resultado TarjetasCSV
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return resultado, err
+	}
+	texto := strings.TrimPrefix(string(data), "\ufeff")
+
+	lector := csv.NewReader(strings.NewReader(texto))
+	primera, _, _ := strings.Cut(texto, "\n")
+	if strings.Count(primera, ";") > strings.Count(primera, ",") {
+		lector.Comma = ';'
+	}
+	lector.FieldsPerRecord = -1
+	lector.TrimLeadingSpace = true
+
+	encabezado, err := lector.Read()
+	if err == io.EOF {
+		return resultado, errDatosInvalidos("El archivo CSV está vacío", "The CSV file is empty")
+	}
+	if err != nil {
+		return resultado, errDatosInvalidos(fmt.Sprintf("Encabezado CSV inválido: %v", err), fmt.Sprintf("Invalid CSV header: %v", err))
+	}
+	indices, err := indicesColumnasCSV(encabezado)
+	if err != nil {
+		return resultado, err
+	}
+	resultado.Columnas = map[string]bool{}
+	for c := range indices {
+		resultado.Columnas[c] = true
+	}
+
+	vistos := map[string]int{}
+	for {
+		registro, err := lector.Read()
+		if err == io.EOF {
+			break
+		}
+		var formato *csv.ParseError
+		if errors.As(err, &formato) {
+			resultado.Rechazos = append(resultado.Rechazos, RechazoCSV{Fila: formato.Line, Motivo: formato.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return resultado, err
+		}
+		linea, _ := lector.FieldPos(0)
+		if renglonVacio(registro) {
+			continue
+		}
+		resultado.Renglones++
+
+		campo := func(nombre string) string {
+			if i, ok := indices[nombre]; ok && i < len(registro) {
+				return strings.TrimSpace(registro[i])
+			}
+			return ""
+		}
+		nombre := campo("nombre")
+		rechazar := func(motivo string) {
+			resultado.Rechazos = append(resultado.Rechazos, RechazoCSV{Fila: linea, Nombre: nombre, Motivo: motivo})
+		}
+		if nombre == "" {
+			rechazar("falta el nombre")
+			continue
+		}
+		tipo := normalizarClave(campo("tipo"))
+		switch tipo {
+		case "débito":
+			tipo = "debito"
+		case "crédito":
+			tipo = "credito"
+		}
+		clave := tipo + "/" + normalizarClave(nombre)
+		if anterior, ok := vistos[clave]; ok {
+			rechazar(fmt.Sprintf("el nombre ya aparece en la fila %d", anterior))
+			continue
+		}
+
+		fila := filaCSV{campo: campo}
+		switch tipo {
+		case "debito":
+			t := TarjetaDebito{
+				Nombre:              nombre,
+				Banco:               campo("banco"),
+				Tramos:              fila.tramos("tasa_rendimiento"),
+				SaldoMinimo:         fila.numero("saldo_minimo", limitesMonto),
+				ComisionInactividad: fila.numero("comision_inactividad", limitesMonto),
+				ComisionAnual:       fila.numero("comision_anual", limitesMonto),
+				Saldo:               fila.numero("saldo", limitesMonto),
+				Tags:                ParsearLista(campo("tags")),
+			}
+			moneda, err := ValidarMoneda(campo("moneda"))
+			if err != nil && fila.err == nil {
+				fila.err = err
+			}
+			if moneda != MonedaMXN {
+				t.Moneda = moneda
+			}
+			if fila.err != nil {
+				rechazar(fila.err.Error())
+				continue
+			}
+			resultado.Debito = append(resultado.Debito, t)
+		case "credito":
+			t := TarjetaCredito{
+				Nombre:                  nombre,
+				Banco:                   campo("banco"),
+				TasaInteres:             fila.numero("tasa_interes", limitesTasa),
+				CAT:                     fila.numero("cat", limitesTasa),
+				LimiteCredito:           fila.numero("limite_credito", limitesMonto),
+				BeneficiosCashback:      fila.numero("beneficios_cashback", limitesFraccion),
+				MesesSinIntereses:       fila.siNo("meses_sin_intereses"),
+				FechaAnualidad:          fila.fecha("fecha_anualidad"),
+				ComisionAnual:           fila.numero("comision_anual", limitesMonto),
+				Saldo:                   fila.numero("saldo", limitesMonto),
+				Tags:                    ParsearLista(campo("tags")),
+				FacturacionParaCondonar: fila.numero("facturacion_para_condonar", limitesMonto),
+			}
+			if fila.err != nil {
+				rechazar(fila.err.Error())
+				continue
+			}
+			resultado.Credito = append(resultado.Credito, t)
+		default:
+			rechazar(fmt.Sprintf("tipo '%s' inválido (usa debito o credito)", campo("tipo")))
+			continue
+		}
+		vistos[clave] = linea
+	}
+	return resultado, nil
+}
+
+// indicesColumnasCSV ubica cada columna del encabezado y rechaza las desconocidas, las
+// repetidas y la falta de las obligatorias
+func indicesColumnasCSV(encabezado []string) (map[string]int, error) {
+	conocidas := map[string]bool{}
+	for _, c := range ColumnasCSVTarjetas {
+		conocidas[c] = true
+	}
+	indices := map[string]int{}
+	for i, c := range encabezado {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !conocidas[c] {
+			return nil, errDatosInvalidos(
+				fmt.Sprintf("Columna desconocida '%s'; las columnas válidas son: %s", c, strings.Join(ColumnasCSVTarjetas, ", ")),
+				fmt.Sprintf("Unknown column '%s'; valid columns are: %s", c, strings.Join(ColumnasCSVTarjetas, ", ")))
+		}
+		if _, ok := indices[c]; ok {
+			return nil, errDatosInvalidos(fmt.Sprintf("La columna '%s' está repetida", c), fmt.Sprintf("Column '%s' is repeated", c))
+		}
+		indices[c] = i
+	}
+	for _, c := range columnasCSVObligatorias {
+		if _, ok := indices[c]; !ok {
+			return nil, errDatosInvalidos(fmt.Sprintf("Falta la columna obligatoria '%s'", c), fmt.Sprintf("Missing required column '%s'", c))
+		}
+	}
+	return indices, nil
+}
+
+func renglonVacio(registro []string) bool {
+	for _, v := range registro {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// filaCSV convierte los campos de una fila y se queda con el primer error
+type filaCSV struct {
+	campo func(string) string
+	err   error
+}
+
+func (f *filaCSV) numero(columna string, limites LimitesNumero) float64 {
+	texto := f.campo(columna)
+	if texto == "" || f.err != nil {
+		return 0
+	}
+	valor, err := parsearDecimal(texto, limites)
+	if err != nil {
+		f.err = fmt.Errorf("%s: %v", columna, err)
+	}
+	return valor
+}
+
+// tramos lee una tasa sola o tramos hasta:tasa; vacía es una cuenta sin rendimiento
+func (f *filaCSV) tramos(columna string) []TramoRendimiento {
+	texto := f.campo(columna)
+	if texto == "" || f.err != nil {
+		return nil
+	}
+	tramos, err := ParsearTramosRendimiento(texto)
+	if err != nil {
+		f.err = fmt.Errorf("%s: %v", columna, err)
+	}
+	return tramos
+}
+
+func (f *filaCSV) siNo(columna string) bool {
+	texto := strings.ToLower(f.campo(columna))
+	if texto == "" || f.err != nil {
+		return false
+	}
+	if valor, ok := parsearSiNo(texto); ok {
+		return valor
+	}
+	if valor, err := strconv.ParseBool(texto); err == nil {
+		return valor
+	}
+	f.err = fmt.Errorf("%s: '%s' no es si ni no", columna, texto)
+	return false
+}
+
+func (f *filaCSV) fecha(columna string) string {
+	texto := f.campo(columna)
+	if texto == "" || f.err != nil {
+		return ""
+	}
+	if _, err := time.Parse("2006-01-02", texto); err != nil {
+		f.err = fmt.Errorf("%s: '%s' no es una fecha AAAA-MM-DD", columna, texto)
+	}
+	return texto
+}
+
+// CombinarTarjetasCSV agrega a las tarjetas las que se leyeron del CSV. Una tarjeta con el
+// mismo tipo y nombre que una registrada se actualiza solo en las columnas que trae el
+// archivo; lo demás, incluidos los planes de MSI, se conserva. Con reemplazar las tarjetas
+// de débito y crédito registradas se descartan.
+func CombinarTarjetasCSV(tarjetas *Tarjetas, leidas TarjetasCSV, reemplazar bool) (nuevas, actualizadas int) {
+	if reemplazar {
+		tarjetas.Debito, tarjetas.Credito = nil, nil
+	}
+	columna := func(nombre string) bool { return leidas.Columnas[nombre] }
+	for _, t := range leidas.Debito {
+		i, ok := indiceDebito(*tarjetas, t.Nombre)
+		if !ok {
+			tarjetas.Debito = append(tarjetas.Debito, t)
+			nuevas++
+			continue
+		}
+		d := &tarjetas.Debito[i]
+		d.Nombre = t.Nombre
+		actualizarCampo(columna("banco"), &d.Banco, t.Banco)
+		actualizarCampo(columna("tasa_rendimiento"), &d.Tramos, t.Tramos)
+		actualizarCampo(columna("saldo_minimo"), &d.SaldoMinimo, t.SaldoMinimo)
+		actualizarCampo(columna("comision_inactividad"), &d.ComisionInactividad, t.ComisionInactividad)
+		actualizarCampo(columna("comision_anual"), &d.ComisionAnual, t.ComisionAnual)
+		actualizarCampo(columna("saldo"), &d.Saldo, t.Saldo)
+		actualizarCampo(columna("tags"), &d.Tags, t.Tags)
+		actualizarCampo(columna("moneda"), &d.Moneda, t.Moneda)
+		actualizadas++
+	}
+	for _, t := range leidas.Credito {
+		i, ok := indiceCredito(*tarjetas, t.Nombre)
+		if !ok {
+			tarjetas.Credito = append(tarjetas.Credito, t)
+			nuevas++
+			continue
+		}
+		d := &tarjetas.Credito[i]
+		d.Nombre = t.Nombre
+		actualizarCampo(columna("banco"), &d.Banco, t.Banco)
+		actualizarCampo(columna("tasa_interes"), &d.TasaInteres, t.TasaInteres)
+		actualizarCampo(columna("cat"), &d.CAT, t.CAT)
+		actualizarCampo(columna("limite_credito"), &d.LimiteCredito, t.LimiteCredito)
+		actualizarCampo(columna("beneficios_cashback"), &d.BeneficiosCashback, t.BeneficiosCashback)
+		actualizarCampo(columna("meses_sin_intereses"), &d.MesesSinIntereses, t.MesesSinIntereses)
+		actualizarCampo(columna("fecha_anualidad"), &d.FechaAnualidad, t.FechaAnualidad)
+		actualizarCampo(columna("comision_anual"), &d.ComisionAnual, t.ComisionAnual)
+		actualizarCampo(columna("saldo"), &d.Saldo, t.Saldo)
+		actualizarCampo(columna("tags"), &d.Tags, t.Tags)
+		actualizarCampo(columna("facturacion_para_condonar"), &d.FacturacionParaCondonar, t.FacturacionParaCondonar)
+		actualizadas++
+	}
+	return nuevas, actualizadas
+}
+
+// actualizarCampo copia el valor importado si su columna venía en el archivo
+func actualizarCampo[T any](hay bool, destino *T, valor T) {
+	if hay {
+		*destino = valor
+	}
+}
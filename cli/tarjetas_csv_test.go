@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTarjetasCSVIdaYVuelta(t *testing.T) {
+	// Un CAT arriba de 100% es común en tarjetas de crédito y debe volver a importarse
+	tarjetas := Tarjetas{Credito: []TarjetaCredito{{Nombre: "Oro", Banco: "BBVA", TasaInteres: 0.9, CAT: 1.25, ComisionAnual: 600}}}
+	var buf bytes.Buffer
+	if err := EscribirTarjetasCSV(&buf, tarjetas); err != nil {
+		t.Fatal(err)
+	}
+	leidas, err := LeerTarjetasCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leidas.Rechazos) > 0 {
+		t.Fatalf("rechazos al volver a importar: %+v", leidas.Rechazos)
+	}
+	if len(leidas.Credito) != 1 || leidas.Credito[0].CAT != 1.25 || leidas.Credito[0].TasaInteres != 0.9 {
+		t.Errorf("tarjetas leídas %+v", leidas.Credito)
+	}
+}
+
+func TestParsearDecimal(t *testing.T) {
+	casos := []struct {
+		texto    string
+		valor    float64
+		invalido bool
+	}{
+		{"0.36", 0.36, false},
+		{"1.25", 1.25, false}, // Dentro del rango de limitesTasa, no se supone un porcentaje
+		{"36%", 0.36, false},
+		{"120%", 1.2, false},
+		{"36", 0, true}, // Fuera del rango y dividido entre cien sí cabe
+		{"1500%", 0, true},
+	}
+	for _, c := range casos {
+		valor, err := parsearDecimal(c.texto, limitesTasa)
+		if c.invalido {
+			if err == nil {
+				t.Errorf("%s: se esperaba un error, salió %g", c.texto, valor)
+			}
+			continue
+		}
+		if err != nil || valor != c.valor {
+			t.Errorf("%s: %g, %v; se esperaba %g", c.texto, valor, err, c.valor)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import "sort"
+
+// ProductoRendimiento es un producto de ahorro o inversión visto solo por su tasa
+type ProductoRendimiento struct {
+	Nombre string
+	Origen string // Tuya, Caja, Catálogo o Referencia
+	Tasa   float64
+}
+
+// Neta regresa la tasa después de ISR
+func (p ProductoRendimiento) Neta() float64 {
+	return TasaNetaISR(p.Tasa)
+}
+
+// Real regresa la tasa neta de ISR descontando la inflación
+func (p ProductoRendimiento) Real(inflacion float64) float64 {
+	return p.Neta() - inflacion
+}
+
+// ProductosRendimiento reúne las cuentas registradas, las cajas de ahorro, las cuentas del
+// catálogo y las tasas de referencia, ordenados de mayor a menor tasa
+func ProductosRendimiento(tarjetas Tarjetas, catalogo Catalogo) []ProductoRendimiento {
+	var productos []ProductoRendimiento
+	for _, t := range tarjetas.Debito {
+		productos = append(productos, ProductoRendimiento{Nombre: t.Nombre + " (" + t.Banco + ")", Origen: "Tuya", Tasa: t.TasaPonderada(t.Saldo)})
+	}
+	for _, c := range tarjetas.Cajas {
+		productos = append(productos, ProductoRendimiento{Nombre: c.Nombre + " (" + c.Entidad + ")", Origen: "Caja", Tasa: c.TasaRendimiento})
+	}
+	for _, d := range catalogo.Debito {
+		productos = append(productos, ProductoRendimiento{Nombre: d.Nombre + " (" + d.Banco + ")", Origen: "Catálogo", Tasa: d.TasaPonderada(0)})
+	}
+	for _, b := range catalogo.Benchmarks {
+		productos = append(productos, ProductoRendimiento{Nombre: b.Nombre, Origen: "Referencia", Tasa: b.Tasa})
+	}
+
+	sort.SliceStable(productos, func(i, j int) bool {
+		return productos[i].Tasa > productos[j].Tasa
+	})
+	return productos
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"finmex/calc"
+)
+
+// ParsearTramosRendimiento lee las tasas de una cuenta de débito. Una tasa sola, como "0.05",
+// aplica a todo el saldo; las escalonadas son tramos hasta:tasa separados por comas, con el
+// excedente sin tope al final: "25000:0.15,0.08" paga 15% hasta $25,000 y 8% por el resto.
+func ParsearTramosRendimiento(texto string) ([]TramoRendimiento, error) {
+	var tramos []TramoRendimiento
+	topes := map[float64]bool{}
+	for _, parte := range ParsearLista(texto) {
+		var tramo TramoRendimiento
+		var err error
+		hasta, tasa, conTope := strings.Cut(parte, ":")
+		if !conTope {
+			tasa = hasta
+		} else if tramo.Hasta, err = ParsearNumero(hasta); err == nil {
+			err = validarLimites(tramo.Hasta, limitesMonto)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Tramo '%s': %w", parte, err)
+		}
+		if tramo.Tasa, err = parsearDecimal(tasa, limitesTasa); err != nil {
+			return nil, fmt.Errorf("Tramo '%s': %w", parte, err)
+		}
+		if topes[tramo.Hasta] {
+			if tramo.Hasta == 0 {
+				return nil, errDatosInvalidos("Solo un tramo puede quedar sin tope", "Only one tier can be uncapped")
+			}
+			return nil, errDatosInvalidos(
+				fmt.Sprintf("Hay dos tramos hasta $%.2f", tramo.Hasta),
+				fmt.Sprintf("There are two tiers up to $%.2f", tramo.Hasta))
+		}
+		topes[tramo.Hasta] = true
+		tramos = append(tramos, tramo)
+	}
+	if len(tramos) == 0 {
+		return nil, errDatosInvalidos("Se necesita al menos una tasa de rendimiento", "At least one interest rate is required")
+	}
+	return calc.TarjetaDebito{Tramos: tramos}.TramosOrdenados(), nil
+}
+
+// FormatearTramos escribe los tramos como los lee ParsearTramosRendimiento
+func FormatearTramos(tramos []TramoRendimiento) string {
+	partes := make([]string, len(tramos))
+	for i, t := range tramos {
+		partes[i] = formatearNumero(t.Tasa)
+		if t.Hasta > 0 {
+			partes[i] = formatearNumero(t.Hasta) + ":" + partes[i]
+		}
+	}
+	return strings.Join(partes, ",")
+}
+
+// DescribirTramos explica las tasas de una cuenta, p. ej. "15.00% hasta $25000.00, 8.00% por
+// el excedente"
+func DescribirTramos(t TarjetaDebito) string {
+	tramos := t.TramosOrdenados()
+	if !tramosEscalonados(tramos) {
+		return fmt.Sprintf("%.2f%%", t.TasaPonderada(0)*100)
+	}
+	partes := make([]string, 0, len(tramos)+1)
+	for i, tramo := range tramos {
+		switch {
+		case tramo.Hasta > 0:
+			partes = append(partes, fmt.Sprintf("%.2f%% hasta $%.2f", tramo.Tasa*100, tramo.Hasta))
+		case i == 0:
+			partes = append(partes, fmt.Sprintf("%.2f%%", tramo.Tasa*100))
+		default:
+			partes = append(partes, fmt.Sprintf("%.2f%% por el excedente", tramo.Tasa*100))
+		}
+	}
+	if n := len(tramos); n > 0 && tramos[n-1].Hasta > 0 {
+		partes = append(partes, "0% por el excedente")
+	}
+	return strings.Join(partes, ", ")
+}
+
+// tramosEscalonados dice si la tasa cambia con el saldo, ya sea por tener varios tramos o un
+// tope después del cual no se gana nada
+func tramosEscalonados(tramos []TramoRendimiento) bool {
+	return len(tramos) > 1 || len(tramos) == 1 && tramos[0].Hasta > 0
+}
+
+// Tramos regresa las tasas de una cuenta nueva: --tramos para las escalonadas o --tasa para
+// una sola. En modo interactivo se acepta cualquiera de las dos formas.
+func (k capturaFlags) Tramos(pregunta string) ([]TramoRendimiento, error) {
+	switch {
+	case k.c.IsSet("tramos"):
+		return ParsearTramosRendimiento(k.c.String("tramos"))
+	case k.c.IsSet("tasa"):
+		tasa, err := k.numeroDeFlag("tasa", limitesTasa)
+		return calc.TasaUnica(tasa), err
+	}
+	return leerTramos(func() (string, error) { return leerTextoRequerido(pregunta + ": ") })
+}
+
+// EditarTramos regresa las nuevas tasas de una cuenta; en modo interactivo se muestran las
+// actuales en el mismo formato para no perder los tramos de una cuenta escalonada
+func (k capturaFlags) EditarTramos(pregunta string, t TarjetaDebito) ([]TramoRendimiento, error) {
+	if k.c.IsSet("tramos") || k.c.IsSet("tasa") {
+		return k.Tramos(pregunta)
+	}
+	if !k.interactiva {
+		return t.TramosOrdenados(), nil
+	}
+	return leerTramos(func() (string, error) { return leerTextoConValor(pregunta, FormatearTramos(t.TramosOrdenados())) })
+}
+
+// leerTramos repite la pregunta hasta obtener tramos válidos
+func leerTramos(leer func() (string, error)) ([]TramoRendimiento, error) {
+	for {
+		texto, err := leer()
+		if err != nil {
+			return nil, err
+		}
+		tramos, err := ParsearTramosRendimiento(texto)
+		if err == nil {
+			return tramos, nil
+		}
+		fmt.Printf("  Valor inválido: %v. Intenta de nuevo.\n", err)
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import "finmex/calc"
+
+// SimulacionTransferencia compara quedarse con la deuda en la tarjeta actual contra
+// traspasarla a otra tarjeta con una promoción, pagando lo mismo cada mes
+type SimulacionTransferencia struct {
+	Origen          string        `json:"origen"`
+	Destino         string        `json:"destino"`
+	Deuda           float64       `json:"deuda"`
+	Pago            float64       `json:"pago"`
+	Comision        float64       `json:"comision"` // Fracción del monto, sin IVA
+	TasaPromocional float64       `json:"tasa_promocional"`
+	MesesPromocion  int           `json:"meses_promocion"`
+	Actual          CostoTraspaso `json:"actual"`
+	Transferencia   CostoTraspaso `json:"transferencia"`
+	Ahorro          float64       `json:"ahorro"` // Positivo si conviene transferir
+	// PagoPromocion es el pago mensual con el que la deuda traspasada se liquida antes de que
+	// termine la promoción
+	PagoPromocion float64 `json:"pago_promocion"`
+	Conviene      bool    `json:"conviene"`
+}
+
+// SimularTransferencia calcula el ahorro neto en intereses de traspasar la deuda de origen a
+// destino con calc.SimularTransferencia. La comisión del traspaso cuenta como costo.
+func SimularTransferencia(origen, destino TarjetaCredito, deuda, pago float64, promocion TransferenciaSaldo) SimulacionTransferencia {
+	// El único error es una deuda en cero, que el comando ya rechaza
+	r, _ := calc.SimularTransferencia(calc.EntradaTransferencia{
+		Deuda: deuda, TasaOrigen: origen.TasaInteres, TasaDestino: destino.TasaInteres, PagoMensual: pago, TransferenciaSaldo: promocion,
+	})
+	return SimulacionTransferencia{
+		Origen:          origen.Nombre,
+		Destino:         destino.Nombre,
+		Deuda:           deuda,
+		Pago:            pago,
+		Comision:        promocion.Comision,
+		TasaPromocional: promocion.TasaPromocional,
+		MesesPromocion:  promocion.MesesPromocion,
+		Actual:          r.Actual,
+		Transferencia:   r.Transferencia,
+		Ahorro:          r.Ahorro,
+		PagoPromocion:   r.PagoPromocion,
+		Conviene:        r.Ahorro > 0,
+	}
+}
@@ -0,0 +1,514 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Secuencias ANSI para resaltar sin depender de colores; se omiten con NO_COLOR
+const (
+	ansiNegritas  = "\x1b[1m"
+	ansiTenue     = "\x1b[2m"
+	ansiInvertido = "\x1b[7m"
+	ansiNormal    = "\x1b[0m"
+)
+
+// vistaTUI es una pantalla de la interfaz de terminal. Las vistas se apilan: Esc regresa a
+// la anterior y al vaciar la pila termina el programa.
+type vistaTUI interface {
+	Actualizar(m *modeloTUI, tecla tea.KeyMsg) tea.Cmd
+	Dibujar(m *modeloTUI) string
+	Ayuda() string
+}
+
+// modeloTUI es el estado de la interfaz: los datos cargados y la pila de pantallas
+type modeloTUI struct {
+	tarjetas Tarjetas
+	catalogo Catalogo
+	pila     []vistaTUI
+	privado  bool
+	color    bool
+	aviso    string // Resultado de la última acción
+	alto     int
+}
+
+func (m *modeloTUI) Init() tea.Cmd {
+	return nil
+}
+
+func (m *modeloTUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.alto = msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "ctrl+p":
+			m.privado = !m.privado
+			return m, nil
+		}
+		cmd := m.pila[len(m.pila)-1].Actualizar(m, msg)
+		if len(m.pila) == 0 {
+			return m, tea.Quit
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *modeloTUI) View() string {
+	if len(m.pila) == 0 {
+		return ""
+	}
+	vista := m.pila[len(m.pila)-1]
+
+	var b strings.Builder
+	b.WriteString(m.estilo(ansiNegritas, "finmex"))
+	if m.privado {
+		b.WriteString(m.estilo(ansiTenue, "  (modo privado)"))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(vista.Dibujar(m))
+	if m.aviso != "" {
+		b.WriteString("\n" + m.aviso + "\n")
+	}
+	b.WriteString("\n" + m.estilo(ansiTenue, vista.Ayuda()+" · ctrl+p privado · ctrl+c salir"))
+
+	if m.privado {
+		return OcultarMontos(b.String())
+	}
+	return b.String()
+}
+
+// abrir pone una pantalla encima de la actual
+func (m *modeloTUI) abrir(v vistaTUI) {
+	m.pila = append(m.pila, v)
+}
+
+// reemplazar cambia la pantalla actual por otra, para que Esc no regrese a ella
+func (m *modeloTUI) reemplazar(v vistaTUI) {
+	m.pila[len(m.pila)-1] = v
+}
+
+// regresar quita la pantalla actual
+func (m *modeloTUI) regresar() {
+	m.pila = m.pila[:len(m.pila)-1]
+}
+
+// estilo aplica una secuencia ANSI si la terminal acepta color
+func (m *modeloTUI) estilo(secuencia, texto string) string {
+	if !m.color {
+		return texto
+	}
+	return secuencia + texto + ansiNormal
+}
+
+// lineasDisponibles es cuántos renglones de contenido caben en la terminal
+func (m *modeloTUI) lineasDisponibles() int {
+	if m.alto <= 0 {
+		return 20
+	}
+	if n := m.alto - 8; n > 3 {
+		return n
+	}
+	return 3
+}
+
+// opcionMenu es una entrada del menú con la acción que abre
+type opcionMenu struct {
+	Titulo string
+	Abrir  func(m *modeloTUI) vistaTUI
+}
+
+// menuTUI es una lista de opciones que se recorre con las flechas
+type menuTUI struct {
+	titulo   string
+	opciones []opcionMenu
+	cursor   int
+}
+
+func (v *menuTUI) Actualizar(m *modeloTUI, tecla tea.KeyMsg) tea.Cmd {
+	switch tecla.String() {
+	case "up", "k":
+		v.cursor = (v.cursor + len(v.opciones) - 1) % len(v.opciones)
+	case "down", "j", "tab":
+		v.cursor = (v.cursor + 1) % len(v.opciones)
+	case "p":
+		m.privado = !m.privado
+	case "esc", "q":
+		m.regresar()
+	case "enter":
+		m.aviso = ""
+		opcion := v.opciones[v.cursor]
+		if opcion.Abrir == nil {
+			m.regresar()
+			return nil
+		}
+		if siguiente := opcion.Abrir(m); siguiente != nil {
+			m.abrir(siguiente)
+		}
+	}
+	return nil
+}
+
+func (v *menuTUI) Dibujar(m *modeloTUI) string {
+	var b strings.Builder
+	b.WriteString(m.estilo(ansiNegritas, v.titulo) + "\n\n")
+	for i, o := range v.opciones {
+		if i == v.cursor {
+			b.WriteString(m.estilo(ansiInvertido, "> "+o.Titulo) + "\n")
+		} else {
+			b.WriteString("  " + o.Titulo + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (v *menuTUI) Ayuda() string {
+	return "↑/↓ moverse · enter elegir · esc regresar"
+}
+
+// tablaTUI muestra renglones con columnas alineadas; el renglón elegido se resalta y con
+// enter se abre su detalle, si la tabla tiene uno
+type tablaTUI struct {
+	titulo      string
+	encabezados []string
+	filas       [][]string
+	pie         string
+	cursor      int
+	inicio      int // Primer renglón visible
+	detalle     func(m *modeloTUI, fila int) vistaTUI
+}
+
+func (v *tablaTUI) Actualizar(m *modeloTUI, tecla tea.KeyMsg) tea.Cmd {
+	visibles := m.lineasDisponibles() - 4
+	switch tecla.String() {
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(v.filas)-1 {
+			v.cursor++
+		}
+	case "pgup":
+		v.cursor -= visibles
+	case "pgdown":
+		v.cursor += visibles
+	case "home", "g":
+		v.cursor = 0
+	case "end", "G":
+		v.cursor = len(v.filas) - 1
+	case "p":
+		m.privado = !m.privado
+	case "esc", "q":
+		m.regresar()
+		return nil
+	case "enter":
+		if v.detalle != nil && len(v.filas) > 0 {
+			m.aviso = ""
+			if siguiente := v.detalle(m, v.cursor); siguiente != nil {
+				m.abrir(siguiente)
+			}
+		}
+		return nil
+	}
+
+	if v.cursor >= len(v.filas) {
+		v.cursor = len(v.filas) - 1
+	}
+	if v.cursor < 0 {
+		v.cursor = 0
+	}
+	if v.cursor < v.inicio {
+		v.inicio = v.cursor
+	}
+	if visibles > 0 && v.cursor >= v.inicio+visibles {
+		v.inicio = v.cursor - visibles + 1
+	}
+	return nil
+}
+
+func (v *tablaTUI) Dibujar(m *modeloTUI) string {
+	anchos := make([]int, len(v.encabezados))
+	for i, e := range v.encabezados {
+		anchos[i] = utf8.RuneCountInString(e)
+	}
+	for _, f := range v.filas {
+		for i, celda := range f {
+			if n := utf8.RuneCountInString(celda); i < len(anchos) && n > anchos[i] {
+				anchos[i] = n
+			}
+		}
+	}
+	renglon := func(celdas []string) string {
+		partes := make([]string, len(anchos))
+		for i := range anchos {
+			celda := ""
+			if i < len(celdas) {
+				celda = celdas[i]
+			}
+			partes[i] = celda + strings.Repeat(" ", anchos[i]-utf8.RuneCountInString(celda))
+		}
+		return strings.Join(partes, "   ")
+	}
+
+	var b strings.Builder
+	b.WriteString(m.estilo(ansiNegritas, v.titulo) + "\n\n")
+	b.WriteString("  " + m.estilo(ansiNegritas, renglon(v.encabezados)) + "\n")
+	if len(v.filas) == 0 {
+		b.WriteString("  (sin registros)\n")
+	}
+
+	visibles := m.lineasDisponibles() - 4
+	if visibles < 1 {
+		visibles = 1
+	}
+	fin := v.inicio + visibles
+	if fin > len(v.filas) {
+		fin = len(v.filas)
+	}
+	for i := v.inicio; i < fin; i++ {
+		if i == v.cursor {
+			b.WriteString(m.estilo(ansiInvertido, "> "+renglon(v.filas[i])) + "\n")
+		} else {
+			b.WriteString("  " + renglon(v.filas[i]) + "\n")
+		}
+	}
+	if len(v.filas) > visibles {
+		b.WriteString(m.estilo(ansiTenue, fmt.Sprintf("  %d-%d de %d", v.inicio+1, fin, len(v.filas))) + "\n")
+	}
+	if v.pie != "" {
+		b.WriteString("\n" + v.pie + "\n")
+	}
+	return b.String()
+}
+
+func (v *tablaTUI) Ayuda() string {
+	if v.detalle != nil {
+		return "↑/↓ moverse · enter detalle · esc regresar"
+	}
+	return "↑/↓ moverse · esc regresar"
+}
+
+// textoTUI muestra un resultado de varias líneas que se puede desplazar
+type textoTUI struct {
+	titulo string
+	lineas []string
+	inicio int
+}
+
+// nuevoTextoTUI separa el texto en líneas para mostrarlo
+func nuevoTextoTUI(titulo, texto string) *textoTUI {
+	return &textoTUI{titulo: titulo, lineas: strings.Split(strings.TrimRight(texto, "\n"), "\n")}
+}
+
+func (v *textoTUI) Actualizar(m *modeloTUI, tecla tea.KeyMsg) tea.Cmd {
+	maximo := len(v.lineas) - m.lineasDisponibles() + 2
+	switch tecla.String() {
+	case "up", "k":
+		v.inicio--
+	case "down", "j":
+		v.inicio++
+	case "pgup":
+		v.inicio -= m.lineasDisponibles()
+	case "pgdown", " ":
+		v.inicio += m.lineasDisponibles()
+	case "p":
+		m.privado = !m.privado
+	case "esc", "q", "enter":
+		m.regresar()
+	}
+	if v.inicio > maximo {
+		v.inicio = maximo
+	}
+	if v.inicio < 0 {
+		v.inicio = 0
+	}
+	return nil
+}
+
+func (v *textoTUI) Dibujar(m *modeloTUI) string {
+	fin := v.inicio + m.lineasDisponibles() - 2
+	if fin > len(v.lineas) {
+		fin = len(v.lineas)
+	}
+	return m.estilo(ansiNegritas, v.titulo) + "\n\n" + strings.Join(v.lineas[v.inicio:fin], "\n") + "\n"
+}
+
+func (v *textoTUI) Ayuda() string {
+	return "↑/↓ desplazar · esc regresar"
+}
+
+// Tipos de campo de un formulario, según cómo se valida lo capturado
+const (
+	campoTexto          = iota // Obligatorio
+	campoNumero                // Obligatorio y dentro de los límites
+	campoNumeroOpcional        // Vacío vale 0
+	campoSiNo                  // s/n; vacío es no
+	campoLista                 // Separada por comas, puede quedar vacía
+)
+
+// campoTUI es un dato que se captura en un formulario
+type campoTUI struct {
+	Etiqueta string
+	Tipo     int
+	Limites  LimitesNumero
+	valor    []rune
+	error    string
+}
+
+// formularioTUI captura varios campos y los valida todos antes de enviarlos. A diferencia
+// de la captura por línea, los textos pueden llevar espacios y un error no obliga a volver
+// a empezar.
+type formularioTUI struct {
+	titulo string
+	campos []campoTUI
+	foco   int
+	error  string
+	enviar func(m *modeloTUI, f *formularioTUI) error
+}
+
+// nuevoFormularioTUI crea el formulario con los campos vacíos
+func nuevoFormularioTUI(titulo string, campos []campoTUI, enviar func(m *modeloTUI, f *formularioTUI) error) *formularioTUI {
+	return &formularioTUI{titulo: titulo, campos: campos, enviar: enviar}
+}
+
+// conValor pone un valor inicial a un campo
+func (f *formularioTUI) conValor(i int, valor string) *formularioTUI {
+	f.campos[i].valor = []rune(valor)
+	return f
+}
+
+func (f *formularioTUI) Actualizar(m *modeloTUI, tecla tea.KeyMsg) tea.Cmd {
+	campo := &f.campos[f.foco]
+	switch tecla.Type {
+	case tea.KeyEsc:
+		m.regresar()
+	case tea.KeyTab, tea.KeyDown:
+		f.foco = (f.foco + 1) % len(f.campos)
+	case tea.KeyShiftTab, tea.KeyUp:
+		f.foco = (f.foco + len(f.campos) - 1) % len(f.campos)
+	case tea.KeyBackspace:
+		if len(campo.valor) > 0 {
+			campo.valor = campo.valor[:len(campo.valor)-1]
+		}
+		campo.error = ""
+	case tea.KeyCtrlU:
+		campo.valor = nil
+		campo.error = ""
+	case tea.KeySpace:
+		campo.valor = append(campo.valor, ' ')
+	case tea.KeyRunes:
+		campo.valor = append(campo.valor, tecla.Runes...)
+		campo.error = ""
+	case tea.KeyEnter:
+		if f.foco < len(f.campos)-1 {
+			f.foco++
+			return nil
+		}
+		if !f.validar() {
+			return nil
+		}
+		if err := f.enviar(m, f); err != nil {
+			f.error = err.Error()
+		}
+	}
+	return nil
+}
+
+// validar revisa todos los campos y pone el foco en el primero con error
+func (f *formularioTUI) validar() bool {
+	primero := -1
+	for i := range f.campos {
+		c := &f.campos[i]
+		c.error = ""
+		texto := strings.TrimSpace(string(c.valor))
+		switch c.Tipo {
+		case campoTexto:
+			if texto == "" {
+				c.error = "obligatorio"
+			}
+		case campoNumero, campoNumeroOpcional:
+			if texto == "" {
+				if c.Tipo == campoNumero {
+					c.error = "obligatorio"
+				}
+				break
+			}
+			if _, err := parsearDecimal(texto, c.Limites); err != nil {
+				c.error = err.Error()
+			}
+		case campoSiNo:
+			if _, ok := parsearSiNo(texto); texto != "" && !ok {
+				c.error = "responde s o n"
+			}
+		}
+		if c.error != "" && primero < 0 {
+			primero = i
+		}
+	}
+	if primero >= 0 {
+		f.foco = primero
+		return false
+	}
+	return true
+}
+
+// Texto regresa el texto capturado en el campo, sin espacios sobrantes
+func (f *formularioTUI) Texto(i int) string {
+	return strings.TrimSpace(string(f.campos[i].valor))
+}
+
+// Numero regresa el número de un campo ya validado
+func (f *formularioTUI) Numero(i int) float64 {
+	valor, _ := ParsearNumero(f.Texto(i))
+	return valor
+}
+
+// SiNo regresa la respuesta de un campo de sí o no ya validado
+func (f *formularioTUI) SiNo(i int) bool {
+	valor, _ := parsearSiNo(f.Texto(i))
+	return valor
+}
+
+// Lista regresa los elementos separados por comas de un campo
+func (f *formularioTUI) Lista(i int) []string {
+	return ParsearLista(f.Texto(i))
+}
+
+func (f *formularioTUI) Dibujar(m *modeloTUI) string {
+	ancho := 0
+	for _, c := range f.campos {
+		if n := utf8.RuneCountInString(c.Etiqueta); n > ancho {
+			ancho = n
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(m.estilo(ansiNegritas, f.titulo) + "\n\n")
+	for i, c := range f.campos {
+		etiqueta := c.Etiqueta + strings.Repeat(" ", ancho-utf8.RuneCountInString(c.Etiqueta))
+		valor := string(c.valor)
+		if i == f.foco {
+			b.WriteString("> " + m.estilo(ansiNegritas, etiqueta) + "  " + valor + m.estilo(ansiInvertido, " ") + "\n")
+		} else {
+			b.WriteString("  " + etiqueta + "  " + valor + "\n")
+		}
+		if c.error != "" {
+			b.WriteString("  " + strings.Repeat(" ", ancho) + "  ↳ " + c.error + "\n")
+		}
+	}
+	if f.error != "" {
+		b.WriteString("\nError: " + f.error + "\n")
+	}
+	return b.String()
+}
+
+func (f *formularioTUI) Ayuda() string {
+	return "tab/↓ siguiente · enter en el último campo guarda · ctrl+u borrar campo · esc cancelar"
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"finmex/banxico"
+	"finmex/calc"
+
+	"github.com/urfave/cli/v2"
+)
+
+// VALOR_UDI es el valor estimado de la UDI en pesos que se usa si no se indica otro y no
+// hay dato de Banxico
+const VALOR_UDI = 8.90
+
+// valorUDIConfigurado es el valor de --valor-udi; en cero se consulta Banxico
+var valorUDIConfigurado float64
+
+var valorUDIVigente struct {
+	sync.Once
+	valor  float64
+	origen string
+}
+
+// flagValorUDI fija el valor de la UDI en lugar de consultarlo
+func flagValorUDI() cli.Flag {
+	return conLimites(&cli.Float64Flag{Name: "valor-udi", Usage: "Valor de la UDI en pesos; por defecto el último publicado por Banxico", EnvVars: []string{"FINMEX_VALOR_UDI"}}, limitesMonto)
+}
+
+// configurarValorUDI aplica --valor-udi
+func configurarValorUDI(valor float64) error {
+	if valor < 0 {
+		return fmt.Errorf("El valor de la UDI no puede ser negativo: %.6f", valor)
+	}
+	valorUDIConfigurado = valor
+	return nil
+}
+
+// ValorUDIVigente regresa el valor de la UDI en pesos y de dónde salió: --valor-udi, el
+// último dato de Banxico o VALOR_UDI como estimación. Banxico se consulta una sola vez por
+// ejecución.
+func ValorUDIVigente() (float64, string) {
+	valorUDIVigente.Do(func() {
+		if valorUDIConfigurado > 0 {
+			valorUDIVigente.valor, valorUDIVigente.origen = valorUDIConfigurado, "--valor-udi"
+			return
+		}
+		valorUDIVigente.valor, valorUDIVigente.origen = VALOR_UDI, "estimado"
+		datos, err := clienteBanxico().Oportuno(banxico.SerieUDIS)
+		if err != nil && !errors.Is(err, banxico.ErrSinToken) {
+			registro.Warn("no se pudo actualizar el valor de la UDI desde Banxico", "error", err)
+		}
+		if len(datos) > 0 {
+			valorUDIVigente.valor = datos[0].Valor
+			valorUDIVigente.origen = "Banxico " + datos[0].Fecha
+		}
+	})
+	return valorUDIVigente.valor, valorUDIVigente.origen
+}
+
+// enUDIS convierte un monto en pesos corrientes que se recibe o se paga dentro de los meses
+// dados, proyectando la UDI con la inflación vigente
+func enUDIS(monto, meses float64) MontoUDIS {
+	valor, _ := ValorUDIVigente()
+	constantes := calc.PesosConstantes(monto, InflacionVigente(), meses)
+	return MontoUDIS{
+		ValorUDI:        valor,
+		Meses:           meses,
+		UDIS:            calc.PesosAUDIs(constantes, valor),
+		PesosConstantes: constantes,
+	}
+}
+
+// pagadoEnUDIS suma los pagos de liquidar la deuda en pesos de hoy; cada pago se descuenta
+// con la inflación hasta su fecha
+func pagadoEnUDIS(t TarjetaCredito, deuda, pago float64, frecuencia Frecuencia) MontoUDIS {
+	valor, _ := ValorUDIVigente()
+	tabla := calc.TablaAmortizacionCredito(t, deuda, pago, frecuencia)
+	constantes := calc.PagosPesosConstantes(tabla, InflacionVigente(), frecuencia.PeriodosPorAño())
+	return MontoUDIS{
+		ValorUDI:        valor,
+		Meses:           float64(len(tabla)) * 12 / float64(frecuencia.PeriodosPorAño()),
+		UDIS:            calc.PesosAUDIs(constantes, valor),
+		PesosConstantes: constantes,
+	}
+}
+
+// imprimirEnUDIS muestra el renglón de pesos constantes de un análisis
+func imprimirEnUDIS(etiqueta string, m MontoUDIS) {
+	fmt.Printf("%s en pesos de hoy: $%.2f (%.2f UDIS a $%.6f, inflación de %.2f%%)\n",
+		etiqueta, m.PesosConstantes, m.UDIS, m.ValorUDI, InflacionVigente()*100)
+}
@@ -0,0 +1,52 @@
+package cli
+
+import "finmex/calc"
+
+// CATEGORIA_DESPENSA es la categoría de movimientos que se cubre con vales de despensa
+const CATEGORIA_DESPENSA = "despensa"
+
+// EfectoVales resume cuánto aportan los vales al ingreso neto en un mes
+type EfectoVales struct {
+	Bruto   float64 `json:"bruto"`   // Monto depositado
+	Exento  float64 `json:"exento"`  // Parte libre de ISR
+	Gravado float64 `json:"gravado"` // Parte que excede el tope de exención
+	ISR     float64 `json:"isr"`     // Impuesto retenido por la parte gravada
+	Neto    float64 `json:"neto"`    // Lo que realmente se recibe
+}
+
+// FilaVales son unos vales con su efecto en la salida de vales listar con --output
+type FilaVales struct {
+	ValeDespensa
+	Tope float64 `json:"tope"` // Tope de exención en pesos
+	EfectoVales
+}
+
+// CalcularEfectoVales calcula la parte exenta y gravada de los vales del mes con la UMA del año
+func CalcularEfectoVales(v ValeDespensa, año int) (EfectoVales, error) {
+	uma, err := calc.UMAMensual(año)
+	if err != nil {
+		return EfectoVales{}, err
+	}
+
+	efecto := EfectoVales{Bruto: v.MontoMensual, Exento: v.MontoMensual}
+	if tope := v.TopeUMAs * uma; v.MontoMensual > tope {
+		efecto.Exento = tope
+		efecto.Gravado = v.MontoMensual - tope
+	}
+	efecto.ISR = efecto.Gravado * v.TasaMarginal
+	efecto.Neto = efecto.Bruto - efecto.ISR
+	return efecto, nil
+}
+
+// ValesNetosMensuales suma el ingreso neto mensual de todos los vales registrados
+func ValesNetosMensuales(vales []ValeDespensa, año int) (float64, error) {
+	total := 0.0
+	for _, v := range vales {
+		efecto, err := CalcularEfectoVales(v, año)
+		if err != nil {
+			return 0, err
+		}
+		total += efecto.Neto
+	}
+	return total, nil
+}
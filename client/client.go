@@ -0,0 +1,194 @@
+// Package client consume la API HTTP de finmex serve con métodos tipados, para usar las
+// tarjetas y los cálculos de finmex desde otros servicios sin armar las solicitudes a mano.
+// Las respuestas usan los tipos del paquete api, los mismos con los que responde el servidor.
+//
+//	c := client.NuevoCliente("http://127.0.0.1:8080", os.Getenv("FINMEX_TOKEN"))
+//	analisis, err := c.Analizar(ctx, "Oro", client.ConsultaCredito{Deuda: 20000})
+//	if errors.Is(err, api.ErrTarjetaNoEncontrada) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"finmex/api"
+)
+
+// Cliente habla con un servidor de finmex serve
+type Cliente struct {
+	base  string // Dirección del servidor, p. ej. http://127.0.0.1:8080
+	token string // Se manda como Authorization: Bearer si no está vacío
+	HTTP  *http.Client
+}
+
+// NuevoCliente crea un cliente para el servidor en base con el token de finmex serve --token;
+// sin token las solicitudes van sin autorización
+func NuevoCliente(base, token string) *Cliente {
+	return &Cliente{
+		base:  strings.TrimSuffix(base, "/"),
+		token: token,
+		HTTP:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ConsultaCredito es la deuda a analizar o comparar. Sin Pago el servidor usa el pago
+// mínimo; sin Frecuencia, la mensual.
+type ConsultaCredito struct {
+	Deuda      float64
+	Pago       float64
+	Frecuencia api.Frecuencia
+}
+
+func (q ConsultaCredito) parametros() url.Values {
+	v := url.Values{}
+	agregarNumero(v, "deuda", q.Deuda)
+	agregarNumero(v, "pago", q.Pago)
+	if q.Frecuencia != "" {
+		v.Set("frecuencia", string(q.Frecuencia))
+	}
+	return v
+}
+
+// Salud regresa la versión y la configuración vigente del servidor
+func (c *Cliente) Salud(ctx context.Context) (api.EstadoServidor, error) {
+	var estado api.EstadoServidor
+	return estado, c.solicitar(ctx, http.MethodGet, "/api/salud", nil, nil, &estado)
+}
+
+// ListarTarjetas regresa todos los productos registrados, como finmex exportar
+func (c *Cliente) ListarTarjetas(ctx context.Context) (api.Tarjetas, error) {
+	var tarjetas api.Tarjetas
+	return tarjetas, c.solicitar(ctx, http.MethodGet, "/api/tarjetas", nil, nil, &tarjetas)
+}
+
+// ListarDebito regresa las cuentas de débito con su posición frente al mercado
+func (c *Cliente) ListarDebito(ctx context.Context) ([]api.FilaDebito, error) {
+	var filas []api.FilaDebito
+	return filas, c.solicitar(ctx, http.MethodGet, "/api/debito", nil, nil, &filas)
+}
+
+// ListarCredito regresa las tarjetas de crédito con su posición frente al mercado
+func (c *Cliente) ListarCredito(ctx context.Context) ([]api.FilaCredito, error) {
+	var filas []api.FilaCredito
+	return filas, c.solicitar(ctx, http.MethodGet, "/api/credito", nil, nil, &filas)
+}
+
+// AgregarDebito registra una cuenta de débito y la regresa como quedó guardada
+func (c *Cliente) AgregarDebito(ctx context.Context, t api.TarjetaDebito) (api.TarjetaDebito, error) {
+	var agregada api.TarjetaDebito
+	return agregada, c.solicitar(ctx, http.MethodPost, "/api/debito", nil, t, &agregada)
+}
+
+// AgregarCredito registra una tarjeta de crédito y la regresa como quedó guardada
+func (c *Cliente) AgregarCredito(ctx context.Context, t api.TarjetaCredito) (api.TarjetaCredito, error) {
+	var agregada api.TarjetaCredito
+	return agregada, c.solicitar(ctx, http.MethodPost, "/api/credito", nil, t, &agregada)
+}
+
+// Rendimiento analiza una cuenta de débito registrada; con saldo 0 se usa el registrado
+func (c *Cliente) Rendimiento(ctx context.Context, nombre string, saldo float64) (api.AnalisisDebito, error) {
+	var a api.AnalisisDebito
+	v := url.Values{}
+	agregarNumero(v, "saldo", saldo)
+	return a, c.solicitar(ctx, http.MethodGet, "/api/debito/"+url.PathEscape(nombre)+"/rendimiento", v, nil, &a)
+}
+
+// Analizar calcula lo que cuesta liquidar una deuda con una tarjeta de crédito registrada,
+// como credito analizar; con Deuda 0 se usa el saldo registrado
+func (c *Cliente) Analizar(ctx context.Context, nombre string, q ConsultaCredito) (api.AnalisisCredito, error) {
+	var a api.AnalisisCredito
+	return a, c.solicitar(ctx, http.MethodGet, "/api/credito/"+url.PathEscape(nombre)+"/costo", q.parametros(), nil, &a)
+}
+
+// Comparar calcula lo que cuesta liquidar la misma deuda con cada tarjeta de crédito
+// registrada, como comparar credito. La consulta necesita Deuda y Pago.
+func (c *Cliente) Comparar(ctx context.Context, q ConsultaCredito) ([]api.ResultadoComparacionCredito, error) {
+	var resultados []api.ResultadoComparacionCredito
+	return resultados, c.solicitar(ctx, http.MethodGet, "/api/comparar/credito", q.parametros(), nil, &resultados)
+}
+
+// CompararDebito calcula el rendimiento real de cada cuenta y caja de ahorro con el mismo
+// saldo, como comparar debito
+func (c *Cliente) CompararDebito(ctx context.Context, saldo float64) ([]api.ResultadoComparacionDebito, error) {
+	var resultados []api.ResultadoComparacionDebito
+	v := url.Values{}
+	agregarNumero(v, "saldo", saldo)
+	return resultados, c.solicitar(ctx, http.MethodGet, "/api/comparar/debito", v, nil, &resultados)
+}
+
+// solicitar manda la solicitud y decodifica la respuesta en destino. Las respuestas de error
+// con código se regresan como *api.ErrorFinmex, para compararlas con errors.Is igual que los
+// errores de la terminal.
+func (c *Cliente) solicitar(ctx context.Context, metodo, ruta string, consulta url.Values, cuerpo, destino interface{}) error {
+	direccion := c.base + ruta
+	if len(consulta) > 0 {
+		direccion += "?" + consulta.Encode()
+	}
+	var lector io.Reader
+	if cuerpo != nil {
+		data, err := json.Marshal(cuerpo)
+		if err != nil {
+			return err
+		}
+		lector = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, metodo, direccion, lector)
+	if err != nil {
+		return err
+	}
+	if cuerpo != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return errorRespuesta(resp.StatusCode, data)
+	}
+	if err := json.Unmarshal(data, destino); err != nil {
+		return fmt.Errorf("Respuesta inválida de %s %s: %w", metodo, ruta, err)
+	}
+	return nil
+}
+
+// errorRespuesta convierte el cuerpo de una respuesta de error del servidor
+func errorRespuesta(estado int, data []byte) error {
+	var cuerpo struct {
+		Error   string          `json:"error"`
+		Codigo  api.CodigoError `json:"codigo"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(data, &cuerpo); err != nil || cuerpo.Error == "" {
+		return fmt.Errorf("El servidor respondió %d %s", estado, http.StatusText(estado))
+	}
+	if cuerpo.Codigo == "" {
+		return fmt.Errorf("El servidor respondió %d: %s", estado, cuerpo.Error)
+	}
+	return &api.ErrorFinmex{Codigo: cuerpo.Codigo, Mensaje: cuerpo.Error, Message: cuerpo.Message}
+}
+
+// agregarNumero agrega el parámetro solo si tiene valor, para que el servidor use su valor
+// por defecto
+func agregarNumero(v url.Values, nombre string, valor float64) {
+	if valor != 0 {
+		v.Set(nombre, strconv.FormatFloat(valor, 'f', -1, 64))
+	}
+}
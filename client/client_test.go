@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"finmex/api"
+)
+
+func TestAnalizarArmaLaConsulta(t *testing.T) {
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/credito/Nu Morada/costo" {
+			t.Errorf("ruta %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("deuda") != "20000" || q.Get("frecuencia") != "quincenal" || q.Has("pago") {
+			t.Errorf("consulta %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer secreto" {
+			t.Errorf("autorización %q", r.Header.Get("Authorization"))
+		}
+		json.NewEncoder(w).Encode(api.AnalisisCredito{Nombre: "Nu Morada", Deuda: 20000, Pagos: 30})
+	}))
+	defer servidor.Close()
+
+	c := NuevoCliente(servidor.URL+"/", "secreto")
+	a, err := c.Analizar(context.Background(), "Nu Morada", ConsultaCredito{Deuda: 20000, Frecuencia: api.FrecuenciaQuincenal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Nombre != "Nu Morada" || a.Pagos != 30 {
+		t.Errorf("análisis %+v", a)
+	}
+}
+
+func TestCompararDecodificaLasFilas(t *testing.T) {
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/comparar/credito" || r.URL.Query().Get("pago") != "1500.5" {
+			t.Errorf("solicitud %s", r.URL)
+		}
+		json.NewEncoder(w).Encode([]api.ResultadoComparacionCredito{{Nombre: "Oro", CostoTotal: 3200}, {Nombre: "Azul", CostoTotal: 4100}})
+	}))
+	defer servidor.Close()
+
+	resultados, err := NuevoCliente(servidor.URL, "").Comparar(context.Background(), ConsultaCredito{Deuda: 20000, Pago: 1500.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resultados) != 2 || resultados[1].Nombre != "Azul" || resultados[1].CostoTotal != 4100 {
+		t.Errorf("resultados %+v", resultados)
+	}
+}
+
+func TestAgregarCreditoMandaElCuerpo(t *testing.T) {
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tarjeta api.TarjetaCredito
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&tarjeta) != nil || tarjeta.Nombre != "Oro" {
+			t.Errorf("solicitud %s con %+v", r.Method, tarjeta)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(tarjeta)
+	}))
+	defer servidor.Close()
+
+	agregada, err := NuevoCliente(servidor.URL, "").AgregarCredito(context.Background(), api.TarjetaCredito{Nombre: "Oro", Banco: "BBVA", TasaInteres: 0.4})
+	if err != nil || agregada.TasaInteres != 0.4 {
+		t.Errorf("agregada %+v, %v", agregada, err)
+	}
+}
+
+func TestErroresConCodigo(t *testing.T) {
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/credito/Nada/costo":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "No existe la tarjeta de crédito 'Nada'", "codigo": "TARJETA_NO_ENCONTRADA", "message": "Credit card 'Nada' not found"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": "Error al cargar tarjetas: disco lleno"}`))
+		}
+	}))
+	defer servidor.Close()
+	c := NuevoCliente(servidor.URL, "")
+
+	_, err := c.Analizar(context.Background(), "Nada", ConsultaCredito{})
+	var e *api.ErrorFinmex
+	if !errors.Is(err, api.ErrTarjetaNoEncontrada) || !errors.As(err, &e) || e.Localizado("en") != "Credit card 'Nada' not found" {
+		t.Errorf("error %v", err)
+	}
+	if _, err := c.ListarTarjetas(context.Background()); err == nil || errors.As(err, &e) {
+		t.Errorf("un error sin código no debe ser ErrorFinmex: %v", err)
+	}
+}
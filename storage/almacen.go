@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// maxRegistro es el tamaño máximo de una línea NDJSON
+const maxRegistro = 1024 * 1024
+
+// Storage guarda los datos de finmex: un documento con los productos registrados, que se
+// reemplaza completo en cada escritura, y un historial de registros que solo crece. El
+// contenido de ambos lo define quien llama; aquí solo se manejan como JSON.
+type Storage interface {
+	// Cargar lee el documento en v. Regresa false si todavía no se ha guardado nada.
+	Cargar(v interface{}) (bool, error)
+	// Guardar reemplaza el documento completo; si falla, el anterior queda intacto
+	Guardar(v interface{}) error
+	// AgregarRegistros escribe los registros al final del historial
+	AgregarRegistros(registros []json.RawMessage) error
+	// RecorrerRegistros llama a fn con cada registro en el orden en que se agregaron y
+	// se detiene en el primer error de fn, que se regresa tal cual
+	RecorrerRegistros(fn func(json.RawMessage) error) error
+	// Ruta es el archivo principal, para detectar cambios hechos por otro proceso
+	Ruta() string
+	Close() error
+}
+
+// Buscador es un Storage que resuelve el filtro del historial por su cuenta, sin que
+// quien llama tenga que leer cada registro
+type Buscador interface {
+	// BuscarRegistros es como RecorrerRegistros pero solo con los que cumplen el filtro
+	BuscarRegistros(filtro FiltroRegistros, fn func(json.RawMessage) error) error
+}
+
+// ArchivosJSON guarda el documento como JSON con sangría y el historial como NDJSON
+type ArchivosJSON struct {
+	Documento string
+	Historial string
+}
+
+// NuevoArchivosJSON usa los dos archivos indicados
+func NuevoArchivosJSON(documento, historial string) *ArchivosJSON {
+	return &ArchivosJSON{Documento: documento, Historial: historial}
+}
+
+func (a *ArchivosJSON) Cargar(v interface{}) (bool, error) {
+	err := LeerJSON(a.Documento, v)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (a *ArchivosJSON) Guardar(v interface{}) error {
+	return GuardarJSON(a.Documento, v)
+}
+
+func (a *ArchivosJSON) AgregarRegistros(registros []json.RawMessage) error {
+	return AgregarNDJSON(a.Historial, registros)
+}
+
+// RecorrerRegistros lee el historial línea por línea sin cargarlo a memoria. Las líneas
+// vacías se ignoran; una línea que no es JSON se reporta como *ErrorFormato.
+func (a *ArchivosJSON) RecorrerRegistros(fn func(json.RawMessage) error) error {
+	archivo, err := os.Open(a.Historial)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer archivo.Close()
+
+	scanner := bufio.NewScanner(archivo)
+	scanner.Buffer(make([]byte, 64*1024), maxRegistro)
+
+	linea := 0
+	for scanner.Scan() {
+		linea++
+		texto := bytes.TrimSpace(scanner.Bytes())
+		if len(texto) == 0 {
+			continue
+		}
+		if !json.Valid(texto) {
+			return &ErrorFormato{Archivo: a.Historial, Causa: fmt.Errorf("línea %d", linea)}
+		}
+		if err := fn(append(json.RawMessage(nil), texto...)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (a *ArchivosJSON) Ruta() string {
+	return a.Documento
+}
+
+func (a *ArchivosJSON) Close() error {
+	return nil
+}
+
+// Copiar pasa el documento y el historial de origen a destino. Sirve para migrar entre
+// almacenes; si origen no tiene documento solo se copia el historial.
+func Copiar(destino, origen Storage) error {
+	var documento json.RawMessage
+	hay, err := origen.Cargar(&documento)
+	if err != nil {
+		return err
+	}
+	if hay {
+		if err := destino.Guardar(documento); err != nil {
+			return err
+		}
+	}
+
+	const lote = 1000
+	registros := make([]json.RawMessage, 0, lote)
+	err = origen.RecorrerRegistros(func(r json.RawMessage) error {
+		registros = append(registros, r)
+		if len(registros) < lote {
+			return nil
+		}
+		err := destino.AgregarRegistros(registros)
+		registros = registros[:0]
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(registros) > 0 {
+		return destino.AgregarRegistros(registros)
+	}
+	return nil
+}
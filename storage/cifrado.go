@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// firmaCifrado abre los archivos cifrados; el último byte es la versión del formato
+var firmaCifrado = []byte("FINMEXC\x01")
+
+const (
+	tamañoSal   = 16
+	tamañoLlave = 32 // AES-256
+)
+
+// iteracionesPBKDF2 son las vueltas de PBKDF2-SHA256 para derivar la llave de los archivos
+// nuevos. Se guardan en el encabezado, así que cambiarlas no afecta a los existentes.
+var iteracionesPBKDF2 = 600000
+
+// maxIteracionesPBKDF2 es el límite de iteraciones que se acepta al abrir un archivo. El
+// encabezado se autentica hasta después de derivar la llave, así que sin límite un archivo
+// alterado podría dejar la derivación corriendo por horas.
+const maxIteracionesPBKDF2 = 10 * 600000
+
+// ErrPassphraseIncorrecta indica que la passphrase no abre el archivo cifrado o que el
+// archivo fue alterado; AES-GCM no distingue entre ambos casos
+var ErrPassphraseIncorrecta = errors.New("la passphrase es incorrecta o el archivo cifrado fue alterado")
+
+// Cifrado guarda el documento y el historial juntos en un solo archivo cifrado con
+// AES-256-GCM. La llave se deriva de la passphrase con PBKDF2 y una sal aleatoria. Todo
+// se descifra al abrir y cada escritura vuelve a cifrar el archivo completo con un nonce
+// nuevo, reemplazándolo de forma atómica.
+type Cifrado struct {
+	ruta        string
+	llave       []byte
+	sal         []byte
+	iteraciones int
+	contenido   contenidoCifrado
+}
+
+// contenidoCifrado es lo que va dentro del archivo cifrado
+type contenidoCifrado struct {
+	Documento json.RawMessage   `json:"documento,omitempty"`
+	Registros []json.RawMessage `json:"registros,omitempty"`
+}
+
+// AbrirCifrado descifra el archivo de la ruta con la passphrase. Si no existe, se creará
+// con esa passphrase al primer Guardar o AgregarRegistros.
+func AbrirCifrado(ruta string, passphrase []byte) (*Cifrado, error) {
+	data, err := os.ReadFile(ruta)
+	if os.IsNotExist(err) {
+		c := &Cifrado{ruta: ruta, sal: make([]byte, tamañoSal), iteraciones: iteracionesPBKDF2}
+		if _, err := rand.Read(c.sal); err != nil {
+			return nil, err
+		}
+		c.llave, err = derivarLlave(passphrase, c.sal, c.iteraciones)
+		return c, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	encabezado := len(firmaCifrado) + 4 + tamañoSal
+	if len(data) < encabezado || !bytes.Equal(data[:len(firmaCifrado)], firmaCifrado) {
+		return nil, &ErrorFormato{Archivo: ruta, Causa: errors.New("no es un archivo cifrado de finmex")}
+	}
+	c := &Cifrado{
+		ruta:        ruta,
+		iteraciones: int(binary.BigEndian.Uint32(data[len(firmaCifrado):])),
+		sal:         append([]byte(nil), data[len(firmaCifrado)+4:encabezado]...),
+	}
+	if c.iteraciones > maxIteracionesPBKDF2 {
+		return nil, &ErrorFormato{Archivo: ruta, Causa: fmt.Errorf("el encabezado pide %d iteraciones y el máximo es %d", c.iteraciones, maxIteracionesPBKDF2)}
+	}
+	if c.llave, err = derivarLlave(passphrase, c.sal, c.iteraciones); err != nil {
+		return nil, err
+	}
+	gcm, err := c.gcm()
+	if err != nil {
+		return nil, err
+	}
+	cifrado := data[encabezado:]
+	if len(cifrado) < gcm.NonceSize() {
+		return nil, &ErrorFormato{Archivo: ruta, Causa: errors.New("archivo cifrado incompleto")}
+	}
+	// El encabezado va como dato adicional para que alterar la sal o las iteraciones se detecte
+	claro, err := gcm.Open(nil, cifrado[:gcm.NonceSize()], cifrado[gcm.NonceSize():], data[:encabezado])
+	if err != nil {
+		return nil, ErrPassphraseIncorrecta
+	}
+	if err := json.Unmarshal(claro, &c.contenido); err != nil {
+		return nil, &ErrorFormato{Archivo: ruta, Causa: err}
+	}
+	return c, nil
+}
+
+func derivarLlave(passphrase, sal []byte, iteraciones int) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("la passphrase no puede estar vacía")
+	}
+	if iteraciones <= 0 {
+		return nil, fmt.Errorf("número de iteraciones inválido: %d", iteraciones)
+	}
+	return pbkdf2.Key(sha256.New, string(passphrase), sal, iteraciones, tamañoLlave)
+}
+
+func (c *Cifrado) gcm() (cipher.AEAD, error) {
+	bloque, err := aes.NewCipher(c.llave)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(bloque)
+}
+
+// escribir cifra el contenido completo y reemplaza el archivo de forma atómica
+func (c *Cifrado) escribir() error {
+	claro, err := json.Marshal(c.contenido)
+	if err != nil {
+		return err
+	}
+	gcm, err := c.gcm()
+	if err != nil {
+		return err
+	}
+	encabezado := append(append([]byte(nil), firmaCifrado...), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(encabezado[len(firmaCifrado):], uint32(c.iteraciones))
+	encabezado = append(encabezado, c.sal...)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data := append(append(encabezado, nonce...), gcm.Seal(nil, nonce, claro, encabezado)...)
+
+	temporal := c.ruta + ".tmp"
+	if err := os.WriteFile(temporal, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(temporal, c.ruta); err != nil {
+		os.Remove(temporal)
+		return err
+	}
+	return nil
+}
+
+func (c *Cifrado) Cargar(v interface{}) (bool, error) {
+	if c.contenido.Documento == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(c.contenido.Documento, v); err != nil {
+		return false, &ErrorFormato{Archivo: c.ruta, Causa: err}
+	}
+	return true, nil
+}
+
+func (c *Cifrado) Guardar(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	anterior := c.contenido.Documento
+	c.contenido.Documento = data
+	if err := c.escribir(); err != nil {
+		c.contenido.Documento = anterior
+		return err
+	}
+	return nil
+}
+
+func (c *Cifrado) AgregarRegistros(registros []json.RawMessage) error {
+	for _, r := range registros {
+		if !json.Valid(r) {
+			return fmt.Errorf("Registro que no es JSON válido: %s", r)
+		}
+	}
+	n := len(c.contenido.Registros)
+	c.contenido.Registros = append(c.contenido.Registros, registros...)
+	if err := c.escribir(); err != nil {
+		c.contenido.Registros = c.contenido.Registros[:n]
+		return err
+	}
+	return nil
+}
+
+func (c *Cifrado) RecorrerRegistros(fn func(json.RawMessage) error) error {
+	for _, r := range c.contenido.Registros {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cifrado) Ruta() string {
+	return c.ruta
+}
+
+func (c *Cifrado) Close() error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func init() {
+	// Las pruebas no necesitan una derivación lenta
+	iteracionesPBKDF2 = 1000
+}
+
+func TestCifradoGuardarYAbrir(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "finmex.cifrado")
+	c, err := AbrirCifrado(ruta, []byte("mi passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vacio documentoPrueba
+	if hay, err := c.Cargar(&vacio); err != nil || hay {
+		t.Fatalf("un archivo nuevo no debe tener documento: hay=%v err=%v", hay, err)
+	}
+
+	original := documentoPrueba{Debito: []registro{{"Nu", 1500}}, Credito: []registro{}, Version: 3}
+	if err := c.Guardar(original); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AgregarRegistros([]json.RawMessage{json.RawMessage(`{"nombre":"a","monto":1}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("1500")) || bytes.Contains(data, []byte("Nu")) {
+		t.Error("el archivo no debe contener los datos en claro")
+	}
+
+	abierto, err := AbrirCifrado(ruta, []byte("mi passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var leido documentoPrueba
+	if hay, err := abierto.Cargar(&leido); err != nil || !hay {
+		t.Fatalf("hay=%v err=%v", hay, err)
+	}
+	if len(leido.Debito) != 1 || leido.Debito[0] != original.Debito[0] || leido.Version != 3 {
+		t.Errorf("se leyó %+v, se guardó %+v", leido, original)
+	}
+	registros := 0
+	abierto.RecorrerRegistros(func(json.RawMessage) error { registros++; return nil })
+	if registros != 1 {
+		t.Errorf("se esperaba 1 registro, hay %d", registros)
+	}
+}
+
+func TestCifradoPassphraseIncorrecta(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "finmex.cifrado")
+	c, err := AbrirCifrado(ruta, []byte("correcta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Guardar(documentoPrueba{Version: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := AbrirCifrado(ruta, []byte("otra")); !errors.Is(err, ErrPassphraseIncorrecta) {
+		t.Errorf("otra passphrase debe regresar ErrPassphraseIncorrecta: %v", err)
+	}
+
+	// Alterar la sal del encabezado también se detecta
+	data, _ := os.ReadFile(ruta)
+	data[len(firmaCifrado)+4] ^= 1
+	os.WriteFile(ruta, data, 0600)
+	if _, err := AbrirCifrado(ruta, []byte("correcta")); !errors.Is(err, ErrPassphraseIncorrecta) {
+		t.Errorf("un encabezado alterado debe rechazarse: %v", err)
+	}
+
+	// Un número de iteraciones desmedido se rechaza antes de derivar la llave
+	binary.BigEndian.PutUint32(data[len(firmaCifrado):], 0xFFFFFFFF)
+	os.WriteFile(ruta, data, 0600)
+	var formato *ErrorFormato
+	if _, err := AbrirCifrado(ruta, []byte("correcta")); !errors.As(err, &formato) {
+		t.Errorf("demasiadas iteraciones deben regresar *ErrorFormato: %v", err)
+	}
+
+	otro := filepath.Join(t.TempDir(), "tarjetas.json")
+	os.WriteFile(otro, []byte(`{"debito":[]}`), 0644)
+	if _, err := AbrirCifrado(otro, []byte("correcta")); !errors.As(err, &formato) {
+		t.Errorf("un archivo que no está cifrado debe regresar *ErrorFormato: %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// VERSION_SQLITE es la versión del esquema de tablas que crea AbrirSQLite
+const VERSION_SQLITE = 2
+
+// esquemaSQLite crea las tablas. Cada llave del documento es una familia de productos;
+// si su valor es un arreglo, cada elemento se guarda como un renglón de productos y
+// familias.valor queda en NULL. Los registros del historial son movimientos: además del
+// JSON se guardan en columnas los campos por los que se filtra, con los textos en
+// minúsculas, para que BuscarRegistros use los índices en vez de leer cada registro.
+const esquemaSQLite = `
+CREATE TABLE IF NOT EXISTS familias (
+	nombre TEXT PRIMARY KEY,
+	valor  TEXT
+);
+CREATE TABLE IF NOT EXISTS productos (
+	familia  TEXT    NOT NULL REFERENCES familias(nombre) ON DELETE CASCADE,
+	posicion INTEGER NOT NULL,
+	datos    TEXT    NOT NULL,
+	PRIMARY KEY (familia, posicion)
+);
+CREATE TABLE IF NOT EXISTS movimientos (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	datos       TEXT NOT NULL,
+	fecha       TEXT NOT NULL DEFAULT '',
+	descripcion TEXT NOT NULL DEFAULT '',
+	categoria   TEXT NOT NULL DEFAULT '',
+	tarjeta     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS movimientos_fecha ON movimientos (fecha);
+CREATE INDEX IF NOT EXISTS movimientos_categoria ON movimientos (categoria, fecha);
+CREATE INDEX IF NOT EXISTS movimientos_tarjeta ON movimientos (tarjeta, fecha);
+`
+
+// columnasVersion1 agrega a una base de la versión 1 las columnas de movimientos, que
+// se llenan después desde el JSON de cada registro
+const columnasVersion1 = `
+ALTER TABLE movimientos ADD COLUMN fecha       TEXT NOT NULL DEFAULT '';
+ALTER TABLE movimientos ADD COLUMN descripcion TEXT NOT NULL DEFAULT '';
+ALTER TABLE movimientos ADD COLUMN categoria   TEXT NOT NULL DEFAULT '';
+ALTER TABLE movimientos ADD COLUMN tarjeta     TEXT NOT NULL DEFAULT '';
+`
+
+// Registros que se leen por tanda al llenar las columnas de una base de la versión 1
+const tandaMigracion = 1000
+
+// columnasMovimiento son los campos de un registro que se guardan en columnas
+type columnasMovimiento struct {
+	Fecha       string `json:"fecha"`
+	Descripcion string `json:"descripcion"`
+	Categoria   string `json:"categoria"`
+	Tarjeta     string `json:"tarjeta"`
+}
+
+// leerColumnas extrae las columnas del registro. Un registro sin esos campos, o que no
+// es un objeto, las deja vacías y solo se encuentra con un filtro vacío.
+func leerColumnas(r json.RawMessage) columnasMovimiento {
+	var c columnasMovimiento
+	json.Unmarshal(r, &c)
+	c.Descripcion = strings.ToLower(c.Descripcion)
+	c.Categoria = strings.ToLower(c.Categoria)
+	c.Tarjeta = strings.ToLower(c.Tarjeta)
+	return c
+}
+
+// FiltroRegistros elige movimientos del historial. Los textos se comparan sin distinguir
+// mayúsculas, las fechas (AAAA-MM-DD) incluyen sus extremos y un campo vacío no filtra.
+type FiltroRegistros struct {
+	Texto     string // Parte de la descripción
+	Categoria string
+	Tarjeta   string
+	Desde     string
+	Hasta     string
+}
+
+// SQLite guarda los datos en una base SQLite. Cada escritura es una transacción, así que
+// una falla a medias no deja datos parciales.
+type SQLite struct {
+	db   *sql.DB
+	ruta string
+}
+
+// AbrirSQLite abre la base en la ruta, creándola con sus tablas si no existe
+func AbrirSQLite(ruta string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", "file:"+ruta+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	s := &SQLite{db: db, ruta: ruta}
+	if err := s.prepararEsquema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("No se pudo preparar %s: %v", ruta, err)
+	}
+	return s, nil
+}
+
+// prepararEsquema crea las tablas, actualiza las bases anteriores y rechaza las de una
+// versión más nueva
+func (s *SQLite) prepararEsquema() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > VERSION_SQLITE {
+		return fmt.Errorf("la base es de la versión %d y esta versión de finmex solo conoce hasta la %d", version, VERSION_SQLITE)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if version == 1 {
+		if err := llenarColumnas(tx); err != nil {
+			return fmt.Errorf("actualizando de la versión 1: %v", err)
+		}
+	}
+	if _, err := tx.Exec(esquemaSQLite); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", VERSION_SQLITE)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// llenarColumnas agrega las columnas de movimientos a una base de la versión 1 y las llena
+// desde el JSON de los registros ya guardados
+func llenarColumnas(tx *sql.Tx) error {
+	if _, err := tx.Exec(columnasVersion1); err != nil {
+		return err
+	}
+
+	ultimo := int64(0)
+	for {
+		filas, err := tx.Query("SELECT id, datos FROM movimientos WHERE id > ? ORDER BY id LIMIT ?", ultimo, tandaMigracion)
+		if err != nil {
+			return err
+		}
+		type pendiente struct {
+			id       int64
+			columnas columnasMovimiento
+		}
+		var tanda []pendiente
+		for filas.Next() {
+			var datos string
+			if err := filas.Scan(&ultimo, &datos); err != nil {
+				filas.Close()
+				return err
+			}
+			tanda = append(tanda, pendiente{ultimo, leerColumnas(json.RawMessage(datos))})
+		}
+		filas.Close()
+		if err := filas.Err(); err != nil {
+			return err
+		}
+		if len(tanda) == 0 {
+			return nil
+		}
+
+		for _, p := range tanda {
+			c := p.columnas
+			if _, err := tx.Exec("UPDATE movimientos SET fecha = ?, descripcion = ?, categoria = ?, tarjeta = ? WHERE id = ?",
+				c.Fecha, c.Descripcion, c.Categoria, c.Tarjeta, p.id); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *SQLite) Cargar(v interface{}) (bool, error) {
+	documento := map[string]json.RawMessage{}
+	arreglos := map[string][]json.RawMessage{}
+
+	familias, err := s.db.Query("SELECT nombre, valor FROM familias")
+	if err != nil {
+		return false, err
+	}
+	for familias.Next() {
+		var nombre string
+		var valor sql.NullString
+		if err := familias.Scan(&nombre, &valor); err != nil {
+			familias.Close()
+			return false, err
+		}
+		if valor.Valid {
+			documento[nombre] = json.RawMessage(valor.String)
+		} else {
+			arreglos[nombre] = []json.RawMessage{}
+		}
+	}
+	familias.Close()
+	if err := familias.Err(); err != nil {
+		return false, err
+	}
+	if len(documento) == 0 && len(arreglos) == 0 {
+		return false, nil
+	}
+
+	productos, err := s.db.Query("SELECT familia, datos FROM productos ORDER BY familia, posicion")
+	if err != nil {
+		return false, err
+	}
+	defer productos.Close()
+	for productos.Next() {
+		var familia, datos string
+		if err := productos.Scan(&familia, &datos); err != nil {
+			return false, err
+		}
+		arreglos[familia] = append(arreglos[familia], json.RawMessage(datos))
+	}
+	if err := productos.Err(); err != nil {
+		return false, err
+	}
+
+	for nombre, elementos := range arreglos {
+		data, err := json.Marshal(elementos)
+		if err != nil {
+			return false, &ErrorFormato{Archivo: s.ruta, Causa: fmt.Errorf("%s: %v", nombre, err)}
+		}
+		documento[nombre] = data
+	}
+	data, err := json.Marshal(documento)
+	if err != nil {
+		return false, &ErrorFormato{Archivo: s.ruta, Causa: err}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, &ErrorFormato{Archivo: s.ruta, Causa: err}
+	}
+	return true, nil
+}
+
+// Guardar reemplaza todas las familias del documento, que debe ser un objeto JSON
+func (s *SQLite) Guardar(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var documento map[string]json.RawMessage
+	if err := json.Unmarshal(data, &documento); err != nil {
+		return fmt.Errorf("El documento a guardar debe ser un objeto JSON: %v", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM familias"); err != nil {
+		return err
+	}
+	for nombre, valor := range documento {
+		var elementos []json.RawMessage
+		if json.Unmarshal(valor, &elementos) != nil || elementos == nil {
+			if _, err := tx.Exec("INSERT INTO familias (nombre, valor) VALUES (?, ?)", nombre, string(valor)); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.Exec("INSERT INTO familias (nombre, valor) VALUES (?, NULL)", nombre); err != nil {
+			return err
+		}
+		for i, e := range elementos {
+			if _, err := tx.Exec("INSERT INTO productos (familia, posicion, datos) VALUES (?, ?, ?)", nombre, i, string(e)); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLite) AgregarRegistros(registros []json.RawMessage) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, r := range registros {
+		if !json.Valid(r) {
+			return fmt.Errorf("Registro que no es JSON válido: %s", r)
+		}
+		c := leerColumnas(r)
+		if _, err := tx.Exec("INSERT INTO movimientos (datos, fecha, descripcion, categoria, tarjeta) VALUES (?, ?, ?, ?, ?)",
+			string(r), c.Fecha, c.Descripcion, c.Categoria, c.Tarjeta); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLite) RecorrerRegistros(fn func(json.RawMessage) error) error {
+	return s.BuscarRegistros(FiltroRegistros{}, fn)
+}
+
+// BuscarRegistros llama a fn con los registros que cumplen el filtro, en el orden en que
+// se agregaron. La categoría, la tarjeta y las fechas se resuelven con los índices; el
+// texto se busca en la columna de la descripción sin leer el JSON de cada registro.
+func (s *SQLite) BuscarRegistros(filtro FiltroRegistros, fn func(json.RawMessage) error) error {
+	var condiciones []string
+	var argumentos []interface{}
+	if filtro.Texto != "" {
+		condiciones = append(condiciones, "instr(descripcion, ?) > 0")
+		argumentos = append(argumentos, strings.ToLower(filtro.Texto))
+	}
+	if filtro.Categoria != "" {
+		condiciones = append(condiciones, "categoria = ?")
+		argumentos = append(argumentos, strings.ToLower(filtro.Categoria))
+	}
+	if filtro.Tarjeta != "" {
+		condiciones = append(condiciones, "tarjeta = ?")
+		argumentos = append(argumentos, strings.ToLower(filtro.Tarjeta))
+	}
+	if filtro.Desde != "" {
+		condiciones = append(condiciones, "fecha >= ?")
+		argumentos = append(argumentos, filtro.Desde)
+	}
+	if filtro.Hasta != "" {
+		condiciones = append(condiciones, "fecha <= ?")
+		argumentos = append(argumentos, filtro.Hasta)
+	}
+	consulta := "SELECT datos FROM movimientos"
+	if len(condiciones) > 0 {
+		consulta += " WHERE " + strings.Join(condiciones, " AND ")
+	}
+
+	filas, err := s.db.Query(consulta+" ORDER BY id", argumentos...)
+	if err != nil {
+		return err
+	}
+	defer filas.Close()
+
+	for filas.Next() {
+		var datos string
+		if err := filas.Scan(&datos); err != nil {
+			return err
+		}
+		if err := fn(json.RawMessage(datos)); err != nil {
+			return err
+		}
+	}
+	return filas.Err()
+}
+
+func (s *SQLite) Ruta() string {
+	return s.ruta
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
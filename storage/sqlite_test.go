@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type documentoPrueba struct {
+	Debito  []registro `json:"debito"`
+	Credito []registro `json:"credito"`
+	Metas   []registro `json:"metas,omitempty"`
+	Version int        `json:"version"`
+}
+
+func abrirPrueba(t *testing.T) *SQLite {
+	t.Helper()
+	s, err := AbrirSQLite(filepath.Join(t.TempDir(), "finmex.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLiteDocumento(t *testing.T) {
+	s := abrirPrueba(t)
+
+	var vacio documentoPrueba
+	if hay, err := s.Cargar(&vacio); err != nil || hay {
+		t.Fatalf("una base nueva no debe tener documento: hay=%v err=%v", hay, err)
+	}
+
+	original := documentoPrueba{
+		Debito:  []registro{{"Nu", 1500}, {"Hey", 20}},
+		Credito: []registro{},
+		Version: 3,
+	}
+	if err := s.Guardar(original); err != nil {
+		t.Fatal(err)
+	}
+
+	var leido documentoPrueba
+	if hay, err := s.Cargar(&leido); err != nil || !hay {
+		t.Fatalf("hay=%v err=%v", hay, err)
+	}
+	if len(leido.Debito) != 2 || leido.Debito[0] != original.Debito[0] || leido.Debito[1] != original.Debito[1] {
+		t.Errorf("débito: se leyó %v, se guardó %v", leido.Debito, original.Debito)
+	}
+	if leido.Credito == nil || len(leido.Credito) != 0 {
+		t.Errorf("un arreglo vacío debe seguir vacío, no nulo: %#v", leido.Credito)
+	}
+	if leido.Version != 3 {
+		t.Errorf("los valores que no son arreglos se conservan: %d", leido.Version)
+	}
+
+	// Guardar reemplaza todo, incluso las familias que ya no están
+	if err := s.Guardar(documentoPrueba{Metas: []registro{{"Viaje", 9000}}}); err != nil {
+		t.Fatal(err)
+	}
+	leido = documentoPrueba{}
+	if _, err := s.Cargar(&leido); err != nil {
+		t.Fatal(err)
+	}
+	if leido.Debito != nil || len(leido.Metas) != 1 || leido.Metas[0].Nombre != "Viaje" {
+		t.Errorf("después de reemplazar se leyó %+v", leido)
+	}
+}
+
+func TestSQLiteRegistros(t *testing.T) {
+	s := abrirPrueba(t)
+
+	lote := []json.RawMessage{json.RawMessage(`{"nombre":"a","monto":1}`), json.RawMessage(`{"nombre":"b","monto":2}`)}
+	if err := s.AgregarRegistros(lote); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AgregarRegistros([]json.RawMessage{json.RawMessage(`{"nombre":"c","monto":3}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AgregarRegistros([]json.RawMessage{json.RawMessage(`{"nombre":`)}); err == nil {
+		t.Error("un registro que no es JSON debe rechazarse")
+	}
+
+	var nombres string
+	err := s.RecorrerRegistros(func(r json.RawMessage) error {
+		var reg registro
+		if err := json.Unmarshal(r, &reg); err != nil {
+			return err
+		}
+		nombres += reg.Nombre
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nombres != "abc" {
+		t.Errorf("se recorrió %q, se esperaba abc en orden", nombres)
+	}
+
+	alto := errors.New("alto")
+	vistos := 0
+	err = s.RecorrerRegistros(func(json.RawMessage) error {
+		vistos++
+		return alto
+	})
+	if !errors.Is(err, alto) || vistos != 1 {
+		t.Errorf("el error de fn debe detener el recorrido: err=%v vistos=%d", err, vistos)
+	}
+}
+
+// descripcionesEncontradas junta separadas por coma las descripciones que regresa el filtro
+func descripcionesEncontradas(t *testing.T, s *SQLite, filtro FiltroRegistros) string {
+	t.Helper()
+	var descripciones []string
+	err := s.BuscarRegistros(filtro, func(r json.RawMessage) error {
+		var m struct{ Descripcion string }
+		if err := json.Unmarshal(r, &m); err != nil {
+			return err
+		}
+		descripciones = append(descripciones, m.Descripcion)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(descripciones, ",")
+}
+
+var movimientosPrueba = []json.RawMessage{
+	json.RawMessage(`{"fecha":"2024-01-05","descripcion":"OXXO PERIFÉRICO","categoria":"Súper","tarjeta":"Nu"}`),
+	json.RawMessage(`{"fecha":"2024-02-10","descripcion":"Walmart Insurgentes","categoria":"súper","tarjeta":"Oro"}`),
+	json.RawMessage(`{"fecha":"2024-03-15","descripcion":"oxxo centro","tarjeta":"NU"}`),
+}
+
+func TestSQLiteBuscarRegistros(t *testing.T) {
+	s := abrirPrueba(t)
+	if err := s.AgregarRegistros(movimientosPrueba); err != nil {
+		t.Fatal(err)
+	}
+
+	casos := []struct {
+		filtro FiltroRegistros
+		se     string
+	}{
+		{FiltroRegistros{}, "OXXO PERIFÉRICO,Walmart Insurgentes,oxxo centro"},
+		{FiltroRegistros{Texto: "Oxxo"}, "OXXO PERIFÉRICO,oxxo centro"},
+		{FiltroRegistros{Texto: "periférico"}, "OXXO PERIFÉRICO"},
+		{FiltroRegistros{Categoria: "SÚPER"}, "OXXO PERIFÉRICO,Walmart Insurgentes"},
+		{FiltroRegistros{Tarjeta: "nu", Texto: "centro"}, "oxxo centro"},
+		{FiltroRegistros{Desde: "2024-02-10", Hasta: "2024-03-15"}, "Walmart Insurgentes,oxxo centro"},
+		{FiltroRegistros{Tarjeta: "Hey"}, ""},
+	}
+	for _, c := range casos {
+		if encontradas := descripcionesEncontradas(t, s, c.filtro); encontradas != c.se {
+			t.Errorf("%+v: %q, se esperaba %q", c.filtro, encontradas, c.se)
+		}
+	}
+
+	// La tarjeta y la categoría se buscan con su índice, sin recorrer la tabla
+	for _, columna := range []string{"tarjeta", "categoria"} {
+		var id, padre, libre int
+		var plan string
+		err := s.db.QueryRow("EXPLAIN QUERY PLAN SELECT datos FROM movimientos WHERE "+columna+" = ? ORDER BY id", "nu").Scan(&id, &padre, &libre, &plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(plan, "movimientos_"+columna) {
+			t.Errorf("%s: el plan no usa el índice: %s", columna, plan)
+		}
+	}
+}
+
+func TestSQLiteActualizaVersion1(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "finmex.db")
+	db, err := sql.Open("sqlite", "file:"+ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE movimientos (id INTEGER PRIMARY KEY AUTOINCREMENT, datos TEXT NOT NULL);
+		PRAGMA user_version = 1`)
+	for _, m := range movimientosPrueba {
+		if err == nil {
+			_, err = db.Exec("INSERT INTO movimientos (datos) VALUES (?)", string(m))
+		}
+	}
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := AbrirSQLite(ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if encontradas := descripcionesEncontradas(t, s, FiltroRegistros{Tarjeta: "Nu"}); encontradas != "OXXO PERIFÉRICO,oxxo centro" {
+		t.Errorf("los movimientos de la versión 1 deben poder filtrarse: %q", encontradas)
+	}
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil || version != VERSION_SQLITE {
+		t.Errorf("la base quedó en la versión %d: %v", version, err)
+	}
+}
+
+func TestSQLiteReabrir(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "finmex.db")
+	s, err := AbrirSQLite(ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Guardar(documentoPrueba{Debito: []registro{{"Nu", 1}}}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = AbrirSQLite(ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	var leido documentoPrueba
+	if hay, err := s.Cargar(&leido); err != nil || !hay || len(leido.Debito) != 1 {
+		t.Errorf("al reabrir: hay=%v err=%v %+v", hay, err, leido)
+	}
+}
+
+func TestCopiarDesdeJSON(t *testing.T) {
+	dir := t.TempDir()
+	origen := NuevoArchivosJSON(filepath.Join(dir, "tarjetas.json"), filepath.Join(dir, "movimientos.ndjson"))
+	if err := origen.Guardar(documentoPrueba{Debito: []registro{{"Nu", 1500}}, Credito: []registro{{"Oro", 300}}}); err != nil {
+		t.Fatal(err)
+	}
+	var movimientos []json.RawMessage
+	for i := 0; i < 1500; i++ {
+		movimientos = append(movimientos, json.RawMessage(`{"nombre":"m","monto":1}`))
+	}
+	if err := origen.AgregarRegistros(movimientos); err != nil {
+		t.Fatal(err)
+	}
+
+	destino := abrirPrueba(t)
+	if err := Copiar(destino, origen); err != nil {
+		t.Fatal(err)
+	}
+
+	var leido documentoPrueba
+	if _, err := destino.Cargar(&leido); err != nil {
+		t.Fatal(err)
+	}
+	if len(leido.Debito) != 1 || len(leido.Credito) != 1 || leido.Credito[0].Monto != 300 {
+		t.Errorf("documento copiado: %+v", leido)
+	}
+	total := 0
+	destino.RecorrerRegistros(func(json.RawMessage) error {
+		total++
+		return nil
+	})
+	if total != 1500 {
+		t.Errorf("se copiaron %d registros, se esperaban 1500", total)
+	}
+}
@@ -0,0 +1,89 @@
+// Package storage guarda los datos de finmex en archivos locales. Los documentos JSON se
+// reemplazan completos escribiendo un archivo temporal y renombrándolo, para que una falla
+// a medio guardar no deje el archivo dañado; los archivos NDJSON solo crecen agregando una
+// línea por registro. La interfaz Storage reúne ambos para que los comandos no dependan de
+// dónde se guardan: en esos archivos (ArchivosJSON), en una base SQLite o en un archivo
+// cifrado (Cifrado).
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// ErrorFormato indica que el archivo existe pero su contenido no es JSON válido
+type ErrorFormato struct {
+	Archivo string
+	Causa   error
+}
+
+func (e *ErrorFormato) Error() string {
+	return fmt.Sprintf("%s no es JSON válido: %v", e.Archivo, e.Causa)
+}
+
+func (e *ErrorFormato) Unwrap() error {
+	return e.Causa
+}
+
+// Existe indica si hay un archivo en la ruta
+func Existe(ruta string) bool {
+	_, err := os.Stat(ruta)
+	return err == nil
+}
+
+// LeerJSON carga el documento JSON de la ruta en v. Si el archivo no existe regresa el
+// error de os (se detecta con os.IsNotExist); si no se puede interpretar, un *ErrorFormato.
+func LeerJSON(ruta string, v interface{}) error {
+	data, err := ioutil.ReadFile(ruta)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &ErrorFormato{Archivo: ruta, Causa: err}
+	}
+	return nil
+}
+
+// GuardarJSON escribe v como JSON con sangría reemplazando el archivo de forma atómica
+func GuardarJSON(ruta string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	temporal := ruta + ".tmp"
+	if err := ioutil.WriteFile(temporal, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(temporal, ruta); err != nil {
+		os.Remove(temporal)
+		return err
+	}
+	return nil
+}
+
+// AgregarNDJSON escribe cada valor como una línea JSON al final del archivo, creándolo si
+// no existe
+func AgregarNDJSON[T any](ruta string, valores []T) error {
+	archivo, err := os.OpenFile(ruta, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(archivo)
+	encoder := json.NewEncoder(w)
+	for _, v := range valores {
+		if err := encoder.Encode(v); err != nil {
+			archivo.Close()
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		archivo.Close()
+		return err
+	}
+	return archivo.Close()
+}
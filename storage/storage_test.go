@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type registro struct {
+	Nombre string  `json:"nombre"`
+	Monto  float64 `json:"monto"`
+}
+
+func TestGuardarYLeerJSON(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "datos.json")
+	if Existe(ruta) {
+		t.Fatal("el archivo no debería existir todavía")
+	}
+
+	original := []registro{{"Nu", 1500}, {"Oro", 320.5}}
+	if err := GuardarJSON(ruta, original); err != nil {
+		t.Fatal(err)
+	}
+	if Existe(ruta + ".tmp") {
+		t.Error("el archivo temporal debe desaparecer al guardar")
+	}
+
+	var leido []registro
+	if err := LeerJSON(ruta, &leido); err != nil {
+		t.Fatal(err)
+	}
+	if len(leido) != 2 || leido[0] != original[0] || leido[1] != original[1] {
+		t.Errorf("se leyó %v, se guardó %v", leido, original)
+	}
+}
+
+func TestLeerJSONErrores(t *testing.T) {
+	dir := t.TempDir()
+
+	var v []registro
+	if err := LeerJSON(filepath.Join(dir, "no-existe.json"), &v); !os.IsNotExist(err) {
+		t.Errorf("un archivo inexistente debe regresar el error de os: %v", err)
+	}
+
+	ruta := filepath.Join(dir, "roto.json")
+	if err := os.WriteFile(ruta, []byte("{no es json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var formato *ErrorFormato
+	if err := LeerJSON(ruta, &v); !errors.As(err, &formato) || formato.Archivo != ruta {
+		t.Errorf("un archivo dañado debe regresar *ErrorFormato: %v", err)
+	}
+}
+
+func TestAgregarNDJSON(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "movimientos.ndjson")
+	if err := AgregarNDJSON(ruta, []registro{{"a", 1}, {"b", 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AgregarNDJSON(ruta, []registro{{"c", 3}}); err != nil {
+		t.Fatal(err)
+	}
+
+	archivo, err := os.Open(ruta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archivo.Close()
+	lineas := 0
+	for s := bufio.NewScanner(archivo); s.Scan(); {
+		lineas++
+	}
+	if lineas != 3 {
+		t.Errorf("se esperaban 3 líneas, hay %d", lineas)
+	}
+}